@@ -109,14 +109,18 @@ func (ta *TiltAnalytics) TiltfileOpt() analytics.Opt {
 	return ta.opt.tiltfile
 }
 
+// The most restrictive of the env, Tiltfile, and user settings wins:
+// an opt-out from any source disables reporting.
 func (ta *TiltAnalytics) EffectiveOpt() analytics.Opt {
-	if ta.opt.env != analytics.OptDefault {
-		return ta.opt.env
+	if ta.opt.env == analytics.OptOut ||
+		ta.opt.tiltfile == analytics.OptOut ||
+		ta.opt.user == analytics.OptOut {
+		return analytics.OptOut
 	}
-	if ta.opt.tiltfile != analytics.OptDefault {
-		return ta.opt.tiltfile
+	if ta.opt.tiltfile == analytics.OptIn || ta.opt.user == analytics.OptIn {
+		return analytics.OptIn
 	}
-	return ta.opt.user
+	return analytics.OptDefault
 }
 
 func (ta *TiltAnalytics) SetUserOpt(opt analytics.Opt) error {
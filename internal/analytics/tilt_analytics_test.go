@@ -114,6 +114,28 @@ func TestWithoutGlobalTags(t *testing.T) {
 	assert.Equal(t, 1, len(ma.Counts))
 }
 
+func TestEffectiveOptMostRestrictive(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		user     analytics.Opt
+		tiltfile analytics.Opt
+		expected analytics.Opt
+	}{
+		{"user in, tiltfile out", analytics.OptIn, analytics.OptOut, analytics.OptOut},
+		{"user out, tiltfile in", analytics.OptOut, analytics.OptIn, analytics.OptOut},
+		{"user default, tiltfile in", analytics.OptDefault, analytics.OptIn, analytics.OptIn},
+		{"user in, tiltfile default", analytics.OptIn, analytics.OptDefault, analytics.OptIn},
+		{"both default", analytics.OptDefault, analytics.OptDefault, analytics.OptDefault},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			os := &userOptSetting{opt: test.user}
+			_, a := NewMemoryTiltAnalyticsForTest(os)
+			a.SetTiltfileOpt(test.tiltfile)
+			assert.Equal(t, test.expected, a.EffectiveOpt())
+		})
+	}
+}
+
 func analyticsViaTransition(t *testing.T, initialOpt, newOpt analytics.Opt) (*TiltAnalytics, *analytics.MemoryAnalytics) {
 	ma := analytics.NewMemoryAnalytics()
 	os := &userOptSetting{opt: initialOpt}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/distribution/reference"
@@ -17,6 +18,7 @@ import (
 	"github.com/opencontainers/go-digest"
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/windmilleng/tilt/internal/container"
 
@@ -35,12 +37,18 @@ type dockerImageBuilder struct {
 	//
 	// By default, all builds are labeled with a build mode.
 	extraLabels dockerfile.Labels
+
+	// One tar cache per build context, so rebuilds of the same target
+	// reuse the previous build's tar layout and file hashes.
+	contextCacheMu sync.Mutex
+	contextCaches  map[string]*ContextCache
 }
 
 type ImageBuilder interface {
 	BuildImage(ctx context.Context, ps *PipelineState, refs container.RefSet, db model.DockerBuild, filter model.PathMatcher) (container.TaggedRefs, error)
 	PushImage(ctx context.Context, name reference.NamedTagged) error
-	TagRefs(ctx context.Context, refs container.RefSet, dig digest.Digest) (container.TaggedRefs, error)
+	PullImage(ctx context.Context, name reference.Named) error
+	TagRefs(ctx context.Context, refs container.RefSet, dig digest.Digest, strategy model.TagStrategy, buildPath string) (container.TaggedRefs, error)
 	ImageExists(ctx context.Context, ref reference.NamedTagged) (bool, error)
 }
 
@@ -52,9 +60,21 @@ var _ ImageBuilder = &dockerImageBuilder{}
 
 func NewDockerImageBuilder(dCli docker.Client, extraLabels dockerfile.Labels) *dockerImageBuilder {
 	return &dockerImageBuilder{
-		dCli:        dCli,
-		extraLabels: extraLabels,
+		dCli:          dCli,
+		extraLabels:   extraLabels,
+		contextCaches: make(map[string]*ContextCache),
+	}
+}
+
+func (d *dockerImageBuilder) contextCacheFor(buildPath string) *ContextCache {
+	d.contextCacheMu.Lock()
+	defer d.contextCacheMu.Unlock()
+	cache, ok := d.contextCaches[buildPath]
+	if !ok {
+		cache = NewContextCache()
+		d.contextCaches[buildPath] = cache
 	}
+	return cache
 }
 
 func (d *dockerImageBuilder) BuildImage(ctx context.Context, ps *PipelineState, refs container.RefSet, db model.DockerBuild, filter model.PathMatcher) (container.TaggedRefs, error) {
@@ -151,8 +171,8 @@ func (d *dockerImageBuilder) addRemainingRuns(df dockerfile.Dockerfile, remainin
 }
 
 // Tag the digest with the given name and wm-tilt tag.
-func (d *dockerImageBuilder) TagRefs(ctx context.Context, refs container.RefSet, dig digest.Digest) (container.TaggedRefs, error) {
-	tag, err := digestAsTag(dig)
+func (d *dockerImageBuilder) TagRefs(ctx context.Context, refs container.RefSet, dig digest.Digest, strategy model.TagStrategy, buildPath string) (container.TaggedRefs, error) {
+	tag, err := tagForBuild(strategy, dig, buildPath)
 	if err != nil {
 		return container.TaggedRefs{}, errors.Wrap(err, "TagImage")
 	}
@@ -210,36 +230,121 @@ func (d *dockerImageBuilder) ImageExists(ctx context.Context, ref reference.Name
 	return len(images) > 0, nil
 }
 
-func (d *dockerImageBuilder) buildFromDf(ctx context.Context, ps *PipelineState, db model.DockerBuild, paths []PathMapping, filter model.PathMatcher, refs container.RefSet) (container.TaggedRefs, error) {
-	logger.Get(ctx).Infof("Building Dockerfile:\n%s\n", indent(db.Dockerfile, "  "))
-	span, ctx := opentracing.StartSpanFromContext(ctx, "daemon-buildFromDf")
-	defer span.Finish()
+// Pull any base images that aren't present locally, so that the first
+// build doesn't stall silently while the daemon fetches them.
+func (d *dockerImageBuilder) prePullBaseImages(ctx context.Context, ps *PipelineState, df dockerfile.Dockerfile) {
+	ast, err := dockerfile.ParseAST(df)
+	if err != nil {
+		// The build itself will surface the parse error.
+		return
+	}
 
-	ps.StartBuildStep(ctx, "Tarring context…")
+	baseImages, err := ast.BaseImages()
+	if err != nil || len(baseImages) == 0 {
+		return
+	}
 
-	// NOTE(maia): some people want to know what files we're adding (b/c `ADD . /` isn't descriptive)
-	if logger.Get(ctx).Level().ShouldDisplay(logger.VerboseLvl) {
-		for _, pm := range paths {
-			ps.Printf(ctx, pm.PrettyStr())
+	missing := []reference.Named{}
+	for _, ref := range baseImages {
+		_, _, err := d.dCli.ImageInspectWithRaw(ctx, ref.String())
+		if err != nil {
+			missing = append(missing, ref)
 		}
 	}
+	if len(missing) == 0 {
+		return
+	}
 
-	pr, pw := io.Pipe()
-	go func(ctx context.Context) {
-		err := tarContextAndUpdateDf(ctx, pw, dockerfile.Dockerfile(db.Dockerfile), paths, filter)
+	ps.StartBuildStep(ctx, "Pulling base images")
+	g, gCtx := errgroup.WithContext(ps.AttachLogger(ctx))
+	for _, ref := range missing {
+		ref := ref
+		g.Go(func() error {
+			return d.PullImage(gCtx, ref)
+		})
+	}
+	err = g.Wait()
+	if err != nil {
+		// The daemon may still be able to pull with its own credentials,
+		// so log the failure and let the build proceed.
+		logger.Get(ctx).Warnf("Pre-pulling base images: %v", err)
+	}
+}
+
+// Pull the given image from its registry, streaming progress to the log.
+func (d *dockerImageBuilder) PullImage(ctx context.Context, ref reference.Named) error {
+	logger.Get(ctx).Infof("Pulling %s", container.FamiliarString(ref))
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "daemon-PullImage")
+	defer span.Finish()
+
+	imagePullResponse, err := d.dCli.ImagePull(ctx, ref)
+	if err != nil {
+		return errors.Wrap(err, "PullImage#ImagePull")
+	}
+
+	defer func() {
+		err := imagePullResponse.Close()
 		if err != nil {
-			_ = pw.CloseWithError(err)
-		} else {
-			_ = pw.Close()
+			logger.Get(ctx).Infof("unable to close imagePullResponse: %s", err)
 		}
-	}(ctx)
+	}()
+
+	_, err = readDockerOutput(ctx, imagePullResponse)
+	if err != nil {
+		return errors.Wrapf(err, "pulling image %q", container.FamiliarString(ref))
+	}
+
+	return nil
+}
+
+func (d *dockerImageBuilder) buildFromDf(ctx context.Context, ps *PipelineState, db model.DockerBuild, paths []PathMapping, filter model.PathMatcher, refs container.RefSet) (container.TaggedRefs, error) {
+	logger.Get(ctx).Infof("Building Dockerfile:\n%s\n", indent(db.Dockerfile, "  "))
+	span, ctx := opentracing.StartSpanFromContext(ctx, "daemon-buildFromDf")
+	defer span.Finish()
+
+	d.prePullBaseImages(ctx, ps, dockerfile.Dockerfile(db.Dockerfile))
+
+	options := Options(nil, db)
+	var ctxReader *countingReader
+
+	if d.dCli.BuilderVersion() == types.BuilderBuildKit {
+		// On BuildKit, the context is synced over the build session rather
+		// than tarred up and sent in the request body, so only files that
+		// changed since the last build cross the wire.
+		options.ContextDir = db.BuildPath
+		options.DockerfileContents = db.Dockerfile
+		options.Filter = filter
+	} else {
+		ps.StartBuildStep(ctx, "Tarring context…")
+
+		// NOTE(maia): some people want to know what files we're adding (b/c `ADD . /` isn't descriptive)
+		if logger.Get(ctx).Level().ShouldDisplay(logger.VerboseLvl) {
+			for _, pm := range paths {
+				ps.Printf(ctx, pm.PrettyStr())
+			}
+		}
+
+		pr, pw := io.Pipe()
+		go func(ctx context.Context) {
+			err := tarContextAndUpdateDf(ctx, pw, dockerfile.Dockerfile(db.Dockerfile), paths, filter, d.contextCacheFor(db.BuildPath))
+			if err != nil {
+				_ = pw.CloseWithError(err)
+			} else {
+				_ = pw.Close()
+			}
+		}(ctx)
+
+		ctxReader = &countingReader{reader: pr}
+		options.Context = ctxReader
+	}
 
 	ps.StartBuildStep(ctx, "Building image")
 	spanBuild, ctx := opentracing.StartSpanFromContext(ctx, "daemon-ImageBuild")
 	imageBuildResponse, err := d.dCli.ImageBuild(
 		ctx,
-		pr,
-		Options(pr, db),
+		options.Context,
+		options,
 	)
 	spanBuild.Finish()
 	if err != nil {
@@ -258,7 +363,13 @@ func (d *dockerImageBuilder) buildFromDf(ctx context.Context, ps *PipelineState,
 		return container.TaggedRefs{}, err
 	}
 
-	tagged, err := d.TagRefs(ctx, refs, digest)
+	contextBytes := int64(-1)
+	if ctxReader != nil {
+		contextBytes = ctxReader.total()
+	}
+	d.reportSizes(ctx, ps, span, contextBytes, digest)
+
+	tagged, err := d.TagRefs(ctx, refs, digest, db.TagStrategy, db.BuildPath)
 	if err != nil {
 		return container.TaggedRefs{}, errors.Wrap(err, "PushImage")
 	}
@@ -266,6 +377,39 @@ func (d *dockerImageBuilder) buildFromDf(ctx context.Context, ps *PipelineState,
 	return tagged, nil
 }
 
+// Report how much build context we uploaded to the daemon and how big the
+// resulting image is, so ballooning builds are easy to spot.
+//
+// Pass contextBytes == -1 when the context wasn't tarred up (BuildKit syncs
+// it over the session, so we don't see the byte count).
+func (d *dockerImageBuilder) reportSizes(ctx context.Context, ps *PipelineState, span opentracing.Span, contextBytes int64, dig digest.Digest) {
+	var line string
+	if contextBytes >= 0 {
+		span.SetTag("context_bytes", contextBytes)
+		line = fmt.Sprintf("Build context: %s", humanSize(contextBytes))
+	}
+
+	data, _, err := d.dCli.ImageInspectWithRaw(ctx, dig.String())
+	if err == nil {
+		span.SetTag("image_bytes", data.Size)
+		if line != "" {
+			line += " | "
+		}
+		line += fmt.Sprintf("Image size: %s", humanSize(data.Size))
+	}
+	if line != "" {
+		ps.Printf(ctx, "%s", line)
+	}
+
+	threshold := contextWarnThreshold()
+	if contextBytes > threshold {
+		logger.Get(ctx).Warnf("Build context (%s) exceeds %s. "+
+			"Consider excluding large directories with a .dockerignore file or ignore= in your Tiltfile. "+
+			"(Adjust this warning with the %s env variable.)",
+			humanSize(contextBytes), humanSize(threshold), contextWarnSizeEnvName)
+	}
+}
+
 func (d *dockerImageBuilder) getDigestFromBuildOutput(ctx context.Context, reader io.Reader) (digest.Digest, error) {
 	result, err := readDockerOutput(ctx, reader)
 	if err != nil {
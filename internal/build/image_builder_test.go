@@ -9,6 +9,7 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/windmilleng/tilt/internal/container"
@@ -93,6 +94,35 @@ func TestDigestFromOutputV1_23(t *testing.T) {
 	}
 }
 
+func TestPrePullBaseImages(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+	defer f.teardown()
+
+	df := dockerfile.Dockerfile(`
+FROM golang:10 as builder
+RUN go build -o /app .
+
+FROM alpine:3.10
+COPY --from=builder /app /app
+`)
+	f.b.prePullBaseImages(f.ctx, f.ps, df)
+
+	// pulls run concurrently, so don't assert on order
+	assert.ElementsMatch(t, []string{"docker.io/library/golang:10", "docker.io/library/alpine:3.10"},
+		f.fakeDocker.ImagesPulled)
+}
+
+func TestPrePullBaseImagesSkipsPresent(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+	defer f.teardown()
+
+	f.fakeDocker.Images["docker.io/library/alpine"] = types.ImageInspect{ID: "sha256:deadbeef"}
+
+	f.b.prePullBaseImages(f.ctx, f.ps, simpleDockerfile)
+
+	assert.Equal(t, 0, f.fakeDocker.PullCount)
+}
+
 func makeDockerBuildErrorOutput(s string) string {
 	b := &bytes.Buffer{}
 	err := json.NewEncoder(b).Encode(s)
@@ -35,6 +35,10 @@ import (
 type PathMapping struct {
 	LocalPath     string
 	ContainerPath string
+
+	// Who the synced files should be owned by in the container, as "uid" or
+	// "uid:gid". Carried over from the sync this mapping came from.
+	RunAs string
 }
 
 func (m PathMapping) PrettyStr() string {
@@ -65,6 +69,7 @@ func (m PathMapping) Filter(matcher model.PathMatcher) ([]PathMapping, error) {
 		result = append(result, PathMapping{
 			LocalPath:     currentLocal,
 			ContainerPath: path.Join(m.ContainerPath, filepath.ToSlash(rpLocal)),
+			RunAs:         m.RunAs,
 		})
 		return nil
 	})
@@ -131,6 +136,7 @@ func fileToPathMapping(file string, sync []model.Sync) (pm PathMapping, couldMap
 			return PathMapping{
 				LocalPath:     file,
 				ContainerPath: containerPath,
+				RunAs:         s.RunAs,
 			}, true, nil
 		}
 	}
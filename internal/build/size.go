@@ -0,0 +1,53 @@
+package build
+
+import (
+	"io"
+	"os"
+	"sync/atomic"
+
+	"github.com/docker/go-units"
+)
+
+// Warn when the build context sent to the Docker daemon exceeds this size.
+// A big context is usually a sign that a heavy directory (node_modules,
+// .git, build artifacts) should be filtered out with a .dockerignore or
+// an ignore= in the Tiltfile.
+const defaultContextWarnThreshold = 100 * units.MB
+
+// Overrides the context-size warning threshold, expressed as a human
+// size ("250MB", "1GB"). Mostly for people with legitimately huge
+// contexts who want to silence the warning, or tighten it.
+const contextWarnSizeEnvName = "TILT_CONTEXT_WARN_SIZE"
+
+func contextWarnThreshold() int64 {
+	v := os.Getenv(contextWarnSizeEnvName)
+	if v == "" {
+		return defaultContextWarnThreshold
+	}
+	threshold, err := units.FromHumanSize(v)
+	if err != nil {
+		return defaultContextWarnThreshold
+	}
+	return threshold
+}
+
+// Counts the bytes of the build context as it streams to the Docker
+// daemon, so we can report how much we uploaded.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	atomic.AddInt64(&r.count, int64(n))
+	return n, err
+}
+
+func (r *countingReader) total() int64 {
+	return atomic.LoadInt64(&r.count)
+}
+
+func humanSize(bytes int64) string {
+	return units.HumanSize(float64(bytes))
+}
@@ -0,0 +1,36 @@
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/docker/go-units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountingReader(t *testing.T) {
+	r := &countingReader{reader: strings.NewReader("hello world")}
+	data, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(data))
+	assert.Equal(t, int64(11), r.total())
+}
+
+func TestContextWarnThreshold(t *testing.T) {
+	oldVal := os.Getenv(contextWarnSizeEnvName)
+	defer func() {
+		_ = os.Setenv(contextWarnSizeEnvName, oldVal)
+	}()
+
+	_ = os.Setenv(contextWarnSizeEnvName, "")
+	assert.Equal(t, int64(defaultContextWarnThreshold), contextWarnThreshold())
+
+	_ = os.Setenv(contextWarnSizeEnvName, "250MB")
+	assert.Equal(t, int64(250*units.MB), contextWarnThreshold())
+
+	_ = os.Setenv(contextWarnSizeEnvName, "not-a-size")
+	assert.Equal(t, int64(defaultContextWarnThreshold), contextWarnThreshold())
+}
@@ -1,8 +1,72 @@
 package build
 
+import (
+	"fmt"
+	"os/exec"
+	"os/user"
+	"regexp"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
 // The image tag prefix can be customized.
 //
 // This allows our integration tests to customize
 // the prefix so that they can write to a public
 // registry without interfering with each other.
 var ImageTagPrefix = "tilt-"
+
+// Characters that aren't allowed in an image tag.
+var invalidTagCharsRegexp = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// Computes the tag for a freshly built image.
+//
+// By default, we tag with a prefix of the image's content hash. A
+// Tiltfile can override the scheme with image_tag_scheme(); the
+// placeholders are expanded here, at tag time, so the tag that's pushed
+// is the tag that's injected into the deployed YAML.
+func tagForBuild(strategy model.TagStrategy, dig digest.Digest, buildPath string) (string, error) {
+	if strategy.Empty() {
+		return digestAsTag(dig)
+	}
+
+	str := dig.Encoded()
+	if len(str) < 16 {
+		return "", fmt.Errorf("digest too short: %s", str)
+	}
+
+	tag := strings.ReplaceAll(strategy.Template, "{hash}", str[:16])
+
+	if strategy.UsesGitSHA() {
+		sha, err := gitHeadSHA(buildPath)
+		if err != nil {
+			return "", err
+		}
+		tag = strings.ReplaceAll(tag, "{gitsha}", sha)
+	}
+
+	if strings.Contains(tag, "{user}") {
+		u, err := user.Current()
+		if err != nil {
+			return "", errors.Wrap(err, "expanding {user} in image tag template")
+		}
+		tag = strings.ReplaceAll(tag, "{user}", invalidTagCharsRegexp.ReplaceAllString(u.Username, "-"))
+	}
+
+	return tag, nil
+}
+
+func gitHeadSHA(buildPath string) (string, error) {
+	out, err := exec.Command("git", "-C", buildPath, "rev-parse", "--short=12", "HEAD").Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("expanding {gitsha} in image tag template: %v\n%s", err, string(ee.Stderr))
+		}
+		return "", errors.Wrap(err, "expanding {gitsha} in image tag template")
+	}
+	return strings.TrimSpace(string(out)), nil
+}
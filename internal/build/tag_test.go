@@ -0,0 +1,52 @@
+package build
+
+import (
+	"os/user"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestTagForBuildDefault(t *testing.T) {
+	dig := digest.Digest("sha256:cc5f4c463f81c55183d8d737ba2f0d30b3e6f3670dbe2da68f0aac168e93fbb1")
+	tag, err := tagForBuild(model.TagStrategy{}, dig, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "tilt-cc5f4c463f81c551"
+	if tag != expected {
+		t.Errorf("Expected %s, actual: %s", expected, tag)
+	}
+}
+
+func TestTagForBuildTemplate(t *testing.T) {
+	u, err := user.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dig := digest.Digest("sha256:cc5f4c463f81c55183d8d737ba2f0d30b3e6f3670dbe2da68f0aac168e93fbb1")
+	tag, err := tagForBuild(model.TagStrategy{Template: "dev-{user}-{hash}"}, dig, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	username := invalidTagCharsRegexp.ReplaceAllString(u.Username, "-")
+	expected := "dev-" + username + "-cc5f4c463f81c551"
+	if tag != expected {
+		t.Errorf("Expected %s, actual: %s", expected, tag)
+	}
+}
+
+func TestTagForBuildShortDigest(t *testing.T) {
+	dig := digest.Digest("sha256:cc")
+	_, err := tagForBuild(model.TagStrategy{Template: "dev-{hash}"}, dig, "")
+	expected := "too short"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected error %q, actual: %v", expected, err)
+	}
+}
@@ -3,10 +3,14 @@ package build
 import (
 	"archive/tar"
 	"context"
+	"crypto/sha256"
+	"fmt"
+	"hash"
 	"io"
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,15 +26,29 @@ type ArchiveBuilder struct {
 	tw     *tar.Writer
 	filter model.PathMatcher
 	paths  []string // local paths archived
+
+	// Optional cache of headers and content hashes from the previous
+	// archive of the same context. May be nil.
+	cache *ContextCache
+
+	// Content hashes of the regular files written to this archive,
+	// keyed by their name in the tar.
+	hashes map[string]string
 }
 
 func NewArchiveBuilder(writer io.Writer, filter model.PathMatcher) *ArchiveBuilder {
+	return NewCachedArchiveBuilder(writer, filter, nil)
+}
+
+// NewCachedArchiveBuilder is like NewArchiveBuilder, but reuses headers
+// and content hashes from previous archives of the same build context.
+func NewCachedArchiveBuilder(writer io.Writer, filter model.PathMatcher, cache *ContextCache) *ArchiveBuilder {
 	tw := tar.NewWriter(writer)
 	if filter == nil {
 		filter = model.EmptyMatcher
 	}
 
-	return &ArchiveBuilder{tw: tw, filter: filter}
+	return &ArchiveBuilder{tw: tw, filter: filter, cache: cache, hashes: make(map[string]string)}
 }
 
 func (a *ArchiveBuilder) Close() error {
@@ -88,7 +106,7 @@ func (a *ArchiveBuilder) ArchivePathsIfExist(ctx context.Context, paths []PathMa
 	// mappings work that we're not sure about.
 	entries := []archiveEntry{}
 	for _, p := range paths {
-		newEntries, err := a.entriesForPath(ctx, p.LocalPath, p.ContainerPath)
+		newEntries, err := a.entriesForPath(ctx, p)
 		if err != nil {
 			return errors.Wrapf(err, "tarPath '%s'", p.LocalPath)
 		}
@@ -104,6 +122,14 @@ func (a *ArchiveBuilder) ArchivePathsIfExist(ctx context.Context, paths []PathMa
 		}
 		a.paths = append(a.paths, entry.path)
 	}
+
+	if a.cache != nil {
+		seen := make(map[string]bool, len(entries))
+		for _, entry := range entries {
+			seen[entry.cacheKey] = true
+		}
+		a.cache.prune(seen)
+	}
 	return nil
 }
 
@@ -112,16 +138,54 @@ func (a *ArchiveBuilder) Paths() []string {
 	return a.paths
 }
 
+// ContentsDigest returns a digest over the names and content hashes of
+// the regular files in the archive, for detecting whether the context
+// changed between builds. Only meaningful when built with a cache.
+func (a *ArchiveBuilder) ContentsDigest() string {
+	names := make([]string, 0, len(a.hashes))
+	for name := range a.hashes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s %s\n", name, a.hashes[name])
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
 type archiveEntry struct {
-	path   string
-	info   os.FileInfo
-	header *tar.Header
+	path     string
+	info     os.FileInfo
+	header   *tar.Header
+	cacheKey string
+
+	// Content hash carried over from the cache, or empty if the file
+	// changed and must be re-hashed as it's written.
+	cachedHash string
 }
 
 // tarPath writes the given source path into tarWriter at the given dest (recursively for directories).
 // e.g. tarring my_dir --> dest d: d/file_a, d/file_b
 // If source path does not exist, quietly skips it and returns no err
-func (a *ArchiveBuilder) entriesForPath(ctx context.Context, localPath, containerPath string) ([]archiveEntry, error) {
+//
+// Modes and symlinks are preserved as they are on disk. Ownership is cleared,
+// unless the mapping carries a RunAs, in which case the files are owned by
+// that uid/gid in the container.
+func (a *ArchiveBuilder) entriesForPath(ctx context.Context, mapping PathMapping) ([]archiveEntry, error) {
+	localPath := mapping.LocalPath
+	containerPath := mapping.ContainerPath
+
+	var runAsUID, runAsGID int
+	if mapping.RunAs != "" {
+		var err error
+		runAsUID, runAsGID, err = model.ParseRunAs(mapping.RunAs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	localInfo, err := os.Stat(localPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -163,6 +227,24 @@ func (a *ArchiveBuilder) entriesForPath(ctx context.Context, localPath, containe
 			return nil
 		}
 
+		// The same local path can land at different container paths (or with
+		// different ownership), so the cache key includes all three.
+		cacheKey := curLocalPath + "|" + containerPath + "|" + mapping.RunAs
+
+		if a.cache != nil {
+			header, cachedHash, ok := a.cache.lookup(cacheKey, info)
+			if ok {
+				result = append(result, archiveEntry{
+					path:       curLocalPath,
+					info:       info,
+					header:     header,
+					cacheKey:   cacheKey,
+					cachedHash: cachedHash,
+				})
+				return nil
+			}
+		}
+
 		linkname := ""
 		if info.Mode()&os.ModeSymlink != 0 {
 			var err error
@@ -182,6 +264,13 @@ func (a *ArchiveBuilder) entriesForPath(ctx context.Context, localPath, containe
 
 		clearUIDAndGID(header)
 
+		if mapping.RunAs != "" {
+			header.Uid = runAsUID
+			header.Gid = runAsGID
+			header.Uname = ""
+			header.Gname = ""
+		}
+
 		if localPathIsDir {
 			// Name of file in tar should be relative to source directory...
 			tmp, err := filepath.Rel(localPath, curLocalPath)
@@ -196,10 +285,16 @@ func (a *ArchiveBuilder) entriesForPath(ctx context.Context, localPath, containe
 			header.Name = containerPath
 		}
 		header.Name = path.Clean(header.Name)
+
+		if a.cache != nil {
+			a.cache.storeHeader(cacheKey, info, header)
+		}
+
 		result = append(result, archiveEntry{
-			path:   curLocalPath,
-			info:   info,
-			header: header,
+			path:     curLocalPath,
+			info:     info,
+			header:   header,
+			cacheKey: cacheKey,
 		})
 
 		return nil
@@ -236,19 +331,37 @@ func (a *ArchiveBuilder) writeEntry(entry archiveEntry) error {
 			_ = file.Close()
 		}()
 
-		_, err = io.CopyN(a.tw, file, info.Size())
+		// If we don't have a cached hash for this file, hash it during
+		// the same read that streams it into the tar.
+		var w io.Writer = a.tw
+		var hasher hash.Hash
+		if a.cache != nil && entry.cachedHash == "" {
+			hasher = sha256.New()
+			w = io.MultiWriter(a.tw, hasher)
+		}
+
+		_, err = io.CopyN(w, file, info.Size())
 		if err != nil && err != io.EOF {
 			return errors.Wrapf(err, "%s: copying Contents", path)
 		}
+
+		if a.cache != nil {
+			contentHash := entry.cachedHash
+			if hasher != nil {
+				contentHash = fmt.Sprintf("%x", hasher.Sum(nil))
+				a.cache.storeHash(entry.cacheKey, contentHash)
+			}
+			a.hashes[header.Name] = contentHash
+		}
 	}
 	return nil
 }
 
-func tarContextAndUpdateDf(ctx context.Context, writer io.Writer, df dockerfile.Dockerfile, paths []PathMapping, filter model.PathMatcher) error {
+func tarContextAndUpdateDf(ctx context.Context, writer io.Writer, df dockerfile.Dockerfile, paths []PathMapping, filter model.PathMatcher, cache *ContextCache) error {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "daemon-tarContextAndUpdateDf")
 	defer span.Finish()
 
-	ab := NewArchiveBuilder(writer, filter)
+	ab := NewCachedArchiveBuilder(writer, filter, cache)
 	err := ab.ArchivePathsIfExist(ctx, paths)
 	if err != nil {
 		return errors.Wrap(err, "archivePaths")
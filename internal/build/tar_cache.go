@@ -0,0 +1,86 @@
+package build
+
+import (
+	"archive/tar"
+	"os"
+	"sync"
+	"time"
+)
+
+// ContextCache remembers the tar layout and content hashes of a build
+// context between builds.
+//
+// On a rebuild, any file whose size, mod time, and mode are unchanged
+// reuses its cached header and hash instead of being re-stat'd into a
+// fresh header and re-hashed. The file's contents still stream into the
+// tar; for changed files, we hash during that same read so hashing
+// overlaps with the Docker API upload rather than adding a second pass.
+type ContextCache struct {
+	mu      sync.Mutex
+	entries map[string]contextCacheEntry
+}
+
+type contextCacheEntry struct {
+	size    int64
+	modTime time.Time
+	mode    os.FileMode
+	header  *tar.Header
+
+	// Hex-encoded sha256 of the file contents. Empty until the file has
+	// been written to a tar at least once.
+	contentHash string
+}
+
+func NewContextCache() *ContextCache {
+	return &ContextCache{entries: make(map[string]contextCacheEntry)}
+}
+
+// lookup returns the cached header and content hash for the given key,
+// if the file's metadata still matches what we saw last build.
+func (c *ContextCache) lookup(key string, info os.FileInfo) (*tar.Header, string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || e.size != info.Size() || !e.modTime.Equal(info.ModTime()) || e.mode != info.Mode() {
+		return nil, "", false
+	}
+
+	// Copy the header so tar-writing can't mutate the cached one.
+	header := *e.header
+	return &header, e.contentHash, true
+}
+
+func (c *ContextCache) storeHeader(key string, info os.FileInfo, header *tar.Header) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	headerCopy := *header
+	c.entries[key] = contextCacheEntry{
+		size:    info.Size(),
+		modTime: info.ModTime(),
+		mode:    info.Mode(),
+		header:  &headerCopy,
+	}
+}
+
+func (c *ContextCache) storeHash(key string, hash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	e.contentHash = hash
+	c.entries[key] = e
+}
+
+// prune drops entries for paths that were not seen on the most recent
+// archive, so deleted files don't accumulate in the cache.
+func (c *ContextCache) prune(seen map[string]bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if !seen[key] {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package build
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestCachedArchiveIdenticalOutput(t *testing.T) {
+	f := newFixture(t)
+	defer f.tearDown()
+
+	f.WriteFile("src/a.txt", "hello world")
+	f.WriteFile("src/sub/b.txt", "goodbye world")
+
+	paths := []PathMapping{
+		PathMapping{
+			LocalPath:     f.JoinPath("src"),
+			ContainerPath: "/src",
+		},
+	}
+
+	cache := NewContextCache()
+	first := f.archiveWithCache(cache, paths)
+	second := f.archiveWithCache(cache, paths)
+
+	assert.Equal(t, first.Bytes(), second.Bytes())
+
+	actual := tar.NewReader(second)
+	f.assertFilesInTar(actual, []expectedFile{
+		expectedFile{Path: "src/a.txt", Contents: "hello world", AssertUidAndGidAreZero: true},
+		expectedFile{Path: "src/sub/b.txt", Contents: "goodbye world"},
+	})
+}
+
+func TestContentsDigestAcrossRebuilds(t *testing.T) {
+	f := newFixture(t)
+	defer f.tearDown()
+
+	f.WriteFile("src/a.txt", "hello world")
+
+	paths := []PathMapping{
+		PathMapping{
+			LocalPath:     f.JoinPath("src"),
+			ContainerPath: "/src",
+		},
+	}
+
+	cache := NewContextCache()
+	buf := new(bytes.Buffer)
+	ab := NewCachedArchiveBuilder(buf, model.EmptyMatcher, cache)
+	require.NoError(t, ab.ArchivePathsIfExist(f.ctx, paths))
+	require.NoError(t, ab.Close())
+	digest := ab.ContentsDigest()
+
+	// Nothing changed, so a rebuild produces the same digest.
+	buf.Reset()
+	ab = NewCachedArchiveBuilder(buf, model.EmptyMatcher, cache)
+	require.NoError(t, ab.ArchivePathsIfExist(f.ctx, paths))
+	require.NoError(t, ab.Close())
+	assert.Equal(t, digest, ab.ContentsDigest())
+
+	// Changing a file changes the digest.
+	f.WriteFile("src/a.txt", "hello again, world")
+	buf.Reset()
+	ab = NewCachedArchiveBuilder(buf, model.EmptyMatcher, cache)
+	require.NoError(t, ab.ArchivePathsIfExist(f.ctx, paths))
+	require.NoError(t, ab.Close())
+	assert.NotEqual(t, digest, ab.ContentsDigest())
+}
+
+func TestCacheDropsDeletedFiles(t *testing.T) {
+	f := newFixture(t)
+	defer f.tearDown()
+
+	f.WriteFile("src/a.txt", "hello world")
+	f.WriteFile("src/b.txt", "goodbye world")
+
+	paths := []PathMapping{
+		PathMapping{
+			LocalPath:     f.JoinPath("src"),
+			ContainerPath: "/src",
+		},
+	}
+
+	cache := NewContextCache()
+	_ = f.archiveWithCache(cache, paths)
+
+	require.NoError(t, os.Remove(f.JoinPath("src/b.txt")))
+
+	second := f.archiveWithCache(cache, paths)
+	actual := tar.NewReader(second)
+	f.assertFilesInTar(actual, []expectedFile{
+		expectedFile{Path: "src/a.txt", Contents: "hello world"},
+		expectedFile{Path: "src/b.txt", Missing: true},
+	})
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	for key := range cache.entries {
+		assert.NotContains(t, key, "b.txt")
+	}
+}
+
+func (f *fixture) archiveWithCache(cache *ContextCache, paths []PathMapping) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	ab := NewCachedArchiveBuilder(buf, model.EmptyMatcher, cache)
+	require.NoError(f.t, ab.ArchivePathsIfExist(f.ctx, paths))
+	require.NoError(f.t, ab.Close())
+	return buf
+}
@@ -198,6 +198,66 @@ func TestArchiveSymlink(t *testing.T) {
 	})
 }
 
+func TestArchiveRunAs(t *testing.T) {
+	f := newFixture(t)
+	buf := new(bytes.Buffer)
+	ab := NewArchiveBuilder(buf, model.EmptyMatcher)
+	defer ab.Close()
+	defer f.tearDown()
+
+	f.WriteFile("src/a.txt", "hello world")
+
+	paths := []PathMapping{
+		PathMapping{
+			LocalPath:     f.JoinPath("src"),
+			ContainerPath: "/src",
+			RunAs:         "1000:2000",
+		},
+	}
+
+	err := ab.ArchivePathsIfExist(f.ctx, paths)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	actual := tar.NewReader(buf)
+	found := false
+	for {
+		header, err := actual.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		assert.Equal(t, 1000, header.Uid, "uid of %s", header.Name)
+		assert.Equal(t, 2000, header.Gid, "gid of %s", header.Name)
+		if header.Name == "src/a.txt" {
+			found = true
+		}
+	}
+	assert.True(t, found, "expected src/a.txt in tar")
+}
+
+func TestArchiveRunAsBadSpec(t *testing.T) {
+	f := newFixture(t)
+	buf := new(bytes.Buffer)
+	ab := NewArchiveBuilder(buf, model.EmptyMatcher)
+	defer ab.Close()
+	defer f.tearDown()
+
+	f.WriteFile("src/a.txt", "hello world")
+
+	err := ab.ArchivePathsIfExist(f.ctx, []PathMapping{
+		PathMapping{
+			LocalPath:     f.JoinPath("src"),
+			ContainerPath: "/src",
+			RunAs:         "tilt-user",
+		},
+	})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "run_as must be of the form 'uid' or 'uid:gid'")
+	}
+}
+
 func TestArchiveSocket(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("Cannot create a unix socket on windows")
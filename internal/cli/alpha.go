@@ -4,8 +4,9 @@ import "github.com/spf13/cobra"
 
 func newAlphaCmd() *cobra.Command {
 	result := &cobra.Command{
-		Use:   "alpha",
-		Short: "unstable/advanced commands still in alpha",
+		Use:     "alpha",
+		Aliases: []string{"x"},
+		Short:   "unstable/advanced commands still in alpha",
 		Long: `Unstable/advanced commands still in alpha; for advanced users only.
 
 The APIs of these commands may change frequently.
@@ -13,6 +14,7 @@ The APIs of these commands may change frequently.
 	}
 
 	addCommand(result, newTiltfileResultCmd())
+	addCommand(result, &portForwardCmd{})
 
 	return result
 }
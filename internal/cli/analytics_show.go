@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/windmilleng/wmclient/pkg/analytics"
+
+	tiltanalytics "github.com/windmilleng/tilt/internal/analytics"
+	"github.com/windmilleng/tilt/pkg/logger"
+)
+
+func newAnalyticsShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Print the analytics data Tilt would report, and the current opt-in status",
+		Long: `Print the analytics data Tilt would report, and the current opt-in status.
+
+Tilt only reports anonymized usage data, and only if you've opted in.
+This command shows exactly which tags would accompany every report, so
+you can see what leaves your machine before deciding.
+
+A Tiltfile can additionally opt a repo out with analytics_settings(enable=False);
+the most restrictive setting wins.
+`,
+		Args: cobra.NoArgs,
+		Run:  showAnalytics,
+	}
+}
+
+func showAnalytics(cmd *cobra.Command, args []string) {
+	a, err := newAnalytics(logger.NewLogger(logger.InfoLvl, os.Stdout))
+	if err != nil {
+		cmdFail(fmt.Errorf("Error initializing analytics: %v", err))
+	}
+
+	envOpt := "(not set)"
+	if ok, reason := tiltanalytics.IsAnalyticsDisabledFromEnv(); ok {
+		envOpt = fmt.Sprintf("out (%s)", reason)
+	}
+
+	fmt.Printf("User setting (tilt analytics opt): %s\n", a.UserOpt())
+	fmt.Printf("Environment setting:               %s\n", envOpt)
+	fmt.Printf("Effective:                         %s\n", a.EffectiveOpt())
+	fmt.Println()
+	fmt.Println("If a Tiltfile calls analytics_settings(enable=False), that repo is also opted out.")
+	fmt.Println()
+
+	if a.EffectiveOpt() == analytics.OptOut {
+		fmt.Println("Analytics are disabled. Nothing will be reported.")
+		return
+	}
+
+	fmt.Println("Tags reported with every event:")
+	printTag := func(name string) {
+		if v, ok := a.GlobalTag(name); ok {
+			fmt.Printf("  %-12s %s\n", name+":", v)
+		}
+	}
+	printTag(tiltanalytics.TagVersion)
+	printTag(tiltanalytics.TagOS)
+	printTag(analytics.TagUser)
+	printTag(analytics.TagMachine)
+	printTag(tiltanalytics.TagGitRepoHash)
+
+	fmt.Println()
+	fmt.Printf("Event names are Tilt commands and lifecycle events (e.g. cmd.up, up.running); ")
+	fmt.Printf("they never include resource names, file paths, or image names.\n")
+}
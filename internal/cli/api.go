@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/windmilleng/tilt/internal/tiltfile"
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+)
+
+func newApiCmd() *cobra.Command {
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "api [builtin]",
+		Short: "Show the Tiltfile API of this binary",
+		Long: `Shows the builtins a Tiltfile can call in this binary.
+
+With no arguments, lists every builtin. With a builtin name, shows its
+signature and documentation. With --json, dumps the whole API as JSON,
+suitable for generating editor autocomplete from the binary itself.
+`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			docs, err := tiltfile.ApiDocs(provideTiltInfo())
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(docs)
+			}
+
+			if len(args) == 1 {
+				return printApiDoc(docs, args[0])
+			}
+
+			for _, doc := range docs {
+				fmt.Println(doc.Name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Dump the full API as JSON")
+
+	return cmd
+}
+
+func printApiDoc(docs []starkit.BuiltinDoc, name string) error {
+	for _, doc := range docs {
+		if doc.Name != name {
+			continue
+		}
+
+		if doc.Signature != "" {
+			fmt.Println(doc.Signature)
+		} else {
+			fmt.Println(doc.Name)
+		}
+		if doc.Summary != "" {
+			fmt.Printf("\n%s\n", doc.Summary)
+		}
+		if len(doc.Args) > 0 {
+			fmt.Println()
+			for _, arg := range doc.Args {
+				if arg.Type != "" {
+					fmt.Printf("  %s (%s): %s\n", arg.Name, arg.Type, arg.Doc)
+				} else {
+					fmt.Printf("  %s: %s\n", arg.Name, arg.Doc)
+				}
+			}
+		}
+		if doc.Signature == "" && doc.Summary == "" {
+			fmt.Println("(no documentation recorded for this builtin)")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no builtin named %q; run `tilt api` for the full list", name)
+}
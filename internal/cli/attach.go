@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/spf13/cobra"
+
+	"github.com/windmilleng/tilt/pkg/model"
+	"github.com/windmilleng/tilt/pkg/model/logstore"
+	proto_webview "github.com/windmilleng/tilt/pkg/webview"
+)
+
+var attachHost string
+
+func newAttachCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attach",
+		Short: "Connect the terminal to an already-running Tilt session",
+		Long: `Connect the terminal to an already-running Tilt session.
+
+Streams logs and resource status changes from a Tilt server that's
+running in the background or on a remote host, over the same websocket
+the web UI uses. The session keeps running when you detach (Ctrl-C).
+`,
+		Args: cobra.NoArgs,
+		Run:  attachSession,
+	}
+	cmd.Flags().IntVar(&webPort, "port", DefaultWebPort, "Port for the Tilt HTTP server")
+	cmd.Flags().StringVar(&attachHost, "host", "localhost", "Host for the Tilt HTTP server")
+	return cmd
+}
+
+func attachSession(cmd *cobra.Command, args []string) {
+	u := url.URL{Scheme: "ws", Host: fmt.Sprintf("%s:%d", attachHost, webPort), Path: "/ws/view"}
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), nil)
+	if err != nil {
+		cmdFail(fmt.Errorf("Could not connect to Tilt at %s: %v\nIs Tilt running? (tilt up)", u.String(), err))
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	fmt.Printf("Attached to Tilt at %s:%d. Detach with Ctrl-C; the session keeps running.\n", attachHost, webPort)
+
+	streamer := newViewStreamer(os.Stdout)
+	jspb := &runtime.JSONPb{OrigName: false, EmitDefaults: true}
+	for {
+		_, reader, err := conn.NextReader()
+		if err != nil {
+			cmdFail(fmt.Errorf("Lost connection to Tilt: %v", err))
+		}
+
+		view := &proto_webview.View{}
+		if err := jspb.NewDecoder(reader).Decode(view); err != nil {
+			cmdFail(fmt.Errorf("Error reading view from Tilt: %v", err))
+		}
+
+		streamer.handle(view)
+
+		if err := ackView(conn, jspb, view); err != nil {
+			cmdFail(fmt.Errorf("Lost connection to Tilt: %v", err))
+		}
+	}
+}
+
+// Acknowledge the logs we've seen, so the server only sends us
+// new segments on the next update.
+func ackView(conn *websocket.Conn, jspb *runtime.JSONPb, view *proto_webview.View) error {
+	if view.LogList == nil || view.LogList.ToCheckpoint <= 0 {
+		return nil
+	}
+	ack := &proto_webview.AckWebsocketRequest{
+		ToCheckpoint:  view.LogList.ToCheckpoint,
+		TiltStartTime: view.TiltStartTime,
+	}
+	data, err := jspb.Marshal(ack)
+	if err != nil {
+		return err
+	}
+	return conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// Prints logs and resource status transitions from a stream of views.
+type viewStreamer struct {
+	out           io.Writer
+	atLineStart   bool
+	runtimeStatus map[string]string
+}
+
+func newViewStreamer(out io.Writer) *viewStreamer {
+	return &viewStreamer{
+		out:           out,
+		atLineStart:   true,
+		runtimeStatus: make(map[string]string),
+	}
+}
+
+func (s *viewStreamer) handle(view *proto_webview.View) {
+	s.printLogs(view.LogList)
+
+	for _, r := range view.Resources {
+		if r.RuntimeStatus == "" {
+			continue
+		}
+		old, seen := s.runtimeStatus[r.Name]
+		if seen && old != r.RuntimeStatus {
+			fmt.Fprintf(s.out, "%s→ %s is now %s\n", s.lineBreak(), r.Name, r.RuntimeStatus)
+		}
+		s.runtimeStatus[r.Name] = r.RuntimeStatus
+	}
+}
+
+func (s *viewStreamer) printLogs(logList *proto_webview.LogList) {
+	if logList == nil || logList.FromCheckpoint == -1 {
+		return
+	}
+
+	for _, segment := range logList.Segments {
+		prefix := ""
+		if span, ok := logList.Spans[segment.SpanId]; ok && span.ManifestName != "" {
+			prefix = logstore.SourcePrefix(model.ManifestName(span.ManifestName))
+		}
+		s.write(prefix, segment.Text)
+	}
+}
+
+// Writes a segment of log text, inserting the source prefix at the start
+// of each line. Segments aren't necessarily line-aligned.
+func (s *viewStreamer) write(prefix string, text string) {
+	for len(text) > 0 {
+		if s.atLineStart {
+			fmt.Fprint(s.out, prefix)
+		}
+
+		i := strings.IndexByte(text, '\n')
+		if i == -1 {
+			fmt.Fprint(s.out, text)
+			s.atLineStart = false
+			return
+		}
+
+		fmt.Fprint(s.out, text[:i+1])
+		s.atLineStart = true
+		text = text[i+1:]
+	}
+}
+
+// If we're interrupting a partial log line, start a fresh one.
+func (s *viewStreamer) lineBreak() string {
+	if s.atLineStart {
+		return ""
+	}
+	s.atLineStart = true
+	return "\n"
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/windmilleng/tilt/pkg/model/logstore"
+	proto_webview "github.com/windmilleng/tilt/pkg/webview"
+)
+
+func TestViewStreamerPrefixesLogLines(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := newViewStreamer(out)
+
+	s.handle(&proto_webview.View{
+		LogList: &proto_webview.LogList{
+			Spans: map[string]*proto_webview.LogSpan{
+				"span:fe": {ManifestName: "fe"},
+			},
+			Segments: []*proto_webview.LogSegment{
+				{SpanId: "span:fe", Text: "building"},
+				{SpanId: "span:fe", Text: " image\ndone\n"},
+			},
+			ToCheckpoint: 2,
+		},
+	})
+
+	prefix := logstore.SourcePrefix("fe")
+	assert.Equal(t, prefix+"building image\n"+prefix+"done\n", out.String())
+}
+
+func TestViewStreamerReportsStatusChanges(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := newViewStreamer(out)
+
+	s.handle(&proto_webview.View{
+		Resources: []*proto_webview.Resource{{Name: "fe", RuntimeStatus: "pending"}},
+	})
+	assert.Empty(t, out.String(), "first sighting shouldn't be reported as a transition")
+
+	s.handle(&proto_webview.View{
+		Resources: []*proto_webview.Resource{{Name: "fe", RuntimeStatus: "ok"}},
+	})
+	assert.Equal(t, "→ fe is now ok\n", out.String())
+}
@@ -11,13 +11,28 @@ import (
 
 	"github.com/windmilleng/tilt/internal/analytics"
 	"github.com/windmilleng/tilt/internal/cloud"
+	"github.com/windmilleng/tilt/internal/engine/exit"
 	"github.com/windmilleng/tilt/internal/store"
 	"github.com/windmilleng/tilt/pkg/logger"
 )
 
+// The exit controller is wired up before cobra parses the ci flags,
+// so these need to be globals, like logActionsFlag.
+var ciTimeoutFlag time.Duration
+var ciResourceTimeoutFlag time.Duration
+
+func provideExitTimeouts() exit.Timeouts {
+	return exit.Timeouts{
+		Global:   ciTimeoutFlag,
+		Resource: ciResourceTimeoutFlag,
+	}
+}
+
 type ciCmd struct {
-	fileName             string
-	outputSnapshotOnExit string
+	fileName                string
+	outputSnapshotOnExit    string
+	outputSnapshotOnFailure string
+	reportFile              string
 }
 
 func (c *ciCmd) register() *cobra.Command {
@@ -45,6 +60,18 @@ While Tilt is running, you can view the UI at %s:%d
 	cmd.Flags().Lookup("logactions").Hidden = true
 	cmd.Flags().StringVar(&c.outputSnapshotOnExit, "output-snapshot-on-exit", "",
 		"If specified, Tilt will dump a snapshot of its state to the specified path when it exits")
+	cmd.Flags().StringVar(&c.outputSnapshotOnFailure, "output-snapshot-on-failure", "",
+		"If specified, Tilt will dump a snapshot of its state to the specified path when it exits with failure, so CI failures can be debugged after the runner is gone")
+	cmd.Flags().DurationVar(&ciTimeoutFlag, "timeout", 0,
+		"If set, Tilt will exit with failure if all resources haven't become ready within the given duration (e.g., 30m)")
+	cmd.Flags().DurationVar(&ciResourceTimeoutFlag, "resource-timeout", 0,
+		"If set, Tilt will exit with failure if any individual resource hasn't become ready within the given duration of its last deploy (e.g., 5m)")
+	cmd.Flags().StringVar(&outputModeFlag, "output", "",
+		"If 'json', Tilt will emit newline-delimited JSON progress events to stdout")
+	addSnapshotFlags(cmd)
+	addMaxMemoryFlag(cmd)
+	cmd.Flags().StringVar(&c.reportFile, "output-report", "",
+		"If specified, Tilt will write a machine-readable report of the run to the given path when it exits (JUnit XML for .xml paths, JSON otherwise)")
 
 	return cmd
 }
@@ -86,6 +113,16 @@ func (c *ciCmd) run(ctx context.Context, args []string) error {
 	err = upper.Start(ctx, args, cmdCIDeps.TiltBuild, engineMode,
 		c.fileName, false, a.UserOpt(), cmdCIDeps.Token,
 		string(cmdCIDeps.CloudAddress))
+	if err != nil && c.outputSnapshotOnFailure != "" {
+		cloud.WriteSnapshot(ctx, cmdCIDeps.Store, c.outputSnapshotOnFailure)
+	}
+	if c.reportFile != "" {
+		reportErr := writeCIReport(c.reportFile, buildCIReport(cmdCIDeps.Store, err))
+		if reportErr != nil {
+			logger.Get(ctx).Infof("Error writing CI report: %v", reportErr)
+		}
+	}
+	printSessionSummary(cmdCIDeps.History, cmdCIDeps.Store)
 	if err == nil {
 		fmt.Println(color.GreenString("SUCCESS. All workloads are healthy."))
 	}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// A machine-readable record of what happened to each resource during a
+// `tilt ci` run, for ingestion by CI dashboards.
+type ciResourceReport struct {
+	Name          string        `json:"name" xml:"-"`
+	BuildDuration time.Duration `json:"build_duration_ns" xml:"-"`
+	DeployTime    time.Time     `json:"deploy_time" xml:"-"`
+	RuntimeStatus string        `json:"runtime_status" xml:"-"`
+	Error         string        `json:"error,omitempty" xml:"-"`
+}
+
+type ciReport struct {
+	StartTime time.Time          `json:"start_time"`
+	Duration  time.Duration      `json:"duration_ns"`
+	Error     string             `json:"error,omitempty"`
+	Resources []ciResourceReport `json:"resources"`
+}
+
+func buildCIReport(st store.RStore, runErr error) ciReport {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	report := ciReport{
+		StartTime: state.TiltStartTime,
+		Duration:  time.Since(state.TiltStartTime),
+	}
+	if runErr != nil {
+		report.Error = runErr.Error()
+	}
+
+	for _, mt := range state.Targets() {
+		r := ciResourceReport{
+			Name:          string(mt.Manifest.Name),
+			BuildDuration: mt.State.LastBuild().Duration(),
+			DeployTime:    mt.State.LastSuccessfulDeployTime,
+		}
+
+		if err := mt.State.LastBuild().Error; err != nil {
+			r.Error = err.Error()
+		}
+
+		rs := mt.State.RuntimeState
+		if rs == nil {
+			r.RuntimeStatus = string(model.RuntimeStatusPending)
+		} else {
+			r.RuntimeStatus = string(rs.RuntimeStatus())
+			if r.Error == "" && rs.RuntimeStatusError() != nil {
+				r.Error = rs.RuntimeStatusError().Error()
+			}
+		}
+
+		report.Resources = append(report.Resources, r)
+	}
+
+	return report
+}
+
+// Write the report to the given path. The format is inferred from the file
+// extension: .xml gets JUnit XML, anything else gets JSON.
+func writeCIReport(path string, report ciReport) error {
+	var data []byte
+	var err error
+	if filepath.Ext(path) == ".xml" {
+		data, err = junitXML(report)
+	} else {
+		data, err = json.MarshalIndent(report, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("generating CI report: %v", err)
+	}
+
+	err = ioutil.WriteFile(path, data, 0644)
+	if err != nil {
+		return fmt.Errorf("writing CI report: %v", err)
+	}
+	return nil
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+type junitTestCase struct {
+	XMLName   xml.Name      `xml:"testcase"`
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      float64       `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitTestSuite struct {
+	XMLName  xml.Name        `xml:"testsuite"`
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Time     float64         `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+func junitXML(report ciReport) ([]byte, error) {
+	suite := junitTestSuite{
+		Name:  "tilt-ci",
+		Tests: len(report.Resources),
+		Time:  report.Duration.Seconds(),
+	}
+
+	for _, r := range report.Resources {
+		c := junitTestCase{
+			Name:      r.Name,
+			ClassName: "tilt-ci",
+			Time:      r.BuildDuration.Seconds(),
+		}
+		if r.Error != "" {
+			c.Failure = &junitFailure{Message: r.Error}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+
+	data, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/internal/k8s/testyaml"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils/manifestbuilder"
+	"github.com/windmilleng/tilt/internal/testutils/tempdir"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestCIReportJSON(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	report := reportForTest(t, f)
+	path := filepath.Join(f.Path(), "report.json")
+	require.NoError(t, writeCIReport(path, report))
+
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), `"name": "fe"`)
+	assert.Contains(t, string(contents), `"runtime_status": "pending"`)
+	assert.Contains(t, string(contents), `"error": "build failed"`)
+}
+
+func TestCIReportJUnitXML(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	report := reportForTest(t, f)
+	path := filepath.Join(f.Path(), "report.xml")
+	require.NoError(t, writeCIReport(path, report))
+
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), `<testsuite name="tilt-ci" tests="1" failures="1"`)
+	assert.Contains(t, string(contents), `<failure message="build failed"`)
+}
+
+func reportForTest(t *testing.T, f *tempdir.TempDirFixture) ciReport {
+	st := store.NewTestingStore()
+	st.WithState(func(state *store.EngineState) {
+		state.TiltStartTime = time.Now().Add(-time.Minute)
+
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now().Add(-30 * time.Second),
+			FinishTime: time.Now(),
+			Error:      fmt.Errorf("build failed"),
+		})
+	})
+
+	return buildCIReport(st, fmt.Errorf("build failed"))
+}
@@ -47,21 +47,37 @@ Tilt watches your files for edits, automatically builds your container images,
 and applies any changes to bring your environment
 up-to-date in real-time. Think 'docker build && kubectl apply' or 'docker-compose up'.
 `,
+		BashCompletionFunction: bashCompletionFunc,
 	}
 
 	addCommand(rootCmd, &ciCmd{})
 	addCommand(rootCmd, &upCmd{})
 	addCommand(rootCmd, &dockerCmd{})
+	addCommand(rootCmd, &execCmd{})
 	addCommand(rootCmd, &doctorCmd{})
+	addCommand(rootCmd, newLintCmd())
 	addCommand(rootCmd, newDownCmd())
 	addCommand(rootCmd, &versionCmd{})
 	addCommand(rootCmd, &dockerPruneCmd{})
 	addCommand(rootCmd, newArgsCmd())
 
-	rootCmd.AddCommand(analytics.NewCommand())
+	analyticsCmd := analytics.NewCommand()
+	analyticsCmd.AddCommand(newAnalyticsShowCmd())
+	rootCmd.AddCommand(analyticsCmd)
 	rootCmd.AddCommand(newKubectlCmd())
 	rootCmd.AddCommand(newDumpCmd(rootCmd))
 	rootCmd.AddCommand(newTriggerCmd())
+	rootCmd.AddCommand(newGetCmd())
+	rootCmd.AddCommand(newDescribeCmd())
+	rootCmd.AddCommand(newExplainCmd())
+	rootCmd.AddCommand(newAttachCmd())
+	rootCmd.AddCommand(newSnapshotCmd())
+	rootCmd.AddCommand(newExtCmd())
+	rootCmd.AddCommand(newApiCmd())
+	rootCmd.AddCommand(newLspCmd())
+	rootCmd.AddCommand(newWaitCmd())
+	rootCmd.AddCommand(newStatusCmd())
+	rootCmd.AddCommand(newCompletionCmd())
 	rootCmd.AddCommand(newAlphaCmd())
 
 	if len(os.Args) > 2 && os.Args[1] == "kubectl" {
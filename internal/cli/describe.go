@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/spf13/cobra"
+
+	"github.com/windmilleng/tilt/pkg/webview"
+)
+
+func newDescribeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe RESOURCE_NAME",
+		Short: "Print detailed status for a resource in a running Tilt",
+		Long: `Print detailed status for a resource in a running Tilt.
+
+Queries the Tilt HTTP server (so Tilt must already be running) and prints
+the resource's runtime status, last build, pods, endpoints, and recent
+errors, similar to 'kubectl describe'.
+`,
+		Args: cobra.ExactArgs(1),
+		Run:  describeResource,
+	}
+	cmd.Flags().IntVar(&webPort, "port", DefaultWebPort, "Port for the Tilt HTTP server")
+	cmd.Flags().StringVarP(&getOutputFlag, "output", "o", "", "Output format. One of: json|yaml")
+	return cmd
+}
+
+func describeResource(cmd *cobra.Command, args []string) {
+	name := args[0]
+
+	view := fetchView(webPort)
+	var resource *webview.Resource
+	for _, r := range view.Resources {
+		if r.Name == name {
+			resource = r
+			break
+		}
+	}
+	if resource == nil {
+		cmdFail(fmt.Errorf("No resource found with name %q. Run 'tilt get resources' to list resources.", name))
+	}
+
+	switch getOutputFlag {
+	case "", "text":
+		printResourceDetail(os.Stdout, resource)
+	case "json", "yaml":
+		printResourceData(os.Stdout, resource, getOutputFlag)
+	default:
+		cmdFail(fmt.Errorf("Unknown output format %q. Must be one of: json|yaml", getOutputFlag))
+	}
+}
+
+func printResourceDetail(w io.Writer, r *webview.Resource) {
+	fmt.Fprintf(w, "Name:           %s\n", r.Name)
+	fmt.Fprintf(w, "Runtime Status: %s\n", emptyDash(r.RuntimeStatus))
+	fmt.Fprintf(w, "Update Status:  %s\n", updateSummary(r))
+	fmt.Fprintf(w, "Last Deploy:    %s\n", timestampSummary(r.LastDeployTime))
+
+	if info := r.K8SResourceInfo; info != nil && info.PodName != "" {
+		fmt.Fprintf(w, "Pod:            %s\n", podSummary(r))
+	}
+	if len(r.Endpoints) > 0 {
+		fmt.Fprintf(w, "Endpoints:      %s\n", strings.Join(r.Endpoints, ", "))
+	}
+
+	if len(r.BuildHistory) > 0 {
+		fmt.Fprintf(w, "Build History:\n")
+		for _, b := range r.BuildHistory {
+			result := "ok"
+			if b.Error != "" {
+				result = fmt.Sprintf("error: %s", b.Error)
+			}
+			fmt.Fprintf(w, "  %s  (%s)  %s\n",
+				timestampSummary(b.StartTime), buildDuration(b), result)
+		}
+	}
+
+	errs := recentErrors(r)
+	if len(errs) > 0 {
+		fmt.Fprintf(w, "Recent Errors:\n")
+		for _, e := range errs {
+			fmt.Fprintf(w, "  %s\n", e)
+		}
+	}
+}
+
+// Pulls together the error states a user would otherwise have to dig
+// through the web UI for.
+func recentErrors(r *webview.Resource) []string {
+	var errs []string
+	if len(r.BuildHistory) > 0 && r.BuildHistory[0].Error != "" {
+		errs = append(errs, fmt.Sprintf("build: %s", r.BuildHistory[0].Error))
+	}
+	if info := r.K8SResourceInfo; info != nil && info.PodStatusMessage != "" {
+		errs = append(errs, fmt.Sprintf("pod: %s", info.PodStatusMessage))
+	}
+	if r.CrashLog != "" {
+		line := strings.SplitN(strings.TrimSpace(r.CrashLog), "\n", 2)[0]
+		errs = append(errs, fmt.Sprintf("crash: %s", line))
+	}
+	return errs
+}
+
+func timestampSummary(ts *timestamp.Timestamp) string {
+	t, err := ptypes.Timestamp(ts)
+	if err != nil || t.IsZero() {
+		return "never"
+	}
+	return t.Local().Format(time.RFC1123)
+}
+
+func buildDuration(b *webview.BuildRecord) string {
+	start, err := ptypes.Timestamp(b.StartTime)
+	if err != nil {
+		return "-"
+	}
+	finish, err := ptypes.Timestamp(b.FinishTime)
+	if err != nil || finish.IsZero() {
+		return "in progress"
+	}
+	return finish.Sub(start).Truncate(time.Millisecond).String()
+}
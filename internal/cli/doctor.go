@@ -3,7 +3,10 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"runtime"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -11,6 +14,7 @@ import (
 	wmanalytics "github.com/windmilleng/wmclient/pkg/analytics"
 
 	"github.com/windmilleng/tilt/internal/analytics"
+	"github.com/windmilleng/tilt/internal/k8s"
 )
 
 type doctorCmd struct {
@@ -118,6 +122,14 @@ func (c *doctorCmd) run(ctx context.Context, args []string) error {
 	kVersion, err := wireK8sVersion(ctx)
 	printField("Version", kVersion, err)
 
+	kClient, kClientErr := wireK8sClient(ctx)
+	if kClientErr == nil {
+		c.printConnectivity(ctx, kClient)
+		c.printPermissions(ctx, kClient, ns)
+	}
+
+	c.printFileWatchLimits()
+
 	fmt.Println("---")
 	fmt.Println("Thanks for seeing the Tilt Doctor!")
 	fmt.Println("Please send the info above when filing bug reports. 💗")
@@ -146,6 +158,104 @@ func (c *doctorCmd) run(ctx context.Context, args []string) error {
 	return nil
 }
 
+func (c *doctorCmd) printConnectivity(ctx context.Context, kClient k8s.Client) {
+	err := kClient.ConnectedToCluster(ctx)
+	if err != nil {
+		printField("Cluster Reachable", nil, err)
+		fmt.Println("  → Check that your cluster is running and your kubeconfig points at it (kubectl config current-context)")
+		return
+	}
+	printField("Cluster Reachable", "yes", nil)
+}
+
+// The verbs Tilt needs to deploy and watch resources. If any are missing,
+// builds or watches will fail with RBAC errors, so surface them here with
+// a suggested fix.
+func (c *doctorCmd) printPermissions(ctx context.Context, kClient k8s.Client, ns k8s.Namespace) {
+	checks := []struct {
+		group    string
+		resource string
+		verbs    []string
+	}{
+		{"", "pods", []string{"get", "list", "watch"}},
+		{"", "services", []string{"get", "list", "watch"}},
+		{"", "events", []string{"list", "watch"}},
+		{"apps", "deployments", []string{"get", "list", "create", "update", "delete"}},
+	}
+
+	fmt.Println("---")
+	fmt.Println("Kubernetes Permissions")
+
+	anyMissing := false
+	for _, check := range checks {
+		var missing []string
+		var reviewErr error
+		for _, verb := range check.verbs {
+			allowed, err := kClient.CanI(ctx, verb, check.group, check.resource)
+			if err != nil {
+				reviewErr = err
+				break
+			}
+			if !allowed {
+				missing = append(missing, verb)
+			}
+		}
+
+		name := check.resource
+		if check.group != "" {
+			name = fmt.Sprintf("%s.%s", check.resource, check.group)
+		}
+
+		if reviewErr != nil {
+			printField(name, nil, reviewErr)
+		} else if len(missing) > 0 {
+			anyMissing = true
+			fmt.Printf("- %s: missing %v\n", name, missing)
+		} else {
+			printField(name, "ok", nil)
+		}
+	}
+
+	if anyMissing {
+		fmt.Printf("  → Ask your cluster admin for edit access to namespace %q, e.g.:\n", ns.String())
+		fmt.Printf("    kubectl create rolebinding tilt-edit --clusterrole=edit --user=<your-user> -n %s\n", ns.String())
+	}
+}
+
+// On Linux, Tilt's file watches consume inotify resources. If the limits
+// are too low, watches fail in confusing ways, so report them with the
+// sysctl to raise them.
+func (c *doctorCmd) printFileWatchLimits() {
+	if runtime.GOOS != "linux" {
+		return
+	}
+
+	fmt.Println("---")
+	fmt.Println("File Watch Limits")
+
+	watches, err := readProcInt("/proc/sys/fs/inotify/max_user_watches")
+	printField("inotify max_user_watches", strconv.Itoa(watches), err)
+	if err == nil && watches < 65536 {
+		fmt.Println("  → This may be too low for large projects. Increase it with:")
+		fmt.Println("    sudo sysctl fs.inotify.max_user_watches=524288")
+	}
+
+	instances, err := readProcInt("/proc/sys/fs/inotify/max_user_instances")
+	printField("inotify max_user_instances", strconv.Itoa(instances), err)
+	if err == nil && instances < 128 {
+		fmt.Println("  → This may be too low. Increase it with:")
+		fmt.Println("    sudo sysctl fs.inotify.max_user_instances=512")
+	}
+}
+
+func readProcInt(path string) (int, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(contents)))
+}
+
 func printField(name string, v interface{}, err error) {
 	if err != nil {
 		fmt.Printf("- %s: Error: %v\n", name, err)
@@ -19,6 +19,7 @@ import (
 type downCmd struct {
 	fileName         string
 	deleteNamespaces bool
+	deleteCluster    bool
 	downDepsProvider func(ctx context.Context, tiltAnalytics *analytics.TiltAnalytics) (DownDeps, error)
 }
 
@@ -51,6 +52,8 @@ In that case, see https://tilt.dev/user_config.html and/or comments in your Tilt
 
 	addTiltfileFlag(cmd, &c.fileName)
 	cmd.Flags().BoolVar(&c.deleteNamespaces, "delete-namespaces", false, "delete namespaces defined in the Tiltfile (by default, don't)")
+	cmd.Flags().BoolVar(&c.deleteCluster, "delete-cluster", false, "delete the cluster created by 'tilt up --provision-cluster' (requires --provision-cluster)")
+	addClusterProvisionFlags(cmd)
 
 	return cmd
 }
@@ -82,7 +85,12 @@ func (c *downCmd) down(ctx context.Context, downDeps DownDeps, args []string) er
 	if !c.deleteNamespaces {
 		var namespaces []k8s.K8sEntity
 		entities, namespaces, err = k8s.Filter(entities, func(e k8s.K8sEntity) (b bool, err error) {
-			return e.GVK() != schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Namespace"}, nil
+			if e.GVK() != (schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Namespace"}) {
+				return true, nil
+			}
+			// Namespaces generated by dev_namespace() exist only for this
+			// developer's resources, so always clean them up.
+			return e.Labels()[k8s.DevNamespaceLabel] == "true", nil
 		})
 		if err != nil {
 			return errors.Wrap(err, "filtering out namespaces")
@@ -97,7 +105,19 @@ func (c *downCmd) down(ctx context.Context, downDeps DownDeps, args []string) er
 		}
 	}
 
-	if len(entities) > 0 {
+	if c.deleteCluster {
+		// Deleting the whole cluster supersedes deleting individual resources.
+		p, err := k8s.NewClusterProvisioner(provisionClusterFlag)
+		if err != nil {
+			return errors.Wrap(err, "--delete-cluster requires --provision-cluster")
+		}
+
+		logger.Get(ctx).Infof("Deleting %s cluster %q", provisionClusterFlag, provisionClusterName)
+		err = p.Delete(ctx, provisionClusterName)
+		if err != nil {
+			return err
+		}
+	} else if len(entities) > 0 {
 		err = downDeps.kClient.Delete(ctx, entities)
 		if err != nil {
 			return errors.Wrap(err, "Deleting k8s entities")
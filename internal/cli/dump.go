@@ -1,11 +1,15 @@
 package cli
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -29,6 +33,7 @@ and may change frequently.
 	result.AddCommand(newDumpWebviewCmd())
 	result.AddCommand(newDumpEngineCmd())
 	result.AddCommand(newDumpLogStoreCmd())
+	result.AddCommand(newDumpProfileCmd())
 	result.AddCommand(newDumpCliDocsCmd(rootCmd))
 
 	return result
@@ -92,6 +97,97 @@ and may change frequently.
 	return cmd
 }
 
+type dumpProfileCmd struct {
+	duration time.Duration
+	out      string
+}
+
+func newDumpProfileCmd() *cobra.Command {
+	c := &dumpProfileCmd{}
+
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "dump a performance profile of the running Tilt process",
+		Long: `Collects CPU, heap, and goroutine profiles from the running Tilt process
+and writes them to a tar.gz bundle.
+
+The CPU profile samples the process for --duration; the other profiles are
+point-in-time. Inspect the results with 'go tool pprof', or attach the bundle
+to a performance issue so Tilt developers can.
+`,
+		Run:  c.run,
+		Args: cobra.NoArgs,
+	}
+	cmd.Flags().IntVar(&webPort, "port", DefaultWebPort, "Port for the Tilt HTTP server")
+	cmd.Flags().DurationVar(&c.duration, "duration", 30*time.Second, "How long to sample the CPU profile")
+	cmd.Flags().StringVar(&c.out, "out", "", "Output path for the profile bundle (default tilt_profile_<timestamp>.tar.gz)")
+	return cmd
+}
+
+func (c *dumpProfileCmd) run(cmd *cobra.Command, args []string) {
+	out := c.out
+	if out == "" {
+		out = fmt.Sprintf("tilt_profile_%s.tar.gz", time.Now().Format("20060102_150405"))
+	}
+
+	seconds := int(c.duration.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+
+	profiles := []struct {
+		filename string
+		path     string
+	}{
+		{"cpu.pprof", fmt.Sprintf("pprof/profile?seconds=%d", seconds)},
+		{"heap.pprof", "pprof/heap"},
+		{"goroutine.pprof", "pprof/goroutine"},
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		cmdFail(fmt.Errorf("dump profile: %v", err))
+	}
+	gzw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gzw)
+
+	fmt.Printf("Sampling CPU profile for %s\n", c.duration)
+	for _, p := range profiles {
+		body := debugGet(webPort, p.path)
+		b, err := ioutil.ReadAll(body)
+		_ = body.Close()
+		if err != nil {
+			cmdFail(fmt.Errorf("dump profile %s: %v", p.filename, err))
+		}
+
+		err = tw.WriteHeader(&tar.Header{
+			Name:    p.filename,
+			Mode:    0644,
+			Size:    int64(len(b)),
+			ModTime: time.Now(),
+		})
+		if err == nil {
+			_, err = tw.Write(b)
+		}
+		if err != nil {
+			cmdFail(fmt.Errorf("dump profile %s: %v", p.filename, err))
+		}
+	}
+
+	err = tw.Close()
+	if err == nil {
+		err = gzw.Close()
+	}
+	if err == nil {
+		err = f.Close()
+	}
+	if err != nil {
+		cmdFail(fmt.Errorf("dump profile: %v", err))
+	}
+
+	fmt.Printf("Wrote profile bundle to %s\n", out)
+}
+
 type dumpCliDocsCmd struct {
 	rootCmd *cobra.Command
 	dir     string
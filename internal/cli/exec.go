@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/windmilleng/tilt/internal/analytics"
+	"github.com/windmilleng/tilt/internal/container"
+	"github.com/windmilleng/tilt/internal/docker"
+	"github.com/windmilleng/tilt/internal/k8s"
+)
+
+type execCmd struct {
+	container string
+}
+
+func (c *execCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "exec <resource> [-- command ...]",
+		DisableFlagsInUseLine: true,
+		Short:                 "Open a shell in a resource's running container",
+		Long: `
+Opens a shell (or runs the given command) in the container backing a
+resource, using the same Docker and Kubernetes environments Tilt uses.
+
+For docker-compose resources, this execs into the service's container.
+For Kubernetes resources, this execs into the resource's current pod.
+`,
+		Example: "tilt exec app\ntilt exec app -- ls /var/log",
+	}
+	cmd.Flags().StringVarP(&c.container, "container", "c", "",
+		"Container to exec into, for multi-container pods")
+	return cmd
+}
+
+func (c *execCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	a.Incr("cmd.exec", map[string]string{})
+	defer a.Flush(time.Second)
+
+	if len(args) == 0 {
+		return fmt.Errorf("usage: tilt exec <resource> [-- command ...]")
+	}
+	resource := args[0]
+	command := args[1:]
+	if len(command) == 0 {
+		command = []string{"/bin/sh"}
+	}
+
+	// Try docker-compose first: it doesn't need a cluster connection,
+	// and compose service names are the resource names.
+	ran, err := c.composeExec(ctx, resource, command)
+	if ran {
+		return err
+	}
+
+	kCli, err := wireK8sClient(ctx)
+	if err != nil {
+		return err
+	}
+	ns, err := wireNamespace(ctx)
+	if err != nil {
+		return err
+	}
+
+	selector := labels.Set{k8s.ManifestNameLabel: resource}.AsSelector()
+	pods, err := kCli.ListPods(ctx, ns, selector)
+	if err != nil {
+		return errors.Wrap(err, "listing pods")
+	}
+
+	var pod *v1.Pod
+	for i, p := range pods {
+		if p.Status.Phase == v1.PodRunning {
+			pod = &pods[i]
+			break
+		}
+	}
+	if pod == nil {
+		return fmt.Errorf("no running container or pod found for resource %q; is it up?", resource)
+	}
+
+	cName, err := pickContainer(pod, c.container)
+	if err != nil {
+		return err
+	}
+
+	return kCli.Exec(ctx, k8s.PodIDFromPod(pod), cName, k8s.NamespaceFromPod(pod),
+		command, os.Stdin, os.Stdout, os.Stderr)
+}
+
+// Exec into the docker-compose container for the given service, if there is
+// one. Returns false if the resource doesn't resolve to a compose container
+// (including when Docker itself isn't reachable), so the caller can fall
+// back to Kubernetes.
+func (c *execCmd) composeExec(ctx context.Context, service string, command []string) (bool, error) {
+	client, err := wireDockerLocalClient(ctx)
+	if err != nil || client.CheckConnected() != nil {
+		return false, nil
+	}
+
+	containers, err := client.ContainerList(ctx, types.ContainerListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("label", fmt.Sprintf("com.docker.compose.service=%s", service))),
+	})
+	if err != nil || len(containers) == 0 {
+		return false, nil
+	}
+
+	// Shell out to the docker CLI so the user gets a real interactive TTY,
+	// with the same environment Tilt itself uses (like `tilt docker`).
+	dockerArgs := append([]string{"exec", "-it", containers[0].ID}, command...)
+	cmd := exec.CommandContext(ctx, "docker", dockerArgs...)
+	cmd.Env = append(os.Environ(), docker.Env(client.Env()).AsEnviron()...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return true, cmd.Run()
+}
+
+// Choose the container to exec into. With one app container there's nothing
+// to choose; with several, the user has to pick with --container.
+func pickContainer(pod *v1.Pod, name string) (container.Name, error) {
+	if name != "" {
+		for _, c := range pod.Spec.Containers {
+			if c.Name == name {
+				return container.Name(name), nil
+			}
+		}
+		return "", fmt.Errorf("no container %q in pod %s", name, pod.Name)
+	}
+
+	if len(pod.Spec.Containers) == 0 {
+		return "", fmt.Errorf("no containers in pod %s", pod.Name)
+	}
+
+	// If the only other containers are mesh-injected sidecars (istio-proxy,
+	// linkerd-proxy), exec into the app container. The sidecars are still
+	// reachable with an explicit --container.
+	var appContainers []v1.Container
+	for _, c := range pod.Spec.Containers {
+		if k8s.IsMeshSidecar(container.Name(c.Name)) {
+			continue
+		}
+		appContainers = append(appContainers, c)
+	}
+	if len(appContainers) == 1 {
+		return container.Name(appContainers[0].Name), nil
+	}
+
+	if len(pod.Spec.Containers) > 1 {
+		names := make([]string, len(pod.Spec.Containers))
+		for i, c := range pod.Spec.Containers {
+			names[i] = c.Name
+		}
+		return "", fmt.Errorf("pod %s has more than one container (%s); choose one with --container",
+			pod.Name, strings.Join(names, ", "))
+	}
+	return container.Name(pod.Spec.Containers[0].Name), nil
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/windmilleng/tilt/internal/container"
+)
+
+func execTestPod(containers ...string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "fe-abc123"},
+	}
+	for _, c := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: c})
+	}
+	return pod
+}
+
+func TestPickContainerSingle(t *testing.T) {
+	name, err := pickContainer(execTestPod("main"), "")
+	assert.NoError(t, err)
+	assert.Equal(t, container.Name("main"), name)
+}
+
+func TestPickContainerMultipleNeedsFlag(t *testing.T) {
+	_, err := pickContainer(execTestPod("main", "sidecar"), "")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "main, sidecar")
+		assert.Contains(t, err.Error(), "--container")
+	}
+
+	name, err := pickContainer(execTestPod("main", "sidecar"), "sidecar")
+	assert.NoError(t, err)
+	assert.Equal(t, container.Name("sidecar"), name)
+}
+
+func TestPickContainerNotFound(t *testing.T) {
+	_, err := pickContainer(execTestPod("main"), "nope")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `no container "nope" in pod fe-abc123`)
+	}
+}
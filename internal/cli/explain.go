@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/windmilleng/tilt/pkg/model"
+	"github.com/windmilleng/tilt/pkg/webview"
+)
+
+func newExplainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain RESOURCE_NAME",
+		Short: "Explain why a resource is in its current state",
+		Long: `Explain why a resource is in its current state.
+
+Queries the Tilt HTTP server (so Tilt must already be running) and
+narrates how the resource got where it is: what triggered the last
+build, which files changed, and what it's still waiting on (resource
+deps, pod scheduling, link health checks).
+`,
+		Args: cobra.ExactArgs(1),
+		Run:  explainResource,
+	}
+	cmd.Flags().IntVar(&webPort, "port", DefaultWebPort, "Port for the Tilt HTTP server")
+	return cmd
+}
+
+func explainResource(cmd *cobra.Command, args []string) {
+	name := args[0]
+
+	view := fetchView(webPort)
+	var resource *webview.Resource
+	for _, r := range view.Resources {
+		if r.Name == name {
+			resource = r
+			break
+		}
+	}
+	if resource == nil {
+		cmdFail(fmt.Errorf("No resource found with name %q. Run 'tilt get resources' to list resources.", name))
+	}
+
+	printExplanation(os.Stdout, resource)
+}
+
+func printExplanation(w io.Writer, r *webview.Resource) {
+	state := emptyDash(r.ResourceState)
+	if r.ResourceStateReason != "" {
+		fmt.Fprintf(w, "%s is %s: %s\n", r.Name, state, r.ResourceStateReason)
+	} else {
+		fmt.Fprintf(w, "%s is %s\n", r.Name, state)
+	}
+
+	if b := lastFinishedBuild(r); b != nil {
+		fmt.Fprintf(w, "\nLast update:\n")
+		fmt.Fprintf(w, "  finished %s (took %s)\n", timestampSummary(b.FinishTime), buildDuration(b))
+		fmt.Fprintf(w, "  triggered by: %s\n", buildReasonSummary(b.BuildReason))
+		if len(b.Edits) > 0 {
+			fmt.Fprintf(w, "  changed files: %s\n", strings.Join(b.Edits, ", "))
+		}
+		if b.Error != "" {
+			fmt.Fprintf(w, "  failed: %s\n", b.Error)
+		}
+	}
+
+	if r.CurrentBuild != nil && r.CurrentBuild.StartTime != nil {
+		fmt.Fprintf(w, "\nUpdate in progress:\n")
+		fmt.Fprintf(w, "  started %s\n", timestampSummary(r.CurrentBuild.StartTime))
+		fmt.Fprintf(w, "  triggered by: %s\n", buildReasonSummary(r.CurrentBuild.BuildReason))
+		if len(r.CurrentBuild.Edits) > 0 {
+			fmt.Fprintf(w, "  changed files: %s\n", strings.Join(r.CurrentBuild.Edits, ", "))
+		}
+	}
+
+	if r.HasPendingChanges || r.Queued {
+		fmt.Fprintf(w, "\nPending:\n")
+		if r.Queued {
+			fmt.Fprintf(w, "  queued for update\n")
+		}
+		if r.HasPendingChanges {
+			fmt.Fprintf(w, "  pending changes since %s: %s\n",
+				timestampSummary(r.PendingBuildSince), strings.Join(r.PendingBuildEdits, ", "))
+			if !model.TriggerMode(r.TriggerMode).AutoOnChange() {
+				fmt.Fprintf(w, "  waiting for the user to trigger an update (manual trigger mode)\n")
+			}
+		}
+	}
+
+	waits := waitingOn(r)
+	if len(waits) > 0 {
+		fmt.Fprintf(w, "\nWaiting on:\n")
+		for _, wait := range waits {
+			fmt.Fprintf(w, "  %s\n", wait)
+		}
+	}
+}
+
+// The most recent build that actually ran to completion; the first
+// history entry is the current build while one is in flight.
+func lastFinishedBuild(r *webview.Resource) *webview.BuildRecord {
+	for _, b := range r.BuildHistory {
+		if b.FinishTime != nil {
+			return b
+		}
+	}
+	return nil
+}
+
+func buildReasonSummary(reason int32) string {
+	s := model.BuildReason(reason).String()
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// Everything the resource is still blocked on at runtime, so the user
+// doesn't have to piece it together from pod state and link health.
+func waitingOn(r *webview.Resource) []string {
+	var waits []string
+	if info := r.K8SResourceInfo; info != nil {
+		if info.PodName == "" {
+			if r.LastDeployTime != nil {
+				waits = append(waits, "a pod to be scheduled")
+			}
+		} else if !info.AllContainersReady {
+			wait := fmt.Sprintf("pod %s (status %s) to have all containers ready", info.PodName, emptyDash(info.PodStatus))
+			if info.PodStatusMessage != "" {
+				wait = fmt.Sprintf("%s: %s", wait, info.PodStatusMessage)
+			}
+			waits = append(waits, wait)
+		}
+	}
+	for _, l := range r.Links {
+		switch l.HealthStatus {
+		case "down":
+			waits = append(waits, fmt.Sprintf("link %s to pass its health check", l.Url))
+		case "unknown":
+			waits = append(waits, fmt.Sprintf("link %s to be probed", l.Url))
+		}
+	}
+	return waits
+}
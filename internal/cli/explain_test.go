@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/windmilleng/tilt/pkg/model"
+	"github.com/windmilleng/tilt/pkg/webview"
+)
+
+func TestExplainBuildAndPending(t *testing.T) {
+	start, _ := ptypes.TimestampProto(time.Now().Add(-5 * time.Minute))
+	finish, _ := ptypes.TimestampProto(time.Now().Add(-4 * time.Minute))
+
+	r := &webview.Resource{
+		Name:                "fe",
+		ResourceState:       "degraded",
+		ResourceStateReason: "build failed: exit status 1",
+		TriggerMode:         int32(model.TriggerModeManualAfterInitial),
+		BuildHistory: []*webview.BuildRecord{{
+			StartTime:   start,
+			FinishTime:  finish,
+			Edits:       []string{"main.go"},
+			Error:       "exit status 1",
+			BuildReason: int32(model.BuildReasonFlagChangedFiles),
+		}},
+		HasPendingChanges: true,
+		PendingBuildEdits: []string{"main.go", "util.go"},
+		PendingBuildSince: start,
+	}
+
+	out := &bytes.Buffer{}
+	printExplanation(out, r)
+
+	s := out.String()
+	assert.Contains(t, s, "fe is degraded: build failed: exit status 1")
+	assert.Contains(t, s, "triggered by: Changed Files")
+	assert.Contains(t, s, "changed files: main.go")
+	assert.Contains(t, s, "failed: exit status 1")
+	assert.Contains(t, s, "pending changes since")
+	assert.Contains(t, s, "waiting for the user to trigger an update")
+}
+
+func TestExplainWaitingOn(t *testing.T) {
+	deploy, _ := ptypes.TimestampProto(time.Now().Add(-time.Minute))
+
+	r := &webview.Resource{
+		Name:           "be",
+		ResourceState:  "deploying",
+		LastDeployTime: deploy,
+		K8SResourceInfo: &webview.K8SResourceInfo{
+			PodName:            "be-abc123",
+			PodStatus:          "Pending",
+			PodStatusMessage:   "0/3 nodes are available",
+			AllContainersReady: false,
+		},
+		Links: []*webview.Link{
+			{Url: "http://localhost:8000/", HealthStatus: "down"},
+			{Url: "http://localhost:9000/"},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	printExplanation(out, r)
+
+	s := out.String()
+	assert.Contains(t, s, "be is deploying")
+	assert.Contains(t, s, "pod be-abc123 (status Pending) to have all containers ready: 0/3 nodes are available")
+	assert.Contains(t, s, "link http://localhost:8000/ to pass its health check")
+	assert.NotContains(t, s, "http://localhost:9000")
+}
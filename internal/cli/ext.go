@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/windmilleng/tilt/internal/tiltfile"
+	"github.com/windmilleng/tilt/internal/tiltfile/tiltextension"
+)
+
+// Manage the extensions (`load("ext://...")` modules) cached in the
+// tilt_modules directory next to the Tiltfile.
+func newExtCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ext",
+		Short: "Manage Tilt extensions (load(\"ext://...\") modules)",
+	}
+	cmd.AddCommand(newExtListCmd())
+	cmd.AddCommand(newExtUpdateCmd())
+	return cmd
+}
+
+func newExtListCmd() *cobra.Command {
+	var fileName string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the extensions this project has fetched",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := extStoreForTiltfile(fileName)
+			if err != nil {
+				return err
+			}
+
+			extensions, err := store.List(context.Background())
+			if err != nil {
+				return err
+			}
+			if len(extensions) == 0 {
+				fmt.Println("No extensions installed.")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tCOMMIT\tFETCHED\tREGISTRY")
+			for _, e := range extensions {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+					e.Name, shortRef(e.GitCommitHash),
+					e.TimeFetched.Format(time.RFC3339), e.ExtensionRegistry)
+			}
+			return w.Flush()
+		},
+	}
+	addTiltfileFlag(cmd, &fileName)
+	return cmd
+}
+
+func newExtUpdateCmd() *cobra.Command {
+	var fileName string
+	cmd := &cobra.Command{
+		Use:   "update [<extension>[@<ref>]...]",
+		Short: "Re-fetch extensions from the extension repo",
+		Long: `Re-fetches extensions from the extension repo and replaces the cached copies.
+
+With no arguments, updates every extension recorded in tilt_modules/extensions.json
+to the head of the repo. Individual extensions can be pinned to a commit, tag,
+or branch with an @ref suffix, e.g. tilt ext update restart_process@v1.2.0.
+`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := extStoreForTiltfile(fileName)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				extensions, err := store.List(context.Background())
+				if err != nil {
+					return err
+				}
+				if len(extensions) == 0 {
+					fmt.Println("No extensions installed.")
+					return nil
+				}
+				for _, e := range extensions {
+					args = append(args, e.Name)
+				}
+			}
+
+			fetcher := tiltextension.NewGithubFetcher()
+			for _, moduleName := range args {
+				err := updateExtension(context.Background(), fetcher, store, moduleName)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	addTiltfileFlag(cmd, &fileName)
+	return cmd
+}
+
+func updateExtension(ctx context.Context, fetcher tiltextension.Fetcher, store tiltextension.Store, moduleName string) error {
+	contents, err := fetcher.Fetch(ctx, moduleName)
+	if err != nil {
+		return err
+	}
+	_, err = store.Write(ctx, contents)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Updated %s to %s\n", contents.Name, shortRef(contents.GitCommitHash))
+	return nil
+}
+
+func extStoreForTiltfile(fileName string) (*tiltextension.LocalStore, error) {
+	if fileName == "" {
+		fileName = tiltfile.FileName
+	}
+	absPath, err := filepath.Abs(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return tiltextension.NewLocalStore(filepath.Dir(absPath)), nil
+}
+
+// Full commit SHAs are unwieldy in a table; tags and branch names pass
+// through unchanged.
+func shortRef(ref string) string {
+	if len(ref) == 40 {
+		return ref[:7]
+	}
+	return ref
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/windmilleng/tilt/pkg/webview"
+)
+
+var getOutputFlag string
+
+func newGetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get resources",
+		Short: "Print the status of resources in a running Tilt",
+		Long: `Print the status of resources in a running Tilt.
+
+Queries the Tilt HTTP server (so Tilt must already be running) and prints
+one line per resource with its runtime status, last update, pod, and
+endpoints, similar to 'kubectl get'.
+`,
+		Args: cobra.ExactArgs(1),
+		Run:  getResources,
+	}
+	cmd.Flags().IntVar(&webPort, "port", DefaultWebPort, "Port for the Tilt HTTP server")
+	cmd.Flags().StringVarP(&getOutputFlag, "output", "o", "", "Output format. One of: json|yaml")
+	return cmd
+}
+
+func getResources(cmd *cobra.Command, args []string) {
+	kind := args[0]
+	if kind != "resources" && kind != "resource" && kind != "res" {
+		cmdFail(fmt.Errorf("Unknown kind %q. Only \"resources\" is supported.", kind))
+	}
+
+	view := fetchView(webPort)
+	switch getOutputFlag {
+	case "", "table":
+		printResourceTable(os.Stdout, view.Resources)
+	case "json", "yaml":
+		printResourceData(os.Stdout, view.Resources, getOutputFlag)
+	default:
+		cmdFail(fmt.Errorf("Unknown output format %q. Must be one of: json|yaml", getOutputFlag))
+	}
+}
+
+// Fetches the current view of the world from the running Tilt server.
+func fetchView(webPort int) *webview.View {
+	body := apiGet(webPort, "view")
+	defer func() {
+		_ = body.Close()
+	}()
+
+	view := &webview.View{}
+	jspb := &runtime.JSONPb{OrigName: false, EmitDefaults: true}
+	if err := jspb.NewDecoder(body).Decode(view); err != nil {
+		cmdFail(fmt.Errorf("Error reading view from Tilt: %v", err))
+	}
+	return view
+}
+
+func printResourceTable(w io.Writer, resources []*webview.Resource) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTATUS\tUPDATE\tPOD\tENDPOINTS")
+	for _, r := range resources {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			r.Name,
+			emptyDash(r.RuntimeStatus),
+			updateSummary(r),
+			podSummary(r),
+			emptyDash(strings.Join(r.Endpoints, ", ")))
+	}
+	_ = tw.Flush()
+}
+
+func printResourceData(w io.Writer, data interface{}, format string) {
+	// The server serializes with jsonpb, so round-trip through it
+	// to keep field names consistent with /api/view.
+	jspb := &runtime.JSONPb{OrigName: false, EmitDefaults: true}
+	jsonData, err := jspb.Marshal(data)
+	if err != nil {
+		cmdFail(fmt.Errorf("Error serializing resources: %v", err))
+	}
+
+	if format == "yaml" {
+		var generic interface{}
+		if err := yaml.Unmarshal(jsonData, &generic); err != nil {
+			cmdFail(fmt.Errorf("Error serializing resources: %v", err))
+		}
+		yamlData, err := yaml.Marshal(generic)
+		if err != nil {
+			cmdFail(fmt.Errorf("Error serializing resources: %v", err))
+		}
+		fmt.Fprintf(w, "%s", yamlData)
+		return
+	}
+
+	var indented json.RawMessage = jsonData
+	pretty, err := json.MarshalIndent(indented, "", "  ")
+	if err != nil {
+		cmdFail(fmt.Errorf("Error serializing resources: %v", err))
+	}
+	fmt.Fprintf(w, "%s\n", pretty)
+}
+
+// A one-word summary of where the resource is in its update lifecycle.
+func updateSummary(r *webview.Resource) string {
+	if r.CurrentBuild != nil && r.CurrentBuild.StartTime != nil {
+		return "in_progress"
+	}
+	if len(r.BuildHistory) > 0 {
+		last := r.BuildHistory[0]
+		if last.Error != "" {
+			return "error"
+		}
+		if r.HasPendingChanges {
+			return "pending"
+		}
+		return "ok"
+	}
+	if r.HasPendingChanges || r.Queued {
+		return "pending"
+	}
+	return "none"
+}
+
+func podSummary(r *webview.Resource) string {
+	info := r.K8SResourceInfo
+	if info == nil || info.PodName == "" {
+		return "-"
+	}
+	if info.PodStatus == "" {
+		return info.PodName
+	}
+	return fmt.Sprintf("%s (%s)", info.PodName, info.PodStatus)
+}
+
+func emptyDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/pkg/webview"
+)
+
+func TestGetResourceTable(t *testing.T) {
+	out := &bytes.Buffer{}
+	printResourceTable(out, []*webview.Resource{
+		resourceForGetTest(t, "fe", ""),
+		resourceForGetTest(t, "be", "compile error"),
+	})
+
+	s := out.String()
+	assert.Contains(t, s, "NAME")
+	assert.Contains(t, s, "fe")
+	assert.Contains(t, s, "ok")
+	assert.Contains(t, s, "pod-fe (Running)")
+	assert.Contains(t, s, "http://localhost:9000")
+	assert.Contains(t, s, "error")
+}
+
+func TestDescribeResourceDetail(t *testing.T) {
+	out := &bytes.Buffer{}
+	printResourceDetail(out, resourceForGetTest(t, "be", "compile error"))
+
+	s := out.String()
+	assert.Contains(t, s, "Name:           be")
+	assert.Contains(t, s, "Runtime Status: ok")
+	assert.Contains(t, s, "Update Status:  error")
+	assert.Contains(t, s, "Pod:            pod-be (Running)")
+	assert.Contains(t, s, "Build History:")
+	assert.Contains(t, s, "error: compile error")
+	assert.Contains(t, s, "Recent Errors:")
+	assert.Contains(t, s, "build: compile error")
+}
+
+func TestGetResourceJSON(t *testing.T) {
+	out := &bytes.Buffer{}
+	printResourceData(out, []*webview.Resource{resourceForGetTest(t, "fe", "")}, "json")
+	assert.Contains(t, out.String(), `"name": "fe"`)
+
+	out.Reset()
+	printResourceData(out, resourceForGetTest(t, "fe", ""), "yaml")
+	assert.Contains(t, out.String(), "name: fe")
+}
+
+func resourceForGetTest(t *testing.T, name string, buildError string) *webview.Resource {
+	start, err := ptypes.TimestampProto(time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	finish, err := ptypes.TimestampProto(time.Now().Add(-50 * time.Second))
+	require.NoError(t, err)
+
+	return &webview.Resource{
+		Name:           name,
+		RuntimeStatus:  "ok",
+		LastDeployTime: finish,
+		BuildHistory: []*webview.BuildRecord{
+			{StartTime: start, FinishTime: finish, Error: buildError},
+		},
+		K8SResourceInfo: &webview.K8SResourceInfo{
+			PodName:   "pod-" + name,
+			PodStatus: "Running",
+		},
+		Endpoints: []string{"http://localhost:9000"},
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/windmilleng/tilt/internal/analytics"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// Statically checks the Tiltfile without connecting to a cluster or a
+// docker daemon, so it can run as a pre-commit hook.
+type lintCmd struct {
+	fileName string
+}
+
+var _ tiltCmd = &lintCmd{}
+
+func newLintCmd() *lintCmd {
+	return &lintCmd{}
+}
+
+func (c *lintCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "lint [-- <Tiltfile args>]",
+		Aliases: []string{"verify"},
+		Short:   "Verify that the Tiltfile parses and evaluates, without deploying anything",
+		Long: fmt.Sprintf(`Verify that the Tiltfile parses and evaluates, without deploying anything.
+
+Executes the Tiltfile in a dry run: no images are built and nothing is
+deployed, but errors like syntax errors, missing files referenced by
+read_file/docker_build, undefined config flags, and duplicate resources
+are reported.
+
+Exit code 0: the Tiltfile evaluated successfully
+Exit code 1: some failure in setup
+Exit code %d: error when evaluating the Tiltfile
+
+Suitable for use as a pre-commit hook.`, TiltfileErrExitCode),
+	}
+
+	addTiltfileFlag(cmd, &c.fileName)
+
+	return cmd
+}
+
+func (c *lintCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	a.Incr("cmd.lint", nil)
+	defer a.Flush(time.Second)
+
+	deps, err := wireTiltfileResult(ctx, a)
+	if err != nil {
+		return errors.Wrap(err, "wiring dependencies")
+	}
+
+	tlr := deps.tfl.Load(ctx, c.fileName, model.NewUserConfigState(args))
+	if tlr.Error != nil {
+		fmt.Fprintln(os.Stderr, tlr.Error)
+		os.Exit(TiltfileErrExitCode)
+	}
+
+	for _, m := range tlr.Manifests {
+		if err := m.Validate(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(TiltfileErrExitCode)
+		}
+	}
+
+	fmt.Printf("OK: %d resources\n", len(tlr.Manifests))
+	return nil
+}
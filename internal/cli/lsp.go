@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/windmilleng/tilt/internal/lsp"
+	"github.com/windmilleng/tilt/internal/tiltfile"
+)
+
+func newLspCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lsp",
+		Short: "Run a Tiltfile language server over stdio",
+		Long: `Runs a minimal language server for Tiltfiles, speaking the Language
+Server Protocol over stdin/stdout.
+
+It serves document symbols, go-to-definition (including across load()
+statements), hover documentation for every builtin in this binary, and
+syntax diagnostics as you type. Point your editor's LSP client at
+"tilt lsp" for Tiltfiles.
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			docs, err := tiltfile.ApiDocs(provideTiltInfo())
+			if err != nil {
+				return err
+			}
+			return lsp.NewServer(os.Stdin, os.Stdout, docs).Run()
+		},
+	}
+}
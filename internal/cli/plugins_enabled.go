@@ -0,0 +1,8 @@
+// +build tiltplugins
+
+package cli
+
+// Linked only when building with -tags tiltplugins, so plugin
+// registration files can live in the plugins package without affecting
+// the default build. See the plugins package docs.
+import _ "github.com/windmilleng/tilt/plugins"
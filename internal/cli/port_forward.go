@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/windmilleng/tilt/internal/analytics"
+)
+
+type portForwardCmd struct {
+	remove bool
+	host   string
+}
+
+func (c *portForwardCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "port-forward <resource> <local-port>[:<container-port>]",
+		Short: "Add or remove a port forward on a running Tilt resource",
+		Long: `Add or remove a port forward on a running Tilt resource,
+without editing the Tiltfile.
+
+The forward follows the resource's current pod, reconnecting as pods come
+and go, and shows up as an endpoint in the UI. If the container port is
+omitted, Tilt forwards to the pod's default container port.
+
+Forwards added this way last until they're removed or Tilt exits.
+`,
+		Example: "tilt alpha port-forward frontend 8080:80\ntilt alpha port-forward frontend 8080 --remove",
+		Args:    cobra.ExactArgs(2),
+	}
+	cmd.Flags().IntVar(&webPort, "port", DefaultWebPort, "Port for the Tilt HTTP server")
+	cmd.Flags().BoolVar(&c.remove, "remove", false, "Remove the forward on the given local port instead of adding one")
+	cmd.Flags().StringVar(&c.host, "host", "", "Host to bind the local port to (localhost by default)")
+	return cmd
+}
+
+func (c *portForwardCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	a.Incr("cmd.portForward", map[string]string{})
+	defer a.Flush(time.Second)
+
+	resource := args[0]
+	localPort, containerPort, err := parsePortSpec(args[1])
+	if err != nil {
+		return err
+	}
+
+	payload := []byte(fmt.Sprintf(
+		`{"manifest_name":%q,"local_port":%d,"container_port":%d,"host":%q,"remove":%v}`,
+		resource, localPort, containerPort, c.host, c.remove))
+
+	body := apiPostJson(webPort, "port_forward", payload)
+	_ = body.Close()
+
+	if c.remove {
+		fmt.Printf("Removed port forward %d for resource: %q\n", localPort, resource)
+	} else {
+		fmt.Printf("Forwarding port %d for resource: %q\n", localPort, resource)
+	}
+	return nil
+}
+
+// Parse "8080" or "8080:80" into a local and container port.
+func parsePortSpec(spec string) (localPort int, containerPort int, err error) {
+	parts := strings.SplitN(spec, ":", 2)
+	localPort, err = strconv.Atoi(parts[0])
+	if err != nil || localPort <= 0 {
+		return 0, 0, fmt.Errorf("invalid local port %q", parts[0])
+	}
+	if len(parts) == 2 {
+		containerPort, err = strconv.Atoi(parts[1])
+		if err != nil || containerPort <= 0 {
+			return 0, 0, fmt.Errorf("invalid container port %q", parts[1])
+		}
+	}
+	return localPort, containerPort, nil
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/pkg/browser"
+	"github.com/spf13/cobra"
+
+	proto_webview "github.com/windmilleng/tilt/pkg/webview"
+)
+
+// Default port for the offline snapshot viewer. Deliberately not
+// DefaultWebPort, so it can run alongside a live 'tilt up'.
+const DefaultSnapshotViewPort = 10351
+
+var snapshotViewPort int
+var snapshotNoBrowser bool
+
+func newSnapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save and view snapshots of Tilt's state",
+	}
+	cmd.AddCommand(newSnapshotSaveCmd())
+	cmd.AddCommand(newSnapshotViewCmd())
+	return cmd
+}
+
+func newSnapshotSaveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "save FILE",
+		Short: "Save a snapshot of the running Tilt session to a file",
+		Long: `Save a snapshot of the running Tilt session to a file.
+
+The snapshot captures the full session state: resources, build history,
+and logs. Share the file with a teammate, who can explore it with
+'tilt snapshot view FILE' — no cloud account needed.
+`,
+		Args: cobra.ExactArgs(1),
+		Run:  saveSnapshot,
+	}
+	cmd.Flags().IntVar(&webPort, "port", DefaultWebPort, "Port for the Tilt HTTP server")
+	return cmd
+}
+
+func saveSnapshot(cmd *cobra.Command, args []string) {
+	path := args[0]
+
+	body := apiGet(webPort, "snapshot/current")
+	defer func() {
+		_ = body.Close()
+	}()
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		cmdFail(fmt.Errorf("Could not open %s: %v", path, err))
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if _, err := io.Copy(f, body); err != nil {
+		cmdFail(fmt.Errorf("Error writing snapshot to %s: %v", path, err))
+	}
+
+	fmt.Printf("Saved snapshot to %s\n", path)
+}
+
+func newSnapshotViewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "view FILE",
+		Short: "Serve a saved snapshot in the Tilt web UI",
+		Long: `Serve a saved snapshot in the Tilt web UI.
+
+Starts a local web server that renders the snapshot exactly as the
+session looked when it was captured. Works entirely offline.
+`,
+		Args: cobra.ExactArgs(1),
+		Run:  viewSnapshot,
+	}
+	cmd.Flags().IntVar(&snapshotViewPort, "port", DefaultSnapshotViewPort, "Port for the snapshot viewer")
+	cmd.Flags().BoolVar(&snapshotNoBrowser, "no-browser", false, "If true, the snapshot viewer will not open a browser on startup")
+	return cmd
+}
+
+func viewSnapshot(cmd *cobra.Command, args []string) {
+	path := args[0]
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		cmdFail(fmt.Errorf("Could not read %s: %v", path, err))
+	}
+	if err := validateSnapshot(contents); err != nil {
+		cmdFail(fmt.Errorf("%s does not look like a Tilt snapshot: %v", path, err))
+	}
+
+	info := provideTiltInfo()
+	webMode, err := provideWebMode(info)
+	if err != nil {
+		cmdFail(err)
+	}
+	assetServer, err := provideAssetServer(webMode, provideWebVersion(info))
+	if err != nil {
+		cmdFail(fmt.Errorf("Error serving web assets: %v", err))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = assetServer.Serve(ctx)
+	}()
+
+	url := fmt.Sprintf("http://localhost:%d/snapshot/local", snapshotViewPort)
+	fmt.Printf("Serving snapshot at %s\n", url)
+	if !snapshotNoBrowser {
+		_ = browser.OpenURL(url)
+	}
+
+	err = http.ListenAndServe(fmt.Sprintf(":%d", snapshotViewPort), snapshotHandler(contents, assetServer))
+	if err != nil {
+		cmdFail(fmt.Errorf("Error serving snapshot: %v", err))
+	}
+}
+
+// Serves the snapshot JSON where the web UI expects to fetch it, and
+// everything else from the asset server.
+func snapshotHandler(contents []byte, assets http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasPrefix(req.URL.Path, "/api/snapshot/") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write(contents)
+			return
+		}
+		assets.ServeHTTP(w, req)
+	})
+}
+
+func validateSnapshot(contents []byte) error {
+	snapshot := &proto_webview.Snapshot{}
+	jspb := &runtime.JSONPb{OrigName: false, EmitDefaults: true}
+	if err := jspb.NewDecoder(bytes.NewReader(contents)).Decode(snapshot); err != nil {
+		return err
+	}
+	if snapshot.View == nil {
+		return fmt.Errorf("no view in snapshot")
+	}
+	return nil
+}
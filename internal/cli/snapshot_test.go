@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/pkg/assets"
+)
+
+func TestValidateSnapshot(t *testing.T) {
+	good := []byte(`{"view": {"resources": [{"name": "fe"}]}}`)
+	assert.NoError(t, validateSnapshot(good))
+
+	assert.Error(t, validateSnapshot([]byte(`{}`)), "missing view")
+	assert.Error(t, validateSnapshot([]byte(`not json`)))
+}
+
+func TestSnapshotHandler(t *testing.T) {
+	contents := []byte(`{"view": {}}`)
+	handler := snapshotHandler(contents, assets.NewFakeServer())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/snapshot/local", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, string(contents), rec.Body.String())
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	// Non-API paths fall through to the asset server.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/snapshot/local", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
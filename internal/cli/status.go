@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/windmilleng/tilt/internal/objects"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// Exit codes for `tilt status`, chosen so scripts can branch on them.
+const (
+	statusExitReady      = 0
+	statusExitError      = 1
+	statusExitInProgress = 2
+)
+
+func newStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status [resource]",
+		Short: "Print resource status and exit with a code scripts can branch on",
+		Long: `Prints the state of each resource in a running Tilt, then exits with:
+
+  0  all resources are ready
+  1  at least one resource is in an error state
+  2  no errors, but at least one resource is still in progress
+
+With a resource name, only that resource is considered. Useful in
+Makefiles and shell scripts, e.g.:
+
+  until tilt status frontend; do sleep 1; done
+`,
+		Args: cobra.MaximumNArgs(1),
+		Run:  runStatus,
+	}
+	cmd.Flags().IntVar(&webPort, "port", DefaultWebPort, "Port for the Tilt HTTP server")
+	return cmd
+}
+
+func runStatus(cmd *cobra.Command, args []string) {
+	statuses := fetchResourceStatuses(webPort)
+	if len(args) == 1 {
+		name := args[0]
+		filtered := make([]resourceStatusItem, 0, 1)
+		for _, item := range statuses {
+			if item.Metadata.Name == name {
+				filtered = append(filtered, item)
+			}
+		}
+		if len(filtered) == 0 {
+			cmdFail(fmt.Errorf("No resource %q in the running Tilt", name))
+		}
+		statuses = filtered
+	}
+
+	printResourceStatuses(os.Stdout, statuses)
+	os.Exit(statusExitCode(statuses))
+}
+
+type resourceStatusItem struct {
+	Metadata objects.ObjectMeta     `json:"metadata"`
+	Status   objects.ResourceStatus `json:"status"`
+}
+
+func fetchResourceStatuses(webPort int) []resourceStatusItem {
+	url := apiURL(webPort, "objects/resources")
+	res, err := http.Get(url)
+	if err != nil {
+		cmdFail(fmt.Errorf("Could not connect to Tilt at %s: %v", url, err))
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode != http.StatusOK {
+		cmdFail(fmt.Errorf("Request to %s failed with status %q", url, res.Status))
+	}
+
+	var list struct {
+		Items []resourceStatusItem `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&list); err != nil {
+		cmdFail(fmt.Errorf("Error decoding response from %s: %v", url, err))
+	}
+	return list.Items
+}
+
+func printResourceStatuses(w io.Writer, statuses []resourceStatusItem) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSTATE\tREASON")
+	for _, item := range statuses {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n",
+			item.Metadata.Name,
+			item.Status.State,
+			emptyDash(item.Status.StateReason))
+	}
+	_ = tw.Flush()
+}
+
+// Errors trump in-progress: a script retrying on exit code 2 shouldn't
+// spin forever on a resource that needs a human. Disabled resources
+// (manual trigger mode, never triggered) don't block readiness, since
+// nothing will ever start them.
+func statusExitCode(statuses []resourceStatusItem) int {
+	code := statusExitReady
+	for _, item := range statuses {
+		switch item.Status.State {
+		case model.ResourceStateDegraded:
+			return statusExitError
+		case model.ResourceStateReady, model.ResourceStateDisabled:
+		default:
+			code = statusExitInProgress
+		}
+	}
+	return code
+}
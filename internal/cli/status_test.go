@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/windmilleng/tilt/internal/objects"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestStatusExitCode(t *testing.T) {
+	assert.Equal(t, statusExitReady, statusExitCode(nil))
+	assert.Equal(t, statusExitReady, statusExitCode([]resourceStatusItem{
+		statusItemForTest("fe", model.ResourceStateReady),
+		statusItemForTest("manual", model.ResourceStateDisabled),
+	}))
+	assert.Equal(t, statusExitInProgress, statusExitCode([]resourceStatusItem{
+		statusItemForTest("fe", model.ResourceStateReady),
+		statusItemForTest("be", model.ResourceStateDeploying),
+	}))
+
+	// Errors win over in-progress.
+	assert.Equal(t, statusExitError, statusExitCode([]resourceStatusItem{
+		statusItemForTest("fe", model.ResourceStateBuilding),
+		statusItemForTest("be", model.ResourceStateDegraded),
+	}))
+}
+
+func TestStatusTable(t *testing.T) {
+	out := &bytes.Buffer{}
+	item := statusItemForTest("fe", model.ResourceStateDegraded)
+	item.Status.StateReason = "build failed"
+	printResourceStatuses(out, []resourceStatusItem{item})
+
+	s := out.String()
+	assert.Contains(t, s, "NAME")
+	assert.Contains(t, s, "fe")
+	assert.Contains(t, s, "degraded")
+	assert.Contains(t, s, "build failed")
+}
+
+func statusItemForTest(name string, state model.ResourceState) resourceStatusItem {
+	return resourceStatusItem{
+		Metadata: objects.ObjectMeta{Name: name},
+		Status:   objects.ResourceStatus{State: state},
+	}
+}
@@ -2,11 +2,14 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
 
 	"github.com/windmilleng/tilt/pkg/logger"
 
@@ -21,6 +24,7 @@ const TiltfileErrExitCode = 5
 
 type tiltfileResultCmd struct {
 	fileName string
+	output   string
 }
 
 var _ tiltCmd = &tiltfileResultCmd{}
@@ -42,10 +46,13 @@ func newTiltfileResultCmd() *tiltfileResultCmd {
 func (c *tiltfileResultCmd) register() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "tiltfile-result",
-		Short: "Exec the Tiltfile and print results as JSON (note: the API is unstable and may change)",
-		Long: `Exec the Tiltfile and print results as JSON (note: the API is unstable and may change).
+		Short: "Exec the Tiltfile and print the assembled manifests as JSON or YAML (note: the API is unstable and may change)",
+		Long: `Exec the Tiltfile headlessly and print the assembled manifest set --
+resources, images, deps, and rendered YAML -- as JSON or YAML, so other
+tooling can consume Tilt's model without running the engine (note: the
+API is unstable and may change).
 
-Exit code 0: successful Tiltfile evaluation (JSON printed to stdout)
+Exit code 0: successful Tiltfile evaluation (results printed to stdout)
 Exit code 1: some failure in setup, printing results, etc. (any logs printed to stderr)
 Exit code 5: error when evaluating the Tiltfile, such as syntax error, illegal Tiltfile operation, etc. (any logs printed to stderr)
 
@@ -53,6 +60,7 @@ Run with -v | --verbose to print Tiltfile execution logs on stderr, regardless o
 	}
 
 	addTiltfileFlag(cmd, &c.fileName)
+	cmd.Flags().StringVarP(&c.output, "output", "o", "json", "Output format. One of: json|yaml")
 
 	return cmd
 }
@@ -89,14 +97,107 @@ func (c *tiltfileResultCmd) run(ctx context.Context, args []string) error {
 		os.Exit(TiltfileErrExitCode)
 	}
 
-	err = encodeJSON(tlr)
+	err = printTiltfileResult(os.Stdout, tlr, c.output)
 	if err != nil {
 		maybePrintDeferredLogsToStderr(ctx, showTiltfileLogs)
-		return errors.Wrap(err, "encoding JSON")
+		return errors.Wrap(err, "printing result")
 	}
 	return nil
 }
 
+// The shape we print for external tooling. model.Manifest doesn't
+// serialize usefully (the deploy target is unexported), so we flatten
+// the parts a consumer would care about.
+type tiltfileResult struct {
+	Manifests   []manifestResult `json:"manifests"`
+	ConfigFiles []string         `json:"configFiles,omitempty"`
+}
+
+type manifestResult struct {
+	Name         string        `json:"name"`
+	TriggerMode  string        `json:"triggerMode"`
+	ResourceDeps []string      `json:"resourceDeps,omitempty"`
+	Images       []imageResult `json:"images,omitempty"`
+
+	// Rendered k8s YAML, for k8s resources.
+	K8sYAML string `json:"k8sYaml,omitempty"`
+
+	// Compose config paths, for docker-compose resources.
+	DockerComposeConfigPaths []string `json:"dockerComposeConfigPaths,omitempty"`
+}
+
+type imageResult struct {
+	Ref          string   `json:"ref"`
+	ContextPaths []string `json:"contextPaths,omitempty"`
+}
+
+func resultFromLoad(tlr tiltfile.TiltfileLoadResult) tiltfileResult {
+	result := tiltfileResult{
+		Manifests:   []manifestResult{},
+		ConfigFiles: tlr.ConfigFiles,
+	}
+	for _, m := range tlr.Manifests {
+		mr := manifestResult{
+			Name:        m.Name.String(),
+			TriggerMode: triggerModeString(m.TriggerMode),
+		}
+		for _, dep := range m.ResourceDependencies {
+			mr.ResourceDeps = append(mr.ResourceDeps, dep.String())
+		}
+		for _, it := range m.ImageTargets {
+			mr.Images = append(mr.Images, imageResult{
+				Ref:          it.Refs.ConfigurationRef.String(),
+				ContextPaths: it.LocalPaths(),
+			})
+		}
+		if m.IsK8s() {
+			mr.K8sYAML = m.K8sTarget().YAML
+		}
+		if m.IsDC() {
+			mr.DockerComposeConfigPaths = m.DockerComposeTarget().ConfigPaths
+		}
+		result.Manifests = append(result.Manifests, mr)
+	}
+	return result
+}
+
+func triggerModeString(tm model.TriggerMode) string {
+	if tm.AutoOnChange() {
+		return "auto"
+	}
+	return "manual"
+}
+
+func printTiltfileResult(w io.Writer, tlr tiltfile.TiltfileLoadResult, format string) error {
+	result := resultFromLoad(tlr)
+
+	switch format {
+	case "", "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case "yaml":
+		// Round-trip through JSON so the YAML field names match the
+		// JSON output.
+		jsonData, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		var generic interface{}
+		if err := yaml.Unmarshal(jsonData, &generic); err != nil {
+			return err
+		}
+		yamlData, err := yaml.Marshal(generic)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s", yamlData)
+		return err
+	default:
+		return fmt.Errorf("unknown output format %q; must be one of: json|yaml", format)
+	}
+}
+
 func maybePrintDeferredLogsToStderr(ctx context.Context, showTiltfileLogs bool) {
 	if showTiltfileLogs {
 		// We've already printed the logs elsewhere, do nothing
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/internal/container"
+	"github.com/windmilleng/tilt/internal/tiltfile"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestTiltfileResultOutput(t *testing.T) {
+	m := model.Manifest{
+		Name:                 "fe",
+		ResourceDependencies: []model.ManifestName{"db"},
+		ImageTargets:         []model.ImageTarget{model.MustNewImageTarget(container.MustParseSelector("gcr.io/fe"))},
+	}.WithDeployTarget(model.K8sTarget{YAML: "yamlll"})
+
+	tlr := tiltfile.TiltfileLoadResult{
+		Manifests:   []model.Manifest{m},
+		ConfigFiles: []string{"Tiltfile"},
+	}
+
+	out := &bytes.Buffer{}
+	require.NoError(t, printTiltfileResult(out, tlr, "json"))
+	s := out.String()
+	assert.Contains(t, s, `"name": "fe"`)
+	assert.Contains(t, s, `"ref": "gcr.io/fe"`)
+	assert.Contains(t, s, `"resourceDeps"`)
+	assert.Contains(t, s, `"k8sYaml": "yamlll"`)
+
+	out.Reset()
+	require.NoError(t, printTiltfileResult(out, tlr, "yaml"))
+	assert.Contains(t, out.String(), "name: fe")
+
+	assert.Error(t, printTiltfileResult(out, tlr, "toml"))
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/windmilleng/tilt/pkg/model"
 )
 
+var triggerFullRebuild bool
+
 func newTriggerCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "trigger [RESOURCE_NAME]",
@@ -22,15 +24,21 @@ Otherwise, this command will force a full rebuild.
 		Run:  triggerUpdate,
 	}
 	cmd.Flags().IntVar(&webPort, "port", DefaultWebPort, "Port for the Tilt HTTP server")
+	cmd.Flags().BoolVar(&triggerFullRebuild, "full-rebuild", false, "Force a full image build, bypassing any live_update")
 	return cmd
 }
 
 func triggerUpdate(cmd *cobra.Command, args []string) {
 	resource := args[0]
 
+	reason := model.BuildReasonFlagTriggerCLI
+	if triggerFullRebuild {
+		reason = reason.With(model.BuildReasonFlagForceFullBuild)
+	}
+
 	// TODO(maia): this should probably be the triggerPayload struct, but seems
 	//   like a lot of code to move over (to avoid import cycles) for one call.
-	payload := []byte(fmt.Sprintf(`{"manifest_names":[%q], "build_reason": %d}`, resource, model.BuildReasonFlagTriggerCLI))
+	payload := []byte(fmt.Sprintf(`{"manifest_names":[%q], "build_reason": %d}`, resource, reason))
 
 	body := apiPostJson(webPort, "trigger", payload)
 	_ = body.Close()
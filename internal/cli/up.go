@@ -1,24 +1,33 @@
 package cli
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log"
 	"net/url"
 	"os"
+	"os/exec"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/fatih/color"
 	"github.com/mattn/go-isatty"
 	"github.com/opentracing/opentracing-go"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/sync/errgroup"
 	"k8s.io/klog"
 
 	"github.com/windmilleng/tilt/internal/analytics"
 	"github.com/windmilleng/tilt/internal/cloud"
+	"github.com/windmilleng/tilt/internal/crash"
 	engineanalytics "github.com/windmilleng/tilt/internal/engine/analytics"
 	"github.com/windmilleng/tilt/internal/engine/buildcontrol"
+	"github.com/windmilleng/tilt/internal/engine/memory"
+	"github.com/windmilleng/tilt/internal/engine/progress"
+	"github.com/windmilleng/tilt/internal/engine/snapshots"
+	"github.com/windmilleng/tilt/internal/history"
 	"github.com/windmilleng/tilt/internal/hud"
 	"github.com/windmilleng/tilt/internal/k8s"
 	"github.com/windmilleng/tilt/internal/store"
@@ -34,12 +43,131 @@ const DefaultWebPort = 10350
 const DefaultWebDevPort = 46764
 
 var updateModeFlag string = string(buildcontrol.UpdateModeAuto)
+var clusterSharesDockerFlag bool = false
+var validateK8sObjectsFlag bool = false
 var webModeFlag model.WebMode = model.DefaultWebMode
 var webPort = 0
 var webHost = DefaultWebHost
 var webDevPort = 0
 var noBrowser bool = false
 var logActionsFlag bool = false
+var outputModeFlag string
+var snapshotDirFlag string
+var snapshotIntervalFlag time.Duration
+var snapshotKeepFlag int
+var maxMemoryFlag string
+var provisionClusterFlag string
+var provisionClusterName string
+var provisionKubeconfigCmdFlag string
+
+func provideProgressMode() progress.Mode {
+	return progress.Mode(outputModeFlag)
+}
+
+func provideSnapshotsConfig() snapshots.Config {
+	return snapshots.Config{
+		Dir:      snapshotDirFlag,
+		Interval: snapshotIntervalFlag,
+		MaxCount: snapshotKeepFlag,
+	}
+}
+
+func provideMemoryLimit() (memory.Limit, error) {
+	if maxMemoryFlag == "" {
+		return 0, nil
+	}
+	n, err := units.RAMInBytes(maxMemoryFlag)
+	if err != nil {
+		return 0, fmt.Errorf("parsing --max-memory %q: %v", maxMemoryFlag, err)
+	}
+	return memory.Limit(n), nil
+}
+
+func addMaxMemoryFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&maxMemoryFlag, "max-memory", "",
+		"Maximum memory Tilt may use (e.g. 500MB) before shedding old logs, build history, and caches. Empty means no limit")
+}
+
+func addClusterProvisionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&provisionClusterFlag, "provision-cluster", "",
+		fmt.Sprintf("If set to '%s', '%s', or '%s', create a cluster with that tool on startup if it doesn't already exist. '%s' creates a virtual cluster inside the current kube context's cluster",
+			k8s.ProvisionToolKIND, k8s.ProvisionToolK3D, k8s.ProvisionToolVcluster, k8s.ProvisionToolVcluster))
+	cmd.Flags().StringVar(&provisionClusterName, "cluster-name", "tilt",
+		"The cluster name to use with --provision-cluster and --delete-cluster")
+	cmd.Flags().StringVar(&provisionKubeconfigCmdFlag, "provision-kubeconfig-cmd", "",
+		"Run this command on startup and point the session at the kubeconfig it prints to stdout. An escape hatch for cluster provisioners Tilt doesn't know about")
+}
+
+// Bring up the local cluster if the user asked us to and it isn't already
+// running. Runs before wiring, so the k8s client connects to the new cluster.
+func provisionCluster(ctx context.Context) error {
+	p, err := k8s.NewClusterProvisioner(provisionClusterFlag)
+	if err != nil {
+		return err
+	}
+
+	exists, err := p.Exists(ctx, provisionClusterName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		logOutput(fmt.Sprintf("Creating %s cluster %q (context %s)…",
+			provisionClusterFlag, provisionClusterName, p.ContextName(provisionClusterName)))
+		err = p.Create(ctx, provisionClusterName)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Tools like vcluster don't switch the default kubeconfig's context;
+	// the provisioned cluster is only reachable through a generated
+	// kubeconfig, which we wire into the session via $KUBECONFIG.
+	kubeconfigPath, err := p.Kubeconfig(ctx, provisionClusterName)
+	if err != nil {
+		return err
+	}
+	if kubeconfigPath == "" {
+		return nil
+	}
+	return useKubeconfig(kubeconfigPath)
+}
+
+// Runs the user-supplied provisioner command and points the session at the
+// kubeconfig it prints, so provisioners Tilt doesn't know about (vcluster
+// wrappers, company tooling) can still hand `tilt up` an isolated cluster.
+func provisionKubeconfig(ctx context.Context) error {
+	c := model.ToHostCmd(provisionKubeconfigCmdFlag)
+	cmd := exec.CommandContext(ctx, c.Argv[0], c.Argv[1:]...)
+	out := &bytes.Buffer{}
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "--provision-kubeconfig-cmd %q", provisionKubeconfigCmdFlag)
+	}
+
+	kubeconfigPath, err := k8s.WriteTempKubeconfig(out.Bytes())
+	if err != nil {
+		return err
+	}
+	return useKubeconfig(kubeconfigPath)
+}
+
+// Makes the rest of the session (client wiring, kubectl subprocesses) use
+// the given kubeconfig. Runs before wiring, so the k8s client loads it.
+func useKubeconfig(path string) error {
+	logOutput(fmt.Sprintf("Using generated kubeconfig %s", path))
+	return os.Setenv("KUBECONFIG", path)
+}
+
+func addSnapshotFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&snapshotDirFlag, "snapshot-dir", "",
+		"If set, Tilt will automatically save snapshots of its state into this directory, every --snapshot-interval and on every build failure")
+	cmd.Flags().DurationVar(&snapshotIntervalFlag, "snapshot-interval", 10*time.Minute,
+		"How often to save an automatic snapshot. Requires --snapshot-dir")
+	cmd.Flags().IntVar(&snapshotKeepFlag, "snapshot-keep", 20,
+		"How many automatic snapshots to keep before deleting the oldest. Requires --snapshot-dir")
+}
 
 type upCmd struct {
 	watch                bool
@@ -82,6 +210,10 @@ local resources--i.e. those using serve_cmd--are terminated when you exit Tilt.
 	cmd.Flags().Var(&webModeFlag, "web-mode", "Values: local, prod. Controls whether to use prod assets or a local dev server")
 	cmd.Flags().StringVar(&updateModeFlag, "update-mode", string(buildcontrol.UpdateModeAuto),
 		fmt.Sprintf("Control the strategy Tilt uses for updating instances. Possible values: %v", buildcontrol.AllUpdateModes))
+	cmd.Flags().BoolVar(&clusterSharesDockerFlag, "cluster-shares-docker", false,
+		"If true, Tilt assumes the cluster runs against the local docker daemon and skips pushing images. Useful for docker-desktop or microk8s contexts with custom names that Tilt can't auto-detect")
+	cmd.Flags().BoolVar(&validateK8sObjectsFlag, "validate-k8s-objects", false,
+		"If true, Tilt validates Kubernetes objects with a server-side dry-run before applying them, so that admission-webhook and schema rejections fail the build before anything changes in the cluster")
 	cmd.Flags().StringVar(&c.traceTags, "traceTags", "", "tags to add to spans for easy querying, of the form: key1=val1,key2=val2")
 	cmd.Flags().BoolVar(&c.hud, "hud", true, "If true, tilt will open in HUD mode.")
 	cmd.Flags().BoolVar(&logActionsFlag, "logactions", false, "log all actions and state changes")
@@ -90,6 +222,10 @@ local resources--i.e. those using serve_cmd--are terminated when you exit Tilt.
 	cmd.Flags().Lookup("logactions").Hidden = true
 	cmd.Flags().BoolVar(&noBrowser, "no-browser", false, "If true, web UI will not open on startup.")
 	cmd.Flags().StringVar(&c.outputSnapshotOnExit, "output-snapshot-on-exit", "", "If specified, Tilt will dump a snapshot of its state to the specified path when it exits")
+	cmd.Flags().StringVar(&outputModeFlag, "output", "", "If 'json', Tilt will emit newline-delimited JSON progress events to stdout instead of the HUD")
+	addSnapshotFlags(cmd)
+	addMaxMemoryFlag(cmd)
+	addClusterProvisionFlags(cmd)
 
 	// this is to test the new behavior before enabling it in Tilt 1.0
 	// https://app.clubhouse.io/windmill/epic/5549/make-tui-hard-to-find-in-tilt-1-0
@@ -131,6 +267,20 @@ func (c *upCmd) run(ctx context.Context, args []string) error {
 		span.SetTag(k, v)
 	}
 
+	if provisionClusterFlag != "" {
+		err := provisionCluster(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if provisionKubeconfigCmdFlag != "" {
+		err := provisionKubeconfig(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	deferred := logger.NewDeferredLogger(ctx)
 	ctx = redirectLogs(ctx, deferred)
 
@@ -140,7 +290,8 @@ func (c *upCmd) run(ctx context.Context, args []string) error {
 		log.Printf("Tilt analytics disabled: %s", reason)
 	}
 
-	hudEnabled := c.isHudEnabledByConfig() && isatty.IsTerminal(os.Stdout.Fd())
+	hudEnabled := c.isHudEnabledByConfig() && isatty.IsTerminal(os.Stdout.Fd()) &&
+		provideProgressMode() != progress.ModeJSON
 	cmdUpDeps, err := wireCmdUp(ctx, hud.HudEnabled(hudEnabled), a, cmdUpTags)
 	if err != nil {
 		deferred.SetOutput(deferred.Original())
@@ -188,10 +339,26 @@ func (c *upCmd) run(ctx context.Context, args []string) error {
 	})
 
 	err = g.Wait()
-	if err != context.Canceled {
+	if err == context.Canceled {
+		err = nil
+	}
+	if err != nil {
+		crash.Report(err, cmdUpDeps.Store, os.Stderr)
 		return err
-	} else {
-		return nil
+	}
+
+	printSessionSummary(cmdUpDeps.History, cmdUpDeps.Store)
+	return nil
+}
+
+func printSessionSummary(hs *history.Store, st *store.Store) {
+	state := st.RLockState()
+	startTime := state.TiltStartTime
+	st.RUnlockState()
+
+	err := hs.WriteSessionSummary(os.Stdout, startTime)
+	if err != nil {
+		log.Printf("Error writing session summary: %v", err)
 	}
 }
 
@@ -211,6 +378,10 @@ func provideUpdateModeFlag() buildcontrol.UpdateModeFlag {
 	return buildcontrol.UpdateModeFlag(updateModeFlag)
 }
 
+func provideClusterSharesDockerFlag() buildcontrol.ClusterSharesDockerFlag {
+	return buildcontrol.ClusterSharesDockerFlag(clusterSharesDockerFlag)
+}
+
 func provideLogActions() store.LogActionsFlag {
 	return store.LogActionsFlag(logActionsFlag)
 }
@@ -219,6 +390,10 @@ func provideKubectlLogLevel() k8s.KubectlLogLevel {
 	return k8s.KubectlLogLevel(klogLevel)
 }
 
+func provideServerSideValidation() k8s.ServerSideValidation {
+	return k8s.ServerSideValidation(validateK8sObjectsFlag)
+}
+
 func provideWebMode(b model.TiltBuild) (model.WebMode, error) {
 	switch webModeFlag {
 	case model.LocalWebMode, model.ProdWebMode, model.PrecompiledWebMode:
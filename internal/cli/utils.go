@@ -15,6 +15,24 @@ func apiURL(webPort int, path string) string {
 	return fmt.Sprintf("http://localhost:%d/api/%s", webPort, path)
 }
 
+func debugURL(webPort int, path string) string {
+	path = strings.TrimLeft(path, "/")
+	return fmt.Sprintf("http://localhost:%d/debug/%s", webPort, path)
+}
+
+func debugGet(webPort int, path string) (body io.ReadCloser) {
+	url := debugURL(webPort, path)
+	res, err := http.Get(url)
+	if err != nil {
+		cmdFail(fmt.Errorf("Could not connect to Tilt at %s: %v", url, err))
+	}
+
+	if res.StatusCode != http.StatusOK {
+		failWithNonOKResponse(url, res)
+	}
+	return res.Body
+}
+
 func apiGet(webPort int, path string) (body io.ReadCloser) {
 	url := apiURL(webPort, path)
 	res, err := http.Get(url)
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/windmilleng/tilt/internal/objects"
+)
+
+const waitPollInterval = time.Second
+
+func newWaitCmd() *cobra.Command {
+	var forCondition string
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "wait resource/NAME",
+		Short: "Wait for a resource to reach a condition",
+		Long: `Blocks until the given resource reaches a condition, the way
+kubectl wait blocks on k8s objects. Useful for scripting against a
+running Tilt, e.g. waiting for a server to come up before seeding data:
+
+  tilt wait --for=condition=Ready resource/frontend --timeout=2m
+
+Supported conditions: Ready (the resource's runtime has been ready at
+least once), BuildSucceeded (the last build completed without error).
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			condition, err := parseWaitCondition(forCondition)
+			if err != nil {
+				return err
+			}
+			name, err := parseWaitTarget(args[0])
+			if err != nil {
+				return err
+			}
+			return waitOnResource(webPort, name, condition, timeout)
+		},
+	}
+
+	cmd.Flags().IntVar(&webPort, "port", DefaultWebPort, "Port for the Tilt HTTP server")
+	cmd.Flags().StringVar(&forCondition, "for", "condition=Ready", "The condition to wait on, as condition=NAME")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "How long to wait before giving up")
+	return cmd
+}
+
+type waitCondition string
+
+const (
+	waitConditionReady          waitCondition = "Ready"
+	waitConditionBuildSucceeded waitCondition = "BuildSucceeded"
+)
+
+func parseWaitCondition(s string) (waitCondition, error) {
+	if !strings.HasPrefix(s, "condition=") {
+		return "", fmt.Errorf("--for must be of the form condition=NAME (got: %q)", s)
+	}
+	switch c := waitCondition(strings.TrimPrefix(s, "condition=")); c {
+	case waitConditionReady, waitConditionBuildSucceeded:
+		return c, nil
+	default:
+		return "", fmt.Errorf("unknown condition %q; supported conditions: %s, %s",
+			c, waitConditionReady, waitConditionBuildSucceeded)
+	}
+}
+
+func parseWaitTarget(s string) (string, error) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 || parts[0] != "resource" || parts[1] == "" {
+		return "", fmt.Errorf("target must be of the form resource/NAME (got: %q)", s)
+	}
+	return parts[1], nil
+}
+
+func conditionMet(condition waitCondition, status objects.ResourceStatus) bool {
+	switch condition {
+	case waitConditionReady:
+		return status.Ready
+	case waitConditionBuildSucceeded:
+		return !status.LastBuildFinishTime.IsZero() && status.LastBuildError == ""
+	}
+	return false
+}
+
+func waitOnResource(webPort int, name string, condition waitCondition, timeout time.Duration) error {
+	url := apiURL(webPort, fmt.Sprintf("objects/resources/%s", name))
+	deadline := time.Now().Add(timeout)
+
+	for {
+		status, err := fetchResourceStatus(url)
+		if err != nil {
+			return err
+		}
+		if status != nil && conditionMet(condition, *status) {
+			fmt.Printf("resource/%s condition met: %s\n", name, condition)
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for condition %s on resource/%s", condition, name)
+		}
+		time.Sleep(waitPollInterval)
+	}
+}
+
+// Returns nil without an error if the resource doesn't exist yet, so we
+// keep waiting for it to appear (e.g. mid-Tiltfile-load).
+func fetchResourceStatus(url string) (*objects.ResourceStatus, error) {
+	res, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("Could not connect to Tilt at %s: %v", url, err)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Request to %s failed with status %q", url, res.Status)
+	}
+
+	var obj struct {
+		Status objects.ResourceStatus `json:"status"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("Error decoding response from %s: %v", url, err)
+	}
+	return &obj.Status, nil
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/windmilleng/tilt/internal/objects"
+)
+
+func TestParseWaitCondition(t *testing.T) {
+	c, err := parseWaitCondition("condition=Ready")
+	assert.NoError(t, err)
+	assert.Equal(t, waitConditionReady, c)
+
+	c, err = parseWaitCondition("condition=BuildSucceeded")
+	assert.NoError(t, err)
+	assert.Equal(t, waitConditionBuildSucceeded, c)
+
+	_, err = parseWaitCondition("Ready")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "condition=NAME")
+	}
+
+	_, err = parseWaitCondition("condition=Frobnicated")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unknown condition")
+	}
+}
+
+func TestParseWaitTarget(t *testing.T) {
+	name, err := parseWaitTarget("resource/fe")
+	assert.NoError(t, err)
+	assert.Equal(t, "fe", name)
+
+	for _, s := range []string{"fe", "resource/", "pod/fe"} {
+		_, err := parseWaitTarget(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestWaitConditionMet(t *testing.T) {
+	assert.False(t, conditionMet(waitConditionReady, objects.ResourceStatus{}))
+	assert.True(t, conditionMet(waitConditionReady, objects.ResourceStatus{Ready: true}))
+
+	assert.False(t, conditionMet(waitConditionBuildSucceeded, objects.ResourceStatus{}))
+	assert.True(t, conditionMet(waitConditionBuildSucceeded, objects.ResourceStatus{
+		LastBuildFinishTime: time.Now(),
+	}))
+	assert.False(t, conditionMet(waitConditionBuildSucceeded, objects.ResourceStatus{
+		LastBuildFinishTime: time.Now(),
+		LastBuildError:      "compile error",
+	}))
+}
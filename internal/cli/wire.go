@@ -28,9 +28,21 @@ import (
 	"github.com/windmilleng/tilt/internal/engine/dockerprune"
 	"github.com/windmilleng/tilt/internal/engine/exit"
 	"github.com/windmilleng/tilt/internal/engine/fswatch"
+	"github.com/windmilleng/tilt/internal/engine/k8sgc"
+	"github.com/windmilleng/tilt/internal/engine/prepull"
+	"github.com/windmilleng/tilt/internal/engine/progress"
+	"github.com/windmilleng/tilt/internal/engine/snapshots"
+	"github.com/windmilleng/tilt/internal/history"
+	"github.com/windmilleng/tilt/internal/engine/drift"
+	"github.com/windmilleng/tilt/internal/engine/linkhealth"
+	"github.com/windmilleng/tilt/internal/engine/podmetrics"
+	"github.com/windmilleng/tilt/internal/engine/notify"
+	"github.com/windmilleng/tilt/internal/objects"
+	"github.com/windmilleng/tilt/internal/session"
 	"github.com/windmilleng/tilt/internal/engine/k8srollout"
 	"github.com/windmilleng/tilt/internal/engine/k8swatch"
 	"github.com/windmilleng/tilt/internal/engine/local"
+	"github.com/windmilleng/tilt/internal/engine/memory"
 	"github.com/windmilleng/tilt/internal/engine/runtimelog"
 	"github.com/windmilleng/tilt/internal/engine/telemetry"
 	"github.com/windmilleng/tilt/internal/feature"
@@ -64,6 +76,7 @@ var BaseWireSet = wire.NewSet(
 	K8sWireSet,
 	tiltfile.WireSet,
 	provideKubectlLogLevel,
+	provideServerSideValidation,
 
 	docker.SwitchWireSet,
 
@@ -73,12 +86,15 @@ var BaseWireSet = wire.NewSet(
 	engine.DeployerWireSet,
 	runtimelog.NewPodLogManager,
 	engine.NewPortForwardController,
+	engine.NewCmdButtonController,
 	engine.NewBuildController,
 	local.ProvideExecer,
 	local.NewController,
 	k8swatch.NewPodWatcher,
 	k8swatch.NewServiceWatcher,
+	k8swatch.NewJobWatcher,
 	k8swatch.NewEventWatchManager,
+	k8swatch.NewKubeconfigWatcher,
 	configs.NewConfigsController,
 	telemetry.NewController,
 	dcwatch.NewEventWatcher,
@@ -89,7 +105,25 @@ var BaseWireSet = wire.NewSet(
 	cloud.WireSet,
 	cloudurl.ProvideAddress,
 	k8srollout.NewPodMonitor,
+	k8sgc.NewController,
+	provideProgressMode,
+	progress.NewWriter,
+	provideSnapshotsConfig,
+	snapshots.NewController,
+	provideMemoryLimit,
+	memory.NewController,
+	history.NewStore,
+	history.NewController,
+	session.NewController,
+	objects.NewRegistry,
+	objects.NewController,
+	notify.ProvideNotifier,
+	notify.NewController,
+	drift.NewController,
+	linkhealth.NewController,
+	podmetrics.NewController,
 	telemetry.NewStartTracker,
+	provideExitTimeouts,
 	exit.NewController,
 
 	provideClock,
@@ -100,6 +134,7 @@ var BaseWireSet = wire.NewSet(
 	wire.Bind(new(store.RStore), new(*store.Store)),
 
 	dockerprune.NewDockerPruner,
+	prepull.NewPrePuller,
 
 	provideTiltInfo,
 	engine.ProvideSubscribers,
@@ -107,6 +142,7 @@ var BaseWireSet = wire.NewSet(
 	engineanalytics.NewAnalyticsUpdater,
 	engineanalytics.ProvideAnalyticsReporter,
 	provideUpdateModeFlag,
+	provideClusterSharesDockerFlag,
 	fswatch.NewWatchManager,
 	fswatch.ProvideFsWatcherMaker,
 	fswatch.ProvideTimerMaker,
@@ -206,6 +242,7 @@ func wireRuntime(ctx context.Context) (container.Runtime, error) {
 	wire.Build(
 		K8sWireSet,
 		provideKubectlLogLevel,
+		provideServerSideValidation,
 		k8s.ProvideMinikubeClient,
 	)
 	return "", nil
@@ -216,6 +253,16 @@ func wireK8sVersion(ctx context.Context) (*version.Info, error) {
 	return nil, nil
 }
 
+func wireK8sClient(ctx context.Context) (k8s.Client, error) {
+	wire.Build(
+		K8sWireSet,
+		provideKubectlLogLevel,
+		provideServerSideValidation,
+		k8s.ProvideMinikubeClient,
+	)
+	return nil, nil
+}
+
 func wireDockerClusterClient(ctx context.Context) (docker.ClusterClient, error) {
 	wire.Build(BaseWireSet)
 	return nil, nil
@@ -31,11 +31,23 @@ import (
 	"github.com/windmilleng/tilt/internal/engine/configs"
 	"github.com/windmilleng/tilt/internal/engine/dcwatch"
 	"github.com/windmilleng/tilt/internal/engine/dockerprune"
+	"github.com/windmilleng/tilt/internal/engine/drift"
+	"github.com/windmilleng/tilt/internal/engine/linkhealth"
+	"github.com/windmilleng/tilt/internal/engine/podmetrics"
 	"github.com/windmilleng/tilt/internal/engine/exit"
 	"github.com/windmilleng/tilt/internal/engine/fswatch"
+	"github.com/windmilleng/tilt/internal/engine/k8sgc"
+	"github.com/windmilleng/tilt/internal/engine/prepull"
+	"github.com/windmilleng/tilt/internal/engine/progress"
+	"github.com/windmilleng/tilt/internal/engine/snapshots"
+	"github.com/windmilleng/tilt/internal/history"
+	"github.com/windmilleng/tilt/internal/engine/notify"
+	"github.com/windmilleng/tilt/internal/objects"
+	"github.com/windmilleng/tilt/internal/session"
 	"github.com/windmilleng/tilt/internal/engine/k8srollout"
 	"github.com/windmilleng/tilt/internal/engine/k8swatch"
 	"github.com/windmilleng/tilt/internal/engine/local"
+	"github.com/windmilleng/tilt/internal/engine/memory"
 	"github.com/windmilleng/tilt/internal/engine/runtimelog"
 	"github.com/windmilleng/tilt/internal/engine/telemetry"
 	"github.com/windmilleng/tilt/internal/feature"
@@ -72,7 +84,8 @@ func wireTiltfileResult(ctx context.Context, analytics2 *analytics.TiltAnalytics
 	int2 := provideKubectlLogLevel()
 	kubectlRunner := k8s.ProvideKubectlRunner(kubeContext, int2)
 	minikubeClient := k8s.ProvideMinikubeClient(kubeContext)
-	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig)
+	serverSideValidation := provideServerSideValidation()
+	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig, serverSideValidation)
 	extension := k8scontext.NewExtension(kubeContext, env)
 	tiltBuild := provideTiltInfo()
 	versionExtension := version.NewExtension(tiltBuild)
@@ -109,7 +122,8 @@ func wireDockerPrune(ctx context.Context, analytics2 *analytics.TiltAnalytics) (
 	int2 := provideKubectlLogLevel()
 	kubectlRunner := k8s.ProvideKubectlRunner(kubeContext, int2)
 	minikubeClient := k8s.ProvideMinikubeClient(kubeContext)
-	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig)
+	serverSideValidation := provideServerSideValidation()
+	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig, serverSideValidation)
 	runtime := k8s.ProvideContainerRuntime(ctx, client)
 	clusterEnv := docker.ProvideClusterEnv(ctx, env, runtime, minikubeClient)
 	localEnv := docker.ProvideLocalEnv(ctx, clusterEnv)
@@ -165,10 +179,14 @@ func wireCmdUp(ctx context.Context, hudEnabled hud.HudEnabled, analytics3 *analy
 	int2 := provideKubectlLogLevel()
 	kubectlRunner := k8s.ProvideKubectlRunner(kubeContext, int2)
 	minikubeClient := k8s.ProvideMinikubeClient(kubeContext)
-	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig)
+	serverSideValidation := provideServerSideValidation()
+	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig, serverSideValidation)
 	ownerFetcher := k8s.ProvideOwnerFetcher(client)
-	podWatcher := k8swatch.NewPodWatcher(client, ownerFetcher)
-	serviceWatcher := k8swatch.NewServiceWatcher(client, ownerFetcher)
+	clientPool := k8s.ProvideClientPool(kubeContext, client, ownerFetcher)
+	podWatcher := k8swatch.NewPodWatcher(clientPool)
+	kubeconfigWatcher := k8swatch.NewKubeconfigWatcher(kubeContext)
+	serviceWatcher := k8swatch.NewServiceWatcher(clientPool)
+	jobWatcher := k8swatch.NewJobWatcher(clientPool)
 	podLogManager := runtimelog.NewPodLogManager(client)
 	portForwardController := engine.NewPortForwardController(client)
 	fsWatcherMaker := fswatch.ProvideFsWatcherMaker()
@@ -183,6 +201,7 @@ func wireCmdUp(ctx context.Context, hudEnabled hud.HudEnabled, analytics3 *analy
 		return CmdUpDeps{}, err
 	}
 	switchCli := docker.ProvideSwitchCli(clusterClient, localClient)
+	cmdButtonController := engine.NewCmdButtonController(client, localClient)
 	dockerContainerUpdater := containerupdate.NewDockerContainerUpdater(switchCli)
 	syncletImageRef, err := sidecar.ProvideSyncletImageRef(ctx)
 	if err != nil {
@@ -191,13 +210,16 @@ func wireCmdUp(ctx context.Context, hudEnabled hud.HudEnabled, analytics3 *analy
 	syncletManager := containerupdate.NewSyncletManager(client, syncletImageRef)
 	syncletUpdater := containerupdate.NewSyncletUpdater(syncletManager)
 	execUpdater := containerupdate.NewExecUpdater(client)
+	copySidecarUpdater := containerupdate.NewCopySidecarUpdater(client)
 	buildcontrolUpdateModeFlag := provideUpdateModeFlag()
-	updateMode, err := buildcontrol.ProvideUpdateMode(buildcontrolUpdateModeFlag, env, runtime)
+	clusterSharesDockerFlag := provideClusterSharesDockerFlag()
+	clusterSharesDocker := buildcontrol.ProvideClusterSharesDocker(ctx, clusterSharesDockerFlag, env, runtime, client, switchCli)
+	updateMode, err := buildcontrol.ProvideUpdateMode(buildcontrolUpdateModeFlag, clusterSharesDocker, runtime)
 	if err != nil {
 		return CmdUpDeps{}, err
 	}
 	clock := build.ProvideClock()
-	liveUpdateBuildAndDeployer := engine.NewLiveUpdateBuildAndDeployer(dockerContainerUpdater, syncletUpdater, execUpdater, updateMode, env, runtime, clock)
+	liveUpdateBuildAndDeployer := engine.NewLiveUpdateBuildAndDeployer(dockerContainerUpdater, syncletUpdater, execUpdater, copySidecarUpdater, updateMode, clusterSharesDocker, runtime, clock)
 	labels := _wireLabelsValue
 	dockerImageBuilder := build.NewDockerImageBuilder(switchCli, labels)
 	imageBuilder := build.DefaultImageBuilder(dockerImageBuilder)
@@ -205,12 +227,12 @@ func wireCmdUp(ctx context.Context, hudEnabled hud.HudEnabled, analytics3 *analy
 	clusterName := k8s.ProvideClusterName(ctx, config)
 	kindLoader := engine.NewKINDLoader(env, clusterName)
 	syncletContainer := sidecar.ProvideSyncletContainer(syncletImageRef)
-	imageBuildAndDeployer := engine.NewImageBuildAndDeployer(imageBuilder, execCustomBuilder, client, env, analytics3, updateMode, clock, runtime, kindLoader, syncletContainer)
+	imageBuildAndDeployer := engine.NewImageBuildAndDeployer(imageBuilder, execCustomBuilder, client, clientPool, env, clusterSharesDocker, analytics3, updateMode, clock, runtime, kindLoader, syncletContainer)
 	dockerComposeClient := dockercompose.NewDockerComposeClient(localEnv)
 	imageAndCacheBuilder := engine.NewImageAndCacheBuilder(imageBuilder, execCustomBuilder, updateMode)
-	dockerComposeBuildAndDeployer := engine.NewDockerComposeBuildAndDeployer(dockerComposeClient, switchCli, imageAndCacheBuilder, clock)
+	dockerComposeBuildAndDeployer := engine.NewDockerComposeBuildAndDeployer(dockerComposeClient, localClient, imageAndCacheBuilder, clock)
 	localTargetBuildAndDeployer := engine.NewLocalTargetBuildAndDeployer(clock)
-	buildOrder := engine.DefaultBuildOrder(liveUpdateBuildAndDeployer, imageBuildAndDeployer, dockerComposeBuildAndDeployer, localTargetBuildAndDeployer, updateMode, env, runtime)
+	buildOrder := engine.DefaultBuildOrder(liveUpdateBuildAndDeployer, imageBuildAndDeployer, dockerComposeBuildAndDeployer, localTargetBuildAndDeployer, updateMode, clusterSharesDocker, runtime)
 	spanCollector := tracer.NewSpanCollector(ctx)
 	traceTracer, err := tracer.InitOpenTelemetry(ctx, spanCollector)
 	if err != nil {
@@ -223,7 +245,7 @@ func wireCmdUp(ctx context.Context, hudEnabled hud.HudEnabled, analytics3 *analy
 	versionExtension := version.NewExtension(tiltBuild)
 	defaults := _wireDefaultsValue
 	tiltfileLoader := tiltfile.ProvideTiltfileLoader(analytics3, client, extension, versionExtension, dockerComposeClient, modelWebHost, defaults, env)
-	configsController := configs.NewConfigsController(tiltfileLoader, switchCli)
+	configsController := configs.NewConfigsController(tiltfileLoader, switchCli, traceTracer)
 	eventWatcher := dcwatch.NewEventWatcher(dockerComposeClient, localClient)
 	dockerComposeLogManager := runtimelog.NewDockerComposeLogManager(dockerComposeClient)
 	profilerManager := engine.NewProfilerManager()
@@ -240,7 +262,13 @@ func wireCmdUp(ctx context.Context, hudEnabled hud.HudEnabled, analytics3 *analy
 	httpClient := cloud.ProvideHttpClient()
 	address := cloudurl.ProvideAddress()
 	snapshotUploader := cloud.NewSnapshotUploader(httpClient, address)
-	headsUpServer, err := server.ProvideHeadsUpServer(ctx, storeStore, assetsServer, analytics3, snapshotUploader)
+	windmillDir, err := dirs.UseWindmillDir()
+	if err != nil {
+		return CmdUpDeps{}, err
+	}
+	historyStore := history.NewStore(windmillDir)
+	objectsRegistry := objects.NewRegistry()
+	headsUpServer, err := server.ProvideHeadsUpServer(ctx, storeStore, assetsServer, analytics3, snapshotUploader, historyStore, objectsRegistry)
 	if err != nil {
 		return CmdUpDeps{}, err
 	}
@@ -258,13 +286,29 @@ func wireCmdUp(ctx context.Context, hudEnabled hud.HudEnabled, analytics3 *analy
 	execer := local.ProvideExecer()
 	localController := local.NewController(execer)
 	podMonitor := k8srollout.NewPodMonitor()
-	exitController := exit.NewController()
-	v2 := engine.ProvideSubscribers(headsUpDisplay, podWatcher, serviceWatcher, podLogManager, portForwardController, watchManager, buildController, configsController, eventWatcher, dockerComposeLogManager, profilerManager, syncletManager, analyticsReporter, headsUpServerController, tiltVersionChecker, analyticsUpdater, eventWatchManager, cloudStatusManager, updateUploader, dockerPruner, controller, localController, podMonitor, exitController)
-	upper := engine.NewUpper(ctx, storeStore, v2)
-	windmillDir, err := dirs.UseWindmillDir()
+	timeouts := provideExitTimeouts()
+	exitController := exit.NewController(timeouts)
+	gcController := k8sgc.NewController(client)
+	mode := provideProgressMode()
+	progressWriter := progress.NewWriter(stdout, mode)
+	snapshotsConfig := provideSnapshotsConfig()
+	snapshotsController := snapshots.NewController(snapshotsConfig)
+	historyController := history.NewController(historyStore)
+	sessionController := session.NewController()
+	objectsController := objects.NewController(objectsRegistry)
+	notifier := notify.ProvideNotifier()
+	notifyController := notify.NewController(httpClient, notifier)
+	driftController := drift.NewController(client, clock)
+	linkhealthController := linkhealth.NewController(httpClient, clock)
+	podmetricsController := podmetrics.NewController(client, clock)
+	memoryLimit, err := provideMemoryLimit()
 	if err != nil {
 		return CmdUpDeps{}, err
 	}
+	memoryController := memory.NewController(memoryLimit, podWatcher, eventWatchManager)
+	prePuller := prepull.NewPrePuller(imageBuilder)
+	v2 := engine.ProvideSubscribers(headsUpDisplay, podWatcher, serviceWatcher, jobWatcher, podLogManager, portForwardController, cmdButtonController, watchManager, buildController, configsController, eventWatcher, dockerComposeLogManager, profilerManager, syncletManager, analyticsReporter, headsUpServerController, tiltVersionChecker, analyticsUpdater, eventWatchManager, cloudStatusManager, updateUploader, dockerPruner, controller, localController, podMonitor, podmetricsController, exitController, gcController, progressWriter, snapshotsController, historyController, sessionController, objectsController, notifyController, driftController, linkhealthController, memoryController, prePuller, kubeconfigWatcher)
+	upper := engine.NewUpper(ctx, storeStore, v2)
 	tokenToken, err := token.GetOrCreateToken(windmillDir)
 	if err != nil {
 		return CmdUpDeps{}, err
@@ -276,6 +320,7 @@ func wireCmdUp(ctx context.Context, hudEnabled hud.HudEnabled, analytics3 *analy
 		Token:        tokenToken,
 		CloudAddress: address,
 		Store:        storeStore,
+		History:      historyStore,
 	}
 	return cmdUpDeps, nil
 }
@@ -321,10 +366,14 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics) (CmdCID
 	int2 := provideKubectlLogLevel()
 	kubectlRunner := k8s.ProvideKubectlRunner(kubeContext, int2)
 	minikubeClient := k8s.ProvideMinikubeClient(kubeContext)
-	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig)
+	serverSideValidation := provideServerSideValidation()
+	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig, serverSideValidation)
 	ownerFetcher := k8s.ProvideOwnerFetcher(client)
-	podWatcher := k8swatch.NewPodWatcher(client, ownerFetcher)
-	serviceWatcher := k8swatch.NewServiceWatcher(client, ownerFetcher)
+	clientPool := k8s.ProvideClientPool(kubeContext, client, ownerFetcher)
+	podWatcher := k8swatch.NewPodWatcher(clientPool)
+	kubeconfigWatcher := k8swatch.NewKubeconfigWatcher(kubeContext)
+	serviceWatcher := k8swatch.NewServiceWatcher(clientPool)
+	jobWatcher := k8swatch.NewJobWatcher(clientPool)
 	podLogManager := runtimelog.NewPodLogManager(client)
 	portForwardController := engine.NewPortForwardController(client)
 	fsWatcherMaker := fswatch.ProvideFsWatcherMaker()
@@ -339,6 +388,7 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics) (CmdCID
 		return CmdCIDeps{}, err
 	}
 	switchCli := docker.ProvideSwitchCli(clusterClient, localClient)
+	cmdButtonController := engine.NewCmdButtonController(client, localClient)
 	dockerContainerUpdater := containerupdate.NewDockerContainerUpdater(switchCli)
 	syncletImageRef, err := sidecar.ProvideSyncletImageRef(ctx)
 	if err != nil {
@@ -347,13 +397,16 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics) (CmdCID
 	syncletManager := containerupdate.NewSyncletManager(client, syncletImageRef)
 	syncletUpdater := containerupdate.NewSyncletUpdater(syncletManager)
 	execUpdater := containerupdate.NewExecUpdater(client)
+	copySidecarUpdater := containerupdate.NewCopySidecarUpdater(client)
 	buildcontrolUpdateModeFlag := provideUpdateModeFlag()
-	updateMode, err := buildcontrol.ProvideUpdateMode(buildcontrolUpdateModeFlag, env, runtime)
+	clusterSharesDockerFlag := provideClusterSharesDockerFlag()
+	clusterSharesDocker := buildcontrol.ProvideClusterSharesDocker(ctx, clusterSharesDockerFlag, env, runtime, client, switchCli)
+	updateMode, err := buildcontrol.ProvideUpdateMode(buildcontrolUpdateModeFlag, clusterSharesDocker, runtime)
 	if err != nil {
 		return CmdCIDeps{}, err
 	}
 	clock := build.ProvideClock()
-	liveUpdateBuildAndDeployer := engine.NewLiveUpdateBuildAndDeployer(dockerContainerUpdater, syncletUpdater, execUpdater, updateMode, env, runtime, clock)
+	liveUpdateBuildAndDeployer := engine.NewLiveUpdateBuildAndDeployer(dockerContainerUpdater, syncletUpdater, execUpdater, copySidecarUpdater, updateMode, clusterSharesDocker, runtime, clock)
 	labels := _wireLabelsValue
 	dockerImageBuilder := build.NewDockerImageBuilder(switchCli, labels)
 	imageBuilder := build.DefaultImageBuilder(dockerImageBuilder)
@@ -361,12 +414,12 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics) (CmdCID
 	clusterName := k8s.ProvideClusterName(ctx, config)
 	kindLoader := engine.NewKINDLoader(env, clusterName)
 	syncletContainer := sidecar.ProvideSyncletContainer(syncletImageRef)
-	imageBuildAndDeployer := engine.NewImageBuildAndDeployer(imageBuilder, execCustomBuilder, client, env, analytics3, updateMode, clock, runtime, kindLoader, syncletContainer)
+	imageBuildAndDeployer := engine.NewImageBuildAndDeployer(imageBuilder, execCustomBuilder, client, clientPool, env, clusterSharesDocker, analytics3, updateMode, clock, runtime, kindLoader, syncletContainer)
 	dockerComposeClient := dockercompose.NewDockerComposeClient(localEnv)
 	imageAndCacheBuilder := engine.NewImageAndCacheBuilder(imageBuilder, execCustomBuilder, updateMode)
-	dockerComposeBuildAndDeployer := engine.NewDockerComposeBuildAndDeployer(dockerComposeClient, switchCli, imageAndCacheBuilder, clock)
+	dockerComposeBuildAndDeployer := engine.NewDockerComposeBuildAndDeployer(dockerComposeClient, localClient, imageAndCacheBuilder, clock)
 	localTargetBuildAndDeployer := engine.NewLocalTargetBuildAndDeployer(clock)
-	buildOrder := engine.DefaultBuildOrder(liveUpdateBuildAndDeployer, imageBuildAndDeployer, dockerComposeBuildAndDeployer, localTargetBuildAndDeployer, updateMode, env, runtime)
+	buildOrder := engine.DefaultBuildOrder(liveUpdateBuildAndDeployer, imageBuildAndDeployer, dockerComposeBuildAndDeployer, localTargetBuildAndDeployer, updateMode, clusterSharesDocker, runtime)
 	spanCollector := tracer.NewSpanCollector(ctx)
 	traceTracer, err := tracer.InitOpenTelemetry(ctx, spanCollector)
 	if err != nil {
@@ -379,7 +432,7 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics) (CmdCID
 	versionExtension := version.NewExtension(tiltBuild)
 	defaults := _wireDefaultsValue
 	tiltfileLoader := tiltfile.ProvideTiltfileLoader(analytics3, client, extension, versionExtension, dockerComposeClient, modelWebHost, defaults, env)
-	configsController := configs.NewConfigsController(tiltfileLoader, switchCli)
+	configsController := configs.NewConfigsController(tiltfileLoader, switchCli, traceTracer)
 	eventWatcher := dcwatch.NewEventWatcher(dockerComposeClient, localClient)
 	dockerComposeLogManager := runtimelog.NewDockerComposeLogManager(dockerComposeClient)
 	profilerManager := engine.NewProfilerManager()
@@ -396,7 +449,13 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics) (CmdCID
 	httpClient := cloud.ProvideHttpClient()
 	address := cloudurl.ProvideAddress()
 	snapshotUploader := cloud.NewSnapshotUploader(httpClient, address)
-	headsUpServer, err := server.ProvideHeadsUpServer(ctx, storeStore, assetsServer, analytics3, snapshotUploader)
+	windmillDir, err := dirs.UseWindmillDir()
+	if err != nil {
+		return CmdCIDeps{}, err
+	}
+	historyStore := history.NewStore(windmillDir)
+	objectsRegistry := objects.NewRegistry()
+	headsUpServer, err := server.ProvideHeadsUpServer(ctx, storeStore, assetsServer, analytics3, snapshotUploader, historyStore, objectsRegistry)
 	if err != nil {
 		return CmdCIDeps{}, err
 	}
@@ -415,13 +474,29 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics) (CmdCID
 	execer := local.ProvideExecer()
 	localController := local.NewController(execer)
 	podMonitor := k8srollout.NewPodMonitor()
-	exitController := exit.NewController()
-	v2 := engine.ProvideSubscribers(headsUpDisplay, podWatcher, serviceWatcher, podLogManager, portForwardController, watchManager, buildController, configsController, eventWatcher, dockerComposeLogManager, profilerManager, syncletManager, analyticsReporter, headsUpServerController, tiltVersionChecker, analyticsUpdater, eventWatchManager, cloudStatusManager, updateUploader, dockerPruner, controller, localController, podMonitor, exitController)
-	upper := engine.NewUpper(ctx, storeStore, v2)
-	windmillDir, err := dirs.UseWindmillDir()
+	timeouts := provideExitTimeouts()
+	exitController := exit.NewController(timeouts)
+	gcController := k8sgc.NewController(client)
+	mode := provideProgressMode()
+	progressWriter := progress.NewWriter(stdout, mode)
+	snapshotsConfig := provideSnapshotsConfig()
+	snapshotsController := snapshots.NewController(snapshotsConfig)
+	historyController := history.NewController(historyStore)
+	sessionController := session.NewController()
+	objectsController := objects.NewController(objectsRegistry)
+	notifier := notify.ProvideNotifier()
+	notifyController := notify.NewController(httpClient, notifier)
+	driftController := drift.NewController(client, clock)
+	linkhealthController := linkhealth.NewController(httpClient, clock)
+	podmetricsController := podmetrics.NewController(client, clock)
+	memoryLimit, err := provideMemoryLimit()
 	if err != nil {
 		return CmdCIDeps{}, err
 	}
+	memoryController := memory.NewController(memoryLimit, podWatcher, eventWatchManager)
+	prePuller := prepull.NewPrePuller(imageBuilder)
+	v2 := engine.ProvideSubscribers(headsUpDisplay, podWatcher, serviceWatcher, jobWatcher, podLogManager, portForwardController, cmdButtonController, watchManager, buildController, configsController, eventWatcher, dockerComposeLogManager, profilerManager, syncletManager, analyticsReporter, headsUpServerController, tiltVersionChecker, analyticsUpdater, eventWatchManager, cloudStatusManager, updateUploader, dockerPruner, controller, localController, podMonitor, podmetricsController, exitController, gcController, progressWriter, snapshotsController, historyController, sessionController, objectsController, notifyController, driftController, linkhealthController, memoryController, prePuller, kubeconfigWatcher)
+	upper := engine.NewUpper(ctx, storeStore, v2)
 	tokenToken, err := token.GetOrCreateToken(windmillDir)
 	if err != nil {
 		return CmdCIDeps{}, err
@@ -432,6 +507,7 @@ func wireCmdCI(ctx context.Context, analytics3 *analytics.TiltAnalytics) (CmdCID
 		Token:        tokenToken,
 		CloudAddress: address,
 		Store:        storeStore,
+		History:      historyStore,
 	}
 	return cmdCIDeps, nil
 }
@@ -507,7 +583,8 @@ func wireRuntime(ctx context.Context) (container.Runtime, error) {
 	int2 := provideKubectlLogLevel()
 	kubectlRunner := k8s.ProvideKubectlRunner(kubeContext, int2)
 	minikubeClient := k8s.ProvideMinikubeClient(kubeContext)
-	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig)
+	serverSideValidation := provideServerSideValidation()
+	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig, serverSideValidation)
 	runtime := k8s.ProvideContainerRuntime(ctx, client)
 	return runtime, nil
 }
@@ -523,6 +600,29 @@ func wireK8sVersion(ctx context.Context) (*version2.Info, error) {
 	return info, nil
 }
 
+func wireK8sClient(ctx context.Context) (k8s.Client, error) {
+	clientConfig := k8s.ProvideClientConfig()
+	config, err := k8s.ProvideKubeConfig(clientConfig)
+	if err != nil {
+		return nil, err
+	}
+	env := k8s.ProvideEnv(ctx, config)
+	restConfigOrError := k8s.ProvideRESTConfig(clientConfig)
+	clientsetOrError := k8s.ProvideClientset(restConfigOrError)
+	portForwardClient := k8s.ProvidePortForwardClient(restConfigOrError, clientsetOrError)
+	namespace := k8s.ProvideConfigNamespace(clientConfig)
+	kubeContext, err := k8s.ProvideKubeContext(config)
+	if err != nil {
+		return nil, err
+	}
+	int2 := provideKubectlLogLevel()
+	kubectlRunner := k8s.ProvideKubectlRunner(kubeContext, int2)
+	minikubeClient := k8s.ProvideMinikubeClient(kubeContext)
+	serverSideValidation := provideServerSideValidation()
+	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig, serverSideValidation)
+	return client, nil
+}
+
 func wireDockerClusterClient(ctx context.Context) (docker.ClusterClient, error) {
 	clientConfig := k8s.ProvideClientConfig()
 	config, err := k8s.ProvideKubeConfig(clientConfig)
@@ -541,7 +641,8 @@ func wireDockerClusterClient(ctx context.Context) (docker.ClusterClient, error)
 	int2 := provideKubectlLogLevel()
 	kubectlRunner := k8s.ProvideKubectlRunner(kubeContext, int2)
 	minikubeClient := k8s.ProvideMinikubeClient(kubeContext)
-	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig)
+	serverSideValidation := provideServerSideValidation()
+	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig, serverSideValidation)
 	runtime := k8s.ProvideContainerRuntime(ctx, client)
 	clusterEnv := docker.ProvideClusterEnv(ctx, env, runtime, minikubeClient)
 	localEnv := docker.ProvideLocalEnv(ctx, clusterEnv)
@@ -571,7 +672,8 @@ func wireDockerLocalClient(ctx context.Context) (docker.LocalClient, error) {
 	int2 := provideKubectlLogLevel()
 	kubectlRunner := k8s.ProvideKubectlRunner(kubeContext, int2)
 	minikubeClient := k8s.ProvideMinikubeClient(kubeContext)
-	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig)
+	serverSideValidation := provideServerSideValidation()
+	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig, serverSideValidation)
 	runtime := k8s.ProvideContainerRuntime(ctx, client)
 	clusterEnv := docker.ProvideClusterEnv(ctx, env, runtime, minikubeClient)
 	localEnv := docker.ProvideLocalEnv(ctx, clusterEnv)
@@ -597,7 +699,8 @@ func wireDownDeps(ctx context.Context, tiltAnalytics *analytics.TiltAnalytics) (
 	int2 := provideKubectlLogLevel()
 	kubectlRunner := k8s.ProvideKubectlRunner(kubeContext, int2)
 	minikubeClient := k8s.ProvideMinikubeClient(kubeContext)
-	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig)
+	serverSideValidation := provideServerSideValidation()
+	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig, serverSideValidation)
 	extension := k8scontext.NewExtension(kubeContext, env)
 	tiltBuild := provideTiltInfo()
 	versionExtension := version.NewExtension(tiltBuild)
@@ -614,10 +717,10 @@ func wireDownDeps(ctx context.Context, tiltAnalytics *analytics.TiltAnalytics) (
 
 // wire.go:
 
-var K8sWireSet = wire.NewSet(k8s.ProvideEnv, k8s.ProvideClusterName, k8s.ProvideKubeContext, k8s.ProvideKubeConfig, k8s.ProvideClientConfig, k8s.ProvideClientset, k8s.ProvideRESTConfig, k8s.ProvidePortForwardClient, k8s.ProvideConfigNamespace, k8s.ProvideKubectlRunner, k8s.ProvideContainerRuntime, k8s.ProvideServerVersion, k8s.ProvideK8sClient, k8s.ProvideOwnerFetcher)
+var K8sWireSet = wire.NewSet(k8s.ProvideEnv, k8s.ProvideClusterName, k8s.ProvideKubeContext, k8s.ProvideKubeConfig, k8s.ProvideClientConfig, k8s.ProvideClientset, k8s.ProvideRESTConfig, k8s.ProvidePortForwardClient, k8s.ProvideConfigNamespace, k8s.ProvideKubectlRunner, provideServerSideValidation, k8s.ProvideContainerRuntime, k8s.ProvideServerVersion, k8s.ProvideK8sClient, k8s.ProvideOwnerFetcher)
 
 var BaseWireSet = wire.NewSet(
-	K8sWireSet, tiltfile.WireSet, provideKubectlLogLevel, docker.SwitchWireSet, dockercompose.NewDockerComposeClient, clockwork.NewRealClock, engine.DeployerWireSet, runtimelog.NewPodLogManager, engine.NewPortForwardController, engine.NewBuildController, local.ProvideExecer, local.NewController, k8swatch.NewPodWatcher, k8swatch.NewServiceWatcher, k8swatch.NewEventWatchManager, configs.NewConfigsController, telemetry.NewController, dcwatch.NewEventWatcher, runtimelog.NewDockerComposeLogManager, engine.NewProfilerManager, engine.NewGithubClientFactory, engine.NewTiltVersionChecker, cloud.WireSet, cloudurl.ProvideAddress, k8srollout.NewPodMonitor, telemetry.NewStartTracker, exit.NewController, provideClock, hud.WireSet, provideLogActions, store.NewStore, wire.Bind(new(store.RStore), new(*store.Store)), dockerprune.NewDockerPruner, provideTiltInfo, engine.ProvideSubscribers, engine.NewUpper, analytics2.NewAnalyticsUpdater, analytics2.ProvideAnalyticsReporter, provideUpdateModeFlag, fswatch.NewWatchManager, fswatch.ProvideFsWatcherMaker, fswatch.ProvideTimerMaker, provideWebVersion,
+	K8sWireSet, tiltfile.WireSet, provideKubectlLogLevel, docker.SwitchWireSet, dockercompose.NewDockerComposeClient, clockwork.NewRealClock, engine.DeployerWireSet, runtimelog.NewPodLogManager, engine.NewPortForwardController, engine.NewCmdButtonController, engine.NewBuildController, local.ProvideExecer, local.NewController, k8swatch.NewPodWatcher, k8swatch.NewServiceWatcher, k8swatch.NewEventWatchManager, k8swatch.NewKubeconfigWatcher, configs.NewConfigsController, telemetry.NewController, dcwatch.NewEventWatcher, runtimelog.NewDockerComposeLogManager, engine.NewProfilerManager, engine.NewGithubClientFactory, engine.NewTiltVersionChecker, cloud.WireSet, cloudurl.ProvideAddress, k8srollout.NewPodMonitor, k8sgc.NewController, provideProgressMode, progress.NewWriter, provideSnapshotsConfig, snapshots.NewController, provideMemoryLimit, memory.NewController, history.NewStore, history.NewController, session.NewController, objects.NewRegistry, objects.NewController, notify.ProvideNotifier, notify.NewController, drift.NewController, linkhealth.NewController, podmetrics.NewController, telemetry.NewStartTracker, provideExitTimeouts, exit.NewController, provideClock, hud.WireSet, provideLogActions, store.NewStore, wire.Bind(new(store.RStore), new(*store.Store)), dockerprune.NewDockerPruner, prepull.NewPrePuller, provideTiltInfo, engine.ProvideSubscribers, engine.NewUpper, analytics2.NewAnalyticsUpdater, analytics2.ProvideAnalyticsReporter, provideUpdateModeFlag, fswatch.NewWatchManager, fswatch.ProvideFsWatcherMaker, fswatch.ProvideTimerMaker, provideWebVersion,
 	provideWebMode,
 	provideWebURL,
 	provideWebPort,
@@ -632,6 +735,7 @@ type CmdUpDeps struct {
 	Token        token.Token
 	CloudAddress cloudurl.Address
 	Store        *store.Store
+	History      *history.Store
 }
 
 type CmdCIDeps struct {
@@ -640,6 +744,7 @@ type CmdCIDeps struct {
 	Token        token.Token
 	CloudAddress cloudurl.Address
 	Store        *store.Store
+	History      *history.Store
 }
 
 type DownDeps struct {
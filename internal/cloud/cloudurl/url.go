@@ -8,7 +8,8 @@ import (
 
 // this is in its own package to avoid circular dependencies
 
-// an address like cloud.tilt.dev or localhost:10450
+// an address like cloud.tilt.dev, localhost:10450, or a full URL
+// like https://snapshots.internal.example.com for self-hosted backends
 type Address string
 
 const addressEnvName = "TILT_CLOUD_ADDRESS"
@@ -23,6 +24,15 @@ func ProvideAddress() Address {
 }
 
 func URL(cloudAddress string) *url.URL {
+	// Addresses with an explicit scheme are taken as-is, so a backend
+	// can be served over http on a non-localhost host.
+	if strings.Contains(cloudAddress, "://") {
+		u, err := url.Parse(cloudAddress)
+		if err == nil && u.Host != "" {
+			return u
+		}
+	}
+
 	var u url.URL
 	u.Host = cloudAddress
 	u.Scheme = "https"
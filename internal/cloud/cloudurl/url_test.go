@@ -0,0 +1,18 @@
+package cloudurl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURL(t *testing.T) {
+	assert.Equal(t, "https://cloud.tilt.dev", URL("cloud.tilt.dev").String())
+	assert.Equal(t, "http://localhost:10450", URL("localhost:10450").String())
+
+	// Self-hosted backends can specify the scheme explicitly.
+	assert.Equal(t, "https://snapshots.internal.example.com",
+		URL("https://snapshots.internal.example.com").String())
+	assert.Equal(t, "http://snapshots.internal.example.com:8080",
+		URL("http://snapshots.internal.example.com:8080").String())
+}
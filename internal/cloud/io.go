@@ -18,9 +18,10 @@ func WriteSnapshot(ctx context.Context, store *store.Store, path string) {
 	}
 
 	state := store.RLockState()
-	defer store.RUnlockState()
+	snapshot := state.CopyForView()
+	store.RUnlockState()
 
-	err = WriteSnapshotTo(ctx, state, f)
+	err = WriteSnapshotTo(ctx, snapshot, f)
 	if err != nil {
 		logger.Get(ctx).Errorf("Writing snapshot to file: %v", err)
 	}
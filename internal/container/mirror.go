@@ -0,0 +1,38 @@
+package container
+
+import (
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+)
+
+// Rewrites a Docker Hub image ref to pull through the given registry
+// mirror host, preserving any tag or digest. Refs that already point at
+// another registry are returned unchanged.
+func ReplaceWithMirror(ref reference.Named, mirrorHost string) (reference.Named, bool, error) {
+	if reference.Domain(ref) != "docker.io" {
+		return ref, false, nil
+	}
+
+	newRef, err := ParseNamed(mirrorHost + "/" + reference.Path(ref))
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "mirroring %s via %s", ref.String(), mirrorHost)
+	}
+
+	if tagged, ok := ref.(reference.NamedTagged); ok {
+		nt, err := reference.WithTag(newRef, tagged.Tag())
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "mirroring %s via %s", ref.String(), mirrorHost)
+		}
+		return nt, true, nil
+	}
+
+	if digested, ok := ref.(reference.Canonical); ok {
+		nd, err := reference.WithDigest(newRef, digested.Digest())
+		if err != nil {
+			return nil, false, errors.Wrapf(err, "mirroring %s via %s", ref.String(), mirrorHost)
+		}
+		return nd, true, nil
+	}
+
+	return newRef, true, nil
+}
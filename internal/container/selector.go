@@ -23,7 +23,8 @@ func NameSelector(ref reference.Named) RefSelector {
 func NewRefSelector(ref reference.Named) RefSelector {
 	matchType := matchName
 	_, hasTag := ref.(reference.NamedTagged)
-	if hasTag {
+	_, hasDigest := ref.(reference.Digested)
+	if hasTag || hasDigest {
 		matchType = matchExact
 	}
 	return RefSelector{
@@ -68,9 +69,31 @@ func (s RefSelector) Matches(toMatch reference.Named) bool {
 		return false
 	}
 
+	if toMatch.Name() != s.ref.Name() {
+		return false
+	}
+
 	if s.matchType == matchName {
-		return toMatch.Name() == s.ref.Name()
+		return true
+	}
+
+	// Exact matching compares the tag and digest components individually,
+	// so that a ref pinned to both a tag and a digest (the form the container
+	// runtime reports) still matches a tag-only selector.
+	if tagged, ok := s.ref.(reference.Tagged); ok {
+		toTagged, toHasTag := toMatch.(reference.Tagged)
+		// If both refs have a tag, matching tags are enough. We deliberately
+		// don't compare digests here: for multi-arch images, the manifest
+		// list digest and the platform manifest digest refer to the same
+		// logical image but never compare equal.
+		return toHasTag && toTagged.Tag() == tagged.Tag()
+	}
+
+	if digested, ok := s.ref.(reference.Digested); ok {
+		toDigested, toHasDigest := toMatch.(reference.Digested)
+		return toHasDigest && toDigested.Digest() == digested.Digest()
 	}
+
 	return toMatch.String() == s.ref.String()
 }
 
@@ -0,0 +1,38 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const exampleDigest = "sha256:2baf1f40105d9501fe319a8ec463fdf4325a2a5df445adf3f572f626253678c9"
+const otherDigest = "sha256:11cd0b38bc3ceb958ffb2f9bd70be3fb317ce7d255c8a4c3f4af30e298aa1aab"
+
+func TestNameSelectorMatchesDigestPinnedRef(t *testing.T) {
+	s := MustParseSelector("gcr.io/foo/app")
+	assert.True(t, s.Matches(MustParseNamed("gcr.io/foo/app@"+exampleDigest)))
+	assert.False(t, s.Matches(MustParseNamed("gcr.io/foo/sidecar@"+exampleDigest)))
+}
+
+func TestTaggedSelectorIgnoresExtraDigest(t *testing.T) {
+	// The container runtime often reports the ref it ran as name:tag@digest.
+	s := MustParseTaggedSelector("gcr.io/foo/app:v1")
+	assert.True(t, s.Matches(MustParseNamed("gcr.io/foo/app:v1@"+exampleDigest)))
+	assert.False(t, s.Matches(MustParseNamed("gcr.io/foo/app:v2@"+exampleDigest)))
+	assert.False(t, s.Matches(MustParseNamed("gcr.io/foo/app@"+exampleDigest)))
+}
+
+func TestDigestSelectorMatchesExactDigest(t *testing.T) {
+	s := NewRefSelector(MustParseNamed("gcr.io/foo/app@" + exampleDigest))
+	assert.True(t, s.Matches(MustParseNamed("gcr.io/foo/app@"+exampleDigest)))
+	assert.False(t, s.Matches(MustParseNamed("gcr.io/foo/app@"+otherDigest)))
+	assert.False(t, s.Matches(MustParseNamed("gcr.io/foo/app:v1")))
+}
+
+func TestDigestSelectorIsNotANameMatch(t *testing.T) {
+	// A digest-pinned sidecar from third-party YAML shouldn't be treated
+	// as matching every other ref with the same name.
+	s := NewRefSelector(MustParseNamed("gcr.io/foo/app@" + exampleDigest))
+	assert.False(t, s.Matches(MustParseNamed("gcr.io/foo/app")))
+}
@@ -0,0 +1,85 @@
+package containerupdate
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/windmilleng/tilt/internal/container"
+	"github.com/windmilleng/tilt/internal/k8s"
+)
+
+// The copy helper is a tiny sidecar with tar and a shell, injected next to
+// app containers whose images (distroless, scratch) carry neither. It mounts
+// the same volumes as the app container, so files the helper untars into a
+// shared volume show up in the app container too.
+const CopyHelperContainerName container.Name = "tilt-copy-helper"
+
+const DefaultCopyHelperImage = "busybox:1.31"
+
+const CopyHelperImageEnvVar = "TILT_COPY_HELPER_IMAGE"
+
+func copyHelperImage() string {
+	v := os.Getenv(CopyHelperImageEnvVar)
+	if v != "" {
+		return v
+	}
+	return DefaultCopyHelperImage
+}
+
+func copyHelperContainer(appContainer v1.Container) v1.Container {
+	return v1.Container{
+		Name:            string(CopyHelperContainerName),
+		Image:           copyHelperImage(),
+		ImagePullPolicy: v1.PullIfNotPresent,
+
+		// Sleep until the pod shuts down, exiting promptly on TERM.
+		Command: []string{"/bin/sh", "-c", "trap 'exit 0' TERM; while true; do sleep 1; done"},
+
+		VolumeMounts: appContainer.VolumeMounts,
+	}
+}
+
+// Inject the copy helper into any pod that runs the selected image, mirroring
+// the volume mounts of the matched container.
+func InjectCopyHelper(entity k8s.K8sEntity, selector container.RefSelector) (k8s.K8sEntity, bool, error) {
+	entity = entity.DeepCopy()
+
+	pods, err := k8s.ExtractPods(&entity)
+	if err != nil {
+		return k8s.K8sEntity{}, false, err
+	}
+
+	injected := false
+	for _, pod := range pods {
+		if PodSpecContainsCopyHelper(*pod) {
+			injected = true
+			continue
+		}
+
+		for _, c := range pod.Containers {
+			cRef, err := container.ParseNamed(c.Image)
+			if err != nil {
+				return k8s.K8sEntity{}, false, errors.Wrap(err, "InjectCopyHelper")
+			}
+			if !selector.Matches(cRef) {
+				continue
+			}
+
+			pod.Containers = append(pod.Containers, copyHelperContainer(c))
+			injected = true
+			break
+		}
+	}
+	return entity, injected, nil
+}
+
+func PodSpecContainsCopyHelper(spec v1.PodSpec) bool {
+	for _, c := range spec.Containers {
+		if c.Name == string(CopyHelperContainerName) {
+			return true
+		}
+	}
+	return false
+}
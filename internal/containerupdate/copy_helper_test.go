@@ -0,0 +1,55 @@
+package containerupdate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/windmilleng/tilt/internal/container"
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/k8s/testyaml"
+)
+
+func TestInjectCopyHelper(t *testing.T) {
+	entities, err := k8s.ParseYAMLFromString(testyaml.SanchoYAML)
+	require.NoError(t, err)
+	require.Len(t, entities, 1)
+
+	// Give the app container a volume mount; the helper should mirror it.
+	entity := entities[0]
+	deployment := entity.Obj.(*appsv1.Deployment)
+	mounts := []v1.VolumeMount{{Name: "sync-vol", MountPath: "/app"}}
+	deployment.Spec.Template.Spec.Containers[0].VolumeMounts = mounts
+
+	selector := container.MustParseSelector("gcr.io/some-project-162817/sancho")
+	newEntity, injected, err := InjectCopyHelper(entity, selector)
+	require.NoError(t, err)
+	assert.True(t, injected)
+
+	containers := newEntity.Obj.(*appsv1.Deployment).Spec.Template.Spec.Containers
+	require.Len(t, containers, 2)
+	helper := containers[1]
+	assert.Equal(t, string(CopyHelperContainerName), helper.Name)
+	assert.Equal(t, DefaultCopyHelperImage, helper.Image)
+	assert.Equal(t, mounts, helper.VolumeMounts)
+
+	// Injecting again doesn't add a second helper.
+	newEntity, injected, err = InjectCopyHelper(newEntity, selector)
+	require.NoError(t, err)
+	assert.True(t, injected)
+	assert.Len(t, newEntity.Obj.(*appsv1.Deployment).Spec.Template.Spec.Containers, 2)
+}
+
+func TestInjectCopyHelperNoMatch(t *testing.T) {
+	entities, err := k8s.ParseYAMLFromString(testyaml.SanchoYAML)
+	require.NoError(t, err)
+	require.Len(t, entities, 1)
+
+	selector := container.MustParseSelector("gcr.io/some-project-162817/not-sancho")
+	_, injected, err := InjectCopyHelper(entities[0], selector)
+	require.NoError(t, err)
+	assert.False(t, injected)
+}
@@ -0,0 +1,59 @@
+package containerupdate
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/logger"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// CopySidecarUpdater live-updates containers that have neither tar nor a
+// shell (distroless, scratch). Instead of exec'ing into the app container, it
+// execs into the tilt-copy-helper sidecar, which shares the app container's
+// volumes; syncs that land on a shared volume are visible to the app.
+type CopySidecarUpdater struct {
+	kCli k8s.Client
+}
+
+var _ ContainerUpdater = &CopySidecarUpdater{}
+
+func NewCopySidecarUpdater(kCli k8s.Client) *CopySidecarUpdater {
+	return &CopySidecarUpdater{kCli: kCli}
+}
+
+func (cu *CopySidecarUpdater) UpdateContainer(ctx context.Context, cInfo store.ContainerInfo,
+	archiveToCopy io.Reader, filesToDelete []string, cmds []model.Cmd, hotReload bool) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "CopySidecarUpdater-UpdateContainer")
+	defer span.Finish()
+
+	if !hotReload {
+		return fmt.Errorf("CopySidecarUpdater does not support `restart_container()` step. If you ran Tilt " +
+			"with `--update-mode=copy-sidecar`, omit this flag. If you are using a non-Docker container runtime, " +
+			"see https://github.com/windmilleng/rerun-process-wrapper for a workaround")
+	}
+
+	if len(cmds) > 0 {
+		return fmt.Errorf("CopySidecarUpdater does not support `run()` steps: the app container has no shell " +
+			"to run them in. Remove the run() steps from your live_update, or use an image that carries a shell")
+	}
+
+	w := logger.Get(ctx).Writer(logger.InfoLvl)
+
+	if len(filesToDelete) > 0 {
+		err := cu.kCli.Exec(ctx,
+			cInfo.PodID, CopyHelperContainerName, cInfo.Namespace,
+			append([]string{"rm", "-rf"}, filesToDelete...), nil, w, w)
+		if err != nil {
+			return err
+		}
+	}
+
+	return cu.kCli.Exec(ctx, cInfo.PodID, CopyHelperContainerName, cInfo.Namespace,
+		[]string{"tar", "-C", "/", "-x", "-f", "-"}, archiveToCopy, w, w)
+}
@@ -0,0 +1,68 @@
+package containerupdate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/testutils"
+)
+
+func TestCopySidecarDoesntSupportRestart(t *testing.T) {
+	f := newCopySidecarFixture(t)
+
+	err := f.csu.UpdateContainer(f.ctx, TestContainerInfo, newReader("boop"), toDelete, nil, false)
+	if assert.NotNil(t, err, "expect CopySidecar UpdateContainer to fail if !hotReload") {
+		assert.Contains(t, err.Error(), "CopySidecarUpdater does not support `restart_container()` step")
+	}
+}
+
+func TestCopySidecarDoesntSupportRunSteps(t *testing.T) {
+	f := newCopySidecarFixture(t)
+
+	err := f.csu.UpdateContainer(f.ctx, TestContainerInfo, newReader("boop"), nil, cmds, true)
+	if assert.NotNil(t, err, "expect CopySidecar UpdateContainer to fail when given run() steps") {
+		assert.Contains(t, err.Error(), "CopySidecarUpdater does not support `run()` steps")
+	}
+}
+
+func TestCopySidecarExecsIntoHelper(t *testing.T) {
+	f := newCopySidecarFixture(t)
+
+	err := f.csu.UpdateContainer(f.ctx, TestContainerInfo, newReader("hello world"), toDelete, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, f.kCli.ExecCalls, 2, "expect exactly 2 k8s exec calls") {
+		rmCall := f.kCli.ExecCalls[0]
+		assert.Equal(t, CopyHelperContainerName, rmCall.CName)
+		assert.Equal(t, []string{"rm", "-rf", "/foo/delete_me", "/bar/me_too"}, rmCall.Cmd)
+
+		tarCall := f.kCli.ExecCalls[1]
+		assert.Equal(t, CopyHelperContainerName, tarCall.CName)
+		assert.Equal(t, []string{"tar", "-C", "/", "-x", "-f", "-"}, tarCall.Cmd)
+		assert.Equal(t, []byte("hello world"), tarCall.Stdin)
+	}
+}
+
+type copySidecarFixture struct {
+	t    testing.TB
+	ctx  context.Context
+	kCli *k8s.FakeK8sClient
+	csu  *CopySidecarUpdater
+}
+
+func newCopySidecarFixture(t testing.TB) *copySidecarFixture {
+	fakeCli := k8s.NewFakeK8sClient()
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+
+	return &copySidecarFixture{
+		t:    t,
+		ctx:  ctx,
+		kCli: fakeCli,
+		csu:  NewCopySidecarUpdater(fakeCli),
+	}
+}
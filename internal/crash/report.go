@@ -0,0 +1,143 @@
+package crash
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/windmilleng/tilt/internal/store"
+)
+
+// Crash bundles are only ever submitted when this endpoint is set;
+// an unset value means bundles stay on the local disk.
+const endpointEnvName = "TILT_CRASH_REPORT_ENDPOINT"
+
+// Writes a crash bundle for the given fatal error and tells the user
+// where it landed. If a crash report endpoint is configured, also
+// submits the bundle there.
+func Report(crashErr error, st *store.Store, out io.Writer) {
+	path, err := WriteBundle(crashErr, st)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "Error writing crash bundle: %v\n", err)
+		return
+	}
+	_, _ = fmt.Fprintf(out, "Tilt hit a fatal error. Wrote a crash bundle to %s\n", path)
+
+	endpoint := os.Getenv(endpointEnvName)
+	if endpoint == "" {
+		return
+	}
+	err = submit(endpoint, path)
+	if err != nil {
+		_, _ = fmt.Fprintf(out, "Error submitting crash bundle: %v\n", err)
+		return
+	}
+	_, _ = fmt.Fprintf(out, "Submitted crash bundle to %s\n", endpoint)
+}
+
+// Writes a local crash bundle (stack traces, recent actions, and a
+// redacted state summary) to a temp file and returns its path.
+func WriteBundle(crashErr error, st *store.Store) (string, error) {
+	f, err := ioutil.TempFile("", "tilt-crash-*.txt")
+	if err != nil {
+		return "", errors.Wrap(err, "WriteBundle")
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	err = writeBundleTo(f, crashErr, st)
+	if err != nil {
+		return "", errors.Wrap(err, "WriteBundle")
+	}
+	return f.Name(), nil
+}
+
+func writeBundleTo(w io.Writer, crashErr error, st *store.Store) error {
+	var b bytes.Buffer
+
+	state := st.RLockState()
+	version := state.TiltBuildInfo
+	summary := stateSummary(state)
+	st.RUnlockState()
+
+	fmt.Fprintf(&b, "Tilt crash report\n")
+	fmt.Fprintf(&b, "Version: %s (%s)\n\n", version.Version, version.Date)
+	fmt.Fprintf(&b, "Error:\n%v\n\n", crashErr)
+
+	fmt.Fprintf(&b, "Recent actions (oldest first):\n")
+	for _, a := range st.ActionHistory() {
+		fmt.Fprintf(&b, "  %s\n", a)
+	}
+	fmt.Fprintf(&b, "\n")
+
+	// The summary deliberately only contains resource names, statuses, and
+	// error messages -- no file contents, env vars, or Tiltfile arguments.
+	fmt.Fprintf(&b, "State summary (redacted):\n%s\n", summary)
+
+	fmt.Fprintf(&b, "Goroutine stacks:\n%s\n", allStacks())
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+func stateSummary(state store.EngineState) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "  %d resources\n", len(state.ManifestTargets))
+	for _, mt := range state.Targets() {
+		status := "unknown"
+		if mt.State.RuntimeState != nil {
+			status = string(mt.State.RuntimeState.RuntimeStatus())
+		}
+
+		lastBuild := "no builds"
+		if !mt.State.LastBuild().Empty() {
+			if mt.State.LastBuild().Error != nil {
+				lastBuild = fmt.Sprintf("last build failed: %v", mt.State.LastBuild().Error)
+			} else {
+				lastBuild = "last build ok"
+			}
+		}
+		fmt.Fprintf(&b, "  %s: runtime %s, %s\n", mt.Manifest.Name, status, lastBuild)
+	}
+	return b.String()
+}
+
+func allStacks() []byte {
+	buf := make([]byte, 1024*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+func submit(endpoint string, path string) error {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(endpoint, "text/plain", bytes.NewReader(contents))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("crash endpoint returned status %s: %s", resp.Status, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package crash
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestWriteBundle(t *testing.T) {
+	st, _ := store.NewStoreWithFakeReducer()
+	st.Dispatch(fakeAction{})
+
+	state := st.LockMutableStateForTesting()
+	state.TiltBuildInfo = model.TiltBuild{Version: "v0.0.0-test"}
+	m := model.Manifest{Name: "fe"}
+	mt := store.NewManifestTarget(m)
+	mt.State.AddCompletedBuild(model.BuildRecord{
+		StartTime:  time.Now().Add(-time.Second),
+		FinishTime: time.Now(),
+		Error:      fmt.Errorf("compile error"),
+	})
+	state.UpsertManifestTarget(mt)
+	st.UnlockMutableState()
+
+	var buf bytes.Buffer
+	err := writeBundleTo(&buf, fmt.Errorf("PANIC: oh no"), st)
+	require.NoError(t, err)
+
+	contents := buf.String()
+	assert.Contains(t, contents, "Version: v0.0.0-test")
+	assert.Contains(t, contents, "PANIC: oh no")
+	assert.Contains(t, contents, "crash.fakeAction")
+	assert.Contains(t, contents, "fe: runtime unknown, last build failed: compile error")
+	assert.Contains(t, contents, "goroutine")
+
+	// Nothing sensitive should sneak into the bundle.
+	assert.NotContains(t, contents, "UserConfigState")
+}
+
+func TestActionHistoryBounded(t *testing.T) {
+	st, _ := store.NewStoreWithFakeReducer()
+	for i := 0; i < 100; i++ {
+		st.Dispatch(fakeAction{})
+	}
+	assert.Equal(t, 50, len(st.ActionHistory()))
+}
+
+type fakeAction struct{}
+
+func (fakeAction) Action() {}
@@ -2,6 +2,7 @@ package docker
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -11,6 +12,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/blang/semver"
@@ -26,8 +28,10 @@ import (
 	"github.com/moby/buildkit/identity"
 	"github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/auth/authprovider"
+	"github.com/moby/buildkit/session/filesync"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	fstypes "github.com/tonistiigi/fsutil/types"
 
 	"github.com/windmilleng/tilt/internal/container"
 	"github.com/windmilleng/tilt/internal/docker/buildkit"
@@ -97,6 +101,7 @@ type Client interface {
 	// Returns an ExitError if the command exits with a non-zero exit code.
 	ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, out io.Writer) error
 
+	ImagePull(ctx context.Context, image reference.Named) (io.ReadCloser, error)
 	ImagePush(ctx context.Context, image reference.NamedTagged) (io.ReadCloser, error)
 	ImageBuild(ctx context.Context, buildContext io.Reader, options BuildOptions) (types.ImageBuildResponse, error)
 	ImageTag(ctx context.Context, source, target string) error
@@ -135,6 +140,45 @@ type Cli struct {
 	initError error
 	initDone  chan bool
 	env       Env
+
+	// Long-lived buildkit sessions, keyed by context dir and ssh/secret
+	// specs. Reusing a session across builds avoids redoing the session
+	// handshake on every build, and lets the daemon diff the build context
+	// against the previous build's snapshot so only changed files cross
+	// the wire.
+	//
+	// The sessions run against the client's lifetime context rather than
+	// any one build's, so they survive for the next build. A session whose
+	// server loop has exited (e.g. the daemon dropped it) is evicted and
+	// replaced on the next build.
+	sessionCtx      context.Context
+	buildSessions   map[string]*buildSession
+	buildSessionsMu sync.Mutex
+}
+
+// A long-lived buildkit session and the local state backing it.
+type buildSession struct {
+	session *session.Session
+
+	// Directory served as the "dockerfile" synced dir. Tilt generates the
+	// Dockerfile, so it's rewritten here before each build rather than
+	// read out of the context.
+	dockerfileDir string
+
+	// Closed when the session's server loop exits, so a dead session can
+	// be detected and replaced instead of reused forever.
+	done chan struct{}
+}
+
+func (bs *buildSession) writeDockerfile(contents string) error {
+	return ioutil.WriteFile(filepath.Join(bs.dockerfileDir, "Dockerfile"), []byte(contents), 0644)
+}
+
+func (bs *buildSession) close() {
+	_ = bs.session.Close()
+	if bs.dockerfileDir != "" {
+		_ = os.RemoveAll(bs.dockerfileDir)
+	}
 }
 
 func NewDockerClient(ctx context.Context, env Env) Client {
@@ -173,6 +217,8 @@ func NewDockerClient(ctx context.Context, env Env) Client {
 		builderVersion: builderVersion,
 		serverVersion:  serverVersion,
 		initDone:       make(chan bool),
+		sessionCtx:     ctx,
+		buildSessions:  make(map[string]*buildSession),
 	}
 
 	go cli.backgroundInit(ctx)
@@ -289,10 +335,10 @@ type dockerCreds struct {
 	sessionID   string
 }
 
-func (c *Cli) startBuildkitSession(ctx context.Context, key string, sshSpecs []string, secretSpecs []string) (*session.Session, error) {
+func (c *Cli) startBuildkitSession(ctx context.Context, key string, sshSpecs []string, secretSpecs []string, extras ...session.Attachable) (*session.Session, chan struct{}, error) {
 	session, err := session.NewSession(ctx, "tilt", key)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 
 	}
 
@@ -302,7 +348,7 @@ func (c *Cli) startBuildkitSession(ctx context.Context, key string, sshSpecs []s
 	if len(secretSpecs) > 0 {
 		ss, err := buildkit.ParseSecretSpecs(secretSpecs)
 		if err != nil {
-			return nil, errors.Wrapf(err, "could not parse secret: %v", secretSpecs)
+			return nil, nil, errors.Wrapf(err, "could not parse secret: %v", secretSpecs)
 		}
 		session.Allow(ss)
 	}
@@ -310,12 +356,18 @@ func (c *Cli) startBuildkitSession(ctx context.Context, key string, sshSpecs []s
 	if len(sshSpecs) > 0 {
 		sshp, err := buildkit.ParseSSHSpecs(sshSpecs)
 		if err != nil {
-			return nil, errors.Wrapf(err, "could not parse ssh: %v", sshSpecs)
+			return nil, nil, errors.Wrapf(err, "could not parse ssh: %v", sshSpecs)
 		}
 		session.Allow(sshp)
 	}
 
+	for _, a := range extras {
+		session.Allow(a)
+	}
+
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
 		defer func() {
 			_ = session.Close()
 		}()
@@ -326,7 +378,7 @@ func (c *Cli) startBuildkitSession(ctx context.Context, key string, sshSpecs []s
 		}
 		_ = session.Run(ctx, dialSession)
 	}()
-	return session, nil
+	return session, done, nil
 }
 
 // When we pull from a private docker registry, we have to get credentials
@@ -347,7 +399,7 @@ func (c *Cli) initCreds(ctx context.Context) dockerCreds {
 	creds := dockerCreds{}
 
 	if c.builderVersion == types.BuilderBuildKit {
-		session, err := c.startBuildkitSession(ctx, sessionSharedKey, nil, nil)
+		session, _, err := c.startBuildkitSession(ctx, sessionSharedKey, nil, nil)
 		if err != nil {
 			logger.Get(ctx).Warnf("Docker BuildKit session failed to init: %v", err)
 		} else if session != nil {
@@ -408,6 +460,47 @@ func (c *Cli) ServerVersion() types.Version {
 	return c.serverVersion
 }
 
+func (c *Cli) ImagePull(ctx context.Context, ref reference.Named) (io.ReadCloser, error) {
+	<-c.initDone
+
+	if c.initError != nil {
+		logger.Get(ctx).Verbosef("%v", c.initError)
+	}
+
+	repoInfo, err := registry.ParseRepositoryInfo(ref)
+	if err != nil {
+		return nil, errors.Wrap(err, "ImagePull#ParseRepositoryInfo")
+	}
+
+	infoWriter := logger.Get(ctx).Writer(logger.InfoLvl)
+	cli, err := command.NewDockerCli(
+		command.WithCombinedStreams(infoWriter),
+		command.WithContentTrust(true),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "ImagePull#NewDockerCli")
+	}
+
+	err = cli.Initialize(cliflags.NewClientOptions())
+	if err != nil {
+		return nil, errors.Wrap(err, "ImagePull#InitializeCLI")
+	}
+	authConfig := command.ResolveAuthConfig(ctx, cli, repoInfo.Index)
+	requestPrivilege := command.RegistryAuthenticationPrivilegedFunc(cli, repoInfo.Index, "pull")
+
+	encodedAuth, err := command.EncodeAuthToBase64(authConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "ImagePull#EncodeAuthToBase64")
+	}
+
+	options := types.ImagePullOptions{
+		RegistryAuth:  encodedAuth,
+		PrivilegeFunc: requestPrivilege,
+	}
+
+	return c.Client.ImagePull(ctx, ref.String(), options)
+}
+
 func (c *Cli) ImagePush(ctx context.Context, ref reference.NamedTagged) (io.ReadCloser, error) {
 	<-c.initDone
 
@@ -454,6 +547,11 @@ func (c *Cli) ImagePush(ctx context.Context, ref reference.NamedTagged) (io.Read
 	return c.Client.ImagePush(ctx, ref.String(), options)
 }
 
+// The magic RemoteContext value that tells the daemon to pull the build
+// context over the session (filesync) instead of reading a tarball from
+// the request body.
+const remoteContextClientSession = "client-session"
+
 func (c *Cli) ImageBuild(ctx context.Context, buildContext io.Reader, options BuildOptions) (types.ImageBuildResponse, error) {
 	<-c.initDone
 
@@ -461,20 +559,22 @@ func (c *Cli) ImageBuild(ctx context.Context, buildContext io.Reader, options Bu
 		logger.Get(ctx).Verbosef("%v", c.initError)
 	}
 
-	var oneTimeSession *session.Session
+	syncContext := options.ContextDir != "" && c.builderVersion == types.BuilderBuildKit
+
 	sessionID := c.creds.sessionID
-	if len(options.SSHSpecs) > 0 || len(options.SecretSpecs) > 0 {
+	var bs *buildSession
+	if syncContext || len(options.SSHSpecs) > 0 || len(options.SecretSpecs) > 0 {
 		if c.builderVersion != types.BuilderBuildKit {
 			return types.ImageBuildResponse{},
 				fmt.Errorf("Docker SSH secrets only work on Buildkit, but Buildkit has been disabled")
 		}
 
 		var err error
-		oneTimeSession, err = c.startBuildkitSession(ctx, identity.NewID(), options.SSHSpecs, options.SecretSpecs)
+		bs, err = c.buildSessionFor(options, syncContext)
 		if err != nil {
 			return types.ImageBuildResponse{}, errors.Wrapf(err, "ImageBuild")
 		}
-		sessionID = oneTimeSession.ID()
+		sessionID = bs.session.ID()
 	}
 
 	opts := types.ImageBuildOptions{}
@@ -482,6 +582,7 @@ func (c *Cli) ImageBuild(ctx context.Context, buildContext io.Reader, options Bu
 	opts.AuthConfigs = c.creds.authConfigs
 	opts.SessionID = sessionID
 	opts.Remove = options.Remove
+
 	opts.Context = options.Context
 	opts.BuildArgs = options.BuildArgs
 	opts.Dockerfile = options.Dockerfile
@@ -489,20 +590,121 @@ func (c *Cli) ImageBuild(ctx context.Context, buildContext io.Reader, options Bu
 	opts.Target = options.Target
 	opts.NetworkMode = options.Network
 
+	if syncContext {
+		// Sync the context over the session instead of sending a tarball
+		// in the request body. The daemon diffs the context against the
+		// snapshot it cached under the session's shared key, so after the
+		// first build only changed files cross the wire.
+		err := bs.writeDockerfile(options.DockerfileContents)
+		if err != nil {
+			return types.ImageBuildResponse{}, errors.Wrapf(err, "ImageBuild")
+		}
+		opts.RemoteContext = remoteContextClientSession
+		opts.Dockerfile = "Dockerfile"
+		opts.Context = nil
+		buildContext = nil
+	}
+
 	opts.Labels = BuiltByTiltLabel // label all images as built by us
 
-	response, err := c.Client.ImageBuild(ctx, buildContext, opts)
+	return c.Client.ImageBuild(ctx, buildContext, opts)
+}
+
+// buildSessionFor returns a long-lived session for the given build,
+// starting one on the first build that needs it. The session forwards the
+// build's ssh agents and secrets, and (when syncContext is set) serves the
+// build context and Dockerfile from disk.
+//
+// A session whose server loop has exited -- e.g. because the daemon
+// restarted -- is discarded and a fresh one is started in its place.
+func (c *Cli) buildSessionFor(options BuildOptions, syncContext bool) (*buildSession, error) {
+	key := options.ContextDir +
+		"\x01" + strings.Join(options.SSHSpecs, "\x00") +
+		"\x01" + strings.Join(options.SecretSpecs, "\x00")
+
+	c.buildSessionsMu.Lock()
+	defer c.buildSessionsMu.Unlock()
+
+	bs, ok := c.buildSessions[key]
+	if ok {
+		select {
+		case <-bs.done:
+			// The session died. Clean it up and start another.
+			bs.close()
+			delete(c.buildSessions, key)
+		default:
+			return bs, nil
+		}
+	}
+
+	var dockerfileDir string
+	var extras []session.Attachable
+	if syncContext {
+		var err error
+		dockerfileDir, err = ioutil.TempDir("", "tilt-dockerfile-")
+		if err != nil {
+			return nil, err
+		}
+
+		extras = append(extras, filesync.NewFSSyncProvider([]filesync.SyncedDir{
+			{
+				Name: "context",
+				Dir:  options.ContextDir,
+				Map:  fileSyncMap(options.ContextDir, options.Filter),
+			},
+			{
+				Name: "dockerfile",
+				Dir:  dockerfileDir,
+			},
+		}))
+	}
+
+	// The daemon keys its cached context snapshots on the session's shared
+	// key, so derive it from the build key to get incremental re-syncs of
+	// the same context dir even across Tilt restarts.
+	sharedKey := fmt.Sprintf("%x", sha256.Sum256([]byte(key)))
+
+	s, done, err := c.startBuildkitSession(c.sessionCtx, sharedKey, options.SSHSpecs, options.SecretSpecs, extras...)
 	if err != nil {
-		if oneTimeSession != nil {
-			_ = oneTimeSession.Close()
+		if dockerfileDir != "" {
+			_ = os.RemoveAll(dockerfileDir)
 		}
-		return response, err
+		return nil, err
 	}
 
-	if oneTimeSession != nil {
-		response.Body = WrapReadCloserWithTearDown(response.Body, oneTimeSession.Close)
+	bs = &buildSession{session: s, dockerfileDir: dockerfileDir, done: done}
+	c.buildSessions[key] = bs
+	return bs, nil
+}
+
+// fileSyncMap adapts Tilt's ignore filter to the context walk, mirroring
+// what tarring the context does: filtered files stay home, and owner info
+// is cleared so builds don't depend on local uids.
+func fileSyncMap(contextDir string, filter model.PathMatcher) func(string, *fstypes.Stat) bool {
+	return func(p string, stat *fstypes.Stat) bool {
+		if filter != nil {
+			matches, err := filter.Matches(filepath.Join(contextDir, filepath.FromSlash(p)))
+			if err == nil && matches {
+				return false
+			}
+		}
+		stat.Uid = 0
+		stat.Gid = 0
+		return true
 	}
-	return response, err
+}
+
+// Close shuts down the long-lived buildkit sessions, then the underlying
+// client.
+func (c *Cli) Close() error {
+	c.buildSessionsMu.Lock()
+	for key, bs := range c.buildSessions {
+		bs.close()
+		delete(c.buildSessions, key)
+	}
+	c.buildSessionsMu.Unlock()
+
+	return c.Client.Close()
 }
 
 func (c *Cli) CopyToContainerRoot(ctx context.Context, container string, content io.Reader) error {
@@ -53,6 +53,9 @@ func (c explodingClient) CopyToContainerRoot(ctx context.Context, container stri
 func (c explodingClient) ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, out io.Writer) error {
 	return c.err
 }
+func (c explodingClient) ImagePull(ctx context.Context, ref reference.Named) (io.ReadCloser, error) {
+	return nil, c.err
+}
 func (c explodingClient) ImagePush(ctx context.Context, ref reference.NamedTagged) (io.ReadCloser, error) {
 	return nil, c.err
 }
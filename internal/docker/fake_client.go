@@ -85,6 +85,10 @@ type FakeClient struct {
 	PushOptions types.ImagePushOptions
 	PushOutput  string
 
+	PullCount        int
+	ImagesPulled     []string
+	PullErrorToThrow error // next call to ImagePull will throw this err (after which we clear the error)
+
 	BuildCount        int
 	BuildOptions      BuildOptions
 	BuildOutput       string
@@ -93,6 +97,10 @@ type FakeClient struct {
 	ImageListCount int
 	ImageListOpts  []types.ImageListOptions
 
+	// If set, returned from ImageList as-is instead of synthesizing
+	// ImageListCount summaries.
+	ImageListOutput []types.ImageSummary
+
 	TagCount  int
 	TagSource string
 	TagTarget string
@@ -217,6 +225,19 @@ func (c *FakeClient) CopyToContainerRoot(ctx context.Context, container string,
 	return nil
 }
 
+func (c *FakeClient) ImagePull(ctx context.Context, ref reference.Named) (io.ReadCloser, error) {
+	c.PullCount++
+	c.ImagesPulled = append(c.ImagesPulled, ref.String())
+
+	err := c.PullErrorToThrow
+	if err != nil {
+		c.PullErrorToThrow = nil
+		return nil, err
+	}
+
+	return NewFakeDockerResponse(""), nil
+}
+
 func (c *FakeClient) ImagePush(ctx context.Context, ref reference.NamedTagged) (io.ReadCloser, error) {
 	c.PushCount++
 	c.PushImage = ref.String()
@@ -254,6 +275,9 @@ func (c *FakeClient) ImageInspectWithRaw(ctx context.Context, imageID string) (t
 
 func (c *FakeClient) ImageList(ctx context.Context, options types.ImageListOptions) ([]types.ImageSummary, error) {
 	c.ImageListOpts = append(c.ImageListOpts, options)
+	if c.ImageListOutput != nil {
+		return c.ImageListOutput, nil
+	}
 	summaries := make([]types.ImageSummary, c.ImageListCount)
 	for i := range summaries {
 		summaries[i] = types.ImageSummary{
@@ -1,6 +1,10 @@
 package docker
 
-import "io"
+import (
+	"io"
+
+	"github.com/windmilleng/tilt/pkg/model"
+)
 
 type BuildOptions struct {
 	Context     io.Reader
@@ -12,4 +16,19 @@ type BuildOptions struct {
 	SecretSpecs []string
 	Network     string
 	ExtraTags   []string
+
+	// If non-empty, the directory of the build context on disk. On BuildKit,
+	// the context is synced from this directory over the build session --
+	// incrementally, so only files that changed since the previous build
+	// cross the wire -- and Context is ignored.
+	ContextDir string
+
+	// The Dockerfile contents to build with when syncing the context from
+	// ContextDir. (Tilt generates the Dockerfile, so it isn't necessarily
+	// on disk inside the context.)
+	DockerfileContents string
+
+	// Files matching the filter are left out of the synced context,
+	// mirroring what the tar path does to the archive.
+	Filter model.PathMatcher
 }
@@ -72,6 +72,9 @@ func (c *switchCli) CopyToContainerRoot(ctx context.Context, container string, c
 func (c *switchCli) ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, out io.Writer) error {
 	return c.client().ExecInContainer(ctx, cID, cmd, out)
 }
+func (c *switchCli) ImagePull(ctx context.Context, ref reference.Named) (io.ReadCloser, error) {
+	return c.client().ImagePull(ctx, ref)
+}
 func (c *switchCli) ImagePush(ctx context.Context, ref reference.NamedTagged) (io.ReadCloser, error) {
 	return c.client().ImagePush(ctx, ref)
 }
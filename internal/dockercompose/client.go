@@ -27,11 +27,17 @@ type DockerComposeClient interface {
 	Config(ctx context.Context, configPaths []string) (string, error)
 	Services(ctx context.Context, configPaths []string) (string, error)
 	ContainerID(ctx context.Context, configPaths []string, serviceName model.TargetName) (container.ID, error)
+
+	// Set the compose profiles to activate on every subsequent command.
+	// Called when the Tiltfile is loaded, before any services are parsed,
+	// since profiles change which services `docker-compose config` reports.
+	SetProfiles(profiles []string)
 }
 
 type cmdDCClient struct {
-	env docker.Env
-	mu  *sync.Mutex
+	env      docker.Env
+	profiles []string
+	mu       *sync.Mutex
 }
 
 // TODO(dmiller): we might want to make this take a path to the docker-compose config so we don't
@@ -54,9 +60,7 @@ func (c *cmdDCClient) Up(ctx context.Context, configPaths []string, serviceName
 		args = []string{"--verbose"}
 	}
 
-	for _, config := range configPaths {
-		args = append(args, "-f", config)
-	}
+	args = append(args, c.projectArgs(configPaths)...)
 
 	args = append(args, "up", "--no-deps", "-d")
 
@@ -88,9 +92,7 @@ func (c *cmdDCClient) Down(ctx context.Context, configPaths []string, stdout, st
 	if logger.Get(ctx).Level().ShouldDisplay(logger.VerboseLvl) {
 		args = []string{"--verbose"}
 	}
-	for _, config := range configPaths {
-		args = append(args, "-f", config)
-	}
+	args = append(args, c.projectArgs(configPaths)...)
 
 	args = append(args, "down")
 	cmd := c.dcCommand(ctx, args)
@@ -109,9 +111,7 @@ func (c *cmdDCClient) StreamLogs(ctx context.Context, configPaths []string, serv
 	// TODO(maia): --since time
 	// (may need to implement with `docker log <cID>` instead since `d-c log` doesn't support `--since`
 	var args []string
-	for _, config := range configPaths {
-		args = append(args, "-f", config)
-	}
+	args = append(args, c.projectArgs(configPaths)...)
 	args = append(args, "logs", "--no-color", "-f", serviceName.String())
 	cmd := c.dcCommand(ctx, args)
 	stdout, err := cmd.StdoutPipe()
@@ -142,9 +142,7 @@ func (c *cmdDCClient) StreamEvents(ctx context.Context, configPaths []string) (<
 	ch := make(chan string)
 
 	var args []string
-	for _, config := range configPaths {
-		args = append(args, "-f", config)
-	}
+	args = append(args, c.projectArgs(configPaths)...)
 	args = append(args, "events", "--json")
 	cmd := c.dcCommand(ctx, args)
 	stdout, err := cmd.StdoutPipe()
@@ -193,6 +191,23 @@ func (c *cmdDCClient) ContainerID(ctx context.Context, configPaths []string, ser
 	return container.ID(id), nil
 }
 
+func (c *cmdDCClient) SetProfiles(profiles []string) {
+	c.profiles = profiles
+}
+
+// The global flags every docker-compose invocation needs: one -f per config
+// file, one --profile per active profile.
+func (c *cmdDCClient) projectArgs(configPaths []string) []string {
+	var args []string
+	for _, config := range configPaths {
+		args = append(args, "-f", config)
+	}
+	for _, profile := range c.profiles {
+		args = append(args, "--profile", profile)
+	}
+	return args
+}
+
 func (c *cmdDCClient) dcCommand(ctx context.Context, args []string) *exec.Cmd {
 	cmd := exec.CommandContext(ctx, "docker-compose", args...)
 	cmd.Env = append(os.Environ(), c.env.AsEnviron()...)
@@ -201,11 +216,7 @@ func (c *cmdDCClient) dcCommand(ctx context.Context, args []string) *exec.Cmd {
 
 func (c *cmdDCClient) dcOutput(ctx context.Context, configPaths []string, args ...string) (string, error) {
 
-	var tempArgs []string
-	for _, config := range configPaths {
-		tempArgs = append(tempArgs, "-f", config)
-	}
-	args = append(tempArgs, args...)
+	args = append(c.projectArgs(configPaths), args...)
 	cmd := c.dcCommand(ctx, args)
 
 	output, err := cmd.Output()
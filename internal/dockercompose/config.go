@@ -2,6 +2,7 @@ package dockercompose
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -49,11 +50,96 @@ func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
 }
 
 type ServiceConfig struct {
-	RawYAML []byte      // We store this to diff against when docker-compose.yml is edited to see if the manifest has changed
-	Build   BuildConfig `yaml:"build"`
-	Image   string      `yaml:"image"`
-	Volumes Volumes     `yaml:"volumes"`
-	Ports   Ports       `yaml:"ports"`
+	RawYAML     []byte      // We store this to diff against when docker-compose.yml is edited to see if the manifest has changed
+	Build       BuildConfig `yaml:"build"`
+	Image       string      `yaml:"image"`
+	Volumes     Volumes     `yaml:"volumes"`
+	Ports       Ports       `yaml:"ports"`
+	DependsOn   DependsOn   `yaml:"depends_on"`
+	HealthCheck HealthCheck `yaml:"healthcheck"`
+	EnvFiles    EnvFiles    `yaml:"env_file"`
+}
+
+// The services this service depends on. Compose supports both a short form
+// (a list of service names) and a long form (a map of service name to
+// {condition: ...}); we accept both and only keep the names, since Tilt
+// always waits for a dependency to be ready (healthy if it has a
+// healthcheck, started otherwise) before starting the dependent.
+type DependsOn struct {
+	ServiceNames []string
+}
+
+func (d *DependsOn) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var short []string
+	if err := unmarshal(&short); err == nil {
+		d.ServiceNames = short
+		return nil
+	}
+
+	var long map[string]struct {
+		Condition string `yaml:"condition"`
+	}
+	if err := unmarshal(&long); err != nil {
+		return errors.Wrap(err, "unmarshalling depends_on")
+	}
+
+	for name := range long {
+		d.ServiceNames = append(d.ServiceNames, name)
+	}
+	sort.Strings(d.ServiceNames)
+	return nil
+}
+
+// Marshal back out in the short form, so re-marshaled configs stay parseable.
+func (d DependsOn) MarshalYAML() (interface{}, error) {
+	return d.ServiceNames, nil
+}
+
+type HealthCheck struct {
+	Disable bool            `yaml:"disable"`
+	Test    HealthCheckTest `yaml:"test"`
+}
+
+// Whether the container will report a health status Tilt can gate on.
+func (h HealthCheck) Defined() bool {
+	return len(h.Test) > 0 && !h.Disable &&
+		!(len(h.Test) == 1 && h.Test[0] == "NONE")
+}
+
+// The test can be a string or a list (["CMD", ...], ["NONE"], etc.).
+type HealthCheckTest []string
+
+func (t *HealthCheckTest) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*t = []string{single}
+		return nil
+	}
+
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return errors.Wrap(err, "unmarshalling healthcheck test")
+	}
+	*t = list
+	return nil
+}
+
+// env_file can be a string or a list of strings, relative to the compose file.
+type EnvFiles []string
+
+func (e *EnvFiles) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var single string
+	if err := unmarshal(&single); err == nil {
+		*e = []string{single}
+		return nil
+	}
+
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return errors.Wrap(err, "unmarshalling env_file")
+	}
+	*e = list
+	return nil
 }
 
 type BuildConfig struct {
@@ -86,6 +86,8 @@ const (
 	ActionExecStart
 	ActionExport
 	ActionHealthStatus
+	ActionHealthStatusHealthy
+	ActionHealthStatusUnhealthy
 	ActionKill
 	ActionOom
 	ActionPause
@@ -100,29 +102,32 @@ const (
 )
 
 var stringToAction = map[string]Action{
-	"attach":        ActionAttach,
-	"commit":        ActionCommit,
-	"copy":          ActionCopy,
-	"create":        ActionCreate,
-	"destroy":       ActionDestroy,
-	"die":           ActionDie,
-	"exec_create":   ActionExecCreate,
-	"exec_detach":   ActionExecDetach,
-	"exec_die":      ActionExecDie,
-	"exec_start":    ActionExecStart,
-	"export":        ActionExport,
-	"health_status": ActionHealthStatus,
-	"kill":          ActionKill,
-	"oom":           ActionOom,
-	"pause":         ActionPause,
-	"rename":        ActionRename,
-	"resize":        ActionResize,
-	"restart":       ActionRestart,
-	"start":         ActionStart,
-	"stop":          ActionStop,
-	"top":           ActionTop,
-	"unpause":       ActionUnpause,
-	"update":        ActionUpdate,
+	"attach":      ActionAttach,
+	"commit":      ActionCommit,
+	"copy":        ActionCopy,
+	"create":      ActionCreate,
+	"destroy":     ActionDestroy,
+	"die":         ActionDie,
+	"exec_create": ActionExecCreate,
+	"exec_detach": ActionExecDetach,
+	"exec_die":    ActionExecDie,
+	"exec_start":  ActionExecStart,
+	"export":      ActionExport,
+	// Docker embeds the health status in the action string.
+	"health_status":            ActionHealthStatus,
+	"health_status: healthy":   ActionHealthStatusHealthy,
+	"health_status: unhealthy": ActionHealthStatusUnhealthy,
+	"kill":                     ActionKill,
+	"oom":                      ActionOom,
+	"pause":                    ActionPause,
+	"rename":                   ActionRename,
+	"resize":                   ActionResize,
+	"restart":                  ActionRestart,
+	"start":                    ActionStart,
+	"stop":                     ActionStop,
+	"top":                      ActionTop,
+	"unpause":                  ActionUnpause,
+	"update":                   ActionUpdate,
 }
 
 func (a Action) String() string {
@@ -20,6 +20,7 @@ type FakeDCClient struct {
 	eventJson         chan string
 	ConfigOutput      string
 	ServicesOutput    string
+	Profiles          []string
 
 	UpCalls   []UpCall
 	DownError error
@@ -119,3 +120,7 @@ func (c *FakeDCClient) Services(ctx context.Context, configPaths []string) (stri
 func (c *FakeDCClient) ContainerID(ctx context.Context, configPaths []string, serviceName model.TargetName) (container.ID, error) {
 	return c.ContainerIdOutput, nil
 }
+
+func (c *FakeDCClient) SetProfiles(profiles []string) {
+	c.Profiles = profiles
+}
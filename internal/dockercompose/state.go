@@ -18,6 +18,17 @@ func (evt Event) IsStartupEvent() bool {
 	return evt.Action == ActionStart || evt.Action == ActionRestart || evt.Action == ActionUpdate
 }
 
+func (evt Event) IsHealthyEvent() bool {
+	return evt.Type == TypeContainer && evt.Action == ActionHealthStatusHealthy
+}
+
+func (evt Event) IsCrashEvent() bool {
+	if evt.Type != TypeContainer {
+		return false
+	}
+	return evt.Action == ActionDie || evt.Action == ActionOom
+}
+
 type State struct {
 	ContainerState types.ContainerState
 	ContainerID    container.ID
@@ -39,6 +50,14 @@ func (s State) RuntimeStatus() model.RuntimeStatus {
 	if s.ContainerState.Error != "" || s.ContainerState.ExitCode != 0 {
 		return model.RuntimeStatusError
 	}
+	if s.ContainerState.Health != nil {
+		switch s.ContainerState.Health.Status {
+		case types.Unhealthy:
+			return model.RuntimeStatusError
+		case types.Starting:
+			return model.RuntimeStatusPending
+		}
+	}
 	if s.ContainerState.Running ||
 		// Status strings taken from comments on:
 		// https://godoc.org/github.com/docker/docker/api/types#ContainerState
@@ -63,6 +82,9 @@ func (s State) RuntimeStatusError() error {
 	if s.ContainerState.ExitCode != 0 {
 		return fmt.Errorf("Container %s exited with %d", s.ContainerID, s.ContainerState.ExitCode)
 	}
+	if s.ContainerState.Health != nil && s.ContainerState.Health.Status == types.Unhealthy {
+		return fmt.Errorf("Container %s failed its healthcheck", s.ContainerID)
+	}
 	return fmt.Errorf("Container %s error status: %s", s.ContainerID, s.ContainerState.Status)
 }
 
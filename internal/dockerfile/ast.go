@@ -120,6 +120,38 @@ func (a AST) traverseImageRefs(visitor func(node *parser.Node, ref reference.Nam
 	})
 }
 
+// Find all the images that this dockerfile pulls from a registry,
+// skipping references to earlier build stages.
+func (a AST) BaseImages() ([]reference.Named, error) {
+	stages, _, _ := instructions.Parse(a.result.AST)
+	stageNames := make(map[string]bool)
+	for i, stage := range stages {
+		if stage.Name != "" {
+			stageNames[strings.ToLower(stage.Name)] = true
+		}
+		// COPY --from can refer to a stage by index.
+		stageNames[fmt.Sprintf("%d", i)] = true
+	}
+
+	seen := make(map[string]bool)
+	result := []reference.Named{}
+	err := a.traverseImageRefs(func(node *parser.Node, ref reference.Named) reference.Named {
+		name := container.FamiliarString(ref)
+		if stageNames[strings.ToLower(name)] || name == "scratch" {
+			return nil
+		}
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (a AST) InjectImageDigest(selector container.RefSelector, ref reference.NamedTagged) (bool, error) {
 	modified := false
 	err := a.traverseImageRefs(func(node *parser.Node, toReplace reference.Named) reference.Named {
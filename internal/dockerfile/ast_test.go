@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/windmilleng/tilt/internal/container"
 )
 
 func TestPrintBasicAST(t *testing.T) {
@@ -111,6 +113,65 @@ FROM golang:10
 	assertPrint(t, orig, expected)
 }
 
+func TestBaseImages(t *testing.T) {
+	assertBaseImages(t, `
+FROM golang:10
+RUN echo hi
+`, []string{"golang:10"})
+}
+
+func TestBaseImagesMultiStage(t *testing.T) {
+	assertBaseImages(t, `
+FROM golang:10 as builder
+RUN go build -o /app .
+
+FROM alpine:3.10
+COPY --from=builder /app /app
+`, []string{"golang:10", "alpine:3.10"})
+}
+
+func TestBaseImagesSkipsStageIndex(t *testing.T) {
+	assertBaseImages(t, `
+FROM golang:10
+RUN go build -o /app .
+
+FROM alpine:3.10
+COPY --from=0 /app /app
+`, []string{"golang:10", "alpine:3.10"})
+}
+
+func TestBaseImagesCopyFrom(t *testing.T) {
+	assertBaseImages(t, `
+FROM alpine:3.10
+COPY --from=gcr.io/windmill/helper:latest /app /app
+`, []string{"alpine:3.10", "gcr.io/windmill/helper:latest"})
+}
+
+func TestBaseImagesDedupes(t *testing.T) {
+	assertBaseImages(t, `
+FROM golang:10 as a
+FROM golang:10 as b
+`, []string{"golang:10"})
+}
+
+func assertBaseImages(t *testing.T, original string, expected []string) {
+	ast, err := ParseAST(Dockerfile(original))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs, err := ast.BaseImages()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := []string{}
+	for _, ref := range refs {
+		actual = append(actual, container.FamiliarString(ref))
+	}
+	assert.Equal(t, expected, actual)
+}
+
 // Convert the dockerfile into an AST, print it, and then
 // assert that the result is the same as the original.
 func assertPrintSame(t *testing.T, original string) {
@@ -0,0 +1,41 @@
+package dockerfile
+
+import (
+	"github.com/docker/distribution/reference"
+	"github.com/moby/buildkit/frontend/dockerfile/parser"
+
+	"github.com/windmilleng/tilt/internal/container"
+)
+
+// Rewrites Docker Hub base images (in FROM lines and COPY --from flags)
+// to pull through the given registry mirror, so rate-limited and
+// air-gapped environments work without editing every Dockerfile.
+func RewriteRegistryMirror(df Dockerfile, mirrorHost string) (Dockerfile, bool, error) {
+	ast, err := ParseAST(df)
+	if err != nil {
+		return "", false, err
+	}
+
+	modified := false
+	err = ast.traverseImageRefs(func(node *parser.Node, ref reference.Named) reference.Named {
+		newRef, replaced, err := container.ReplaceWithMirror(ref, mirrorHost)
+		if err != nil || !replaced {
+			return nil
+		}
+		modified = true
+		return newRef
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	if !modified {
+		return df, false, nil
+	}
+
+	newDf, err := ast.Print()
+	if err != nil {
+		return "", false, err
+	}
+	return newDf, true, nil
+}
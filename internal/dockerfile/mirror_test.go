@@ -0,0 +1,66 @@
+package dockerfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirrorTagged(t *testing.T) {
+	df := Dockerfile(`
+FROM golang:1.10
+ADD . .
+`)
+	newDf, modified, err := RewriteRegistryMirror(df, "mirror.example.com")
+	if assert.NoError(t, err) {
+		assert.True(t, modified)
+		assert.Equal(t, `
+FROM mirror.example.com/library/golang:1.10
+ADD . .
+`, string(newDf))
+	}
+}
+
+func TestMirrorUntagged(t *testing.T) {
+	df := Dockerfile(`
+FROM vandelay/common
+ADD . .
+`)
+	newDf, modified, err := RewriteRegistryMirror(df, "mirror.example.com")
+	if assert.NoError(t, err) {
+		assert.True(t, modified)
+		assert.Equal(t, `
+FROM mirror.example.com/vandelay/common
+ADD . .
+`, string(newDf))
+	}
+}
+
+func TestMirrorSkipsOtherRegistries(t *testing.T) {
+	df := Dockerfile(`
+FROM gcr.io/windmill/foo:v1
+ADD . .
+`)
+	newDf, modified, err := RewriteRegistryMirror(df, "mirror.example.com")
+	if assert.NoError(t, err) {
+		assert.False(t, modified)
+		assert.Equal(t, df, newDf)
+	}
+}
+
+func TestMirrorCopyFrom(t *testing.T) {
+	df := Dockerfile(`
+FROM gcr.io/windmill/foo:v1
+COPY --from=golang:1.10 /usr/local/go /usr/local/go
+ADD . .
+`)
+	newDf, modified, err := RewriteRegistryMirror(df, "mirror.example.com")
+	if assert.NoError(t, err) {
+		assert.True(t, modified)
+		assert.Equal(t, `
+FROM gcr.io/windmill/foo:v1
+COPY --from=mirror.example.com/library/golang:1.10 /usr/local/go /usr/local/go
+ADD . .
+`, string(newDf))
+	}
+}
@@ -10,7 +10,6 @@ import (
 
 	"github.com/windmilleng/tilt/internal/container"
 	"github.com/windmilleng/tilt/internal/engine/buildcontrol"
-	"github.com/windmilleng/tilt/internal/k8s"
 	"github.com/windmilleng/tilt/internal/store"
 	"github.com/windmilleng/tilt/pkg/logger"
 	"github.com/windmilleng/tilt/pkg/model"
@@ -118,18 +117,22 @@ func (composite *CompositeBuildAndDeployer) BuildAndDeploy(ctx context.Context,
 }
 
 func DefaultBuildOrder(lubad *LiveUpdateBuildAndDeployer, ibad *ImageBuildAndDeployer, dcbad *DockerComposeBuildAndDeployer,
-	ltbad *LocalTargetBuildAndDeployer, updMode buildcontrol.UpdateMode, env k8s.Env, runtime container.Runtime) BuildOrder {
+	ltbad *LocalTargetBuildAndDeployer, updMode buildcontrol.UpdateMode, sharesDocker buildcontrol.ClusterSharesDocker, runtime container.Runtime) BuildOrder {
 	if updMode == buildcontrol.UpdateModeImage || updMode == buildcontrol.UpdateModeNaive {
 		return BuildOrder{dcbad, ibad, ltbad}
 	}
 
-	if updMode == buildcontrol.UpdateModeSynclet || shouldUseSynclet(updMode, env, runtime) {
+	if updMode == buildcontrol.UpdateModeSynclet || shouldUseSynclet(updMode, sharesDocker, runtime) {
 		ibad.SetInjectSynclet(true)
 	}
 
+	if updMode == buildcontrol.UpdateModeCopySidecar {
+		ibad.SetInjectCopyHelper(true)
+	}
+
 	return BuildOrder{lubad, dcbad, ibad, ltbad}
 }
 
-func shouldUseSynclet(updMode buildcontrol.UpdateMode, env k8s.Env, runtime container.Runtime) bool {
-	return updMode == buildcontrol.UpdateModeAuto && !env.UsesLocalDockerRegistry() && runtime == container.RuntimeDocker
+func shouldUseSynclet(updMode buildcontrol.UpdateMode, sharesDocker buildcontrol.ClusterSharesDocker, runtime container.Runtime) bool {
+	return updMode == buildcontrol.UpdateModeAuto && !bool(sharesDocker) && runtime == container.RuntimeDocker
 }
@@ -63,6 +63,11 @@ func IsDontFallBackError(err error) bool {
 
 var _ error = DontFallBackError{}
 
+// Reported when we cancel an in-flight build because newer file changes
+// made it stale. Not a real failure: the next build restarts from the
+// latest state.
+var ErrBuildSuperseded = errors.New("build superseded by newer file changes")
+
 // A permanent error indicates that the whole build pipeline needs to stop.
 // It will never recover, even on subsequent rebuilds.
 func IsFatalError(err error) bool {
@@ -0,0 +1,82 @@
+package buildcontrol
+
+import (
+	"context"
+
+	"github.com/docker/docker/api/types"
+
+	"github.com/windmilleng/tilt/internal/container"
+	"github.com/windmilleng/tilt/internal/docker"
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/pkg/logger"
+)
+
+// Whether the cluster's container runtime shares an image store with the
+// docker daemon that Tilt builds into, so that built images are visible
+// in the cluster without a push.
+type ClusterSharesDocker bool
+
+// A type to bind to the --cluster-shares-docker flag, which forces the
+// no-push path for clusters we can't detect (e.g., a docker-desktop or
+// microk8s context with a custom name).
+type ClusterSharesDockerFlag bool
+
+func ProvideClusterSharesDocker(ctx context.Context, flag ClusterSharesDockerFlag, env k8s.Env, runtime container.Runtime, kCli k8s.Client, dCli docker.Client) ClusterSharesDocker {
+	if flag {
+		return true
+	}
+
+	if runtime != container.RuntimeDocker {
+		return false
+	}
+
+	if env.UsesLocalDockerRegistry() {
+		return true
+	}
+
+	// We only probe contexts we know nothing about. For envs we positively
+	// identified as remote (like GKE), probing would just waste a round-trip.
+	if env == k8s.EnvUnknown {
+		return ClusterSharesDocker(probeSharedDockerDaemon(ctx, kCli, dCli))
+	}
+
+	return false
+}
+
+// Detects a renamed docker-desktop/microk8s-style cluster by checking
+// whether any image the node reports in its status is also present in the
+// docker daemon we build into. Remote clusters won't share their system
+// images (kube-proxy et al.) with the local daemon, so an overlap is a
+// strong signal that the two are the same image store.
+func probeSharedDockerDaemon(ctx context.Context, kCli k8s.Client, dCli docker.Client) bool {
+	nodeImages, err := kCli.NodeImages(ctx)
+	if err != nil {
+		logger.Get(ctx).Debugf("Error fetching node images: %v", err)
+		return false
+	}
+
+	summaries, err := dCli.ImageList(ctx, types.ImageListOptions{})
+	if err != nil {
+		logger.Get(ctx).Debugf("Error listing docker images: %v", err)
+		return false
+	}
+
+	daemonImages := make(map[string]bool)
+	for _, s := range summaries {
+		for _, name := range s.RepoDigests {
+			daemonImages[name] = true
+		}
+		for _, name := range s.RepoTags {
+			daemonImages[name] = true
+		}
+	}
+
+	for _, name := range nodeImages {
+		if daemonImages[name] {
+			logger.Get(ctx).Infof("Detected that the cluster shares the local docker daemon; skipping image pushes")
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,60 @@
+package buildcontrol
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/windmilleng/tilt/internal/container"
+	"github.com/windmilleng/tilt/internal/docker"
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/testutils"
+)
+
+func TestClusterSharesDockerFlagOverride(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	kCli := k8s.NewFakeK8sClient()
+	dCli := docker.NewFakeClient()
+
+	sharesDocker := ProvideClusterSharesDocker(ctx, ClusterSharesDockerFlag(true), k8s.EnvGKE, container.RuntimeDocker, kCli, dCli)
+	assert.True(t, bool(sharesDocker))
+}
+
+func TestClusterSharesDockerWellKnownEnv(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	kCli := k8s.NewFakeK8sClient()
+	dCli := docker.NewFakeClient()
+
+	sharesDocker := ProvideClusterSharesDocker(ctx, ClusterSharesDockerFlag(false), k8s.EnvMinikube, container.RuntimeDocker, kCli, dCli)
+	assert.True(t, bool(sharesDocker))
+
+	sharesDocker = ProvideClusterSharesDocker(ctx, ClusterSharesDockerFlag(false), k8s.EnvGKE, container.RuntimeDocker, kCli, dCli)
+	assert.False(t, bool(sharesDocker))
+}
+
+func TestClusterSharesDockerProbe(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	kCli := k8s.NewFakeK8sClient()
+	kCli.FakeNodeImages = []string{"k8s.gcr.io/kube-proxy:v1.15.0"}
+	dCli := docker.NewFakeClient()
+	dCli.ImageListOutput = []types.ImageSummary{
+		{RepoTags: []string{"k8s.gcr.io/kube-proxy:v1.15.0"}},
+	}
+
+	sharesDocker := ProvideClusterSharesDocker(ctx, ClusterSharesDockerFlag(false), k8s.EnvUnknown, container.RuntimeDocker, kCli, dCli)
+	assert.True(t, bool(sharesDocker))
+}
+
+func TestClusterSharesDockerProbeNoOverlap(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	kCli := k8s.NewFakeK8sClient()
+	kCli.FakeNodeImages = []string{"k8s.gcr.io/kube-proxy:v1.15.0"}
+	dCli := docker.NewFakeClient()
+	dCli.ImageListOutput = []types.ImageSummary{
+		{RepoTags: []string{"gcr.io/some-project-162817/sancho:latest"}},
+	}
+
+	sharesDocker := ProvideClusterSharesDocker(ctx, ClusterSharesDockerFlag(false), k8s.EnvUnknown, container.RuntimeDocker, kCli, dCli)
+	assert.False(t, bool(sharesDocker))
+}
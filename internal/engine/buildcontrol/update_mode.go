@@ -4,7 +4,6 @@ import (
 	"fmt"
 
 	"github.com/windmilleng/tilt/internal/container"
-	"github.com/windmilleng/tilt/internal/k8s"
 )
 
 type UpdateMode string
@@ -31,6 +30,11 @@ var (
 
 	// Use `kubectl exec`
 	UpdateModeKubectlExec UpdateMode = "exec"
+
+	// Copy files via an injected helper sidecar that shares the app
+	// container's volumes. For distroless/scratch images without tar or a
+	// shell.
+	UpdateModeCopySidecar UpdateMode = "copy-sidecar"
 )
 
 var AllUpdateModes = []UpdateMode{
@@ -40,9 +44,10 @@ var AllUpdateModes = []UpdateMode{
 	UpdateModeSynclet,
 	UpdateModeContainer,
 	UpdateModeKubectlExec,
+	UpdateModeCopySidecar,
 }
 
-func ProvideUpdateMode(flag UpdateModeFlag, env k8s.Env, runtime container.Runtime) (UpdateMode, error) {
+func ProvideUpdateMode(flag UpdateModeFlag, sharesDocker ClusterSharesDocker, runtime container.Runtime) (UpdateMode, error) {
 	valid := false
 	for _, mode := range AllUpdateModes {
 		if mode == UpdateMode(flag) {
@@ -56,7 +61,7 @@ func ProvideUpdateMode(flag UpdateModeFlag, env k8s.Env, runtime container.Runti
 
 	mode := UpdateMode(flag)
 	if mode == UpdateModeContainer {
-		if !env.UsesLocalDockerRegistry() || runtime != container.RuntimeDocker {
+		if !sharesDocker || runtime != container.RuntimeDocker {
 			return "", fmt.Errorf("update mode %q is only valid with local Docker clusters like Docker For Mac, Minikube, and MicroK8s", flag)
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/windmilleng/tilt/internal/engine/buildcontrol"
@@ -18,6 +19,16 @@ type BuildController struct {
 	b                  BuildAndDeployer
 	buildsStartedCount int // used to synchronize with state
 	disabledForTesting bool
+
+	mu         sync.Mutex
+	inProgress map[model.ManifestName]*inProgressBuild
+}
+
+// Tracks an in-flight BuildAndDeploy call, so we can cancel it if its
+// inputs change before it finishes.
+type inProgressBuild struct {
+	cancel     context.CancelFunc
+	superseded bool
 }
 
 type buildEntry struct {
@@ -32,7 +43,8 @@ type buildEntry struct {
 
 func NewBuildController(b BuildAndDeployer) *BuildController {
 	return &BuildController{
-		b: b,
+		b:          b,
+		inProgress: make(map[model.ManifestName]*inProgressBuild),
 	}
 }
 
@@ -84,6 +96,9 @@ func (c *BuildController) OnChange(ctx context.Context, st store.RStore) {
 	if c.disabledForTesting {
 		return
 	}
+
+	c.cancelSupersededBuilds(st)
+
 	entry, ok := c.needsBuild(ctx, st)
 	if !ok {
 		return
@@ -97,22 +112,96 @@ func (c *BuildController) OnChange(ctx context.Context, st store.RStore) {
 		SpanID:       entry.spanID,
 	})
 
+	buildCtx, cancel := context.WithCancel(ctx)
+	c.mu.Lock()
+	c.inProgress[entry.name] = &inProgressBuild{cancel: cancel}
+	c.mu.Unlock()
+
 	go func() {
+		defer cancel()
+
 		// Send the logs to both the EngineState and the normal log stream.
 		actionWriter := BuildLogActionWriter{
 			store:        st,
 			manifestName: entry.name,
 			spanID:       entry.spanID,
 		}
-		ctx := logger.CtxWithLogHandler(ctx, actionWriter)
+		ctx := logger.CtxWithLogHandler(buildCtx, actionWriter)
 
 		c.logBuildEntry(ctx, entry)
 
 		result, err := c.buildAndDeploy(ctx, st, entry)
+		if c.finishBuild(entry.name) && err != nil {
+			// We canceled the build ourselves because its inputs went
+			// stale. Report it as superseded, not failed, so the restart
+			// doesn't look like an error and doesn't tear down Tilt the
+			// way a context.Canceled build does.
+			err = buildcontrol.ErrBuildSuperseded
+		}
 		st.Dispatch(buildcontrol.NewBuildCompleteAction(entry.name, entry.spanID, result, err))
 	}()
 }
 
+// Cancel in-flight builds whose files have changed since they started.
+// Once the canceled build completes, the next OnChange restarts it
+// against the latest state.
+func (c *BuildController) cancelSupersededBuilds(st store.RStore) {
+	state := st.RLockState()
+	var superseded []model.ManifestName
+	for name := range state.CurrentlyBuilding {
+		ms, ok := state.ManifestState(name)
+		if !ok || !ms.IsBuilding() {
+			continue
+		}
+		if hasFileChangesSince(ms, ms.CurrentBuild.StartTime) {
+			superseded = append(superseded, name)
+		}
+	}
+	st.RUnlockState()
+
+	for _, name := range superseded {
+		c.cancelBuild(name)
+	}
+}
+
+func (c *BuildController) cancelBuild(name model.ManifestName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	build, ok := c.inProgress[name]
+	if !ok || build.superseded {
+		return
+	}
+	build.superseded = true
+	build.cancel()
+}
+
+// Stop tracking a build that just finished. Reports whether we canceled
+// it because it was superseded.
+func (c *BuildController) finishBuild(name model.ManifestName) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	build, ok := c.inProgress[name]
+	if !ok {
+		return false
+	}
+	delete(c.inProgress, name)
+	return build.superseded
+}
+
+// Whether any watched file for this manifest has changed after the given time.
+func hasFileChangesSince(ms *store.ManifestState, t time.Time) bool {
+	for _, status := range ms.BuildStatuses {
+		for _, modTime := range status.PendingFileChanges {
+			if modTime.After(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (c *BuildController) buildAndDeploy(ctx context.Context, st store.RStore, entry buildEntry) (store.BuildResultSet, error) {
 	targets := entry.targets
 	for _, target := range targets {
@@ -232,7 +321,8 @@ func buildStateSet(ctx context.Context, manifest model.Manifest, specs []model.T
 
 	isLiveUpdateEligibleTrigger := reason.HasTrigger() &&
 		reason.Has(model.BuildReasonFlagChangedFiles) &&
-		manifest.TriggerMode != model.TriggerModeAuto
+		!manifest.TriggerMode.AutoOnChange() &&
+		!reason.Has(model.BuildReasonFlagForceFullBuild)
 	isImageBuildTrigger := reason.HasTrigger() && !isLiveUpdateEligibleTrigger
 	if isImageBuildTrigger {
 		for k, v := range result {
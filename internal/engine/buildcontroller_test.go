@@ -17,6 +17,7 @@ import (
 	"github.com/windmilleng/tilt/pkg/model"
 
 	"github.com/windmilleng/tilt/internal/container"
+	"github.com/windmilleng/tilt/internal/engine/buildcontrol"
 	"github.com/windmilleng/tilt/internal/hud/server"
 	"github.com/windmilleng/tilt/internal/k8s/testyaml"
 	"github.com/windmilleng/tilt/internal/store"
@@ -515,6 +516,7 @@ func TestBuildControllerImageBuildTrigger(t *testing.T) {
 		name               string
 		triggerMode        model.TriggerMode
 		filesChanged       bool
+		reason             model.BuildReason
 		expectedImageBuild bool
 	}{
 		{name: "manual including initial with change", triggerMode: model.TriggerModeManualIncludingInitial, filesChanged: true, expectedImageBuild: false},
@@ -522,6 +524,7 @@ func TestBuildControllerImageBuildTrigger(t *testing.T) {
 		{name: "manual including initial without change", triggerMode: model.TriggerModeManualIncludingInitial, filesChanged: false, expectedImageBuild: true},
 		{name: "manual after initial without change", triggerMode: model.TriggerModeManualAfterInitial, filesChanged: false, expectedImageBuild: true},
 		{name: "auto without change", triggerMode: model.TriggerModeAuto, filesChanged: false, expectedImageBuild: true},
+		{name: "manual with change, force full build", triggerMode: model.TriggerModeManualAfterInitial, filesChanged: true, reason: model.BuildReasonFlagTriggerCLI.With(model.BuildReasonFlagForceFullBuild), expectedImageBuild: true},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			f := newTestFixture(t)
@@ -550,7 +553,7 @@ func TestBuildControllerImageBuildTrigger(t *testing.T) {
 				f.assertNoCall("even tho there are pending changes, manual manifest shouldn't build w/o explicit trigger")
 			}
 
-			f.store.Dispatch(server.AppendToTriggerQueueAction{Name: mName})
+			f.store.Dispatch(server.AppendToTriggerQueueAction{Name: mName, Reason: tc.reason})
 			call := f.nextCallComplete()
 			state := call.oneState()
 			assert.Equal(t, expectedFiles, state.FilesChanged())
@@ -1059,22 +1062,19 @@ func TestBuildControllerWontBuildManifestThatsAlreadyBuilding(t *testing.T) {
 	f.editFileAndWaitForManifestBuilding("fe", "A.txt")
 	f.waitUntilNumBuildSlots(2)
 
-	// a second file change doesn't start a second build, b/c 'fe' is already building
+	// a second file change doesn't start a second simultaneous build for
+	// 'fe': it cancels the in-flight build, which comes back superseded
 	f.fsWatcher.Events <- watch.NewFileEvent(f.JoinPath("B.txt"))
-	f.waitUntilNumBuildSlots(2) // still two build slots available
-
-	// complete the first build
-	f.completeBuildForManifest(manifest)
-	call := f.nextCall("expect build from first pending file change (A.txt)")
+	call := f.nextCall("expect canceled build from first pending file change (A.txt)")
 	f.assertCallIsForManifestAndFiles(call, manifest, "A.txt")
 	f.waitForCompletedBuildCount(2)
 
-	// we freed up a build slot; expect the second build to start
+	// the restarted build picks up both pending changes
 	f.waitUntilManifestBuilding("fe")
 
 	f.completeBuildForManifest(manifest)
-	call = f.nextCall("expect build from second pending file change (B.txt)")
-	f.assertCallIsForManifestAndFiles(call, manifest, "B.txt")
+	call = f.nextCall("expect restarted build with both pending file changes")
+	f.assertCallIsForManifestAndFiles(call, manifest, "A.txt", "B.txt")
 	f.waitUntilManifestNotBuilding("fe")
 
 	err := f.Stop()
@@ -1140,14 +1140,14 @@ func TestCurrentlyBuildingMayExceedMaxParallelUpdates(t *testing.T) {
 	f.setMaxParallelUpdates(2)
 	f.waitUntilNumBuildSlots(0)
 
-	// another file change for manB -- will try to start another build as soon as possible
+	// another file change for manB -- cancels the in-flight manB build,
+	// which comes back superseded
 	f.fsWatcher.Events <- watch.NewFileEvent(f.JoinPath("b/other.go"))
 
-	f.completeBuildForManifest(manB)
-	call := f.nextCall("expect manB build complete")
+	call := f.nextCall("expect superseded manB build complete")
 	f.assertCallIsForManifestAndFiles(call, manB, "b/main.go")
 
-	// we should NOT see another build for manB, even though it has a pending file change,
+	// we should NOT see another build for manB, even though it has pending file changes,
 	// b/c we don't have enough slots (since we decreased maxParallelUpdates)
 	f.waitUntilNumBuildSlots(0)
 	f.waitUntilManifestNotBuilding("manB")
@@ -1157,12 +1157,12 @@ func TestCurrentlyBuildingMayExceedMaxParallelUpdates(t *testing.T) {
 	call = f.nextCall("expect manA build complete")
 	f.assertCallIsForManifestAndFiles(call, manA, "a/main.go")
 
-	// ...now that we have an available slots again, manB will rebuild
+	// ...now that we have an available slots again, manB will rebuild with all its changes
 	f.waitUntilManifestBuilding("manB")
 
 	f.completeBuildForManifest(manB)
 	call = f.nextCall("expect manB build complete (second build)")
-	f.assertCallIsForManifestAndFiles(call, manB, "b/other.go")
+	f.assertCallIsForManifestAndFiles(call, manB, "b/main.go", "b/other.go")
 
 	f.completeBuildForManifest(manC)
 	call = f.nextCall("expect manC build complete")
@@ -1258,6 +1258,43 @@ func TestErrorHandlingWithMultipleBuilds(t *testing.T) {
 	f.assertAllBuildsConsumed()
 }
 
+func TestBuildControllerCancelsSupersededBuild(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.TearDown()
+	f.b.completeBuildsManually = true
+
+	manifest := f.newManifest("fe")
+	f.Start([]model.Manifest{manifest})
+	f.completeAndCheckBuildsForManifests(manifest)
+
+	f.editFileAndWaitForManifestBuilding("fe", "A.txt")
+
+	// A change to a watched file makes the in-flight build stale, so we
+	// cancel it rather than finish a build we're about to throw away.
+	f.fsWatcher.Events <- watch.NewFileEvent(f.JoinPath("B.txt"))
+	call := f.nextCall("expect superseded build")
+	f.assertCallIsForManifestAndFiles(call, manifest, "A.txt")
+
+	f.WaitUntilManifestState("build recorded as superseded", "fe", func(ms store.ManifestState) bool {
+		return ms.LastBuild().Error == buildcontrol.ErrBuildSuperseded
+	})
+	f.withState(func(st store.EngineState) {
+		assert.Contains(t, st.LogStore.String(), "Build canceled: newer file changes detected")
+		assert.NotContains(t, st.LogStore.String(), "Build Failed")
+	})
+
+	// The restarted build sees both changes.
+	f.waitUntilManifestBuilding("fe")
+	f.completeBuildForManifest(manifest)
+	call = f.nextCall("expect restarted build with the latest state")
+	f.assertCallIsForManifestAndFiles(call, manifest, "A.txt", "B.txt")
+	f.waitUntilManifestNotBuilding("fe")
+
+	err := f.Stop()
+	assert.NoError(t, err)
+	f.assertAllBuildsConsumed()
+}
+
 func (f *testFixture) waitUntilManifestBuilding(name model.ManifestName) {
 	msg := fmt.Sprintf("manifest %q is building", name)
 	f.WaitUntilManifestState(msg, name, func(ms store.ManifestState) bool {
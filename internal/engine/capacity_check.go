@@ -0,0 +1,134 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/pkg/logger"
+)
+
+// The CPU/memory an entity's pods ask for, scaled by its replica count.
+type entityRequests struct {
+	name   string
+	cpu    resource.Quantity
+	memory resource.Quantity
+}
+
+// Compares the CPU/memory requests of the entities we're about to deploy
+// against the namespace's remaining quota and the cluster's allocatable
+// capacity. Over-committed deploys still go ahead -- the scheduler has the
+// final say -- but the user gets a breakdown up front instead of pods
+// hanging Pending with no explanation in the HUD.
+func reportClusterCapacity(ctx context.Context, kCli k8s.Client, entities []k8s.K8sEntity) {
+	byNamespace := map[k8s.Namespace][]entityRequests{}
+	for _, e := range entities {
+		req, ok := requestsForEntity(e)
+		if !ok {
+			continue
+		}
+		byNamespace[e.Namespace()] = append(byNamespace[e.Namespace()], req)
+	}
+
+	l := logger.Get(ctx)
+	for ns, reqs := range byNamespace {
+		totalCPU := resource.Quantity{}
+		totalMemory := resource.Quantity{}
+		for _, r := range reqs {
+			totalCPU.Add(r.cpu)
+			totalMemory.Add(r.memory)
+		}
+
+		capacity, err := kCli.ClusterCapacity(ctx, ns)
+		if err != nil {
+			// Capacity info is best-effort; the user might not have
+			// permission to read quotas or nodes.
+			l.Debugf("Cluster capacity check: %v", err)
+			continue
+		}
+
+		var problems []string
+		if capacity.QuotaCPU != nil && totalCPU.Cmp(*capacity.QuotaCPU) > 0 {
+			problems = append(problems, fmt.Sprintf(
+				"CPU: requested %s, but namespace %q only has %s of request quota left",
+				totalCPU.String(), ns, capacity.QuotaCPU.String()))
+		}
+		if capacity.QuotaMemory != nil && totalMemory.Cmp(*capacity.QuotaMemory) > 0 {
+			problems = append(problems, fmt.Sprintf(
+				"Memory: requested %s, but namespace %q only has %s of request quota left",
+				totalMemory.String(), ns, capacity.QuotaMemory.String()))
+		}
+		if !capacity.AllocatableCPU.IsZero() && totalCPU.Cmp(capacity.AllocatableCPU) > 0 {
+			problems = append(problems, fmt.Sprintf(
+				"CPU: requested %s, but the cluster only has %s allocatable in total",
+				totalCPU.String(), capacity.AllocatableCPU.String()))
+		}
+		if !capacity.AllocatableMemory.IsZero() && totalMemory.Cmp(capacity.AllocatableMemory) > 0 {
+			problems = append(problems, fmt.Sprintf(
+				"Memory: requested %s, but the cluster only has %s allocatable in total",
+				totalMemory.String(), capacity.AllocatableMemory.String()))
+		}
+		if len(problems) == 0 {
+			continue
+		}
+
+		l.Warnf("WARNING: this deploy may not fit in the cluster. Pods that don't fit will hang Pending.")
+		for _, p := range problems {
+			l.Warnf("  %s", p)
+		}
+		l.Infof("Requested by workload:")
+		for _, r := range reqs {
+			l.Infof("  %s: cpu %s, memory %s", r.name, r.cpu.String(), r.memory.String())
+		}
+	}
+}
+
+func requestsForEntity(e k8s.K8sEntity) (entityRequests, bool) {
+	podSpecs, err := k8s.ExtractPods(e.Obj)
+	if err != nil || len(podSpecs) == 0 {
+		return entityRequests{}, false
+	}
+
+	req := entityRequests{name: fmt.Sprintf("%s %s", e.GVK().Kind, e.Name())}
+	replicas := int64(workloadReplicas(e))
+	for _, spec := range podSpecs {
+		for _, c := range spec.Containers {
+			if cpu, ok := c.Resources.Requests[v1.ResourceCPU]; ok {
+				req.cpu.Add(*resource.NewMilliQuantity(cpu.MilliValue()*replicas, cpu.Format))
+			}
+			if mem, ok := c.Resources.Requests[v1.ResourceMemory]; ok {
+				req.memory.Add(*resource.NewQuantity(mem.Value()*replicas, mem.Format))
+			}
+		}
+	}
+	if req.cpu.IsZero() && req.memory.IsZero() {
+		return entityRequests{}, false
+	}
+	return req, true
+}
+
+// The replica count declared on the workload's spec, defaulting to 1 when
+// the kind has no replicas field (or leaves it unset).
+func workloadReplicas(e k8s.K8sEntity) int32 {
+	obj := reflect.Indirect(reflect.ValueOf(e.Obj))
+	if obj.Kind() != reflect.Struct {
+		return 1
+	}
+	spec := obj.FieldByName("Spec")
+	if !spec.IsValid() || spec.Kind() != reflect.Struct {
+		return 1
+	}
+	field := spec.FieldByName("Replicas")
+	if !field.IsValid() {
+		return 1
+	}
+	replicas, ok := field.Interface().(*int32)
+	if !ok || replicas == nil || *replicas <= 0 {
+		return 1
+	}
+	return *replicas
+}
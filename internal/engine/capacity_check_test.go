@@ -0,0 +1,78 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/pkg/logger"
+)
+
+const capacityTestYAML = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: hungry
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: hungry
+        image: gcr.io/hungry
+        resources:
+          requests:
+            cpu: "2"
+            memory: 1Gi
+`
+
+func TestRequestsForEntity(t *testing.T) {
+	entities, err := k8s.ParseYAMLFromString(capacityTestYAML)
+	require.NoError(t, err)
+	require.Len(t, entities, 1)
+
+	req, ok := requestsForEntity(entities[0])
+	require.True(t, ok)
+	assert.Equal(t, "Deployment hungry", req.name)
+	assert.Equal(t, "6", req.cpu.String())
+	assert.Equal(t, "3Gi", req.memory.String())
+}
+
+func TestReportClusterCapacityOverQuota(t *testing.T) {
+	kCli := k8s.NewFakeK8sClient()
+	quotaCPU := resource.MustParse("4")
+	kCli.FakeClusterCapacity = k8s.ClusterCapacity{QuotaCPU: &quotaCPU}
+
+	entities, err := k8s.ParseYAMLFromString(capacityTestYAML)
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	ctx := logger.WithLogger(context.Background(), logger.NewLogger(logger.DebugLvl, out))
+	reportClusterCapacity(ctx, kCli, entities)
+
+	assert.Contains(t, out.String(), "may not fit in the cluster")
+	assert.Contains(t, out.String(), `CPU: requested 6, but namespace "default" only has 4 of request quota left`)
+	assert.Contains(t, out.String(), "Deployment hungry: cpu 6, memory 3Gi")
+}
+
+func TestReportClusterCapacityFits(t *testing.T) {
+	kCli := k8s.NewFakeK8sClient()
+	kCli.FakeClusterCapacity = k8s.ClusterCapacity{
+		AllocatableCPU:    resource.MustParse("8"),
+		AllocatableMemory: resource.MustParse("16Gi"),
+	}
+
+	entities, err := k8s.ParseYAMLFromString(capacityTestYAML)
+	require.NoError(t, err)
+
+	out := &bytes.Buffer{}
+	ctx := logger.WithLogger(context.Background(), logger.NewLogger(logger.DebugLvl, out))
+	reportClusterCapacity(ctx, kCli, entities)
+
+	assert.Empty(t, out.String())
+}
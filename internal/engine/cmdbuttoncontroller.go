@@ -0,0 +1,184 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/windmilleng/tilt/internal/container"
+	"github.com/windmilleng/tilt/internal/docker"
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/logger"
+	"github.com/windmilleng/tilt/pkg/model"
+	"github.com/windmilleng/tilt/pkg/model/logstore"
+)
+
+// Runs cmd_button commands when the user presses a button, streaming
+// output to the resource's log. Presses arrive on the engine state's
+// CmdButtonQueue (via CommandButtonAction); we pop them off by
+// dispatching CmdButtonDispatchedAction once the command has started.
+type CmdButtonController struct {
+	kClient k8s.Client
+	dClient docker.LocalClient
+
+	// Presses we've already started, so a slow store round-trip doesn't
+	// run the same press twice.
+	started map[store.CmdButtonPress]bool
+}
+
+func NewCmdButtonController(kClient k8s.Client, dClient docker.LocalClient) *CmdButtonController {
+	return &CmdButtonController{
+		kClient: kClient,
+		dClient: dClient,
+		started: make(map[store.CmdButtonPress]bool),
+	}
+}
+
+// Removes a press from the queue once its command has been started.
+type CmdButtonDispatchedAction struct {
+	Press store.CmdButtonPress
+}
+
+func (CmdButtonDispatchedAction) Action() {}
+
+// Everything a button run needs from the engine state, captured under
+// the state lock so the goroutine doesn't touch the store.
+type cmdButtonRun struct {
+	press  store.CmdButtonPress
+	button model.CmdButton
+
+	// for local commands
+	workdir string
+
+	// for in-container commands
+	isDC        bool
+	containerID container.ID
+	podID       k8s.PodID
+	namespace   k8s.Namespace
+	cName       container.Name
+}
+
+func (c *CmdButtonController) OnChange(ctx context.Context, st store.RStore) {
+	runs := c.diff(st)
+	for _, run := range runs {
+		st.Dispatch(CmdButtonDispatchedAction{Press: run.press})
+		go c.run(ctx, st, run)
+	}
+}
+
+func (c *CmdButtonController) diff(st store.RStore) []cmdButtonRun {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	inQueue := make(map[store.CmdButtonPress]bool, len(state.CmdButtonQueue))
+	var runs []cmdButtonRun
+	for _, press := range state.CmdButtonQueue {
+		inQueue[press] = true
+		if c.started[press] {
+			continue
+		}
+
+		mt, ok := state.ManifestTargets[press.ManifestName]
+		if !ok {
+			continue
+		}
+
+		var button model.CmdButton
+		found := false
+		for _, b := range mt.Manifest.Buttons {
+			if b.Name == press.ButtonName {
+				button = b
+				found = true
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+
+		run := cmdButtonRun{
+			press:   press,
+			button:  button,
+			workdir: filepath.Dir(state.TiltfilePath),
+			isDC:    mt.Manifest.IsDC(),
+		}
+		if button.InContainer {
+			if run.isDC {
+				run.containerID = mt.State.DCRuntimeState().ContainerID
+			} else {
+				pod := mt.State.MostRecentPod()
+				run.podID = pod.PodID
+				run.namespace = pod.Namespace
+				if len(pod.Containers) > 0 {
+					run.cName = pod.Containers[0].Name
+				}
+			}
+		}
+
+		c.started[press] = true
+		runs = append(runs, run)
+	}
+
+	// Forget presses that have left the queue, so the map doesn't grow
+	// for the life of the process.
+	for press := range c.started {
+		if !inQueue[press] {
+			delete(c.started, press)
+		}
+	}
+
+	return runs
+}
+
+func (c *CmdButtonController) run(ctx context.Context, st store.RStore, run cmdButtonRun) {
+	mn := run.press.ManifestName
+	spanID := logstore.SpanID(fmt.Sprintf("cmdbutton:%s:%s:%d", mn, run.button.Name, run.press.Time.UnixNano()))
+	out := cmdButtonLogWriter{st: st, mn: mn, spanID: spanID}
+
+	fmt.Fprintf(out, "Running button %q: %s\n", run.button.Name, run.button.Cmd.String())
+
+	err := c.exec(ctx, run, out)
+	if err != nil {
+		fmt.Fprintf(out, "Button %q failed: %v\n", run.button.Name, err)
+		return
+	}
+	fmt.Fprintf(out, "Button %q done\n", run.button.Name)
+}
+
+func (c *CmdButtonController) exec(ctx context.Context, run cmdButtonRun, out cmdButtonLogWriter) error {
+	argv := run.button.Cmd.Argv
+	if !run.button.InContainer {
+		cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+		cmd.Dir = run.workdir
+		cmd.Stdout = out
+		cmd.Stderr = out
+		return cmd.Run()
+	}
+
+	if run.isDC {
+		if run.containerID == "" {
+			return fmt.Errorf("no running container")
+		}
+		return c.dClient.ExecInContainer(ctx, run.containerID, run.button.Cmd, out)
+	}
+
+	if run.podID == "" || run.cName == "" {
+		return fmt.Errorf("no running pod")
+	}
+	return c.kClient.Exec(ctx, run.podID, run.cName, run.namespace, argv, nil, out, out)
+}
+
+var _ store.Subscriber = &CmdButtonController{}
+
+type cmdButtonLogWriter struct {
+	st     store.RStore
+	mn     model.ManifestName
+	spanID logstore.SpanID
+}
+
+func (w cmdButtonLogWriter) Write(p []byte) (n int, err error) {
+	w.st.Dispatch(store.NewLogAction(w.mn, w.spanID, logger.InfoLvl, nil, append([]byte{}, p...)))
+	return len(p), nil
+}
@@ -0,0 +1,62 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/internal/docker"
+	"github.com/windmilleng/tilt/internal/dockercompose"
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestCmdButtonInContainerDC(t *testing.T) {
+	st := store.NewTestingStore()
+	kCli := k8s.NewFakeK8sClient()
+	dCli := docker.NewFakeClient()
+	c := NewCmdButtonController(kCli, dCli)
+
+	m := model.Manifest{
+		Name: "db",
+		Buttons: []model.CmdButton{
+			{Name: "flush", Cmd: model.ToUnixCmd("redis-cli flushall"), InContainer: true},
+		},
+	}.WithDeployTarget(model.DockerComposeTarget{ConfigPaths: []string{"docker-compose.yml"}})
+
+	press := store.CmdButtonPress{ManifestName: "db", ButtonName: "flush", Time: time.Now()}
+
+	state := st.LockMutableStateForTesting()
+	state.UpsertManifestTarget(store.NewManifestTarget(m))
+	state.ManifestTargets["db"].State.RuntimeState = dockercompose.State{ContainerID: "cid"}
+	state.CmdButtonQueue = []store.CmdButtonPress{press}
+	st.UnlockMutableState()
+
+	c.OnChange(context.Background(), st)
+
+	// The command runs on a goroutine; wait for it to hit the fake client.
+	deadline := time.Now().Add(time.Second)
+	for len(dCli.ExecCalls) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	require.Len(t, dCli.ExecCalls, 1)
+	assert.Equal(t, "cid", dCli.ExecCalls[0].Container)
+	assert.Equal(t, model.ToUnixCmd("redis-cli flushall"), dCli.ExecCalls[0].Cmd)
+
+	dispatched := false
+	for _, a := range st.Actions() {
+		if da, ok := a.(CmdButtonDispatchedAction); ok && da.Press == press {
+			dispatched = true
+		}
+	}
+	assert.True(t, dispatched, "expected the press to be popped off the queue")
+
+	// A second OnChange with the same queue shouldn't re-run the command.
+	c.OnChange(context.Background(), st)
+	time.Sleep(10 * time.Millisecond)
+	assert.Len(t, dCli.ExecCalls, 1)
+}
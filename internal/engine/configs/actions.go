@@ -33,6 +33,10 @@ type ConfigsReloadedAction struct {
 	AnalyticsTiltfileOpt analytics.Opt
 	VersionSettings      model.VersionSettings
 	UpdateSettings       model.UpdateSettings
+	NotifySettings       model.NotifySettings
+	SecuritySettings     model.SecuritySettings
+	SigningSettings      model.SigningSettings
+	PrePullImages        []string
 
 	// A checkpoint into the logstore when Tiltfile execution started.
 	// Useful for knowing how far back in time we have to scrub secrets.
@@ -3,9 +3,11 @@ package configs
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
+	apitrace "go.opentelemetry.io/otel/api/trace"
 
 	"github.com/windmilleng/tilt/internal/docker"
 	"github.com/windmilleng/tilt/internal/ospath"
@@ -15,19 +17,38 @@ import (
 	"github.com/windmilleng/tilt/pkg/model"
 )
 
+// How long to wait after a config file changes before re-executing the
+// Tiltfile. One "save all" in an editor delivers a burst of watch events,
+// and we'd rather run once against the settled state than start on the
+// first event and throw that run away.
+const configDebounceDelay = 200 * time.Millisecond
+
+// Don't let a steady stream of config changes postpone the reload forever.
+const configDebounceMax = 2 * time.Second
+
 type ConfigsController struct {
 	disabledForTesting bool
 	tfl                tiltfile.TiltfileLoader
 	dockerClient       docker.Client
+	tracer             apitrace.Tracer
 	clock              func() time.Time
-	loadCount          int
+	debounceDelay      time.Duration
+
+	mu             sync.Mutex
+	loadCount      int
+	loadInProgress bool
+	loadStartTime  time.Time
+	cancelLoad     context.CancelFunc
+	loadSuperseded bool
 }
 
-func NewConfigsController(tfl tiltfile.TiltfileLoader, dockerClient docker.Client) *ConfigsController {
+func NewConfigsController(tfl tiltfile.TiltfileLoader, dockerClient docker.Client, tracer apitrace.Tracer) *ConfigsController {
 	return &ConfigsController{
-		tfl:          tfl,
-		dockerClient: dockerClient,
-		clock:        time.Now,
+		tfl:           tfl,
+		dockerClient:  dockerClient,
+		tracer:        tracer,
+		clock:         time.Now,
+		debounceDelay: configDebounceDelay,
 	}
 }
 
@@ -39,6 +60,10 @@ func (cc *ConfigsController) DisableForTesting(disabled bool) {
 	cc.disabledForTesting = disabled
 }
 
+func (cc *ConfigsController) SetDebounceDelayForTesting(d time.Duration) {
+	cc.debounceDelay = d
+}
+
 // Modeled after BuildController.nextTargetToBuild. Check to see that:
 // 1) There's currently no Tiltfile build running,
 // 2) There are pending file changes, and
@@ -81,9 +106,29 @@ func logTiltfileChanges(ctx context.Context, filesChanged map[string]bool) {
 }
 
 func (cc *ConfigsController) loadTiltfile(ctx context.Context, st store.RStore,
-	filesChanged map[string]bool, argsChanged bool, tiltfilePath string, loadCount int) {
+	tiltfilePath string, loadCount int) {
+
+	defer cc.endLoad()
+
+	// Wait for the config file changes to settle before executing.
+	cc.waitForConfigQuietPeriod(ctx, st)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
 	startTime := cc.clock()
+
+	state := st.RLockState()
+	filesChanged := make(map[string]bool)
+	for k := range state.PendingConfigFileChanges {
+		filesChanged[k] = true
+	}
+	argsChanged := state.UserConfigState.ArgsChangeTime.After(state.TiltfileState.LastBuild().StartTime)
+	checkpointAtExecStart := state.LogStore.Checkpoint()
+	firstBuild := !state.TiltfileState.StartedFirstBuild()
+	userConfigState := state.UserConfigState
+	st.RUnlockState()
+
 	st.Dispatch(ConfigsReloadStartedAction{
 		FilesChanged: filesChanged,
 		StartTime:    startTime,
@@ -93,20 +138,24 @@ func (cc *ConfigsController) loadTiltfile(ctx context.Context, st store.RStore,
 	actionWriter := NewTiltfileLogWriter(st, loadCount)
 	ctx = logger.CtxWithLogHandler(ctx, actionWriter)
 
-	state := st.RLockState()
-	checkpointAtExecStart := state.LogStore.Checkpoint()
-	firstBuild := !state.TiltfileState.StartedFirstBuild()
 	if !firstBuild {
 		logTiltfileChanges(ctx, filesChanged)
 	}
-	userConfigState := state.UserConfigState
 	if argsChanged {
 		logger.Get(ctx).Infof("Tiltfile args changed to: %v", userConfigState.Args)
 	}
-	st.RUnlockState()
 
+	// Make the execution cancelable, so config changes that arrive
+	// mid-run can abandon it and restart from the latest state.
+	cc.setCancelLoad(cancel, startTime)
+
+	ctx, span := cc.tracer.Start(ctx, "tiltfile:exec")
 	tlr := cc.tfl.Load(ctx, tiltfilePath, userConfigState)
-	if tlr.Error == nil && len(tlr.Manifests) == 0 {
+	span.End()
+
+	if cc.clearCancelLoad() && ctx.Err() != nil {
+		tlr.Error = fmt.Errorf("Tiltfile run canceled: config files changed mid-run")
+	} else if tlr.Error == nil && len(tlr.Manifests) == 0 {
 		tlr.Error = fmt.Errorf("No resources found. Check out https://docs.tilt.dev/tutorial.html to get started!")
 	}
 
@@ -140,6 +189,10 @@ func (cc *ConfigsController) loadTiltfile(ctx context.Context, st store.RStore,
 		CheckpointAtExecStart: checkpointAtExecStart,
 		VersionSettings:       tlr.VersionSettings,
 		UpdateSettings:        tlr.UpdateSettings,
+		NotifySettings:        tlr.NotifySettings,
+		SecuritySettings:      tlr.SecuritySettings,
+		SigningSettings:       tlr.SigningSettings,
+		PrePullImages:         tlr.PrePullImages,
 	})
 }
 
@@ -148,6 +201,8 @@ func (cc *ConfigsController) OnChange(ctx context.Context, st store.RStore) {
 		return
 	}
 
+	cc.cancelSupersededLoad(st)
+
 	state := st.RLockState()
 	defer st.RUnlockState()
 
@@ -155,24 +210,115 @@ func (cc *ConfigsController) OnChange(ctx context.Context, st store.RStore) {
 		return
 	}
 
-	filesChanged := make(map[string]bool)
-	for k := range state.PendingConfigFileChanges {
-		filesChanged[k] = true
-	}
-
-	argsChanged := state.UserConfigState.ArgsChangeTime.After(state.TiltfileState.LastBuild().StartTime)
-
 	tiltfilePath, err := state.RelativeTiltfilePath()
 	if err != nil {
 		st.Dispatch(store.NewErrorAction(err))
 		return
 	}
 
+	loadCount, ok := cc.beginLoad()
+	if !ok {
+		// A reload is already running or waiting out the debounce window.
+		return
+	}
+
 	// Release the state lock and load the tiltfile in a separate goroutine
+	go cc.loadTiltfile(ctx, st, tiltfilePath, loadCount)
+}
+
+// Cancel the in-flight Tiltfile run if config files have changed since it
+// started; the results would be stale before we even applied them.
+func (cc *ConfigsController) cancelSupersededLoad(st store.RStore) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.cancelLoad == nil {
+		return
+	}
+
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	for _, t := range state.PendingConfigFileChanges {
+		if t.After(cc.loadStartTime) {
+			cc.loadSuperseded = true
+			cc.cancelLoad()
+			cc.cancelLoad = nil
+			return
+		}
+	}
+}
+
+func (cc *ConfigsController) beginLoad() (int, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.loadInProgress {
+		return 0, false
+	}
+	cc.loadInProgress = true
+	cc.loadSuperseded = false
 	cc.loadCount++
+	return cc.loadCount, true
+}
+
+func (cc *ConfigsController) endLoad() {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.loadInProgress = false
+	cc.cancelLoad = nil
+}
+
+func (cc *ConfigsController) setCancelLoad(cancel context.CancelFunc, startTime time.Time) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.cancelLoad = cancel
+	cc.loadStartTime = startTime
+	cc.loadSuperseded = false
+}
+
+// Reports whether the run was canceled because it was superseded.
+func (cc *ConfigsController) clearCancelLoad() bool {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.cancelLoad = nil
+	return cc.loadSuperseded
+}
+
+// Block until no config file has changed for debounceDelay (or until
+// we've waited configDebounceMax total), so a burst of file changes
+// turns into one reload.
+func (cc *ConfigsController) waitForConfigQuietPeriod(ctx context.Context, st store.RStore) {
+	if cc.debounceDelay == 0 {
+		return
+	}
+
+	deadline := time.Now().Add(configDebounceMax)
+	for {
+		state := st.RLockState()
+		var latest time.Time
+		for _, t := range state.PendingConfigFileChanges {
+			if t.After(latest) {
+				latest = t
+			}
+		}
+		st.RUnlockState()
+
+		now := time.Now()
+		quietAt := latest.Add(cc.debounceDelay)
+		if latest.IsZero() || !quietAt.After(now) || now.After(deadline) {
+			return
+		}
+		if quietAt.After(deadline) {
+			quietAt = deadline
+		}
 
-	loadCount := cc.loadCount
-	go cc.loadTiltfile(ctx, st, filesChanged, argsChanged, tiltfilePath, loadCount)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(quietAt.Sub(now)):
+		}
+	}
 }
 
 func requiresDocker(tlr tiltfile.TiltfileLoadResult) bool {
@@ -181,6 +327,11 @@ func requiresDocker(tlr tiltfile.TiltfileLoadResult) bool {
 	}
 
 	for _, m := range tlr.Manifests {
+		// Mixed-mode sessions report OrchestratorK8s, but compose services
+		// still run against the local Docker daemon.
+		if m.IsDC() {
+			return true
+		}
 		for _, iTarget := range m.ImageTargets {
 			if iTarget.IsDockerBuild() {
 				return true
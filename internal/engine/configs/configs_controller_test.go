@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	apitrace "go.opentelemetry.io/otel/api/trace"
 
 	"github.com/windmilleng/tilt/internal/container"
 	"github.com/windmilleng/tilt/internal/docker"
@@ -74,6 +75,46 @@ func TestConfigsControllerDockerNotConnectedButNotRequired(t *testing.T) {
 	assert.NoError(t, a.Err)
 }
 
+func TestConfigsControllerDebounce(t *testing.T) {
+	f := newCCFixture(t)
+	defer f.TearDown()
+
+	f.cc.debounceDelay = 50 * time.Millisecond
+
+	f.addManifest("fe")
+	bar := manifestbuilder.New(f, "bar").WithK8sYAML(testyaml.SanchoYAML).Build()
+	f.tfl.Result = tiltfile.TiltfileLoadResult{
+		Manifests: []model.Manifest{bar},
+	}
+
+	f.cc.OnChange(f.ctx, f.st)
+
+	// A second config change lands while we're still waiting out the
+	// debounce window; it should fold into the same reload.
+	state := f.st.LockMutableStateForTesting()
+	state.PendingConfigFileChanges["Tiltfile.extra"] = time.Now()
+	f.st.UnlockMutableState()
+	f.cc.OnChange(f.ctx, f.st)
+
+	a := store.WaitForAction(f.T(), reflect.TypeOf(ConfigsReloadStartedAction{}), f.st.Actions)
+	cra, ok := a.(ConfigsReloadStartedAction)
+	if !ok {
+		f.T().Fatalf("didn't get an action of type %T", ConfigsReloadStartedAction{})
+	}
+	assert.True(t, cra.FilesChanged["Tiltfile"])
+	assert.True(t, cra.FilesChanged["Tiltfile.extra"])
+
+	store.WaitForAction(f.T(), reflect.TypeOf(ConfigsReloadedAction{}), f.st.Actions)
+
+	startedCount := 0
+	for _, a := range f.st.Actions() {
+		if _, ok := a.(ConfigsReloadStartedAction); ok {
+			startedCount++
+		}
+	}
+	assert.Equal(t, 1, startedCount)
+}
+
 type ccFixture struct {
 	*tempdir.TempDirFixture
 	ctx    context.Context
@@ -90,7 +131,8 @@ func newCCFixture(t *testing.T) *ccFixture {
 	st := store.NewTestingStore()
 	tfl := tiltfile.NewFakeTiltfileLoader()
 	d := docker.NewFakeClient()
-	cc := NewConfigsController(tfl, d)
+	cc := NewConfigsController(tfl, d, apitrace.NoopTracer{})
+	cc.debounceDelay = 0
 	fc := testutils.NewRandomFakeClock()
 	cc.clock = fc.Clock()
 	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
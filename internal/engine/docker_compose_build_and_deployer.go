@@ -27,7 +27,10 @@ type DockerComposeBuildAndDeployer struct {
 
 var _ BuildAndDeployer = &DockerComposeBuildAndDeployer{}
 
-func NewDockerComposeBuildAndDeployer(dcc dockercompose.DockerComposeClient, dc docker.Client,
+// Takes the local docker client: compose containers always run against the
+// local daemon, even when a mixed-mode session deploys other resources to a
+// cluster with its own daemon.
+func NewDockerComposeBuildAndDeployer(dcc dockercompose.DockerComposeClient, dc docker.LocalClient,
 	icb *imageAndCacheBuilder, c build.Clock) *DockerComposeBuildAndDeployer {
 	return &DockerComposeBuildAndDeployer{
 		dcc:   dcc,
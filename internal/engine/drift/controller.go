@@ -0,0 +1,148 @@
+// Package drift watches for out-of-band changes to the objects Tilt has
+// deployed, so that kubectl edits by teammates or operators don't
+// silently diverge from the Tiltfile. Drifted resources are surfaced in
+// the UIs; re-applying is an ordinary triggered update.
+package drift
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/windmilleng/tilt/internal/build"
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/sliceutils"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/logger"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// How often we compare the live cluster objects against what we applied.
+const checkPeriod = 30 * time.Second
+
+type DriftAction struct {
+	ManifestName model.ManifestName
+
+	// Display names of the objects that no longer match our last apply.
+	// Empty means the resource is back in sync.
+	DriftedObjects []string
+}
+
+func (DriftAction) Action() {}
+
+type Controller struct {
+	kCli      k8s.Client
+	clock     build.Clock
+	lastCheck time.Time
+}
+
+func NewController(kCli k8s.Client, clock build.Clock) *Controller {
+	return &Controller{
+		kCli:  kCli,
+		clock: clock,
+	}
+}
+
+type checkTarget struct {
+	name    model.ManifestName
+	objects []store.DeployedObject
+
+	// What the manifest currently reports, so we only dispatch on change.
+	drifted []string
+}
+
+func (c *Controller) OnChange(ctx context.Context, st store.RStore) {
+	if !c.lastCheck.Add(checkPeriod).Before(c.clock.Now()) {
+		return
+	}
+	c.lastCheck = c.clock.Now()
+
+	targets := c.diff(st)
+
+	for _, target := range targets {
+		drifted := c.driftedObjects(ctx, target.objects)
+		if sliceutils.StringSliceEquals(drifted, target.drifted) {
+			continue
+		}
+		if len(drifted) > 0 {
+			logger.Get(ctx).Infof(
+				"Detected out-of-band changes to %s: %s. Trigger an update to re-apply.",
+				target.name, strings.Join(drifted, ", "))
+		}
+		st.Dispatch(DriftAction{ManifestName: target.name, DriftedObjects: drifted})
+	}
+}
+
+func (c *Controller) diff(st store.RStore) []checkTarget {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	var targets []checkTarget
+	for _, mt := range state.Targets() {
+		if !mt.Manifest.IsK8s() || mt.State.IsBuilding() {
+			continue
+		}
+
+		kState, ok := mt.State.RuntimeState.(store.K8sRuntimeState)
+		if !ok || len(kState.DeployedObjects) == 0 {
+			continue
+		}
+
+		objects := make([]store.DeployedObject, 0, len(kState.DeployedObjects))
+		for _, obj := range kState.DeployedObjects {
+			objects = append(objects, obj)
+		}
+
+		targets = append(targets, checkTarget{
+			name:    mt.Manifest.Name,
+			objects: objects,
+			drifted: mt.State.DriftedObjects,
+		})
+	}
+	return targets
+}
+
+func (c *Controller) driftedObjects(ctx context.Context, objects []store.DeployedObject) []string {
+	var drifted []string
+	for _, obj := range objects {
+		displayName := fmt.Sprintf("%s %s", obj.Ref.Kind, obj.Ref.Name)
+
+		live, err := c.kCli.GetByReference(ctx, obj.Ref)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				drifted = append(drifted, fmt.Sprintf("%s (deleted)", displayName))
+			}
+			// Other errors (e.g., connectivity problems) don't tell us
+			// anything about drift.
+			continue
+		}
+
+		if live.UID() != obj.Ref.UID {
+			// Deleted and recreated out-of-band.
+			drifted = append(drifted, displayName)
+			continue
+		}
+
+		// Generation only changes on spec edits, so prefer it where the
+		// kind tracks one; resource version also changes on status
+		// updates, but generation-less kinds (e.g., ConfigMap) have no
+		// status to update.
+		if obj.Generation > 0 {
+			if live.Generation() != obj.Generation {
+				drifted = append(drifted, displayName)
+			}
+			continue
+		}
+		if live.ResourceVersion() != obj.ResourceVersion {
+			drifted = append(drifted, displayName)
+		}
+	}
+	sort.Strings(drifted)
+	return drifted
+}
+
+var _ store.Subscriber = &Controller{}
@@ -0,0 +1,181 @@
+package drift
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/k8s/testyaml"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils"
+	"github.com/windmilleng/tilt/internal/testutils/manifestbuilder"
+	"github.com/windmilleng/tilt/internal/testutils/tempdir"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestNoDriftNoAction(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	e := f.deployManifest("fe", 1)
+	f.kCli.InjectEntityByName(e)
+
+	f.onChange()
+	assert.Empty(t, f.st.Actions())
+}
+
+func TestDriftedGeneration(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	e := f.deployManifest("fe", 1)
+
+	// Someone ran `kubectl edit` and bumped the generation.
+	live := e.DeepCopy()
+	live.Obj.(*appsv1.Deployment).Generation = 2
+	f.kCli.InjectEntityByName(live)
+
+	f.onChange()
+
+	action := f.lastDriftAction()
+	assert.Equal(t, model.ManifestName("fe"), action.ManifestName)
+	assert.Equal(t, []string{"Deployment sancho"}, action.DriftedObjects)
+}
+
+func TestDriftedObjectDeleted(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.deployManifest("fe", 1)
+
+	// Nothing injected into the fake client, so the live object is gone.
+	f.onChange()
+
+	action := f.lastDriftAction()
+	assert.Equal(t, []string{"Deployment sancho (deleted)"}, action.DriftedObjects)
+}
+
+func TestDriftResolvedDispatchesEmpty(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	e := f.deployManifest("fe", 1)
+	f.kCli.InjectEntityByName(e)
+
+	// The manifest currently reports drift, but the live object matches
+	// what we applied again (e.g., the edit was reverted).
+	f.st.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["fe"].State.DriftedObjects = []string{"Deployment sancho"}
+	})
+
+	f.onChange()
+
+	action := f.lastDriftAction()
+	assert.Empty(t, action.DriftedObjects)
+}
+
+func TestDriftCheckThrottled(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.deployManifest("fe", 1)
+
+	f.onChange()
+	require.Len(t, f.driftActions(), 1)
+
+	// Not enough time has passed for another check.
+	f.c.OnChange(f.ctx, f.st)
+	assert.Len(t, f.driftActions(), 1)
+}
+
+type fixture struct {
+	*tempdir.TempDirFixture
+	ctx   context.Context
+	st    *store.TestingStore
+	kCli  *k8s.FakeK8sClient
+	clock fakeClock
+	c     *Controller
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c fakeClock) Now() time.Time { return c.now }
+
+func newFixture(t *testing.T) *fixture {
+	f := tempdir.NewTempDirFixture(t)
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+
+	kCli := k8s.NewFakeK8sClient()
+	clock := fakeClock{now: time.Unix(1551202573, 0)}
+
+	return &fixture{
+		TempDirFixture: f,
+		ctx:            ctx,
+		st:             store.NewTestingStore(),
+		kCli:           kCli,
+		clock:          clock,
+		c:              NewController(kCli, clock),
+	}
+}
+
+// Registers a manifest whose last apply deployed the Sancho deployment
+// at the given generation, and returns the applied entity.
+func (f *fixture) deployManifest(name model.ManifestName, generation int64) k8s.K8sEntity {
+	entities, err := k8s.ParseYAMLFromString(testyaml.SanchoYAML)
+	require.NoError(f.T(), err)
+	require.Len(f.T(), entities, 1)
+
+	e := entities[0]
+	require.NoError(f.T(), k8s.SetUID(&e, "sancho-uid"))
+	e.Obj.(*appsv1.Deployment).Generation = generation
+
+	f.st.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, name).WithK8sYAML(testyaml.SanchoYAML).Build()
+		mt := store.NewManifestTarget(m)
+		mt.State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now().Add(-time.Minute),
+			FinishTime: time.Now(),
+		})
+
+		kState := mt.State.GetOrCreateK8sRuntimeState()
+		kState.DeployedObjects[e.UID()] = store.DeployedObject{
+			Ref:             e.ToObjectReference(),
+			Generation:      e.Generation(),
+			ResourceVersion: e.ResourceVersion(),
+		}
+		mt.State.RuntimeState = kState
+
+		state.UpsertManifestTarget(mt)
+	})
+	return e
+}
+
+// Advances past the check period and runs the controller.
+func (f *fixture) onChange() {
+	f.clock.now = f.clock.now.Add(2 * checkPeriod)
+	f.c.clock = f.clock
+	f.c.OnChange(f.ctx, f.st)
+}
+
+func (f *fixture) driftActions() []DriftAction {
+	var actions []DriftAction
+	for _, a := range f.st.Actions() {
+		if da, ok := a.(DriftAction); ok {
+			actions = append(actions, da)
+		}
+	}
+	return actions
+}
+
+func (f *fixture) lastDriftAction() DriftAction {
+	actions := f.driftActions()
+	require.NotEmpty(f.T(), actions)
+	return actions[len(actions)-1]
+}
@@ -2,6 +2,9 @@ package exit
 
 import (
 	"context"
+	"fmt"
+	"sync"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 
@@ -9,13 +12,28 @@ import (
 	"github.com/windmilleng/tilt/pkg/model"
 )
 
+// Timeouts for CI mode. Zero values mean "no deadline".
+type Timeouts struct {
+	// How long the whole session may take before Tilt gives up and exits
+	// with an error.
+	Global time.Duration
+
+	// How long each individual resource may take to become ready after its
+	// last successful deploy.
+	Resource time.Duration
+}
+
 // Controls normal process termination. Either Tilt completed all its work,
 // or it determined that it was unable to complete the work it was assigned.
 type Controller struct {
+	timeouts Timeouts
+
+	mu           sync.Mutex
+	tickerActive bool
 }
 
-func NewController() *Controller {
-	return &Controller{}
+func NewController(timeouts Timeouts) *Controller {
+	return &Controller{timeouts: timeouts}
 }
 
 func (c *Controller) shouldExit(store store.RStore) Action {
@@ -51,6 +69,14 @@ func (c *Controller) shouldExit(store store.RStore) Action {
 	}
 
 	if state.EngineMode.IsCIMode() {
+		if c.timeouts.Global > 0 && !state.TiltStartTime.IsZero() &&
+			time.Since(state.TiltStartTime) > c.timeouts.Global {
+			return Action{
+				ExitSignal: true,
+				ExitError:  fmt.Errorf("Timed out after %s waiting for resources to be ready", c.timeouts.Global),
+			}
+		}
+
 		// Check the runtime state of all resources.
 		// If any of the resources are in error, exit.
 		allOK := true
@@ -70,6 +96,14 @@ func (c *Controller) shouldExit(store store.RStore) Action {
 			}
 
 			if !c.isRuntimeDone(mt) {
+				if c.timeouts.Resource > 0 && !mt.State.LastSuccessfulDeployTime.IsZero() &&
+					time.Since(mt.State.LastSuccessfulDeployTime) > c.timeouts.Resource {
+					return Action{
+						ExitSignal: true,
+						ExitError: fmt.Errorf("Timed out after %s waiting for resource %q to be ready",
+							c.timeouts.Resource, mt.Manifest.Name),
+					}
+				}
 				allOK = false
 			}
 		}
@@ -121,11 +155,52 @@ func (c *Controller) isRuntimeDone(mt *store.ManifestTarget) bool {
 	return true
 }
 
-func (c *Controller) OnChange(ctx context.Context, store store.RStore) {
-	action := c.shouldExit(store)
+func (c *Controller) OnChange(ctx context.Context, st store.RStore) {
+	c.maybeStartTicker(ctx, st)
+
+	action := c.shouldExit(st)
 	if action.ExitSignal {
-		store.Dispatch(action)
+		st.Dispatch(action)
 	}
 }
 
+// Deadlines can expire without any new action hitting the store, so once
+// we're in CI mode with a timeout configured, re-check them on a timer.
+func (c *Controller) maybeStartTicker(ctx context.Context, st store.RStore) {
+	if c.timeouts.Global <= 0 && c.timeouts.Resource <= 0 {
+		return
+	}
+
+	state := st.RLockState()
+	isCI := state.EngineMode.IsCIMode()
+	st.RUnlockState()
+	if !isCI {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tickerActive {
+		return
+	}
+	c.tickerActive = true
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				action := c.shouldExit(st)
+				if action.ExitSignal {
+					st.Dispatch(action)
+					return
+				}
+			}
+		}
+	}()
+}
+
 var _ store.Subscriber = &Controller{}
@@ -157,6 +157,67 @@ func TestExitControlCISuccess(t *testing.T) {
 	assert.Nil(t, f.store.exitError)
 }
 
+func TestExitControlCIGlobalTimeout(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	defer f.TearDown()
+
+	f.c.timeouts = Timeouts{Global: time.Minute}
+
+	f.store.WithState(func(state *store.EngineState) {
+		state.TiltStartTime = time.Now().Add(-30 * time.Second)
+
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+		})
+	})
+
+	f.c.OnChange(f.ctx, f.store)
+	assert.False(t, f.store.exitSignal)
+
+	f.store.WithState(func(state *store.EngineState) {
+		state.TiltStartTime = time.Now().Add(-2 * time.Minute)
+	})
+
+	f.c.OnChange(f.ctx, f.store)
+	assert.True(t, f.store.exitSignal)
+	require.Error(t, f.store.exitError)
+	assert.Contains(t, f.store.exitError.Error(), "Timed out after 1m0s")
+}
+
+func TestExitControlCIResourceTimeout(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	defer f.TearDown()
+
+	f.c.timeouts = Timeouts{Resource: time.Minute}
+
+	f.store.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+		})
+		state.ManifestTargets["fe"].State.LastSuccessfulDeployTime = time.Now().Add(-30 * time.Second)
+		state.ManifestTargets["fe"].State.RuntimeState = store.NewK8sRuntimeState("fe",
+			store.Pod{PodID: "pod-a", Phase: v1.PodPending})
+	})
+
+	f.c.OnChange(f.ctx, f.store)
+	assert.False(t, f.store.exitSignal)
+
+	f.store.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["fe"].State.LastSuccessfulDeployTime = time.Now().Add(-2 * time.Minute)
+	})
+
+	f.c.OnChange(f.ctx, f.store)
+	assert.True(t, f.store.exitSignal)
+	require.Error(t, f.store.exitError)
+	assert.Contains(t, f.store.exitError.Error(), `resource "fe"`)
+}
+
 func TestExitControlCIJobSuccess(t *testing.T) {
 	f := newFixture(t, store.EngineModeCI)
 	defer f.TearDown()
@@ -200,7 +261,7 @@ func newFixture(t *testing.T, engineMode store.EngineMode) *fixture {
 		state.EngineMode = engineMode
 	})
 
-	c := NewController()
+	c := NewController(Timeouts{})
 	ctx := context.Background()
 
 	return &fixture{
@@ -3,19 +3,23 @@ package engine
 import (
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/distribution/reference"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
+	apitrace "go.opentelemetry.io/otel/api/trace"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/windmilleng/tilt/internal/analytics"
 	"github.com/windmilleng/tilt/internal/build"
 	"github.com/windmilleng/tilt/internal/container"
+	"github.com/windmilleng/tilt/internal/containerupdate"
 	"github.com/windmilleng/tilt/internal/dockerfile"
 	"github.com/windmilleng/tilt/internal/engine/buildcontrol"
 	"github.com/windmilleng/tilt/internal/k8s"
@@ -63,20 +67,30 @@ type ImageBuildAndDeployer struct {
 	ib               build.ImageBuilder
 	icb              *imageAndCacheBuilder
 	k8sClient        k8s.Client
+	clientPool       *k8s.ClientPool
 	env              k8s.Env
+	sharesDocker     buildcontrol.ClusterSharesDocker
 	runtime          container.Runtime
 	analytics        *analytics.TiltAnalytics
 	injectSynclet    bool
+	injectCopyHelper bool
 	clock            build.Clock
 	kl               KINDLoader
 	syncletContainer sidecar.SyncletContainer
+
+	// One preflight per kube context we deploy to, since the allowed
+	// permissions differ per cluster.
+	preflightMu sync.Mutex
+	preflights  map[k8s.KubeContext]*rbacPreflight
 }
 
 func NewImageBuildAndDeployer(
 	b build.ImageBuilder,
 	customBuilder build.CustomBuilder,
 	k8sClient k8s.Client,
+	clientPool *k8s.ClientPool,
 	env k8s.Env,
+	sharesDocker buildcontrol.ClusterSharesDocker,
 	analytics *analytics.TiltAnalytics,
 	updMode buildcontrol.UpdateMode,
 	c build.Clock,
@@ -88,12 +102,17 @@ func NewImageBuildAndDeployer(
 		ib:               b,
 		icb:              NewImageAndCacheBuilder(b, customBuilder, updMode),
 		k8sClient:        k8sClient,
+		clientPool:       clientPool,
 		env:              env,
+		sharesDocker:     sharesDocker,
 		analytics:        analytics,
 		clock:            c,
 		runtime:          runtime,
 		kl:               kl,
 		syncletContainer: syncletContainer,
+		preflights: map[k8s.KubeContext]*rbacPreflight{
+			"": newRBACPreflight(k8sClient),
+		},
 	}
 }
 
@@ -102,6 +121,12 @@ func (ibd *ImageBuildAndDeployer) SetInjectSynclet(inject bool) {
 	ibd.injectSynclet = inject
 }
 
+// Turn on copy-helper injection, for live-updating images without tar or a
+// shell. Should be called before any builds.
+func (ibd *ImageBuildAndDeployer) SetInjectCopyHelper(inject bool) {
+	ibd.injectCopyHelper = inject
+}
+
 func (ibd *ImageBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.RStore, specs []model.TargetSpec, stateSet store.BuildStateSet) (resultSet store.BuildResultSet, err error) {
 	iTargets, kTargets := extractImageAndK8sTargets(specs)
 	if len(kTargets) != 1 {
@@ -131,7 +156,12 @@ func (ibd *ImageBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.R
 
 	var anyLiveUpdate bool
 
+	state := st.RLockState()
+	signingSettings := state.SigningSettings
+	st.RUnlockState()
+
 	iTargetMap := model.ImageTargetsByID(iTargets)
+	ctx, buildSpan := apitrace.CurrentSpan(ctx).Tracer().Start(ctx, "image:build-and-push")
 	err = q.RunBuilds(func(target model.TargetSpec, state store.BuildState, depResults []store.BuildResult) (store.BuildResult, error) {
 		iTarget, ok := target.(model.ImageTarget)
 		if !ok {
@@ -148,7 +178,7 @@ func (ibd *ImageBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.R
 			return nil, err
 		}
 
-		err = ibd.push(ctx, refs.LocalRef, ps, iTarget, kTarget)
+		err = ibd.push(ctx, refs.LocalRef, ps, iTarget, kTarget, signingSettings)
 		if err != nil {
 			return nil, err
 		}
@@ -156,17 +186,23 @@ func (ibd *ImageBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.R
 		anyLiveUpdate = anyLiveUpdate || !iTarget.LiveUpdateInfo().Empty()
 		return store.NewImageBuildResult(iTarget.ID(), refs.LocalRef, refs.ClusterRef), nil
 	})
+	buildSpan.End()
 	if err != nil {
 		return store.BuildResultSet{}, buildcontrol.WrapDontFallBackError(err)
 	}
 
 	// (If we pass an empty list of refs here (as we will do if only deploying
 	// yaml), we just don't inject any image refs into the yaml, nbd.
-	brs, err := ibd.deploy(ctx, st, ps, iTargetMap, kTarget, q.Results(), anyLiveUpdate)
+	prevAppliedYAML := ""
+	if r, ok := stateSet[kTarget.ID()].LastSuccessfulResult.(store.K8sBuildResult); ok {
+		prevAppliedYAML = r.AppliedEntitiesText
+	}
+
+	brs, err := ibd.deploy(ctx, st, ps, iTargetMap, kTarget, q.Results(), anyLiveUpdate, prevAppliedYAML)
 	return brs, buildcontrol.WrapDontFallBackError(err)
 }
 
-func (ibd *ImageBuildAndDeployer) push(ctx context.Context, ref reference.NamedTagged, ps *build.PipelineState, iTarget model.ImageTarget, kTarget model.K8sTarget) error {
+func (ibd *ImageBuildAndDeployer) push(ctx context.Context, ref reference.NamedTagged, ps *build.PipelineState, iTarget model.ImageTarget, kTarget model.K8sTarget, signingSettings model.SigningSettings) error {
 	ps.StartPipelineStep(ctx, "Pushing %s", container.FamiliarString(ref))
 	defer ps.EndPipelineStep(ctx)
 
@@ -195,11 +231,41 @@ func (ibd *ImageBuildAndDeployer) push(ctx context.Context, ref reference.NamedT
 		if err != nil {
 			return err
 		}
+
+		err = ibd.signImage(ps.AttachLogger(ctx), signingSettings, ref)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// Runs the image_signing() command against a freshly pushed image, with
+// TILT_IMAGE set to the pushed ref. A failure blocks the deploy.
+func (ibd *ImageBuildAndDeployer) signImage(ctx context.Context, settings model.SigningSettings, ref reference.NamedTagged) error {
+	if settings.Empty() {
+		return nil
+	}
+
+	l := logger.Get(ctx)
+	l.Infof("Signing %s", container.FamiliarString(ref))
+
+	command := model.ToHostCmd(settings.Command)
+	cmd := exec.CommandContext(ctx, command.Argv[0], command.Argv[1:]...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("TILT_IMAGE=%s", container.FamiliarString(ref)))
+
+	w := l.Writer(logger.InfoLvl)
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "Signing command failed for %s", container.FamiliarString(ref))
+	}
+	return nil
+}
+
 func (ibd *ImageBuildAndDeployer) shouldUseKINDLoad(ctx context.Context, iTarg model.ImageTarget) bool {
 	isKIND := ibd.env == k8s.EnvKIND5 || ibd.env == k8s.EnvKIND6
 	if !isKIND {
@@ -223,7 +289,8 @@ func (ibd *ImageBuildAndDeployer) shouldUseKINDLoad(ctx context.Context, iTarg m
 
 // Returns: the entities deployed and the namespace of the pod with the given image name/tag.
 func (ibd *ImageBuildAndDeployer) deploy(ctx context.Context, st store.RStore, ps *build.PipelineState,
-	iTargetMap map[model.TargetID]model.ImageTarget, kTarget model.K8sTarget, results store.BuildResultSet, needsSynclet bool) (store.BuildResultSet, error) {
+	iTargetMap map[model.TargetID]model.ImageTarget, kTarget model.K8sTarget, results store.BuildResultSet, needsSynclet bool,
+	prevAppliedYAML string) (store.BuildResultSet, error) {
 	ps.StartPipelineStep(ctx, "Deploying")
 	defer ps.EndPipelineStep(ctx)
 
@@ -237,12 +304,31 @@ func (ibd *ImageBuildAndDeployer) deploy(ctx context.Context, st store.RStore, p
 	ctx = ibd.indentLogger(ctx)
 	l := logger.Get(ctx)
 
+	kubeContext := k8s.KubeContext(kTarget.KubeContext)
+	kCli := ibd.clientPool.ClientFor(ctx, kubeContext)
+
+	err = ibd.preflightFor(kubeContext, kCli).Check(ctx, newK8sEntities)
+	if err != nil {
+		return nil, err
+	}
+
+	reportClusterCapacity(ctx, kCli, newK8sEntities)
+
+	if kubeContext != "" {
+		l.Infof("Deploying to context: %s", kubeContext)
+	}
 	l.Infof("Applying via kubectl:")
 	for _, displayName := range kTarget.DisplayNames {
 		l.Infof("→ %s", displayName)
 	}
 
-	deployed, err := ibd.k8sClient.Upsert(ctx, newK8sEntities)
+	diffText := ibd.diffAgainstLastDeploy(ctx, prevAppliedYAML, newK8sEntities)
+
+	state := st.RLockState()
+	upsertTimeout := state.K8sUpsertTimeout
+	st.RUnlockState()
+
+	deployed, err := kCli.Upsert(ctx, newK8sEntities, upsertTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -262,17 +348,60 @@ func (ibd *ImageBuildAndDeployer) deploy(ctx context.Context, st store.RStore, p
 		}
 		podTemplateSpecHashes = append(podTemplateSpecHashes, hs...)
 	}
-	results[kTarget.ID()] = store.NewK8sDeployResult(kTarget.ID(), uids, podTemplateSpecHashes, deployed)
+	results[kTarget.ID()] = store.NewK8sDeployResult(kTarget.ID(), uids, podTemplateSpecHashes, deployed, diffText)
 
 	return results, nil
 }
 
+// Diff the new render against the previously-applied entities, logging the
+// changed fields. A diff failure never fails the build; it only costs us the
+// summary.
+func (ibd *ImageBuildAndDeployer) diffAgainstLastDeploy(ctx context.Context, prevAppliedYAML string, newK8sEntities []k8s.K8sEntity) string {
+	if prevAppliedYAML == "" {
+		return ""
+	}
+
+	l := logger.Get(ctx)
+	prevEntities, err := k8s.ParseYAMLFromString(prevAppliedYAML)
+	if err != nil {
+		l.Debugf("Error parsing previously-applied YAML for diff: %v", err)
+		return ""
+	}
+
+	diff, err := k8s.DiffEntitySets(prevEntities, newK8sEntities)
+	if err != nil {
+		l.Debugf("Error diffing against last deploy: %v", err)
+		return ""
+	}
+	if diff.Empty() {
+		return ""
+	}
+
+	diffText := diff.String()
+	l.Infof("Changes since last deploy:")
+	for _, line := range strings.Split(strings.TrimSuffix(diffText, "\n"), "\n") {
+		l.Infof("  %s", line)
+	}
+	return diffText
+}
+
 func (ibd *ImageBuildAndDeployer) indentLogger(ctx context.Context) context.Context {
 	l := logger.Get(ctx)
 	newL := logger.NewPrefixedLogger(logger.Blue(l).Sprint("     "), l)
 	return logger.WithLogger(ctx, newL)
 }
 
+func (ibd *ImageBuildAndDeployer) preflightFor(kubeContext k8s.KubeContext, kCli k8s.Client) *rbacPreflight {
+	ibd.preflightMu.Lock()
+	defer ibd.preflightMu.Unlock()
+	preflight, ok := ibd.preflights[kubeContext]
+	if !ok {
+		preflight = newRBACPreflight(kCli)
+		ibd.preflights[kubeContext] = preflight
+	}
+	return preflight
+}
+
 func (ibd *ImageBuildAndDeployer) createEntitiesToDeploy(ctx context.Context,
 	iTargetMap map[model.TargetID]model.ImageTarget, k8sTarget model.K8sTarget,
 	results store.BuildResultSet, needsSynclet bool) ([]k8s.K8sEntity, error) {
@@ -285,13 +414,22 @@ func (ibd *ImageBuildAndDeployer) createEntitiesToDeploy(ctx context.Context,
 		return nil, err
 	}
 
+	labelPairs := []model.LabelPair{
+		k8s.TiltManagedByLabel(),
+		k8s.TiltManifestLabel(k8sTarget.Name.String()),
+	}
+	if k8sTarget.KubeContext != "" {
+		labelPairs = append(labelPairs, k8s.TiltKubeContextLabel(k8s.KubeContext(k8sTarget.KubeContext)))
+	}
+
 	depIDs := k8sTarget.DependencyIDs()
 	injectedDepIDs := map[model.TargetID]bool{}
+	targetOverride := !k8sTarget.OverrideCmd.Empty() || k8sTarget.OverrideArgs.ShouldOverride
+	injectedTargetOverride := false
 	for _, e := range entities {
 		injectedSynclet := false
-		e, err = k8s.InjectLabels(e, []model.LabelPair{
-			k8s.TiltManagedByLabel(),
-		})
+		injectedCopyHelper := false
+		e, err = k8s.InjectLabels(e, labelPairs)
 		if err != nil {
 			return nil, errors.Wrap(err, "deploy")
 		}
@@ -312,6 +450,20 @@ func (ibd *ImageBuildAndDeployer) createEntitiesToDeploy(ctx context.Context,
 		// https://github.com/windmilleng/tilt/issues/1962
 		e = k8s.InjectParallelPodManagementPolicy(e)
 
+		// Workload-level command/args overrides from k8s_resource. Applied
+		// before the per-image overrides below, so that an image-level
+		// entrypoint wins if both are set.
+		if targetOverride {
+			var replaced bool
+			e, replaced, err = k8s.InjectCommandAndArgsToFirstContainer(e, k8sTarget.OverrideCmd, k8sTarget.OverrideArgs)
+			if err != nil {
+				return nil, err
+			}
+			if replaced {
+				injectedTargetOverride = true
+			}
+		}
+
 		// When working with a local k8s cluster, we set the pull policy to Never,
 		// to ensure that k8s fails hard if the image is missing from docker.
 		policy := v1.PullIfNotPresent
@@ -357,6 +509,20 @@ func (ibd *ImageBuildAndDeployer) createEntitiesToDeploy(ctx context.Context,
 					}
 					injectedSynclet = true
 				}
+
+				if ibd.injectCopyHelper && !injectedCopyHelper {
+					injectedRefSelector := container.NewRefSelector(ref).WithExactMatch()
+
+					var helperInjected bool
+					e, helperInjected, err = containerupdate.InjectCopyHelper(e, injectedRefSelector)
+					if err != nil {
+						return nil, err
+					}
+					if !helperInjected {
+						return nil, fmt.Errorf("Could not inject copy helper: %v", e)
+					}
+					injectedCopyHelper = true
+				}
 			}
 		}
 
@@ -376,15 +542,21 @@ func (ibd *ImageBuildAndDeployer) createEntitiesToDeploy(ctx context.Context,
 		}
 	}
 
+	if targetOverride && !injectedTargetOverride {
+		return nil, fmt.Errorf("could not inject command %v into resource %s: no pod templates found. "+
+			"Note: k8s_resource entrypoint/args overrides only apply to pod containers, not CRDs",
+			k8sTarget.OverrideCmd.Argv, k8sTarget.Name)
+	}
+
 	return newK8sEntities, nil
 }
 
-// If we're using docker-for-desktop as our k8s backend,
-// we don't need to push to the central registry.
-// The k8s will use the image already available
+// If the cluster shares the docker daemon we build into (docker-for-desktop,
+// minikube, etc., or forced with --cluster-shares-docker), we don't need to
+// push to the central registry. The k8s will use the image already available
 // in the local docker daemon.
 func (ibd *ImageBuildAndDeployer) canAlwaysSkipPush() bool {
-	return ibd.env.UsesLocalDockerRegistry() && ibd.runtime == container.RuntimeDocker
+	return bool(ibd.sharesDocker) && ibd.runtime == container.RuntimeDocker
 }
 
 // Create a new ImageTarget with the Dockerfiles rewritten with the injected images.
@@ -6,6 +6,7 @@ import (
 	"archive/tar"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"strings"
 	"testing"
 	"time"
@@ -678,6 +679,41 @@ func TestDeployInjectOverrideCommandAndArgs(t *testing.T) {
 	assert.Equal(t, []string(nil), c.Args)
 }
 
+func TestDeployInjectK8sTargetOverrideCommandAndArgs(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	cmd := model.ToUnixCmd("air")
+	manifest := NewSanchoDockerBuildManifestWithYaml(f, testyaml.SanchoYAMLWithCommand)
+	kTarg := manifest.K8sTarget()
+	kTarg.OverrideCmd = cmd
+	kTarg.OverrideArgs = model.OverrideArgs{ShouldOverride: true, Args: []string{"--debug"}}
+	manifest = manifest.WithDeployTarget(kTarg)
+
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, buildTargets(manifest), store.BuildStateSet{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entities, err := k8s.ParseYAMLFromString(f.k8s.Yaml)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !assert.Equal(t, 1, len(entities)) {
+		return
+	}
+
+	d := entities[0].Obj.(*v1.Deployment)
+	if !assert.Equal(t, 1, len(d.Spec.Template.Spec.Containers)) {
+		return
+	}
+
+	c := d.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, cmd.Argv, c.Command)
+	assert.Equal(t, []string{"--debug"}, c.Args)
+}
+
 func TestCantInjectOverrideCommandWithoutContainer(t *testing.T) {
 	f := newIBDFixture(t, k8s.EnvGKE)
 	defer f.TearDown()
@@ -776,6 +812,46 @@ func TestDockerBuildTargetStage(t *testing.T) {
 	assert.Equal(t, "stage", f.docker.BuildOptions.Target)
 }
 
+func TestImageSigningRunsAfterPush(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	out := f.JoinPath("signed.txt")
+	f.st.WithState(func(state *store.EngineState) {
+		state.SigningSettings = model.SigningSettings{
+			Command: fmt.Sprintf("echo \"$TILT_IMAGE\" > %s", out),
+		}
+	})
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, buildTargets(manifest), store.BuildStateSet{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, string(contents), "gcr.io/some-project-162817/sancho:tilt-")
+}
+
+func TestImageSigningFailureBlocksDeploy(t *testing.T) {
+	f := newIBDFixture(t, k8s.EnvGKE)
+	defer f.TearDown()
+
+	f.st.WithState(func(state *store.EngineState) {
+		state.SigningSettings = model.SigningSettings{Command: "false"}
+	})
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.ibd.BuildAndDeploy(f.ctx, f.st, buildTargets(manifest), store.BuildStateSet{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Signing command failed")
+	}
+	assert.Empty(t, f.k8s.Yaml, "expected nothing to be deployed")
+}
+
 type ibdFixture struct {
 	*tempdir.TempDirFixture
 	ctx    context.Context
@@ -0,0 +1,159 @@
+package k8sgc
+
+import (
+	"context"
+
+	"github.com/windmilleng/tilt/internal/feature"
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/logger"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// Controller garbage-collects Kubernetes objects that have disappeared from a
+// manifest's rendered YAML, or whose manifest has been removed from the
+// Tiltfile entirely, so that stale Deployments don't keep running forever.
+//
+// Opt-in via the k8s_gc feature flag. Before deleting anything, we check that
+// the object on the cluster is labeled as managed by Tilt, so we never GC
+// objects that someone else deployed.
+type Controller struct {
+	kClient k8s.Client
+
+	// The entities from the last YAML we saw for each manifest.
+	//
+	// We only GC when YAML we've already seen this session changes, never on
+	// first sight of a manifest, so objects deployed by a previous Tilt
+	// session are left alone until the user edits their Tiltfile.
+	seen map[model.ManifestName]manifestEntities
+}
+
+type manifestEntities struct {
+	yaml     string
+	entities []k8s.K8sEntity
+}
+
+func NewController(kClient k8s.Client) *Controller {
+	return &Controller{
+		kClient: kClient,
+		seen:    make(map[model.ManifestName]manifestEntities),
+	}
+}
+
+var _ store.Subscriber = &Controller{}
+
+func (c *Controller) OnChange(ctx context.Context, st store.RStore) {
+	toDelete := c.diff(st)
+	if len(toDelete) == 0 {
+		return
+	}
+
+	owned := c.filterOwnedByTilt(ctx, toDelete)
+	if len(owned) == 0 {
+		return
+	}
+
+	l := logger.Get(ctx)
+	l.Infof("Deleting orphaned Kubernetes objects:")
+	for _, e := range owned {
+		l.Infof("→ %s/%s", e.GVK().Kind, e.Name())
+	}
+
+	err := c.kClient.Delete(ctx, owned)
+	if err != nil {
+		l.Infof("Error garbage collecting Kubernetes objects: %v", err)
+	}
+}
+
+// Figure out the diff between the YAML we last saw for each manifest and
+// the YAML that's currently in the data store.
+func (c *Controller) diff(st store.RStore) []k8s.K8sEntity {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	if !state.Features[feature.KubernetesGC] {
+		return nil
+	}
+
+	current := make(map[model.ManifestName]string)
+	for _, mt := range state.Targets() {
+		if !mt.Manifest.IsK8s() {
+			continue
+		}
+		current[mt.Manifest.Name] = mt.Manifest.K8sTarget().YAML
+	}
+
+	var toDelete []k8s.K8sEntity
+	for name, prev := range c.seen {
+		yaml, stillExists := current[name]
+		if stillExists && yaml == prev.yaml {
+			continue
+		}
+
+		var newEntities []k8s.K8sEntity
+		if stillExists {
+			var err error
+			newEntities, err = k8s.ParseYAMLFromString(yaml)
+			if err != nil {
+				// The Tiltfile loader will surface the parse error;
+				// don't GC against YAML we can't understand.
+				continue
+			}
+		}
+
+		for _, e := range prev.entities {
+			if !containsEntity(newEntities, e) {
+				toDelete = append(toDelete, e)
+			}
+		}
+
+		if stillExists {
+			c.seen[name] = manifestEntities{yaml: yaml, entities: newEntities}
+		} else {
+			delete(c.seen, name)
+		}
+	}
+
+	// Record manifests we haven't seen before, without GCing anything.
+	for name, yaml := range current {
+		if _, ok := c.seen[name]; ok {
+			continue
+		}
+		entities, err := k8s.ParseYAMLFromString(yaml)
+		if err != nil {
+			continue
+		}
+		c.seen[name] = manifestEntities{yaml: yaml, entities: entities}
+	}
+
+	return toDelete
+}
+
+// Keep only the entities whose cluster objects are labeled as managed by Tilt.
+//
+// Objects that are already gone (or that we can't fetch) are dropped, since
+// there's nothing to GC.
+func (c *Controller) filterOwnedByTilt(ctx context.Context, entities []k8s.K8sEntity) []k8s.K8sEntity {
+	var result []k8s.K8sEntity
+	for _, e := range entities {
+		clusterObj, err := c.kClient.GetByReference(ctx, e.ToObjectReference())
+		if err != nil {
+			continue
+		}
+		if clusterObj.Labels()[k8s.ManagedByLabel] == k8s.ManagedByValue {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func containsEntity(entities []k8s.K8sEntity, target k8s.K8sEntity) bool {
+	for _, e := range entities {
+		if e.GVK() == target.GVK() &&
+			e.Name() == target.Name() &&
+			e.Namespace() == target.Namespace() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package k8sgc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/internal/feature"
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/k8s/testyaml"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils"
+	"github.com/windmilleng/tilt/internal/testutils/manifestbuilder"
+	"github.com/windmilleng/tilt/internal/testutils/tempdir"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestGCOrphanedObject(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.upsertManifest("fe", testyaml.SanchoYAML)
+	f.injectManagedByTilt(testyaml.SanchoYAML)
+
+	// First sight of the manifest never GCs anything.
+	f.c.OnChange(f.ctx, f.store)
+	assert.Empty(t, f.kCli.DeletedYaml)
+
+	// Re-rendering the manifest with different objects deletes the old ones.
+	f.upsertManifest("fe", testyaml.DoggosDeploymentYaml)
+	f.c.OnChange(f.ctx, f.store)
+	assert.Contains(t, f.kCli.DeletedYaml, "sancho")
+}
+
+func TestGCManifestRemoved(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.upsertManifest("fe", testyaml.SanchoYAML)
+	f.injectManagedByTilt(testyaml.SanchoYAML)
+
+	f.c.OnChange(f.ctx, f.store)
+	assert.Empty(t, f.kCli.DeletedYaml)
+
+	f.store.WithState(func(state *store.EngineState) {
+		delete(state.ManifestTargets, "fe")
+	})
+
+	f.c.OnChange(f.ctx, f.store)
+	assert.Contains(t, f.kCli.DeletedYaml, "sancho")
+}
+
+func TestGCDisabledByDefault(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.store.WithState(func(state *store.EngineState) {
+		state.Features[feature.KubernetesGC] = false
+	})
+
+	f.upsertManifest("fe", testyaml.SanchoYAML)
+	f.injectManagedByTilt(testyaml.SanchoYAML)
+
+	f.c.OnChange(f.ctx, f.store)
+	f.upsertManifest("fe", testyaml.DoggosDeploymentYaml)
+	f.c.OnChange(f.ctx, f.store)
+	assert.Empty(t, f.kCli.DeletedYaml)
+}
+
+func TestGCSkipsObjectsNotManagedByTilt(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.upsertManifest("fe", testyaml.SanchoYAML)
+
+	// The cluster object exists, but without the managed-by label,
+	// so someone else must have deployed it.
+	entities, err := k8s.ParseYAMLFromString(testyaml.SanchoYAML)
+	require.NoError(t, err)
+	f.kCli.InjectEntityByName(entities...)
+
+	f.c.OnChange(f.ctx, f.store)
+	f.upsertManifest("fe", testyaml.DoggosDeploymentYaml)
+	f.c.OnChange(f.ctx, f.store)
+	assert.Empty(t, f.kCli.DeletedYaml)
+}
+
+type fixture struct {
+	*tempdir.TempDirFixture
+	ctx   context.Context
+	store *store.TestingStore
+	kCli  *k8s.FakeK8sClient
+	c     *Controller
+}
+
+func newFixture(t *testing.T) *fixture {
+	f := tempdir.NewTempDirFixture(t)
+
+	st := store.NewTestingStore()
+	st.WithState(func(state *store.EngineState) {
+		state.Features = map[string]bool{feature.KubernetesGC: true}
+	})
+
+	kCli := k8s.NewFakeK8sClient()
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+
+	return &fixture{
+		TempDirFixture: f,
+		ctx:            ctx,
+		store:          st,
+		kCli:           kCli,
+		c:              NewController(kCli),
+	}
+}
+
+func (f *fixture) upsertManifest(name model.ManifestName, yaml string) {
+	m := manifestbuilder.New(f, name).WithK8sYAML(yaml).Build()
+	f.store.WithState(func(state *store.EngineState) {
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+	})
+}
+
+// Make the objects in the given YAML visible on the (fake) cluster,
+// labeled the way Tilt labels everything it deploys.
+func (f *fixture) injectManagedByTilt(yaml string) {
+	entities, err := k8s.ParseYAMLFromString(yaml)
+	require.NoError(f.T(), err)
+	for _, e := range entities {
+		labeled, err := k8s.InjectLabels(e, []model.LabelPair{k8s.TiltManagedByLabel()})
+		require.NoError(f.T(), err)
+		f.kCli.InjectEntityByName(labeled)
+	}
+}
@@ -38,18 +38,29 @@ func (m *PodMonitor) diff(st store.RStore) []podStatus {
 	for _, mt := range state.Targets() {
 		ms := mt.State
 		manifest := mt.Manifest
-		pod := ms.MostRecentPod()
-		podID := pod.PodID
-		if podID == "" {
-			continue
+
+		// Deployment-style resources roll a single pod forward, but
+		// StatefulSets and DaemonSets have a whole set of pods worth
+		// tracking individually.
+		runtime := ms.K8sRuntimeState()
+		pods := []store.Pod{ms.MostRecentPod()}
+		if runtime.TracksEveryPod() {
+			pods = runtime.PodList()
 		}
 
-		active[podID] = true
+		for _, pod := range pods {
+			podID := pod.PodID
+			if podID == "" {
+				continue
+			}
+
+			active[podID] = true
 
-		currentStatus := newPodStatus(pod, manifest.Name)
-		if !podStatusesEqual(currentStatus, m.pods[podID]) {
-			updates = append(updates, currentStatus)
-			m.pods[podID] = currentStatus
+			currentStatus := newPodStatus(pod, manifest.Name)
+			if !podStatusesEqual(currentStatus, m.pods[podID]) {
+				updates = append(updates, currentStatus)
+				m.pods[podID] = currentStatus
+			}
 		}
 	}
 
@@ -3,10 +3,13 @@ package k8swatch
 import (
 	"net/url"
 
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/store"
 	"github.com/windmilleng/tilt/pkg/model"
 )
 
@@ -21,6 +24,28 @@ type PodChangeAction struct {
 
 func (PodChangeAction) Action() {}
 
+// A change event identical to the queued one (e.g., an informer resync)
+// folds into it, so a pod event storm doesn't back up the store.
+//
+// Distinct events always pass through: the pod reducer is sensitive to
+// intermediate states (it baselines restart counts on the first event
+// it sees for a pod), so we can't just keep the latest.
+func (a PodChangeAction) Coalesce(next store.Action) (store.Action, bool) {
+	nextChange, ok := next.(PodChangeAction)
+	if !ok {
+		return nil, false
+	}
+	if a.ManifestName != nextChange.ManifestName || a.MatchedAncestorUID != nextChange.MatchedAncestorUID {
+		return nil, false
+	}
+	if !equality.Semantic.DeepEqual(a.Pod, nextChange.Pod) {
+		return nil, false
+	}
+	return a, true
+}
+
+var _ store.CoalescingAction = PodChangeAction{}
+
 func NewPodChangeAction(pod *v1.Pod, mn model.ManifestName, matchedAncestorUID types.UID) PodChangeAction {
 	return PodChangeAction{
 		Pod:                pod,
@@ -43,6 +68,28 @@ func NewPodDeleteAction(podID k8s.PodID, namespace k8s.Namespace) PodDeleteActio
 	}
 }
 
+type KubeContextChangedAction struct {
+	// The kubeconfig's new current context. Empty means it's back to the
+	// context Tilt started with.
+	KubeContext k8s.KubeContext
+}
+
+func (KubeContextChangedAction) Action() {}
+
+type JobChangeAction struct {
+	Job          *batchv1.Job
+	ManifestName model.ManifestName
+}
+
+func (JobChangeAction) Action() {}
+
+func NewJobChangeAction(job *batchv1.Job, mn model.ManifestName) JobChangeAction {
+	return JobChangeAction{
+		Job:          job,
+		ManifestName: mn,
+	}
+}
+
 type ServiceChangeAction struct {
 	Service      *v1.Service
 	ManifestName model.ManifestName
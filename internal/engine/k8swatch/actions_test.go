@@ -0,0 +1,43 @@
+package k8swatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestPodChangeCoalesce(t *testing.T) {
+	pending := podWithPhase("pod-a", v1.PodPending)
+	pendingDup := podWithPhase("pod-a", v1.PodPending)
+	running := podWithPhase("pod-a", v1.PodRunning)
+
+	a := NewPodChangeAction(pending, "fe", types.UID("pod-a"))
+
+	// Exact duplicates fold into the queued action.
+	merged, ok := a.Coalesce(NewPodChangeAction(pendingDup, "fe", types.UID("pod-a")))
+	if assert.True(t, ok) {
+		assert.Equal(t, v1.PodPending, merged.(PodChangeAction).Pod.Status.Phase)
+	}
+
+	// A real state change passes through.
+	_, ok = a.Coalesce(NewPodChangeAction(running, "fe", types.UID("pod-a")))
+	assert.False(t, ok)
+
+	_, ok = a.Coalesce(NewPodChangeAction(pendingDup, "other-manifest", types.UID("pod-a")))
+	assert.False(t, ok)
+}
+
+func podWithPhase(uid string, phase v1.PodPhase) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: uid,
+			UID:  types.UID(uid),
+		},
+		Status: v1.PodStatus{
+			Phase: phase,
+		},
+	}
+}
@@ -87,7 +87,9 @@ func (m *EventWatchManager) OnChange(ctx context.Context, st store.RStore) {
 func (m *EventWatchManager) setupWatch(ctx context.Context, st store.RStore, tiltStartTime time.Time) {
 	m.watching = true
 
-	ch, err := m.kClient.WatchEvents(ctx)
+	// Scope the watch to objects Tilt deployed, so a busy shared cluster
+	// doesn't flood us with events we'd just throw away.
+	ch, err := m.kClient.WatchEvents(ctx, k8s.ManagedByTiltSelector())
 	if err != nil {
 		err = errors.Wrap(err, "Error watching k8s events\n")
 		st.Dispatch(store.NewErrorAction(err))
@@ -96,6 +98,32 @@ func (m *EventWatchManager) setupWatch(ctx context.Context, st store.RStore, til
 	go m.dispatchEventsLoop(ctx, ch, st, tiltStartTime)
 }
 
+// Drop cached events that don't belong to anything Tilt deployed. Called
+// under memory pressure; if an owner shows up later, the watch events rebuild
+// the cache entries we need.
+func (m *EventWatchManager) PruneCache() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	live := store.NewUIDSet()
+	for uid := range m.knownDeployedUIDs {
+		for eventUID := range m.knownDescendentEventUIDs[uid] {
+			live.Add(eventUID)
+		}
+	}
+
+	for uid := range m.knownEvents {
+		if !live.Contains(uid) {
+			delete(m.knownEvents, uid)
+		}
+	}
+	for uid := range m.knownDescendentEventUIDs {
+		if _, ok := m.knownDeployedUIDs[uid]; !ok {
+			delete(m.knownDescendentEventUIDs, uid)
+		}
+	}
+}
+
 // When new UIDs are deployed, go through all our known events and dispatch
 // new actions. This handles the case where we get the event
 // before the deploy id shows up in the manifest, which is way more common than
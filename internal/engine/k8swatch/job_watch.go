@@ -0,0 +1,155 @@
+package k8swatch
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// JobWatcher watches batch Jobs so that run-to-completion resources can
+// report succeeded/failed from the Job conditions, rather than guessing
+// from pod phases (which lie when a Job retries).
+type JobWatcher struct {
+	pool *k8s.ClientPool
+
+	// The client and context of the watch we're currently running, if any.
+	kCli        k8s.Client
+	watching    bool
+	watchingCtx k8s.KubeContext
+	cancel      context.CancelFunc
+
+	mu                sync.RWMutex
+	knownDeployedUIDs map[types.UID]model.ManifestName
+	knownJobs         map[types.UID]*batchv1.Job
+}
+
+func NewJobWatcher(pool *k8s.ClientPool) *JobWatcher {
+	return &JobWatcher{
+		pool:              pool,
+		knownDeployedUIDs: make(map[types.UID]model.ManifestName),
+		knownJobs:         make(map[types.UID]*batchv1.Job),
+	}
+}
+
+func (w *JobWatcher) diff(st store.RStore) (watcherTaskList, k8s.KubeContext) {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	taskList := createWatcherTaskList(state, w.knownDeployedUIDs)
+	kubeContext := state.CurrentKubeContext
+	if w.watching && w.watchingCtx == kubeContext {
+		taskList.needsWatch = false
+	}
+	return taskList, kubeContext
+}
+
+func (w *JobWatcher) OnChange(ctx context.Context, st store.RStore) {
+	taskList, kubeContext := w.diff(st)
+	if taskList.needsWatch {
+		w.setupWatch(ctx, st, kubeContext)
+	}
+
+	if len(taskList.newUIDs) > 0 {
+		w.setupNewUIDs(st, taskList.newUIDs)
+	}
+}
+
+func (w *JobWatcher) setupWatch(ctx context.Context, st store.RStore, kubeContext k8s.KubeContext) {
+	if w.cancel != nil {
+		// The current context changed out from under us; tear down the watch
+		// on the old cluster before starting one on the new cluster.
+		w.cancel()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	w.watching = true
+	w.watchingCtx = kubeContext
+	w.cancel = cancel
+	w.kCli = w.pool.ClientFor(ctx, kubeContext)
+
+	ch, err := w.kCli.WatchJobs(ctx, k8s.ManagedByTiltSelector())
+	if err != nil {
+		err = errors.Wrap(err, "Error watching jobs. Are you connected to kubernetes?\n")
+		st.Dispatch(store.NewErrorAction(err))
+		return
+	}
+
+	go w.dispatchJobChangesLoop(ctx, ch, st)
+}
+
+// When new UIDs are deployed, go through all our known jobs and dispatch
+// new events, in case the Job change event beat the deploy result (see
+// ServiceWatcher.setupNewUIDs).
+func (w *JobWatcher) setupNewUIDs(st store.RStore, newUIDs map[types.UID]model.ManifestName) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for uid, mn := range newUIDs {
+		w.knownDeployedUIDs[uid] = mn
+
+		for _, job := range w.knownJobs {
+			if matchesUID(job, uid) {
+				st.Dispatch(NewJobChangeAction(job, mn))
+			}
+		}
+	}
+}
+
+// Match up the job update to a manifest.
+//
+// Checks the Job's own UID first, then its owners, so that Jobs spawned
+// by a deployed CronJob are matched too.
+func (w *JobWatcher) triageJobUpdate(job *batchv1.Job) model.ManifestName {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.knownJobs[job.UID] = job
+
+	for uid, mn := range w.knownDeployedUIDs {
+		if matchesUID(job, uid) {
+			return mn
+		}
+	}
+	return ""
+}
+
+func matchesUID(job *batchv1.Job, uid types.UID) bool {
+	if job.UID == uid {
+		return true
+	}
+	for _, owner := range job.OwnerReferences {
+		if owner.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *JobWatcher) dispatchJobChangesLoop(ctx context.Context, ch <-chan *batchv1.Job, st store.RStore) {
+	for {
+		select {
+		case job, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			manifestName := w.triageJobUpdate(job)
+			if manifestName == "" {
+				continue
+			}
+
+			st.Dispatch(NewJobChangeAction(job, manifestName))
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package k8swatch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestJobWatch(t *testing.T) {
+	f := newJWFixture(t)
+	defer f.TearDown()
+
+	uid := types.UID("fake-job-uid")
+	manifest := f.addManifest("migrate")
+	f.addDeployedUID(manifest, uid)
+
+	j := jobNamed("migrate", uid)
+	f.kClient.EmitJob(k8s.ManagedByTiltSelector(), j)
+
+	f.assertObservedJobChangeActions(JobChangeAction{Job: j, ManifestName: manifest.Name})
+}
+
+// A Job spawned by a deployed CronJob matches via its owner reference,
+// not its own UID.
+func TestJobWatchCronJobOwner(t *testing.T) {
+	f := newJWFixture(t)
+	defer f.TearDown()
+
+	cronUID := types.UID("fake-cronjob-uid")
+	manifest := f.addManifest("nightly")
+	f.addDeployedUID(manifest, cronUID)
+
+	j := jobNamed("nightly-12345", types.UID("spawned-job-uid"))
+	j.OwnerReferences = []metav1.OwnerReference{
+		{Kind: "CronJob", Name: "nightly", UID: cronUID},
+	}
+	f.kClient.EmitJob(k8s.ManagedByTiltSelector(), j)
+
+	f.assertObservedJobChangeActions(JobChangeAction{Job: j, ManifestName: manifest.Name})
+}
+
+func TestJobWatchUIDDelayed(t *testing.T) {
+	f := newJWFixture(t)
+	defer f.TearDown()
+
+	uid := types.UID("fake-job-uid")
+	manifest := f.addManifest("migrate")
+
+	f.jw.OnChange(f.ctx, f.store)
+
+	j := jobNamed("migrate", uid)
+	f.kClient.EmitJob(k8s.ManagedByTiltSelector(), j)
+	f.waitUntilJobKnown(uid)
+
+	f.addDeployedUID(manifest, uid)
+
+	f.assertObservedJobChangeActions(JobChangeAction{Job: j, ManifestName: manifest.Name})
+}
+
+func jobNamed(name string, uid types.UID) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			UID:  uid,
+		},
+	}
+}
+
+func (f *jwFixture) addManifest(manifestName string) model.Manifest {
+	state := f.store.LockMutableStateForTesting()
+	defer f.store.UnlockMutableState()
+	dt := model.K8sTarget{Name: model.TargetName(manifestName)}
+	m := model.Manifest{Name: model.ManifestName(manifestName)}.WithDeployTarget(dt)
+	state.UpsertManifestTarget(store.NewManifestTarget(m))
+	return m
+}
+
+func (f *jwFixture) addDeployedUID(m model.Manifest, uid types.UID) {
+	defer f.jw.OnChange(f.ctx, f.store)
+
+	state := f.store.LockMutableStateForTesting()
+	defer f.store.UnlockMutableState()
+	mState, ok := state.ManifestState(m.Name)
+	if !ok {
+		f.t.Fatalf("Unknown manifest: %s", m.Name)
+	}
+	runtimeState := mState.GetOrCreateK8sRuntimeState()
+	runtimeState.DeployedUIDSet[uid] = true
+}
+
+type jwFixture struct {
+	t       *testing.T
+	kClient *k8s.FakeK8sClient
+	jw      *JobWatcher
+	ctx     context.Context
+	cancel  func()
+	store   *store.TestingStore
+}
+
+func newJWFixture(t *testing.T) *jwFixture {
+	kClient := k8s.NewFakeK8sClient()
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	ctx, cancel := context.WithCancel(ctx)
+
+	of := k8s.ProvideOwnerFetcher(kClient)
+	jw := NewJobWatcher(k8s.ProvideClientPool("fake-context", kClient, of))
+	st := store.NewTestingStore()
+
+	return &jwFixture{
+		kClient: kClient,
+		jw:      jw,
+		ctx:     ctx,
+		cancel:  cancel,
+		t:       t,
+		store:   st,
+	}
+}
+
+func (f *jwFixture) TearDown() {
+	f.kClient.TearDown()
+	f.cancel()
+	f.store.AssertNoErrorActions(f.t)
+}
+
+func (f *jwFixture) assertObservedJobChangeActions(expected ...JobChangeAction) {
+	start := time.Now()
+	for time.Since(start) < time.Second {
+		actions := f.store.Actions()
+		if len(actions) == len(expected) {
+			break
+		}
+	}
+
+	var observed []JobChangeAction
+	for _, a := range f.store.Actions() {
+		jca, ok := a.(JobChangeAction)
+		if !ok {
+			f.t.Fatalf("got non-%T: %v", JobChangeAction{}, a)
+		}
+		observed = append(observed, jca)
+	}
+	if !assert.Equal(f.t, expected, observed) {
+		f.t.FailNow()
+	}
+}
+
+func (f *jwFixture) waitUntilJobKnown(uid types.UID) {
+	start := time.Now()
+	for time.Since(start) < time.Second {
+		f.jw.mu.Lock()
+		_, known := f.jw.knownJobs[uid]
+		f.jw.mu.Unlock()
+		if known {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	f.t.Fatalf("timeout waiting for job with UID: %s", uid)
+}
@@ -0,0 +1,100 @@
+package k8swatch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/logger"
+)
+
+// How often we re-read the kubeconfig. We poll rather than use file
+// notifications because kubectl rewrites the config with an atomic rename,
+// which many watch implementations miss.
+const kubeconfigPollInterval = 2 * time.Second
+
+// Polls the kubeconfig for changes to the current context, so that
+// `kubectl config use-context` takes effect without restarting Tilt.
+type KubeconfigWatcher struct {
+	startContext k8s.KubeContext
+
+	// Re-reads the kubeconfig from disk. Swapped out in tests.
+	load func() (*api.Config, error)
+
+	mu       sync.Mutex
+	started  bool
+	lastSeen k8s.KubeContext
+}
+
+var _ store.Subscriber = &KubeconfigWatcher{}
+
+func NewKubeconfigWatcher(kubeContext k8s.KubeContext) *KubeconfigWatcher {
+	return &KubeconfigWatcher{
+		startContext: kubeContext,
+		lastSeen:     kubeContext,
+		load:         clientcmd.NewDefaultClientConfigLoadingRules().Load,
+	}
+}
+
+func (w *KubeconfigWatcher) OnChange(ctx context.Context, st store.RStore) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.started {
+		return
+	}
+	w.started = true
+	go w.loop(ctx, st)
+}
+
+func (w *KubeconfigWatcher) loop(ctx context.Context, st store.RStore) {
+	ticker := time.NewTicker(kubeconfigPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.check(ctx, st)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (w *KubeconfigWatcher) check(ctx context.Context, st store.RStore) {
+	config, err := w.load()
+	if err != nil {
+		// A kubeconfig mid-rewrite can be transiently unreadable; try again
+		// on the next poll.
+		logger.Get(ctx).Debugf("Reloading kubeconfig: %v", err)
+		return
+	}
+
+	current := k8s.KubeContext(config.CurrentContext)
+	if current == "" {
+		// No current context set (or the config is mid-write); nothing to
+		// switch to.
+		return
+	}
+
+	w.mu.Lock()
+	changed := current != w.lastSeen
+	w.lastSeen = current
+	w.mu.Unlock()
+	if !changed {
+		return
+	}
+
+	if current == w.startContext {
+		logger.Get(ctx).Infof("Kube context switched back to %s; re-establishing watches", current)
+		st.Dispatch(KubeContextChangedAction{})
+		return
+	}
+
+	logger.Get(ctx).Infof("Kube context switched to %s; re-establishing watches against the new cluster", current)
+	st.Dispatch(KubeContextChangedAction{KubeContext: current})
+}
@@ -0,0 +1,44 @@
+package k8swatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils"
+)
+
+func TestKubeconfigWatchContextSwitch(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	st := store.NewTestingStore()
+
+	current := "start-context"
+	w := NewKubeconfigWatcher("start-context")
+	w.load = func() (*api.Config, error) {
+		return &api.Config{CurrentContext: current}, nil
+	}
+
+	// No change yet.
+	w.check(ctx, st)
+	assert.Empty(t, st.Actions())
+
+	current = "other-context"
+	w.check(ctx, st)
+	assert.Equal(t, []store.Action{
+		KubeContextChangedAction{KubeContext: "other-context"},
+	}, st.Actions())
+
+	// Same context again shouldn't re-fire.
+	w.check(ctx, st)
+	assert.Equal(t, 1, len(st.Actions()))
+
+	// Switching back reports an empty context, i.e. the one we started with.
+	current = "start-context"
+	w.check(ctx, st)
+	assert.Equal(t, []store.Action{
+		KubeContextChangedAction{KubeContext: "other-context"},
+		KubeContextChangedAction{},
+	}, st.Actions())
+}
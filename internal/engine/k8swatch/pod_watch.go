@@ -28,8 +28,7 @@ var errorWaitingReasons = map[string]bool{
 }
 
 type PodWatcher struct {
-	kCli         k8s.Client
-	ownerFetcher k8s.OwnerFetcher
+	pool *k8s.ClientPool
 
 	mu                sync.RWMutex
 	watches           []PodWatch
@@ -45,10 +44,9 @@ type PodWatcher struct {
 	knownPods map[types.UID]*v1.Pod
 }
 
-func NewPodWatcher(kCli k8s.Client, ownerFetcher k8s.OwnerFetcher) *PodWatcher {
+func NewPodWatcher(pool *k8s.ClientPool) *PodWatcher {
 	return &PodWatcher{
-		kCli:                   kCli,
-		ownerFetcher:           ownerFetcher,
+		pool:                   pool,
 		knownDeployedUIDs:      make(map[types.UID]model.ManifestName),
 		knownDescendentPodUIDs: make(map[types.UID]store.UIDSet),
 		knownPods:              make(map[types.UID]*v1.Pod),
@@ -56,13 +54,16 @@ func NewPodWatcher(kCli k8s.Client, ownerFetcher k8s.OwnerFetcher) *PodWatcher {
 }
 
 type PodWatch struct {
-	name   model.ManifestName
-	labels labels.Selector
-	cancel context.CancelFunc
+	name        model.ManifestName
+	labels      labels.Selector
+	kubeContext k8s.KubeContext
+	cancel      context.CancelFunc
 }
 
 func (pw PodWatch) Equal(other PodWatch) bool {
-	return pw.name == other.name && k8s.SelectorEqual(pw.labels, other.labels)
+	return pw.name == other.name &&
+		pw.kubeContext == other.kubeContext &&
+		k8s.SelectorEqual(pw.labels, other.labels)
 }
 
 // returns all elements of `a` that are not in `b`
@@ -99,15 +100,28 @@ func (w *PodWatcher) diff(ctx context.Context, st store.RStore) podWatchTaskList
 
 	taskList := createWatcherTaskList(state, w.knownDeployedUIDs)
 	var neededWatches []PodWatch
+
+	// Watch every kube context we deploy to. Targets without an explicit
+	// context follow the kubeconfig's current context, so a `kubectl config
+	// use-context` tears down the old cluster's watch and starts a new one.
+	defaultContext := state.CurrentKubeContext
+	kubeContexts := map[k8s.KubeContext]bool{defaultContext: true}
 	for _, mt := range state.Targets() {
+		kubeContext := k8s.KubeContext(mt.Manifest.K8sTarget().KubeContext)
+		if kubeContext == "" {
+			kubeContext = defaultContext
+		}
+		kubeContexts[kubeContext] = true
 		for _, ls := range mt.Manifest.K8sTarget().ExtraPodSelectors {
 			if !ls.Empty() {
-				neededWatches = append(neededWatches, PodWatch{name: mt.Manifest.Name, labels: ls})
+				neededWatches = append(neededWatches, PodWatch{name: mt.Manifest.Name, labels: ls, kubeContext: kubeContext})
 			}
 		}
 	}
 	if taskList.needsWatch {
-		neededWatches = append(neededWatches, PodWatch{labels: k8s.ManagedByTiltSelector()})
+		for kubeContext := range kubeContexts {
+			neededWatches = append(neededWatches, PodWatch{labels: k8s.ManagedByTiltSelector(), kubeContext: kubeContext})
+		}
 	}
 
 	return podWatchTaskList{
@@ -124,13 +138,14 @@ func (w *PodWatcher) OnChange(ctx context.Context, st store.RStore) {
 		ctx, cancel := context.WithCancel(ctx)
 		pw.cancel = cancel
 		w.addWatch(pw)
-		ch, err := w.kCli.WatchPods(ctx, pw.labels)
+		kCli := w.pool.ClientFor(ctx, pw.kubeContext)
+		ch, err := kCli.WatchPods(ctx, pw.labels)
 		if err != nil {
 			err = errors.Wrap(err, "Error watching pods. Are you connected to kubernetes?\n")
 			st.Dispatch(store.NewErrorAction(err))
 			return
 		}
-		go w.dispatchPodChangesLoop(ctx, ch, st)
+		go w.dispatchPodChangesLoop(ctx, w.pool.OwnerFetcherFor(ctx, pw.kubeContext), ch, st)
 	}
 
 	for _, pw := range taskList.teardown {
@@ -188,6 +203,33 @@ func (w *PodWatcher) setupNewUIDs(ctx context.Context, st store.RStore, newUIDs
 	}
 }
 
+// Drop cached pods that don't belong to anything Tilt deployed. Called under
+// memory pressure; if an owner shows up later, the watch events rebuild the
+// cache entries we need.
+func (w *PodWatcher) PruneCache() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	live := make(map[types.UID]bool)
+	for uid := range w.knownDeployedUIDs {
+		live[uid] = true
+		for podUID := range w.knownDescendentPodUIDs[uid] {
+			live[podUID] = true
+		}
+	}
+
+	for uid := range w.knownPods {
+		if !live[uid] {
+			delete(w.knownPods, uid)
+		}
+	}
+	for uid := range w.knownDescendentPodUIDs {
+		if !live[uid] {
+			delete(w.knownDescendentPodUIDs, uid)
+		}
+	}
+}
+
 func (w *PodWatcher) upsertPod(pod *v1.Pod) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -251,8 +293,8 @@ func (w *PodWatcher) triagePodTree(pod *v1.Pod, objTree k8s.ObjectRefTree) (mode
 	return "", ""
 }
 
-func (w *PodWatcher) dispatchPodChange(ctx context.Context, pod *v1.Pod, st store.RStore) {
-	objTree, err := w.ownerFetcher.OwnerTreeOf(ctx, k8s.NewK8sEntity(pod))
+func (w *PodWatcher) dispatchPodChange(ctx context.Context, ownerFetcher k8s.OwnerFetcher, pod *v1.Pod, st store.RStore) {
+	objTree, err := ownerFetcher.OwnerTreeOf(ctx, k8s.NewK8sEntity(pod))
 	if err != nil {
 		logger.Get(ctx).Infof("Handling pod update (%q): %v", pod.Name, err)
 		return
@@ -271,7 +313,7 @@ func (w *PodWatcher) dispatchPodChange(ctx context.Context, pod *v1.Pod, st stor
 	w.mu.Unlock()
 }
 
-func (w *PodWatcher) dispatchPodChangesLoop(ctx context.Context, ch <-chan k8s.ObjectUpdate, st store.RStore) {
+func (w *PodWatcher) dispatchPodChangesLoop(ctx context.Context, ownerFetcher k8s.OwnerFetcher, ch <-chan k8s.ObjectUpdate, st store.RStore) {
 	for {
 		select {
 		case obj, ok := <-ch:
@@ -283,7 +325,7 @@ func (w *PodWatcher) dispatchPodChangesLoop(ctx context.Context, ch <-chan k8s.O
 			if ok {
 				w.upsertPod(pod)
 
-				go w.dispatchPodChange(ctx, pod, st)
+				go w.dispatchPodChange(ctx, ownerFetcher, pod, st)
 				continue
 			}
 
@@ -220,6 +220,64 @@ func TestPodWatchHandleSelectorChange(t *testing.T) {
 	assert.Equal(t, []model.ManifestName{manifest2.Name, manifest2.Name}, f.manifestNames)
 }
 
+func TestPodWatchMultipleContexts(t *testing.T) {
+	f := newPWFixture(t)
+	defer f.TearDown()
+
+	kClient2 := k8s.NewFakeK8sClient()
+	defer kClient2.TearDown()
+	f.pool.SetClientForTesting("other-context", kClient2)
+
+	manifest := f.addManifestWithSelectors("server")
+	manifest2 := f.addManifestWithKubeContext("server2", "other-context")
+
+	f.pw.OnChange(f.ctx, f.store)
+
+	// Each cluster gets its own managed-by-tilt watch.
+	ls := k8s.ManagedByTiltSelector()
+	f.assertWatchedSelectors(ls)
+	assert.Equal(t, []labels.Selector{ls}, kClient2.WatchedSelectors())
+
+	pb := podbuilder.New(t, manifest)
+	p := pb.Build()
+	f.addDeployedUID(manifest, pb.DeploymentUID())
+	f.kClient.InjectEntityByName(pb.ObjectTreeEntities()...)
+	f.kClient.EmitPod(ls, p)
+
+	pb2 := podbuilder.New(t, manifest2).WithPodID("pod2")
+	p2 := pb2.Build()
+	f.addDeployedUID(manifest2, pb2.DeploymentUID())
+	kClient2.InjectEntityByName(pb2.ObjectTreeEntities()...)
+	kClient2.EmitPod(ls, p2)
+
+	f.assertObservedPods(p, p2)
+}
+
+func TestPodWatchKubeContextSwitch(t *testing.T) {
+	f := newPWFixture(t)
+	defer f.TearDown()
+
+	kClient2 := k8s.NewFakeK8sClient()
+	defer kClient2.TearDown()
+	f.pool.SetClientForTesting("new-context", kClient2)
+
+	f.addManifestWithSelectors("server")
+	f.pw.OnChange(f.ctx, f.store)
+
+	ls := k8s.ManagedByTiltSelector()
+	f.assertWatchedSelectors(ls)
+	assert.Empty(t, kClient2.WatchedSelectors())
+
+	// Simulate `kubectl config use-context new-context`: the watch should
+	// be re-established against the new cluster.
+	state := f.store.LockMutableStateForTesting()
+	state.CurrentKubeContext = "new-context"
+	f.store.UnlockMutableState()
+
+	f.pw.OnChange(f.ctx, f.store)
+	assert.Equal(t, []labels.Selector{ls}, kClient2.WatchedSelectors())
+}
+
 func TestPodsDispatchedInOrder(t *testing.T) {
 	f := newPWFixture(t)
 	defer f.TearDown()
@@ -244,10 +302,15 @@ func TestPodsDispatchedInOrder(t *testing.T) {
 		f.kClient.EmitPod(ls, pod)
 	}
 
-	f.waitForPodActionCount(count)
+	// The watcher dispatches the freshest known version of the pod for
+	// every event, and identical queued events coalesce -- so we won't
+	// necessarily see all 20 actions, but the latest version must
+	// arrive and the ones we see must be in order.
+	f.waitForPodResourceVersion(fmt.Sprintf("%d", count-1))
 
-	// Make sure the pods showed up in order.
-	for i := 1; i < count; i++ {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := 1; i < len(f.pods); i++ {
 		pod := f.pods[i]
 		lastPod := f.pods[i-1]
 		podV, _ := strconv.Atoi(pod.ResourceVersion)
@@ -319,6 +382,20 @@ func (f *pwFixture) addManifestWithSelectors(manifestName string, ls ...labels.S
 	return mt.Manifest
 }
 
+func (f *pwFixture) addManifestWithKubeContext(manifestName string, kubeContext k8s.KubeContext) model.Manifest {
+	state := f.store.LockMutableStateForTesting()
+	m := manifestbuilder.New(f, model.ManifestName(manifestName)).
+		WithK8sYAML(testyaml.SanchoYAML).
+		Build()
+	kTarget := m.K8sTarget()
+	kTarget.KubeContext = string(kubeContext)
+	m = m.WithDeployTarget(kTarget)
+	mt := store.NewManifestTarget(m)
+	state.UpsertManifestTarget(mt)
+	f.store.UnlockMutableState()
+	return mt.Manifest
+}
+
 func (f *pwFixture) removeManifest(manifestName string) {
 	mn := model.ManifestName(manifestName)
 	state := f.store.LockMutableStateForTesting()
@@ -337,6 +414,7 @@ type pwFixture struct {
 	*tempdir.TempDirFixture
 	t             *testing.T
 	kClient       *k8s.FakeK8sClient
+	pool          *k8s.ClientPool
 	pw            *PodWatcher
 	ctx           context.Context
 	cancel        func()
@@ -365,10 +443,12 @@ func newPWFixture(t *testing.T) *pwFixture {
 	ctx, cancel := context.WithCancel(ctx)
 
 	of := k8s.ProvideOwnerFetcher(kClient)
-	pw := NewPodWatcher(kClient, of)
+	pool := k8s.ProvideClientPool("fake-context", kClient, of)
+	pw := NewPodWatcher(pool)
 	ret := &pwFixture{
 		TempDirFixture: tempdir.NewTempDirFixture(t),
 		kClient:        kClient,
+		pool:           pool,
 		pw:             pw,
 		ctx:            ctx,
 		cancel:         cancel,
@@ -422,6 +502,24 @@ func (f *pwFixture) waitForPodActionCount(count int) {
 	f.t.Fatalf("Timeout waiting for %d pod actions", count)
 }
 
+func (f *pwFixture) waitForPodResourceVersion(rv string) {
+	start := time.Now()
+	for time.Since(start) < time.Second {
+		f.mu.Lock()
+		for _, pod := range f.pods {
+			if pod.ResourceVersion == rv {
+				f.mu.Unlock()
+				return
+			}
+		}
+		f.mu.Unlock()
+
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	f.t.Fatalf("Timeout waiting for pod with resource version %s", rv)
+}
+
 func (f *pwFixture) assertObservedPods(pods ...*corev1.Pod) {
 	f.waitForPodActionCount(len(pods))
 	require.ElementsMatch(f.t, pods, f.pods)
@@ -0,0 +1,67 @@
+package k8swatch
+
+import (
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/windmilleng/tilt/internal/container"
+	"github.com/windmilleng/tilt/internal/store"
+)
+
+// The kubelet reports probe failures as warning events on the pod with
+// this reason.
+const probeFailureReason = "Unhealthy"
+
+var probeNames = []string{"Liveness", "Readiness", "Startup"}
+
+// Recognize a probe-failure event and turn it into a structured warning.
+//
+// Probe events have messages like
+//
+//	Liveness probe failed: HTTP probe failed with statuscode: 500
+//
+// and identify the container in the involved object's field path
+// ("spec.containers{app}").
+func ProbeWarningForEvent(event *v1.Event) (store.ProbeWarning, bool) {
+	if event.Reason != probeFailureReason || event.InvolvedObject.Kind != "Pod" {
+		return store.ProbeWarning{}, false
+	}
+
+	probe := ""
+	for _, name := range probeNames {
+		if strings.HasPrefix(event.Message, name+" probe") {
+			probe = name
+			break
+		}
+	}
+	if probe == "" {
+		return store.ProbeWarning{}, false
+	}
+
+	output := ""
+	if i := strings.Index(event.Message, ": "); i != -1 {
+		output = strings.TrimSpace(event.Message[i+2:])
+	}
+
+	count := int(event.Count)
+	if count < 1 {
+		count = 1
+	}
+
+	return store.ProbeWarning{
+		Probe:         probe,
+		ContainerName: containerNameFromFieldPath(event.InvolvedObject.FieldPath),
+		Count:         count,
+		LastOutput:    output,
+	}, true
+}
+
+func containerNameFromFieldPath(fieldPath string) container.Name {
+	start := strings.Index(fieldPath, "{")
+	end := strings.LastIndex(fieldPath, "}")
+	if start == -1 || end <= start {
+		return ""
+	}
+	return container.Name(fieldPath[start+1 : end])
+}
@@ -0,0 +1,73 @@
+package k8swatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/windmilleng/tilt/internal/store"
+)
+
+func TestProbeWarningForEvent(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		event    *v1.Event
+		expected store.ProbeWarning
+		ok       bool
+	}{
+		{
+			name: "readiness",
+			event: probeEvent("Unhealthy",
+				"Readiness probe failed: HTTP probe failed with statuscode: 500", 3),
+			expected: store.ProbeWarning{
+				Probe:         "Readiness",
+				ContainerName: "sancho",
+				Count:         3,
+				LastOutput:    "HTTP probe failed with statuscode: 500",
+			},
+			ok: true,
+		},
+		{
+			name:  "liveness errored",
+			event: probeEvent("Unhealthy", "Liveness probe errored: rpc error: context deadline exceeded", 1),
+			expected: store.ProbeWarning{
+				Probe:         "Liveness",
+				ContainerName: "sancho",
+				Count:         1,
+				LastOutput:    "rpc error: context deadline exceeded",
+			},
+			ok: true,
+		},
+		{
+			name:  "other unhealthy event",
+			event: probeEvent("Unhealthy", "something else entirely", 1),
+		},
+		{
+			name:  "other reason",
+			event: probeEvent("BackOff", "Liveness probe failed: nope", 1),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			warning, ok := ProbeWarningForEvent(test.event)
+			assert.Equal(t, test.ok, ok)
+			if test.ok {
+				assert.Equal(t, test.expected, warning)
+			}
+		})
+	}
+}
+
+func probeEvent(reason string, message string, count int32) *v1.Event {
+	return &v1.Event{
+		Reason:  reason,
+		Message: message,
+		Count:   count,
+		Type:    v1.EventTypeWarning,
+		InvolvedObject: v1.ObjectReference{
+			Kind:      "Pod",
+			Name:      "sancho-pod",
+			FieldPath: "spec.containers{sancho}",
+		},
+	}
+}
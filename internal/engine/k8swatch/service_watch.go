@@ -15,25 +15,28 @@ import (
 )
 
 type ServiceWatcher struct {
-	kCli         k8s.Client
-	ownerFetcher k8s.OwnerFetcher
-	watching     bool
+	pool *k8s.ClientPool
+
+	// The client and context of the watch we're currently running, if any.
+	kCli        k8s.Client
+	watching    bool
+	watchingCtx k8s.KubeContext
+	cancel      context.CancelFunc
 
 	mu                sync.RWMutex
 	knownDeployedUIDs map[types.UID]model.ManifestName
 	knownServices     map[types.UID]*v1.Service
 }
 
-func NewServiceWatcher(kCli k8s.Client, ownerFetcher k8s.OwnerFetcher) *ServiceWatcher {
+func NewServiceWatcher(pool *k8s.ClientPool) *ServiceWatcher {
 	return &ServiceWatcher{
-		kCli:              kCli,
-		ownerFetcher:      ownerFetcher,
+		pool:              pool,
 		knownDeployedUIDs: make(map[types.UID]model.ManifestName),
 		knownServices:     make(map[types.UID]*v1.Service),
 	}
 }
 
-func (w *ServiceWatcher) diff(st store.RStore) watcherTaskList {
+func (w *ServiceWatcher) diff(st store.RStore) (watcherTaskList, k8s.KubeContext) {
 	state := st.RLockState()
 	defer st.RUnlockState()
 
@@ -41,16 +44,17 @@ func (w *ServiceWatcher) diff(st store.RStore) watcherTaskList {
 	defer w.mu.RUnlock()
 
 	taskList := createWatcherTaskList(state, w.knownDeployedUIDs)
-	if w.watching {
+	kubeContext := state.CurrentKubeContext
+	if w.watching && w.watchingCtx == kubeContext {
 		taskList.needsWatch = false
 	}
-	return taskList
+	return taskList, kubeContext
 }
 
 func (w *ServiceWatcher) OnChange(ctx context.Context, st store.RStore) {
-	taskList := w.diff(st)
+	taskList, kubeContext := w.diff(st)
 	if taskList.needsWatch {
-		w.setupWatch(ctx, st)
+		w.setupWatch(ctx, st, kubeContext)
 	}
 
 	if len(taskList.newUIDs) > 0 {
@@ -58,8 +62,17 @@ func (w *ServiceWatcher) OnChange(ctx context.Context, st store.RStore) {
 	}
 }
 
-func (w *ServiceWatcher) setupWatch(ctx context.Context, st store.RStore) {
+func (w *ServiceWatcher) setupWatch(ctx context.Context, st store.RStore, kubeContext k8s.KubeContext) {
+	if w.cancel != nil {
+		// The current context changed out from under us; tear down the watch
+		// on the old cluster before starting one on the new cluster.
+		w.cancel()
+	}
+	ctx, cancel := context.WithCancel(ctx)
 	w.watching = true
+	w.watchingCtx = kubeContext
+	w.cancel = cancel
+	w.kCli = w.pool.ClientFor(ctx, kubeContext)
 
 	ch, err := w.kCli.WatchServices(ctx, k8s.ManagedByTiltSelector())
 	if err != nil {
@@ -68,7 +81,7 @@ func (w *ServiceWatcher) setupWatch(ctx context.Context, st store.RStore) {
 		return
 	}
 
-	go w.dispatchServiceChangesLoop(ctx, ch, st)
+	go w.dispatchServiceChangesLoop(ctx, w.kCli, ch, st)
 }
 
 // When new UIDs are deployed, go through all our known services and dispatch
@@ -113,7 +126,7 @@ func (w *ServiceWatcher) triageServiceUpdate(service *v1.Service) model.Manifest
 	return manifestName
 }
 
-func (w *ServiceWatcher) dispatchServiceChangesLoop(ctx context.Context, ch <-chan *v1.Service, st store.RStore) {
+func (w *ServiceWatcher) dispatchServiceChangesLoop(ctx context.Context, kCli k8s.Client, ch <-chan *v1.Service, st store.RStore) {
 	for {
 		select {
 		case service, ok := <-ch:
@@ -126,7 +139,7 @@ func (w *ServiceWatcher) dispatchServiceChangesLoop(ctx context.Context, ch <-ch
 				continue
 			}
 
-			err := DispatchServiceChange(st, service, manifestName, w.kCli.NodeIP(ctx))
+			err := DispatchServiceChange(st, service, manifestName, kCli.NodeIP(ctx))
 			if err != nil {
 				logger.Get(ctx).Infof("error resolving service url %s: %v", service.Name, err)
 			}
@@ -120,7 +120,7 @@ func newSWFixture(t *testing.T) *swFixture {
 	ctx, cancel := context.WithCancel(ctx)
 
 	of := k8s.ProvideOwnerFetcher(kClient)
-	sw := NewServiceWatcher(kClient, of)
+	sw := NewServiceWatcher(k8s.ProvideClientPool("fake-context", kClient, of))
 	st := store.NewTestingStore()
 
 	return &swFixture{
@@ -0,0 +1,135 @@
+// Package linkhealth probes the HTTP health checks declared on resource
+// links (link() in the Tiltfile), so the UIs can show per-link up/down
+// state and required links can gate resource readiness.
+package linkhealth
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/windmilleng/tilt/internal/build"
+	"github.com/windmilleng/tilt/internal/cloud"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// How often we probe the declared health check URLs.
+const checkPeriod = 10 * time.Second
+
+type LinkHealthAction struct {
+	ManifestName model.ManifestName
+
+	// Result for each probed link, keyed by the link's URL.
+	Health map[string]bool
+}
+
+func (LinkHealthAction) Action() {}
+
+type Controller struct {
+	client    cloud.HttpClient
+	clock     build.Clock
+	lastCheck time.Time
+}
+
+func NewController(client cloud.HttpClient, clock build.Clock) *Controller {
+	return &Controller{
+		client: client,
+		clock:  clock,
+	}
+}
+
+type checkTarget struct {
+	name  model.ManifestName
+	links []model.Link
+
+	// What the manifest state currently records, so we only dispatch on
+	// change.
+	health map[string]bool
+}
+
+func (c *Controller) OnChange(ctx context.Context, st store.RStore) {
+	if !c.lastCheck.Add(checkPeriod).Before(c.clock.Now()) {
+		return
+	}
+	c.lastCheck = c.clock.Now()
+
+	for _, target := range c.diff(st) {
+		health := c.probe(ctx, target.links)
+		if healthEqual(health, target.health) {
+			continue
+		}
+		st.Dispatch(LinkHealthAction{ManifestName: target.name, Health: health})
+	}
+}
+
+func (c *Controller) diff(st store.RStore) []checkTarget {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	var targets []checkTarget
+	for _, mt := range state.Targets() {
+		var links []model.Link
+		for _, l := range mt.Manifest.Links {
+			if l.HasHealthCheck() {
+				links = append(links, l)
+			}
+		}
+		if len(links) == 0 {
+			continue
+		}
+
+		health := make(map[string]bool, len(links))
+		for _, l := range links {
+			if up, ok := mt.State.LinkHealth[l.URL]; ok {
+				health[l.URL] = up
+			}
+		}
+
+		targets = append(targets, checkTarget{
+			name:   mt.Manifest.Name,
+			links:  links,
+			health: health,
+		})
+	}
+	return targets
+}
+
+func (c *Controller) probe(ctx context.Context, links []model.Link) map[string]bool {
+	health := make(map[string]bool, len(links))
+	for _, l := range links {
+		health[l.URL] = c.probeOne(ctx, l)
+	}
+	return health
+}
+
+func (c *Controller) probeOne(ctx context.Context, l model.Link) bool {
+	req, err := http.NewRequest(http.MethodGet, l.HealthCheckURL, nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return false
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	return resp.StatusCode == l.HealthCheckExpectedStatus
+}
+
+func healthEqual(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+var _ store.Subscriber = &Controller{}
@@ -0,0 +1,157 @@
+package linkhealth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils"
+	"github.com/windmilleng/tilt/internal/testutils/httptest"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestLinkUp(t *testing.T) {
+	f := newFixture(t)
+
+	f.addManifest("fe", model.Link{
+		URL:                       "http://localhost:8000/",
+		HealthCheckURL:            "http://localhost:8000/healthz",
+		HealthCheckExpectedStatus: 200,
+	})
+	f.client.SetResponse("ok")
+
+	f.onChange()
+
+	action := f.lastHealthAction()
+	assert.Equal(t, model.ManifestName("fe"), action.ManifestName)
+	assert.Equal(t, map[string]bool{"http://localhost:8000/": true}, action.Health)
+
+	requests := f.client.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "http://localhost:8000/healthz", requests[0].URL.String())
+}
+
+func TestLinkDown(t *testing.T) {
+	f := newFixture(t)
+
+	f.addManifest("fe", model.Link{
+		URL:                       "http://localhost:8000/",
+		HealthCheckURL:            "http://localhost:8000/healthz",
+		HealthCheckExpectedStatus: 200,
+	})
+	// The fake client answers 500 until given a response.
+
+	f.onChange()
+
+	action := f.lastHealthAction()
+	assert.Equal(t, map[string]bool{"http://localhost:8000/": false}, action.Health)
+}
+
+func TestNoDispatchWhenUnchanged(t *testing.T) {
+	f := newFixture(t)
+
+	f.addManifest("fe", model.Link{
+		URL:                       "http://localhost:8000/",
+		HealthCheckURL:            "http://localhost:8000/healthz",
+		HealthCheckExpectedStatus: 200,
+	})
+	f.client.SetResponse("ok")
+	f.st.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["fe"].State.LinkHealth = map[string]bool{"http://localhost:8000/": true}
+	})
+
+	f.onChange()
+	assert.Empty(t, f.healthActions())
+}
+
+func TestLinksWithoutHealthChecksIgnored(t *testing.T) {
+	f := newFixture(t)
+
+	f.addManifest("fe", model.Link{URL: "http://localhost:8000/"})
+
+	f.onChange()
+	assert.Empty(t, f.healthActions())
+	assert.Empty(t, f.client.Requests())
+}
+
+func TestCheckThrottled(t *testing.T) {
+	f := newFixture(t)
+
+	f.addManifest("fe", model.Link{
+		URL:                       "http://localhost:8000/",
+		HealthCheckURL:            "http://localhost:8000/healthz",
+		HealthCheckExpectedStatus: 200,
+	})
+
+	f.onChange()
+	require.Len(t, f.client.Requests(), 1)
+
+	// Not enough time has passed for another probe.
+	f.c.OnChange(f.ctx, f.st)
+	assert.Len(t, f.client.Requests(), 1)
+}
+
+type fixture struct {
+	t      *testing.T
+	ctx    context.Context
+	st     *store.TestingStore
+	client *httptest.FakeClient
+	clock  fakeClock
+	c      *Controller
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c fakeClock) Now() time.Time { return c.now }
+
+func newFixture(t *testing.T) *fixture {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+
+	client := httptest.NewFakeClient()
+	clock := fakeClock{now: time.Unix(1551202573, 0)}
+
+	return &fixture{
+		t:      t,
+		ctx:    ctx,
+		st:     store.NewTestingStore(),
+		client: client,
+		clock:  clock,
+		c:      NewController(client, clock),
+	}
+}
+
+func (f *fixture) addManifest(name model.ManifestName, links ...model.Link) {
+	f.st.WithState(func(state *store.EngineState) {
+		m := model.Manifest{Name: name, Links: links}
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+	})
+}
+
+// Advances past the check period and runs the controller.
+func (f *fixture) onChange() {
+	f.clock.now = f.clock.now.Add(2 * checkPeriod)
+	f.c.clock = f.clock
+	f.c.OnChange(f.ctx, f.st)
+}
+
+func (f *fixture) healthActions() []LinkHealthAction {
+	var actions []LinkHealthAction
+	for _, a := range f.st.Actions() {
+		if lha, ok := a.(LinkHealthAction); ok {
+			actions = append(actions, lha)
+		}
+	}
+	return actions
+}
+
+func (f *fixture) lastHealthAction() LinkHealthAction {
+	actions := f.healthActions()
+	require.NotEmpty(f.t, actions)
+	return actions[len(actions)-1]
+}
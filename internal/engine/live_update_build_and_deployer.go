@@ -19,7 +19,6 @@ import (
 
 	"github.com/windmilleng/tilt/internal/build"
 	"github.com/windmilleng/tilt/internal/ignore"
-	"github.com/windmilleng/tilt/internal/k8s"
 	"github.com/windmilleng/tilt/internal/store"
 	"github.com/windmilleng/tilt/pkg/logger"
 	"github.com/windmilleng/tilt/pkg/model"
@@ -28,26 +27,29 @@ import (
 var _ BuildAndDeployer = &LiveUpdateBuildAndDeployer{}
 
 type LiveUpdateBuildAndDeployer struct {
-	dcu     *containerupdate.DockerContainerUpdater
-	scu     *containerupdate.SyncletUpdater
-	ecu     *containerupdate.ExecUpdater
-	updMode buildcontrol.UpdateMode
-	env     k8s.Env
-	runtime container.Runtime
-	clock   build.Clock
+	dcu          *containerupdate.DockerContainerUpdater
+	scu          *containerupdate.SyncletUpdater
+	ecu          *containerupdate.ExecUpdater
+	csu          *containerupdate.CopySidecarUpdater
+	updMode      buildcontrol.UpdateMode
+	sharesDocker buildcontrol.ClusterSharesDocker
+	runtime      container.Runtime
+	clock        build.Clock
 }
 
 func NewLiveUpdateBuildAndDeployer(dcu *containerupdate.DockerContainerUpdater,
 	scu *containerupdate.SyncletUpdater, ecu *containerupdate.ExecUpdater,
-	updMode buildcontrol.UpdateMode, env k8s.Env, runtime container.Runtime, c build.Clock) *LiveUpdateBuildAndDeployer {
+	csu *containerupdate.CopySidecarUpdater,
+	updMode buildcontrol.UpdateMode, sharesDocker buildcontrol.ClusterSharesDocker, runtime container.Runtime, c build.Clock) *LiveUpdateBuildAndDeployer {
 	return &LiveUpdateBuildAndDeployer{
-		dcu:     dcu,
-		scu:     scu,
-		ecu:     ecu,
-		updMode: updMode,
-		env:     env,
-		runtime: runtime,
-		clock:   c,
+		dcu:          dcu,
+		scu:          scu,
+		ecu:          ecu,
+		csu:          csu,
+		updMode:      updMode,
+		sharesDocker: sharesDocker,
+		runtime:      runtime,
+		clock:        c,
 	}
 }
 
@@ -264,11 +266,15 @@ func (lubad *LiveUpdateBuildAndDeployer) containerUpdaterForSpecs(specs []model.
 		return lubad.ecu
 	}
 
-	if shouldUseSynclet(lubad.updMode, lubad.env, lubad.runtime) {
+	if lubad.updMode == buildcontrol.UpdateModeCopySidecar {
+		return lubad.csu
+	}
+
+	if shouldUseSynclet(lubad.updMode, lubad.sharesDocker, lubad.runtime) {
 		return lubad.scu
 	}
 
-	if lubad.runtime == container.RuntimeDocker && lubad.env.UsesLocalDockerRegistry() {
+	if lubad.runtime == container.RuntimeDocker && bool(lubad.sharesDocker) {
 		return lubad.dcu
 	}
 
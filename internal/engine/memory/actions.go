@@ -0,0 +1,10 @@
+package memory
+
+// Reports that Tilt is over its memory limit, so reducers should shed
+// whatever state they can.
+type MemoryPressureAction struct {
+	Usage uint64
+	Limit int64
+}
+
+func (MemoryPressureAction) Action() {}
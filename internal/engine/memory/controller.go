@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	"github.com/docker/go-units"
+
+	"github.com/windmilleng/tilt/internal/engine/k8swatch"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/logger"
+)
+
+// How often to sample memory usage. Reading memory stats briefly stops
+// the world, so we don't want to do it on every store change.
+const checkInterval = 10 * time.Second
+
+// How long to wait between rounds of shedding, so one round has a chance
+// to be garbage-collected before we decide we need another.
+const shedInterval = time.Minute
+
+// The maximum bytes of heap Tilt may use before shedding memory.
+// The zero value means no limit.
+type Limit int64
+
+// Watches Tilt's own memory usage against the --max-memory limit.
+//
+// When we're over, shed load predictably — truncate the log store, drop old
+// build history, prune the watch caches — and warn the user, rather than
+// growing until the kernel OOM-kills us. Intended for resource-constrained
+// environments like devcontainers.
+type Controller struct {
+	limit Limit
+	pw    *k8swatch.PodWatcher
+	ewm   *k8swatch.EventWatchManager
+
+	clock     func() time.Time
+	heapBytes func() uint64
+
+	lastCheck time.Time
+	lastShed  time.Time
+}
+
+func NewController(limit Limit, pw *k8swatch.PodWatcher, ewm *k8swatch.EventWatchManager) *Controller {
+	return &Controller{
+		limit:     limit,
+		pw:        pw,
+		ewm:       ewm,
+		clock:     time.Now,
+		heapBytes: liveHeapBytes,
+	}
+}
+
+var _ store.Subscriber = &Controller{}
+
+func (c *Controller) OnChange(ctx context.Context, st store.RStore) {
+	if c.limit <= 0 {
+		return
+	}
+
+	now := c.clock()
+	if now.Sub(c.lastCheck) < checkInterval {
+		return
+	}
+	c.lastCheck = now
+
+	usage := c.heapBytes()
+	if usage <= uint64(c.limit) {
+		return
+	}
+
+	if now.Sub(c.lastShed) < shedInterval {
+		return
+	}
+	c.lastShed = now
+
+	logger.Get(ctx).Warnf(
+		"Tilt is using %s of memory, over the --max-memory limit of %s. Dropping old logs, build history, and watch caches.",
+		units.BytesSize(float64(usage)), units.BytesSize(float64(c.limit)))
+
+	c.pw.PruneCache()
+	c.ewm.PruneCache()
+
+	st.Dispatch(MemoryPressureAction{Usage: usage, Limit: int64(c.limit)})
+}
+
+func liveHeapBytes() uint64 {
+	stats := &runtime.MemStats{}
+	runtime.ReadMemStats(stats)
+	return stats.HeapAlloc
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/windmilleng/tilt/internal/engine/k8swatch"
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils"
+)
+
+func TestNoLimitNeverSheds(t *testing.T) {
+	f := newFixture(t, 0)
+
+	f.heap = 500 * 1000 * 1000
+	f.c.OnChange(f.ctx, f.st)
+	assert.Equal(t, 0, f.pressureCount())
+}
+
+func TestUnderLimitNoOp(t *testing.T) {
+	f := newFixture(t, 100*1000*1000)
+
+	f.heap = 50 * 1000 * 1000
+	f.c.OnChange(f.ctx, f.st)
+	assert.Equal(t, 0, f.pressureCount())
+}
+
+func TestOverLimitSheds(t *testing.T) {
+	f := newFixture(t, 100*1000*1000)
+
+	f.heap = 150 * 1000 * 1000
+	f.c.OnChange(f.ctx, f.st)
+	assert.Equal(t, 1, f.pressureCount())
+
+	// Another check right away is throttled.
+	f.now = f.now.Add(checkInterval)
+	f.c.OnChange(f.ctx, f.st)
+	assert.Equal(t, 1, f.pressureCount(), "should wait out the shed interval")
+
+	// Still over the limit after the shed interval: shed again.
+	f.now = f.now.Add(shedInterval)
+	f.c.OnChange(f.ctx, f.st)
+	assert.Equal(t, 2, f.pressureCount())
+}
+
+func TestChecksAreThrottled(t *testing.T) {
+	f := newFixture(t, 100*1000*1000)
+
+	f.heap = 150 * 1000 * 1000
+	f.c.OnChange(f.ctx, f.st)
+	assert.Equal(t, 1, f.pressureCount())
+
+	// The next store change arrives before the check interval has elapsed,
+	// so we shouldn't even sample memory.
+	f.heap = 999 * 1000 * 1000
+	f.now = f.now.Add(checkInterval / 2)
+	f.c.OnChange(f.ctx, f.st)
+	assert.Equal(t, 1, f.pressureCount())
+}
+
+type fixture struct {
+	t    *testing.T
+	ctx  context.Context
+	st   *store.TestingStore
+	c    *Controller
+	now  time.Time
+	heap uint64
+}
+
+func newFixture(t *testing.T, limit Limit) *fixture {
+	kCli := k8s.NewFakeK8sClient()
+	of := k8s.ProvideOwnerFetcher(kCli)
+	pw := k8swatch.NewPodWatcher(k8s.ProvideClientPool("fake-context", kCli, of))
+	ewm := k8swatch.NewEventWatchManager(kCli, of)
+
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+
+	f := &fixture{
+		t:   t,
+		ctx: ctx,
+		st:  store.NewTestingStore(),
+		c:   NewController(limit, pw, ewm),
+		now: time.Now(),
+	}
+	f.c.clock = func() time.Time { return f.now }
+	f.c.heapBytes = func() uint64 { return f.heap }
+
+	// The first check shouldn't be throttled.
+	f.c.lastCheck = f.now.Add(-checkInterval)
+	f.c.lastShed = f.now.Add(-shedInterval)
+	return f
+}
+
+func (f *fixture) pressureCount() int {
+	count := 0
+	for _, a := range f.st.Actions() {
+		if _, ok := a.(MemoryPressureAction); ok {
+			count++
+		}
+	}
+	return count
+}
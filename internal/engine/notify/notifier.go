@@ -0,0 +1,9 @@
+package notify
+
+import "context"
+
+// Shows a native OS notification. Implementations live in the
+// platform-specific notifier_*.go files.
+type Notifier interface {
+	Notify(ctx context.Context, title string, message string) error
+}
@@ -0,0 +1,28 @@
+// +build darwin
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Posts to the macOS notification center via osascript, which ships
+// with the OS.
+type darwinNotifier struct{}
+
+func ProvideNotifier() Notifier {
+	return darwinNotifier{}
+}
+
+func (darwinNotifier) Notify(ctx context.Context, title string, message string) error {
+	script := fmt.Sprintf("display notification %q with title %q",
+		escapeAppleScript(message), escapeAppleScript(title))
+	return exec.CommandContext(ctx, "osascript", "-e", script).Run()
+}
+
+func escapeAppleScript(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
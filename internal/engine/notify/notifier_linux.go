@@ -0,0 +1,24 @@
+// +build linux
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// Uses notify-send (libnotify), available on most desktop Linux
+// distributions.
+type linuxNotifier struct{}
+
+func ProvideNotifier() Notifier {
+	return linuxNotifier{}
+}
+
+func (linuxNotifier) Notify(ctx context.Context, title string, message string) error {
+	if _, err := exec.LookPath("notify-send"); err != nil {
+		return fmt.Errorf("desktop notifications require notify-send (libnotify): %v", err)
+	}
+	return exec.CommandContext(ctx, "notify-send", title, message).Run()
+}
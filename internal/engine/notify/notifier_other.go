@@ -0,0 +1,22 @@
+// +build !darwin,!linux
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+// TODO: Windows toast notifications need either a vendored
+// toast library or a powershell shim; until then, desktop notifications
+// are a no-op off macOS and Linux.
+type unsupportedNotifier struct{}
+
+func ProvideNotifier() Notifier {
+	return unsupportedNotifier{}
+}
+
+func (unsupportedNotifier) Notify(ctx context.Context, title string, message string) error {
+	return fmt.Errorf("desktop notifications aren't supported on %s", runtime.GOOS)
+}
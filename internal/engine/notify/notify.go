@@ -0,0 +1,158 @@
+// Package notify POSTs JSON payloads to a user-configured webhook when
+// key lifecycle events happen (builds finishing, resources becoming
+// ready, crashes), for Slack-style notifications and custom automation.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/windmilleng/tilt/internal/cloud"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/logger"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// The payload POSTed to the webhook for each event.
+type Payload struct {
+	Event    model.NotifyEvent `json:"event"`
+	Resource string            `json:"resource"`
+	Time     time.Time         `json:"time"`
+	Error    string            `json:"error,omitempty"`
+}
+
+type Controller struct {
+	client   cloud.HttpClient
+	notifier Notifier
+
+	startTime time.Time
+
+	notifiedBuild map[model.ManifestName]time.Time
+	ready         map[model.ManifestName]bool
+	crashing      map[model.ManifestName]bool
+}
+
+func NewController(client cloud.HttpClient, notifier Notifier) *Controller {
+	return &Controller{
+		client:        client,
+		notifier:      notifier,
+		startTime:     time.Now(),
+		notifiedBuild: make(map[model.ManifestName]time.Time),
+		ready:         make(map[model.ManifestName]bool),
+		crashing:      make(map[model.ManifestName]bool),
+	}
+}
+
+var _ store.Subscriber = &Controller{}
+
+func (c *Controller) OnChange(ctx context.Context, st store.RStore) {
+	settings, payloads := c.diff(st)
+	for _, p := range payloads {
+		if settings.WebhookURL != "" {
+			c.post(ctx, settings.WebhookURL, p)
+		}
+		if settings.Desktop {
+			c.notifyDesktop(ctx, p)
+		}
+	}
+}
+
+func (c *Controller) diff(st store.RStore) (model.NotifySettings, []Payload) {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	settings := state.NotifySettings
+	notifying := settings.WebhookURL != "" || settings.Desktop
+	var payloads []Payload
+	record := func(event model.NotifyEvent, name model.ManifestName, t time.Time, errMsg string) {
+		if !notifying || !settings.EventSelected(event) {
+			return
+		}
+		payloads = append(payloads, Payload{Event: event, Resource: name.String(), Time: t, Error: errMsg})
+	}
+
+	for _, mt := range state.Targets() {
+		name := mt.Manifest.Name
+		ms := mt.State
+
+		lastBuild := ms.LastBuild()
+		if !lastBuild.FinishTime.IsZero() && c.notifiedBuild[name] != lastBuild.FinishTime {
+			c.notifiedBuild[name] = lastBuild.FinishTime
+
+			// Don't re-announce builds from before this session (e.g.,
+			// restored from the .tilt/ dir).
+			if lastBuild.FinishTime.After(c.startTime) {
+				if lastBuild.Error != nil {
+					record(model.NotifyEventBuildFailed, name, lastBuild.FinishTime, lastBuild.Error.Error())
+				} else {
+					record(model.NotifyEventBuildSucceeded, name, lastBuild.FinishTime, "")
+				}
+			}
+		}
+
+		ready := ms.RuntimeState != nil && ms.RuntimeState.HasEverBeenReadyOrSucceeded()
+		if ready && !c.ready[name] {
+			c.ready[name] = true
+			record(model.NotifyEventResourceReady, name, time.Now(), "")
+		}
+
+		if ms.NeedsRebuildFromCrash && !c.crashing[name] {
+			record(model.NotifyEventCrash, name, time.Now(), ms.CrashLog.String())
+		}
+		c.crashing[name] = ms.NeedsRebuildFromCrash
+	}
+
+	return settings, payloads
+}
+
+// Successful builds are too chatty for desktop notifications; only
+// surface breakage and the moment a resource first comes up.
+func (c *Controller) notifyDesktop(ctx context.Context, p Payload) {
+	var message string
+	switch p.Event {
+	case model.NotifyEventBuildFailed:
+		message = fmt.Sprintf("%s: build failed", p.Resource)
+	case model.NotifyEventResourceReady:
+		message = fmt.Sprintf("%s is ready", p.Resource)
+	case model.NotifyEventCrash:
+		message = fmt.Sprintf("%s crashed", p.Resource)
+	default:
+		return
+	}
+
+	if err := c.notifier.Notify(ctx, "Tilt", message); err != nil {
+		logger.Get(ctx).Debugf("Error showing desktop notification: %v", err)
+	}
+}
+
+func (c *Controller) post(ctx context.Context, url string, p Payload) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		logger.Get(ctx).Debugf("Error marshaling notify payload: %v", err)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		logger.Get(ctx).Debugf("Error creating notify request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		logger.Get(ctx).Infof("Error notifying webhook of %s: %v", p.Event, err)
+		return
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 400 {
+		logger.Get(ctx).Infof("Webhook returned status %d for %s event", resp.StatusCode, p.Event)
+	}
+}
@@ -0,0 +1,203 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/internal/k8s/testyaml"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils"
+	"github.com/windmilleng/tilt/internal/testutils/httptest"
+	"github.com/windmilleng/tilt/internal/testutils/manifestbuilder"
+	"github.com/windmilleng/tilt/internal/testutils/tempdir"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+const webhookURL = "https://hooks.example.com/abc"
+
+func TestNotifyBuildFailed(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.addManifest("fe")
+	f.completeBuild("fe", fmt.Errorf("compile error"))
+
+	f.c.OnChange(f.ctx, f.st)
+	f.c.OnChange(f.ctx, f.st)
+
+	payloads := f.payloads()
+	require.Len(t, payloads, 1, "the same build shouldn't be announced twice")
+	assert.Equal(t, model.NotifyEventBuildFailed, payloads[0].Event)
+	assert.Equal(t, "fe", payloads[0].Resource)
+	assert.Equal(t, "compile error", payloads[0].Error)
+}
+
+func TestNotifyBuildSucceeded(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.addManifest("fe")
+	f.completeBuild("fe", nil)
+
+	f.c.OnChange(f.ctx, f.st)
+
+	payloads := f.payloads()
+	require.Len(t, payloads, 1)
+	assert.Equal(t, model.NotifyEventBuildSucceeded, payloads[0].Event)
+}
+
+func TestNotifyResourceReady(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.addManifest("fe")
+	f.st.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["fe"].State.RuntimeState = store.LocalRuntimeState{HasSucceededAtLeastOnce: true}
+	})
+
+	f.c.OnChange(f.ctx, f.st)
+	f.c.OnChange(f.ctx, f.st)
+
+	payloads := f.payloads()
+	require.Len(t, payloads, 1, "readiness should only be announced on the transition")
+	assert.Equal(t, model.NotifyEventResourceReady, payloads[0].Event)
+}
+
+func TestNotifyEventFilter(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.st.WithState(func(state *store.EngineState) {
+		state.NotifySettings.Events = []model.NotifyEvent{model.NotifyEventResourceReady}
+	})
+	f.addManifest("fe")
+	f.completeBuild("fe", nil)
+
+	f.c.OnChange(f.ctx, f.st)
+
+	assert.Empty(t, f.payloads())
+}
+
+func TestDesktopNotifications(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.st.WithState(func(state *store.EngineState) {
+		state.NotifySettings = model.NotifySettings{Desktop: true}
+	})
+	f.addManifest("fe")
+	f.completeBuild("fe", fmt.Errorf("compile error"))
+
+	f.c.OnChange(f.ctx, f.st)
+
+	assert.Empty(t, f.client.Requests(), "no webhook configured")
+	assert.Equal(t, []string{"fe: build failed"}, f.notifier.messages)
+}
+
+func TestDesktopSkipsSuccessfulBuilds(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.st.WithState(func(state *store.EngineState) {
+		state.NotifySettings = model.NotifySettings{Desktop: true}
+	})
+	f.addManifest("fe")
+	f.completeBuild("fe", nil)
+
+	f.c.OnChange(f.ctx, f.st)
+
+	assert.Empty(t, f.notifier.messages)
+}
+
+func TestNotifyDisabled(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.st.WithState(func(state *store.EngineState) {
+		state.NotifySettings = model.NotifySettings{}
+	})
+	f.addManifest("fe")
+	f.completeBuild("fe", nil)
+
+	f.c.OnChange(f.ctx, f.st)
+
+	assert.Empty(t, f.payloads())
+}
+
+type fixture struct {
+	*tempdir.TempDirFixture
+	ctx      context.Context
+	st       *store.TestingStore
+	client   *httptest.FakeClient
+	notifier *fakeNotifier
+	c        *Controller
+}
+
+type fakeNotifier struct {
+	messages []string
+}
+
+func (fn *fakeNotifier) Notify(ctx context.Context, title string, message string) error {
+	fn.messages = append(fn.messages, message)
+	return nil
+}
+
+var _ Notifier = &fakeNotifier{}
+
+func newFixture(t *testing.T) *fixture {
+	f := tempdir.NewTempDirFixture(t)
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+
+	client := httptest.NewFakeClient()
+	client.SetResponse("ok")
+	notifier := &fakeNotifier{}
+
+	st := store.NewTestingStore()
+	st.WithState(func(state *store.EngineState) {
+		state.NotifySettings.WebhookURL = webhookURL
+	})
+
+	return &fixture{
+		TempDirFixture: f,
+		ctx:            ctx,
+		st:             st,
+		client:         client,
+		notifier:       notifier,
+		c:              NewController(client, notifier),
+	}
+}
+
+func (f *fixture) addManifest(name model.ManifestName) {
+	f.st.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, name).WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+	})
+}
+
+func (f *fixture) completeBuild(name model.ManifestName, buildErr error) {
+	finish := time.Now().Add(time.Second)
+	f.st.WithState(func(state *store.EngineState) {
+		state.ManifestTargets[name].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  finish.Add(-2 * time.Second),
+			FinishTime: finish,
+			Error:      buildErr,
+		})
+	})
+}
+
+func (f *fixture) payloads() []Payload {
+	var payloads []Payload
+	for _, req := range f.client.Requests() {
+		require.Equal(f.T(), webhookURL, req.URL.String())
+		var p Payload
+		require.NoError(f.T(), json.NewDecoder(req.Body).Decode(&p))
+		payloads = append(payloads, p)
+	}
+	return payloads
+}
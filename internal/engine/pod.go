@@ -7,9 +7,11 @@ import (
 
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/windmilleng/tilt/internal/container"
 	"github.com/windmilleng/tilt/internal/engine/k8swatch"
+	"github.com/windmilleng/tilt/internal/engine/podmetrics"
 	"github.com/windmilleng/tilt/internal/engine/runtimelog"
 	"github.com/windmilleng/tilt/internal/k8s"
 	"github.com/windmilleng/tilt/internal/store"
@@ -53,12 +55,14 @@ func handlePodChangeAction(ctx context.Context, state *store.EngineState, action
 	podInfo.Phase = pod.Status.Phase
 	podInfo.StatusMessages = k8swatch.PodStatusErrorMessages(*pod)
 	podInfo.Conditions = pod.Status.Conditions
+	podInfo.CPULimit, podInfo.MemoryLimit = podLimits(pod)
 
 	prunePods(ms)
 
 	oldRestartTotal := podInfo.AllContainerRestarts()
 	podInfo.Containers = podContainers(ctx, pod, pod.Status.ContainerStatuses)
 	podInfo.InitContainers = podContainers(ctx, pod, pod.Status.InitContainerStatuses)
+	podInfo.PruneProbeWarnings()
 	if isNew {
 		// This is the first time we've seen this pod.
 		// Ignore any restarts that happened before Tilt saw it.
@@ -74,7 +78,13 @@ func handlePodChangeAction(ctx context.Context, state *store.EngineState, action
 		return
 	}
 
-	if podInfo.AllContainersReady() || podInfo.Phase == v1.PodSucceeded {
+	ready := podInfo.AllContainersReady() || podInfo.Phase == v1.PodSucceeded
+	if ms.K8sRuntimeState().TracksEveryPod() {
+		// One ready pod isn't enough for a StatefulSet or DaemonSet;
+		// wait for the whole set.
+		ready = ms.K8sRuntimeState().RuntimeStatus() == model.RuntimeStatusOK
+	}
+	if ready {
 		runtime := ms.K8sRuntimeState()
 		runtime.LastReadyOrSucceededTime = time.Now()
 		ms.RuntimeState = runtime
@@ -218,14 +228,15 @@ func containerForStatus(ctx context.Context, pod *v1.Pod, cStatus v1.ContainerSt
 	}
 
 	return store.Container{
-		Name:     cName,
-		ID:       cID,
-		Ports:    ports,
-		Ready:    cStatus.Ready,
-		Running:  isRunning,
-		ImageRef: cRef,
-		Restarts: int(cStatus.RestartCount),
-		Status:   k8swatch.ContainerStatusToRuntimeState(cStatus),
+		Name:         cName,
+		ID:           cID,
+		Ports:        ports,
+		Ready:        cStatus.Ready,
+		Running:      isRunning,
+		ImageRef:     cRef,
+		Restarts:     int(cStatus.RestartCount),
+		Status:       k8swatch.ContainerStatusToRuntimeState(cStatus),
+		MeshInjected: k8s.IsMeshSidecar(cName),
 	}, nil
 }
 
@@ -277,6 +288,11 @@ func prunePods(ms *store.ManifestState) {
 			delete(runtime.Pods, key)
 		}
 	}
+	// For run-to-completion resources, terminated pods are the interesting
+	// ones: keep every attempt so its status and logs stay visible.
+	if len(runtime.Jobs) > 0 {
+		return
+	}
 	// Continue pruning until we have 1 pod.
 	for runtime.PodLen() > 1 {
 		bestPod := ms.MostRecentPod()
@@ -299,6 +315,49 @@ func prunePods(ms *store.ManifestState) {
 	}
 }
 
+// Resource limits summed across the pod's containers, for usage warnings.
+func podLimits(pod *v1.Pod) (cpu resource.Quantity, memory resource.Quantity) {
+	for _, c := range pod.Spec.Containers {
+		if q, ok := c.Resources.Limits[v1.ResourceCPU]; ok {
+			cpu.Add(q)
+		}
+		if q, ok := c.Resources.Limits[v1.ResourceMemory]; ok {
+			memory.Add(q)
+		}
+	}
+	return cpu, memory
+}
+
+func handlePodMetricsAction(state *store.EngineState, action podmetrics.PodMetricsAction) {
+	samples := make(map[k8s.PodID]k8s.PodMetrics, len(action.Metrics))
+	for _, m := range action.Metrics {
+		samples[m.PodID] = m
+	}
+
+	for _, mt := range state.Targets() {
+		ms := mt.State
+		runtime := ms.K8sRuntimeState()
+		for podID, podInfo := range runtime.Pods {
+			m, ok := samples[podID]
+			if !ok {
+				continue
+			}
+
+			oldPct := podInfo.MemoryPctOfLimit()
+			podInfo.Usage = store.PodUsage{CPU: m.CPU(), Memory: m.Memory()}
+
+			// Warn once when usage crosses the threshold, so the pod's
+			// log says why it got OOM-killed if it does.
+			newPct := podInfo.MemoryPctOfLimit()
+			if newPct >= store.MemoryUsageWarningPct && oldPct < store.MemoryUsageWarningPct {
+				msg := fmt.Sprintf("Pod %s is using %d%% of its memory limit; it's at risk of being OOM-killed.\n",
+					podID, newPct)
+				handleLogAction(state, store.NewLogAction(ms.Name, podInfo.SpanID, logger.WarnLvl, nil, []byte(msg)))
+			}
+		}
+	}
+}
+
 func handlePodResetRestartsAction(state *store.EngineState, action store.PodResetRestartsAction) {
 	ms, ok := state.ManifestState(action.ManifestName)
 	if !ok {
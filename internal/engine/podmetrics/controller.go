@@ -0,0 +1,106 @@
+// Package podmetrics polls metrics-server (the metrics.k8s.io API) for the
+// CPU/memory usage of tracked pods, so the UIs can show live usage per
+// resource and warn when a pod is closing in on its memory limit.
+package podmetrics
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/windmilleng/tilt/internal/build"
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/logger"
+)
+
+// How often we poll for new usage samples. Metrics-server itself only
+// refreshes every 15-60s, so polling faster buys nothing.
+const pollPeriod = 15 * time.Second
+
+// When the metrics API isn't being served, back off and check again
+// occasionally in case metrics-server gets installed mid-session.
+const unavailableRecheckPeriod = 5 * time.Minute
+
+type PodMetricsAction struct {
+	Metrics []k8s.PodMetrics
+}
+
+func (PodMetricsAction) Action() {}
+
+type Controller struct {
+	kClient k8s.Client
+	clock   build.Clock
+
+	lastPoll         time.Time
+	unavailableUntil time.Time
+}
+
+func NewController(kClient k8s.Client, clock build.Clock) *Controller {
+	return &Controller{
+		kClient: kClient,
+		clock:   clock,
+	}
+}
+
+func (c *Controller) OnChange(ctx context.Context, st store.RStore) {
+	now := c.clock.Now()
+	if now.Before(c.lastPoll.Add(pollPeriod)) || now.Before(c.unavailableUntil) {
+		return
+	}
+
+	namespaces := c.diff(st)
+	if len(namespaces) == 0 {
+		return
+	}
+	c.lastPoll = now
+
+	var metrics []k8s.PodMetrics
+	for _, ns := range namespaces {
+		m, err := c.kClient.PodMetrics(ctx, ns, k8s.ManagedByTiltSelector())
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// The cluster doesn't run metrics-server.
+				c.unavailableUntil = now.Add(unavailableRecheckPeriod)
+				return
+			}
+
+			// Usage display is best-effort; don't spam the log over a
+			// missed sample.
+			logger.Get(ctx).Debugf("Error fetching pod metrics: %v", err)
+			return
+		}
+		metrics = append(metrics, m...)
+	}
+
+	if len(metrics) == 0 {
+		return
+	}
+	st.Dispatch(PodMetricsAction{Metrics: metrics})
+}
+
+// The namespaces of all the pods we're tracking.
+func (c *Controller) diff(st store.RStore) []k8s.Namespace {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	if !state.EngineMode.WatchesRuntime() {
+		return nil
+	}
+
+	seen := make(map[k8s.Namespace]bool)
+	var namespaces []k8s.Namespace
+	for _, mt := range state.Targets() {
+		for _, pod := range mt.State.K8sRuntimeState().PodList() {
+			if pod.PodID == "" || seen[pod.Namespace] {
+				continue
+			}
+			seen[pod.Namespace] = true
+			namespaces = append(namespaces, pod.Namespace)
+		}
+	}
+	return namespaces
+}
+
+var _ store.Subscriber = &Controller{}
@@ -0,0 +1,150 @@
+package podmetrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils"
+	"github.com/windmilleng/tilt/internal/testutils/manifestutils"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestPollDispatchesMetrics(t *testing.T) {
+	f := newFixture(t)
+
+	f.addManifestWithPod("fe", "fe-pod")
+	f.kClient.PodMetricsList = []k8s.PodMetrics{
+		{
+			PodID: "fe-pod",
+			Containers: []k8s.ContainerMetrics{
+				{Name: "fe", CPU: resource.MustParse("12m"), Memory: resource.MustParse("34Mi")},
+			},
+		},
+	}
+
+	f.onChange()
+
+	actions := f.metricsActions()
+	require.Len(t, actions, 1)
+	require.Len(t, actions[0].Metrics, 1)
+	assert.Equal(t, k8s.PodID("fe-pod"), actions[0].Metrics[0].PodID)
+	cpu := actions[0].Metrics[0].CPU()
+	assert.Equal(t, int64(12), cpu.MilliValue())
+}
+
+func TestNoPodsNoPoll(t *testing.T) {
+	f := newFixture(t)
+
+	f.st.WithState(func(state *store.EngineState) {
+		m := model.Manifest{Name: "fe"}.WithDeployTarget(model.K8sTarget{Name: "fe"})
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+	})
+
+	f.onChange()
+	assert.Empty(t, f.metricsActions())
+}
+
+func TestMetricsServerUnavailable(t *testing.T) {
+	f := newFixture(t)
+
+	f.addManifestWithPod("fe", "fe-pod")
+	f.kClient.PodMetricsError = apierrors.NewNotFound(
+		schema.GroupResource{Group: "metrics.k8s.io", Resource: "pods"}, "")
+
+	f.onChange()
+	assert.Empty(t, f.metricsActions())
+
+	// The API isn't there; don't keep asking for a while, even once it
+	// would answer.
+	f.kClient.PodMetricsError = nil
+	f.kClient.PodMetricsList = []k8s.PodMetrics{{PodID: "fe-pod"}}
+	f.onChange()
+	assert.Empty(t, f.metricsActions())
+
+	// After the recheck period, polling resumes.
+	f.advance(2 * unavailableRecheckPeriod)
+	f.c.OnChange(f.ctx, f.st)
+	assert.Len(t, f.metricsActions(), 1)
+}
+
+func TestPollThrottled(t *testing.T) {
+	f := newFixture(t)
+
+	f.addManifestWithPod("fe", "fe-pod")
+	f.kClient.PodMetricsList = []k8s.PodMetrics{{PodID: "fe-pod"}}
+
+	f.onChange()
+	require.Len(t, f.metricsActions(), 1)
+
+	// Not enough time has passed for another poll.
+	f.c.OnChange(f.ctx, f.st)
+	assert.Len(t, f.metricsActions(), 1)
+}
+
+type fixture struct {
+	t       *testing.T
+	ctx     context.Context
+	st      *store.TestingStore
+	kClient *k8s.FakeK8sClient
+	clock   fakeClock
+	c       *Controller
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c fakeClock) Now() time.Time { return c.now }
+
+func newFixture(t *testing.T) *fixture {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+
+	kClient := k8s.NewFakeK8sClient()
+	clock := fakeClock{now: time.Unix(1551202573, 0)}
+
+	return &fixture{
+		t:       t,
+		ctx:     ctx,
+		st:      store.NewTestingStore(),
+		kClient: kClient,
+		clock:   clock,
+		c:       NewController(kClient, clock),
+	}
+}
+
+func (f *fixture) addManifestWithPod(name model.ManifestName, podID k8s.PodID) {
+	f.st.WithState(func(state *store.EngineState) {
+		m := model.Manifest{Name: name}.WithDeployTarget(model.K8sTarget{Name: model.TargetName(name)})
+		state.UpsertManifestTarget(manifestutils.NewManifestTargetWithPod(m, store.Pod{PodID: podID}))
+	})
+}
+
+func (f *fixture) advance(d time.Duration) {
+	f.clock.now = f.clock.now.Add(d)
+	f.c.clock = f.clock
+}
+
+// Advances past the poll period and runs the controller.
+func (f *fixture) onChange() {
+	f.advance(2 * pollPeriod)
+	f.c.OnChange(f.ctx, f.st)
+}
+
+func (f *fixture) metricsActions() []PodMetricsAction {
+	var result []PodMetricsAction
+	for _, a := range f.st.Actions() {
+		if ma, ok := a.(PodMetricsAction); ok {
+			result = append(result, ma)
+		}
+	}
+	return result
+}
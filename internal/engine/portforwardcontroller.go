@@ -42,6 +42,9 @@ func (m *PortForwardController) diff(ctx context.Context, st store.RStore) (toSt
 		ms := mt.State
 		manifest := mt.Manifest
 		pod := ms.MostRecentPod()
+		if manifest.DeployStrategy == model.DeployStrategyBlueGreen {
+			pod = m.blueGreenPod(ms, pod)
+		}
 		podID := pod.PodID
 		if podID == "" {
 			continue
@@ -53,6 +56,7 @@ func (m *PortForwardController) diff(ctx context.Context, st store.RStore) (toSt
 		}
 
 		forwards := populatePortForwards(manifest, pod)
+		forwards = append(forwards, populateForwards(ms.AdHocPortForwards, pod)...)
 		if len(forwards) == 0 {
 			continue
 		}
@@ -96,6 +100,28 @@ func (m *PortForwardController) diff(ctx context.Context, st store.RStore) (toSt
 	return toStart, toShutdown
 }
 
+// With a blue/green strategy, don't retarget forwards at a pod that
+// isn't serving yet: prefer the newest ready pod, and failing that keep
+// whichever pod we're already forwarding to.
+func (m *PortForwardController) blueGreenPod(ms *store.ManifestState, fallback store.Pod) store.Pod {
+	kState, ok := ms.RuntimeState.(store.K8sRuntimeState)
+	if !ok {
+		return fallback
+	}
+
+	ready := kState.MostRecentReadyPod()
+	if !ready.Empty() {
+		return ready
+	}
+
+	for _, pod := range kState.PodList() {
+		if _, isActive := m.activeForwards[pod.PodID]; isActive {
+			return pod
+		}
+	}
+	return fallback
+}
+
 func (m *PortForwardController) OnChange(ctx context.Context, st store.RStore) {
 	toStart, toShutdown := m.diff(ctx, st)
 	for _, entry := range toShutdown {
@@ -183,8 +209,11 @@ type portForwardEntry struct {
 // have ContainerPort = 0, populate them with the default port for the pod.
 // Quietly drop forwards that we can't populate.
 func populatePortForwards(m model.Manifest, pod store.Pod) []model.PortForward {
+	return populateForwards(m.K8sTarget().PortForwards, pod)
+}
+
+func populateForwards(fwds []model.PortForward, pod store.Pod) []model.PortForward {
 	cPorts := pod.AllContainerPorts()
-	fwds := m.K8sTarget().PortForwards
 	forwards := make([]model.PortForward, 0, len(fwds))
 	for _, forward := range fwds {
 		if forward.ContainerPort == 0 {
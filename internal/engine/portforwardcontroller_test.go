@@ -129,6 +129,37 @@ func TestPortForwardAutoDiscovery2(t *testing.T) {
 	assert.Equal(t, 8080, f.kCli.LastForwardPortRemotePort)
 }
 
+func TestPortForwardAdHoc(t *testing.T) {
+	f := newPLCFixture(t)
+	defer f.TearDown()
+
+	state := f.st.LockMutableStateForTesting()
+	m := model.Manifest{Name: "fe"}.WithDeployTarget(model.K8sTarget{})
+	state.UpsertManifestTarget(store.NewManifestTarget(m))
+	state.ManifestTargets["fe"].State.RuntimeState = store.NewK8sRuntimeState("fe", store.Pod{PodID: "pod-id", Phase: v1.PodRunning})
+	f.st.UnlockMutableState()
+
+	f.onChange()
+	assert.Equal(t, 0, len(f.plc.activeForwards))
+
+	state = f.st.LockMutableStateForTesting()
+	state.ManifestTargets["fe"].State.AdHocPortForwards = []model.PortForward{
+		{LocalPort: 8080, ContainerPort: 8081},
+	}
+	f.st.UnlockMutableState()
+
+	f.onChange()
+	assert.Equal(t, 1, len(f.plc.activeForwards))
+	assert.Equal(t, 8081, f.kCli.LastForwardPortRemotePort)
+
+	state = f.st.LockMutableStateForTesting()
+	state.ManifestTargets["fe"].State.AdHocPortForwards = nil
+	f.st.UnlockMutableState()
+
+	f.onChange()
+	assert.Equal(t, 0, len(f.plc.activeForwards))
+}
+
 func TestPortForwardChangePort(t *testing.T) {
 	f := newPLCFixture(t)
 	defer f.TearDown()
@@ -191,6 +222,66 @@ func TestPortForwardRestart(t *testing.T) {
 	assert.Equal(t, 2, f.kCli.CreatePortForwardCallCount)
 }
 
+func TestPortForwardBlueGreen(t *testing.T) {
+	f := newPLCFixture(t)
+	defer f.TearDown()
+
+	state := f.st.LockMutableStateForTesting()
+	m := model.Manifest{
+		Name:           "fe",
+		DeployStrategy: model.DeployStrategyBlueGreen,
+	}.WithDeployTarget(model.K8sTarget{
+		PortForwards: []model.PortForward{
+			{
+				LocalPort:     8080,
+				ContainerPort: 8081,
+			},
+		},
+	})
+	state.UpsertManifestTarget(store.NewManifestTarget(m))
+	f.st.UnlockMutableState()
+
+	oldPod := store.Pod{
+		PodID:      "pod-old",
+		Phase:      v1.PodRunning,
+		StartedAt:  time.Now().Add(-time.Minute),
+		Containers: []store.Container{{Name: "main", Ready: true}},
+	}
+
+	state = f.st.LockMutableStateForTesting()
+	state.ManifestTargets["fe"].State.RuntimeState = store.NewK8sRuntimeState("fe", oldPod)
+	f.st.UnlockMutableState()
+
+	f.onChange()
+	assert.Equal(t, 1, len(f.plc.activeForwards))
+	assert.Equal(t, "pod-old", f.kCli.LastForwardPortPodID.String())
+
+	// A new pod appears but isn't ready; keep forwarding to the old one.
+	newPod := store.Pod{
+		PodID:      "pod-new",
+		Phase:      v1.PodRunning,
+		StartedAt:  time.Now(),
+		Containers: []store.Container{{Name: "main", Ready: false}},
+	}
+	state = f.st.LockMutableStateForTesting()
+	state.ManifestTargets["fe"].State.RuntimeState = store.NewK8sRuntimeState("fe", oldPod, newPod)
+	f.st.UnlockMutableState()
+
+	f.onChange()
+	assert.Equal(t, 1, len(f.plc.activeForwards))
+	assert.Equal(t, "pod-old", f.kCli.LastForwardPortPodID.String())
+
+	// Once the new pod is ready, the forward cuts over.
+	newPod.Containers[0].Ready = true
+	state = f.st.LockMutableStateForTesting()
+	state.ManifestTargets["fe"].State.RuntimeState = store.NewK8sRuntimeState("fe", oldPod, newPod)
+	f.st.UnlockMutableState()
+
+	f.onChange()
+	assert.Equal(t, 1, len(f.plc.activeForwards))
+	assert.Equal(t, "pod-new", f.kCli.LastForwardPortPodID.String())
+}
+
 type portForwardTestCase struct {
 	spec           []model.PortForward
 	containerPorts []int32
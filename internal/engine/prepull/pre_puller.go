@@ -0,0 +1,74 @@
+package prepull
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/windmilleng/tilt/internal/build"
+	"github.com/windmilleng/tilt/internal/container"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/logger"
+)
+
+// Pulls the images registered with the pre_pull() builtin as soon as the
+// Tiltfile has been loaded, so the first build doesn't stall waiting on them.
+type PrePuller struct {
+	ib build.ImageBuilder
+
+	// images we've already tried to pull, so each is only attempted once
+	attempted map[string]bool
+}
+
+var _ store.Subscriber = &PrePuller{}
+
+func NewPrePuller(ib build.ImageBuilder) *PrePuller {
+	return &PrePuller{
+		ib:        ib,
+		attempted: make(map[string]bool),
+	}
+}
+
+func (p *PrePuller) OnChange(ctx context.Context, st store.RStore) {
+	state := st.RLockState()
+	images := append([]string{}, state.PrePullImages...)
+	st.RUnlockState()
+
+	var toPull []string
+	for _, image := range images {
+		if p.attempted[image] {
+			continue
+		}
+		p.attempted[image] = true
+		toPull = append(toPull, image)
+	}
+	if len(toPull) == 0 {
+		return
+	}
+
+	g, gCtx := errgroup.WithContext(ctx)
+	for _, image := range toPull {
+		image := image
+		g.Go(func() error {
+			p.pull(gCtx, image)
+			return nil
+		})
+	}
+	_ = g.Wait()
+}
+
+func (p *PrePuller) pull(ctx context.Context, image string) {
+	l := logger.Get(ctx)
+
+	ref, err := container.ParseNamed(image)
+	if err != nil {
+		// pre_pull() validates its arguments, so this shouldn't happen.
+		l.Infof("[Pre Pull] invalid image name %q: %v", image, err)
+		return
+	}
+
+	err = p.ib.PullImage(ctx, ref)
+	if err != nil {
+		l.Infof("[Pre Pull] error pulling %s: %v", container.FamiliarString(ref), err)
+	}
+}
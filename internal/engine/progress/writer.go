@@ -0,0 +1,148 @@
+package progress
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/windmilleng/tilt/internal/hud"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// How progress should be reported to the terminal.
+type Mode string
+
+const (
+	// Normal human-readable output.
+	ModeDefault Mode = ""
+
+	// Newline-delimited JSON events for consumption by wrapper tools
+	// and IDE integrations.
+	ModeJSON Mode = "json"
+)
+
+// A single newline-delimited JSON event.
+type Event struct {
+	// One of "build_started", "build_finished", "pod_status", "runtime_status".
+	Type string `json:"type"`
+
+	Resource string    `json:"resource"`
+	Time     time.Time `json:"time"`
+
+	// Set on build_finished.
+	DurationMS int64  `json:"duration_ms,omitempty"`
+	Error      string `json:"error,omitempty"`
+
+	// Set on pod_status.
+	Pod       string `json:"pod,omitempty"`
+	PodStatus string `json:"pod_status,omitempty"`
+
+	// Set on runtime_status.
+	RuntimeStatus string `json:"runtime_status,omitempty"`
+}
+
+// Writer emits machine-readable progress events as resources build and
+// deploy. It's a no-op unless --output=json was passed.
+type Writer struct {
+	stdout hud.Stdout
+	mode   Mode
+
+	building      map[model.ManifestName]bool
+	lastBuild     map[model.ManifestName]time.Time
+	podStatus     map[model.ManifestName]string
+	runtimeStatus map[model.ManifestName]model.RuntimeStatus
+}
+
+func NewWriter(stdout hud.Stdout, mode Mode) *Writer {
+	return &Writer{
+		stdout:        stdout,
+		mode:          mode,
+		building:      make(map[model.ManifestName]bool),
+		lastBuild:     make(map[model.ManifestName]time.Time),
+		podStatus:     make(map[model.ManifestName]string),
+		runtimeStatus: make(map[model.ManifestName]model.RuntimeStatus),
+	}
+}
+
+var _ store.Subscriber = &Writer{}
+
+func (w *Writer) OnChange(ctx context.Context, st store.RStore) {
+	if w.mode != ModeJSON {
+		return
+	}
+
+	events := w.diff(st)
+	encoder := json.NewEncoder(w.stdout)
+	for _, ev := range events {
+		// Encode appends the newline delimiter for us.
+		_ = encoder.Encode(ev)
+	}
+}
+
+func (w *Writer) diff(st store.RStore) []Event {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	var events []Event
+	for _, mt := range state.Targets() {
+		name := mt.Manifest.Name
+		ms := mt.State
+
+		building := state.IsCurrentlyBuilding(name)
+		if building && !w.building[name] {
+			events = append(events, Event{
+				Type:     "build_started",
+				Resource: name.String(),
+				Time:     ms.CurrentBuild.StartTime,
+			})
+		}
+		w.building[name] = building
+
+		lastBuild := ms.LastBuild()
+		if !lastBuild.Empty() && !lastBuild.FinishTime.IsZero() &&
+			lastBuild.FinishTime != w.lastBuild[name] {
+			ev := Event{
+				Type:       "build_finished",
+				Resource:   name.String(),
+				Time:       lastBuild.FinishTime,
+				DurationMS: lastBuild.Duration().Milliseconds(),
+			}
+			if lastBuild.Error != nil {
+				ev.Error = lastBuild.Error.Error()
+			}
+			events = append(events, ev)
+			w.lastBuild[name] = lastBuild.FinishTime
+		}
+
+		if k8sState, ok := ms.RuntimeState.(store.K8sRuntimeState); ok {
+			pod := k8sState.MostRecentPod()
+			status := string(pod.Phase)
+			if pod.PodID != "" && status != w.podStatus[name] {
+				events = append(events, Event{
+					Type:      "pod_status",
+					Resource:  name.String(),
+					Time:      time.Now(),
+					Pod:       pod.PodID.String(),
+					PodStatus: status,
+				})
+				w.podStatus[name] = status
+			}
+		}
+
+		if ms.RuntimeState != nil {
+			status := ms.RuntimeState.RuntimeStatus()
+			if status != w.runtimeStatus[name] {
+				events = append(events, Event{
+					Type:          "runtime_status",
+					Resource:      name.String(),
+					Time:          time.Now(),
+					RuntimeStatus: string(status),
+				})
+				w.runtimeStatus[name] = status
+			}
+		}
+	}
+
+	return events
+}
@@ -0,0 +1,101 @@
+package progress
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/windmilleng/tilt/internal/k8s/testyaml"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils/manifestbuilder"
+	"github.com/windmilleng/tilt/internal/testutils/tempdir"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestWriterEmitsBuildEvents(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.st.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+		state.CurrentlyBuilding["fe"] = true
+		state.ManifestTargets["fe"].State.CurrentBuild = model.BuildRecord{StartTime: time.Now()}
+	})
+
+	f.w.OnChange(f.ctx, f.st)
+	assert.Contains(t, f.out.String(), `"type":"build_started"`)
+
+	f.st.WithState(func(state *store.EngineState) {
+		delete(state.CurrentlyBuilding, "fe")
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now().Add(-time.Second),
+			FinishTime: time.Now(),
+		})
+	})
+
+	f.w.OnChange(f.ctx, f.st)
+	assert.Contains(t, f.out.String(), `"type":"build_finished"`)
+}
+
+func TestWriterEmitsPodAndRuntimeEvents(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.st.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+		state.ManifestTargets["fe"].State.RuntimeState = store.NewK8sRuntimeState("fe",
+			store.Pod{PodID: "pod-a", Phase: v1.PodRunning})
+	})
+
+	f.w.OnChange(f.ctx, f.st)
+	out := f.out.String()
+	assert.Contains(t, out, `"type":"pod_status"`)
+	assert.Contains(t, out, `"pod":"pod-a"`)
+	assert.Contains(t, out, `"type":"runtime_status"`)
+
+	// No new events if nothing changed.
+	f.out.Reset()
+	f.w.OnChange(f.ctx, f.st)
+	assert.Empty(t, f.out.String())
+}
+
+func TestWriterDisabledByDefault(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+	f.w.mode = ModeDefault
+
+	f.st.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+		state.CurrentlyBuilding["fe"] = true
+	})
+
+	f.w.OnChange(f.ctx, f.st)
+	assert.Empty(t, f.out.String())
+}
+
+type fixture struct {
+	*tempdir.TempDirFixture
+	ctx context.Context
+	st  *store.TestingStore
+	out *bytes.Buffer
+	w   *Writer
+}
+
+func newFixture(t *testing.T) *fixture {
+	f := tempdir.NewTempDirFixture(t)
+	out := &bytes.Buffer{}
+	return &fixture{
+		TempDirFixture: f,
+		ctx:            context.Background(),
+		st:             store.NewTestingStore(),
+		out:            out,
+		w:              NewWriter(out, ModeJSON),
+	}
+}
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/pkg/logger"
+)
+
+// The verbs we need on every kind we deploy.
+var preflightDeployVerbs = []string{"get", "create", "update", "delete"}
+
+// The kinds we watch in every namespace we deploy to.
+var preflightWatchKinds = []schema.GroupVersionKind{
+	{Version: "v1", Kind: "Pod"},
+	{Version: "v1", Kind: "Service"},
+	{Version: "v1", Kind: "Event"},
+}
+
+// Runs SelfSubjectAccessReview checks for every kind/namespace a deploy
+// is about to touch, so missing RBAC permissions surface as one precise
+// report up front instead of an opaque Forbidden error mid-deploy.
+type rbacPreflight struct {
+	kClient k8s.Client
+
+	mu sync.Mutex
+
+	// Checks that have already come back allowed, so we don't re-review
+	// them on every build. Denied checks are re-run, in case the user
+	// fixes their RBAC config while Tilt is running.
+	allowed map[rbacCheck]bool
+}
+
+type rbacCheck struct {
+	verb string
+	gvk  schema.GroupVersionKind
+	ns   k8s.Namespace
+}
+
+func newRBACPreflight(kClient k8s.Client) *rbacPreflight {
+	return &rbacPreflight{
+		kClient: kClient,
+		allowed: make(map[rbacCheck]bool),
+	}
+}
+
+func (p *rbacPreflight) Check(ctx context.Context, entities []k8s.K8sEntity) error {
+	var checks []rbacCheck
+	namespaces := make(map[k8s.Namespace]bool)
+	for _, e := range entities {
+		ns := e.Namespace()
+		for _, verb := range preflightDeployVerbs {
+			checks = append(checks, rbacCheck{verb: verb, gvk: e.GVK(), ns: ns})
+		}
+		namespaces[ns] = true
+	}
+	for ns := range namespaces {
+		for _, gvk := range preflightWatchKinds {
+			checks = append(checks, rbacCheck{verb: "watch", gvk: gvk, ns: ns})
+		}
+	}
+
+	var missing []string
+	for _, check := range checks {
+		p.mu.Lock()
+		done := p.allowed[check]
+		p.mu.Unlock()
+		if done {
+			continue
+		}
+
+		allowed, err := p.kClient.CanIKind(ctx, check.verb, check.gvk, check.ns)
+		if err != nil {
+			// Don't block deploys if we can't run the review (e.g., a CRD
+			// whose type isn't registered yet); the apply itself will
+			// report any real permission error.
+			logger.Get(ctx).Debugf("RBAC preflight for %s %s: %v", check.verb, check.gvk.Kind, err)
+			continue
+		}
+
+		if allowed {
+			p.mu.Lock()
+			p.allowed[check] = true
+			p.mu.Unlock()
+			continue
+		}
+
+		kind := check.gvk.Kind
+		if check.gvk.Group != "" {
+			kind = fmt.Sprintf("%s.%s", kind, check.gvk.Group)
+		}
+		missing = append(missing, fmt.Sprintf("cannot %s %s in namespace %q", check.verb, kind, check.ns))
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing Kubernetes RBAC permissions:\n  %s\n"+
+			"Grant them to the current user or service account, or run `tilt doctor` for more detail",
+			strings.Join(missing, "\n  "))
+	}
+	return nil
+}
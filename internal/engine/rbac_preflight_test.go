@@ -0,0 +1,41 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/k8s/testyaml"
+)
+
+func TestPreflightAllAllowed(t *testing.T) {
+	kCli := k8s.NewFakeK8sClient()
+	p := newRBACPreflight(kCli)
+
+	entities, err := k8s.ParseYAMLFromString(testyaml.SanchoYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, p.Check(context.Background(), entities))
+}
+
+func TestPreflightReportsMissingVerbs(t *testing.T) {
+	kCli := k8s.NewFakeK8sClient()
+	kCli.DenyKindAccess("create", "Deployment", "sancho-ns")
+	kCli.DenyKindAccess("watch", "Pod", "sancho-ns")
+	p := newRBACPreflight(kCli)
+
+	entities, err := k8s.ParseYAMLFromString(testyaml.SanchoYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = p.Check(context.Background(), entities)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `cannot create Deployment.apps in namespace "sancho-ns"`)
+		assert.Contains(t, err.Error(), `cannot watch Pod in namespace "sancho-ns"`)
+	}
+}
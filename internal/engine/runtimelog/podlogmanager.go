@@ -55,10 +55,14 @@ func (m *PodLogManager) diff(ctx context.Context, st store.RStore) (setup []PodL
 				continue
 			}
 
+			// Mesh-injected sidecar proxies are chatty, and their logs
+			// aren't the user's code. Only stream the app containers.
+			containers := pod.AppContainers()
+
 			// if pod has more than one container, we should prefix logs with the container name
-			shouldPrefix := len(pod.Containers) > 1
+			shouldPrefix := len(containers) > 1
 
-			for _, c := range pod.Containers {
+			for _, c := range containers {
 				// Key the log watcher by the container id, so we auto-restart the
 				// watching if the container crashes.
 				key := podLogKey{
@@ -125,7 +129,7 @@ func (m *PodLogManager) shouldWatchPodLogs(pod store.Pod) bool {
 	// will happen when we try to store them in the `m.watches` map.  This should
 	// only happen if the pod is still in a weird creating state. It shouldn't
 	// happen when user code is running.
-	for _, container := range pod.Containers {
+	for _, container := range pod.AppContainers() {
 		if container.Name == "" || container.ID == "" {
 			return false
 		}
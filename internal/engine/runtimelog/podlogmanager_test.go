@@ -201,6 +201,36 @@ func TestContainerPrefixes(t *testing.T) {
 	f.AssertOutputDoesNotContain(cNameNoPrefix.String())
 }
 
+func TestMeshSidecarLogsNotStreamed(t *testing.T) {
+	f := newPLMFixture(t)
+	defer f.TearDown()
+
+	f.kClient.SetLogsForPodContainer(podID, "app", "hello world!")
+	f.kClient.SetLogsForPodContainer(podID, "istio-proxy", "proxy noise!")
+
+	state := f.store.LockMutableStateForTesting()
+
+	p := store.Pod{
+		PodID: podID,
+		Phase: v1.PodRunning,
+		Containers: []store.Container{
+			store.Container{Name: "app", ID: "cid1"},
+			store.Container{Name: "istio-proxy", ID: "cid2", MeshInjected: true},
+		},
+	}
+	state.UpsertManifestTarget(manifestutils.NewManifestTargetWithPod(
+		model.Manifest{Name: "server"}, p))
+	f.store.UnlockMutableState()
+
+	f.plm.OnChange(f.ctx, f.store)
+	f.AssertOutputContains("hello world!")
+	f.AssertOutputDoesNotContain("proxy noise!")
+
+	// The app container is the only one being streamed,
+	// so its logs shouldn't be prefixed.
+	f.AssertOutputDoesNotContain("[app]")
+}
+
 func TestLogsByPodPhase(t *testing.T) {
 	for _, test := range []struct {
 		phase      v1.PodPhase
@@ -0,0 +1,145 @@
+package snapshots
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/windmilleng/tilt/internal/cloud"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/logger"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+const snapshotPrefix = "snapshot-"
+const snapshotSuffix = ".json"
+
+// Opt-in configuration for automatic snapshots. The zero value
+// disables them.
+type Config struct {
+	// Directory to write snapshots into.
+	Dir string
+
+	// How often to capture a snapshot. Snapshots are also captured on
+	// every build failure, regardless of the interval.
+	Interval time.Duration
+
+	// How many snapshots to keep before deleting the oldest.
+	MaxCount int
+}
+
+func (c Config) enabled() bool {
+	return c.Dir != ""
+}
+
+// Periodically saves snapshots of Tilt's state to a local directory,
+// with ring-buffer retention, so sessions can be reconstructed after
+// the fact ("it was broken an hour ago").
+type Controller struct {
+	config Config
+	clock  func() time.Time
+
+	lastSnapshot time.Time
+	lastBuildErr map[model.ManifestName]time.Time
+}
+
+func NewController(config Config) *Controller {
+	return &Controller{
+		config:       config,
+		clock:        time.Now,
+		lastBuildErr: make(map[model.ManifestName]time.Time),
+	}
+}
+
+var _ store.Subscriber = &Controller{}
+
+func (c *Controller) OnChange(ctx context.Context, st store.RStore) {
+	if !c.config.enabled() {
+		return
+	}
+
+	now := c.clock()
+	capture := c.newBuildFailure(st)
+	if !capture && c.config.Interval > 0 && now.Sub(c.lastSnapshot) >= c.config.Interval {
+		capture = true
+	}
+	if !capture {
+		return
+	}
+
+	c.lastSnapshot = now
+	if err := c.capture(ctx, st, now); err != nil {
+		logger.Get(ctx).Infof("Error saving snapshot: %v", err)
+		return
+	}
+	if err := c.trim(); err != nil {
+		logger.Get(ctx).Infof("Error trimming old snapshots: %v", err)
+	}
+}
+
+// Reports whether any resource has a failed build we haven't
+// snapshotted yet.
+func (c *Controller) newBuildFailure(st store.RStore) bool {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	failed := false
+	for _, mt := range state.Targets() {
+		lastBuild := mt.State.LastBuild()
+		if lastBuild.Empty() || lastBuild.Error == nil {
+			continue
+		}
+		if c.lastBuildErr[mt.Manifest.Name] != lastBuild.FinishTime {
+			c.lastBuildErr[mt.Manifest.Name] = lastBuild.FinishTime
+			failed = true
+		}
+	}
+	return failed
+}
+
+func (c *Controller) capture(ctx context.Context, st store.RStore, now time.Time) error {
+	if err := os.MkdirAll(c.config.Dir, 0755); err != nil {
+		return err
+	}
+
+	path := filepath.Join(c.config.Dir,
+		fmt.Sprintf("%s%s%s", snapshotPrefix, now.Format("20060102-150405"), snapshotSuffix))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	state := st.RLockState()
+	snapshot := state.CopyForView()
+	st.RUnlockState()
+
+	return cloud.WriteSnapshotTo(ctx, snapshot, f)
+}
+
+// Deletes the oldest snapshots once we're over the retention limit.
+func (c *Controller) trim() error {
+	if c.config.MaxCount <= 0 {
+		return nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(c.config.Dir, snapshotPrefix+"*"+snapshotSuffix))
+	if err != nil {
+		return err
+	}
+
+	// The timestamped names sort chronologically.
+	sort.Strings(paths)
+	for len(paths) > c.config.MaxCount {
+		if err := os.Remove(paths[0]); err != nil {
+			return err
+		}
+		paths = paths[1:]
+	}
+	return nil
+}
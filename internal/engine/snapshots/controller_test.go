@@ -0,0 +1,116 @@
+package snapshots
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/internal/k8s/testyaml"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils"
+	"github.com/windmilleng/tilt/internal/testutils/manifestbuilder"
+	"github.com/windmilleng/tilt/internal/testutils/tempdir"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestSnapshotOnInterval(t *testing.T) {
+	f := newFixture(t, Config{Interval: 10 * time.Minute})
+	defer f.TearDown()
+
+	f.c.OnChange(f.ctx, f.st)
+	assert.Equal(t, 1, f.snapshotCount(), "first OnChange should capture")
+
+	f.c.OnChange(f.ctx, f.st)
+	assert.Equal(t, 1, f.snapshotCount(), "interval hasn't elapsed")
+
+	f.now = f.now.Add(11 * time.Minute)
+	f.c.OnChange(f.ctx, f.st)
+	assert.Equal(t, 2, f.snapshotCount())
+}
+
+func TestSnapshotOnBuildFailure(t *testing.T) {
+	f := newFixture(t, Config{Interval: time.Hour})
+	defer f.TearDown()
+
+	f.c.OnChange(f.ctx, f.st)
+	require.Equal(t, 1, f.snapshotCount())
+
+	finish := time.Now()
+	f.st.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  finish.Add(-time.Second),
+			FinishTime: finish,
+			Error:      fmt.Errorf("build failed"),
+		})
+	})
+
+	f.now = f.now.Add(time.Second)
+	f.c.OnChange(f.ctx, f.st)
+	assert.Equal(t, 2, f.snapshotCount(), "build failure should capture immediately")
+
+	f.c.OnChange(f.ctx, f.st)
+	assert.Equal(t, 2, f.snapshotCount(), "same failure shouldn't capture twice")
+}
+
+func TestSnapshotRetention(t *testing.T) {
+	f := newFixture(t, Config{Interval: time.Minute, MaxCount: 3})
+	defer f.TearDown()
+
+	for i := 0; i < 5; i++ {
+		f.c.OnChange(f.ctx, f.st)
+		f.now = f.now.Add(2 * time.Minute)
+	}
+	assert.Equal(t, 3, f.snapshotCount())
+}
+
+func TestSnapshotDisabledByDefault(t *testing.T) {
+	f := newFixture(t, Config{})
+	defer f.TearDown()
+	f.c.config.Dir = ""
+
+	f.c.OnChange(f.ctx, f.st)
+	assert.Equal(t, 0, f.snapshotCount())
+}
+
+type fixture struct {
+	*tempdir.TempDirFixture
+	ctx context.Context
+	st  *store.TestingStore
+	c   *Controller
+	now time.Time
+}
+
+func newFixture(t *testing.T, config Config) *fixture {
+	f := tempdir.NewTempDirFixture(t)
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	if config.Dir == "" {
+		config.Dir = filepath.Join(f.Path(), "snapshots")
+	}
+
+	st := store.NewTestingStore()
+	st.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+	})
+
+	ret := &fixture{
+		TempDirFixture: f,
+		ctx:            ctx,
+		st:             st,
+		c:              NewController(config),
+		now:            time.Now(),
+	}
+	ret.c.clock = func() time.Time { return ret.now }
+	return ret
+}
+
+func (f *fixture) snapshotCount() int {
+	paths, err := filepath.Glob(filepath.Join(f.c.config.Dir, snapshotPrefix+"*"+snapshotSuffix))
+	require.NoError(f.T(), err)
+	return len(paths)
+}
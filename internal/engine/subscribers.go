@@ -7,24 +7,39 @@ import (
 	"github.com/windmilleng/tilt/internal/engine/configs"
 	"github.com/windmilleng/tilt/internal/engine/dcwatch"
 	"github.com/windmilleng/tilt/internal/engine/dockerprune"
+	"github.com/windmilleng/tilt/internal/engine/drift"
 	"github.com/windmilleng/tilt/internal/engine/exit"
 	"github.com/windmilleng/tilt/internal/engine/fswatch"
+	"github.com/windmilleng/tilt/internal/engine/k8sgc"
 	"github.com/windmilleng/tilt/internal/engine/k8srollout"
 	"github.com/windmilleng/tilt/internal/engine/k8swatch"
+	"github.com/windmilleng/tilt/internal/engine/linkhealth"
 	"github.com/windmilleng/tilt/internal/engine/local"
+	"github.com/windmilleng/tilt/internal/engine/memory"
+	"github.com/windmilleng/tilt/internal/engine/notify"
+	"github.com/windmilleng/tilt/internal/engine/podmetrics"
+	"github.com/windmilleng/tilt/internal/engine/prepull"
+	"github.com/windmilleng/tilt/internal/engine/progress"
 	"github.com/windmilleng/tilt/internal/engine/runtimelog"
+	"github.com/windmilleng/tilt/internal/engine/snapshots"
 	"github.com/windmilleng/tilt/internal/engine/telemetry"
+	"github.com/windmilleng/tilt/internal/history"
 	"github.com/windmilleng/tilt/internal/hud"
 	"github.com/windmilleng/tilt/internal/hud/server"
+	"github.com/windmilleng/tilt/internal/objects"
+	"github.com/windmilleng/tilt/internal/session"
 	"github.com/windmilleng/tilt/internal/store"
+	pluginstore "github.com/windmilleng/tilt/pkg/store"
 )
 
 func ProvideSubscribers(
 	hud hud.HeadsUpDisplay,
 	pw *k8swatch.PodWatcher,
 	sw *k8swatch.ServiceWatcher,
+	jw *k8swatch.JobWatcher,
 	plm *runtimelog.PodLogManager,
 	pfc *PortForwardController,
+	cbc *CmdButtonController,
 	fwm *fswatch.WatchManager,
 	bc *BuildController,
 	cc *configs.ConfigsController,
@@ -43,14 +58,29 @@ func ProvideSubscribers(
 	tc *telemetry.Controller,
 	lc *local.Controller,
 	podm *k8srollout.PodMonitor,
+	pmc *podmetrics.Controller,
 	ec *exit.Controller,
+	gc *k8sgc.Controller,
+	prw *progress.Writer,
+	sc *snapshots.Controller,
+	hc *history.Controller,
+	ssc *session.Controller,
+	oc *objects.Controller,
+	nc *notify.Controller,
+	dfc *drift.Controller,
+	lhc *linkhealth.Controller,
+	mc *memory.Controller,
+	pp *prepull.PrePuller,
+	kcw *k8swatch.KubeconfigWatcher,
 ) []store.Subscriber {
-	return []store.Subscriber{
+	subscribers := []store.Subscriber{
 		hud,
 		pw,
 		sw,
+		jw,
 		plm,
 		pfc,
+		cbc,
 		fwm,
 		bc,
 		cc,
@@ -69,6 +99,20 @@ func ProvideSubscribers(
 		tc,
 		lc,
 		podm,
+		pmc,
 		ec,
+		gc,
+		prw,
+		sc,
+		hc,
+		ssc,
+		oc,
+		nc,
+		dfc,
+		lhc,
+		mc,
+		pp,
+		kcw,
 	}
+	return append(subscribers, pluginstore.RegisteredSubscribers()...)
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
 	"os/exec"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 type Controller struct {
 	spans      tracer.SpanSource
 	clock      build.Clock
+	httpClient *http.Client
 	runCounter int
 	lastRunAt  time.Time
 }
@@ -26,6 +29,7 @@ func NewController(clock build.Clock, spans tracer.SpanSource) *Controller {
 	return &Controller{
 		clock:      clock,
 		spans:      spans,
+		httpClient: http.DefaultClient,
 		runCounter: 0,
 	}
 }
@@ -38,7 +42,7 @@ func (t *Controller) OnChange(ctx context.Context, st store.RStore) {
 	tc := ts.Cmd
 	st.RUnlockState()
 
-	if tc.Empty() || !t.lastRunAt.Add(period).Before(t.clock.Now()) {
+	if (tc.Empty() && ts.Endpoint == "") || !t.lastRunAt.Add(period).Before(t.clock.Now()) {
 		return
 	}
 
@@ -57,6 +61,14 @@ func (t *Controller) OnChange(ctx context.Context, st store.RStore) {
 		return
 	}
 
+	if tc.Empty() {
+		if err := t.exportToEndpoint(ctx, ts.Endpoint, r); err != nil {
+			t.logError(st, fmt.Errorf("Error sending telemetry to %s: %v", ts.Endpoint, err))
+			requeueFn()
+		}
+		return
+	}
+
 	// run the command with the contents of the spans as jsonlines on stdin
 	cmd := exec.CommandContext(ctx, tc.Argv[0], tc.Argv[1:]...)
 	cmd.Dir = ts.Workdir
@@ -70,7 +82,31 @@ func (t *Controller) OnChange(ctx context.Context, st store.RStore) {
 	}
 }
 
+// POSTs the spans as jsonlines to a user-specified collector.
+func (t *Controller) exportToEndpoint(ctx context.Context, endpoint string, r io.Reader) error {
+	req, err := http.NewRequest(http.MethodPost, endpoint, r)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("collector returned status %s: %s", resp.Status, body)
+	}
+	return nil
+}
+
 func (t *Controller) logError(st store.RStore, err error) {
-	spanID := logstore.SpanID(fmt.Sprintf("telemetry:%s", string(t.runCounter)))
+	spanID := logstore.SpanID(fmt.Sprintf("telemetry:%d", t.runCounter))
 	st.Dispatch(store.NewLogAction(model.TiltfileManifestName, spanID, logger.InfoLvl, nil, []byte(err.Error())))
 }
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"runtime"
 	"strings"
@@ -101,6 +103,46 @@ func TestTelScriptFailsTimeIsUpShouldDeleteFileAndSetTime(t *testing.T) {
 	f.assertTelemetryScriptRanAtIs(t1)
 }
 
+func TestTelEndpointPostsSpans(t *testing.T) {
+	f := newTCFixture(t)
+	defer f.teardown()
+	t1 := time.Now()
+	f.clock.now = t1
+
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received, _ = ioutil.ReadAll(req.Body)
+	}))
+	defer server.Close()
+
+	f.endpoint = server.URL
+	f.run()
+
+	assert.Contains(t, string(received), "SpanContext")
+	f.assertNoLogs()
+	f.assertTelemetryScriptRanAtIs(t1)
+	f.assertNoSpans()
+}
+
+func TestTelEndpointFailureRequeuesSpans(t *testing.T) {
+	f := newTCFixture(t)
+	defer f.teardown()
+	t1 := time.Now()
+	f.clock.now = t1
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	f.endpoint = server.URL
+	f.run()
+
+	f.assertLog("collector returned status")
+	f.assertSpansPresent()
+	f.assertTelemetryScriptRanAtIs(t1)
+}
+
 type tcFixture struct {
 	t          *testing.T
 	ctx        context.Context
@@ -108,6 +150,7 @@ type tcFixture struct {
 	clock      fakeClock
 	st         *store.TestingStore
 	cmd        string
+	endpoint   string
 	lastRun    time.Time
 	spans      []*exporttrace.SpanData
 	sc         *tracer.SpanCollector
@@ -181,8 +224,9 @@ func (tcf *tcFixture) run() {
 	}
 
 	ts := model.TelemetrySettings{
-		Cmd:     model.ToHostCmd(tcf.cmd),
-		Workdir: tcf.temp.Path(),
+		Cmd:      model.ToHostCmd(tcf.cmd),
+		Workdir:  tcf.temp.Path(),
+		Endpoint: tcf.endpoint,
 	}
 	tcf.st.SetState(store.EngineState{
 		TelemetrySettings: ts,
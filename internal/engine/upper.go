@@ -10,6 +10,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/opentracing/opentracing-go"
 	"github.com/windmilleng/wmclient/pkg/analytics"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 
 	tiltanalytics "github.com/windmilleng/tilt/internal/analytics"
@@ -19,14 +20,19 @@ import (
 	"github.com/windmilleng/tilt/internal/engine/buildcontrol"
 	"github.com/windmilleng/tilt/internal/engine/configs"
 	"github.com/windmilleng/tilt/internal/engine/dcwatch"
+	"github.com/windmilleng/tilt/internal/engine/drift"
 	"github.com/windmilleng/tilt/internal/engine/exit"
 	"github.com/windmilleng/tilt/internal/engine/fswatch"
 	"github.com/windmilleng/tilt/internal/engine/k8swatch"
+	"github.com/windmilleng/tilt/internal/engine/linkhealth"
 	"github.com/windmilleng/tilt/internal/engine/local"
+	"github.com/windmilleng/tilt/internal/engine/memory"
+	"github.com/windmilleng/tilt/internal/engine/podmetrics"
 	"github.com/windmilleng/tilt/internal/engine/runtimelog"
 	"github.com/windmilleng/tilt/internal/hud"
 	"github.com/windmilleng/tilt/internal/hud/server"
 	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/session"
 	"github.com/windmilleng/tilt/internal/sliceutils"
 	"github.com/windmilleng/tilt/internal/store"
 	"github.com/windmilleng/tilt/internal/token"
@@ -134,6 +140,10 @@ func upperReducerFn(ctx context.Context, state *store.EngineState, action store.
 		handlePodResetRestartsAction(state, action)
 	case k8swatch.ServiceChangeAction:
 		handleServiceEvent(ctx, state, action)
+	case k8swatch.JobChangeAction:
+		handleJobEvent(ctx, state, action)
+	case k8swatch.KubeContextChangedAction:
+		handleKubeContextChanged(state, action)
 	case store.K8sEventAction:
 		handleK8sEvent(ctx, state, action)
 	case buildcontrol.BuildCompleteAction:
@@ -148,8 +158,16 @@ func upperReducerFn(ctx context.Context, state *store.EngineState, action store.
 		handleDockerComposeEvent(ctx, state, action)
 	case server.AppendToTriggerQueueAction:
 		appendToTriggerQueue(state, action.Name, action.Reason)
+	case server.PortForwardAction:
+		handlePortForwardAction(state, action)
+	case server.CommandButtonAction:
+		handleCommandButtonAction(state, action)
+	case CmdButtonDispatchedAction:
+		handleCmdButtonDispatched(state, action)
 	case hud.StartProfilingAction:
 		handleStartProfilingAction(state)
+	case memory.MemoryPressureAction:
+		handleMemoryPressureAction(state)
 	case hud.StopProfilingAction:
 		handleStopProfilingAction(state)
 	case hud.DumpEngineStateAction:
@@ -174,6 +192,14 @@ func upperReducerFn(ctx context.Context, state *store.EngineState, action store.
 		handleLogAction(state, action)
 	case exit.Action:
 		handleExitAction(state, action)
+	case session.RestoredAction:
+		handleSessionRestored(state, action)
+	case drift.DriftAction:
+		handleDriftDetected(state, action)
+	case linkhealth.LinkHealthAction:
+		handleLinkHealth(state, action)
+	case podmetrics.PodMetricsAction:
+		handlePodMetricsAction(state, action)
 
 	default:
 		state.FatalError = fmt.Errorf("unrecognized action: %T", action)
@@ -239,7 +265,10 @@ func handleBuildCompleted(ctx context.Context, engineState *store.EngineState, c
 	}
 
 	err := cb.Error
-	if err != nil {
+	if err == buildcontrol.ErrBuildSuperseded {
+		s := "Build canceled: newer file changes detected"
+		handleLogAction(engineState, store.NewLogAction(mt.Manifest.Name, cb.SpanID, logger.InfoLvl, nil, []byte(s)))
+	} else if err != nil {
 		s := fmt.Sprintf("Build Failed: %v", err)
 		handleLogAction(engineState, store.NewLogAction(mt.Manifest.Name, cb.SpanID, logger.ErrorLvl, nil, []byte(s)))
 	}
@@ -299,8 +328,12 @@ func handleBuildCompleted(ctx context.Context, engineState *store.EngineState, c
 	// build succeeds or fails.
 	liveUpdateContainerIDs := cb.Result.LiveUpdatedContainerIDs()
 	if len(liveUpdateContainerIDs) == 0 {
-		// Assume this was an image build, and reset all the container ids
-		ms.LiveUpdatedContainerIDs = container.NewIDSet()
+		// Assume this was an image build, and reset all the container ids.
+		// A superseded build didn't change any containers, so its
+		// bookkeeping is still accurate.
+		if err != buildcontrol.ErrBuildSuperseded {
+			ms.LiveUpdatedContainerIDs = container.NewIDSet()
+		}
 	} else {
 		for _, cID := range liveUpdateContainerIDs {
 			ms.LiveUpdatedContainerIDs[cID] = true
@@ -322,6 +355,16 @@ func handleBuildCompleted(ctx context.Context, engineState *store.EngineState, c
 			ms.RuntimeState = state
 		}
 
+		deployedObjects := cb.Result.DeployedObjects()
+		if len(deployedObjects) > 0 {
+			state := ms.GetOrCreateK8sRuntimeState()
+			state.DeployedObjects = deployedObjects
+			ms.RuntimeState = state
+		}
+
+		// A fresh apply resolves any out-of-band edits.
+		ms.DriftedObjects = nil
+
 		deployedPodTemplateSpecHashSet := cb.Result.DeployedPodTemplateSpecHashes()
 		if len(deployedPodTemplateSpecHashSet) > 0 {
 			state := ms.GetOrCreateK8sRuntimeState()
@@ -348,8 +391,9 @@ func handleBuildCompleted(ctx context.Context, engineState *store.EngineState, c
 				if state.StartTime.IsZero() {
 					state = state.WithStartTime(cb.FinishTime)
 				}
-				if state.LastReadyTime.IsZero() {
-					// NB: this will differ from StartTime once we support DC health checks
+				if state.LastReadyTime.IsZero() && !mt.Manifest.DockerComposeTarget().HasHealthcheck {
+					// Started is as ready as a service without a healthcheck
+					// gets; services with one wait for a healthy event.
 					state = state.WithLastReadyTime(cb.FinishTime)
 				}
 			}
@@ -386,6 +430,45 @@ func appendToTriggerQueue(state *store.EngineState, mn model.ManifestName, reaso
 	state.TriggerQueue = append(state.TriggerQueue, mn)
 }
 
+// Add or remove an ad-hoc port forward. An add with the same local port as
+// an existing forward replaces it.
+func handlePortForwardAction(state *store.EngineState, action server.PortForwardAction) {
+	ms, ok := state.ManifestState(action.Name)
+	if !ok {
+		return
+	}
+
+	var forwards []model.PortForward
+	for _, fwd := range ms.AdHocPortForwards {
+		if fwd.LocalPort == action.Forward.LocalPort {
+			continue
+		}
+		forwards = append(forwards, fwd)
+	}
+	if !action.Remove {
+		forwards = append(forwards, action.Forward)
+	}
+	ms.AdHocPortForwards = forwards
+}
+
+// Queue a cmd_button press for the CmdButtonController to run.
+func handleCommandButtonAction(state *store.EngineState, action server.CommandButtonAction) {
+	state.CmdButtonQueue = append(state.CmdButtonQueue, store.CmdButtonPress{
+		ManifestName: action.Name,
+		ButtonName:   action.ButtonName,
+		Time:         time.Now(),
+	})
+}
+
+func handleCmdButtonDispatched(state *store.EngineState, action CmdButtonDispatchedAction) {
+	for i, press := range state.CmdButtonQueue {
+		if press == action.Press {
+			state.CmdButtonQueue = append(state.CmdButtonQueue[:i], state.CmdButtonQueue[i+1:]...)
+			break
+		}
+	}
+}
+
 func removeFromTriggerQueue(state *store.EngineState, mn model.ManifestName) {
 	mState, ok := state.ManifestState(mn)
 	if ok {
@@ -408,6 +491,27 @@ func handleStartProfilingAction(state *store.EngineState) {
 	state.IsProfiling = true
 }
 
+// Don't shrink the log store below this, no matter how tight memory is.
+const minLogLengthUnderMemoryPressure = 1000 * 1000
+
+func handleMemoryPressureAction(state *store.EngineState) {
+	// Halve the log store's length limit and truncate down to it.
+	newMax := state.LogStore.MaxLength() / 2
+	if newMax >= minLogLengthUnderMemoryPressure {
+		state.LogStore.SetMaxLength(newMax)
+	}
+
+	// Keep only the most recent build record for each resource.
+	for _, mt := range state.Targets() {
+		if len(mt.State.BuildHistory) > 1 {
+			mt.State.BuildHistory = mt.State.BuildHistory[:1]
+		}
+	}
+	if len(state.TiltfileState.BuildHistory) > 1 {
+		state.TiltfileState.BuildHistory = state.TiltfileState.BuildHistory[:1]
+	}
+}
+
 func handleFSEvent(
 	ctx context.Context,
 	state *store.EngineState,
@@ -523,6 +627,7 @@ func handleConfigsReloaded(
 		mt, ok := state.ManifestTargets[m.ManifestName()]
 		if !ok {
 			mt = store.NewManifestTarget(m)
+			maybeRestoreCompletedBuild(ctx, state, mt)
 		}
 
 		newDefOrder[i] = m.ManifestName()
@@ -550,10 +655,15 @@ func handleConfigsReloaded(
 	state.Features = event.Features
 	state.TeamID = event.TeamID
 	state.TelemetrySettings = event.TelemetrySettings
+	state.NotifySettings = event.NotifySettings
+	state.SecuritySettings = event.SecuritySettings
+	state.SigningSettings = event.SigningSettings
+	state.PrePullImages = event.PrePullImages
 	state.VersionSettings = event.VersionSettings
 	state.AnalyticsTiltfileOpt = event.AnalyticsTiltfileOpt
 
 	state.MaxParallelUpdates = event.UpdateSettings.MaxParallelUpdatesMinOne()
+	state.K8sUpsertTimeout = event.UpdateSettings.K8sUpsertTimeout
 
 	// Remove pending file changes that were consumed by this build.
 	for file, modTime := range state.PendingConfigFileChanges {
@@ -563,6 +673,56 @@ func handleConfigsReloaded(
 	}
 }
 
+func handleSessionRestored(state *store.EngineState, action session.RestoredAction) {
+	for name, r := range action.Resources {
+		state.RestoredSession[name] = r
+	}
+}
+
+func handleDriftDetected(state *store.EngineState, action drift.DriftAction) {
+	mt, ok := state.ManifestTargets[action.ManifestName]
+	if !ok {
+		return
+	}
+	mt.State.DriftedObjects = action.DriftedObjects
+}
+
+func handleLinkHealth(state *store.EngineState, action linkhealth.LinkHealthAction) {
+	mt, ok := state.ManifestTargets[action.ManifestName]
+	if !ok {
+		return
+	}
+	if mt.State.LinkHealth == nil {
+		mt.State.LinkHealth = make(map[string]bool)
+	}
+	for url, up := range action.Health {
+		mt.State.LinkHealth[url] = up
+	}
+}
+
+// If a previous session built this resource successfully and its inputs
+// haven't changed since, seed the completed build so we skip the
+// initial rebuild.
+func maybeRestoreCompletedBuild(ctx context.Context, state *store.EngineState, mt *store.ManifestTarget) {
+	r, ok := state.RestoredSession[mt.Manifest.Name]
+	if !ok || r.LastBuildError != "" || r.InputHash == "" {
+		return
+	}
+
+	// Fingerprinting only stats files (no content reads), so it's cheap
+	// enough to do in the reducer.
+	if session.InputHash(mt.Manifest.LocalPaths()) != r.InputHash {
+		return
+	}
+
+	mt.State.AddCompletedBuild(model.BuildRecord{
+		StartTime:  r.LastBuildStart,
+		FinishTime: r.LastBuildFinish,
+		Reason:     model.BuildReasonFlagInit,
+	})
+	logger.Get(ctx).Infof("Restored %s from the previous session (inputs unchanged); skipping initial build", mt.Manifest.Name)
+}
+
 func handleLogAction(state *store.EngineState, action store.LogAction) {
 	state.LogStore.Append(action, state.Secrets)
 }
@@ -585,12 +745,57 @@ func handleServiceEvent(ctx context.Context, state *store.EngineState, action k8
 	runtime.LBs[k8s.ServiceName(service.Name)] = action.URL
 }
 
+func handleJobEvent(ctx context.Context, state *store.EngineState, action k8swatch.JobChangeAction) {
+	job := action.Job
+	ms, ok := state.ManifestState(action.ManifestName)
+	if !ok {
+		return
+	}
+
+	status := &store.K8sJob{Name: job.Name}
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != v1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			status.Succeeded = true
+		case batchv1.JobFailed:
+			status.Failed = true
+			status.Message = cond.Message
+		}
+	}
+
+	runtime := ms.GetOrCreateK8sRuntimeState()
+	runtime.Jobs[job.UID] = status
+	if status.Succeeded {
+		runtime.LastReadyOrSucceededTime = time.Now()
+	}
+	ms.RuntimeState = runtime
+}
+
+func handleKubeContextChanged(state *store.EngineState, action k8swatch.KubeContextChangedAction) {
+	state.CurrentKubeContext = action.KubeContext
+}
+
 func handleK8sEvent(ctx context.Context, state *store.EngineState, action store.K8sEventAction) {
 	evt := action.Event
 
 	if evt.Type != v1.EventTypeNormal {
 		handleLogAction(state, action.ToLogAction(action.ManifestName))
 	}
+
+	if warning, ok := k8swatch.ProbeWarningForEvent(evt); ok {
+		ms, ok := state.ManifestState(action.ManifestName)
+		if !ok {
+			return
+		}
+		podInfo, ok := ms.K8sRuntimeState().Pods[k8s.PodID(evt.InvolvedObject.Name)]
+		if !ok {
+			return
+		}
+		podInfo.RecordProbeWarning(warning)
+	}
 }
 
 func handleDumpEngineStateAction(ctx context.Context, engineState *store.EngineState) {
@@ -662,11 +867,12 @@ func handleLocalServeStatusAction(ctx context.Context, state *store.EngineState,
 func handleDockerComposeEvent(ctx context.Context, engineState *store.EngineState, action dcwatch.EventAction) {
 	evt := action.Event
 	mn := model.ManifestName(evt.Service)
-	ms, ok := engineState.ManifestState(mn)
+	mt, ok := engineState.ManifestTargets[mn]
 	if !ok {
 		// No corresponding manifest, nothing to do
 		return
 	}
+	ms := mt.State
 
 	state, _ := ms.RuntimeState.(dockercompose.State)
 
@@ -676,10 +882,35 @@ func handleDockerComposeEvent(ctx context.Context, engineState *store.EngineStat
 
 	if evt.IsStartupEvent() {
 		state = state.WithStartTime(action.Time)
-		// NB: this will differ from StartTime once we support DC health checks
+		// Services with a healthcheck aren't ready until it passes.
+		if !mt.Manifest.DockerComposeTarget().HasHealthcheck {
+			state = state.WithLastReadyTime(action.Time)
+		}
+	}
+
+	if evt.IsHealthyEvent() && state.LastReadyTime.IsZero() {
 		state = state.WithLastReadyTime(action.Time)
 	}
 
+	// Parity with pod crash detection: if the container died unexpectedly,
+	// save the tail of its log so we can show why it crashed. Skip events
+	// during our own builds -- force-recreate kills the old container.
+	if evt.IsCrashEvent() && action.ContainerState.ExitCode != 0 && !ms.IsBuilding() {
+		if state.SpanID == "" {
+			ms.CrashLog = model.Log{}
+		} else {
+			ms.CrashLog = model.NewLog(engineState.LogStore.TailSpan(50, state.SpanID))
+		}
+
+		msg := fmt.Sprintf("Container for %s exited with code %d.", mn, action.ContainerState.ExitCode)
+		if mt.Manifest.DockerComposeTarget().AutoRestart {
+			ms.NeedsRebuildFromCrash = true
+			msg += " Restarting."
+		}
+		le := store.NewLogAction(mn, state.SpanID, logger.WarnLvl, nil, []byte(msg+"\n"))
+		handleLogAction(engineState, le)
+	}
+
 	ms.RuntimeState = state
 }
 
@@ -1,3 +1,4 @@
+//go:build !windows
 // +build !windows
 
 package engine
@@ -25,36 +26,52 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/windmilleng/wmclient/pkg/analytics"
+	"github.com/windmilleng/wmclient/pkg/dirs"
+	apitrace "go.opentelemetry.io/otel/api/trace"
 	"gopkg.in/yaml.v2"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	tiltanalytics "github.com/windmilleng/tilt/internal/analytics"
+	"github.com/windmilleng/tilt/internal/build"
 	"github.com/windmilleng/tilt/internal/cloud"
 	"github.com/windmilleng/tilt/internal/container"
 	"github.com/windmilleng/tilt/internal/containerupdate"
 	"github.com/windmilleng/tilt/internal/docker"
 	"github.com/windmilleng/tilt/internal/dockercompose"
+	"github.com/windmilleng/tilt/internal/dockerfile"
 	engineanalytics "github.com/windmilleng/tilt/internal/engine/analytics"
 	"github.com/windmilleng/tilt/internal/engine/buildcontrol"
 	"github.com/windmilleng/tilt/internal/engine/configs"
 	"github.com/windmilleng/tilt/internal/engine/dcwatch"
 	"github.com/windmilleng/tilt/internal/engine/dockerprune"
+	"github.com/windmilleng/tilt/internal/engine/drift"
+	"github.com/windmilleng/tilt/internal/engine/linkhealth"
+	"github.com/windmilleng/tilt/internal/engine/podmetrics"
 	"github.com/windmilleng/tilt/internal/engine/exit"
 	"github.com/windmilleng/tilt/internal/engine/fswatch"
+	"github.com/windmilleng/tilt/internal/engine/k8sgc"
 	"github.com/windmilleng/tilt/internal/engine/k8srollout"
 	"github.com/windmilleng/tilt/internal/engine/k8swatch"
 	"github.com/windmilleng/tilt/internal/engine/local"
+	"github.com/windmilleng/tilt/internal/engine/memory"
+	"github.com/windmilleng/tilt/internal/engine/prepull"
+	"github.com/windmilleng/tilt/internal/engine/progress"
 	"github.com/windmilleng/tilt/internal/engine/runtimelog"
+	"github.com/windmilleng/tilt/internal/engine/snapshots"
 	"github.com/windmilleng/tilt/internal/engine/telemetry"
 	"github.com/windmilleng/tilt/internal/feature"
 	"github.com/windmilleng/tilt/internal/github"
+	"github.com/windmilleng/tilt/internal/history"
 	"github.com/windmilleng/tilt/internal/hud"
 	"github.com/windmilleng/tilt/internal/hud/server"
 	"github.com/windmilleng/tilt/internal/hud/view"
 	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/engine/notify"
 	"github.com/windmilleng/tilt/internal/k8s/testyaml"
+	"github.com/windmilleng/tilt/internal/objects"
+	"github.com/windmilleng/tilt/internal/session"
 	"github.com/windmilleng/tilt/internal/store"
 	"github.com/windmilleng/tilt/internal/synclet"
 	"github.com/windmilleng/tilt/internal/testutils"
@@ -289,7 +306,7 @@ func (b *fakeBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.RSto
 			templateSpecHashes = b.nextPodTemplateSpecHashes
 			b.nextPodTemplateSpecHashes = nil
 		}
-		result[call.k8s().ID()] = store.NewK8sDeployResult(call.k8s().ID(), uids, templateSpecHashes, nil)
+		result[call.k8s().ID()] = store.NewK8sDeployResult(call.k8s().ID(), uids, templateSpecHashes, nil, "")
 	}
 
 	b.nextLiveUpdateContainerIDs = nil
@@ -305,7 +322,10 @@ func (b *fakeBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.RSto
 	b.mu.Unlock()
 
 	// block until we know we're supposed to resolve this build
-	b.waitUntilBuildCompleted(ctx, buildKey)
+	waitErr := b.waitUntilBuildCompleted(ctx, buildKey)
+	if err == nil && waitErr != nil {
+		err = waitErr
+	}
 
 	return result, err
 }
@@ -326,16 +346,25 @@ func (b *fakeBuildAndDeployer) registerBuild(key string) {
 	b.getOrCreateBuildCompletionChannel(key)
 }
 
-func (b *fakeBuildAndDeployer) waitUntilBuildCompleted(ctx context.Context, key string) {
+func (b *fakeBuildAndDeployer) waitUntilBuildCompleted(ctx context.Context, key string) error {
 	ch := b.getOrCreateBuildCompletionChannel(key)
 
 	// wait until channel for this build is closed, or context is canceled/finishes.
+	var err error
 	select {
 	case <-ch:
 	case <-ctx.Done():
+		// If the build was completed and the context canceled at the same
+		// time, count it as completed.
+		select {
+		case <-ch:
+		default:
+			err = ctx.Err()
+		}
 	}
 
 	b.buildCompletionChans.Delete(key)
+	return err
 }
 
 func newFakeBuildAndDeployer(t *testing.T) *fakeBuildAndDeployer {
@@ -675,9 +704,10 @@ func TestThreeBuilds(t *testing.T) {
 	assert.Equal(t, []string{f.JoinPath("b.go")}, call.oneState().FilesChanged())
 
 	f.withManifestState("fe", func(ms store.ManifestState) {
-		assert.Equal(t, 2, len(ms.BuildHistory))
+		assert.Equal(t, 3, len(ms.BuildHistory))
 		assert.Equal(t, []string{f.JoinPath("b.go")}, ms.BuildHistory[0].Edits)
 		assert.Equal(t, []string{f.JoinPath("a.go")}, ms.BuildHistory[1].Edits)
+		assert.Empty(t, ms.BuildHistory[2].Edits)
 	})
 
 	err := f.Stop()
@@ -2121,10 +2151,10 @@ func TestUpperPodRestartsBeforeTiltStart(t *testing.T) {
 }
 
 // This tests a bug that led to infinite redeploys:
-// 1. Crash rebuild
-// 2. Immediately do a container build, before we get the event with the new container ID in (1). This container build
-//    should *not* happen in the pre-(1) container ID. Whether it happens in the container from (1) or yields a fresh
-//    container build isn't too important
+//  1. Crash rebuild
+//  2. Immediately do a container build, before we get the event with the new container ID in (1). This container build
+//     should *not* happen in the pre-(1) container ID. Whether it happens in the container from (1) or yields a fresh
+//     container build isn't too important
 func TestUpperBuildImmediatelyAfterCrashRebuild(t *testing.T) {
 	f := newTestFixture(t)
 	defer f.TearDown()
@@ -2377,6 +2407,51 @@ func TestK8sEventGlobalLogAndManifestLog(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestK8sEventProbeWarning(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.TearDown()
+
+	name := model.ManifestName("fe")
+	manifest := f.newManifest(string(name))
+
+	f.Start([]model.Manifest{manifest})
+	f.waitForCompletedBuildCount(1)
+
+	pod := podbuilder.New(f.T(), manifest).Build()
+	f.podEvent(pod, manifest.Name)
+
+	f.WaitUntilManifestState("pod appears", name, func(ms store.ManifestState) bool {
+		return ms.MostRecentPod().PodID.String() == pod.Name
+	})
+
+	probeEvt := &v1.Event{
+		InvolvedObject: v1.ObjectReference{
+			Kind:      "Pod",
+			Name:      pod.Name,
+			UID:       f.lastDeployedUID(name),
+			FieldPath: "spec.containers{sancho}",
+		},
+		Reason:     "Unhealthy",
+		Message:    "Readiness probe failed: HTTP probe failed with statuscode: 500",
+		Count:      3,
+		Type:       v1.EventTypeWarning,
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Time{Time: f.Now()}},
+	}
+	f.kClient.EmitEvent(f.ctx, probeEvt)
+
+	f.WaitUntilManifestState("probe warning recorded", name, func(ms store.ManifestState) bool {
+		warnings := ms.MostRecentPod().ProbeWarnings
+		return len(warnings) == 1 &&
+			warnings[0].Probe == "Readiness" &&
+			warnings[0].ContainerName == "sancho" &&
+			warnings[0].Count == 3 &&
+			warnings[0].LastOutput == "HTTP probe failed with statuscode: 500"
+	})
+
+	err := f.Stop()
+	assert.NoError(t, err)
+}
+
 func TestK8sEventNotLoggedIfNoManifestForUID(t *testing.T) {
 	f := newTestFixture(t)
 	defer f.TearDown()
@@ -2543,6 +2618,52 @@ func TestDockerComposeEventSetsStatus(t *testing.T) {
 	})
 }
 
+func TestDockerComposeHealthcheckGatesReadiness(t *testing.T) {
+	f := newTestFixture(t)
+	_, m := f.setupDCFixture()
+
+	dcTarg := m.DockerComposeTarget()
+	dcTarg.HasHealthcheck = true
+	m = m.WithDeployTarget(dcTarg)
+
+	f.Start([]model.Manifest{m})
+	f.waitForCompletedBuildCount(1)
+
+	f.dispatchDCEvent(m, dockercompose.ActionStart, docker.NewRunningContainerState())
+
+	f.WaitUntilManifestState("container started", m.ManifestName(), func(ms store.ManifestState) bool {
+		return !ms.DCRuntimeState().StartTime.IsZero()
+	})
+
+	// Started isn't ready: the healthcheck hasn't passed yet.
+	f.withManifestState(m.ManifestName(), func(ms store.ManifestState) {
+		assert.True(t, ms.DCRuntimeState().LastReadyTime.IsZero())
+	})
+
+	f.dispatchDCEvent(m, dockercompose.ActionHealthStatusHealthy, docker.NewRunningContainerState())
+
+	f.WaitUntilManifestState("service became ready", m.ManifestName(), func(ms store.ManifestState) bool {
+		return !ms.DCRuntimeState().LastReadyTime.IsZero()
+	})
+}
+
+func TestDockerComposeAutoRestartOnCrash(t *testing.T) {
+	f := newTestFixture(t)
+	_, m := f.setupDCFixture()
+
+	dcTarg := m.DockerComposeTarget()
+	dcTarg.AutoRestart = true
+	m = m.WithDeployTarget(dcTarg)
+
+	f.Start([]model.Manifest{m})
+	f.waitForCompletedBuildCount(1)
+
+	f.dispatchDCEvent(m, dockercompose.ActionDie, docker.NewExitErrorContainerState())
+
+	// The crash should kick off a new deploy to bring the service back up.
+	f.waitForCompletedBuildCount(2)
+}
+
 func TestDockerComposeStartsEventWatcher(t *testing.T) {
 	f := newTestFixture(t)
 	_, m := f.setupDCFixture()
@@ -3475,8 +3596,10 @@ func newTestFixtureWithHud(t *testing.T, h hud.HeadsUpDisplay) *testFixture {
 
 	kCli := k8s.NewFakeK8sClient()
 	of := k8s.ProvideOwnerFetcher(kCli)
-	pw := k8swatch.NewPodWatcher(kCli, of)
-	sw := k8swatch.NewServiceWatcher(kCli, of)
+	clientPool := k8s.ProvideClientPool("fake-context", kCli, of)
+	pw := k8swatch.NewPodWatcher(clientPool)
+	sw := k8swatch.NewServiceWatcher(clientPool)
+	jw := k8swatch.NewJobWatcher(clientPool)
 
 	fSub := fixtureSub{ch: make(chan bool, 1000)}
 	st := store.NewStore(UpperReducer, store.LogActionsFlag(false))
@@ -3494,13 +3617,15 @@ func newTestFixtureWithHud(t *testing.T, h hud.HeadsUpDisplay) *testFixture {
 	env := k8s.EnvDockerDesktop
 	fwm := fswatch.NewWatchManager(watcher.NewSub, timerMaker.Maker())
 	pfc := NewPortForwardController(kCli)
+	cbc := NewCmdButtonController(kCli, dockerClient)
 	au := engineanalytics.NewAnalyticsUpdater(ta, engineanalytics.CmdTags{})
 	ar := engineanalytics.ProvideAnalyticsReporter(ta, st, kCli, env)
 	fakeDcc := dockercompose.NewFakeDockerComposeClient(t, ctx)
 	k8sContextExt := k8scontext.NewExtension("fake-context", env)
 	versionExt := version.NewExtension(model.TiltBuild{Version: "0.5.0"})
 	tfl := tiltfile.ProvideTiltfileLoader(ta, kCli, k8sContextExt, versionExt, fakeDcc, "localhost", feature.MainDefaults, env)
-	cc := configs.NewConfigsController(tfl, dockerClient)
+	cc := configs.NewConfigsController(tfl, dockerClient, apitrace.NoopTracer{})
+	cc.SetDebounceDelayForTesting(0)
 	dcw := dcwatch.NewEventWatcher(fakeDcc, dockerClient)
 	dclm := runtimelog.NewDockerComposeLogManager(fakeDcc)
 	pm := NewProfilerManager()
@@ -3554,9 +3679,22 @@ func newTestFixtureWithHud(t *testing.T, h hud.HeadsUpDisplay) *testFixture {
 	tvc := NewTiltVersionChecker(func() github.Client { return ghc }, tiltVersionCheckTimerMaker)
 	tc := telemetry.NewController(clock, tracer.NewSpanCollector(ctx))
 	podm := k8srollout.NewPodMonitor()
-	ec := exit.NewController()
-
-	subs := ProvideSubscribers(h, pw, sw, plm, pfc, fwm, bc, cc, dcw, dclm, pm, sm, ar, hudsc, tvc, au, ewm, tcum, cuu, dp, tc, lc, podm, ec)
+	ec := exit.NewController(exit.Timeouts{})
+	gc := k8sgc.NewController(kCli)
+	prw := progress.NewWriter(log, progress.ModeDefault)
+	sc := snapshots.NewController(snapshots.Config{})
+	hc := history.NewController(history.NewStore(dirs.NewWindmillDirAt(f.JoinPath("wmdir"))))
+	ssc := session.NewController()
+	oc := objects.NewController(objects.NewRegistry())
+	nc := notify.NewController(httptest.NewFakeClient(), notify.ProvideNotifier())
+	dfc := drift.NewController(kCli, clock)
+	lhc := linkhealth.NewController(httptest.NewFakeClient(), clock)
+	pmc := podmetrics.NewController(kCli, clock)
+	mc := memory.NewController(0, pw, ewm)
+	pp := prepull.NewPrePuller(build.DefaultImageBuilder(build.NewDockerImageBuilder(dockerClient, dockerfile.Labels{})))
+	kcw := k8swatch.NewKubeconfigWatcher("fake-context")
+
+	subs := ProvideSubscribers(h, pw, sw, jw, plm, pfc, cbc, fwm, bc, cc, dcw, dclm, pm, sm, ar, hudsc, tvc, au, ewm, tcum, cuu, dp, tc, lc, podm, pmc, ec, gc, prw, sc, hc, ssc, oc, nc, dfc, lhc, mc, pp, kcw)
 	ret.upper = NewUpper(ctx, st, subs)
 
 	go func() {
@@ -4155,7 +4293,7 @@ func deployResultSet(manifest model.Manifest, uid types.UID, hashes []k8s.PodTem
 		resultSet[iTarget.ID()] = store.NewImageBuildResult(iTarget.ID(), localRefTagged, clusterRefTagged)
 	}
 	ktID := manifest.K8sTarget().ID()
-	resultSet[ktID] = store.NewK8sDeployResult(ktID, []types.UID{uid}, hashes, nil)
+	resultSet[ktID] = store.NewK8sDeployResult(ktID, []types.UID{uid}, hashes, nil, "")
 	return resultSet
 }
 
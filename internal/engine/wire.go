@@ -31,6 +31,7 @@ var DeployerBaseWireSet = wire.NewSet(
 
 	sidecar.WireSet,
 	k8s.ProvideMinikubeClient,
+	k8s.ProvideClientPool,
 	build.DefaultImageBuilder,
 	build.NewDockerImageBuilder,
 	build.NewExecCustomBuilder,
@@ -42,6 +43,7 @@ var DeployerBaseWireSet = wire.NewSet(
 	containerupdate.NewDockerContainerUpdater,
 	containerupdate.NewSyncletUpdater,
 	containerupdate.NewExecUpdater,
+	containerupdate.NewCopySidecarUpdater,
 	NewLiveUpdateBuildAndDeployer,
 	NewDockerComposeBuildAndDeployer,
 	NewImageAndCacheBuilder,
@@ -52,10 +54,12 @@ var DeployerBaseWireSet = wire.NewSet(
 	wire.Bind(new(BuildAndDeployer), new(*CompositeBuildAndDeployer)),
 	NewCompositeBuildAndDeployer,
 	buildcontrol.ProvideUpdateMode,
+	buildcontrol.ProvideClusterSharesDocker,
 )
 
 var DeployerWireSetTest = wire.NewSet(
 	DeployerBaseWireSet,
+	provideLocalDockerClientForTests,
 	containerupdate.NewSyncletManagerForTests,
 	wire.InterfaceValue(new(sdktrace.SpanProcessor), (sdktrace.SpanProcessor)(nil)),
 
@@ -82,6 +86,9 @@ func provideBuildAndDeployer(
 	analytics *analytics.TiltAnalytics) (BuildAndDeployer, error) {
 	wire.Build(
 		DeployerWireSetTest,
+		wire.Value(buildcontrol.ClusterSharesDockerFlag(false)),
+		wire.Value(k8s.KubeContext("fake-context")),
+		k8s.ProvideOwnerFetcher,
 		k8s.ProvideContainerRuntime,
 	)
 
@@ -100,12 +107,21 @@ func provideImageBuildAndDeployer(
 	wire.Build(
 		DeployerWireSetTest,
 		wire.Value(buildcontrol.UpdateModeFlag(buildcontrol.UpdateModeAuto)),
+		wire.Value(buildcontrol.ClusterSharesDockerFlag(false)),
+		wire.Value(k8s.KubeContext("fake-context")),
+		k8s.ProvideOwnerFetcher,
 		k8s.ProvideContainerRuntime,
 	)
 
 	return nil, nil
 }
 
+// The test injectors only have one docker client; compose builds use it
+// as the local client too.
+func provideLocalDockerClientForTests(dCli docker.Client) docker.LocalClient {
+	return docker.LocalClient(dCli)
+}
+
 func provideKubectlLogLevelInfo() k8s.KubectlLogLevel {
 	return k8s.KubectlLogLevel(0)
 }
@@ -118,6 +134,7 @@ func provideDockerComposeBuildAndDeployer(
 	wire.Build(
 		DeployerWireSetTest,
 		wire.Value(buildcontrol.UpdateModeFlag(buildcontrol.UpdateModeAuto)),
+		wire.Value(buildcontrol.ClusterSharesDockerFlag(false)),
 		build.ProvideClock,
 		provideKubectlLogLevelInfo,
 
@@ -128,6 +145,8 @@ func provideDockerComposeBuildAndDeployer(
 		k8s.ProvideKubeContext,
 		k8s.ProvideKubectlRunner,
 		k8s.ProvideK8sClient,
+		wire.Value(k8s.ServerSideValidation(false)),
+		k8s.ProvideOwnerFetcher,
 		k8s.ProvideRESTConfig,
 		k8s.ProvideClientset,
 		k8s.ProvidePortForwardClient,
@@ -36,12 +36,15 @@ func provideBuildAndDeployer(ctx context.Context, docker2 docker.Client, kClient
 	syncletManager := containerupdate.NewSyncletManagerForTests(kClient, syncletClient, sCli)
 	syncletUpdater := containerupdate.NewSyncletUpdater(syncletManager)
 	execUpdater := containerupdate.NewExecUpdater(kClient)
+	copySidecarUpdater := containerupdate.NewCopySidecarUpdater(kClient)
 	runtime := k8s.ProvideContainerRuntime(ctx, kClient)
-	buildcontrolUpdateMode, err := buildcontrol.ProvideUpdateMode(updateMode, env, runtime)
+	clusterSharesDockerFlag := _wireClusterSharesDockerFlagValue
+	clusterSharesDocker := buildcontrol.ProvideClusterSharesDocker(ctx, clusterSharesDockerFlag, env, runtime, kClient, docker2)
+	buildcontrolUpdateMode, err := buildcontrol.ProvideUpdateMode(updateMode, clusterSharesDocker, runtime)
 	if err != nil {
 		return nil, err
 	}
-	liveUpdateBuildAndDeployer := NewLiveUpdateBuildAndDeployer(dockerContainerUpdater, syncletUpdater, execUpdater, buildcontrolUpdateMode, env, runtime, clock)
+	liveUpdateBuildAndDeployer := NewLiveUpdateBuildAndDeployer(dockerContainerUpdater, syncletUpdater, execUpdater, copySidecarUpdater, buildcontrolUpdateMode, clusterSharesDocker, runtime, clock)
 	labels := _wireLabelsValue
 	dockerImageBuilder := build.NewDockerImageBuilder(docker2, labels)
 	imageBuilder := build.DefaultImageBuilder(dockerImageBuilder)
@@ -51,11 +54,15 @@ func provideBuildAndDeployer(ctx context.Context, docker2 docker.Client, kClient
 		return nil, err
 	}
 	syncletContainer := sidecar.ProvideSyncletContainer(syncletImageRef)
-	imageBuildAndDeployer := NewImageBuildAndDeployer(imageBuilder, execCustomBuilder, kClient, env, analytics2, buildcontrolUpdateMode, clock, runtime, kp, syncletContainer)
+	kubeContext := _wireKubeContextValue
+	ownerFetcher := k8s.ProvideOwnerFetcher(kClient)
+	clientPool := k8s.ProvideClientPool(kubeContext, kClient, ownerFetcher)
+	imageBuildAndDeployer := NewImageBuildAndDeployer(imageBuilder, execCustomBuilder, kClient, clientPool, env, clusterSharesDocker, analytics2, buildcontrolUpdateMode, clock, runtime, kp, syncletContainer)
 	engineImageAndCacheBuilder := NewImageAndCacheBuilder(imageBuilder, execCustomBuilder, buildcontrolUpdateMode)
-	dockerComposeBuildAndDeployer := NewDockerComposeBuildAndDeployer(dcc, docker2, engineImageAndCacheBuilder, clock)
+	localClient := provideLocalDockerClientForTests(docker2)
+	dockerComposeBuildAndDeployer := NewDockerComposeBuildAndDeployer(dcc, localClient, engineImageAndCacheBuilder, clock)
 	localTargetBuildAndDeployer := NewLocalTargetBuildAndDeployer(clock)
-	buildOrder := DefaultBuildOrder(liveUpdateBuildAndDeployer, imageBuildAndDeployer, dockerComposeBuildAndDeployer, localTargetBuildAndDeployer, buildcontrolUpdateMode, env, runtime)
+	buildOrder := DefaultBuildOrder(liveUpdateBuildAndDeployer, imageBuildAndDeployer, dockerComposeBuildAndDeployer, localTargetBuildAndDeployer, buildcontrolUpdateMode, clusterSharesDocker, runtime)
 	spanProcessor := _wireSpanProcessorValue
 	traceTracer, err := tracer.InitOpenTelemetry(ctx, spanProcessor)
 	if err != nil {
@@ -66,8 +73,10 @@ func provideBuildAndDeployer(ctx context.Context, docker2 docker.Client, kClient
 }
 
 var (
-	_wireLabelsValue        = dockerfile.Labels{}
-	_wireSpanProcessorValue = (trace.SpanProcessor)(nil)
+	_wireLabelsValue                  = dockerfile.Labels{}
+	_wireSpanProcessorValue           = (trace.SpanProcessor)(nil)
+	_wireClusterSharesDockerFlagValue = buildcontrol.ClusterSharesDockerFlag(false)
+	_wireKubeContextValue             = k8s.KubeContext("fake-context")
 )
 
 func provideImageBuildAndDeployer(ctx context.Context, docker2 docker.Client, kClient k8s.Client, env k8s.Env, dir *dirs.WindmillDir, clock build.Clock, kp KINDLoader, analytics2 *analytics.TiltAnalytics) (*ImageBuildAndDeployer, error) {
@@ -77,7 +86,9 @@ func provideImageBuildAndDeployer(ctx context.Context, docker2 docker.Client, kC
 	execCustomBuilder := build.NewExecCustomBuilder(docker2, clock)
 	updateModeFlag := _wireUpdateModeFlagValue
 	runtime := k8s.ProvideContainerRuntime(ctx, kClient)
-	updateMode, err := buildcontrol.ProvideUpdateMode(updateModeFlag, env, runtime)
+	clusterSharesDockerFlag := _wireClusterSharesDockerFlagValue
+	clusterSharesDocker := buildcontrol.ProvideClusterSharesDocker(ctx, clusterSharesDockerFlag, env, runtime, kClient, docker2)
+	updateMode, err := buildcontrol.ProvideUpdateMode(updateModeFlag, clusterSharesDocker, runtime)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +97,10 @@ func provideImageBuildAndDeployer(ctx context.Context, docker2 docker.Client, kC
 		return nil, err
 	}
 	syncletContainer := sidecar.ProvideSyncletContainer(syncletImageRef)
-	imageBuildAndDeployer := NewImageBuildAndDeployer(imageBuilder, execCustomBuilder, kClient, env, analytics2, updateMode, clock, runtime, kp, syncletContainer)
+	kubeContext := _wireKubeContextValue
+	ownerFetcher := k8s.ProvideOwnerFetcher(kClient)
+	clientPool := k8s.ProvideClientPool(kubeContext, kClient, ownerFetcher)
+	imageBuildAndDeployer := NewImageBuildAndDeployer(imageBuilder, execCustomBuilder, kClient, clientPool, env, clusterSharesDocker, analytics2, updateMode, clock, runtime, kp, syncletContainer)
 	return imageBuildAndDeployer, nil
 }
 
@@ -118,39 +132,50 @@ func provideDockerComposeBuildAndDeployer(ctx context.Context, dcCli dockercompo
 	int2 := provideKubectlLogLevelInfo()
 	kubectlRunner := k8s.ProvideKubectlRunner(kubeContext, int2)
 	minikubeClient := k8s.ProvideMinikubeClient(kubeContext)
-	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig)
+	serverSideValidation := _wireServerSideValidationValue
+	client := k8s.ProvideK8sClient(ctx, env, restConfigOrError, clientsetOrError, portForwardClient, namespace, kubectlRunner, minikubeClient, clientConfig, serverSideValidation)
 	runtime := k8s.ProvideContainerRuntime(ctx, client)
-	updateMode, err := buildcontrol.ProvideUpdateMode(updateModeFlag, env, runtime)
+	clusterSharesDockerFlag := _wireClusterSharesDockerFlagValue
+	clusterSharesDocker := buildcontrol.ProvideClusterSharesDocker(ctx, clusterSharesDockerFlag, env, runtime, client, dCli)
+	updateMode, err := buildcontrol.ProvideUpdateMode(updateModeFlag, clusterSharesDocker, runtime)
 	if err != nil {
 		return nil, err
 	}
 	engineImageAndCacheBuilder := NewImageAndCacheBuilder(imageBuilder, execCustomBuilder, updateMode)
-	dockerComposeBuildAndDeployer := NewDockerComposeBuildAndDeployer(dcCli, dCli, engineImageAndCacheBuilder, clock)
+	localClient := provideLocalDockerClientForTests(dCli)
+	dockerComposeBuildAndDeployer := NewDockerComposeBuildAndDeployer(dcCli, localClient, engineImageAndCacheBuilder, clock)
 	return dockerComposeBuildAndDeployer, nil
 }
 
 var (
 	_wireBuildcontrolUpdateModeFlagValue = buildcontrol.UpdateModeFlag(buildcontrol.UpdateModeAuto)
 	_wireEnvValue                        = k8s.Env(k8s.EnvNone)
+	_wireServerSideValidationValue       = k8s.ServerSideValidation(false)
 )
 
 // wire.go:
 
-var DeployerBaseWireSet = wire.NewSet(wire.Value(dockerfile.Labels{}), wire.Value(UpperReducer), sidecar.WireSet, k8s.ProvideMinikubeClient, build.DefaultImageBuilder, build.NewDockerImageBuilder, build.NewExecCustomBuilder, wire.Bind(new(build.CustomBuilder), new(*build.ExecCustomBuilder)), NewLocalTargetBuildAndDeployer,
-	NewImageBuildAndDeployer, containerupdate.NewDockerContainerUpdater, containerupdate.NewSyncletUpdater, containerupdate.NewExecUpdater, NewLiveUpdateBuildAndDeployer,
+var DeployerBaseWireSet = wire.NewSet(wire.Value(dockerfile.Labels{}), wire.Value(UpperReducer), sidecar.WireSet, k8s.ProvideMinikubeClient, k8s.ProvideClientPool, build.DefaultImageBuilder, build.NewDockerImageBuilder, build.NewExecCustomBuilder, wire.Bind(new(build.CustomBuilder), new(*build.ExecCustomBuilder)), NewLocalTargetBuildAndDeployer,
+	NewImageBuildAndDeployer, containerupdate.NewDockerContainerUpdater, containerupdate.NewSyncletUpdater, containerupdate.NewExecUpdater, containerupdate.NewCopySidecarUpdater, NewLiveUpdateBuildAndDeployer,
 	NewDockerComposeBuildAndDeployer,
 	NewImageAndCacheBuilder,
-	DefaultBuildOrder, tracer.InitOpenTelemetry, wire.Bind(new(BuildAndDeployer), new(*CompositeBuildAndDeployer)), NewCompositeBuildAndDeployer, buildcontrol.ProvideUpdateMode,
+	DefaultBuildOrder, tracer.InitOpenTelemetry, wire.Bind(new(BuildAndDeployer), new(*CompositeBuildAndDeployer)), NewCompositeBuildAndDeployer, buildcontrol.ProvideUpdateMode, buildcontrol.ProvideClusterSharesDocker,
 )
 
 var DeployerWireSetTest = wire.NewSet(
-	DeployerBaseWireSet, containerupdate.NewSyncletManagerForTests, wire.InterfaceValue(new(trace.SpanProcessor), (trace.SpanProcessor)(nil)), synclet.FakeGRPCWrapper,
+	DeployerBaseWireSet, provideLocalDockerClientForTests, containerupdate.NewSyncletManagerForTests, wire.InterfaceValue(new(trace.SpanProcessor), (trace.SpanProcessor)(nil)), synclet.FakeGRPCWrapper,
 )
 
 var DeployerWireSet = wire.NewSet(
 	DeployerBaseWireSet, containerupdate.NewSyncletManager,
 )
 
+// The test injectors only have one docker client; compose builds use it
+// as the local client too.
+func provideLocalDockerClientForTests(dCli docker.Client) docker.LocalClient {
+	return docker.LocalClient(dCli)
+}
+
 func provideKubectlLogLevelInfo() k8s.KubectlLogLevel {
 	return k8s.KubectlLogLevel(0)
 }
@@ -23,6 +23,7 @@ const Events = "events"
 const Snapshots = "snapshots"
 const UpdateHistory = "update_history"
 const Facets = "facets"
+const KubernetesGC = "k8s_gc"
 
 // The Value a flag can have. Status should never be changed.
 type Value struct {
@@ -56,6 +57,10 @@ var MainDefaults = Defaults{
 		Enabled: true,
 		Status:  Active,
 	},
+	KubernetesGC: Value{
+		Enabled: false,
+		Status:  Active,
+	},
 }
 
 // FeatureSet is a mutable set of Features.
@@ -0,0 +1,639 @@
+package git
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Reader answers questions about a git repository by reading the .git
+// directory directly, without shelling out to git. It understands
+// loose and packed objects, packed refs, and index versions 2 and 3 —
+// enough to report branch, HEAD sha, dirty state, and changed files
+// on a normal developer checkout.
+type Reader struct {
+	workDir string
+	gitDir  string
+}
+
+func NewReader(repoPath string) (*Reader, error) {
+	gitDir := filepath.Join(repoPath, ".git")
+	fi, err := os.Stat(gitDir)
+	if err != nil {
+		return nil, fmt.Errorf("%s isn't a valid git repo: it doesn't have a .git/ directory", repoPath)
+	}
+
+	if !fi.IsDir() {
+		// Worktrees and submodules use a .git file pointing at the
+		// real git dir.
+		data, err := ioutil.ReadFile(gitDir)
+		if err != nil {
+			return nil, err
+		}
+		line := strings.TrimSpace(string(data))
+		if !strings.HasPrefix(line, "gitdir: ") {
+			return nil, fmt.Errorf("malformed .git file: %s", gitDir)
+		}
+		dir := strings.TrimPrefix(line, "gitdir: ")
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(repoPath, dir)
+		}
+		gitDir = dir
+	}
+
+	return &Reader{workDir: repoPath, gitDir: gitDir}, nil
+}
+
+// Branch returns the name of the checked-out branch, or "" if HEAD is
+// detached.
+func (r *Reader) Branch() (string, error) {
+	head, err := r.readHead()
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(head, "ref: ") {
+		return strings.TrimPrefix(strings.TrimPrefix(head, "ref: "), "refs/heads/"), nil
+	}
+	return "", nil
+}
+
+// HeadSHA returns the full sha of the current HEAD commit.
+func (r *Reader) HeadSHA() (string, error) {
+	head, err := r.readHead()
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(head, "ref: ") {
+		return r.ResolveRef(strings.TrimPrefix(head, "ref: "))
+	}
+	return head, nil
+}
+
+// IsDirty reports whether any tracked file differs from HEAD, staged
+// or not. Untracked files don't count, matching what image-tagging
+// workflows care about.
+func (r *Reader) IsDirty() (bool, error) {
+	files, err := r.ChangedFiles("HEAD")
+	if err != nil {
+		return false, err
+	}
+	return len(files) > 0, nil
+}
+
+// ChangedFiles returns the paths (relative to the repo root, sorted)
+// that differ between the given base ref and the working tree:
+// commits since base, staged changes, and unstaged edits to tracked
+// files. Untracked files are not included.
+func (r *Reader) ChangedFiles(baseRef string) ([]string, error) {
+	baseSha, err := r.ResolveRef(baseRef)
+	if err != nil {
+		return nil, err
+	}
+	baseFiles, err := r.commitFiles(baseSha)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := r.indexEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string]bool)
+	idxFiles := make(map[string]string, len(entries))
+	for _, e := range entries {
+		if e.mode == modeGitlink {
+			continue
+		}
+		idxFiles[e.path] = e.sha
+		dirty, err := r.entryDirty(e)
+		if err != nil {
+			return nil, err
+		}
+		if dirty {
+			changed[e.path] = true
+		}
+	}
+
+	for p, sha := range baseFiles {
+		if idxSha, ok := idxFiles[p]; !ok || idxSha != sha {
+			changed[p] = true
+		}
+	}
+	for p := range idxFiles {
+		if _, ok := baseFiles[p]; !ok {
+			changed[p] = true
+		}
+	}
+
+	ret := make([]string, 0, len(changed))
+	for p := range changed {
+		ret = append(ret, p)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+var shaRe = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// ResolveRef resolves a branch name, tag, full ref, or sha to a sha,
+// checking loose refs first and packed-refs second, the way git does.
+func (r *Reader) ResolveRef(ref string) (string, error) {
+	if shaRe.MatchString(ref) {
+		return ref, nil
+	}
+	if ref == "HEAD" {
+		return r.HeadSHA()
+	}
+
+	candidates := []string{ref, "refs/" + ref, "refs/heads/" + ref, "refs/tags/" + ref, "refs/remotes/" + ref}
+	for _, c := range candidates {
+		data, err := ioutil.ReadFile(filepath.Join(r.gitDir, filepath.FromSlash(c)))
+		if err != nil {
+			continue
+		}
+		v := strings.TrimSpace(string(data))
+		if strings.HasPrefix(v, "ref: ") {
+			return r.ResolveRef(strings.TrimPrefix(v, "ref: "))
+		}
+		return v, nil
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(r.gitDir, "packed-refs"))
+	if err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if line == "" || line[0] == '#' || line[0] == '^' {
+				continue
+			}
+			parts := strings.SplitN(line, " ", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			for _, c := range candidates {
+				if parts[1] == c {
+					return parts[0], nil
+				}
+			}
+		}
+	}
+
+	return "", fmt.Errorf("unknown ref %q", ref)
+}
+
+func (r *Reader) readHead() (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(r.gitDir, "HEAD"))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// --- the index ---
+
+const modeGitlink = 0160000
+
+type indexEntry struct {
+	path      string
+	sha       string
+	size      uint32
+	mtimeSec  uint32
+	mtimeNsec uint32
+	mode      uint32
+}
+
+func (r *Reader) indexEntries() ([]indexEntry, error) {
+	data, err := ioutil.ReadFile(filepath.Join(r.gitDir, "index"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(data) < 12 || string(data[:4]) != "DIRC" {
+		return nil, fmt.Errorf("malformed git index")
+	}
+	version := binary.BigEndian.Uint32(data[4:8])
+	if version != 2 && version != 3 {
+		return nil, fmt.Errorf("unsupported git index version %d", version)
+	}
+	count := int(binary.BigEndian.Uint32(data[8:12]))
+
+	entries := make([]indexEntry, 0, count)
+	pos := 12
+	for i := 0; i < count; i++ {
+		if pos+62 > len(data) {
+			return nil, fmt.Errorf("malformed git index")
+		}
+		e := indexEntry{
+			mtimeSec:  binary.BigEndian.Uint32(data[pos+8 : pos+12]),
+			mtimeNsec: binary.BigEndian.Uint32(data[pos+12 : pos+16]),
+			mode:      binary.BigEndian.Uint32(data[pos+24 : pos+28]),
+			size:      binary.BigEndian.Uint32(data[pos+36 : pos+40]),
+			sha:       hex.EncodeToString(data[pos+40 : pos+60]),
+		}
+		flags := binary.BigEndian.Uint16(data[pos+60 : pos+62])
+		nameStart := pos + 62
+		if version == 3 && flags&0x4000 != 0 {
+			nameStart += 2 // extended flags word
+		}
+		nameLen := int(flags & 0xfff)
+		if nameLen == 0xfff {
+			end := bytes.IndexByte(data[nameStart:], 0)
+			if end < 0 {
+				return nil, fmt.Errorf("malformed git index")
+			}
+			nameLen = end
+		}
+		if nameStart+nameLen > len(data) {
+			return nil, fmt.Errorf("malformed git index")
+		}
+		e.path = string(data[nameStart : nameStart+nameLen])
+		entries = append(entries, e)
+
+		// Entries are nul-padded to a multiple of eight bytes.
+		pos += ((nameStart+nameLen-pos)/8 + 1) * 8
+	}
+	return entries, nil
+}
+
+// entryDirty reports whether the file on disk differs from its index
+// entry, using git's trick of trusting a matching size + mtime before
+// falling back to hashing the content.
+func (r *Reader) entryDirty(e indexEntry) (bool, error) {
+	if e.mode == modeGitlink {
+		return false, nil
+	}
+	p := filepath.Join(r.workDir, filepath.FromSlash(e.path))
+	fi, err := os.Lstat(p)
+	if err != nil {
+		return true, nil // deleted
+	}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(p)
+		if err != nil {
+			return true, nil
+		}
+		return blobSHA([]byte(target)) != e.sha, nil
+	}
+	if !fi.Mode().IsRegular() {
+		return true, nil
+	}
+	if uint32(fi.Size()) != e.size {
+		return true, nil
+	}
+	mtime := fi.ModTime()
+	if uint32(mtime.Unix()) == e.mtimeSec &&
+		(e.mtimeNsec == 0 || uint32(mtime.Nanosecond()) == e.mtimeNsec) {
+		return false, nil
+	}
+	content, err := ioutil.ReadFile(p)
+	if err != nil {
+		return true, nil
+	}
+	return blobSHA(content) != e.sha, nil
+}
+
+func blobSHA(content []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// --- trees and commits ---
+
+func (r *Reader) commitFiles(sha string) (map[string]string, error) {
+	treeSha, err := r.commitTree(sha)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]string)
+	err = r.flattenTree(treeSha, "", files)
+	return files, err
+}
+
+func (r *Reader) commitTree(sha string) (string, error) {
+	typ, data, err := r.object(sha)
+	if err != nil {
+		return "", err
+	}
+	switch typ {
+	case "tree":
+		return sha, nil
+	case "tag":
+		// Annotated tag: chase the tagged object.
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(line, "object ") {
+				return r.commitTree(strings.TrimPrefix(line, "object "))
+			}
+		}
+		return "", fmt.Errorf("malformed tag object %s", sha)
+	case "commit":
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(line, "tree ") {
+				return strings.TrimPrefix(line, "tree "), nil
+			}
+		}
+		return "", fmt.Errorf("malformed commit object %s", sha)
+	}
+	return "", fmt.Errorf("%s is a %s, not a commit", sha, typ)
+}
+
+func (r *Reader) flattenTree(sha string, prefix string, out map[string]string) error {
+	typ, data, err := r.object(sha)
+	if err != nil {
+		return err
+	}
+	if typ != "tree" {
+		return fmt.Errorf("%s is a %s, not a tree", sha, typ)
+	}
+
+	pos := 0
+	for pos < len(data) {
+		sp := bytes.IndexByte(data[pos:], ' ')
+		if sp < 0 {
+			return fmt.Errorf("malformed tree object %s", sha)
+		}
+		mode := string(data[pos : pos+sp])
+		nul := bytes.IndexByte(data[pos+sp:], 0)
+		if nul < 0 || pos+sp+nul+21 > len(data) {
+			return fmt.Errorf("malformed tree object %s", sha)
+		}
+		name := string(data[pos+sp+1 : pos+sp+nul])
+		entrySha := hex.EncodeToString(data[pos+sp+nul+1 : pos+sp+nul+21])
+		pos += sp + nul + 21
+
+		switch mode {
+		case "40000":
+			err := r.flattenTree(entrySha, prefix+name+"/", out)
+			if err != nil {
+				return err
+			}
+		case "160000":
+			// submodule; skip, like the index walk does
+		default:
+			out[prefix+name] = entrySha
+		}
+	}
+	return nil
+}
+
+// --- the object store ---
+
+func (r *Reader) object(sha string) (string, []byte, error) {
+	if len(sha) != 40 {
+		return "", nil, fmt.Errorf("malformed object name %q", sha)
+	}
+
+	// Loose object first.
+	f, err := os.Open(filepath.Join(r.gitDir, "objects", sha[:2], sha[2:]))
+	if err == nil {
+		defer func() {
+			_ = f.Close()
+		}()
+		data, err := inflate(f)
+		if err != nil {
+			return "", nil, err
+		}
+		nul := bytes.IndexByte(data, 0)
+		if nul < 0 {
+			return "", nil, fmt.Errorf("malformed object %s", sha)
+		}
+		typ := strings.SplitN(string(data[:nul]), " ", 2)[0]
+		return typ, data[nul+1:], nil
+	}
+
+	// Then the packfiles.
+	idxPaths, _ := filepath.Glob(filepath.Join(r.gitDir, "objects", "pack", "*.idx"))
+	for _, idxPath := range idxPaths {
+		offset, ok, err := packOffset(idxPath, sha)
+		if err != nil {
+			return "", nil, err
+		}
+		if !ok {
+			continue
+		}
+		return r.packObject(strings.TrimSuffix(idxPath, ".idx")+".pack", offset)
+	}
+
+	return "", nil, fmt.Errorf("object %s not found", sha)
+}
+
+// packOffset looks up a sha in a version-2 pack index.
+func packOffset(idxPath string, sha string) (int64, bool, error) {
+	data, err := ioutil.ReadFile(idxPath)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(data) < 8+256*4 ||
+		!bytes.Equal(data[:4], []byte{0xff, 0x74, 0x4f, 0x63}) ||
+		binary.BigEndian.Uint32(data[4:8]) != 2 {
+		return 0, false, fmt.Errorf("unsupported pack index %s", idxPath)
+	}
+	raw, err := hex.DecodeString(sha)
+	if err != nil {
+		return 0, false, err
+	}
+
+	fanout := func(i int) int {
+		return int(binary.BigEndian.Uint32(data[8+i*4 : 8+i*4+4]))
+	}
+	total := fanout(255)
+	lo := 0
+	if raw[0] > 0 {
+		lo = fanout(int(raw[0]) - 1)
+	}
+	hi := fanout(int(raw[0]))
+
+	shas := data[8+256*4:]
+	found := -1
+	for lo < hi {
+		mid := (lo + hi) / 2
+		switch bytes.Compare(raw, shas[mid*20:mid*20+20]) {
+		case 0:
+			found = mid
+			lo = hi
+		case -1:
+			hi = mid
+		case 1:
+			lo = mid + 1
+		}
+	}
+	if found == -1 {
+		return 0, false, nil
+	}
+
+	offsets := data[8+256*4+total*20+total*4:]
+	off := binary.BigEndian.Uint32(offsets[found*4 : found*4+4])
+	if off&0x80000000 == 0 {
+		return int64(off), true, nil
+	}
+	large := offsets[total*4:]
+	i := int(off & 0x7fffffff)
+	return int64(binary.BigEndian.Uint64(large[i*8 : i*8+8])), true, nil
+}
+
+func (r *Reader) packObject(packPath string, offset int64) (string, []byte, error) {
+	f, err := os.Open(packPath)
+	if err != nil {
+		return "", nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return r.packObjectAt(f, offset)
+}
+
+var packTypeNames = map[byte]string{1: "commit", 2: "tree", 3: "blob", 4: "tag"}
+
+func (r *Reader) packObjectAt(f *os.File, offset int64) (string, []byte, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return "", nil, err
+	}
+	sr := io.NewSectionReader(f, offset, fi.Size()-offset)
+	buf := make([]byte, 1)
+	readByte := func() (byte, error) {
+		_, err := io.ReadFull(sr, buf)
+		return buf[0], err
+	}
+
+	b, err := readByte()
+	if err != nil {
+		return "", nil, err
+	}
+	typ := (b >> 4) & 7
+	// We don't need the object size, but we do need to consume the
+	// rest of the varint holding it.
+	for b&0x80 != 0 {
+		b, err = readByte()
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	switch typ {
+	case 1, 2, 3, 4:
+		data, err := inflate(sr)
+		return packTypeNames[typ], data, err
+	case 6: // delta against an earlier object in the same pack
+		b, err = readByte()
+		if err != nil {
+			return "", nil, err
+		}
+		rel := int64(b & 0x7f)
+		for b&0x80 != 0 {
+			b, err = readByte()
+			if err != nil {
+				return "", nil, err
+			}
+			rel = ((rel + 1) << 7) | int64(b&0x7f)
+		}
+		baseTyp, baseData, err := r.packObjectAt(f, offset-rel)
+		if err != nil {
+			return "", nil, err
+		}
+		delta, err := inflate(sr)
+		if err != nil {
+			return "", nil, err
+		}
+		out, err := applyDelta(baseData, delta)
+		return baseTyp, out, err
+	case 7: // delta against an object named by sha
+		shaBuf := make([]byte, 20)
+		_, err := io.ReadFull(sr, shaBuf)
+		if err != nil {
+			return "", nil, err
+		}
+		baseTyp, baseData, err := r.object(hex.EncodeToString(shaBuf))
+		if err != nil {
+			return "", nil, err
+		}
+		delta, err := inflate(sr)
+		if err != nil {
+			return "", nil, err
+		}
+		out, err := applyDelta(baseData, delta)
+		return baseTyp, out, err
+	}
+	return "", nil, fmt.Errorf("unsupported pack object type %d", typ)
+}
+
+func applyDelta(base, delta []byte) (result []byte, err error) {
+	// The copy/insert instructions index freely into both buffers;
+	// bounds failures on a corrupt delta surface here as an error
+	// rather than crashing the Tiltfile build.
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("malformed pack delta")
+		}
+	}()
+
+	pos := 0
+	readVarint := func() int {
+		v, shift := 0, uint(0)
+		for {
+			b := delta[pos]
+			pos++
+			v |= int(b&0x7f) << shift
+			shift += 7
+			if b&0x80 == 0 {
+				return v
+			}
+		}
+	}
+	readVarint() // base size; unused
+	resultSize := readVarint()
+
+	out := make([]byte, 0, resultSize)
+	for pos < len(delta) {
+		op := delta[pos]
+		pos++
+		if op&0x80 != 0 { // copy from base
+			var cpOff, cpSize int
+			for i := uint(0); i < 4; i++ {
+				if op&(1<<i) != 0 {
+					cpOff |= int(delta[pos]) << (8 * i)
+					pos++
+				}
+			}
+			for i := uint(0); i < 3; i++ {
+				if op&(0x10<<i) != 0 {
+					cpSize |= int(delta[pos]) << (8 * i)
+					pos++
+				}
+			}
+			if cpSize == 0 {
+				cpSize = 0x10000
+			}
+			out = append(out, base[cpOff:cpOff+cpSize]...)
+		} else if op != 0 { // insert literal bytes
+			out = append(out, delta[pos:pos+int(op)]...)
+			pos += int(op)
+		} else {
+			return nil, fmt.Errorf("malformed pack delta")
+		}
+	}
+	return out, nil
+}
+
+func inflate(rd io.Reader) ([]byte, error) {
+	zr, err := zlib.NewReader(rd)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = zr.Close()
+	}()
+	return ioutil.ReadAll(zr)
+}
@@ -0,0 +1,213 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/internal/testutils/tempdir"
+)
+
+func TestBranch(t *testing.T) {
+	f := newGitFixture(t)
+	defer f.TearDown()
+
+	f.git("init", "-b", "master")
+	f.WriteFile("a.txt", "hello")
+	f.git("add", ".")
+	f.git("commit", "-m", "initial")
+	f.git("checkout", "-b", "feature/thing")
+
+	b, err := f.reader().Branch()
+	require.NoError(t, err)
+	assert.Equal(t, "feature/thing", b)
+}
+
+func TestBranchDetached(t *testing.T) {
+	f := newGitFixture(t)
+	defer f.TearDown()
+
+	f.git("init", "-b", "master")
+	f.WriteFile("a.txt", "hello")
+	f.git("add", ".")
+	f.git("commit", "-m", "initial")
+	f.git("checkout", "--detach")
+
+	b, err := f.reader().Branch()
+	require.NoError(t, err)
+	assert.Equal(t, "", b)
+}
+
+func TestHeadSHA(t *testing.T) {
+	f := newGitFixture(t)
+	defer f.TearDown()
+
+	f.git("init", "-b", "master")
+	f.WriteFile("a.txt", "hello")
+	f.git("add", ".")
+	f.git("commit", "-m", "initial")
+
+	sha, err := f.reader().HeadSHA()
+	require.NoError(t, err)
+	assert.Equal(t, f.git("rev-parse", "HEAD"), sha)
+}
+
+func TestResolveRefPackedRefs(t *testing.T) {
+	f := newGitFixture(t)
+	defer f.TearDown()
+
+	f.git("init", "-b", "master")
+	f.WriteFile("a.txt", "hello")
+	f.git("add", ".")
+	f.git("commit", "-m", "initial")
+	f.git("tag", "v1.0.0")
+	f.git("pack-refs", "--all")
+
+	sha, err := f.reader().ResolveRef("v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, f.git("rev-parse", "HEAD"), sha)
+}
+
+func TestIsDirty(t *testing.T) {
+	f := newGitFixture(t)
+	defer f.TearDown()
+
+	f.git("init", "-b", "master")
+	f.WriteFile("a.txt", "hello")
+	f.git("add", ".")
+	f.git("commit", "-m", "initial")
+
+	dirty, err := f.reader().IsDirty()
+	require.NoError(t, err)
+	assert.False(t, dirty, "clean checkout")
+
+	// Untracked files don't count.
+	f.WriteFile("untracked.txt", "new")
+	dirty, err = f.reader().IsDirty()
+	require.NoError(t, err)
+	assert.False(t, dirty, "untracked file")
+
+	// An unstaged edit does.
+	f.WriteFile("a.txt", "changed")
+	dirty, err = f.reader().IsDirty()
+	require.NoError(t, err)
+	assert.True(t, dirty, "unstaged edit")
+
+	// And so does the same edit once staged.
+	f.git("add", "a.txt")
+	dirty, err = f.reader().IsDirty()
+	require.NoError(t, err)
+	assert.True(t, dirty, "staged edit")
+
+	f.git("commit", "-m", "change")
+	dirty, err = f.reader().IsDirty()
+	require.NoError(t, err)
+	assert.False(t, dirty, "clean again after commit")
+}
+
+func TestChangedFiles(t *testing.T) {
+	f := newGitFixture(t)
+	defer f.TearDown()
+
+	f.git("init", "-b", "master")
+	f.WriteFile("a.txt", "hello")
+	f.WriteFile("sub/b.txt", "world")
+	f.WriteFile("c.txt", "bye")
+	f.git("add", ".")
+	f.git("commit", "-m", "initial")
+	base := f.git("rev-parse", "HEAD")
+
+	// Committed change, staged addition, unstaged edit, and a deletion.
+	f.WriteFile("sub/b.txt", "world!")
+	f.git("commit", "-am", "edit b")
+	f.WriteFile("new.txt", "new")
+	f.git("add", "new.txt")
+	f.WriteFile("a.txt", "hello!")
+	require.NoError(t, os.Remove(f.JoinPath("c.txt")))
+
+	files, err := f.reader().ChangedFiles(base)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.txt", "c.txt", "new.txt", "sub/b.txt"}, files)
+}
+
+func TestChangedFilesAgainstBranch(t *testing.T) {
+	f := newGitFixture(t)
+	defer f.TearDown()
+
+	f.git("init", "-b", "master")
+	f.WriteFile("a.txt", "hello")
+	f.git("add", ".")
+	f.git("commit", "-m", "initial")
+	f.git("checkout", "-b", "feature")
+	f.WriteFile("feature.txt", "feature")
+	f.git("add", ".")
+	f.git("commit", "-m", "add feature")
+
+	files, err := f.reader().ChangedFiles("master")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"feature.txt"}, files)
+}
+
+func TestPackedObjects(t *testing.T) {
+	f := newGitFixture(t)
+	defer f.TearDown()
+
+	f.git("init", "-b", "master")
+	f.WriteFile("a.txt", "hello")
+	f.git("add", ".")
+	f.git("commit", "-m", "initial")
+	base := f.git("rev-parse", "HEAD")
+	f.WriteFile("a.txt", "hello again")
+	f.WriteFile("b.txt", "more")
+	f.git("add", ".")
+	f.git("commit", "-m", "second")
+
+	// Move everything into a packfile (with deltas) so the reader has
+	// no loose objects to fall back on.
+	f.git("repack", "-a", "-d", "--depth=50", "--window=50")
+
+	files, err := f.reader().ChangedFiles(base)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.txt", "b.txt"}, files)
+
+	dirty, err := f.reader().IsDirty()
+	require.NoError(t, err)
+	assert.False(t, dirty)
+}
+
+type gitFixture struct {
+	*tempdir.TempDirFixture
+	t *testing.T
+}
+
+func newGitFixture(t *testing.T) *gitFixture {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	return &gitFixture{TempDirFixture: tempdir.NewTempDirFixture(t), t: t}
+}
+
+func (f *gitFixture) git(args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = f.Path()
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		f.t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func (f *gitFixture) reader() *Reader {
+	r, err := NewReader(f.Path())
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	return r
+}
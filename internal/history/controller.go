@@ -0,0 +1,57 @@
+package history
+
+import (
+	"context"
+	"time"
+
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/logger"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// Watches the store for completed builds and persists them to the
+// history Store.
+type Controller struct {
+	history *Store
+
+	recorded map[model.ManifestName]time.Time
+}
+
+func NewController(history *Store) *Controller {
+	return &Controller{
+		history:  history,
+		recorded: make(map[model.ManifestName]time.Time),
+	}
+}
+
+var _ store.Subscriber = &Controller{}
+
+func (c *Controller) OnChange(ctx context.Context, st store.RStore) {
+	records := c.diff(st)
+	for _, r := range records {
+		if err := c.history.Append(r); err != nil {
+			logger.Get(ctx).Debugf("Error recording build history: %v", err)
+			return
+		}
+	}
+}
+
+func (c *Controller) diff(st store.RStore) []Record {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	var records []Record
+	for _, mt := range state.Targets() {
+		name := mt.Manifest.Name
+		lastBuild := mt.State.LastBuild()
+		if lastBuild.Empty() || lastBuild.FinishTime.IsZero() {
+			continue
+		}
+		if c.recorded[name] == lastBuild.FinishTime {
+			continue
+		}
+		c.recorded[name] = lastBuild.FinishTime
+		records = append(records, RecordFromBuild(name, lastBuild))
+	}
+	return records
+}
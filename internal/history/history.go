@@ -0,0 +1,153 @@
+package history
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/windmilleng/wmclient/pkg/dirs"
+
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+const fileName = "build_history.jsonl"
+
+// How many of the most recent builds count as "recent" when
+// computing trends.
+const recentWindow = 10
+
+// A single completed build, as persisted across sessions.
+type Record struct {
+	Name       string    `json:"name"`
+	StartTime  time.Time `json:"start_time"`
+	DurationMS int64     `json:"duration_ms"`
+	Reason     string    `json:"reason,omitempty"`
+	BuildTypes []string  `json:"build_types,omitempty"`
+	Error      bool      `json:"error,omitempty"`
+}
+
+func RecordFromBuild(name model.ManifestName, b model.BuildRecord) Record {
+	types := make([]string, 0, len(b.BuildTypes))
+	for _, bt := range b.BuildTypes {
+		types = append(types, string(bt))
+	}
+	return Record{
+		Name:       name.String(),
+		StartTime:  b.StartTime,
+		DurationMS: b.Duration().Milliseconds(),
+		Reason:     b.Reason.String(),
+		BuildTypes: types,
+		Error:      b.Error != nil,
+	}
+}
+
+// Persists build records in the Windmill dir as newline-delimited
+// JSON, so they survive across Tilt sessions.
+type Store struct {
+	dir *dirs.WindmillDir
+	mu  sync.Mutex
+}
+
+func NewStore(dir *dirs.WindmillDir) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) Append(r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	f, err := s.dir.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+func (s *Store) Read() ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	contents, err := s.dir.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, line := range strings.Split(contents, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			// Skip lines that didn't survive (e.g., a partial write
+			// from a crashed session).
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+// Per-resource build timing trends, for the API.
+type Trend struct {
+	Name     string `json:"name"`
+	Builds   int    `json:"builds"`
+	Failures int    `json:"failures"`
+
+	// Mean duration over all recorded builds.
+	AvgMS int64 `json:"avg_ms"`
+
+	// Mean duration over the most recent builds, so a growing build
+	// time stands out against the historical average.
+	RecentAvgMS int64 `json:"recent_avg_ms"`
+}
+
+func Trends(records []Record) []Trend {
+	byName := make(map[string][]Record)
+	for _, r := range records {
+		byName[r.Name] = append(byName[r.Name], r)
+	}
+
+	trends := make([]Trend, 0, len(byName))
+	for name, rs := range byName {
+		t := Trend{Name: name, Builds: len(rs)}
+		var total int64
+		for _, r := range rs {
+			total += r.DurationMS
+			if r.Error {
+				t.Failures++
+			}
+		}
+		t.AvgMS = total / int64(len(rs))
+
+		recent := rs
+		if len(recent) > recentWindow {
+			recent = recent[len(recent)-recentWindow:]
+		}
+		var recentTotal int64
+		for _, r := range recent {
+			recentTotal += r.DurationMS
+		}
+		t.RecentAvgMS = recentTotal / int64(len(recent))
+
+		trends = append(trends, t)
+	}
+
+	sort.Slice(trends, func(i, j int) bool { return trends[i].Name < trends[j].Name })
+	return trends
+}
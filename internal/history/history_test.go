@@ -0,0 +1,122 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/windmilleng/wmclient/pkg/dirs"
+
+	"github.com/windmilleng/tilt/internal/k8s/testyaml"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils"
+	"github.com/windmilleng/tilt/internal/testutils/manifestbuilder"
+	"github.com/windmilleng/tilt/internal/testutils/tempdir"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestAppendRead(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	require.NoError(t, f.hs.Append(Record{Name: "fe", DurationMS: 1000}))
+	require.NoError(t, f.hs.Append(Record{Name: "fe", DurationMS: 3000, Error: true}))
+
+	records, err := f.hs.Read()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "fe", records[0].Name)
+	assert.True(t, records[1].Error)
+}
+
+func TestReadEmpty(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	records, err := f.hs.Read()
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestTrends(t *testing.T) {
+	var records []Record
+	// 20 builds of "fe": the first 10 take 1s, the last 10 take 5s.
+	for i := 0; i < 20; i++ {
+		durMS := int64(1000)
+		if i >= 10 {
+			durMS = 5000
+		}
+		records = append(records, Record{Name: "fe", DurationMS: durMS})
+	}
+	records = append(records, Record{Name: "be", DurationMS: 2000, Error: true})
+
+	trends := Trends(records)
+	require.Len(t, trends, 2)
+
+	assert.Equal(t, "be", trends[0].Name)
+	assert.Equal(t, 1, trends[0].Failures)
+
+	assert.Equal(t, "fe", trends[1].Name)
+	assert.Equal(t, 20, trends[1].Builds)
+	assert.Equal(t, int64(3000), trends[1].AvgMS)
+	assert.Equal(t, int64(5000), trends[1].RecentAvgMS)
+}
+
+func TestControllerRecordsCompletedBuilds(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	st := store.NewTestingStore()
+	st.WithState(func(state *store.EngineState) {
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+	})
+
+	c := NewController(f.hs)
+	c.OnChange(f.ctx, st)
+
+	records, err := f.hs.Read()
+	require.NoError(t, err)
+	assert.Empty(t, records, "no builds yet")
+
+	finish := time.Now()
+	st.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  finish.Add(-2 * time.Second),
+			FinishTime: finish,
+			Error:      fmt.Errorf("build failed"),
+			Reason:     model.BuildReasonFlagChangedFiles,
+			BuildTypes: []model.BuildType{model.BuildTypeImage, model.BuildTypeK8s},
+		})
+	})
+
+	c.OnChange(f.ctx, st)
+	c.OnChange(f.ctx, st)
+
+	records, err = f.hs.Read()
+	require.NoError(t, err)
+	require.Len(t, records, 1, "the same build shouldn't be recorded twice")
+	assert.Equal(t, "fe", records[0].Name)
+	assert.Equal(t, int64(2000), records[0].DurationMS)
+	assert.True(t, records[0].Error)
+	assert.Equal(t, []string{"image", "k8s"}, records[0].BuildTypes)
+}
+
+type fixture struct {
+	*tempdir.TempDirFixture
+	ctx context.Context
+	hs  *Store
+}
+
+func newFixture(t *testing.T) *fixture {
+	f := tempdir.NewTempDirFixture(t)
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	return &fixture{
+		TempDirFixture: f,
+		ctx:            ctx,
+		hs:             NewStore(dirs.NewWindmillDirAt(f.Path())),
+	}
+}
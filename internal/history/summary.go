@@ -0,0 +1,71 @@
+package history
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// Print a per-resource rollup of the builds that started after `since`
+// (i.e., during the current Tilt session): how many updates ran, how many
+// were live updates vs. full rebuilds, how many failed, and the total
+// time spent building.
+func (s *Store) WriteSessionSummary(w io.Writer, since time.Time) error {
+	records, err := s.Read()
+	if err != nil {
+		return err
+	}
+
+	byName := make(map[string][]Record)
+	for _, r := range records {
+		if r.StartTime.Before(since) {
+			continue
+		}
+		byName[r.Name] = append(byName[r.Name], r)
+	}
+	if len(byName) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "Session summary:\n")
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "  RESOURCE\tUPDATES\tLIVE UPDATES\tFULL REBUILDS\tFAILURES\tBUILD TIME")
+	for _, name := range names {
+		rs := byName[name]
+		liveUpdates := 0
+		failures := 0
+		var totalMS int64
+		for _, r := range rs {
+			if isLiveUpdate(r) {
+				liveUpdates++
+			}
+			if r.Error {
+				failures++
+			}
+			totalMS += r.DurationMS
+		}
+		fmt.Fprintf(tw, "  %s\t%d\t%d\t%d\t%d\t%s\n",
+			name, len(rs), liveUpdates, len(rs)-liveUpdates, failures,
+			time.Duration(totalMS)*time.Millisecond)
+	}
+	return tw.Flush()
+}
+
+func isLiveUpdate(r Record) bool {
+	for _, bt := range r.BuildTypes {
+		if bt == string(model.BuildTypeLiveUpdate) {
+			return true
+		}
+	}
+	return false
+}
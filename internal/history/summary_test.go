@@ -0,0 +1,53 @@
+package history
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSessionSummary(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	start := time.Now()
+
+	// A build from a previous session shouldn't be counted.
+	require.NoError(t, f.hs.Append(Record{
+		Name: "fe", StartTime: start.Add(-time.Hour), DurationMS: 9000,
+	}))
+
+	require.NoError(t, f.hs.Append(Record{
+		Name: "fe", StartTime: start.Add(time.Second), DurationMS: 2000,
+		BuildTypes: []string{"image", "k8s"},
+	}))
+	require.NoError(t, f.hs.Append(Record{
+		Name: "fe", StartTime: start.Add(2 * time.Second), DurationMS: 500,
+		BuildTypes: []string{"live-update"},
+	}))
+	require.NoError(t, f.hs.Append(Record{
+		Name: "be", StartTime: start.Add(3 * time.Second), DurationMS: 1000,
+		Error: true,
+	}))
+
+	var buf bytes.Buffer
+	require.NoError(t, f.hs.WriteSessionSummary(&buf, start))
+
+	out := buf.String()
+	assert.Contains(t, out, "Session summary:")
+	assert.Regexp(t, `fe\s+2\s+1\s+1\s+0\s+2.5s`, out)
+	assert.Regexp(t, `be\s+1\s+0\s+1\s+1\s+1s`, out)
+	assert.NotContains(t, out, "9000")
+}
+
+func TestWriteSessionSummaryEmpty(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	var buf bytes.Buffer
+	require.NoError(t, f.hs.WriteSessionSummary(&buf, time.Now()))
+	assert.Empty(t, buf.String())
+}
@@ -25,6 +25,10 @@ import (
 // The main loop ensures the HUD updates at least this often
 const DefaultRefreshInterval = 100 * time.Millisecond
 
+// While the view is unchanged we still repaint occasionally, so that
+// elapsed-time displays keep ticking.
+const idleRefreshInterval = time.Second
+
 // number of arrows a pgup/dn is equivalent to
 // (we don't currently worry about trying to know how big a page is, and instead just support pgup/dn as "faster arrows"
 const pgUpDownCount = 20
@@ -46,6 +50,11 @@ type Hud struct {
 	mu               sync.RWMutex
 	isRunning        bool
 	a                *analytics.TiltAnalytics
+
+	// Whether the view or view state changed since the last render, so
+	// the refresh ticker can skip repainting an unchanged screen.
+	dirty          bool
+	lastRenderTime time.Time
 }
 
 var _ HeadsUpDisplay = (*Hud)(nil)
@@ -177,6 +186,13 @@ func (h *Hud) handleScreenEvent(ctx context.Context, dispatch func(action store.
 			case r == 'j':
 				h.activeScroller().Down()
 				h.refreshSelectedIndex()
+			case r == 'p': // cycle through [P]revious builds
+				h.recordInteraction("previous_build")
+				i, selected := h.selectedResource()
+				if i >= 0 && i < len(h.currentViewState.Resources) && len(selected.BuildHistory) > 1 {
+					rvs := &h.currentViewState.Resources[i]
+					rvs.BuildHistoryOffset = (rvs.BuildHistoryOffset + 1) % len(selected.BuildHistory)
+				}
 			case r == 'q': // [Q]uit
 				escape()
 			case r == 'R': // hidden key for recovering from printf junk during demos
@@ -259,6 +275,7 @@ func (h *Hud) handleScreenEvent(ctx context.Context, dispatch func(action store.
 		// just marking this as where sigwinch gets handled
 	}
 
+	h.dirty = true
 	h.refresh(ctx)
 	return false
 }
@@ -288,6 +305,7 @@ func (h *Hud) OnChange(ctx context.Context, st store.RStore) {
 		h.resetResourceSelection()
 	}
 	h.currentView = view
+	h.dirty = true
 	h.refreshSelectedIndex()
 }
 
@@ -300,11 +318,18 @@ func (h *Hud) Refresh(ctx context.Context) {
 // Must hold the lock
 func (h *Hud) setViewState(ctx context.Context, currentViewState view.ViewState) {
 	h.currentViewState = currentViewState
+	h.dirty = true
 	h.refresh(ctx)
 }
 
 // Must hold the lock
 func (h *Hud) refresh(ctx context.Context) {
+	if !h.dirty && time.Since(h.lastRenderTime) < idleRefreshInterval {
+		return
+	}
+	h.dirty = false
+	h.lastRenderTime = time.Now()
+
 	// TODO: We don't handle the order of resources changing
 	for len(h.currentViewState.Resources) < len(h.currentView.Resources) {
 		h.currentViewState.Resources = append(h.currentViewState.Resources, view.ResourceViewState{})
@@ -9,6 +9,7 @@ import (
 
 	"github.com/windmilleng/tilt/internal/hud/view"
 	"github.com/windmilleng/tilt/internal/rty"
+	"github.com/windmilleng/tilt/internal/store"
 	"github.com/windmilleng/tilt/pkg/model"
 	"github.com/windmilleng/tilt/pkg/model/logstore"
 )
@@ -64,6 +65,11 @@ func (v *ResourceView) resourceTitle() rty.Component {
 		l.Add(middotText())
 	}
 
+	if ts := v.titleTextState(); ts != nil {
+		l.Add(ts)
+		l.Add(middotText())
+	}
+
 	l.Add(v.titleTextBuild())
 	l.Add(middotText())
 	l.Add(v.titleTextDeploy())
@@ -150,11 +156,21 @@ func (v *ResourceView) titleTextName() rty.Component {
 }
 
 func (v *ResourceView) warnings() []string {
+	var warnings []string
 	spanID := v.res.LastBuild().SpanID
-	if spanID == "" {
-		return nil
+	if spanID != "" {
+		warnings = append(warnings, v.logReader.Warnings(spanID)...)
+	}
+	k8sInfo := v.res.K8sInfo()
+	for _, w := range k8sInfo.PodProbeWarnings {
+		warnings = append(warnings, w+"\n")
 	}
-	return v.logReader.Warnings(spanID)
+	if k8sInfo.PodMemoryPctOfLimit >= store.MemoryUsageWarningPct {
+		warnings = append(warnings, fmt.Sprintf(
+			"Memory usage %s is %d%% of the pod's limit\n",
+			k8sInfo.PodMemoryUsage, k8sInfo.PodMemoryPctOfLimit))
+	}
+	return warnings
 }
 
 func (v *ResourceView) titleText() rty.Component {
@@ -186,6 +202,21 @@ func titleTextDC(dcInfo view.DCResourceInfo) rty.Component {
 	return sb.Build()
 }
 
+// Shows the in-between lifecycle states (waiting-on-deps, deploying,
+// image-pulling, ...) that the build and deploy cells don't capture.
+func (v *ResourceView) titleTextState() rty.Component {
+	switch v.res.State.State {
+	case model.ResourceStateDisabled,
+		model.ResourceStateWaitingOnDeps,
+		model.ResourceStateDeploying,
+		model.ResourceStateWaitingForPodSchedule,
+		model.ResourceStateImagePulling,
+		model.ResourceStateDrifted:
+		return rty.TextString(string(v.res.State.State))
+	}
+	return nil
+}
+
 func (v *ResourceView) titleTextBuild() rty.Component {
 	return buildStatusCell(makeBuildStatus(v.res, v.triggerMode))
 }
@@ -294,6 +325,11 @@ func (v *ResourceView) resourceExpandedK8s() rty.Component {
 		l.Add(middotText())
 	}
 
+	if k8sInfo.PodCPUUsage != "" {
+		l.Add(resourceTextPodUsage(k8sInfo))
+		l.Add(middotText())
+	}
+
 	if len(v.res.Endpoints) > 0 && !v.endpointsNeedSecondLine() {
 		v.appendEndpoints(l)
 		l.Add(middotText())
@@ -310,6 +346,23 @@ func resourceTextPodName(k8sInfo view.K8sResourceInfo) rty.Component {
 	return sb.Build()
 }
 
+func resourceTextPodUsage(k8sInfo view.K8sResourceInfo) rty.Component {
+	sb := rty.NewStringBuilder()
+	sb.Fg(cLightText).Text("CPU ")
+	sb.Fg(tcell.ColorDefault).Text(k8sInfo.PodCPUUsage)
+	sb.Fg(cLightText).Text(" MEM ")
+
+	memFg := tcell.ColorDefault
+	if k8sInfo.PodMemoryPctOfLimit >= store.MemoryUsageWarningPct {
+		memFg = cPending
+	}
+	sb.Fg(memFg).Text(k8sInfo.PodMemoryUsage)
+	if k8sInfo.PodMemoryPctOfLimit > 0 {
+		sb.Fg(memFg).Textf(" (%d%%)", k8sInfo.PodMemoryPctOfLimit)
+	}
+	return sb.Build()
+}
+
 func resourceTextPodRestarts(k8sInfo view.K8sResourceInfo) rty.Component {
 	s := "restarts"
 	if k8sInfo.PodRestarts == 1 {
@@ -366,12 +419,23 @@ func (v *ResourceView) resourceExpandedHistory() rty.Component {
 		return rty.NewConcatLayout(rty.DirVert)
 	}
 
+	history := v.res.BuildHistory
+	offset := v.rv.BuildHistoryOffset
+	if offset >= len(history) {
+		offset = 0
+	}
+	label := "HISTORY: "
+	if offset > 0 {
+		// The user has paged back with (p).
+		label = fmt.Sprintf("HISTORY (build %d of %d): ", offset+1, len(history))
+	}
+
 	l := rty.NewConcatLayout(rty.DirHor)
-	l.Add(rty.NewStringBuilder().Fg(cLightText).Text("HISTORY: ").Build())
+	l.Add(rty.NewStringBuilder().Fg(cLightText).Text(label).Build())
 
 	rows := rty.NewConcatLayout(rty.DirVert)
 	rowCount := 0
-	if !v.res.CurrentBuild.Empty() {
+	if offset == 0 && !v.res.CurrentBuild.Empty() {
 		rows.Add(NewEditStatusLine(buildStatus{
 			edits:    v.res.CurrentBuild.Edits,
 			reason:   v.res.CurrentBuild.Reason,
@@ -381,7 +445,7 @@ func (v *ResourceView) resourceExpandedHistory() rty.Component {
 		}))
 		rowCount++
 	}
-	for _, bStatus := range v.res.BuildHistory {
+	for _, bStatus := range history[offset:] {
 		if rowCount >= 2 {
 			// at most 2 rows
 			break
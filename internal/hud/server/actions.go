@@ -14,3 +14,22 @@ type SetTiltfileArgsAction struct {
 }
 
 func (SetTiltfileArgsAction) Action() {}
+
+// An ad-hoc port forward added (or removed) at runtime, without editing
+// the Tiltfile. Forwards are keyed by local port within a resource.
+type PortForwardAction struct {
+	Name    model.ManifestName
+	Forward model.PortForward
+	Remove  bool
+}
+
+func (PortForwardAction) Action() {}
+
+// The user clicked a cmd_button (or hit its API endpoint); the engine
+// should run the button's command.
+type CommandButtonAction struct {
+	Name       model.ManifestName
+	ButtonName string
+}
+
+func (CommandButtonAction) Action() {}
@@ -21,8 +21,10 @@ import (
 
 	tiltanalytics "github.com/windmilleng/tilt/internal/analytics"
 	"github.com/windmilleng/tilt/internal/cloud"
+	"github.com/windmilleng/tilt/internal/history"
 	"github.com/windmilleng/tilt/internal/hud/webview"
 	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/objects"
 	"github.com/windmilleng/tilt/internal/store"
 	"github.com/windmilleng/tilt/pkg/assets"
 	"github.com/windmilleng/tilt/pkg/model"
@@ -60,6 +62,8 @@ type HeadsUpServer struct {
 	router            *mux.Router
 	a                 *tiltanalytics.TiltAnalytics
 	uploader          cloud.SnapshotUploader
+	buildHistory      *history.Store
+	objects           *objects.Registry
 	numWebsocketConns int32
 }
 
@@ -68,18 +72,25 @@ func ProvideHeadsUpServer(
 	store *store.Store,
 	assetServer assets.Server,
 	analytics *tiltanalytics.TiltAnalytics,
-	uploader cloud.SnapshotUploader) (*HeadsUpServer, error) {
+	uploader cloud.SnapshotUploader,
+	buildHistory *history.Store,
+	objectRegistry *objects.Registry) (*HeadsUpServer, error) {
 	r := mux.NewRouter().UseEncodedPath()
 	s := &HeadsUpServer{
-		ctx:      ctx,
-		store:    store,
-		router:   r,
-		a:        analytics,
-		uploader: uploader,
+		ctx:          ctx,
+		store:        store,
+		router:       r,
+		a:            analytics,
+		uploader:     uploader,
+		buildHistory: buildHistory,
+		objects:      objectRegistry,
 	}
 
 	r.HandleFunc("/api/view", s.ViewJSON)
 	r.HandleFunc("/api/dump/engine", s.DumpEngineJSON)
+	r.HandleFunc("/api/trends", s.TrendsJSON)
+	r.HandleFunc("/api/objects/{kind}", s.ObjectList)
+	r.HandleFunc("/api/objects/{kind}/{name}", s.ObjectGet)
 	r.HandleFunc("/api/analytics", s.HandleAnalytics)
 	r.HandleFunc("/api/analytics_opt", s.HandleAnalyticsOpt)
 	r.HandleFunc("/api/trigger", s.HandleTrigger)
@@ -90,6 +101,13 @@ func ProvideHeadsUpServer(
 	r.HandleFunc("/ws/view", s.ViewWebsocket)
 	r.HandleFunc("/api/user_started_tilt_cloud_registration", s.userStartedTiltCloudRegistration)
 	r.HandleFunc("/api/set_tiltfile_args", s.HandleSetTiltfileArgs).Methods("POST")
+	r.HandleFunc("/api/port_forward", s.HandlePortForward).Methods("POST")
+	r.HandleFunc("/api/command_button", s.HandleCommandButton).Methods("POST")
+
+	// The net/http/pprof import registers its handlers on http.DefaultServeMux
+	// as a side effect. Route profiling requests there, so `tilt dump profile`
+	// (and `go tool pprof`) can collect profiles from a running Tilt.
+	r.PathPrefix("/debug/pprof/").Handler(http.DefaultServeMux)
 
 	r.PathPrefix("/").Handler(s.cookieWrapper(assetServer))
 
@@ -119,8 +137,10 @@ func (s *HeadsUpServer) Router() http.Handler {
 
 func (s *HeadsUpServer) ViewJSON(w http.ResponseWriter, req *http.Request) {
 	state := s.store.RLockState()
-	view, err := webview.StateToProtoView(state, 0)
+	snapshot := state.CopyForView()
 	s.store.RUnlockState()
+
+	view, err := webview.StateToProtoView(snapshot, 0)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error converting view to proto: %v", err), http.StatusInternalServerError)
 		return
@@ -136,6 +156,101 @@ func (s *HeadsUpServer) ViewJSON(w http.ResponseWriter, req *http.Request) {
 }
 
 // Dump the JSON engine over http. Only intended for 'tilt dump engine'.
+// Serves per-resource build timing trends computed from the
+// cross-session build history.
+func (s *HeadsUpServer) TrendsJSON(w http.ResponseWriter, req *http.Request) {
+	records, err := s.buildHistory.Read()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error reading build history: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(w).Encode(history.Trends(records))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error rendering trends: %v", err), http.StatusInternalServerError)
+	}
+}
+
+func (s *HeadsUpServer) ObjectList(w http.ResponseWriter, req *http.Request) {
+	kind, ok := objects.KindForPath(mux.Vars(req)["kind"])
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unknown object kind: %s", mux.Vars(req)["kind"]), http.StatusNotFound)
+		return
+	}
+
+	if req.URL.Query().Get("watch") == "true" {
+		s.watchObjects(w, req, kind)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(objects.NewList(kind, s.objects.List(kind)))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error rendering object list: %v", err), http.StatusInternalServerError)
+	}
+}
+
+func (s *HeadsUpServer) ObjectGet(w http.ResponseWriter, req *http.Request) {
+	kind, ok := objects.KindForPath(mux.Vars(req)["kind"])
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unknown object kind: %s", mux.Vars(req)["kind"]), http.StatusNotFound)
+		return
+	}
+
+	name := mux.Vars(req)["name"]
+	obj, ok := s.objects.Get(kind, name)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Object not found: %s/%s", kind, name), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(obj)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error rendering object: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// Streams change events for the given kind as newline-delimited JSON,
+// as the Kubernetes API does. The current objects are sent first, as
+// ADDED events.
+func (s *HeadsUpServer) watchObjects(w http.ResponseWriter, req *http.Request, kind string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Start the watch before listing, so no event can slip between the
+	// two. A change that lands in both is sent twice, which watchers
+	// must tolerate anyway.
+	ch, cancel := s.objects.Watch(kind)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	for _, obj := range s.objects.List(kind) {
+		_ = encoder.Encode(objects.Event{Type: objects.Added, Object: obj})
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := encoder.Encode(event); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
 func (s *HeadsUpServer) DumpEngineJSON(w http.ResponseWriter, req *http.Request) {
 	state := s.store.RLockState()
 	defer s.store.RUnlockState()
@@ -149,8 +264,10 @@ func (s *HeadsUpServer) DumpEngineJSON(w http.ResponseWriter, req *http.Request)
 
 func (s *HeadsUpServer) SnapshotJSON(w http.ResponseWriter, req *http.Request) {
 	state := s.store.RLockState()
-	view, err := webview.StateToProtoView(state, 0)
+	snapshot := state.CopyForView()
 	s.store.RUnlockState()
+
+	view, err := webview.StateToProtoView(snapshot, 0)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error converting view to proto: %v", err), http.StatusInternalServerError)
 		return
@@ -234,6 +351,14 @@ func (s *HeadsUpServer) DispatchAction(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
+	state := s.store.RLockState()
+	allowExec := state.SecuritySettings.AllowExec
+	s.store.RUnlockState()
+	if !allowExec {
+		http.Error(w, "actions are disabled by security_settings() in the Tiltfile", http.StatusForbidden)
+		return
+	}
+
 	var payload actionPayload
 	decoder := json.NewDecoder(req.Body)
 	err := decoder.Decode(&payload)
@@ -279,6 +404,91 @@ func (s *HeadsUpServer) HandleTrigger(w http.ResponseWriter, req *http.Request)
 	}
 }
 
+type portForwardPayload struct {
+	ManifestName  string `json:"manifest_name"`
+	LocalPort     int    `json:"local_port"`
+	ContainerPort int    `json:"container_port"`
+	Host          string `json:"host"`
+	Remove        bool   `json:"remove"`
+}
+
+func (s *HeadsUpServer) HandlePortForward(w http.ResponseWriter, req *http.Request) {
+	var payload portForwardPayload
+
+	decoder := json.NewDecoder(req.Body)
+	err := decoder.Decode(&payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error parsing JSON payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if payload.LocalPort <= 0 {
+		http.Error(w, fmt.Sprintf("local_port must be positive, got %d", payload.LocalPort), http.StatusBadRequest)
+		return
+	}
+
+	mName := model.ManifestName(payload.ManifestName)
+	state := s.store.RLockState()
+	_, ok := state.Manifest(mName)
+	s.store.RUnlockState()
+	if !ok {
+		http.Error(w, fmt.Sprintf("no manifest found with name '%s'", mName), http.StatusBadRequest)
+		return
+	}
+
+	s.store.Dispatch(PortForwardAction{
+		Name: mName,
+		Forward: model.PortForward{
+			LocalPort:     payload.LocalPort,
+			ContainerPort: payload.ContainerPort,
+			Host:          payload.Host,
+		},
+		Remove: payload.Remove,
+	})
+}
+
+type commandButtonPayload struct {
+	ManifestName string `json:"manifest_name"`
+	ButtonName   string `json:"button_name"`
+}
+
+func (s *HeadsUpServer) HandleCommandButton(w http.ResponseWriter, req *http.Request) {
+	var payload commandButtonPayload
+
+	decoder := json.NewDecoder(req.Body)
+	err := decoder.Decode(&payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error parsing JSON payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	mName := model.ManifestName(payload.ManifestName)
+	state := s.store.RLockState()
+	m, ok := state.Manifest(mName)
+	s.store.RUnlockState()
+	if !ok {
+		http.Error(w, fmt.Sprintf("no manifest found with name '%s'", mName), http.StatusBadRequest)
+		return
+	}
+
+	found := false
+	for _, b := range m.Buttons {
+		if b.Name == payload.ButtonName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		http.Error(w, fmt.Sprintf("no button '%s' on resource '%s'", payload.ButtonName, mName), http.StatusBadRequest)
+		return
+	}
+
+	s.store.Dispatch(CommandButtonAction{
+		Name:       mName,
+		ButtonName: payload.ButtonName,
+	})
+}
+
 func SendToTriggerQueue(st store.RStore, name string, buildReason model.BuildReason) error {
 	mName := model.ManifestName(name)
 
@@ -13,16 +13,20 @@ import (
 	"time"
 
 	"github.com/windmilleng/tilt/internal/testutils"
+	"github.com/windmilleng/tilt/internal/testutils/tempdir"
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/windmilleng/wmclient/pkg/analytics"
+	"github.com/windmilleng/wmclient/pkg/dirs"
 
 	tiltanalytics "github.com/windmilleng/tilt/internal/analytics"
 	"github.com/windmilleng/tilt/internal/cloud"
 	"github.com/windmilleng/tilt/internal/cloud/cloudurl"
+	"github.com/windmilleng/tilt/internal/history"
 	"github.com/windmilleng/tilt/internal/hud/server"
+	"github.com/windmilleng/tilt/internal/objects"
 	"github.com/windmilleng/tilt/internal/store"
 	"github.com/windmilleng/tilt/pkg/assets"
 	"github.com/windmilleng/tilt/pkg/model"
@@ -420,6 +424,46 @@ func TestSetTiltfileArgs(t *testing.T) {
 	assert.Equal(t, []string{"--foo", "bar", "as df"}, action.Args)
 }
 
+func TestObjectListAndGet(t *testing.T) {
+	f := newTestFixture(t)
+
+	f.objects.Update([]objects.Object{
+		objects.Object{
+			TypeMeta: objects.TypeMeta{APIVersion: objects.APIVersion, Kind: objects.KindResource},
+			Metadata: objects.ObjectMeta{Name: "fe"},
+			Status:   objects.ResourceStatus{Building: true},
+		},
+	})
+
+	// Route through the router so mux populates the path vars.
+	req, err := http.NewRequest(http.MethodGet, "/api/objects/resources", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"kind":"ResourceList"`)
+	assert.Contains(t, rr.Body.String(), `"name":"fe"`)
+
+	req, err = http.NewRequest(http.MethodGet, "/api/objects/resources/fe", nil)
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"building":true`)
+
+	req, err = http.NewRequest(http.MethodGet, "/api/objects/resources/nope", nil)
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	req, err = http.NewRequest(http.MethodGet, "/api/objects/nope", nil)
+	require.NoError(t, err)
+	rr = httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
 type serverFixture struct {
 	t            *testing.T
 	serv         *server.HeadsUpServer
@@ -428,6 +472,7 @@ type serverFixture struct {
 	st           *store.Store
 	getActions   func() []store.Action
 	snapshotHTTP *fakeHTTPClient
+	objects      *objects.Registry
 }
 
 func newTestFixture(t *testing.T) *serverFixture {
@@ -441,7 +486,10 @@ func newTestFixture(t *testing.T) *serverFixture {
 	snapshotHTTP := &fakeHTTPClient{}
 	addr := cloudurl.Address("nonexistent.example.com")
 	uploader := cloud.NewSnapshotUploader(snapshotHTTP, addr)
-	serv, err := server.ProvideHeadsUpServer(context.Background(), st, assets.NewFakeServer(), ta, uploader)
+	temp := tempdir.NewTempDirFixture(t)
+	buildHistory := history.NewStore(dirs.NewWindmillDirAt(temp.Path()))
+	objectRegistry := objects.NewRegistry()
+	serv, err := server.ProvideHeadsUpServer(context.Background(), st, assets.NewFakeServer(), ta, uploader, buildHistory, objectRegistry)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -454,6 +502,7 @@ func newTestFixture(t *testing.T) *serverFixture {
 		st:           st,
 		getActions:   getActions,
 		snapshotHTTP: snapshotHTTP,
+		objects:      objectRegistry,
 	}
 }
 
@@ -131,17 +131,20 @@ func (ws *WebsocketSubscriber) OnChange(ctx context.Context, s store.RStore) {
 	checkpoint := ws.readClientCheckpoint()
 
 	state := s.RLockState()
-	view, err := webview.StateToProtoView(state, checkpoint)
-	tiltStartTime := state.TiltStartTime
+	snapshot := state.CopyForView()
 	s.RUnlockState()
+
+	// Convert and serialize from the snapshot, so a big view or a slow
+	// client doesn't hold the state lock and block reducers.
+	view, err := webview.StateToProtoView(snapshot, checkpoint)
 	if err != nil {
 		logger.Get(ctx).Infof("error converting view to proto for websocket: %v", err)
 		return
 	}
 
-	ws.setTiltStartTime(tiltStartTime)
+	ws.setTiltStartTime(snapshot.TiltStartTime)
 
-	if view.NeedsAnalyticsNudge && !state.AnalyticsNudgeSurfaced {
+	if view.NeedsAnalyticsNudge && !snapshot.AnalyticsNudgeSurfaced {
 		// If we're showing the nudge and no one's told the engine
 		// state about it yet... tell the engine state.
 		s.Dispatch(store.AnalyticsNudgeSurfacedAction{})
@@ -57,6 +57,18 @@ type K8sResourceInfo struct {
 	PodRestarts        int
 	SpanID             logstore.SpanID
 	RunStatus          model.RuntimeStatus
+
+	// Formatted liveness/readiness probe failures for the current pod.
+	PodProbeWarnings []string
+
+	// Usage from metrics-server, pre-formatted (e.g. "12m", "34Mi").
+	// Empty when the cluster doesn't serve pod metrics.
+	PodCPUUsage    string
+	PodMemoryUsage string
+
+	// How much of the pod's memory limit is in use, as a percentage.
+	// Zero when there's no limit or no usage sample.
+	PodMemoryPctOfLimit int
 }
 
 var _ ResourceInfoView = K8sResourceInfo{}
@@ -116,6 +128,9 @@ type Resource struct {
 	LastDeployTime     time.Time
 	TriggerMode        model.TriggerMode
 
+	// Structured lifecycle state, with a reason for any waiting states.
+	State model.ResourceStateStatus
+
 	BuildHistory []model.BuildRecord
 	CurrentBuild model.BuildRecord
 
@@ -299,4 +314,8 @@ const (
 
 type ResourceViewState struct {
 	CollapseState CollapseState
+
+	// Which build record the expanded history pane starts at, so the
+	// user can page back through older builds.
+	BuildHistoryOffset int
 }
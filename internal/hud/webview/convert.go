@@ -124,8 +124,14 @@ func StateToProtoView(s store.EngineState, logCheckpoint logstore.Checkpoint) (*
 			HasPendingChanges:  hasPendingChanges,
 			Facets:             model.FacetsToProto(facets),
 			Queued:             s.ManifestInTriggerQueue(name),
+			Buttons:            model.ButtonsToProto(mt.Manifest.Buttons),
+			Links:              linksToProto(mt.Manifest.Links, ms.LinkHealth),
 		}
 
+		rState := store.ResourceStateFor(s, mt)
+		r.ResourceState = string(rState.State)
+		r.ResourceStateReason = rState.Reason
+
 		err = protoPopulateResourceInfoView(mt, r)
 		if err != nil {
 			return nil, err
@@ -178,6 +184,31 @@ func StateToProtoView(s store.EngineState, logCheckpoint logstore.Checkpoint) (*
 	return ret, nil
 }
 
+func linksToProto(links []model.Link, health map[string]bool) []*proto_webview.Link {
+	if len(links) == 0 {
+		return nil
+	}
+	result := make([]*proto_webview.Link, len(links))
+	for i, l := range links {
+		healthStatus := ""
+		if l.HasHealthCheck() {
+			healthStatus = "unknown"
+			if up, ok := health[l.URL]; ok {
+				if up {
+					healthStatus = "up"
+				} else {
+					healthStatus = "down"
+				}
+			}
+		}
+		result[i] = &proto_webview.Link{
+			Url:          l.URL,
+			HealthStatus: healthStatus,
+		}
+	}
+	return result
+}
+
 func tiltfileResourceProtoView(s store.EngineState) (*proto_webview.Resource, error) {
 	ltfb := s.TiltfileState.LastBuild()
 	ctfb := s.TiltfileState.CurrentBuild
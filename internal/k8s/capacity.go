@@ -0,0 +1,80 @@
+package k8s
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// How much room the cluster has left, from one namespace's point of view.
+type ClusterCapacity struct {
+	// Remaining request quota (hard minus used) across the namespace's
+	// ResourceQuotas. Nil when no quota constrains that resource.
+	QuotaCPU    *resource.Quantity
+	QuotaMemory *resource.Quantity
+
+	// Allocatable CPU/memory summed across all nodes.
+	AllocatableCPU    resource.Quantity
+	AllocatableMemory resource.Quantity
+}
+
+func (c K8sClient) ClusterCapacity(ctx context.Context, n Namespace) (ClusterCapacity, error) {
+	cap := ClusterCapacity{}
+
+	quotaList, err := c.core.ResourceQuotas(n.String()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return cap, errors.Wrap(err, "listing resource quotas")
+	}
+	for _, q := range quotaList.Items {
+		cap.QuotaCPU = minQuantity(cap.QuotaCPU,
+			quotaRemaining(q.Status, v1.ResourceRequestsCPU, v1.ResourceCPU))
+		cap.QuotaMemory = minQuantity(cap.QuotaMemory,
+			quotaRemaining(q.Status, v1.ResourceRequestsMemory, v1.ResourceMemory))
+	}
+
+	nodeList, err := c.core.Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return cap, errors.Wrap(err, "listing nodes")
+	}
+	for _, node := range nodeList.Items {
+		if cpu, ok := node.Status.Allocatable[v1.ResourceCPU]; ok {
+			cap.AllocatableCPU.Add(cpu)
+		}
+		if mem, ok := node.Status.Allocatable[v1.ResourceMemory]; ok {
+			cap.AllocatableMemory.Add(mem)
+		}
+	}
+
+	return cap, nil
+}
+
+// What's left of the quota's hard limit on the given resource. Quotas can
+// spell the request limit either "requests.cpu" or plain "cpu"; check both.
+// Nil if the quota doesn't constrain the resource.
+func quotaRemaining(status v1.ResourceQuotaStatus, names ...v1.ResourceName) *resource.Quantity {
+	for _, name := range names {
+		hard, ok := status.Hard[name]
+		if !ok {
+			continue
+		}
+		remaining := hard.DeepCopy()
+		if used, ok := status.Used[name]; ok {
+			remaining.Sub(used)
+		}
+		return &remaining
+	}
+	return nil
+}
+
+func minQuantity(a *resource.Quantity, b *resource.Quantity) *resource.Quantity {
+	if a == nil {
+		return b
+	}
+	if b == nil || a.Cmp(*b) <= 0 {
+		return a
+	}
+	return b
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/browser"
 	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -35,6 +37,7 @@ import (
 
 	"github.com/windmilleng/tilt/internal/container"
 	"github.com/windmilleng/tilt/pkg/logger"
+	"github.com/windmilleng/tilt/pkg/model"
 )
 
 type Namespace string
@@ -69,7 +72,7 @@ type Client interface {
 	//
 	// Returns entities in the order that they were applied (which may be different
 	// than they were passed in) and with UUIDs from the Kube API
-	Upsert(ctx context.Context, entities []K8sEntity) ([]K8sEntity, error)
+	Upsert(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error)
 
 	// Deletes all given entities.
 	//
@@ -81,6 +84,9 @@ type Client interface {
 
 	PodByID(ctx context.Context, podID PodID, n Namespace) (*v1.Pod, error)
 
+	// All pods in the given namespace matching the label selector.
+	ListPods(ctx context.Context, n Namespace, ls labels.Selector) ([]v1.Pod, error)
+
 	// Creates a channel where all changes to the pod are brodcast.
 	// Takes a pod as input, to indicate the version of the pod where we start watching.
 	WatchPod(ctx context.Context, pod *v1.Pod) (watch.Interface, error)
@@ -95,12 +101,37 @@ type Client interface {
 
 	WatchServices(ctx context.Context, lps labels.Selector) (<-chan *v1.Service, error)
 
-	WatchEvents(ctx context.Context) (<-chan *v1.Event, error)
+	WatchJobs(ctx context.Context, lps labels.Selector) (<-chan *batchv1.Job, error)
+
+	WatchEvents(ctx context.Context, lps labels.Selector) (<-chan *v1.Event, error)
 
 	ConnectedToCluster(ctx context.Context) error
 
+	// Checks whether the current user can perform the given verb on the given
+	// resource type in Tilt's configured namespace, via a
+	// SelfSubjectAccessReview. An empty group means the core API group.
+	CanI(ctx context.Context, verb string, group string, resource string) (bool, error)
+
+	// Like CanI, but takes a kind (resolved to its resource via the cluster's
+	// RESTMapper) and an explicit namespace. Cluster-scoped kinds are checked
+	// at cluster scope regardless of the namespace passed in.
+	CanIKind(ctx context.Context, verb string, gvk schema.GroupVersionKind, ns Namespace) (bool, error)
+
 	ContainerRuntime(ctx context.Context) container.Runtime
 
+	// The image names (including repo digests) that a node reports in its
+	// status. Used to probe whether the cluster shares the local docker daemon.
+	NodeImages(ctx context.Context) ([]string, error)
+
+	// The namespace's remaining request quota and the cluster's total
+	// allocatable capacity, for pre-deploy capacity warnings.
+	ClusterCapacity(ctx context.Context, n Namespace) (ClusterCapacity, error)
+
+	// Current CPU/memory usage for pods matching the label selector, from
+	// metrics-server. Returns a NotFound error when the cluster doesn't
+	// serve the metrics API.
+	PodMetrics(ctx context.Context, n Namespace, ls labels.Selector) ([]PodMetrics, error)
+
 	// Some clusters support a local image registry that we can push to.
 	LocalRegistry(ctx context.Context) container.Registry
 
@@ -110,6 +141,11 @@ type Client interface {
 	Exec(ctx context.Context, podID PodID, cName container.Name, n Namespace, cmd []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error
 }
 
+// ServerSideValidation makes Upsert validate every update with a server-side
+// dry-run apply before the real apply mutates the cluster, so that
+// admission-webhook and schema rejections surface as build failures.
+type ServerSideValidation bool
+
 type K8sClient struct {
 	env               Env
 	kubectlRunner     kubectlRunner
@@ -123,6 +159,7 @@ type K8sClient struct {
 	registryAsync     *registryAsync
 	nodeIPAsync       *nodeIPAsync
 	drm               meta.RESTMapper
+	validate          ServerSideValidation
 }
 
 var _ Client = K8sClient{}
@@ -136,7 +173,8 @@ func ProvideK8sClient(
 	configNamespace Namespace,
 	runner kubectlRunner,
 	mkClient MinikubeClient,
-	clientLoader clientcmd.ClientConfig) Client {
+	clientLoader clientcmd.ClientConfig,
+	validate ServerSideValidation) Client {
 	if env == EnvNone {
 		// No k8s, so no need to get any further configs
 		return &explodingClient{err: fmt.Errorf("Kubernetes context not set in %s", clientLoader.ConfigAccess().GetLoadingPrecedence())}
@@ -187,6 +225,7 @@ func ProvideK8sClient(
 		nodeIPAsync:       nodeIPAsync,
 		dynamic:           di,
 		drm:               drm,
+		validate:          validate,
 	}
 }
 
@@ -241,17 +280,31 @@ func ServiceURL(service *v1.Service, ip NodeIP) (*url.URL, error) {
 	return nil, nil
 }
 
-func (k K8sClient) Upsert(ctx context.Context, entities []K8sEntity) ([]K8sEntity, error) {
+func (k K8sClient) Upsert(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error) {
 	span, ctx := opentracing.StartSpanFromContext(ctx, "daemon-k8sUpsert")
 	defer span.Finish()
 
-	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	if timeout == 0 {
+		timeout = model.DefaultK8sUpsertTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	result := make([]K8sEntity, 0, len(entities))
 
 	mutable, immutable := MutableAndImmutableEntities(entities)
 
+	// Validate the mutable entities before anything touches the cluster. We
+	// skip the immutable ones: they get replaced wholesale below, so a dry-run
+	// apply of an existing Job would fail on its immutable fields even though
+	// the real update will succeed.
+	if k.validate && len(mutable) > 0 {
+		err := k.validateEntities(ctx, mutable)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if len(mutable) > 0 {
 		newEntities, err := k.applyEntitiesAndMaybeForce(ctx, mutable)
 		if err != nil {
@@ -271,6 +324,18 @@ func (k K8sClient) Upsert(ctx context.Context, entities []K8sEntity) ([]K8sEntit
 	return result, nil
 }
 
+// validateEntities runs a server-side dry-run apply, so that the API server's
+// admission webhooks and schema validation see the objects before the real
+// apply does.
+func (k K8sClient) validateEntities(ctx context.Context, entities []K8sEntity) error {
+	_, stderr, err := k.actOnEntities(ctx, []string{"apply", "--dry-run=server"}, entities)
+	if err != nil {
+		names := strings.Join(UniqueNames(entities, 2), ", ")
+		return errors.Wrapf(err, "validating %s with a server-side dry-run:\nstderr: %s", names, stderr)
+	}
+	return nil
+}
+
 func (k K8sClient) forceReplaceEntities(ctx context.Context, entities []K8sEntity) ([]K8sEntity, error) {
 	stdout, stderr, err := k.actOnEntities(ctx, []string{"replace", "-o", "yaml", "--force"}, entities)
 	if err != nil {
@@ -319,6 +384,54 @@ func (k K8sClient) ConnectedToCluster(ctx context.Context) error {
 	return nil
 }
 
+func (k K8sClient) CanI(ctx context.Context, verb string, group string, resource string) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: k.configNamespace.String(),
+				Verb:      verb,
+				Group:     group,
+				Resource:  resource,
+			},
+		},
+	}
+
+	resp, err := k.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, errors.Wrap(err, "performing SelfSubjectAccessReview")
+	}
+	return resp.Status.Allowed, nil
+}
+
+func (k K8sClient) CanIKind(ctx context.Context, verb string, gvk schema.GroupVersionKind, ns Namespace) (bool, error) {
+	rm, err := k.drm.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false, errors.Wrapf(err, "error mapping %s/%s", gvk.Group, gvk.Kind)
+	}
+
+	namespace := ns.String()
+	if rm.Scope.Name() == meta.RESTScopeNameRoot {
+		namespace = ""
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     gvk.Group,
+				Resource:  rm.Resource.Resource,
+			},
+		},
+	}
+
+	resp, err := k.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, errors.Wrap(err, "performing SelfSubjectAccessReview")
+	}
+	return resp.Status.Allowed, nil
+}
+
 // We're using kubectl, so we only get stderr, not structured errors.
 //
 // Take a wild guess if the update is failing due to immutable field errors.
@@ -0,0 +1,127 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// Hands out Clients for kube contexts beyond the one Tilt started against,
+// so that different resources in one session can deploy to different clusters.
+//
+// Clients are built lazily, the first time a context is requested, and cached
+// for the life of the process.
+type ClientPool struct {
+	defaultContext KubeContext
+	defaultClient  Client
+	defaultFetcher OwnerFetcher
+
+	mu            sync.Mutex
+	clients       map[KubeContext]Client
+	ownerFetchers map[KubeContext]OwnerFetcher
+}
+
+func ProvideClientPool(defaultContext KubeContext, defaultClient Client, defaultFetcher OwnerFetcher) *ClientPool {
+	return &ClientPool{
+		defaultContext: defaultContext,
+		defaultClient:  defaultClient,
+		defaultFetcher: defaultFetcher,
+		clients:        make(map[KubeContext]Client),
+		ownerFetchers:  make(map[KubeContext]OwnerFetcher),
+	}
+}
+
+// The client for the given kube context. An empty context means the one
+// Tilt started against.
+func (p *ClientPool) ClientFor(ctx context.Context, kubeContext KubeContext) Client {
+	if kubeContext == "" || kubeContext == p.defaultContext {
+		return p.defaultClient
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	client, ok := p.clients[kubeContext]
+	if !ok {
+		client = p.newClient(ctx, kubeContext)
+		p.clients[kubeContext] = client
+	}
+	return client
+}
+
+// An OwnerFetcher backed by the client for the given kube context, so that
+// owner lookups hit the cluster the object actually lives in.
+func (p *ClientPool) OwnerFetcherFor(ctx context.Context, kubeContext KubeContext) OwnerFetcher {
+	if kubeContext == "" || kubeContext == p.defaultContext {
+		return p.defaultFetcher
+	}
+
+	p.mu.Lock()
+	fetcher, ok := p.ownerFetchers[kubeContext]
+	p.mu.Unlock()
+	if ok {
+		return fetcher
+	}
+
+	client := p.ClientFor(ctx, kubeContext)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fetcher, ok = p.ownerFetchers[kubeContext]
+	if !ok {
+		fetcher = ProvideOwnerFetcher(client)
+		p.ownerFetchers[kubeContext] = fetcher
+	}
+	return fetcher
+}
+
+// Pre-seed the client for a kube context. For testing.
+func (p *ClientPool) SetClientForTesting(kubeContext KubeContext, client Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clients[kubeContext] = client
+	p.ownerFetchers[kubeContext] = ProvideOwnerFetcher(client)
+}
+
+// Build a client against the given kube context, reusing the same loading
+// rules as the default client but overriding the current context.
+func (p *ClientPool) newClient(ctx context.Context, kubeContext KubeContext) Client {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.DefaultClientConfig = &clientcmd.DefaultClientConfig
+
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: string(kubeContext)}
+	clientLoader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+
+	apiConfig, err := clientLoader.RawConfig()
+	if err != nil {
+		return &explodingClient{err: err}
+	}
+
+	if _, ok := apiConfig.Contexts[string(kubeContext)]; !ok {
+		return &explodingClient{err: fmt.Errorf("kube context %q not found in %s",
+			kubeContext, clientLoader.ConfigAccess().GetLoadingPrecedence())}
+	}
+
+	// Env detection keys off the current context of the config.
+	apiConfig.CurrentContext = string(kubeContext)
+	env := ProvideEnv(ctx, &apiConfig)
+
+	restConfig := ProvideRESTConfig(clientLoader)
+	clientset := ProvideClientset(restConfig)
+	pfClient := ProvidePortForwardClient(restConfig, clientset)
+	runner := realKubectlRunner{kubeContext: kubeContext}
+	mkClient := ProvideMinikubeClient(kubeContext)
+	namespace := ProvideConfigNamespace(clientLoader)
+
+	return ProvideK8sClient(ctx, env, restConfig, clientset, pfClient, namespace, runner, mkClient, clientLoader, p.validation())
+}
+
+// Secondary clients inherit the validation setting of the default client.
+func (p *ClientPool) validation() ServerSideValidation {
+	if kCli, ok := p.defaultClient.(K8sClient); ok {
+		return kCli.validate
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -40,7 +41,7 @@ func TestUpsert(t *testing.T) {
 	f := newClientTestFixture(t)
 	postgres, err := ParseYAMLFromString(testyaml.PostgresYAML)
 	assert.Nil(t, err)
-	_, err = f.client.Upsert(f.ctx, postgres)
+	_, err = f.client.Upsert(f.ctx, postgres, time.Minute)
 	assert.Nil(t, err)
 	assert.Equal(t, 1, len(f.runner.calls))
 	assert.Equal(t, []string{"apply", "-o", "yaml", "-f", "-"}, f.runner.calls[0].argv)
@@ -52,7 +53,7 @@ func TestUpsertMutableAndImmutable(t *testing.T) {
 	eJob := MustParseYAMLFromString(t, testyaml.JobYAML)[0]
 	eNamespace := MustParseYAMLFromString(t, testyaml.MyNamespaceYAML)[0]
 
-	_, err := f.client.Upsert(f.ctx, []K8sEntity{eDeploy, eJob, eNamespace})
+	_, err := f.client.Upsert(f.ctx, []K8sEntity{eDeploy, eJob, eNamespace}, time.Minute)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -84,7 +85,7 @@ func TestUpsertAnnotationTooLong(t *testing.T) {
 	postgres := MustParseYAMLFromString(t, testyaml.PostgresYAML)
 
 	f.setStderr(`The ConfigMap "postgres-config" is invalid: metadata.annotations: Too long: must have at most 262144 bytes`)
-	_, err := f.client.Upsert(f.ctx, postgres)
+	_, err := f.client.Upsert(f.ctx, postgres, time.Minute)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -103,13 +104,46 @@ func TestUpsertAnnotationTooLong(t *testing.T) {
 	}
 }
 
+func TestUpsertServerSideValidation(t *testing.T) {
+	f := newClientTestFixture(t)
+	f.client.validate = true
+	postgres, err := ParseYAMLFromString(testyaml.PostgresYAML)
+	assert.Nil(t, err)
+
+	_, err = f.client.Upsert(f.ctx, postgres, time.Minute)
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(f.runner.calls)) {
+		assert.Equal(t, []string{"apply", "--dry-run=server", "-f", "-"}, f.runner.calls[0].argv)
+		assert.Equal(t, []string{"apply", "-o", "yaml", "-f", "-"}, f.runner.calls[1].argv)
+	}
+}
+
+func TestUpsertServerSideValidationRejected(t *testing.T) {
+	f := newClientTestFixture(t)
+	f.client.validate = true
+	postgres, err := ParseYAMLFromString(testyaml.PostgresYAML)
+	assert.Nil(t, err)
+
+	errStr := `Error from server (BadRequest): error when creating "STDIN": admission webhook "check.example.com" denied the request`
+	f.setStderr(errStr)
+	_, err = f.client.Upsert(f.ctx, postgres, time.Minute)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), errStr)
+		assert.Contains(t, err.Error(), "server-side dry-run")
+	}
+	// The rejection happens before anything mutates the cluster.
+	if assert.Equal(t, 1, len(f.runner.calls)) {
+		assert.Equal(t, []string{"apply", "--dry-run=server", "-f", "-"}, f.runner.calls[0].argv)
+	}
+}
+
 func TestUpsertStatefulsetForbidden(t *testing.T) {
 	f := newClientTestFixture(t)
 	postgres, err := ParseYAMLFromString(testyaml.PostgresYAML)
 	assert.Nil(t, err)
 
 	f.setStderr(`The StatefulSet "postgres" is invalid: spec: Forbidden: updates to statefulset spec for fields other than 'replicas', 'template', and 'updateStrategy' are forbidden.`)
-	_, err = f.client.Upsert(f.ctx, postgres)
+	_, err = f.client.Upsert(f.ctx, postgres, time.Minute)
 	if assert.Nil(t, err) && assert.Equal(t, 3, len(f.runner.calls)) {
 		assert.Equal(t, []string{"apply", "-o", "yaml", "-f", "-"}, f.runner.calls[0].argv)
 		assert.Equal(t, []string{"delete", "--ignore-not-found=true", "-f", "-"}, f.runner.calls[1].argv)
@@ -128,7 +162,7 @@ func TestUpsertToTerminatingNamespaceForbidden(t *testing.T) {
 	errStr := `Error from server (Forbidden): error when creating "STDIN": deployments.apps "sancho" is forbidden: unable to create new content in namespace sancho-ns because it is being terminated`
 	f.setStderr(errStr)
 
-	_, err = f.client.Upsert(f.ctx, postgres)
+	_, err = f.client.Upsert(f.ctx, postgres, time.Minute)
 	if assert.NotNil(t, err) {
 		assert.Contains(t, err.Error(), errStr)
 	}
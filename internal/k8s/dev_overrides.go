@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/apps/v1beta1"
+	"k8s.io/api/apps/v1beta2"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Production YAML often asks for several replicas of each Deployment. In
+// development, extra replicas just slow down the deploy and fight over
+// ports, so dev_overrides() scales every Deployment down to one.
+func ScaleDeploymentToOneReplica(entity K8sEntity) K8sEntity {
+	one := int32(1)
+	switch entity.Obj.(type) {
+	case *appsv1.Deployment:
+		entity = entity.DeepCopy()
+		entity.Obj.(*appsv1.Deployment).Spec.Replicas = &one
+		return entity
+	case *v1beta1.Deployment:
+		entity = entity.DeepCopy()
+		entity.Obj.(*v1beta1.Deployment).Spec.Replicas = &one
+		return entity
+	case *v1beta2.Deployment:
+		entity = entity.DeepCopy()
+		entity.Obj.(*v1beta2.Deployment).Spec.Replicas = &one
+		return entity
+	}
+	return entity
+}
+
+// Strips the resource limits and requests from every container in the
+// entity. Production limits are often too tight for dev builds (debug
+// binaries, hot reloaders), and requests can make a workload unschedulable
+// on a small local cluster.
+func StripResourceRequirements(entity K8sEntity) (K8sEntity, error) {
+	entity = entity.DeepCopy()
+	containers, err := extractContainers(&entity)
+	if err != nil {
+		return K8sEntity{}, err
+	}
+
+	for _, container := range containers {
+		container.Resources = v1.ResourceRequirements{}
+	}
+	return entity, nil
+}
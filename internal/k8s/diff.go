@@ -0,0 +1,271 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// EntityDiff describes how one version of an entity differs from another,
+// as lists of field paths (e.g., "spec.template.spec.containers").
+type EntityDiff struct {
+	// Display name of the entity, e.g. "sancho:deployment".
+	Name string
+
+	Added   []string
+	Changed []string
+	Removed []string
+}
+
+func (d EntityDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// SetDiff describes how a render differs from the previously-applied one.
+type SetDiff struct {
+	// Entities that are new in this render.
+	Created []string
+
+	// Entities that dropped out of the render.
+	Deleted []string
+
+	Modified []EntityDiff
+}
+
+func (d SetDiff) Empty() bool {
+	return len(d.Created) == 0 && len(d.Deleted) == 0 && len(d.Modified) == 0
+}
+
+func (d SetDiff) String() string {
+	b := &strings.Builder{}
+	for _, ed := range d.Modified {
+		fmt.Fprintf(b, "%s\n", ed.Name)
+		for _, f := range ed.Added {
+			fmt.Fprintf(b, "  + %s\n", f)
+		}
+		for _, f := range ed.Changed {
+			fmt.Fprintf(b, "  ~ %s\n", f)
+		}
+		for _, f := range ed.Removed {
+			fmt.Fprintf(b, "  - %s\n", f)
+		}
+	}
+	for _, name := range d.Created {
+		fmt.Fprintf(b, "%s (created)\n", name)
+	}
+	for _, name := range d.Deleted {
+		fmt.Fprintf(b, "%s (deleted)\n", name)
+	}
+	return b.String()
+}
+
+// DiffEntitySets matches the entities of the old render up with the entities
+// of the new one (by name, kind, and namespace) and diffs each pair.
+func DiffEntitySets(oldEntities []K8sEntity, newEntities []K8sEntity) (SetDiff, error) {
+	oldByKey := make(map[string]K8sEntity, len(oldEntities))
+	for _, e := range oldEntities {
+		oldByKey[diffKey(e)] = e
+	}
+
+	result := SetDiff{}
+	newKeys := make(map[string]bool, len(newEntities))
+	for _, e := range newEntities {
+		key := diffKey(e)
+		newKeys[key] = true
+
+		old, ok := oldByKey[key]
+		if !ok {
+			result.Created = append(result.Created, diffDisplayName(e))
+			continue
+		}
+
+		ed, err := DiffEntities(old, e)
+		if err != nil {
+			return SetDiff{}, err
+		}
+		if !ed.Empty() {
+			result.Modified = append(result.Modified, ed)
+		}
+	}
+
+	for _, e := range oldEntities {
+		if !newKeys[diffKey(e)] {
+			result.Deleted = append(result.Deleted, diffDisplayName(e))
+		}
+	}
+
+	return result, nil
+}
+
+// DiffEntities computes the fields added, changed, and removed between two
+// versions of the same entity.
+//
+// For registered types, the comparison is strategic-merge-aware: list
+// elements are matched up by their merge key (like the API server does on
+// apply), so reordering a container list doesn't show up as a change.
+// Unregistered types fall back to a plain structural diff.
+func DiffEntities(old K8sEntity, new K8sEntity) (EntityDiff, error) {
+	oldMap, err := diffableMap(old)
+	if err != nil {
+		return EntityDiff{}, err
+	}
+	newMap, err := diffableMap(new)
+	if err != nil {
+		return EntityDiff{}, err
+	}
+
+	d := EntityDiff{Name: diffDisplayName(new)}
+
+	_, oldUnstructured := old.Obj.(runtime.Unstructured)
+	_, newUnstructured := new.Obj.(runtime.Unstructured)
+	strategic := !oldUnstructured && !newUnstructured &&
+		reflect.TypeOf(old.Obj) == reflect.TypeOf(new.Obj)
+
+	if strategic {
+		oldJSON, err := json.Marshal(oldMap)
+		if err != nil {
+			return EntityDiff{}, errors.Wrap(err, "diffing entities")
+		}
+		newJSON, err := json.Marshal(newMap)
+		if err != nil {
+			return EntityDiff{}, errors.Wrap(err, "diffing entities")
+		}
+		patchJSON, err := strategicpatch.CreateTwoWayMergePatch(oldJSON, newJSON, old.Obj)
+		if err == nil {
+			patch := map[string]interface{}{}
+			err = json.Unmarshal(patchJSON, &patch)
+			if err != nil {
+				return EntityDiff{}, errors.Wrap(err, "diffing entities")
+			}
+			classifyPatch(patch, oldMap, "", &d)
+			sortDiffFields(&d)
+			return d, nil
+		}
+		// The type doesn't support strategic merge; fall through to the
+		// structural diff.
+	}
+
+	diffMaps(oldMap, newMap, "", &d)
+	sortDiffFields(&d)
+	return d, nil
+}
+
+// The entity as a JSON-shaped map, with the server-populated fields stripped.
+// Apply output carries status, UIDs, timestamps, etc. that never appear in a
+// fresh render; diffing them would be noise.
+func diffableMap(e K8sEntity) (map[string]interface{}, error) {
+	data, err := json.Marshal(e.Obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "diffing entities")
+	}
+	m := map[string]interface{}{}
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, errors.Wrap(err, "diffing entities")
+	}
+
+	delete(m, "status")
+	meta, ok := m["metadata"].(map[string]interface{})
+	if ok {
+		for _, f := range []string{
+			"uid", "resourceVersion", "generation", "creationTimestamp", "selfLink", "managedFields",
+		} {
+			delete(meta, f)
+		}
+		annotations, ok := meta["annotations"].(map[string]interface{})
+		if ok {
+			delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+			delete(annotations, "deployment.kubernetes.io/revision")
+			if len(annotations) == 0 {
+				delete(meta, "annotations")
+			}
+		}
+	}
+	return m, nil
+}
+
+// Walk a two-way merge patch, classifying each field against the old object:
+// null means the field went away, a key the old object doesn't have is an
+// addition, and everything else is a change.
+func classifyPatch(patch map[string]interface{}, old map[string]interface{}, path string, d *EntityDiff) {
+	for k, v := range patch {
+		// Skip strategic-merge directives ($patch, $setElementOrder/..., etc.)
+		if strings.HasPrefix(k, "$") {
+			continue
+		}
+
+		p := joinFieldPath(path, k)
+		oldV, inOld := old[k]
+		if v == nil {
+			if inOld {
+				d.Removed = append(d.Removed, p)
+			}
+			continue
+		}
+		if !inOld {
+			d.Added = append(d.Added, p)
+			continue
+		}
+		vMap, vIsMap := v.(map[string]interface{})
+		oldVMap, oldVIsMap := oldV.(map[string]interface{})
+		if vIsMap && oldVIsMap {
+			classifyPatch(vMap, oldVMap, p, d)
+			continue
+		}
+		d.Changed = append(d.Changed, p)
+	}
+}
+
+// A plain structural diff, for types without strategic merge metadata.
+func diffMaps(old map[string]interface{}, new map[string]interface{}, path string, d *EntityDiff) {
+	for k, newV := range new {
+		p := joinFieldPath(path, k)
+		oldV, inOld := old[k]
+		if !inOld {
+			d.Added = append(d.Added, p)
+			continue
+		}
+		newVMap, newVIsMap := newV.(map[string]interface{})
+		oldVMap, oldVIsMap := oldV.(map[string]interface{})
+		if newVIsMap && oldVIsMap {
+			diffMaps(oldVMap, newVMap, p, d)
+			continue
+		}
+		if !reflect.DeepEqual(oldV, newV) {
+			d.Changed = append(d.Changed, p)
+		}
+	}
+	for k := range old {
+		if _, inNew := new[k]; !inNew {
+			d.Removed = append(d.Removed, joinFieldPath(path, k))
+		}
+	}
+}
+
+func joinFieldPath(path string, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func sortDiffFields(d *EntityDiff) {
+	sort.Strings(d.Added)
+	sort.Strings(d.Changed)
+	sort.Strings(d.Removed)
+}
+
+func diffKey(e K8sEntity) string {
+	gvk := e.GVK()
+	return fmt.Sprintf("%s/%s/%s/%s", gvk.Group, gvk.Kind, e.Namespace(), e.Name())
+}
+
+func diffDisplayName(e K8sEntity) string {
+	return fmt.Sprintf("%s:%s", e.Name(), strings.ToLower(e.GVK().Kind))
+}
@@ -0,0 +1,100 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/windmilleng/tilt/internal/k8s/testyaml"
+)
+
+func TestDiffEntitiesChangedFields(t *testing.T) {
+	old := MustParseYAMLFromString(t, testyaml.SanchoYAML)[0]
+
+	newYAML := strings.Replace(testyaml.SanchoYAML, "replicas: 1", "replicas: 3", 1)
+	newYAML = strings.Replace(newYAML, "app: sancho\nspec:", "app: sancho\n    owner: me\nspec:", 1)
+	new := MustParseYAMLFromString(t, newYAML)[0]
+
+	d, err := DiffEntities(old, new)
+	require.NoError(t, err)
+	assert.Equal(t, "sancho:deployment", d.Name)
+	assert.Equal(t, []string{"metadata.labels.owner"}, d.Added)
+	assert.Equal(t, []string{"spec.replicas"}, d.Changed)
+	assert.Empty(t, d.Removed)
+}
+
+func TestDiffEntitiesRemovedField(t *testing.T) {
+	old := MustParseYAMLFromString(t, testyaml.SanchoYAML)[0]
+
+	newYAML := strings.Replace(testyaml.SanchoYAML, "  replicas: 1\n", "", 1)
+	new := MustParseYAMLFromString(t, newYAML)[0]
+
+	d, err := DiffEntities(old, new)
+	require.NoError(t, err)
+	assert.Empty(t, d.Added)
+	assert.Empty(t, d.Changed)
+	assert.Equal(t, []string{"spec.replicas"}, d.Removed)
+}
+
+func TestDiffEntitiesIgnoresContainerOrder(t *testing.T) {
+	old := MustParseYAMLFromString(t, testyaml.SanchoSidecarYAML)[0]
+
+	// Strategic merge matches containers by name, so reordering the list
+	// isn't a change.
+	new := old.DeepCopy()
+	containers := new.Obj.(*appsv1.Deployment).Spec.Template.Spec.Containers
+	require.Len(t, containers, 2)
+	containers[0], containers[1] = containers[1], containers[0]
+
+	d, err := DiffEntities(old, new)
+	require.NoError(t, err)
+	assert.True(t, d.Empty(), "expected no diff, got %+v", d)
+}
+
+func TestDiffEntitiesIgnoresServerFields(t *testing.T) {
+	// Apply output carries server-populated fields that never show up in a
+	// fresh render; they shouldn't register as removals.
+	appliedYAML := strings.Replace(testyaml.SanchoYAML,
+		"metadata:\n  name: sancho\n",
+		"metadata:\n  name: sancho\n  uid: 11111111-2222-3333-4444-555555555555\n  resourceVersion: \"42\"\n", 1)
+	old := MustParseYAMLFromString(t, appliedYAML)[0]
+	new := MustParseYAMLFromString(t, testyaml.SanchoYAML)[0]
+
+	d, err := DiffEntities(old, new)
+	require.NoError(t, err)
+	assert.True(t, d.Empty(), "expected no diff, got %+v", d)
+}
+
+func TestDiffEntitySets(t *testing.T) {
+	old := MustParseYAMLFromString(t, testyaml.SanchoYAML+"\n---\n"+testyaml.DoggosDeploymentYaml)
+
+	newYAML := strings.Replace(testyaml.SanchoYAML, "replicas: 1", "replicas: 3", 1)
+	new := MustParseYAMLFromString(t, newYAML+"\n---\n"+testyaml.MyNamespaceYAML)
+
+	d, err := DiffEntitySets(old, new)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mynamespace:namespace"}, d.Created)
+	assert.Equal(t, []string{"doggos:deployment"}, d.Deleted)
+	require.Len(t, d.Modified, 1)
+	assert.Equal(t, []string{"spec.replicas"}, d.Modified[0].Changed)
+
+	expected := `sancho:deployment
+  ~ spec.replicas
+mynamespace:namespace (created)
+doggos:deployment (deleted)
+`
+	assert.Equal(t, expected, d.String())
+}
+
+func TestDiffEntitySetsNoChanges(t *testing.T) {
+	old := MustParseYAMLFromString(t, testyaml.SanchoYAML)
+	new := MustParseYAMLFromString(t, testyaml.SanchoYAML)
+
+	d, err := DiffEntitySets(old, new)
+	require.NoError(t, err)
+	assert.True(t, d.Empty())
+	assert.Equal(t, "", d.String())
+}
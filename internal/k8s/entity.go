@@ -37,6 +37,8 @@ type k8sMeta interface {
 	GetUID() types.UID
 	GetLabels() map[string]string
 	GetOwnerReferences() []metav1.OwnerReference
+	GetGeneration() int64
+	GetResourceVersion() string
 	SetNamespace(ns string)
 }
 
@@ -47,6 +49,8 @@ func (emptyMeta) GetNamespace() string                        { return "" }
 func (emptyMeta) GetUID() types.UID                           { return "" }
 func (emptyMeta) GetLabels() map[string]string                { return make(map[string]string) }
 func (emptyMeta) GetOwnerReferences() []metav1.OwnerReference { return nil }
+func (emptyMeta) GetGeneration() int64                        { return 0 }
+func (emptyMeta) GetResourceVersion() string                  { return "" }
 func (emptyMeta) SetNamespace(ns string)                      {}
 
 var _ k8sMeta = emptyMeta{}
@@ -209,6 +213,16 @@ func (e K8sEntity) UID() types.UID {
 	return e.meta().GetUID()
 }
 
+// The object's generation, bumped by the apiserver on spec changes.
+// Zero for kinds that don't track a generation (e.g., ConfigMap).
+func (e K8sEntity) Generation() int64 {
+	return e.meta().GetGeneration()
+}
+
+func (e K8sEntity) ResourceVersion() string {
+	return e.meta().GetResourceVersion()
+}
+
 func (e K8sEntity) Labels() map[string]string {
 	return e.meta().GetLabels()
 }
@@ -7,8 +7,10 @@ import (
 
 	"github.com/docker/distribution/reference"
 	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/windmilleng/tilt/internal/container"
@@ -21,7 +23,7 @@ type explodingClient struct {
 	err error
 }
 
-func (ec *explodingClient) Upsert(ctx context.Context, entities []K8sEntity) ([]K8sEntity, error) {
+func (ec *explodingClient) Upsert(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error) {
 	return nil, errors.Wrap(ec.err, "could not set up k8s client")
 }
 
@@ -45,6 +47,10 @@ func (ec *explodingClient) PodByID(ctx context.Context, podID PodID, n Namespace
 	return nil, errors.Wrap(ec.err, "could not set up k8s client")
 }
 
+func (ec *explodingClient) ListPods(ctx context.Context, n Namespace, ls labels.Selector) ([]v1.Pod, error) {
+	return nil, errors.Wrap(ec.err, "could not set up k8s client")
+}
+
 func (ec *explodingClient) WatchPod(ctx context.Context, pod *v1.Pod) (watch.Interface, error) {
 	return nil, errors.Wrap(ec.err, "could not set up k8s client")
 }
@@ -65,7 +71,11 @@ func (ec *explodingClient) WatchServices(ctx context.Context, lps labels.Selecto
 	return nil, errors.Wrap(ec.err, "could not set up k8s client")
 }
 
-func (ec *explodingClient) WatchEvents(ctx context.Context) (<-chan *v1.Event, error) {
+func (ec *explodingClient) WatchJobs(ctx context.Context, lps labels.Selector) (<-chan *batchv1.Job, error) {
+	return nil, errors.Wrap(ec.err, "could not set up k8s client")
+}
+
+func (ec *explodingClient) WatchEvents(ctx context.Context, lps labels.Selector) (<-chan *v1.Event, error) {
 	return nil, errors.Wrap(ec.err, "could not set up k8s client")
 }
 
@@ -73,10 +83,30 @@ func (ec *explodingClient) ConnectedToCluster(ctx context.Context) error {
 	return errors.Wrap(ec.err, "could not set up k8s client")
 }
 
+func (ec *explodingClient) CanI(ctx context.Context, verb string, group string, resource string) (bool, error) {
+	return false, errors.Wrap(ec.err, "could not set up k8s client")
+}
+
+func (ec *explodingClient) CanIKind(ctx context.Context, verb string, gvk schema.GroupVersionKind, ns Namespace) (bool, error) {
+	return false, errors.Wrap(ec.err, "could not set up k8s client")
+}
+
 func (ec *explodingClient) ContainerRuntime(ctx context.Context) container.Runtime {
 	return container.RuntimeUnknown
 }
 
+func (ec *explodingClient) NodeImages(ctx context.Context) ([]string, error) {
+	return nil, errors.Wrap(ec.err, "could not set up k8s client")
+}
+
+func (ec *explodingClient) ClusterCapacity(ctx context.Context, n Namespace) (ClusterCapacity, error) {
+	return ClusterCapacity{}, errors.Wrap(ec.err, "could not set up k8s client")
+}
+
+func (ec *explodingClient) PodMetrics(ctx context.Context, n Namespace, ls labels.Selector) ([]PodMetrics, error) {
+	return nil, errors.Wrap(ec.err, "could not set up k8s client")
+}
+
 func (ec *explodingClient) LocalRegistry(ctx context.Context) container.Registry {
 	return container.Registry{}
 }
@@ -11,9 +11,11 @@ import (
 	"github.com/docker/distribution/reference"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/windmilleng/tilt/internal/container"
@@ -54,17 +56,33 @@ type FakeK8sClient struct {
 	serviceWatcherMu sync.Mutex
 	serviceWatches   []fakeServiceWatch
 
+	jobWatcherMu sync.Mutex
+	jobWatches   []fakeJobWatch
+
 	eventsCh       chan *v1.Event
 	EventsWatchErr error
 
 	UpsertError      error
 	LastUpsertResult []K8sEntity
 
-	Runtime    container.Runtime
-	Registry   container.Registry
-	FakeNodeIP NodeIP
+	Runtime        container.Runtime
+	Registry       container.Registry
+	FakeNodeIP     NodeIP
+	FakeNodeImages []string
+
+	FakeClusterCapacity ClusterCapacity
+
+	// Metrics returned by PodMetrics. When PodMetricsError is set, it's
+	// returned instead.
+	PodMetricsList  []PodMetrics
+	PodMetricsError error
+
+	// Pods returned by ListPods, after filtering by label selector.
+	PodList []v1.Pod
 
 	entityByName            map[string]K8sEntity
+	deniedAccess            map[string]bool
+	deniedKindAccess        map[string]bool
 	getByReferenceCallCount int
 
 	ExecCalls  []ExecCall
@@ -89,6 +107,11 @@ type fakePodWatch struct {
 	ch chan ObjectUpdate
 }
 
+type fakeJobWatch struct {
+	ls labels.Selector
+	ch chan *batchv1.Job
+}
+
 func (c *FakeK8sClient) EmitService(ls labels.Selector, s *v1.Service) {
 	c.podWatcherMu.Lock()
 	defer c.podWatcherMu.Unlock()
@@ -121,7 +144,39 @@ func (c *FakeK8sClient) WatchServices(ctx context.Context, ls labels.Selector) (
 	return ch, nil
 }
 
-func (c *FakeK8sClient) WatchEvents(ctx context.Context) (<-chan *v1.Event, error) {
+func (c *FakeK8sClient) EmitJob(ls labels.Selector, j *batchv1.Job) {
+	c.jobWatcherMu.Lock()
+	defer c.jobWatcherMu.Unlock()
+	for _, w := range c.jobWatches {
+		if SelectorEqual(ls, w.ls) {
+			w.ch <- j
+		}
+	}
+}
+
+func (c *FakeK8sClient) WatchJobs(ctx context.Context, ls labels.Selector) (<-chan *batchv1.Job, error) {
+	c.jobWatcherMu.Lock()
+	ch := make(chan *batchv1.Job, 20)
+	c.jobWatches = append(c.jobWatches, fakeJobWatch{ls, ch})
+	c.jobWatcherMu.Unlock()
+
+	go func() {
+		// when ctx is canceled, remove the label selector from the list of watched label selectors
+		<-ctx.Done()
+		c.jobWatcherMu.Lock()
+		var newWatches []fakeJobWatch
+		for _, e := range c.jobWatches {
+			if !SelectorEqual(e.ls, ls) {
+				newWatches = append(newWatches, e)
+			}
+		}
+		c.jobWatches = newWatches
+		c.jobWatcherMu.Unlock()
+	}()
+	return ch, nil
+}
+
+func (c *FakeK8sClient) WatchEvents(ctx context.Context, ls labels.Selector) (<-chan *v1.Event, error) {
 	if c.EventsWatchErr != nil {
 		err := c.EventsWatchErr
 		c.EventsWatchErr = nil
@@ -204,7 +259,7 @@ func (c *FakeK8sClient) ConnectedToCluster(ctx context.Context) error {
 	return nil
 }
 
-func (c *FakeK8sClient) Upsert(ctx context.Context, entities []K8sEntity) ([]K8sEntity, error) {
+func (c *FakeK8sClient) Upsert(ctx context.Context, entities []K8sEntity, timeout time.Duration) ([]K8sEntity, error) {
 	if c.UpsertError != nil {
 		return nil, c.UpsertError
 	}
@@ -244,6 +299,30 @@ func (c *FakeK8sClient) Delete(ctx context.Context, entities []K8sEntity) error
 	return nil
 }
 
+// Verbs (in "verb group/resource" form) that CanI should report as denied.
+func (c *FakeK8sClient) DenyAccess(verb string, group string, resource string) {
+	if c.deniedAccess == nil {
+		c.deniedAccess = make(map[string]bool)
+	}
+	c.deniedAccess[verb+" "+group+"/"+resource] = true
+}
+
+func (c *FakeK8sClient) CanI(ctx context.Context, verb string, group string, resource string) (bool, error) {
+	return !c.deniedAccess[verb+" "+group+"/"+resource], nil
+}
+
+// Verbs (in "verb Kind namespace" form) that CanIKind should report as denied.
+func (c *FakeK8sClient) DenyKindAccess(verb string, kind string, ns Namespace) {
+	if c.deniedKindAccess == nil {
+		c.deniedKindAccess = make(map[string]bool)
+	}
+	c.deniedKindAccess[verb+" "+kind+" "+ns.String()] = true
+}
+
+func (c *FakeK8sClient) CanIKind(ctx context.Context, verb string, gvk schema.GroupVersionKind, ns Namespace) (bool, error) {
+	return !c.deniedKindAccess[verb+" "+gvk.Kind+" "+ns.String()], nil
+}
+
 func (c *FakeK8sClient) InjectEntityByName(entities ...K8sEntity) {
 	if c.entityByName == nil {
 		c.entityByName = make(map[string]K8sEntity)
@@ -289,6 +368,16 @@ func (c *FakeK8sClient) PodByID(ctx context.Context, pID PodID, n Namespace) (*v
 	return nil, nil
 }
 
+func (c *FakeK8sClient) ListPods(ctx context.Context, n Namespace, ls labels.Selector) ([]v1.Pod, error) {
+	var result []v1.Pod
+	for _, p := range c.PodList {
+		if ls.Matches(labels.Set(p.Labels)) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
 func FakePodStatus(image reference.NamedTagged, phase string) v1.PodStatus {
 	return v1.PodStatus{
 		Phase: v1.PodPhase(phase),
@@ -346,6 +435,21 @@ func (c *FakeK8sClient) ContainerRuntime(ctx context.Context) container.Runtime
 	return container.RuntimeDocker
 }
 
+func (c *FakeK8sClient) NodeImages(ctx context.Context) ([]string, error) {
+	return c.FakeNodeImages, nil
+}
+
+func (c *FakeK8sClient) ClusterCapacity(ctx context.Context, n Namespace) (ClusterCapacity, error) {
+	return c.FakeClusterCapacity, nil
+}
+
+func (c *FakeK8sClient) PodMetrics(ctx context.Context, n Namespace, ls labels.Selector) ([]PodMetrics, error) {
+	if c.PodMetricsError != nil {
+		return nil, c.PodMetricsError
+	}
+	return c.PodMetricsList, nil
+}
+
 func (c *FakeK8sClient) LocalRegistry(ctx context.Context) container.Registry {
 	return c.Registry
 }
@@ -28,6 +28,75 @@ func InjectImagePullPolicy(entity K8sEntity, policy v1.PullPolicy) (K8sEntity, e
 	return entity, nil
 }
 
+// Like InjectImagePullPolicy, but only touches containers whose image
+// matches one of the given selectors. Used by dev_overrides() to fix the
+// pull policy of locally-built images without disturbing third-party ones.
+func InjectImagePullPolicyMatching(entity K8sEntity, policy v1.PullPolicy, selectors []container.RefSelector) (K8sEntity, error) {
+	entity = entity.DeepCopy()
+	containers, err := extractContainers(&entity)
+	if err != nil {
+		return K8sEntity{}, err
+	}
+
+	for _, c := range containers {
+		existingRef, err := container.ParseNamed(c.Image)
+		if err != nil {
+			continue
+		}
+
+		for _, selector := range selectors {
+			if selector.Matches(existingRef) {
+				c.ImagePullPolicy = policy
+				break
+			}
+		}
+	}
+	return entity, nil
+}
+
+// Iterate through the fields of a k8s entity and rewrite Docker Hub
+// images to pull through the given registry mirror. Images matching one
+// of the skip selectors (i.e., images Tilt builds itself) are left
+// alone, so they can still be matched for injection at deploy time.
+func InjectRegistryMirror(entity K8sEntity, mirrorHost string, skip []container.RefSelector) (K8sEntity, bool, error) {
+	entity = entity.DeepCopy()
+	containers, err := extractContainers(&entity)
+	if err != nil {
+		return K8sEntity{}, false, err
+	}
+
+	replaced := false
+	for _, c := range containers {
+		ref, err := container.ParseNamed(c.Image)
+		if err != nil {
+			// Malformed images will get a better error later, at deploy time.
+			continue
+		}
+
+		skipped := false
+		for _, sel := range skip {
+			if sel.Matches(ref) {
+				skipped = true
+				break
+			}
+		}
+		if skipped {
+			continue
+		}
+
+		newRef, r, err := container.ReplaceWithMirror(ref, mirrorHost)
+		if err != nil {
+			return K8sEntity{}, false, err
+		}
+		if r {
+			c.Image = container.FamiliarString(newRef)
+			replaced = true
+		}
+	}
+
+	return entity, replaced, nil
+}
+
 // Iterate through the fields of a k8s entity and
 // replace a image name with its digest.
 //
@@ -197,6 +266,40 @@ func InjectCommandAndArgs(entity K8sEntity, ref reference.Named, cmd model.Cmd,
 	return e, nil
 }
 
+// InjectCommandAndArgsToFirstContainer overrides the command and/or args of
+// the first container of every pod template in the entity.
+//
+// Used for workload-level overrides from k8s_resource, where there's no image
+// ref to select a container by. The first container is the workload's primary
+// container by k8s convention, so sidecars are left alone.
+//
+// Returns true if at least one container was rewritten.
+func InjectCommandAndArgsToFirstContainer(entity K8sEntity, cmd model.Cmd, args model.OverrideArgs) (K8sEntity, bool, error) {
+	entity = entity.DeepCopy()
+
+	podSpecs, err := ExtractPods(&entity)
+	if err != nil {
+		return K8sEntity{}, false, err
+	}
+
+	injected := false
+	for _, podSpec := range podSpecs {
+		if len(podSpec.Containers) == 0 {
+			continue
+		}
+
+		c := &podSpec.Containers[0]
+		if !cmd.Empty() {
+			c.Command = cmd.Argv
+		}
+		if args.ShouldOverride {
+			c.Args = args.Args
+		}
+		injected = true
+	}
+	return entity, injected, nil
+}
+
 func injectCommandInContainers(entity K8sEntity, selector container.RefSelector, cmd model.Cmd, args model.OverrideArgs) (K8sEntity, bool, error) {
 	var injected bool
 	containers, err := extractContainers(&entity)
@@ -12,6 +12,15 @@ const ManagedByValue = "tilt"
 
 const ManifestNameLabel = "tilt-manifest"
 
+// Which kube context Tilt deployed this object to. Only set when the
+// resource targets a context other than the one Tilt started against.
+const KubeContextLabel = "tilt-kube-context"
+
+// Marks a namespace that Tilt generated for a single developer (see the
+// dev_namespace builtin). `tilt down` deletes these namespaces even
+// without --delete-namespaces, since nothing else lives in them.
+const DevNamespaceLabel = "tilt-dev-namespace"
+
 func TiltManagedByLabel() model.LabelPair {
 	return model.LabelPair{
 		Key:   ManagedByLabel,
@@ -19,6 +28,22 @@ func TiltManagedByLabel() model.LabelPair {
 	}
 }
 
+func TiltKubeContextLabel(kubeContext KubeContext) model.LabelPair {
+	return model.LabelPair{
+		Key:   KubeContextLabel,
+		Value: string(kubeContext),
+	}
+}
+
+// Which Tilt resource this object (and its pods) belongs to, so commands
+// like `tilt exec` can find them without a running Tilt session.
+func TiltManifestLabel(name string) model.LabelPair {
+	return model.LabelPair{
+		Key:   ManifestNameLabel,
+		Value: name,
+	}
+}
+
 func ManagedByTiltSelector() labels.Selector {
 	return labels.Set{ManagedByLabel: ManagedByValue}.AsSelector()
 }
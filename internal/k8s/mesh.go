@@ -0,0 +1,20 @@
+package k8s
+
+import (
+	"github.com/windmilleng/tilt/internal/container"
+)
+
+// Container names used by the sidecar proxies that service meshes inject
+// into pods (istioctl kube-inject, Linkerd's proxy injector, etc.).
+//
+// Tilt didn't put these containers there, so they shouldn't be candidates
+// for live_update, log streaming, or exec -- but the pod isn't ready until
+// they are, so they still count for readiness.
+var meshSidecarNames = map[container.Name]bool{
+	"istio-proxy":   true,
+	"linkerd-proxy": true,
+}
+
+func IsMeshSidecar(name container.Name) bool {
+	return meshSidecarNames[name]
+}
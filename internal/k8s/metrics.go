@@ -0,0 +1,109 @@
+package k8s
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/windmilleng/tilt/internal/container"
+)
+
+// The pod metrics API served by metrics-server. We read it through the
+// dynamic client, because the metrics clientset isn't vendored.
+var podMetricsGVR = schema.GroupVersionResource{
+	Group:    "metrics.k8s.io",
+	Version:  "v1beta1",
+	Resource: "pods",
+}
+
+// One pod's current resource usage, as reported by metrics-server.
+type PodMetrics struct {
+	PodID      PodID
+	Containers []ContainerMetrics
+}
+
+type ContainerMetrics struct {
+	Name   container.Name
+	CPU    resource.Quantity
+	Memory resource.Quantity
+}
+
+// Usage summed across the pod's containers.
+func (m PodMetrics) CPU() resource.Quantity {
+	total := resource.Quantity{}
+	for _, c := range m.Containers {
+		total.Add(c.CPU)
+	}
+	return total
+}
+
+func (m PodMetrics) Memory() resource.Quantity {
+	total := resource.Quantity{}
+	for _, c := range m.Containers {
+		total.Add(c.Memory)
+	}
+	return total
+}
+
+// Current usage for all pods in the namespace matching the label selector.
+//
+// Returns the list error unwrapped, so the caller can tell a cluster
+// without metrics-server (NotFound) apart from a real failure.
+func (c K8sClient) PodMetrics(ctx context.Context, n Namespace, ls labels.Selector) ([]PodMetrics, error) {
+	list, err := c.dynamic.Resource(podMetricsGVR).Namespace(n.String()).
+		List(ctx, metav1.ListOptions{LabelSelector: ls.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PodMetrics, 0, len(list.Items))
+	for _, item := range list.Items {
+		m, err := podMetricsFromUnstructured(item)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing pod metrics")
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+func podMetricsFromUnstructured(item unstructured.Unstructured) (PodMetrics, error) {
+	m := PodMetrics{PodID: PodID(item.GetName())}
+
+	containers, _, err := unstructured.NestedSlice(item.Object, "containers")
+	if err != nil {
+		return PodMetrics{}, err
+	}
+
+	for _, c := range containers {
+		cMap, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(cMap, "name")
+		cm := ContainerMetrics{Name: container.Name(name)}
+
+		if s, ok, _ := unstructured.NestedString(cMap, "usage", "cpu"); ok {
+			q, err := resource.ParseQuantity(s)
+			if err != nil {
+				return PodMetrics{}, err
+			}
+			cm.CPU = q
+		}
+		if s, ok, _ := unstructured.NestedString(cMap, "usage", "memory"); ok {
+			q, err := resource.ParseQuantity(s)
+			if err != nil {
+				return PodMetrics{}, err
+			}
+			cm.Memory = q
+		}
+
+		m.Containers = append(m.Containers, cm)
+	}
+	return m, nil
+}
@@ -0,0 +1,56 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestPodMetricsFromUnstructured(t *testing.T) {
+	item := unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "fe-pod"},
+		"containers": []interface{}{
+			map[string]interface{}{
+				"name": "fe",
+				"usage": map[string]interface{}{
+					"cpu":    "12m",
+					"memory": "34Mi",
+				},
+			},
+			map[string]interface{}{
+				"name": "istio-proxy",
+				"usage": map[string]interface{}{
+					"cpu":    "3m",
+					"memory": "16Mi",
+				},
+			},
+		},
+	}}
+
+	m, err := podMetricsFromUnstructured(item)
+	require.NoError(t, err)
+	assert.Equal(t, PodID("fe-pod"), m.PodID)
+	require.Len(t, m.Containers, 2)
+	cpu, memory := m.CPU(), m.Memory()
+	assert.Equal(t, int64(15), cpu.MilliValue())
+	assert.Equal(t, int64(50*1024*1024), memory.Value())
+}
+
+func TestPodMetricsFromUnstructuredBadQuantity(t *testing.T) {
+	item := unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "fe-pod"},
+		"containers": []interface{}{
+			map[string]interface{}{
+				"name": "fe",
+				"usage": map[string]interface{}{
+					"cpu": "not-a-quantity",
+				},
+			},
+		},
+	}}
+
+	_, err := podMetricsFromUnstructured(item)
+	assert.Error(t, err)
+}
@@ -8,6 +8,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/watch"
 
 	"github.com/windmilleng/tilt/internal/container"
@@ -45,6 +46,19 @@ func (k K8sClient) PodByID(ctx context.Context, pID PodID, n Namespace) (*v1.Pod
 	return pod, err
 }
 
+func (k K8sClient) ListPods(ctx context.Context, n Namespace, ls labels.Selector) ([]v1.Pod, error) {
+	podList, err := k.core.Pods(n.String()).List(ctx, metav1.ListOptions{
+		LabelSelector: ls.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range podList.Items {
+		FixContainerStatusImages(&podList.Items[i])
+	}
+	return podList.Items, nil
+}
+
 func PodIDFromPod(pod *v1.Pod) PodID {
 	return PodID(pod.ObjectMeta.Name)
 }
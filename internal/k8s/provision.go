@@ -0,0 +1,166 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/windmilleng/tilt/pkg/logger"
+)
+
+// The cluster tools we know how to drive.
+const (
+	ProvisionToolKIND     = "kind"
+	ProvisionToolK3D      = "k3d"
+	ProvisionToolVcluster = "vcluster"
+)
+
+// Provisions development clusters by shelling out to kind, k3d, or
+// vcluster, so `tilt up` can bring up its own cluster when the configured
+// context doesn't exist yet.
+//
+// kind and k3d create a real local cluster. vcluster creates a virtual
+// cluster inside whatever cluster the current kube context points at,
+// which gives each developer an isolated slice of a shared cluster.
+type ClusterProvisioner struct {
+	tool string
+}
+
+func NewClusterProvisioner(tool string) (*ClusterProvisioner, error) {
+	switch tool {
+	case ProvisionToolKIND, ProvisionToolK3D, ProvisionToolVcluster:
+		return &ClusterProvisioner{tool: tool}, nil
+	}
+	return nil, fmt.Errorf("unknown cluster tool %q (valid: %s, %s, %s)",
+		tool, ProvisionToolKIND, ProvisionToolK3D, ProvisionToolVcluster)
+}
+
+// The kube context the tool writes for a cluster with the given name.
+func (p *ClusterProvisioner) ContextName(clusterName string) KubeContext {
+	if p.tool == ProvisionToolVcluster {
+		return KubeContext(fmt.Sprintf("vcluster_%s", clusterName))
+	}
+	return KubeContext(fmt.Sprintf("%s-%s", p.tool, clusterName))
+}
+
+func (p *ClusterProvisioner) Exists(ctx context.Context, clusterName string) (bool, error) {
+	var cmd *exec.Cmd
+	switch p.tool {
+	case ProvisionToolKIND:
+		cmd = exec.CommandContext(ctx, "kind", "get", "clusters")
+	case ProvisionToolK3D:
+		cmd = exec.CommandContext(ctx, "k3d", "cluster", "list", "--no-headers")
+	case ProvisionToolVcluster:
+		cmd = exec.CommandContext(ctx, "vcluster", "list")
+	}
+
+	out := &bytes.Buffer{}
+	cmd.Stdout = out
+	cmd.Stderr = logger.Get(ctx).Writer(logger.DebugLvl)
+	err := cmd.Run()
+	if err != nil {
+		return false, errors.Wrapf(err, "listing %s clusters", p.tool)
+	}
+
+	for _, line := range strings.Split(out.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == clusterName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Creates the cluster and waits for it to be ready. kind and k3d switch
+// the kubeconfig's current context to the new cluster on success; vcluster
+// leaves the host context alone, and the virtual cluster is reached via
+// the kubeconfig that Kubeconfig() fetches.
+func (p *ClusterProvisioner) Create(ctx context.Context, clusterName string) error {
+	var cmd *exec.Cmd
+	switch p.tool {
+	case ProvisionToolKIND:
+		cmd = exec.CommandContext(ctx, "kind", "create", "cluster", "--name", clusterName, "--wait", "2m")
+	case ProvisionToolK3D:
+		cmd = exec.CommandContext(ctx, "k3d", "cluster", "create", clusterName, "--wait", "--timeout", "2m")
+	case ProvisionToolVcluster:
+		cmd = exec.CommandContext(ctx, "vcluster", "create", clusterName, "--connect=false")
+	}
+
+	w := logger.NewMutexWriter(logger.Get(ctx).Writer(logger.InfoLvl))
+	cmd.Stdout = w
+	cmd.Stderr = w
+	err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "creating %s cluster %q", p.tool, clusterName)
+	}
+	return nil
+}
+
+// Kubeconfig writes a kubeconfig file pointing into the named cluster and
+// returns its path. Returns "" for tools that switch the default
+// kubeconfig's current context instead (kind, k3d).
+//
+// For vcluster, this is the only way into the virtual cluster: the host
+// context keeps pointing at the physical cluster, and the virtual
+// cluster's API server is only described by the kubeconfig that
+// `vcluster connect` prints.
+func (p *ClusterProvisioner) Kubeconfig(ctx context.Context, clusterName string) (string, error) {
+	if p.tool != ProvisionToolVcluster {
+		return "", nil
+	}
+
+	cmd := exec.CommandContext(ctx, "vcluster", "connect", clusterName, "--print")
+	out := &bytes.Buffer{}
+	cmd.Stdout = out
+	cmd.Stderr = logger.Get(ctx).Writer(logger.DebugLvl)
+	err := cmd.Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "fetching kubeconfig for vcluster %q", clusterName)
+	}
+
+	return WriteTempKubeconfig(out.Bytes())
+}
+
+// Writes a kubeconfig to a temp file, for wiring a provisioned cluster
+// into the session via $KUBECONFIG.
+func WriteTempKubeconfig(content []byte) (string, error) {
+	f, err := ioutil.TempFile("", "tilt-kubeconfig-")
+	if err != nil {
+		return "", errors.Wrap(err, "writing kubeconfig")
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	_, err = f.Write(content)
+	if err != nil {
+		return "", errors.Wrap(err, "writing kubeconfig")
+	}
+	return f.Name(), nil
+}
+
+func (p *ClusterProvisioner) Delete(ctx context.Context, clusterName string) error {
+	var cmd *exec.Cmd
+	switch p.tool {
+	case ProvisionToolKIND:
+		cmd = exec.CommandContext(ctx, "kind", "delete", "cluster", "--name", clusterName)
+	case ProvisionToolK3D:
+		cmd = exec.CommandContext(ctx, "k3d", "cluster", "delete", clusterName)
+	case ProvisionToolVcluster:
+		cmd = exec.CommandContext(ctx, "vcluster", "delete", clusterName)
+	}
+
+	w := logger.NewMutexWriter(logger.Get(ctx).Writer(logger.InfoLvl))
+	cmd.Stdout = w
+	cmd.Stderr = w
+	err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "deleting %s cluster %q", p.tool, clusterName)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClusterProvisioner(t *testing.T) {
+	_, err := NewClusterProvisioner("kind")
+	require.NoError(t, err)
+
+	_, err = NewClusterProvisioner("k3d")
+	require.NoError(t, err)
+
+	_, err = NewClusterProvisioner("minikube")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `unknown cluster tool "minikube"`)
+	}
+}
+
+func TestClusterProvisionerContextName(t *testing.T) {
+	p, err := NewClusterProvisioner("kind")
+	require.NoError(t, err)
+	assert.Equal(t, KubeContext("kind-tilt"), p.ContextName("tilt"))
+
+	p, err = NewClusterProvisioner("k3d")
+	require.NoError(t, err)
+	assert.Equal(t, KubeContext("k3d-tilt"), p.ContextName("tilt"))
+}
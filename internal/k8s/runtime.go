@@ -61,6 +61,23 @@ func (c K8sClient) ContainerRuntime(ctx context.Context) container.Runtime {
 	return c.runtimeAsync.Runtime(ctx)
 }
 
+func (c K8sClient) NodeImages(ctx context.Context) ([]string, error) {
+	nodeList, err := c.core.Nodes().List(ctx, metav1.ListOptions{
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, node := range nodeList.Items {
+		for _, image := range node.Status.Images {
+			names = append(names, image.Names...)
+		}
+	}
+	return names, nil
+}
+
 func ProvideContainerRuntime(ctx context.Context, kCli Client) container.Runtime {
 	return kCli.ContainerRuntime(ctx)
 }
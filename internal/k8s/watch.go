@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,6 +24,7 @@ import (
 var PodGVR = v1.SchemeGroupVersion.WithResource("pods")
 var ServiceGVR = v1.SchemeGroupVersion.WithResource("services")
 var EventGVR = v1.SchemeGroupVersion.WithResource("events")
+var JobGVR = batchv1.SchemeGroupVersion.WithResource("jobs")
 
 // A wrapper object around SharedInformer objects, to make them
 // a bit easier to use correctly.
@@ -140,9 +142,9 @@ func (kCli K8sClient) makeInformer(
 	return resFactory.Informer(), nil
 }
 
-func (kCli K8sClient) WatchEvents(ctx context.Context) (<-chan *v1.Event, error) {
+func (kCli K8sClient) WatchEvents(ctx context.Context, ls labels.Selector) (<-chan *v1.Event, error) {
 	gvr := EventGVR
-	informer, err := kCli.makeInformer(ctx, gvr, labels.Everything())
+	informer, err := kCli.makeInformer(ctx, gvr, ls)
 	if err != nil {
 		return nil, errors.Wrap(err, "WatchEvents")
 	}
@@ -248,6 +250,34 @@ func (kCli K8sClient) WatchServices(ctx context.Context, ls labels.Selector) (<-
 	return ch, nil
 }
 
+func (kCli K8sClient) WatchJobs(ctx context.Context, ls labels.Selector) (<-chan *batchv1.Job, error) {
+	gvr := JobGVR
+	informer, err := kCli.makeInformer(ctx, gvr, ls)
+	if err != nil {
+		return nil, errors.Wrap(err, "WatchJobs")
+	}
+
+	ch := make(chan *batchv1.Job)
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			mObj, ok := obj.(*batchv1.Job)
+			if ok {
+				ch <- mObj
+			}
+		},
+		UpdateFunc: func(oldObj interface{}, newObj interface{}) {
+			newJob, ok := newObj.(*batchv1.Job)
+			if ok {
+				ch <- newJob
+			}
+		},
+	})
+
+	go runInformer(ctx, "jobs", informer)
+
+	return ch, nil
+}
+
 func runInformer(ctx context.Context, name string, informer cache.SharedInformer) {
 	originalDuration := 3 * time.Second
 	originalBackoff := wait.Backoff{
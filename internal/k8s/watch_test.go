@@ -241,6 +241,14 @@ func TestK8sClient_WatchEventsUpdate(t *testing.T) {
 	tf.assertEvents([]runtime.Object{event3, event2b}, ch)
 }
 
+func TestK8sClient_WatchEventsLabelsPassed(t *testing.T) {
+	tf := newWatchTestFixture(t)
+	defer tf.TearDown()
+
+	lps := labels.Set{"foo": "bar", "baz": "quu"}
+	tf.testEventLabels(lps, lps)
+}
+
 func TestWatchPodsAfterAdding(t *testing.T) {
 	tf := newWatchTestFixture(t)
 	defer tf.TearDown()
@@ -356,7 +364,7 @@ func (tf *watchTestFixture) watchServices() <-chan *v1.Service {
 }
 
 func (tf *watchTestFixture) watchEvents() <-chan *v1.Event {
-	ch, err := tf.kCli.WatchEvents(tf.ctx)
+	ch, err := tf.kCli.WatchEvents(tf.ctx, labels.Everything())
 	if err != nil {
 		tf.t.Fatalf("watchEvents: %v", err)
 	}
@@ -480,3 +488,15 @@ func (tf *watchTestFixture) testServiceLabels(input labels.Set, expectedLabels l
 	expectedLabelSelector := expectedLabels.AsSelector()
 	assert.Equal(tf.t, expectedLabelSelector, tf.watchRestrictions.Labels)
 }
+
+func (tf *watchTestFixture) testEventLabels(input labels.Set, expectedLabels labels.Set) {
+	_, err := tf.kCli.WatchEvents(tf.ctx, input.AsSelector())
+	if !assert.NoError(tf.t, err) {
+		return
+	}
+
+	assert.Equal(tf.t, fields.Everything(), tf.watchRestrictions.Fields)
+
+	expectedLabelSelector := expectedLabels.AsSelector()
+	assert.Equal(tf.t, expectedLabelSelector, tf.watchRestrictions.Labels)
+}
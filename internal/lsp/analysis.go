@@ -0,0 +1,254 @@
+package lsp
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"go.starlark.net/syntax"
+)
+
+// diagnostics parses the file and reports syntax errors. We
+// deliberately stop at the parser: executing a Tiltfile needs a
+// cluster, and the engine already reports execution errors on save.
+func (s *Server) diagnostics(path string) []Diagnostic {
+	src, ok := s.sourceFor(path)
+	if !ok {
+		return nil
+	}
+
+	_, err := syntax.Parse(path, src, 0)
+	if err == nil {
+		return nil
+	}
+
+	if serr, ok := err.(syntax.Error); ok {
+		pos := fromSyntaxPos(serr.Pos)
+		return []Diagnostic{{
+			Range:    Range{Start: pos, End: Position{Line: pos.Line, Character: pos.Character + 1}},
+			Severity: severityError,
+			Source:   "tilt",
+			Message:  serr.Msg,
+		}}
+	}
+	return []Diagnostic{{
+		Severity: severityError,
+		Source:   "tilt",
+		Message:  err.Error(),
+	}}
+}
+
+// documentSymbols lists the file's top-level functions and variables.
+func (s *Server) documentSymbols(path string) []SymbolInformation {
+	f, ok := s.parse(path)
+	if !ok {
+		return nil
+	}
+
+	var syms []SymbolInformation
+	for _, stmt := range f.Stmts {
+		switch stmt := stmt.(type) {
+		case *syntax.DefStmt:
+			syms = append(syms, SymbolInformation{
+				Name:     stmt.Name.Name,
+				Kind:     symbolKindFunction,
+				Location: locationFor(path, stmt.Name),
+			})
+		case *syntax.AssignStmt:
+			if stmt.Op != syntax.EQ {
+				continue
+			}
+			for _, id := range assignedIdents(stmt.LHS) {
+				syms = append(syms, SymbolInformation{
+					Name:     id.Name,
+					Kind:     symbolKindVariable,
+					Location: locationFor(path, id),
+				})
+			}
+		}
+	}
+	return syms
+}
+
+// definition resolves the identifier under the cursor to where it was
+// bound: a top-level def or assignment in the same file, or — for
+// names bound by load() — the definition in the loaded module.
+func (s *Server) definition(path string, pos Position) *Location {
+	f, ok := s.parse(path)
+	if !ok {
+		return nil
+	}
+	id := identAt(f, pos)
+	if id == nil {
+		return nil
+	}
+
+	for _, stmt := range f.Stmts {
+		switch stmt := stmt.(type) {
+		case *syntax.DefStmt:
+			if stmt.Name.Name == id.Name && stmt.Name != id {
+				loc := locationFor(path, stmt.Name)
+				return &loc
+			}
+		case *syntax.AssignStmt:
+			for _, lhs := range assignedIdents(stmt.LHS) {
+				if lhs.Name == id.Name && lhs != id {
+					loc := locationFor(path, lhs)
+					return &loc
+				}
+			}
+		case *syntax.LoadStmt:
+			for i, to := range stmt.To {
+				if to.Name == id.Name {
+					return s.loadedDefinition(path, stmt.ModuleName(), stmt.From[i].Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// loadedDefinition finds the named top-level binding in a loaded
+// module. Only plain file paths resolve; extension and other schemed
+// modules would need the fetch machinery, so we don't chase them.
+func (s *Server) loadedDefinition(fromPath, module, name string) *Location {
+	if strings.Contains(module, "://") {
+		return nil
+	}
+	target := module
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(fromPath), module)
+	}
+
+	if _, ok := s.sourceFor(target); !ok {
+		return nil
+	}
+	for _, sym := range s.documentSymbols(target) {
+		if sym.Name == name {
+			loc := sym.Location
+			return &loc
+		}
+	}
+
+	// The module exists but we couldn't find the name; landing at the
+	// top of the file still beats doing nothing.
+	loc := Location{URI: pathToURI(target)}
+	return &loc
+}
+
+// hover shows the signature and summary of the builtin under the
+// cursor, drawn from the same metadata as `tilt api`.
+func (s *Server) hover(path string, pos Position) *Hover {
+	f, ok := s.parse(path)
+	if !ok {
+		return nil
+	}
+	id := identAt(f, pos)
+	if id == nil {
+		return nil
+	}
+	doc, ok := s.builtins[id.Name]
+	if !ok {
+		return nil
+	}
+
+	var b strings.Builder
+	if doc.Signature != "" {
+		fmt.Fprintf(&b, "```python\n%s\n```", doc.Signature)
+	} else {
+		fmt.Fprintf(&b, "```python\n%s\n```", doc.Name)
+	}
+	if doc.Summary != "" {
+		fmt.Fprintf(&b, "\n\n%s", doc.Summary)
+	}
+	for _, arg := range doc.Args {
+		fmt.Fprintf(&b, "\n\n`%s`", arg.Name)
+		if arg.Type != "" {
+			fmt.Fprintf(&b, " (%s)", arg.Type)
+		}
+		if arg.Doc != "" {
+			fmt.Fprintf(&b, ": %s", arg.Doc)
+		}
+	}
+
+	r := rangeFor(id)
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: b.String()},
+		Range:    &r,
+	}
+}
+
+func (s *Server) parse(path string) (*syntax.File, bool) {
+	src, ok := s.sourceFor(path)
+	if !ok {
+		return nil, false
+	}
+	f, err := syntax.Parse(path, src, 0)
+	if err != nil {
+		return nil, false
+	}
+	return f, true
+}
+
+// identAt returns the innermost identifier whose span covers pos.
+func identAt(f *syntax.File, pos Position) *syntax.Ident {
+	line := int32(pos.Line + 1)
+	col := int32(pos.Character + 1)
+	var found *syntax.Ident
+	syntax.Walk(f, func(n syntax.Node) bool {
+		id, ok := n.(*syntax.Ident)
+		if !ok {
+			return true
+		}
+		start, end := id.Span()
+		if start.Line == line && start.Col <= col && col < end.Col {
+			found = id
+		}
+		return true
+	})
+	return found
+}
+
+// assignedIdents unwraps the LHS of an assignment into the
+// identifiers it binds (x = ..., x, y = ..., [x, y] = ...).
+func assignedIdents(lhs syntax.Expr) []*syntax.Ident {
+	switch lhs := lhs.(type) {
+	case *syntax.Ident:
+		return []*syntax.Ident{lhs}
+	case *syntax.TupleExpr:
+		var ids []*syntax.Ident
+		for _, e := range lhs.List {
+			ids = append(ids, assignedIdents(e)...)
+		}
+		return ids
+	case *syntax.ListExpr:
+		var ids []*syntax.Ident
+		for _, e := range lhs.List {
+			ids = append(ids, assignedIdents(e)...)
+		}
+		return ids
+	case *syntax.ParenExpr:
+		return assignedIdents(lhs.X)
+	}
+	return nil
+}
+
+func locationFor(path string, n syntax.Node) Location {
+	return Location{URI: pathToURI(path), Range: rangeFor(n)}
+}
+
+func rangeFor(n syntax.Node) Range {
+	start, end := n.Span()
+	return Range{Start: fromSyntaxPos(start), End: fromSyntaxPos(end)}
+}
+
+func fromSyntaxPos(p syntax.Position) Position {
+	pos := Position{Line: int(p.Line) - 1, Character: int(p.Col) - 1}
+	if pos.Line < 0 {
+		pos.Line = 0
+	}
+	if pos.Character < 0 {
+		pos.Character = 0
+	}
+	return pos
+}
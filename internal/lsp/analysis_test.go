@@ -0,0 +1,117 @@
+package lsp
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/internal/testutils/tempdir"
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+)
+
+func TestDiagnosticsSyntaxError(t *testing.T) {
+	s := newTestServer()
+	s.docs["/Tiltfile"] = "def foo(:\n"
+
+	diags := s.diagnostics("/Tiltfile")
+	require.Len(t, diags, 1)
+	assert.Equal(t, severityError, diags[0].Severity)
+	assert.Equal(t, 0, diags[0].Range.Start.Line)
+	assert.NotEqual(t, "", diags[0].Message)
+}
+
+func TestDiagnosticsClean(t *testing.T) {
+	s := newTestServer()
+	s.docs["/Tiltfile"] = "x = 1\n"
+
+	assert.Empty(t, s.diagnostics("/Tiltfile"))
+}
+
+func TestDocumentSymbols(t *testing.T) {
+	s := newTestServer()
+	s.docs["/Tiltfile"] = `x = 1
+def build_all():
+  pass
+a, b = 1, 2
+`
+
+	syms := s.documentSymbols("/Tiltfile")
+	require.Len(t, syms, 4)
+	assert.Equal(t, "x", syms[0].Name)
+	assert.Equal(t, symbolKindVariable, syms[0].Kind)
+	assert.Equal(t, "build_all", syms[1].Name)
+	assert.Equal(t, symbolKindFunction, syms[1].Kind)
+	assert.Equal(t, 1, syms[1].Location.Range.Start.Line)
+	assert.Equal(t, "a", syms[2].Name)
+	assert.Equal(t, "b", syms[3].Name)
+}
+
+func TestDefinitionInFile(t *testing.T) {
+	s := newTestServer()
+	s.docs["/Tiltfile"] = `def helper():
+  pass
+helper()
+`
+
+	// Cursor on the call to helper on line 2.
+	loc := s.definition("/Tiltfile", Position{Line: 2, Character: 2})
+	require.NotNil(t, loc)
+	assert.Equal(t, "file:///Tiltfile", loc.URI)
+	assert.Equal(t, Position{Line: 0, Character: 4}, loc.Range.Start)
+}
+
+func TestDefinitionAcrossLoad(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	f.WriteFile("helpers.tiltfile", `def deploy(name):
+  pass
+`)
+	main := f.WriteFile("Tiltfile", `load('./helpers.tiltfile', 'deploy')
+deploy('foo')
+`)
+
+	s := newTestServer()
+	loc := s.definition(main, Position{Line: 1, Character: 0})
+	require.NotNil(t, loc)
+	assert.Equal(t, pathToURI(f.JoinPath("helpers.tiltfile")), loc.URI)
+	assert.Equal(t, Position{Line: 0, Character: 4}, loc.Range.Start)
+}
+
+func TestDefinitionSkipsExtensionModules(t *testing.T) {
+	s := newTestServer()
+	s.docs["/Tiltfile"] = `load('ext://restart_process', 'docker_build_with_restart')
+docker_build_with_restart()
+`
+
+	assert.Nil(t, s.definition("/Tiltfile", Position{Line: 1, Character: 0}))
+}
+
+func TestHoverBuiltin(t *testing.T) {
+	s := NewServer(strings.NewReader(""), ioutil.Discard, []starkit.BuiltinDoc{{
+		Name:      "k8s_yaml",
+		Signature: "k8s_yaml(yaml)",
+		Summary:   "Register Kubernetes objects to deploy.",
+	}})
+	s.docs["/Tiltfile"] = "k8s_yaml('foo.yaml')\n"
+
+	h := s.hover("/Tiltfile", Position{Line: 0, Character: 1})
+	require.NotNil(t, h)
+	assert.Contains(t, h.Contents.Value, "k8s_yaml(yaml)")
+	assert.Contains(t, h.Contents.Value, "Register Kubernetes objects")
+	assert.Equal(t, Position{Line: 0, Character: 0}, h.Range.Start)
+}
+
+func TestHoverUnknownName(t *testing.T) {
+	s := newTestServer()
+	s.docs["/Tiltfile"] = "mystery('foo')\n"
+
+	assert.Nil(t, s.hover("/Tiltfile", Position{Line: 0, Character: 1}))
+}
+
+func newTestServer() *Server {
+	return NewServer(strings.NewReader(""), ioutil.Discard, nil)
+}
@@ -0,0 +1,247 @@
+// Package lsp implements a minimal language server for Tiltfiles,
+// spoken over stdio, so editor extensions don't have to reimplement
+// the Starlark environment. Builtin signatures come from the same
+// metadata as `tilt api`; diagnostics come from the Starlark parser
+// rather than execution, so they're cheap enough to recompute on
+// every keystroke.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+)
+
+type Server struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	// Open documents, keyed by absolute path. These shadow the
+	// filesystem: the editor's unsaved buffer wins.
+	docs map[string]string
+
+	builtins map[string]starkit.BuiltinDoc
+}
+
+func NewServer(in io.Reader, out io.Writer, builtins []starkit.BuiltinDoc) *Server {
+	byName := make(map[string]starkit.BuiltinDoc, len(builtins))
+	for _, doc := range builtins {
+		byName[doc.Name] = doc
+	}
+	return &Server{
+		in:       bufio.NewReader(in),
+		out:      out,
+		docs:     make(map[string]string),
+		builtins: byName,
+	}
+}
+
+// Run serves LSP requests until the client sends exit or closes the
+// connection.
+func (s *Server) Run() error {
+	for {
+		body, err := s.readMessage()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req request
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			return errors.Wrap(err, "malformed request")
+		}
+
+		if req.Method == "exit" {
+			return nil
+		}
+
+		err = s.handle(req)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(req request) error {
+	switch req.Method {
+	case "initialize":
+		return s.reply(req.ID, initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync:       syncKindFull,
+				HoverProvider:          true,
+				DefinitionProvider:     true,
+				DocumentSymbolProvider: true,
+			},
+		})
+	case "initialized":
+		return nil
+	case "shutdown":
+		return s.reply(req.ID, nil)
+	case "textDocument/didOpen":
+		var params didOpenParams
+		err := json.Unmarshal(req.Params, &params)
+		if err != nil {
+			return err
+		}
+		path := uriToPath(params.TextDocument.URI)
+		s.docs[path] = params.TextDocument.Text
+		return s.publishDiagnostics(params.TextDocument.URI, path)
+	case "textDocument/didChange":
+		var params didChangeParams
+		err := json.Unmarshal(req.Params, &params)
+		if err != nil {
+			return err
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil
+		}
+		path := uriToPath(params.TextDocument.URI)
+		s.docs[path] = params.ContentChanges[len(params.ContentChanges)-1].Text
+		return s.publishDiagnostics(params.TextDocument.URI, path)
+	case "textDocument/didClose":
+		var params didCloseParams
+		err := json.Unmarshal(req.Params, &params)
+		if err != nil {
+			return err
+		}
+		delete(s.docs, uriToPath(params.TextDocument.URI))
+		return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+			URI:         params.TextDocument.URI,
+			Diagnostics: []Diagnostic{},
+		})
+	case "textDocument/documentSymbol":
+		var params documentSymbolParams
+		err := json.Unmarshal(req.Params, &params)
+		if err != nil {
+			return err
+		}
+		return s.reply(req.ID, s.documentSymbols(uriToPath(params.TextDocument.URI)))
+	case "textDocument/definition":
+		var params textDocumentPositionParams
+		err := json.Unmarshal(req.Params, &params)
+		if err != nil {
+			return err
+		}
+		loc := s.definition(uriToPath(params.TextDocument.URI), params.Position)
+		if loc == nil {
+			return s.reply(req.ID, nil)
+		}
+		return s.reply(req.ID, loc)
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		err := json.Unmarshal(req.Params, &params)
+		if err != nil {
+			return err
+		}
+		h := s.hover(uriToPath(params.TextDocument.URI), params.Position)
+		if h == nil {
+			return s.reply(req.ID, nil)
+		}
+		return s.reply(req.ID, h)
+	default:
+		// Notifications we don't understand are ignored; requests get
+		// a proper error so the client doesn't hang on the ID.
+		if len(req.ID) == 0 {
+			return nil
+		}
+		return s.replyError(req.ID, codeMethodNotFound, fmt.Sprintf("method not supported: %s", req.Method))
+	}
+}
+
+func (s *Server) publishDiagnostics(uri string, path string) error {
+	diags := s.diagnostics(path)
+	if diags == nil {
+		diags = []Diagnostic{}
+	}
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: diags,
+	})
+}
+
+// sourceFor returns the current text of a file, preferring the open
+// editor buffer over what's on disk.
+func (s *Server) sourceFor(path string) (string, bool) {
+	text, ok := s.docs[path]
+	if ok {
+		return text, true
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
+
+func (s *Server) readMessage() ([]byte, error) {
+	contentLen := -1
+	for {
+		line, err := s.in.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if v := strings.TrimPrefix(line, "Content-Length: "); v != line {
+			contentLen, err = strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("bad Content-Length %q", v)
+			}
+		}
+	}
+	if contentLen < 0 {
+		return nil, fmt.Errorf("message missing Content-Length header")
+	}
+	body := make([]byte, contentLen)
+	_, err := io.ReadFull(s.in, body)
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func (s *Server) reply(id json.RawMessage, result interface{}) error {
+	return s.write(response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (s *Server) replyError(id json.RawMessage, code int, msg string) error {
+	return s.write(response{JSONRPC: "2.0", ID: id, Error: &responseError{Code: code, Message: msg}})
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return s.write(request{JSONRPC: "2.0", Method: method, Params: body})
+}
+
+func (s *Server) write(msg interface{}) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
+
+func uriToPath(uri string) string {
+	return strings.TrimPrefix(uri, "file://")
+}
+
+func pathToURI(path string) string {
+	return "file://" + path
+}
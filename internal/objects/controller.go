@@ -0,0 +1,26 @@
+package objects
+
+import (
+	"context"
+
+	"github.com/windmilleng/tilt/internal/store"
+)
+
+// Keeps the registry in sync with the store.
+type Controller struct {
+	registry *Registry
+}
+
+func NewController(registry *Registry) *Controller {
+	return &Controller{registry: registry}
+}
+
+var _ store.Subscriber = &Controller{}
+
+func (c *Controller) OnChange(ctx context.Context, st store.RStore) {
+	state := st.RLockState()
+	objects := FromEngineState(state)
+	st.RUnlockState()
+
+	c.registry.Update(objects)
+}
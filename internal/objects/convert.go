@@ -0,0 +1,103 @@
+package objects
+
+import (
+	"fmt"
+
+	"github.com/windmilleng/tilt/internal/store"
+)
+
+// FromEngineState converts the current engine state into the full set
+// of API objects.
+func FromEngineState(state store.EngineState) []Object {
+	result := []Object{sessionObject(state)}
+
+	for _, mt := range state.Targets() {
+		name := mt.Manifest.Name.String()
+		ms := mt.State
+
+		result = append(result, Object{
+			TypeMeta: TypeMeta{APIVersion: APIVersion, Kind: KindResource},
+			Metadata: ObjectMeta{Name: name},
+			Spec: ResourceSpec{
+				TriggerMode: mt.Manifest.TriggerMode,
+			},
+			Status: resourceStatus(state, mt),
+		})
+
+		result = append(result, Object{
+			TypeMeta: TypeMeta{APIVersion: APIVersion, Kind: KindFileWatch},
+			Metadata: ObjectMeta{Name: name},
+			Spec: FileWatchSpec{
+				Paths: mt.Manifest.LocalPaths(),
+			},
+			Status: fileWatchStatus(ms),
+		})
+
+		for _, b := range ms.BuildHistory {
+			status := BuildStatus{
+				Resource:     name,
+				StartTime:    b.StartTime,
+				FinishTime:   b.FinishTime,
+				Reason:       b.Reason.String(),
+				Edits:        b.Edits,
+				SpanID:       string(b.SpanID),
+				WarningCount: b.WarningCount,
+			}
+			if b.Error != nil {
+				status.Error = b.Error.Error()
+			}
+			result = append(result, Object{
+				TypeMeta: TypeMeta{APIVersion: APIVersion, Kind: KindBuild},
+				Metadata: ObjectMeta{Name: fmt.Sprintf("%s-%d", name, b.StartTime.UnixNano())},
+				Status:   status,
+			})
+		}
+	}
+
+	return result
+}
+
+func sessionObject(state store.EngineState) Object {
+	return Object{
+		TypeMeta: TypeMeta{APIVersion: APIVersion, Kind: KindSession},
+		Metadata: ObjectMeta{Name: "Tiltfile"},
+		Spec: SessionSpec{
+			TiltfilePath: state.TiltfilePath,
+		},
+		Status: SessionStatus{
+			StartedBuildCount:   state.StartedBuildCount,
+			CompletedBuildCount: state.CompletedBuildCount,
+		},
+	}
+}
+
+func resourceStatus(state store.EngineState, mt *store.ManifestTarget) ResourceStatus {
+	ms := mt.State
+	rState := store.ResourceStateFor(state, mt)
+	status := ResourceStatus{
+		State:       rState.State,
+		StateReason: rState.Reason,
+		Building:    ms.IsBuilding(),
+		Ready:       ms.RuntimeState != nil && ms.RuntimeState.HasEverBeenReadyOrSucceeded(),
+	}
+	lastBuild := ms.LastBuild()
+	if !lastBuild.Empty() {
+		status.LastBuildStartTime = lastBuild.StartTime
+		status.LastBuildFinishTime = lastBuild.FinishTime
+		if lastBuild.Error != nil {
+			status.LastBuildError = lastBuild.Error.Error()
+		}
+	}
+	return status
+}
+
+func fileWatchStatus(ms *store.ManifestState) FileWatchStatus {
+	status := FileWatchStatus{}
+	for _, bs := range ms.BuildStatuses {
+		status.PendingFileChangeCount += len(bs.PendingFileChanges)
+	}
+	if ok, since := ms.HasPendingChanges(); ok {
+		status.PendingSince = since
+	}
+	return status
+}
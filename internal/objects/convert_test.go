@@ -0,0 +1,77 @@
+package objects
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/internal/k8s/testyaml"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils/manifestbuilder"
+	"github.com/windmilleng/tilt/internal/testutils/tempdir"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestFromEngineState(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	state := store.NewState()
+	state.TiltfilePath = f.JoinPath("Tiltfile")
+	state.StartedBuildCount = 2
+	state.CompletedBuildCount = 1
+
+	m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+	mt := store.NewManifestTarget(m)
+	finish := time.Now()
+	mt.State.AddCompletedBuild(model.BuildRecord{
+		StartTime:  finish.Add(-time.Second),
+		FinishTime: finish,
+		Error:      fmt.Errorf("build failed"),
+	})
+	state.UpsertManifestTarget(mt)
+
+	byKind := make(map[string][]Object)
+	for _, obj := range FromEngineState(*state) {
+		assert.Equal(t, APIVersion, obj.APIVersion)
+		byKind[obj.Kind] = append(byKind[obj.Kind], obj)
+	}
+
+	require.Len(t, byKind[KindSession], 1)
+	session := byKind[KindSession][0]
+	assert.Equal(t, "Tiltfile", session.Metadata.Name)
+	assert.Equal(t, f.JoinPath("Tiltfile"), session.Spec.(SessionSpec).TiltfilePath)
+	assert.Equal(t, 2, session.Status.(SessionStatus).StartedBuildCount)
+
+	require.Len(t, byKind[KindResource], 1)
+	resource := byKind[KindResource][0]
+	assert.Equal(t, "fe", resource.Metadata.Name)
+	assert.Equal(t, "build failed", resource.Status.(ResourceStatus).LastBuildError)
+
+	require.Len(t, byKind[KindBuild], 1)
+	build := byKind[KindBuild][0]
+	assert.Equal(t, "fe", build.Status.(BuildStatus).Resource)
+	assert.Equal(t, "build failed", build.Status.(BuildStatus).Error)
+
+	require.Len(t, byKind[KindFileWatch], 1)
+	assert.Equal(t, "fe", byKind[KindFileWatch][0].Metadata.Name)
+}
+
+func TestKindForPath(t *testing.T) {
+	for path, expected := range map[string]string{
+		"sessions":    KindSession,
+		"resources":   KindResource,
+		"builds":      KindBuild,
+		"filewatches": KindFileWatch,
+	} {
+		kind, ok := KindForPath(path)
+		require.True(t, ok, path)
+		assert.Equal(t, expected, kind)
+	}
+
+	_, ok := KindForPath("nope")
+	assert.False(t, ok)
+}
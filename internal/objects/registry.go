@@ -0,0 +1,154 @@
+package objects
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+type EventType string
+
+const (
+	Added    EventType = "ADDED"
+	Modified EventType = "MODIFIED"
+	Deleted  EventType = "DELETED"
+)
+
+type Event struct {
+	Type   EventType `json:"type"`
+	Object Object    `json:"object"`
+}
+
+// How many events a watcher can fall behind before we close its
+// channel. A closed watch means "re-list and re-watch", as in the
+// Kubernetes API.
+const watchBufferSize = 128
+
+// Holds the current set of API objects, and notifies watchers when
+// they change.
+type Registry struct {
+	mu sync.Mutex
+
+	// kind -> name -> object
+	objects map[string]map[string]Object
+
+	lastVersion int64
+	watchers    map[*watcher]bool
+}
+
+type watcher struct {
+	// Empty string means "all kinds".
+	kind string
+	ch   chan Event
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		objects:  make(map[string]map[string]Object),
+		watchers: make(map[*watcher]bool),
+	}
+}
+
+// List returns the objects of the given kind, sorted by name.
+func (r *Registry) List(kind string) []Object {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []Object
+	for _, obj := range r.objects[kind] {
+		result = append(result, obj)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Metadata.Name < result[j].Metadata.Name })
+	return result
+}
+
+func (r *Registry) Get(kind string, name string) (Object, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	obj, ok := r.objects[kind][name]
+	return obj, ok
+}
+
+// Watch returns a channel of change events for the given kind (or all
+// kinds, if kind is empty), and a function to cancel the watch.
+//
+// If the watcher falls too far behind, the channel is closed and the
+// caller should re-list and re-watch.
+func (r *Registry) Watch(kind string) (<-chan Event, func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w := &watcher{kind: kind, ch: make(chan Event, watchBufferSize)}
+	r.watchers[w] = true
+
+	cancel := func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.watchers[w] {
+			delete(r.watchers, w)
+			close(w.ch)
+		}
+	}
+	return w.ch, cancel
+}
+
+// Update replaces the registry contents with the given objects,
+// assigning resource versions and notifying watchers of anything that
+// changed.
+func (r *Registry) Update(objects []Object) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]map[string]bool)
+	for _, obj := range objects {
+		kind := obj.Kind
+		if seen[kind] == nil {
+			seen[kind] = make(map[string]bool)
+		}
+		seen[kind][obj.Metadata.Name] = true
+
+		if r.objects[kind] == nil {
+			r.objects[kind] = make(map[string]Object)
+		}
+
+		old, exists := r.objects[kind][obj.Metadata.Name]
+		if exists && reflect.DeepEqual(old.Spec, obj.Spec) && reflect.DeepEqual(old.Status, obj.Status) {
+			continue
+		}
+
+		r.lastVersion++
+		obj.Metadata.ResourceVersion = strconv.FormatInt(r.lastVersion, 10)
+		r.objects[kind][obj.Metadata.Name] = obj
+
+		eventType := Added
+		if exists {
+			eventType = Modified
+		}
+		r.notify(Event{Type: eventType, Object: obj})
+	}
+
+	for kind, byName := range r.objects {
+		for name, obj := range byName {
+			if !seen[kind][name] {
+				delete(byName, name)
+				r.notify(Event{Type: Deleted, Object: obj})
+			}
+		}
+	}
+}
+
+func (r *Registry) notify(event Event) {
+	for w := range r.watchers {
+		if w.kind != "" && w.kind != event.Object.Kind {
+			continue
+		}
+		select {
+		case w.ch <- event:
+		default:
+			delete(r.watchers, w)
+			close(w.ch)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package objects
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resourceObj(name string, building bool) Object {
+	return Object{
+		TypeMeta: TypeMeta{APIVersion: APIVersion, Kind: KindResource},
+		Metadata: ObjectMeta{Name: name},
+		Status:   ResourceStatus{Building: building},
+	}
+}
+
+func TestRegistryListGet(t *testing.T) {
+	r := NewRegistry()
+	r.Update([]Object{resourceObj("fe", false), resourceObj("be", false)})
+
+	list := r.List(KindResource)
+	require.Len(t, list, 2)
+	assert.Equal(t, "be", list[0].Metadata.Name)
+	assert.Equal(t, "fe", list[1].Metadata.Name)
+
+	obj, ok := r.Get(KindResource, "fe")
+	require.True(t, ok)
+	assert.Equal(t, "fe", obj.Metadata.Name)
+	assert.NotEqual(t, "", obj.Metadata.ResourceVersion)
+
+	_, ok = r.Get(KindResource, "nope")
+	assert.False(t, ok)
+}
+
+func TestRegistryResourceVersionBumpsOnChange(t *testing.T) {
+	r := NewRegistry()
+	r.Update([]Object{resourceObj("fe", false)})
+	v1, _ := r.Get(KindResource, "fe")
+
+	// An identical update leaves the version alone.
+	r.Update([]Object{resourceObj("fe", false)})
+	v2, _ := r.Get(KindResource, "fe")
+	assert.Equal(t, v1.Metadata.ResourceVersion, v2.Metadata.ResourceVersion)
+
+	r.Update([]Object{resourceObj("fe", true)})
+	v3, _ := r.Get(KindResource, "fe")
+	assert.NotEqual(t, v1.Metadata.ResourceVersion, v3.Metadata.ResourceVersion)
+}
+
+func TestRegistryWatch(t *testing.T) {
+	r := NewRegistry()
+	ch, cancel := r.Watch(KindResource)
+	defer cancel()
+
+	r.Update([]Object{resourceObj("fe", false)})
+	event := <-ch
+	assert.Equal(t, Added, event.Type)
+	assert.Equal(t, "fe", event.Object.Metadata.Name)
+
+	r.Update([]Object{resourceObj("fe", true)})
+	event = <-ch
+	assert.Equal(t, Modified, event.Type)
+
+	r.Update(nil)
+	event = <-ch
+	assert.Equal(t, Deleted, event.Type)
+}
+
+func TestRegistryWatchFiltersByKind(t *testing.T) {
+	r := NewRegistry()
+	ch, cancel := r.Watch(KindBuild)
+	defer cancel()
+
+	r.Update([]Object{
+		resourceObj("fe", false),
+		Object{
+			TypeMeta: TypeMeta{APIVersion: APIVersion, Kind: KindBuild},
+			Metadata: ObjectMeta{Name: "fe-1"},
+			Status:   BuildStatus{Resource: "fe"},
+		},
+	})
+
+	event := <-ch
+	assert.Equal(t, KindBuild, event.Object.Kind)
+	assert.Empty(t, ch)
+}
+
+func TestRegistrySlowWatcherClosed(t *testing.T) {
+	r := NewRegistry()
+	ch, cancel := r.Watch(KindResource)
+	defer cancel()
+
+	// A watcher that never reads gets closed once its buffer fills,
+	// rather than blocking the registry.
+	for i := 0; i <= watchBufferSize; i++ {
+		building := i%2 == 0
+		r.Update([]Object{resourceObj("fe", building)})
+	}
+
+	received := 0
+	for range ch {
+		received++
+	}
+	assert.Equal(t, watchBufferSize, received)
+}
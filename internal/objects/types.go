@@ -0,0 +1,121 @@
+// Package objects exposes engine state as versioned, Kubernetes-style
+// API objects with list/get/watch semantics, so external controllers
+// can react to state changes without understanding the store's
+// internal representation.
+package objects
+
+import (
+	"strings"
+	"time"
+
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+const APIVersion = "tilt.dev/v1alpha1"
+
+const (
+	KindSession   = "Session"
+	KindResource  = "Resource"
+	KindBuild     = "Build"
+	KindFileWatch = "FileWatch"
+)
+
+type TypeMeta struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+}
+
+type ObjectMeta struct {
+	Name string `json:"name"`
+
+	// Bumped every time the object's spec or status changes, so
+	// watchers can tell whether they're up to date.
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+type Object struct {
+	TypeMeta
+	Metadata ObjectMeta  `json:"metadata"`
+	Spec     interface{} `json:"spec,omitempty"`
+	Status   interface{} `json:"status,omitempty"`
+}
+
+type List struct {
+	TypeMeta
+	Items []Object `json:"items"`
+}
+
+func NewList(kind string, items []Object) List {
+	if items == nil {
+		items = []Object{}
+	}
+	return List{
+		TypeMeta: TypeMeta{APIVersion: APIVersion, Kind: kind + "List"},
+		Items:    items,
+	}
+}
+
+// KindForPath maps a URL path segment (e.g., "filewatches") to a kind.
+func KindForPath(p string) (string, bool) {
+	switch strings.ToLower(p) {
+	case "sessions":
+		return KindSession, true
+	case "resources":
+		return KindResource, true
+	case "builds":
+		return KindBuild, true
+	case "filewatches":
+		return KindFileWatch, true
+	}
+	return "", false
+}
+
+// There's one Session object per `tilt up`, named "Tiltfile".
+type SessionSpec struct {
+	TiltfilePath string `json:"tiltfilePath"`
+}
+
+type SessionStatus struct {
+	StartedBuildCount   int `json:"startedBuildCount"`
+	CompletedBuildCount int `json:"completedBuildCount"`
+}
+
+type ResourceSpec struct {
+	TriggerMode model.TriggerMode `json:"triggerMode"`
+}
+
+type ResourceStatus struct {
+	State               model.ResourceState `json:"state"`
+	StateReason         string              `json:"stateReason,omitempty"`
+	Building            bool                `json:"building"`
+	Ready               bool                `json:"ready"`
+	LastBuildStartTime  time.Time           `json:"lastBuildStartTime,omitempty"`
+	LastBuildFinishTime time.Time           `json:"lastBuildFinishTime,omitempty"`
+	LastBuildError      string              `json:"lastBuildError,omitempty"`
+}
+
+// One Build object per completed build, named "<resource>-<n>".
+type BuildStatus struct {
+	Resource   string    `json:"resource"`
+	StartTime  time.Time `json:"startTime"`
+	FinishTime time.Time `json:"finishTime"`
+	Error      string    `json:"error,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+
+	// The files whose changes triggered this build.
+	Edits []string `json:"edits,omitempty"`
+
+	// The lookup key for this build's logs in the logstore.
+	SpanID string `json:"spanID,omitempty"`
+
+	WarningCount int `json:"warningCount,omitempty"`
+}
+
+type FileWatchSpec struct {
+	Paths []string `json:"paths,omitempty"`
+}
+
+type FileWatchStatus struct {
+	PendingFileChangeCount int       `json:"pendingFileChangeCount"`
+	PendingSince           time.Time `json:"pendingSince,omitempty"`
+}
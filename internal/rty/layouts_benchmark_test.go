@@ -1,6 +1,7 @@
 package rty
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/gdamore/tcell"
@@ -33,3 +34,28 @@ func BenchmarkNestedFlexLayouts(b *testing.B) {
 		run()
 	}
 }
+
+// Most elements stay off-screen, so most of them should never be
+// rendered.
+func BenchmarkElementScrollMostlyOffscreen(b *testing.B) {
+
+	sc := tcell.NewSimulationScreen("")
+	err := sc.Init()
+	assert.NoError(b, err)
+	sc.SetSize(100, 40)
+
+	r := NewRTY(sc, b)
+
+	var names []string
+	for j := 0; j < 500; j++ {
+		names = append(names, strconv.Itoa(j))
+	}
+
+	for i := 0; i < b.N; i++ {
+		sl, _ := r.RegisterElementScroll("resources", names)
+		for range names {
+			sl.Add(TextString("some element content\n"))
+		}
+		r.Render(sl)
+	}
+}
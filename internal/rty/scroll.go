@@ -321,36 +321,42 @@ func (l *ElementScrollLayout) RenderStateful(w Writer, prevState interface{}, wi
 		return nil, err
 	}
 
-	var canvases []Canvas
-	var heights []int
-	for _, c := range l.children {
-		canvas := w.RenderChildInTemp(c)
-		canvases = append(canvases, canvas)
-		_, childHeight := canvas.Size()
-		heights = append(heights, childHeight)
+	// Lay out children lazily, so elements that stay off-screen never
+	// get rendered at all.
+	canvases := make([]Canvas, len(l.children))
+	heights := make([]int, len(l.children))
+	heightOf := func(i int) int {
+		if canvases[i] == nil {
+			canvases[i] = w.RenderChildInTemp(l.children[i])
+			_, heights[i] = canvases[i].Size()
+		}
+		return heights[i]
 	}
 
-	next.firstVisibleElement = calculateFirstVisibleElement(next, heights, height)
+	next.firstVisibleElement = calculateFirstVisibleElement(next, heightOf, height)
 
 	y := 0
 	showDownArrow := false
-	for i, h := range heights {
-		if i >= next.firstVisibleElement {
-			if h > height-y {
-				h = height - y
-				showDownArrow = true
-			}
-			w, err := w.Divide(0, y, width-1, h)
-			if err != nil {
-				return nil, err
-			}
+	for i := next.firstVisibleElement; i < len(l.children); i++ {
+		if y >= height {
+			showDownArrow = true
+			break
+		}
+		h := heightOf(i)
+		if h > height-y {
+			h = height - y
+			showDownArrow = true
+		}
+		w, err := w.Divide(0, y, width-1, h)
+		if err != nil {
+			return nil, err
+		}
 
-			err = w.Embed(canvases[i], 0, h)
-			if err != nil {
-				return nil, err
-			}
-			y += h
+		err = w.Embed(canvases[i], 0, h)
+		if err != nil {
+			return nil, err
 		}
+		y += h
 	}
 
 	if next.firstVisibleElement != 0 {
@@ -364,14 +370,14 @@ func (l *ElementScrollLayout) RenderStateful(w Writer, prevState interface{}, wi
 	return &next, nil
 }
 
-func calculateFirstVisibleElement(state ElementScrollState, heights []int, height int) int {
+func calculateFirstVisibleElement(state ElementScrollState, heightOf func(int) int, height int) int {
 	if state.elementIdx < state.firstVisibleElement {
 		// if we've scrolled back above the old first visible element, just make the selected element the first visible
 		return state.elementIdx
 	} else if state.elementIdx > state.firstVisibleElement {
 		var lastLineOfSelectedElement int
-		for _, h := range heights[state.firstVisibleElement : state.elementIdx+1] {
-			lastLineOfSelectedElement += h
+		for i := state.firstVisibleElement; i <= state.elementIdx; i++ {
+			lastLineOfSelectedElement += heightOf(i)
 		}
 
 		if lastLineOfSelectedElement > height {
@@ -379,9 +385,9 @@ func calculateFirstVisibleElement(state ElementScrollState, heights []int, heigh
 			// as long as they'll fit on the screen
 			if lastLineOfSelectedElement > state.height {
 				firstVisibleElement := state.elementIdx
-				heightUsed := heights[firstVisibleElement]
+				heightUsed := heightOf(firstVisibleElement)
 				for firstVisibleElement > 0 {
-					prevHeight := heights[firstVisibleElement-1]
+					prevHeight := heightOf(firstVisibleElement - 1)
 					if heightUsed+prevHeight > state.height {
 						break
 					}
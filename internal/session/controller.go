@@ -0,0 +1,160 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/logger"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// Dispatched once on startup with the contents of the previous
+// session, if any.
+type RestoredAction struct {
+	Resources map[model.ManifestName]store.RestoredResourceState
+}
+
+func (RestoredAction) Action() {}
+
+// Watches the store for completed builds and persists them to the
+// .tilt/ dir next to the Tiltfile. On startup, restores the previous
+// session into the store.
+type Controller struct {
+	sessionStore *Store
+
+	restored bool
+
+	// The session contents as last written, so each snapshot only
+	// re-fingerprints resources that built since the last write.
+	resources map[model.ManifestName]Resource
+	written   map[model.ManifestName]time.Time
+}
+
+func NewController() *Controller {
+	return &Controller{
+		resources: make(map[model.ManifestName]Resource),
+		written:   make(map[model.ManifestName]time.Time),
+	}
+}
+
+var _ store.Subscriber = &Controller{}
+
+func (c *Controller) OnChange(ctx context.Context, st store.RStore) {
+	tiltfilePath, changed, current := c.diff(st)
+	if tiltfilePath == "" {
+		return
+	}
+
+	if c.sessionStore == nil {
+		c.sessionStore = NewStore(tiltfilePath)
+	}
+
+	if !c.restored {
+		c.restored = true
+		c.restore(ctx, st)
+	}
+
+	dirty := false
+
+	// Fingerprint outside the store lock; it stats every input file.
+	for _, info := range changed {
+		c.resources[info.name] = info.resource()
+		c.written[info.name] = info.lastBuild.FinishTime
+		dirty = true
+	}
+
+	// Drop resources that no longer exist in the Tiltfile.
+	for name := range c.resources {
+		if !current[name] {
+			delete(c.resources, name)
+			delete(c.written, name)
+			dirty = true
+		}
+	}
+
+	if !dirty {
+		return
+	}
+
+	session := Session{Version: Version, Resources: make(map[string]Resource)}
+	for name, r := range c.resources {
+		session.Resources[name.String()] = r
+	}
+	if err := c.sessionStore.Write(session); err != nil {
+		logger.Get(ctx).Debugf("Error writing session state: %v", err)
+	}
+}
+
+func (c *Controller) restore(ctx context.Context, st store.RStore) {
+	session, err := c.sessionStore.Read()
+	if err != nil {
+		logger.Get(ctx).Debugf("Error reading session state: %v", err)
+		return
+	}
+	if len(session.Resources) == 0 {
+		return
+	}
+
+	resources := make(map[model.ManifestName]store.RestoredResourceState)
+	for name, r := range session.Resources {
+		resources[model.ManifestName(name)] = store.RestoredResourceState{
+			TriggerMode:     r.TriggerMode,
+			InputHash:       r.InputHash,
+			LastBuildStart:  r.LastBuildStart,
+			LastBuildFinish: r.LastBuildFinish,
+			LastBuildError:  r.LastBuildError,
+		}
+	}
+	st.Dispatch(RestoredAction{Resources: resources})
+}
+
+type manifestInfo struct {
+	name        model.ManifestName
+	triggerMode model.TriggerMode
+	localPaths  []string
+	lastBuild   model.BuildRecord
+}
+
+func (info manifestInfo) resource() Resource {
+	r := Resource{
+		Name:            info.name.String(),
+		TriggerMode:     info.triggerMode,
+		LastBuildStart:  info.lastBuild.StartTime,
+		LastBuildFinish: info.lastBuild.FinishTime,
+	}
+	if info.lastBuild.Error != nil {
+		r.LastBuildError = info.lastBuild.Error.Error()
+	}
+	if len(info.localPaths) > 0 {
+		r.InputHash = InputHash(info.localPaths)
+	}
+	return r
+}
+
+func (c *Controller) diff(st store.RStore) (string, []manifestInfo, map[model.ManifestName]bool) {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	var changed []manifestInfo
+	current := make(map[model.ManifestName]bool)
+	for _, mt := range state.Targets() {
+		name := mt.Manifest.Name
+		current[name] = true
+
+		lastBuild := mt.State.LastBuild()
+		if lastBuild.Empty() || lastBuild.FinishTime.IsZero() {
+			continue
+		}
+		if c.written[name] == lastBuild.FinishTime {
+			continue
+		}
+		changed = append(changed, manifestInfo{
+			name:        name,
+			triggerMode: mt.Manifest.TriggerMode,
+			localPaths:  mt.Manifest.LocalPaths(),
+			lastBuild:   lastBuild,
+		})
+	}
+	return state.TiltfilePath, changed, current
+}
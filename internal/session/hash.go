@@ -0,0 +1,57 @@
+package session
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// How many files we're willing to stat when fingerprinting a resource's
+// inputs. Past this, skipping a rebuild isn't worth the startup cost.
+const maxInputFiles = 2000
+
+// InputHash fingerprints the files under the given paths by relative
+// path, size, and mtime. It never reads file contents.
+//
+// Returns "" if the inputs can't be fingerprinted cheaply (too many
+// files, IO errors), which callers should treat as "always changed".
+func InputHash(paths []string) string {
+	sorted := append([]string{}, paths...)
+	sort.Strings(sorted)
+
+	h := sha256.New()
+	count := 0
+	for _, root := range sorted {
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if info.Name() == ".git" || info.Name() == dirName {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			count++
+			if count > maxInputFiles {
+				return errTooManyFiles
+			}
+
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(h, "%s %d %d\n", rel, info.Size(), info.ModTime().UnixNano())
+			return nil
+		})
+		if err != nil {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+var errTooManyFiles = fmt.Errorf("too many input files")
@@ -0,0 +1,95 @@
+// Package session persists per-resource build state to a .tilt/ directory
+// next to the Tiltfile, so a restarted Tilt can skip rebuilding resources
+// whose inputs haven't changed.
+package session
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// Bump this when the session format changes incompatibly.
+// Sessions with a different version are ignored on read.
+const Version = 1
+
+const dirName = ".tilt"
+const fileName = "session.json"
+
+// A snapshot of per-resource build state from one Tilt session.
+type Session struct {
+	Version   int                 `json:"version"`
+	Resources map[string]Resource `json:"resources"`
+}
+
+type Resource struct {
+	Name            string            `json:"name"`
+	TriggerMode     model.TriggerMode `json:"trigger_mode"`
+	InputHash       string            `json:"input_hash,omitempty"`
+	LastBuildStart  time.Time         `json:"last_build_start"`
+	LastBuildFinish time.Time         `json:"last_build_finish"`
+	LastBuildError  string            `json:"last_build_error,omitempty"`
+}
+
+// Reads and writes the session file for a single Tiltfile.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewStore(tiltfilePath string) *Store {
+	return &Store{
+		path: filepath.Join(filepath.Dir(tiltfilePath), dirName, fileName),
+	}
+}
+
+// Read returns an empty session if no file exists, or if the file was
+// written by an incompatible version of Tilt.
+func (s *Store) Read() (Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	empty := Session{Version: Version, Resources: make(map[string]Resource)}
+
+	contents, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return empty, nil
+	} else if err != nil {
+		return empty, err
+	}
+
+	var session Session
+	if err := json.Unmarshal(contents, &session); err != nil {
+		// A corrupt session file (e.g., a partial write from a crashed
+		// session) shouldn't stop Tilt from starting.
+		return empty, nil
+	}
+	if session.Version != Version {
+		return empty, nil
+	}
+	if session.Resources == nil {
+		session.Resources = make(map[string]Resource)
+	}
+	return session, nil
+}
+
+func (s *Store) Write(session Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.path), os.FileMode(0700))
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.path, data, os.FileMode(0600))
+}
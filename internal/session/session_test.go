@@ -0,0 +1,203 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/internal/k8s/testyaml"
+	"github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils"
+	"github.com/windmilleng/tilt/internal/testutils/manifestbuilder"
+	"github.com/windmilleng/tilt/internal/testutils/tempdir"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestReadMissing(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	session, err := f.ss.Read()
+	require.NoError(t, err)
+	assert.Empty(t, session.Resources)
+}
+
+func TestWriteReadRoundtrip(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	finish := time.Now().Truncate(time.Second)
+	in := Session{Version: Version, Resources: map[string]Resource{
+		"fe": Resource{
+			Name:            "fe",
+			TriggerMode:     model.TriggerModeManualAfterInitial,
+			InputHash:       "abc123",
+			LastBuildStart:  finish.Add(-2 * time.Second),
+			LastBuildFinish: finish,
+		},
+	}}
+	require.NoError(t, f.ss.Write(in))
+
+	out, err := f.ss.Read()
+	require.NoError(t, err)
+	require.Contains(t, out.Resources, "fe")
+	assert.Equal(t, "abc123", out.Resources["fe"].InputHash)
+	assert.Equal(t, model.TriggerModeManualAfterInitial, out.Resources["fe"].TriggerMode)
+	assert.True(t, finish.Equal(out.Resources["fe"].LastBuildFinish))
+}
+
+func TestReadIncompatibleVersion(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	require.NoError(t, f.ss.Write(Session{
+		Version:   Version + 1,
+		Resources: map[string]Resource{"fe": Resource{Name: "fe"}},
+	}))
+
+	session, err := f.ss.Read()
+	require.NoError(t, err)
+	assert.Empty(t, session.Resources)
+}
+
+func TestReadCorrupt(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.WriteFile(filepath.Join(dirName, fileName), "{not json")
+
+	session, err := f.ss.Read()
+	require.NoError(t, err)
+	assert.Empty(t, session.Resources)
+}
+
+func TestInputHash(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.WriteFile("src/main.go", "package main")
+	paths := []string{f.JoinPath("src")}
+
+	h1 := InputHash(paths)
+	require.NotEqual(t, "", h1)
+	assert.Equal(t, h1, InputHash(paths))
+
+	f.WriteFile("src/main.go", "package main // changed")
+	assert.NotEqual(t, h1, InputHash(paths))
+}
+
+func TestInputHashMissingPath(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	assert.Equal(t, "", InputHash([]string{f.JoinPath("does-not-exist")}))
+}
+
+func TestControllerPersistsCompletedBuilds(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	st := store.NewTestingStore()
+	st.WithState(func(state *store.EngineState) {
+		state.TiltfilePath = f.JoinPath("Tiltfile")
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		state.UpsertManifestTarget(store.NewManifestTarget(m))
+	})
+
+	c := NewController()
+	c.OnChange(f.ctx, st)
+
+	session, err := f.ss.Read()
+	require.NoError(t, err)
+	assert.Empty(t, session.Resources, "no builds yet")
+
+	finish := time.Now()
+	st.WithState(func(state *store.EngineState) {
+		state.ManifestTargets["fe"].State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  finish.Add(-2 * time.Second),
+			FinishTime: finish,
+		})
+	})
+
+	c.OnChange(f.ctx, st)
+
+	session, err = f.ss.Read()
+	require.NoError(t, err)
+	require.Contains(t, session.Resources, "fe")
+	assert.Equal(t, "", session.Resources["fe"].LastBuildError)
+	assert.True(t, finish.Equal(session.Resources["fe"].LastBuildFinish))
+}
+
+func TestControllerRecordsBuildError(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	st := store.NewTestingStore()
+	st.WithState(func(state *store.EngineState) {
+		state.TiltfilePath = f.JoinPath("Tiltfile")
+		m := manifestbuilder.New(f, "fe").WithK8sYAML(testyaml.SanchoYAML).Build()
+		mt := store.NewManifestTarget(m)
+		mt.State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now().Add(-time.Second),
+			FinishTime: time.Now(),
+			Error:      fmt.Errorf("build failed"),
+		})
+		state.UpsertManifestTarget(mt)
+	})
+
+	c := NewController()
+	c.OnChange(f.ctx, st)
+
+	session, err := f.ss.Read()
+	require.NoError(t, err)
+	require.Contains(t, session.Resources, "fe")
+	assert.Equal(t, "build failed", session.Resources["fe"].LastBuildError)
+}
+
+func TestControllerRestoresPreviousSession(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	require.NoError(t, f.ss.Write(Session{Version: Version, Resources: map[string]Resource{
+		"fe": Resource{Name: "fe", InputHash: "abc123"},
+	}}))
+
+	st := store.NewTestingStore()
+	st.WithState(func(state *store.EngineState) {
+		state.TiltfilePath = f.JoinPath("Tiltfile")
+	})
+
+	c := NewController()
+	c.OnChange(f.ctx, st)
+	c.OnChange(f.ctx, st)
+
+	var restores []RestoredAction
+	for _, a := range st.Actions() {
+		if ra, ok := a.(RestoredAction); ok {
+			restores = append(restores, ra)
+		}
+	}
+	require.Len(t, restores, 1, "the session should only be restored once")
+	assert.Equal(t, "abc123", restores[0].Resources["fe"].InputHash)
+}
+
+type fixture struct {
+	*tempdir.TempDirFixture
+	ctx context.Context
+	ss  *Store
+}
+
+func newFixture(t *testing.T) *fixture {
+	f := tempdir.NewTempDirFixture(t)
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	return &fixture{
+		TempDirFixture: f,
+		ctx:            ctx,
+		ss:             NewStore(f.JoinPath("Tiltfile")),
+	}
+}
@@ -62,6 +62,29 @@ func (le LogAction) String() string {
 	return fmt.Sprintf("manifest: %s, spanID: %s, msg: %q", le.mn, le.spanID, le.msg)
 }
 
+// Consecutive appends to the same log span at the same level merge into
+// one action, so a chatty container doesn't flood the action queue.
+func (le LogAction) Coalesce(next Action) (Action, bool) {
+	nextLog, ok := next.(LogAction)
+	if !ok {
+		return nil, false
+	}
+
+	sameSpan := le.mn == nextLog.mn &&
+		le.spanID == nextLog.spanID &&
+		le.level == nextLog.level &&
+		le.fields == nil && nextLog.fields == nil
+	if !sameSpan {
+		return nil, false
+	}
+
+	merged := le
+	merged.msg = append(append([]byte{}, le.msg...), nextLog.msg...)
+	return merged, true
+}
+
+var _ CoalescingAction = LogAction{}
+
 func NewLogAction(mn model.ManifestName, spanID logstore.SpanID, level logger.Level, fields logger.Fields, b []byte) LogAction {
 	return LogAction{
 		mn:        mn,
@@ -126,33 +126,61 @@ type K8sBuildResult struct {
 	// Hashes of the pod template specs that we deployed to a Kubernetes cluster.
 	PodTemplateSpecHashes []k8s.PodTemplateSpecHash
 
+	// Metadata of the objects we deployed, for drift detection.
+	DeployedObjects []DeployedObject
+
 	AppliedEntitiesText string
+
+	// A rendered diff against the previous deploy's entities. Empty on the
+	// first deploy, or when nothing changed.
+	DiffText string
 }
 
 func (r K8sBuildResult) TargetID() model.TargetID   { return r.id }
 func (r K8sBuildResult) BuildType() model.BuildType { return model.BuildTypeK8s }
 func (r K8sBuildResult) Facets() []model.Facet {
 
-	return []model.Facet{
+	facets := []model.Facet{
 		{
 			Name:  "applied yaml",
 			Value: r.AppliedEntitiesText,
 		},
 	}
+	if r.DiffText != "" {
+		facets = append(facets, model.Facet{
+			Name:  "diff vs last deploy",
+			Value: r.DiffText,
+		})
+	}
+	return facets
 }
 
 // For kubernetes deploy targets.
-func NewK8sDeployResult(id model.TargetID, uids []types.UID, hashes []k8s.PodTemplateSpecHash, appliedEntities []k8s.K8sEntity) BuildResult {
+func NewK8sDeployResult(id model.TargetID, uids []types.UID, hashes []k8s.PodTemplateSpecHash, appliedEntities []k8s.K8sEntity, diffText string) BuildResult {
 	appliedEntitiesText, err := k8s.SerializeSpecYAML(appliedEntities)
 	if err != nil {
 		appliedEntitiesText = fmt.Sprintf("unable to serialize entities to yaml: %s", err.Error())
 	}
 
+	var deployedObjects []DeployedObject
+	for _, e := range appliedEntities {
+		if e.UID() == "" {
+			continue
+		}
+		deployedObjects = append(deployedObjects, DeployedObject{
+			Ref:             e.ToObjectReference(),
+			Generation:      e.Generation(),
+			ResourceVersion: e.ResourceVersion(),
+		})
+	}
+
 	return K8sBuildResult{
 		id:                    id,
 		DeployedUIDs:          uids,
 		PodTemplateSpecHashes: hashes,
+		DeployedObjects:       deployedObjects,
 		AppliedEntitiesText:   appliedEntitiesText,
+		DiffText:              diffText,
 	}
 }
 
@@ -196,6 +224,19 @@ func (set BuildResultSet) DeployedUIDSet() UIDSet {
 	return result
 }
 
+func (set BuildResultSet) DeployedObjects() map[types.UID]DeployedObject {
+	result := make(map[types.UID]DeployedObject)
+	for _, r := range set {
+		r, ok := r.(K8sBuildResult)
+		if ok {
+			for _, obj := range r.DeployedObjects {
+				result[obj.Ref.UID] = obj
+			}
+		}
+	}
+	return result
+}
+
 func (set BuildResultSet) DeployedPodTemplateSpecHashes() PodTemplateSpecHashSet {
 	result := NewPodTemplateSpecHashSet()
 	for _, r := range set {
@@ -456,6 +497,11 @@ func RunningContainersForTargetForOnePod(iTarget model.ImageTarget, runtimeState
 
 	var containers []ContainerInfo
 	for _, c := range pod.Containers {
+		// A mesh-injected sidecar proxy is never a container we built,
+		// so it's never a candidate for in-place updates.
+		if c.MeshInjected {
+			continue
+		}
 		// Only return containers matching our image
 		if c.ImageRef == nil || iTarget.Refs.ClusterRef().Name() != c.ImageRef.Name() {
 			continue
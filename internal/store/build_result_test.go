@@ -30,3 +30,25 @@ func TestOneAndOnlyLiveUpdatedContainerID(t *testing.T) {
 	}
 	assert.Equal(t, "cA", string(set.OneAndOnlyLiveUpdatedContainerID()))
 }
+
+func TestRunningContainersExcludeMeshSidecars(t *testing.T) {
+	iTarget := model.MustNewImageTarget(container.MustParseSelector("sancho"))
+	runtime := NewK8sRuntimeState("sancho", Pod{
+		PodID: "pod-id",
+		Containers: []Container{
+			{Name: "sancho", ID: "cid1", Running: true,
+				ImageRef: container.MustParseNamed("sancho")},
+			// Exclusion is by the injected flag, not the image ref, so a
+			// sidecar can't become a live_update target no matter what
+			// image the mesh runs it from.
+			{Name: "istio-proxy", ID: "cid2", Running: true,
+				ImageRef: container.MustParseNamed("sancho"), MeshInjected: true},
+		},
+	})
+
+	containers, err := RunningContainersForTargetForOnePod(iTarget, runtime)
+	assert.NoError(t, err)
+	if assert.Len(t, containers, 1) {
+		assert.Equal(t, "sancho", string(containers[0].ContainerName))
+	}
+}
@@ -44,6 +44,9 @@ type EngineState struct {
 
 	MaxParallelUpdates int
 
+	// How long each `kubectl apply` gets before we give up on the update.
+	K8sUpsertTimeout time.Duration
+
 	FatalError error
 
 	HUDEnabled bool
@@ -76,6 +79,14 @@ type EngineState struct {
 
 	TriggerQueue []model.ManifestName
 
+	// cmd_button presses waiting for the CmdButtonController to run them.
+	CmdButtonQueue []CmdButtonPress
+
+	// Build state restored from a previous session's .tilt/ dir, keyed by
+	// manifest name. Consulted when manifests first load, to skip initial
+	// builds whose inputs haven't changed since the last session.
+	RestoredSession map[model.ManifestName]RestoredResourceState
+
 	IsProfiling bool
 
 	TiltfileState ManifestState
@@ -103,6 +114,19 @@ type EngineState struct {
 
 	TelemetrySettings model.TelemetrySettings
 
+	NotifySettings model.NotifySettings
+
+	SecuritySettings model.SecuritySettings
+
+	SigningSettings model.SigningSettings
+
+	// Images to pull at startup, before any builds run.
+	PrePullImages []string
+
+	// The kubeconfig's current context, if it has changed since startup.
+	// Empty means the context Tilt started with.
+	CurrentKubeContext k8s.KubeContext
+
 	UserConfigState model.UserConfigState
 }
 
@@ -114,15 +138,18 @@ type CloudStatus struct {
 }
 
 // Merge analytics opt-in status from different sources.
-// The Tiltfile opt-in takes precedence over the user opt-in.
+// The most restrictive setting wins: an opt-out from the environment,
+// the Tiltfile, or the user disables reporting.
 func (e *EngineState) AnalyticsEffectiveOpt() analytics.Opt {
-	if e.AnalyticsEnvOpt != analytics.OptDefault {
-		return e.AnalyticsEnvOpt
+	if e.AnalyticsEnvOpt == analytics.OptOut ||
+		e.AnalyticsTiltfileOpt == analytics.OptOut ||
+		e.AnalyticsUserOpt == analytics.OptOut {
+		return analytics.OptOut
 	}
-	if e.AnalyticsTiltfileOpt != analytics.OptDefault {
-		return e.AnalyticsTiltfileOpt
+	if e.AnalyticsTiltfileOpt == analytics.OptIn || e.AnalyticsUserOpt == analytics.OptIn {
+		return analytics.OptIn
 	}
-	return e.AnalyticsUserOpt
+	return analytics.OptDefault
 }
 
 func (e *EngineState) ManifestNamesForTargetID(id model.TargetID) []model.ManifestName {
@@ -237,6 +264,14 @@ func (e EngineState) Targets() []*ManifestTarget {
 	return result
 }
 
+// A single press of a cmd_button, queued until the controller runs it.
+// Time distinguishes repeated presses of the same button.
+type CmdButtonPress struct {
+	ManifestName model.ManifestName
+	ButtonName   string
+	Time         time.Time
+}
+
 func (e *EngineState) ManifestInTriggerQueue(mn model.ManifestName) bool {
 	for _, queued := range e.TriggerQueue {
 		if queued == mn {
@@ -337,10 +372,23 @@ type ManifestState struct {
 	// We detected stale code and are currently doing an image build
 	NeedsRebuildFromCrash bool
 
+	// Display names of deployed objects that have been edited out-of-band
+	// (e.g., with kubectl edit) since our last apply. Cleared when the
+	// next build completes.
+	DriftedObjects []string
+
+	// Latest result of each link health check, keyed by the link's URL.
+	// A missing key means the link hasn't been probed yet.
+	LinkHealth map[string]bool
+
 	// If a pod had to be killed because it was crashing, we keep the old log
 	// around for a little while so we can show it in the UX.
 	CrashLog model.Log
 
+	// Port forwards added at runtime (`tilt alpha port-forward`), on top of
+	// any declared in the Tiltfile. Keyed by local port.
+	AdHocPortForwards []model.PortForward
+
 	// If this manifest was changed, which config files led to the most recent change in manifest definition
 	ConfigFilesThatCausedChange []string
 
@@ -348,17 +396,32 @@ type ManifestState struct {
 	TriggerReason model.BuildReason
 }
 
+// Per-resource build state persisted by a previous Tilt session.
+//
+// This lives in the store package (rather than internal/session) so the
+// reducers can consult it without an import cycle.
+type RestoredResourceState struct {
+	TriggerMode     model.TriggerMode
+	InputHash       string
+	LastBuildStart  time.Time
+	LastBuildFinish time.Time
+	LastBuildError  string
+}
+
 func NewState() *EngineState {
 	ret := &EngineState{}
 	ret.LogStore = logstore.NewLogStore()
 	ret.ManifestTargets = make(map[model.ManifestName]*ManifestTarget)
 	ret.PendingConfigFileChanges = make(map[string]time.Time)
+	ret.RestoredSession = make(map[model.ManifestName]RestoredResourceState)
 	ret.Secrets = model.SecretSet{}
 	ret.DockerPruneSettings = model.DefaultDockerPruneSettings()
+	ret.SecuritySettings = model.DefaultSecuritySettings()
 	ret.VersionSettings = model.VersionSettings{
 		CheckUpdates: true,
 	}
 	ret.MaxParallelUpdates = 1
+	ret.K8sUpsertTimeout = model.DefaultK8sUpsertTimeout
 	ret.CurrentlyBuilding = make(map[model.ManifestName]bool)
 
 	if ok, _ := tiltanalytics.IsAnalyticsDisabledFromEnv(); ok {
@@ -597,9 +660,16 @@ func ManifestTargetEndpoints(mt *ManifestTarget) (endpoints []string) {
 		sort.Strings(endpoints)
 	}()
 
-	// If the user specified port-forwards in the Tiltfile, we
-	// assume that's what they want to see in the UI
-	portForwards := mt.Manifest.K8sTarget().PortForwards
+	// Links declared in the Tiltfile always show, on top of whatever we
+	// can derive below.
+	for _, l := range mt.Manifest.Links {
+		endpoints = append(endpoints, l.URL)
+	}
+
+	// If the user specified port-forwards in the Tiltfile (or added some at
+	// runtime), we assume that's what they want to see in the UI
+	portForwards := append(append([]model.PortForward{},
+		mt.Manifest.K8sTarget().PortForwards...), mt.State.AdHocPortForwards...)
 	if len(portForwards) > 0 {
 		for _, pf := range portForwards {
 			host := pf.Host
@@ -690,6 +760,7 @@ func StateToView(s EngineState, mu *sync.RWMutex) view.View {
 			PathsWatched:       relWatchPaths,
 			LastDeployTime:     ms.LastSuccessfulDeployTime,
 			TriggerMode:        mt.Manifest.TriggerMode,
+			State:              ResourceStateFor(s, mt),
 			BuildHistory:       buildHistory,
 			PendingBuildEdits:  pendingBuildEdits,
 			PendingBuildSince:  pendingBuildSince,
@@ -751,14 +822,27 @@ func resourceInfoView(mt *ManifestTarget) view.ResourceInfoView {
 			state.ContainerState.Status, state.ContainerID, state.SpanID, state.StartTime, runStatus)
 	case K8sRuntimeState:
 		pod := state.MostRecentPod()
+		var probeWarnings []string
+		for _, w := range pod.ProbeWarnings {
+			probeWarnings = append(probeWarnings, w.String())
+		}
+		cpuUsage, memoryUsage := "", ""
+		if !pod.Usage.Empty() {
+			cpuUsage = fmt.Sprintf("%dm", pod.Usage.CPU.MilliValue())
+			memoryUsage = fmt.Sprintf("%dMi", pod.Usage.Memory.Value()/(1024*1024))
+		}
 		return view.K8sResourceInfo{
-			PodName:            pod.PodID.String(),
-			PodCreationTime:    pod.StartedAt,
-			PodUpdateStartTime: pod.UpdateStartTime,
-			PodStatus:          pod.Status,
-			PodRestarts:        pod.VisibleContainerRestarts(),
-			SpanID:             pod.SpanID,
-			RunStatus:          runStatus,
+			PodName:             pod.PodID.String(),
+			PodCreationTime:     pod.StartedAt,
+			PodUpdateStartTime:  pod.UpdateStartTime,
+			PodStatus:           pod.Status,
+			PodRestarts:         pod.VisibleContainerRestarts(),
+			SpanID:              pod.SpanID,
+			RunStatus:           runStatus,
+			PodProbeWarnings:    probeWarnings,
+			PodCPUUsage:         cpuUsage,
+			PodMemoryUsage:      memoryUsage,
+			PodMemoryPctOfLimit: pod.MemoryPctOfLimit(),
 		}
 	case LocalRuntimeState:
 		return view.NewLocalResourceInfo(runStatus, state.PID, state.SpanID)
@@ -768,6 +852,82 @@ func resourceInfoView(mt *ManifestTarget) view.ResourceInfoView {
 	}
 }
 
+// CopyForView returns a snapshot of the state that's safe to read after
+// the state lock has been released, so the webview can be serialized on
+// a background goroutine without blocking reducers.
+//
+// This isn't a full deep copy. Slices are shared, because reducers
+// replace them wholesale instead of editing their elements. Maps and
+// pointer-backed structures that reducers write through are copied.
+func (e EngineState) CopyForView() EngineState {
+	ret := e
+
+	ret.ManifestTargets = make(map[model.ManifestName]*ManifestTarget, len(e.ManifestTargets))
+	for name, mt := range e.ManifestTargets {
+		ret.ManifestTargets[name] = &ManifestTarget{
+			Manifest: mt.Manifest,
+			State:    mt.State.copyForView(),
+		}
+	}
+
+	ret.TiltfileState = *e.TiltfileState.copyForView()
+
+	ret.CurrentlyBuilding = make(map[model.ManifestName]bool, len(e.CurrentlyBuilding))
+	for name, building := range e.CurrentlyBuilding {
+		ret.CurrentlyBuilding[name] = building
+	}
+
+	ret.PendingConfigFileChanges = make(map[string]time.Time, len(e.PendingConfigFileChanges))
+	for path, t := range e.PendingConfigFileChanges {
+		ret.PendingConfigFileChanges[path] = t
+	}
+
+	if e.LogStore != nil {
+		ret.LogStore = e.LogStore.Copy()
+	}
+
+	return ret
+}
+
+func (ms *ManifestState) copyForView() *ManifestState {
+	ret := *ms
+
+	ret.BuildStatuses = make(map[model.TargetID]*BuildStatus, len(ms.BuildStatuses))
+	for id, status := range ms.BuildStatuses {
+		statusCopy := *status
+		statusCopy.PendingFileChanges = make(map[string]time.Time, len(status.PendingFileChanges))
+		for path, t := range status.PendingFileChanges {
+			statusCopy.PendingFileChanges[path] = t
+		}
+		ret.BuildStatuses[id] = &statusCopy
+	}
+
+	ret.LiveUpdatedContainerIDs = make(map[container.ID]bool, len(ms.LiveUpdatedContainerIDs))
+	for id, v := range ms.LiveUpdatedContainerIDs {
+		ret.LiveUpdatedContainerIDs[id] = v
+	}
+
+	if krs, ok := ms.RuntimeState.(K8sRuntimeState); ok {
+		ret.RuntimeState = krs.copyForView()
+	}
+
+	return &ret
+}
+
+func (s K8sRuntimeState) copyForView() K8sRuntimeState {
+	ret := s
+
+	// Pod reducers mutate pods through these pointers; everything else in
+	// here is replaced wholesale on deploy.
+	ret.Pods = make(map[k8s.PodID]*Pod, len(s.Pods))
+	for id, pod := range s.Pods {
+		podCopy := *pod
+		ret.Pods[id] = &podCopy
+	}
+
+	return ret
+}
+
 // DockerComposeConfigPath returns the path to the docker-compose yaml file of any
 // docker-compose manifests on this EngineState.
 // NOTE(maia): current assumption is only one d-c.yaml per run, so we take the
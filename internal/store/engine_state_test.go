@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/windmilleng/tilt/internal/hud/view"
 	"github.com/windmilleng/tilt/internal/k8s/testyaml"
@@ -16,6 +17,7 @@ import (
 	"github.com/windmilleng/tilt/internal/testutils/tempdir"
 
 	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/pkg/logger"
 	"github.com/windmilleng/tilt/pkg/model"
 )
 
@@ -84,6 +86,99 @@ func TestRuntimeStateUnresourced(t *testing.T) {
 		state.ManifestTargets[m.Name].State.GetOrCreateK8sRuntimeState().RuntimeStatus())
 }
 
+func TestRuntimeStateEveryPodWorkload(t *testing.T) {
+	readyPod := Pod{PodID: "ds-node-1", StartedAt: time.Now(), Phase: corev1.PodRunning,
+		Containers: []Container{{Ready: true}}}
+	pendingPod := Pod{PodID: "ds-node-2", StartedAt: time.Now().Add(time.Minute), Phase: corev1.PodPending,
+		Containers: []Container{{Ready: false}}}
+
+	state := NewK8sRuntimeState("ds", readyPod, pendingPod)
+	state.DeployedObjects["ds-uid"] = DeployedObject{Ref: corev1.ObjectReference{Kind: "DaemonSet"}}
+
+	// A Deployment would report OK once the newest pod is ready; a
+	// DaemonSet isn't healthy until every node's pod is.
+	assert.Equal(t, model.RuntimeStatusPending, state.RuntimeStatus())
+
+	state.Pods["ds-node-2"].Phase = corev1.PodRunning
+	state.Pods["ds-node-2"].Containers = []Container{{Ready: true}}
+	assert.Equal(t, model.RuntimeStatusOK, state.RuntimeStatus())
+
+	state.Pods["ds-node-1"].Phase = corev1.PodFailed
+	assert.Equal(t, model.RuntimeStatusError, state.RuntimeStatus())
+	if assert.Error(t, state.RuntimeStatusError()) {
+		assert.Contains(t, state.RuntimeStatusError().Error(), "ds-node-1")
+	}
+}
+
+func TestRuntimeStateJobs(t *testing.T) {
+	failedPod := Pod{PodID: "attempt-1", StartedAt: time.Now(), Phase: "Failed"}
+	state := NewK8sRuntimeState("migrate", failedPod)
+
+	// With no conditions reported yet, the job is still running.
+	state.Jobs["job-uid"] = &K8sJob{Name: "migrate"}
+	assert.Equal(t, model.RuntimeStatusPending, state.RuntimeStatus())
+
+	// Once the Job reports Complete, the dead attempt pod doesn't matter.
+	state.Jobs["job-uid"].Succeeded = true
+	assert.Equal(t, model.RuntimeStatusOK, state.RuntimeStatus())
+
+	state.Jobs["job-uid"].Succeeded = false
+	state.Jobs["job-uid"].Failed = true
+	state.Jobs["job-uid"].Message = "BackoffLimitExceeded"
+	assert.Equal(t, model.RuntimeStatusError, state.RuntimeStatus())
+	if assert.Error(t, state.RuntimeStatusError()) {
+		assert.Contains(t, state.RuntimeStatusError().Error(), "BackoffLimitExceeded")
+	}
+}
+
+func TestProbeWarnings(t *testing.T) {
+	pod := Pod{PodID: "pod-id", Containers: []Container{
+		{Name: "app", ID: "cid1", Ready: false},
+	}}
+
+	pod.RecordProbeWarning(ProbeWarning{Probe: "Readiness", ContainerName: "app",
+		Count: 1, LastOutput: "connection refused"})
+	pod.RecordProbeWarning(ProbeWarning{Probe: "Readiness", ContainerName: "app",
+		Count: 4, LastOutput: "HTTP probe failed with statuscode: 500"})
+
+	// Repeated failures collapse into one warning per container and probe.
+	if assert.Len(t, pod.ProbeWarnings, 1) {
+		w := pod.ProbeWarnings[0]
+		assert.Equal(t, 4, w.Count)
+		assert.Contains(t, w.String(), "Readiness probe failed (x4)")
+		assert.Contains(t, w.String(), "HTTP probe failed with statuscode: 500")
+	}
+
+	// Some kubelets reset the event count; keep ours monotonic.
+	pod.RecordProbeWarning(ProbeWarning{Probe: "Readiness", ContainerName: "app", Count: 1})
+	assert.Equal(t, 5, pod.ProbeWarnings[0].Count)
+
+	// Once the container goes ready, the warning clears.
+	pod.PruneProbeWarnings()
+	assert.Len(t, pod.ProbeWarnings, 1)
+	pod.Containers[0].Ready = true
+	pod.PruneProbeWarnings()
+	assert.Len(t, pod.ProbeWarnings, 0)
+}
+
+func TestMeshSidecarReadiness(t *testing.T) {
+	pod := Pod{PodID: "pod-id", Phase: corev1.PodRunning, Containers: []Container{
+		{Name: "app", ID: "cid1", Ready: true},
+		{Name: "istio-proxy", ID: "cid2", Ready: false, MeshInjected: true},
+	}}
+
+	// The injected proxy isn't the user's container, but the pod isn't
+	// ready until it is.
+	assert.False(t, pod.AllContainersReady())
+
+	pod.Containers[1].Ready = true
+	assert.True(t, pod.AllContainersReady())
+
+	if assert.Len(t, pod.AppContainers(), 1) {
+		assert.Equal(t, "app", string(pod.AppContainers()[0].Name))
+	}
+}
+
 func TestStateToViewUnresourcedYAMLManifest(t *testing.T) {
 	m, err := k8s.NewK8sOnlyManifestFromYAML(testyaml.SanchoYAML)
 	assert.NoError(t, err)
@@ -123,3 +218,25 @@ func TestRelativeTiltfilePath(t *testing.T) {
 	}
 	assert.Equal(t, "Tiltfile", actual)
 }
+
+func TestCopyForViewIsolatesMutableState(t *testing.T) {
+	m := model.Manifest{Name: "fe"}.WithDeployTarget(model.K8sTarget{Name: "fe"})
+	state := newState([]model.Manifest{m})
+	ms := state.ManifestTargets[m.Name].State
+	ms.RuntimeState = NewK8sRuntimeState(m.Name, Pod{PodID: "pod-a", Status: "Running"})
+	ms.MutableBuildStatus(m.K8sTarget().ID()).PendingFileChanges["a.txt"] = time.Now()
+	state.LogStore.Append(NewGlobalLogAction(logger.InfoLvl, []byte("hello\n")), nil)
+
+	snapshot := state.CopyForView()
+
+	// Mutate the original the way reducers do, and make sure the
+	// snapshot doesn't see the changes.
+	ms.K8sRuntimeState().Pods["pod-a"].Status = "Error"
+	ms.MutableBuildStatus(m.K8sTarget().ID()).PendingFileChanges["b.txt"] = time.Now()
+	state.LogStore.Append(NewGlobalLogAction(logger.InfoLvl, []byte("goodbye\n")), nil)
+
+	snapshotMS := snapshot.ManifestTargets[m.Name].State
+	assert.Equal(t, "Running", snapshotMS.K8sRuntimeState().Pods["pod-a"].Status)
+	assert.Equal(t, 1, len(snapshotMS.BuildStatus(m.K8sTarget().ID()).PendingFileChanges))
+	assert.Equal(t, "hello\n", snapshot.LogStore.String())
+}
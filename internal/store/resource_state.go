@@ -0,0 +1,228 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// Pod statuses that mean the pod is stuck fetching its image rather
+// than in a more general error or startup state.
+var imagePullStatuses = map[string]bool{
+	"Pulling":          true,
+	"ErrImagePull":     true,
+	"ImagePullBackOff": true,
+}
+
+// ResourceStateFor derives the structured lifecycle state for a single
+// resource from the engine state. The full EngineState is needed to
+// check whether resource_deps are satisfied.
+func ResourceStateFor(s EngineState, mt *ManifestTarget) model.ResourceStateStatus {
+	ms := mt.State
+
+	if ms.IsBuilding() {
+		state := model.ResourceStateUpdating
+		if len(ms.BuildHistory) == 0 {
+			state = model.ResourceStateBuilding
+		}
+		return model.ResourceStateStatus{State: state, Reason: ms.CurrentBuild.Reason.String()}
+	}
+
+	if !ms.StartedFirstBuild() {
+		if !mt.Manifest.TriggerMode.AutoInitial() {
+			return model.ResourceStateStatus{
+				State:  model.ResourceStateDisabled,
+				Reason: "waiting for the user to trigger the initial build",
+			}
+		}
+		if dep, waiting := unreadyDependency(s, mt); waiting {
+			return model.ResourceStateStatus{
+				State:  model.ResourceStateWaitingOnDeps,
+				Reason: fmt.Sprintf("waiting for resource %s to be ready", dep),
+			}
+		}
+		return model.ResourceStateStatus{
+			State:  model.ResourceStateBuilding,
+			Reason: "initial build queued",
+		}
+	}
+
+	lastBuild := ms.LastBuild()
+	if lastBuild.Error != nil {
+		return model.ResourceStateStatus{
+			State:  model.ResourceStateDegraded,
+			Reason: fmt.Sprintf("build failed: %v", lastBuild.Error),
+		}
+	}
+
+	if ms.NeedsRebuildFromCrash {
+		return model.ResourceStateStatus{
+			State:  model.ResourceStateDegraded,
+			Reason: "container crashed and lost live_update changes",
+		}
+	}
+
+	if len(ms.DriftedObjects) > 0 {
+		return model.ResourceStateStatus{
+			State:  model.ResourceStateDrifted,
+			Reason: fmt.Sprintf("out-of-band changes to %s; trigger an update to re-apply", strings.Join(ms.DriftedObjects, ", ")),
+		}
+	}
+
+	var status model.ResourceStateStatus
+	if kState, ok := ms.RuntimeState.(K8sRuntimeState); ok {
+		status = checkReadinessTimeout(mt, kState, k8sResourceState(kState))
+	} else {
+		status = runtimeStatusResourceState(ms)
+	}
+
+	// A failing health check on a required link demotes an otherwise-ready
+	// resource. Links that haven't been probed yet don't count against it.
+	if status.State == model.ResourceStateReady {
+		for _, l := range mt.Manifest.Links {
+			if up, probed := ms.LinkHealth[l.URL]; l.RequiredForReadiness && probed && !up {
+				return model.ResourceStateStatus{
+					State:  model.ResourceStateDegraded,
+					Reason: fmt.Sprintf("link %s failed its health check", l.URL),
+				}
+			}
+		}
+	}
+	return status
+}
+
+// If the user set readiness_timeout and pods have been coming up for
+// longer than that since the last deploy, stop reporting an in-progress
+// state and degrade with a diagnosis of what's stuck.
+func checkReadinessTimeout(mt *ManifestTarget, state K8sRuntimeState, status model.ResourceStateStatus) model.ResourceStateStatus {
+	timeout := mt.Manifest.ReadinessTimeout
+	if timeout == 0 {
+		return status
+	}
+
+	switch status.State {
+	case model.ResourceStateDeploying, model.ResourceStateWaitingForPodSchedule, model.ResourceStateImagePulling:
+	default:
+		return status
+	}
+
+	finish := mt.State.LastBuild().FinishTime
+	if finish.IsZero() || time.Since(finish) < timeout {
+		return status
+	}
+
+	return model.ResourceStateStatus{
+		State:  model.ResourceStateDegraded,
+		Reason: fmt.Sprintf("not ready within %s: %s", timeout, stuckDiagnosis(state.MostRecentPod())),
+	}
+}
+
+// Summarize why a pod isn't ready, so a timed-out resource tells the
+// user what to look at instead of just going red.
+func stuckDiagnosis(pod Pod) string {
+	if pod.Empty() {
+		return "no pod has been created"
+	}
+
+	var parts []string
+	for _, c := range pod.Conditions {
+		if c.Type == v1.PodScheduled && c.Status == v1.ConditionFalse {
+			parts = append(parts, fmt.Sprintf("unschedulable: %s", c.Message))
+		}
+	}
+	if imagePullStatuses[pod.Status] {
+		parts = append(parts, fmt.Sprintf("stuck pulling an image (%s)", pod.Status))
+	}
+	parts = append(parts, pod.StatusMessages...)
+	for _, c := range pod.Containers {
+		if !c.Ready {
+			parts = append(parts, fmt.Sprintf("container %s not ready (%d restarts)", c.Name, c.Restarts))
+		}
+	}
+
+	if len(parts) == 0 {
+		return fmt.Sprintf("pod %s status %s", pod.PodID, pod.Status)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func k8sResourceState(state K8sRuntimeState) model.ResourceStateStatus {
+	if state.IsUnresourced {
+		return model.ResourceStateStatus{State: model.ResourceStateReady}
+	}
+
+	pod := state.MostRecentPod()
+	if pod.Empty() {
+		return model.ResourceStateStatus{
+			State:  model.ResourceStateDeploying,
+			Reason: "waiting for a pod to be created",
+		}
+	}
+
+	for _, c := range pod.Conditions {
+		if c.Type == v1.PodScheduled && c.Status == v1.ConditionFalse {
+			return model.ResourceStateStatus{
+				State:  model.ResourceStateWaitingForPodSchedule,
+				Reason: c.Message,
+			}
+		}
+	}
+
+	if imagePullStatuses[pod.Status] {
+		return model.ResourceStateStatus{
+			State:  model.ResourceStateImagePulling,
+			Reason: fmt.Sprintf("pod %s: %s", pod.PodID, pod.Status),
+		}
+	}
+
+	switch state.RuntimeStatus() {
+	case model.RuntimeStatusOK:
+		return model.ResourceStateStatus{State: model.ResourceStateReady}
+	case model.RuntimeStatusError:
+		return model.ResourceStateStatus{
+			State:  model.ResourceStateDegraded,
+			Reason: state.RuntimeStatusError().Error(),
+		}
+	}
+
+	return model.ResourceStateStatus{
+		State:  model.ResourceStateDeploying,
+		Reason: fmt.Sprintf("pod %s: %s", pod.PodID, pod.Status),
+	}
+}
+
+func runtimeStatusResourceState(ms *ManifestState) model.ResourceStateStatus {
+	if ms.RuntimeState == nil {
+		return model.ResourceStateStatus{State: model.ResourceStateDeploying}
+	}
+
+	switch ms.RuntimeState.RuntimeStatus() {
+	case model.RuntimeStatusOK, model.RuntimeStatusNotApplicable:
+		return model.ResourceStateStatus{State: model.ResourceStateReady}
+	case model.RuntimeStatusError:
+		return model.ResourceStateStatus{
+			State:  model.ResourceStateDegraded,
+			Reason: ms.RuntimeState.RuntimeStatusError().Error(),
+		}
+	case model.RuntimeStatusPending:
+		return model.ResourceStateStatus{State: model.ResourceStateDeploying}
+	}
+
+	return model.ResourceStateStatus{State: model.ResourceStateUnknown}
+}
+
+// Mirrors the gating in buildcontrol: resource_deps only block a
+// resource's first build.
+func unreadyDependency(s EngineState, mt *ManifestTarget) (model.ManifestName, bool) {
+	for _, mn := range mt.Manifest.ResourceDependencies {
+		ms, ok := s.ManifestState(mn)
+		if !ok || ms == nil || ms.RuntimeState == nil || !ms.RuntimeState.HasEverBeenReadyOrSucceeded() {
+			return mn, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,219 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestResourceStateFor(t *testing.T) {
+	completedBuild := model.BuildRecord{
+		StartTime:  time.Now().Add(-5 * time.Second),
+		FinishTime: time.Now(),
+	}
+
+	for _, tc := range []struct {
+		name           string
+		setup          func(state *EngineState, mt *ManifestTarget)
+		expectedState  model.ResourceState
+		expectedReason string
+	}{
+		{
+			name: "disabled",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.Manifest.TriggerMode = model.TriggerModeManualIncludingInitial
+			},
+			expectedState:  model.ResourceStateDisabled,
+			expectedReason: "waiting for the user to trigger the initial build",
+		},
+		{
+			name: "waiting on deps",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.Manifest.ResourceDependencies = []model.ManifestName{"dep"}
+				state.UpsertManifestTarget(NewManifestTarget(model.Manifest{Name: "dep"}))
+			},
+			expectedState:  model.ResourceStateWaitingOnDeps,
+			expectedReason: "waiting for resource dep to be ready",
+		},
+		{
+			name: "first build",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.State.CurrentBuild = model.BuildRecord{
+					StartTime: time.Now(),
+					Reason:    model.BuildReasonFlagInit,
+				}
+			},
+			expectedState:  model.ResourceStateBuilding,
+			expectedReason: "Initial Build",
+		},
+		{
+			name: "updating",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.State.AddCompletedBuild(completedBuild)
+				mt.State.CurrentBuild = model.BuildRecord{
+					StartTime: time.Now(),
+					Reason:    model.BuildReasonFlagChangedFiles,
+				}
+			},
+			expectedState:  model.ResourceStateUpdating,
+			expectedReason: "Changed Files",
+		},
+		{
+			name: "build failed",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.State.AddCompletedBuild(model.BuildRecord{
+					StartTime:  completedBuild.StartTime,
+					FinishTime: completedBuild.FinishTime,
+					Error:      fmt.Errorf("compile error"),
+				})
+			},
+			expectedState:  model.ResourceStateDegraded,
+			expectedReason: "build failed: compile error",
+		},
+		{
+			name: "crash",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.State.AddCompletedBuild(completedBuild)
+				mt.State.NeedsRebuildFromCrash = true
+			},
+			expectedState:  model.ResourceStateDegraded,
+			expectedReason: "container crashed and lost live_update changes",
+		},
+		{
+			name: "deploying",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.State.AddCompletedBuild(completedBuild)
+				mt.State.RuntimeState = NewK8sRuntimeState(mt.Manifest.Name)
+			},
+			expectedState:  model.ResourceStateDeploying,
+			expectedReason: "waiting for a pod to be created",
+		},
+		{
+			name: "unschedulable pod",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.State.AddCompletedBuild(completedBuild)
+				mt.State.RuntimeState = NewK8sRuntimeState(mt.Manifest.Name, Pod{
+					PodID: k8s.PodID("pod-a"),
+					Phase: v1.PodPending,
+					Conditions: []v1.PodCondition{
+						{
+							Type:    v1.PodScheduled,
+							Status:  v1.ConditionFalse,
+							Message: "0/3 nodes are available",
+						},
+					},
+				})
+			},
+			expectedState:  model.ResourceStateWaitingForPodSchedule,
+			expectedReason: "0/3 nodes are available",
+		},
+		{
+			name: "image pulling",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.State.AddCompletedBuild(completedBuild)
+				mt.State.RuntimeState = NewK8sRuntimeState(mt.Manifest.Name, Pod{
+					PodID:  k8s.PodID("pod-a"),
+					Phase:  v1.PodPending,
+					Status: "ImagePullBackOff",
+				})
+			},
+			expectedState:  model.ResourceStateImagePulling,
+			expectedReason: "pod pod-a: ImagePullBackOff",
+		},
+		{
+			name: "ready",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.State.AddCompletedBuild(completedBuild)
+				mt.State.RuntimeState = LocalRuntimeState{Status: model.RuntimeStatusOK}
+			},
+			expectedState: model.ResourceStateReady,
+		},
+		{
+			name: "readiness timeout exceeded",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.Manifest.ReadinessTimeout = time.Minute
+				mt.State.AddCompletedBuild(model.BuildRecord{
+					StartTime:  time.Now().Add(-10 * time.Minute),
+					FinishTime: time.Now().Add(-5 * time.Minute),
+				})
+				mt.State.RuntimeState = NewK8sRuntimeState(mt.Manifest.Name, Pod{
+					PodID:  k8s.PodID("pod-a"),
+					Phase:  v1.PodPending,
+					Status: "ImagePullBackOff",
+				})
+			},
+			expectedState:  model.ResourceStateDegraded,
+			expectedReason: "not ready within 1m0s: stuck pulling an image (ImagePullBackOff)",
+		},
+		{
+			name: "readiness timeout not yet exceeded",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.Manifest.ReadinessTimeout = time.Hour
+				mt.State.AddCompletedBuild(model.BuildRecord{
+					StartTime:  time.Now().Add(-10 * time.Minute),
+					FinishTime: time.Now().Add(-5 * time.Minute),
+				})
+				mt.State.RuntimeState = NewK8sRuntimeState(mt.Manifest.Name)
+			},
+			expectedState:  model.ResourceStateDeploying,
+			expectedReason: "waiting for a pod to be created",
+		},
+		{
+			name: "required link down",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.Manifest.Links = []model.Link{{
+					URL:                  "http://localhost:8000/",
+					HealthCheckURL:       "http://localhost:8000/healthz",
+					RequiredForReadiness: true,
+				}}
+				mt.State.AddCompletedBuild(completedBuild)
+				mt.State.RuntimeState = LocalRuntimeState{Status: model.RuntimeStatusOK}
+				mt.State.LinkHealth = map[string]bool{"http://localhost:8000/": false}
+			},
+			expectedState:  model.ResourceStateDegraded,
+			expectedReason: "link http://localhost:8000/ failed its health check",
+		},
+		{
+			name: "required link not yet probed",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.Manifest.Links = []model.Link{{
+					URL:                  "http://localhost:8000/",
+					HealthCheckURL:       "http://localhost:8000/healthz",
+					RequiredForReadiness: true,
+				}}
+				mt.State.AddCompletedBuild(completedBuild)
+				mt.State.RuntimeState = LocalRuntimeState{Status: model.RuntimeStatusOK}
+			},
+			expectedState: model.ResourceStateReady,
+		},
+		{
+			name: "degraded runtime",
+			setup: func(state *EngineState, mt *ManifestTarget) {
+				mt.State.AddCompletedBuild(completedBuild)
+				mt.State.RuntimeState = NewK8sRuntimeState(mt.Manifest.Name, Pod{
+					PodID:  k8s.PodID("pod-a"),
+					Phase:  v1.PodFailed,
+					Status: "Error",
+				})
+			},
+			expectedState:  model.ResourceStateDegraded,
+			expectedReason: "Pod pod-a in error state: Error",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			state := newState([]model.Manifest{{Name: "fe"}})
+			mt := state.ManifestTargets["fe"]
+			tc.setup(state, mt)
+
+			actual := ResourceStateFor(*state, mt)
+			assert.Equal(t, tc.expectedState, actual.State)
+			assert.Equal(t, tc.expectedReason, actual.Reason)
+		})
+	}
+}
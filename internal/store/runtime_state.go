@@ -7,6 +7,7 @@ import (
 
 	"github.com/docker/distribution/reference"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/windmilleng/tilt/internal/container"
@@ -67,11 +68,20 @@ type K8sRuntimeState struct {
 	// In many cases, this will be a Deployment UID.
 	PodAncestorUID types.UID
 
-	Pods                           map[k8s.PodID]*Pod
-	LBs                            map[k8s.ServiceName]*url.URL
+	Pods map[k8s.PodID]*Pod
+	LBs  map[k8s.ServiceName]*url.URL
+
+	// Jobs we deployed (or that a deployed CronJob spawned), keyed by UID.
+	// When a manifest tracks jobs, it has run-to-completion semantics:
+	// status comes from the Job conditions instead of pod readiness.
+	Jobs                           map[types.UID]*K8sJob
 	DeployedUIDSet                 UIDSet                 // for the most recent successful deploy
 	DeployedPodTemplateSpecHashSet PodTemplateSpecHashSet // for the most recent successful deploy
 
+	// Object metadata as of the most recent successful deploy, used to
+	// detect out-of-band edits to the objects we applied.
+	DeployedObjects map[types.UID]DeployedObject
+
 	LastReadyOrSucceededTime time.Time
 
 	// NOTE(nick): This is a dumb hack to handle the UnresourcedYAML
@@ -96,27 +106,118 @@ func NewK8sRuntimeState(mn model.ManifestName, pods ...Pod) K8sRuntimeState {
 		IsUnresourced:                  mn == model.UnresourcedYAMLManifestName,
 		Pods:                           podMap,
 		LBs:                            make(map[k8s.ServiceName]*url.URL),
+		Jobs:                           make(map[types.UID]*K8sJob),
 		DeployedUIDSet:                 NewUIDSet(),
 		DeployedPodTemplateSpecHashSet: NewPodTemplateSpecHashSet(),
+		DeployedObjects:                make(map[types.UID]DeployedObject),
 	}
 }
 
+// DeployedObject remembers what an object looked like when we applied
+// it, so we can tell when someone else has changed it since.
+type DeployedObject struct {
+	Ref v1.ObjectReference
+
+	// Compared against the live object's generation when the kind
+	// tracks one, falling back to resource version otherwise.
+	Generation      int64
+	ResourceVersion string
+}
+
+// The terminal state of a Job we're tracking, read off its conditions.
+type K8sJob struct {
+	Name      string
+	Succeeded bool
+	Failed    bool
+
+	// The message from the Failed condition, if any.
+	Message string
+}
+
 func (s K8sRuntimeState) RuntimeStatusError() error {
 	status := s.RuntimeStatus()
 	if status != model.RuntimeStatusError {
 		return nil
 	}
+	for _, j := range s.Jobs {
+		if j.Failed {
+			return fmt.Errorf("Job %s failed: %s", j.Name, j.Message)
+		}
+	}
+	if s.TracksEveryPod() {
+		for _, pod := range s.PodList() {
+			if podRuntimeStatus(pod) == model.RuntimeStatusError {
+				return fmt.Errorf("Pod %s in error state: %s", pod.PodID, pod.Status)
+			}
+		}
+	}
 	pod := s.MostRecentPod()
 	return fmt.Errorf("Pod %s in error state: %s", pod.PodID, pod.Status)
 }
 
+// HasDeployedKind reports whether the most recent successful deploy
+// included an object of one of the given kinds.
+func (s K8sRuntimeState) HasDeployedKind(kinds ...string) bool {
+	for _, obj := range s.DeployedObjects {
+		for _, k := range kinds {
+			if obj.Ref.Kind == k {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Deployments march pods forward, so the newest pod is the one worth
+// watching. StatefulSets replace pods in order behind the newest one and
+// DaemonSets run a pod per node, so for those every tracked pod counts.
+func (s K8sRuntimeState) TracksEveryPod() bool {
+	return s.HasDeployedKind("StatefulSet", "DaemonSet")
+}
+
 func (s K8sRuntimeState) RuntimeStatus() model.RuntimeStatus {
 	if s.IsUnresourced {
 		return model.RuntimeStatusOK
 	}
 
-	pod := s.MostRecentPod()
+	// Jobs run to completion: the Job conditions are authoritative, so a
+	// retried-then-succeeded Job reads as OK even though an attempt pod died.
+	if len(s.Jobs) > 0 {
+		allSucceeded := true
+		for _, j := range s.Jobs {
+			if j.Failed {
+				return model.RuntimeStatusError
+			}
+			if !j.Succeeded {
+				allSucceeded = false
+			}
+		}
+		if allSucceeded {
+			return model.RuntimeStatusOK
+		}
+		return model.RuntimeStatusPending
+	}
+
+	if s.TracksEveryPod() {
+		if s.PodLen() == 0 {
+			return model.RuntimeStatusPending
+		}
+		ret := model.RuntimeStatusOK
+		for _, pod := range s.PodList() {
+			switch podRuntimeStatus(pod) {
+			case model.RuntimeStatusError:
+				return model.RuntimeStatusError
+			case model.RuntimeStatusPending:
+				ret = model.RuntimeStatusPending
+			}
+		}
+		return ret
+	}
 
+	return podRuntimeStatus(s.MostRecentPod())
+}
+
+func podRuntimeStatus(pod Pod) model.RuntimeStatus {
 	switch pod.Phase {
 	case v1.PodRunning:
 		if pod.AllContainersReady() {
@@ -182,6 +283,25 @@ func (s K8sRuntimeState) MostRecentPod() Pod {
 	return bestPod
 }
 
+// Like MostRecentPod, but only considers pods whose containers are all
+// ready. Used for blue/green-style endpoint switching.
+func (s K8sRuntimeState) MostRecentReadyPod() Pod {
+	bestPod := Pod{}
+	found := false
+
+	for _, v := range s.Pods {
+		if v.Deleting || !v.AllContainersReady() {
+			continue
+		}
+		if !found || v.isAfter(bestPod) {
+			bestPod = *v
+			found = true
+		}
+	}
+
+	return bestPod
+}
+
 func (s K8sRuntimeState) HasOKPodTemplateSpecHash(pod *v1.Pod) bool {
 	// if it doesn't have a label, just let it through - maybe it's from a CRD w/ no pod template spec
 	hash, ok := pod.Labels[k8s.TiltPodTemplateHashLabel]
@@ -220,9 +340,113 @@ type Pod struct {
 
 	Conditions []v1.PodCondition
 
+	// Liveness/readiness probe failures the kubelet has reported for this
+	// pod, so a failing probe shows up as more than an eternally-pending
+	// resource.
+	ProbeWarnings []ProbeWarning
+
+	// The most recent usage sample from metrics-server. Zero when the
+	// cluster doesn't serve pod metrics.
+	Usage PodUsage
+
+	// Resource limits summed across the pod's container specs, so we can
+	// warn when usage approaches them. Zero when no container sets one.
+	CPULimit    resource.Quantity
+	MemoryLimit resource.Quantity
+
 	SpanID model.LogSpanID
 }
 
+type PodUsage struct {
+	CPU    resource.Quantity
+	Memory resource.Quantity
+}
+
+func (u PodUsage) Empty() bool {
+	return u.CPU.IsZero() && u.Memory.IsZero()
+}
+
+// Warn when a pod's memory usage reaches this percentage of its limit:
+// it's about to be OOM-killed.
+const MemoryUsageWarningPct = 90
+
+// How much of the pod's memory limit is in use, as a percentage.
+// Zero when there's no limit or no usage sample.
+func (p Pod) MemoryPctOfLimit() int {
+	if p.MemoryLimit.IsZero() || p.Usage.Memory.IsZero() {
+		return 0
+	}
+	return int(p.Usage.Memory.Value() * 100 / p.MemoryLimit.Value())
+}
+
+// A probe failure reported by the kubelet, aggregated per container and
+// probe type.
+type ProbeWarning struct {
+	// "Liveness", "Readiness", or "Startup".
+	Probe         string
+	ContainerName container.Name
+
+	// How many times the probe has failed, per the event count.
+	Count int
+
+	// The output of the most recent failure.
+	LastOutput string
+}
+
+func (w ProbeWarning) String() string {
+	s := fmt.Sprintf("%s probe failed (x%d)", w.Probe, w.Count)
+	if w.ContainerName != "" {
+		s = fmt.Sprintf("%s [%s]", s, w.ContainerName)
+	}
+	if w.LastOutput != "" {
+		s = fmt.Sprintf("%s: %s", s, w.LastOutput)
+	}
+	return s
+}
+
+// Record a new probe failure, replacing any previous warning for the same
+// container and probe type.
+//
+// Builds a fresh slice instead of editing elements in place, because view
+// snapshots share the old one.
+func (p *Pod) RecordProbeWarning(w ProbeWarning) {
+	warnings := make([]ProbeWarning, 0, len(p.ProbeWarnings)+1)
+	for _, existing := range p.ProbeWarnings {
+		if existing.Probe == w.Probe && existing.ContainerName == w.ContainerName {
+			if w.Count <= existing.Count {
+				w.Count = existing.Count + 1
+			}
+			continue
+		}
+		warnings = append(warnings, existing)
+	}
+	p.ProbeWarnings = append(warnings, w)
+}
+
+// Drop warnings for containers that have become ready: their probes are
+// passing again.
+func (p *Pod) PruneProbeWarnings() {
+	if len(p.ProbeWarnings) == 0 {
+		return
+	}
+
+	ready := make(map[container.Name]bool)
+	for _, c := range p.Containers {
+		if c.Ready {
+			ready[c.Name] = true
+		}
+	}
+
+	warnings := make([]ProbeWarning, 0, len(p.ProbeWarnings))
+	for _, w := range p.ProbeWarnings {
+		if ready[w.ContainerName] {
+			continue
+		}
+		warnings = append(warnings, w)
+	}
+	p.ProbeWarnings = warnings
+}
+
 func (p Pod) AllContainers() []Container {
 	result := []Container{}
 	result = append(result, p.InitContainers...)
@@ -230,6 +454,19 @@ func (p Pod) AllContainers() []Container {
 	return result
 }
 
+// The containers the user's resource actually runs, i.e. everything but
+// mesh-injected sidecar proxies.
+func (p Pod) AppContainers() []Container {
+	result := []Container{}
+	for _, c := range p.Containers {
+		if c.MeshInjected {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
 type Container struct {
 	Name     container.Name
 	ID       container.ID
@@ -239,6 +476,11 @@ type Container struct {
 	ImageRef reference.Named
 	Restarts int
 	Status   model.RuntimeStatus
+
+	// True if a service mesh injected this container (e.g., istio-proxy).
+	// Injected containers count for readiness, but aren't candidates for
+	// live_update, log streaming, or exec.
+	MeshInjected bool
 }
 
 func (c Container) Empty() bool {
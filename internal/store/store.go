@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -15,6 +16,15 @@ import (
 // Allow actions to batch together a bit.
 const actionBatchWindow = time.Millisecond
 
+// How many recently-dispatched actions we remember, so crash reports can
+// show what the engine was doing when it died.
+const maxActionHistory = 50
+
+// Reducers that take longer than this to handle a single action get
+// logged, so we can find the ones that make the HUD stutter in large
+// clusters.
+const reducerTimeBudget = 100 * time.Millisecond
+
 // Read-only store
 type RStore interface {
 	Dispatch(action Action)
@@ -37,6 +47,9 @@ type Store struct {
 	reduce      Reducer
 	logActions  bool
 
+	actionHistory   []string
+	actionHistoryMu sync.Mutex
+
 	// TODO(nick): Define Subscribers and Reducers.
 	// The actionChan is an intermediate representation to make the transition easier.
 }
@@ -120,10 +133,29 @@ func (s *Store) UnlockMutableState() {
 }
 
 func (s *Store) Dispatch(action Action) {
+	s.recordAction(action)
 	s.actionQueue.add(action)
 	go s.drainActions()
 }
 
+func (s *Store) recordAction(action Action) {
+	s.actionHistoryMu.Lock()
+	defer s.actionHistoryMu.Unlock()
+
+	entry := fmt.Sprintf("%s %T", time.Now().Format("15:04:05.000"), action)
+	s.actionHistory = append(s.actionHistory, entry)
+	if len(s.actionHistory) > maxActionHistory {
+		s.actionHistory = s.actionHistory[len(s.actionHistory)-maxActionHistory:]
+	}
+}
+
+// The most recently dispatched actions, oldest first.
+func (s *Store) ActionHistory() []string {
+	s.actionHistoryMu.Lock()
+	defer s.actionHistoryMu.Unlock()
+	return append([]string{}, s.actionHistory...)
+}
+
 func (s *Store) Close() {
 	close(s.actionCh)
 }
@@ -150,9 +182,16 @@ func (s *Store) Loop(ctx context.Context) error {
 					oldState = s.cheapCopyState()
 				}
 
+				reduceStart := time.Now()
 				s.reduce(ctx, s.state, action)
+				reduceElapsed := time.Since(reduceStart)
+				if reduceElapsed > reducerTimeBudget {
+					logger.Get(ctx).Infof("WARNING: reducer took %s to handle %T (budget: %s)",
+						reduceElapsed.Truncate(time.Millisecond), action, reducerTimeBudget)
+				}
 
 				if s.logActions {
+					action := action
 					newState := s.cheapCopyState()
 					go func() {
 						diff, equal := messagediff.PrettyDiff(oldState, newState)
@@ -220,6 +259,18 @@ type Action interface {
 	Action()
 }
 
+// High-frequency actions (log appends, pod change events) can implement
+// CoalescingAction to fold into the most recently queued action, so an
+// event storm doesn't grow the queue without bound while the reducer
+// falls behind.
+type CoalescingAction interface {
+	Action
+
+	// Try to fold `next` into this queued action. Returns the merged
+	// action and true on success; false leaves both actions as-is.
+	Coalesce(next Action) (Action, bool)
+}
+
 type actionQueue struct {
 	actions []Action
 	mu      sync.Mutex
@@ -228,6 +279,18 @@ type actionQueue struct {
 func (q *actionQueue) add(action Action) {
 	q.mu.Lock()
 	defer q.mu.Unlock()
+
+	if len(q.actions) > 0 {
+		last, ok := q.actions[len(q.actions)-1].(CoalescingAction)
+		if ok {
+			merged, ok := last.Coalesce(action)
+			if ok {
+				q.actions[len(q.actions)-1] = merged
+				return
+			}
+		}
+	}
+
 	q.actions = append(q.actions, action)
 }
 
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// Guards the store's action throughput. Webview serialization works from
+// a CopyForView snapshot rather than holding the state lock, so a view
+// subscriber shouldn't slow down reducers much.
+func BenchmarkActionThroughput(b *testing.B) {
+	benchmarkActionThroughput(b, false)
+}
+
+func BenchmarkActionThroughputWithViewSnapshots(b *testing.B) {
+	benchmarkActionThroughput(b, true)
+}
+
+func benchmarkActionThroughput(b *testing.B, withSnapshots bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	st := NewStore(TestReducer, LogActionsFlag(false))
+	seedStateForBenchmark(st)
+
+	if withSnapshots {
+		st.AddSubscriber(ctx, &snapshottingSubscriber{})
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- st.Loop(ctx)
+	}()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		st.Dispatch(CompletedBuildAction{})
+	}
+	st.Dispatch(DoneAction{})
+
+	err := <-done
+	if err != nil && err != context.Canceled {
+		b.Fatalf("Loop failed unexpectedly: %v", err)
+	}
+	if st.state.CompletedBuildCount != b.N {
+		b.Fatalf("expected %d completed builds, got %d", b.N, st.state.CompletedBuildCount)
+	}
+}
+
+func seedStateForBenchmark(st *Store) {
+	state := st.LockMutableStateForTesting()
+	defer st.UnlockMutableState()
+
+	for i := 0; i < 25; i++ {
+		name := model.ManifestName(fmt.Sprintf("manifest-%d", i))
+		mt := NewManifestTarget(model.Manifest{Name: name})
+
+		runtime := NewK8sRuntimeState(name)
+		podID := k8s.PodID(fmt.Sprintf("pod-%d", i))
+		runtime.Pods[podID] = &Pod{
+			PodID:     podID,
+			StartedAt: time.Now(),
+			Status:    "Running",
+		}
+		mt.State.RuntimeState = runtime
+
+		state.UpsertManifestTarget(mt)
+	}
+}
+
+// Snapshots the state on every change, the same way the websocket
+// subscriber does before serializing the webview.
+type snapshottingSubscriber struct{}
+
+func (s *snapshottingSubscriber) OnChange(ctx context.Context, st RStore) {
+	state := st.RLockState()
+	snapshot := state.CopyForView()
+	st.RUnlockState()
+
+	// Touch the snapshot so the copy can't be optimized away.
+	if len(snapshot.ManifestTargets) != len(state.ManifestTargets) {
+		panic("snapshot dropped manifests")
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/windmilleng/tilt/pkg/logger"
 )
 
 func TestProcessActions(t *testing.T) {
@@ -55,6 +57,27 @@ func TestBroadcastActionsBatching(t *testing.T) {
 	f.WaitUntilDone()
 }
 
+func TestQueueCoalescesLogActions(t *testing.T) {
+	q := &actionQueue{}
+	q.add(NewGlobalLogAction(logger.InfoLvl, []byte("hello ")))
+	q.add(NewGlobalLogAction(logger.InfoLvl, []byte("world")))
+	q.add(NewGlobalLogAction(logger.WarnLvl, []byte("oh no")))
+
+	actions := q.drain()
+	if assert.Len(t, actions, 2) {
+		assert.Equal(t, "hello world", string(actions[0].(LogAction).Message()))
+		assert.Equal(t, "oh no", string(actions[1].(LogAction).Message()))
+	}
+}
+
+func TestQueueDoesNotCoalesceAcrossSpans(t *testing.T) {
+	q := &actionQueue{}
+	q.add(NewLogAction("fe", "span-1", logger.InfoLvl, nil, []byte("a")))
+	q.add(NewLogAction("fe", "span-2", logger.InfoLvl, nil, []byte("b")))
+
+	assert.Len(t, q.drain(), 2)
+}
+
 type fixture struct {
 	t      *testing.T
 	store  *Store
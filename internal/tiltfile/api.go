@@ -0,0 +1,33 @@
+package tiltfile
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+
+	"github.com/windmilleng/tilt/pkg/logger"
+
+	"github.com/windmilleng/tilt/internal/container"
+	"github.com/windmilleng/tilt/internal/feature"
+	"github.com/windmilleng/tilt/internal/tiltfile/k8scontext"
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+	"github.com/windmilleng/tilt/internal/tiltfile/version"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// ApiDocs enumerates every builtin a Tiltfile can call in this binary,
+// without executing anything. The tiltfile state it builds is inert:
+// only the extensions' OnStart hooks run, registering names and docs.
+func ApiDocs(tiltBuild model.TiltBuild) ([]starkit.BuiltinDoc, error) {
+	wd, _ := os.Getwd()
+	ctx := logger.WithLogger(context.Background(), logger.NewLogger(logger.NoneLvl, ioutil.Discard))
+	s := newTiltfileState(
+		ctx,
+		nil,
+		model.WebHost(""),
+		k8scontext.Extension{},
+		version.NewExtension(tiltBuild),
+		container.Registry{},
+		feature.FromDefaults(feature.MainDefaults))
+	return starkit.APIDocs(s.extensions(model.UserConfigState{}, wd)...)
+}
@@ -89,9 +89,13 @@ func (idx *buildIndex) findBuilderForConsumedImage(ref reference.Named) *dockerI
 	return nil
 }
 
-func (idx *buildIndex) assertAllMatched() error {
+func (idx *buildIndex) assertAllMatched(us model.UpdateSettings) error {
 	for _, image := range idx.images {
 		if !image.matched {
+			if us.SuppressesUnusedImageWarning(container.FamiliarString(image.configurationRef)) ||
+				us.SuppressesUnusedImageWarning(image.configurationRef.RefFamiliarName()) {
+				continue
+			}
 			bagSizes := []int{2, 3, 4}
 			cm := closestmatch.New(idx.consumedImageNames, bagSizes)
 			matchLines := []string{}
@@ -0,0 +1,87 @@
+package tiltfile
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+
+	"github.com/windmilleng/tilt/internal/tiltfile/value"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// A custom action registered via cmd_button, attached to its resource's
+// manifest once all resources have been assembled.
+type cmdButton struct {
+	name        string
+	resource    string
+	cmd         model.Cmd
+	inContainer bool
+}
+
+func (s *tiltfileState) cmdButton(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var resource string
+	var cmdVal starlark.Value
+	var inContainer bool
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"name", &name,
+		"resource", &resource,
+		"cmd", &cmdVal,
+		"in_container?", &inContainer,
+	); err != nil {
+		return nil, err
+	}
+
+	cmd, err := value.ValueToHostCmd(cmdVal)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("%s: name must not be empty", fn.Name())
+	}
+	if resource == "" {
+		return nil, fmt.Errorf("%s: resource must not be empty", fn.Name())
+	}
+	if cmd.Empty() {
+		return nil, fmt.Errorf("%s: cmd must not be empty", fn.Name())
+	}
+
+	for _, b := range s.cmdButtons {
+		if b.name == name && b.resource == resource {
+			return nil, fmt.Errorf("%s: button %q already defined for resource %q", fn.Name(), name, resource)
+		}
+	}
+
+	s.cmdButtons = append(s.cmdButtons, cmdButton{
+		name:        name,
+		resource:    resource,
+		cmd:         cmd,
+		inContainer: inContainer,
+	})
+
+	return starlark.None, nil
+}
+
+// Attach registered buttons to their manifests. Runs after assembly, so
+// a button can reference any resource type (k8s, compose, or local).
+func (s *tiltfileState) applyCmdButtons(manifests []model.Manifest) ([]model.Manifest, error) {
+	byName := make(map[model.ManifestName]int, len(manifests))
+	for i, m := range manifests {
+		byName[m.Name] = i
+	}
+
+	for _, b := range s.cmdButtons {
+		i, ok := byName[model.ManifestName(b.resource)]
+		if !ok {
+			return nil, fmt.Errorf("%s %q: no resource named %q", cmdButtonN, b.name, b.resource)
+		}
+		manifests[i].Buttons = append(manifests[i].Buttons, model.CmdButton{
+			Name:        b.name,
+			Cmd:         b.cmd,
+			InContainer: b.inContainer,
+		})
+	}
+
+	return manifests, nil
+}
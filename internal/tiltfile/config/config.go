@@ -18,6 +18,11 @@ type Settings struct {
 	enabledResources []model.ManifestName
 	configDef        ConfigDef
 
+	// if enable_resources_for_changed_files was called, the base git ref
+	// to diff against and the repo to diff in
+	changedFilesBase string
+	changedFilesRepo string
+
 	configParseCalled bool
 	userConfigState   model.UserConfigState
 
@@ -62,6 +67,7 @@ func (e *Extension) OnStart(env *starkit.Environment) error {
 		f    starkit.Function
 	}{
 		{"config.set_enabled_resources", setEnabledResources},
+		{"config.enable_resources_for_changed_files", enableResourcesForChangedFiles},
 		{"config.parse", e.parse},
 		{"config.define_string_list", configSettingDefinitionBuiltin(func() configValue {
 			return &stringList{}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"os"
+	"os/exec"
 	"strings"
 	"testing"
 
@@ -79,6 +81,80 @@ config.parse()`
 	}
 }
 
+func TestEnableResourcesForChangedFiles(t *testing.T) {
+	f := NewFixture(t, model.UserConfigState{})
+	defer f.TearDown()
+
+	gitCmd(t, f.Path(), "init", "-b", "master")
+	f.File("svc-a/main.go", "package main")
+	f.File("svc-b/main.go", "package main")
+	gitCmd(t, f.Path(), "add", ".")
+	gitCmd(t, f.Path(), "commit", "-m", "initial")
+
+	// Touch only svc-a.
+	f.File("svc-a/main.go", "package main // changed")
+
+	f.File("Tiltfile", `config.enable_resources_for_changed_files('HEAD')`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	manifests := []model.Manifest{
+		model.Manifest{Name: "a"}.WithDeployTarget(model.NewLocalTarget("a", model.Cmd{}, model.Cmd{}, []string{f.JoinPath("svc-a")}, f.Path())),
+		model.Manifest{Name: "b"}.WithDeployTarget(model.NewLocalTarget("b", model.Cmd{}, model.Cmd{}, []string{f.JoinPath("svc-b")}, f.Path())),
+		model.Manifest{Name: "c", ResourceDependencies: []model.ManifestName{"a"}}.WithDeployTarget(
+			model.NewLocalTarget("c", model.Cmd{}, model.Cmd{}, []string{f.JoinPath("svc-c")}, f.Path())),
+	}
+
+	actual, err := MustState(result).EnabledResources(manifests)
+	require.NoError(t, err)
+
+	// a's inputs changed; c depends on a; b is untouched.
+	var names []model.ManifestName
+	for _, m := range actual {
+		names = append(names, m.Name)
+	}
+	require.Equal(t, []model.ManifestName{"a", "c"}, names)
+}
+
+func TestEnableResourcesForChangedFilesNoneChanged(t *testing.T) {
+	f := NewFixture(t, model.UserConfigState{})
+	defer f.TearDown()
+
+	gitCmd(t, f.Path(), "init", "-b", "master")
+	f.File("svc-a/main.go", "package main")
+	gitCmd(t, f.Path(), "add", ".")
+	gitCmd(t, f.Path(), "commit", "-m", "initial")
+
+	f.File("Tiltfile", `config.enable_resources_for_changed_files('HEAD')`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	manifests := []model.Manifest{
+		model.Manifest{Name: "a"}.WithDeployTarget(model.NewLocalTarget("a", model.Cmd{}, model.Cmd{}, []string{f.JoinPath("svc-a")}, f.Path())),
+	}
+
+	actual, err := MustState(result).EnabledResources(manifests)
+	require.NoError(t, err)
+	require.Empty(t, actual)
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
 func TestParsePositional(t *testing.T) {
 	args := strings.Split("united states canada mexico panama haiti jamaica peru", " ")
 
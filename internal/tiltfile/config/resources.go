@@ -2,10 +2,13 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/pkg/errors"
 	"go.starlark.net/starlark"
 
+	"github.com/windmilleng/tilt/internal/git"
+	"github.com/windmilleng/tilt/internal/ospath"
 	"github.com/windmilleng/tilt/internal/sliceutils"
 	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
 	"github.com/windmilleng/tilt/internal/tiltfile/value"
@@ -43,6 +46,32 @@ func setEnabledResources(thread *starlark.Thread, fn *starlark.Builtin, args sta
 	return starlark.None, nil
 }
 
+// enableResourcesForChangedFiles narrows the session to the resources
+// whose inputs changed relative to a git base ref, plus their dependents,
+// so monorepo users only run what they're working on.
+func enableResourcesForChangedFiles(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var base string
+	repoPath := "."
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"base", &base,
+		"repo?", &repoPath)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	repo := starkit.AbsPath(thread, repoPath)
+	err = starkit.SetState(thread, func(settings Settings) Settings {
+		settings.changedFilesBase = base
+		settings.changedFilesRepo = repo
+		return settings
+	})
+	if err != nil {
+		return starlark.None, err
+	}
+
+	return starlark.None, nil
+}
+
 // for the given args and list of full manifests, figure out which manifests the user actually selected
 func (s Settings) EnabledResources(manifests []model.Manifest) ([]model.Manifest, error) {
 	// if the user called set_enabled_resources, that trumps everything
@@ -50,6 +79,10 @@ func (s Settings) EnabledResources(manifests []model.Manifest) ([]model.Manifest
 		return match(manifests, s.enabledResources)
 	}
 
+	if s.changedFilesBase != "" {
+		return s.affectedResources(manifests)
+	}
+
 	args := s.userConfigState.Args
 
 	// if the user has not called config.parse and has specified args, use those to select which resources
@@ -65,14 +98,103 @@ func (s Settings) EnabledResources(manifests []model.Manifest) ([]model.Manifest
 	return manifests, nil
 }
 
-// add `manifestToAdd` and all of its transitive deps to `result`
-func addManifestAndDeps(result map[model.ManifestName]bool, allManifestsByName map[model.ManifestName]model.Manifest, manifestToAdd model.ManifestName) {
-	if result[manifestToAdd] {
+// SelectedResources returns the set of resource names the session is
+// narrowed to (via set_enabled_resources or args), including transitive
+// resource_deps, so callers can skip assembling resources that aren't
+// selected. A nil map means all resources are enabled.
+func (s Settings) SelectedResources(names []model.ManifestName, deps map[model.ManifestName][]model.ManifestName) (map[model.ManifestName]bool, error) {
+	var requested []model.ManifestName
+	if s.enabledResources != nil {
+		requested = s.enabledResources
+	} else if args := s.userConfigState.Args; args != nil && !s.configParseCalled {
+		for _, arg := range args {
+			requested = append(requested, model.ManifestName(arg))
+		}
+	}
+
+	if len(requested) == 0 {
+		return nil, nil
+	}
+	return matchNames(names, deps, requested)
+}
+
+// affectedResources selects the manifests whose watched paths contain a
+// file changed relative to the base ref, their transitive dependents
+// (resources that list an affected resource in resource_deps), and — via
+// match — the resource_deps they need to run.
+func (s Settings) affectedResources(manifests []model.Manifest) ([]model.Manifest, error) {
+	r, err := git.NewReader(s.changedFilesRepo)
+	if err != nil {
+		return nil, errors.Wrap(err, "enable_resources_for_changed_files")
+	}
+	files, err := r.ChangedFiles(s.changedFilesBase)
+	if err != nil {
+		return nil, errors.Wrap(err, "enable_resources_for_changed_files")
+	}
+
+	absFiles := make([]string, 0, len(files))
+	for _, f := range files {
+		absFiles = append(absFiles, filepath.Join(s.changedFilesRepo, filepath.FromSlash(f)))
+	}
+
+	affected := make(map[model.ManifestName]bool)
+	for _, m := range manifests {
+		if m.DeployTarget() == nil {
+			continue
+		}
+		paths := m.LocalPaths()
+		for _, f := range absFiles {
+			if ospath.IsChildOfOne(paths, f) {
+				affected[m.Name] = true
+				break
+			}
+		}
+	}
+
+	// Pull in dependents: a resource downstream of an affected one needs
+	// to re-run too. Iterate to a fixed point to catch chains.
+	for {
+		grew := false
+		for _, m := range manifests {
+			if affected[m.Name] {
+				continue
+			}
+			for _, d := range m.ResourceDependencies {
+				if affected[d] {
+					affected[m.Name] = true
+					grew = true
+					break
+				}
+			}
+		}
+		if !grew {
+			break
+		}
+	}
+
+	var requested []model.ManifestName
+	for _, m := range manifests {
+		if affected[m.Name] {
+			requested = append(requested, m.Name)
+		}
+	}
+
+	// match() treats an empty request as "everything"; here it means
+	// nothing the user touched maps to a resource, so run nothing.
+	if len(requested) == 0 {
+		return nil, nil
+	}
+	return match(manifests, requested)
+}
+
+// add `nameToAdd` and all of its transitive deps to `result`
+func addNameAndDeps(result map[model.ManifestName]bool, deps map[model.ManifestName][]model.ManifestName, nameToAdd model.ManifestName) {
+	if result[nameToAdd] {
 		return
 	}
-	result[manifestToAdd] = true
-	for _, dep := range allManifestsByName[manifestToAdd].ResourceDependencies {
-		addManifestAndDeps(result, allManifestsByName, dep)
+	result[nameToAdd] = true
+	for _, dep := range deps[nameToAdd] {
+		addNameAndDeps(result, deps, dep)
 	}
 }
 
@@ -82,21 +204,16 @@ func match(manifests []model.Manifest, requestedManifests []model.ManifestName)
 		return manifests, nil
 	}
 
-	manifestsByName := make(map[model.ManifestName]model.Manifest)
+	names := make([]model.ManifestName, 0, len(manifests))
+	deps := make(map[model.ManifestName][]model.ManifestName, len(manifests))
 	for _, m := range manifests {
-		manifestsByName[m.Name] = m
+		names = append(names, m.Name)
+		deps[m.Name] = m.ResourceDependencies
 	}
 
-	manifestsToRun := make(map[model.ManifestName]bool)
-	var unknownNames []string
-
-	for _, m := range requestedManifests {
-		if _, ok := manifestsByName[m]; !ok {
-			unknownNames = append(unknownNames, string(m))
-			continue
-		}
-
-		addManifestAndDeps(manifestsToRun, manifestsByName, m)
+	manifestsToRun, err := matchNames(names, deps, requestedManifests)
+	if err != nil {
+		return nil, err
 	}
 
 	var result []model.Manifest
@@ -106,8 +223,30 @@ func match(manifests []model.Manifest, requestedManifests []model.ManifestName)
 		}
 	}
 
+	return result, nil
+}
+
+// matchNames returns the requested names plus their transitive deps.
+func matchNames(names []model.ManifestName, deps map[model.ManifestName][]model.ManifestName, requested []model.ManifestName) (map[model.ManifestName]bool, error) {
+	known := make(map[model.ManifestName]bool, len(names))
+	for _, n := range names {
+		known[n] = true
+	}
+
+	namesToRun := make(map[model.ManifestName]bool)
+	var unknownNames []string
+
+	for _, n := range requested {
+		if !known[n] {
+			unknownNames = append(unknownNames, string(n))
+			continue
+		}
+
+		addNameAndDeps(namesToRun, deps, n)
+	}
+
 	if len(unknownNames) > 0 {
-		unmatchedNames := unmatchedManifestNames(manifests, requestedManifests)
+		unmatchedNames := unmatchedNames(names, requested)
 
 		return nil, fmt.Errorf(`You specified some resources that could not be found: %s
 Is this a typo? Existing resources in Tiltfile: %s`,
@@ -115,19 +254,19 @@ Is this a typo? Existing resources in Tiltfile: %s`,
 			sliceutils.QuotedStringList(unmatchedNames))
 	}
 
-	return result, nil
+	return namesToRun, nil
 }
 
-func unmatchedManifestNames(manifests []model.Manifest, requestedManifests []model.ManifestName) []string {
-	requestedManifestsByName := make(map[model.ManifestName]bool)
-	for _, m := range requestedManifests {
-		requestedManifestsByName[m] = true
+func unmatchedNames(names []model.ManifestName, requested []model.ManifestName) []string {
+	requestedByName := make(map[model.ManifestName]bool)
+	for _, n := range requested {
+		requestedByName[n] = true
 	}
 
 	var ret []string
-	for _, m := range manifests {
-		if _, ok := requestedManifestsByName[m.Name]; !ok {
-			ret = append(ret, string(m.Name))
+	for _, n := range names {
+		if _, ok := requestedByName[n]; !ok {
+			ret = append(ret, string(n))
 		}
 	}
 
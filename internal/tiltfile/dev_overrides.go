@@ -0,0 +1,92 @@
+package tiltfile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.starlark.net/starlark"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/windmilleng/tilt/internal/container"
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/tiltfile/value"
+)
+
+// Opts in to Tilt's dev-friendly rewrites of the deployed YAML: every
+// Deployment is scaled to one replica, resource limits/requests are
+// stripped, and locally-built images get an IfNotPresent pull policy.
+// Resources named in exclude keep their YAML as written.
+func (s *tiltfileState) devOverridesFn(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if s.devOverridesEnabled {
+		return starlark.None, errors.New("dev overrides already enabled")
+	}
+
+	var excludeVal starlark.Sequence
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"exclude?", &excludeVal); err != nil {
+		return nil, err
+	}
+
+	exclude, err := value.SequenceToStringSlice(excludeVal)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: exclude", fn.Name())
+	}
+
+	s.devOverridesEnabled = true
+	s.devOverridesExclude = exclude
+
+	return starlark.None, nil
+}
+
+// Applies the dev_overrides() rewrites to every assembled resource that
+// hasn't opted out.
+func (s *tiltfileState) applyDevOverrides() error {
+	if !s.devOverridesEnabled {
+		return nil
+	}
+
+	excluded := make(map[string]bool, len(s.devOverridesExclude))
+	for _, name := range s.devOverridesExclude {
+		if _, ok := s.k8sByName[name]; !ok {
+			var knownResources []string
+			for known := range s.k8sByName {
+				knownResources = append(knownResources, known)
+			}
+			return fmt.Errorf("dev_overrides: unknown resource %q in exclude. known resources: %s",
+				name, strings.Join(knownResources, ", "))
+		}
+		excluded[name] = true
+	}
+
+	// Only locally-built images get their pull policy rewritten: a pull
+	// policy of Always on an image that only exists in the local docker
+	// would make the pod unstartable.
+	var localRefs []container.RefSelector
+	for _, image := range s.buildIndex.images {
+		localRefs = append(localRefs, image.configurationRef)
+	}
+
+	for _, r := range s.k8s {
+		if excluded[r.name] {
+			continue
+		}
+		for i, e := range r.entities {
+			e = k8s.ScaleDeploymentToOneReplica(e)
+
+			e, err := k8s.StripResourceRequirements(e)
+			if err != nil {
+				return errors.Wrapf(err, "dev_overrides: resource %s", r.name)
+			}
+
+			e, err = k8s.InjectImagePullPolicyMatching(e, v1.PullIfNotPresent, localRefs)
+			if err != nil {
+				return errors.Wrapf(err, "dev_overrides: resource %s", r.name)
+			}
+
+			r.entities[i] = e
+		}
+	}
+
+	return nil
+}
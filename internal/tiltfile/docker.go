@@ -11,6 +11,7 @@ import (
 
 	"github.com/windmilleng/tilt/internal/container"
 	"github.com/windmilleng/tilt/internal/dockerfile"
+	"github.com/windmilleng/tilt/internal/k8s"
 	"github.com/windmilleng/tilt/internal/ospath"
 	"github.com/windmilleng/tilt/internal/sliceutils"
 	"github.com/windmilleng/tilt/internal/tiltfile/io"
@@ -533,6 +534,129 @@ func (s *tiltfileState) defaultRegistry(thread *starlark.Thread, fn *starlark.Bu
 	return starlark.None, nil
 }
 
+// Configures a pull-through registry mirror for Docker Hub images, both
+// for base images in docker builds and for images in deployed YAML.
+func (s *tiltfileState) registryMirror(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if s.mirrorRegistry != "" {
+		return starlark.None, errors.New("registry mirror already defined")
+	}
+
+	var host string
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"host", &host); err != nil {
+		return nil, err
+	}
+
+	reg, err := container.NewRegistry(host)
+	if err != nil {
+		return starlark.None, errors.Wrapf(err, "validating registryMirror")
+	}
+
+	s.mirrorRegistry = reg.Host
+
+	return starlark.None, nil
+}
+
+// Configures how the tags of built images are named.
+//
+// The tag_template placeholders are expanded at tag time (see
+// build.tagForBuild), so the tag that's pushed is the tag that's
+// injected into the deployed YAML.
+func (s *tiltfileState) imageTagScheme(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if !s.tagStrategy.Empty() {
+		return starlark.None, errors.New("image tag scheme already defined")
+	}
+
+	var scheme, tagTemplate string
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"scheme", &scheme,
+		"tag_template?", &tagTemplate); err != nil {
+		return nil, err
+	}
+
+	switch scheme {
+	case "content-hash":
+		s.tagStrategy = model.TagStrategy{}
+	case "git-sha":
+		// Keep the content hash in the tag, so that rebuilding at the same
+		// commit still produces a distinct tag.
+		s.tagStrategy = model.TagStrategy{Template: "tilt-{gitsha}-{hash}"}
+	case "template":
+		if tagTemplate == "" {
+			return starlark.None, fmt.Errorf("image_tag_scheme: scheme 'template' requires a tag_template")
+		}
+		if !strings.Contains(tagTemplate, "{hash}") {
+			return starlark.None, fmt.Errorf("image_tag_scheme: tag_template %q must contain {hash}, "+
+				"so that each build gets a distinct tag", tagTemplate)
+		}
+		s.tagStrategy = model.TagStrategy{Template: tagTemplate}
+	default:
+		return starlark.None, fmt.Errorf("image_tag_scheme: unknown scheme %q (must be 'content-hash', 'git-sha', or 'template')", scheme)
+	}
+
+	return starlark.None, nil
+}
+
+// Registers images for Tilt to pull at startup, before any builds run,
+// so the first build doesn't stall waiting on them.
+func (s *tiltfileState) prePull(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var imagesVal starlark.Value
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"images", &imagesVal); err != nil {
+		return nil, err
+	}
+
+	images, ok := value.AsStringOrStringList(imagesVal)
+	if !ok {
+		return nil, fmt.Errorf("Argument 'images' must be string or list of strings. Actual: %T", imagesVal)
+	}
+
+	for _, image := range images {
+		_, err := container.ParseNamed(image)
+		if err != nil {
+			return nil, fmt.Errorf("Argument 'images' value %q: %v", image, err)
+		}
+		s.prePullImages = append(s.prePullImages, image)
+	}
+
+	return starlark.None, nil
+}
+
+// Rewrites Docker Hub images in deployed entities to pull through the
+// configured registry mirror.
+func (s *tiltfileState) applyRegistryMirror() error {
+	if s.mirrorRegistry == "" {
+		return nil
+	}
+
+	// Don't mirror images Tilt builds itself: they have to stay matchable
+	// so the built ref can be injected at deploy time.
+	var skip []container.RefSelector
+	for _, image := range s.buildIndex.images {
+		skip = append(skip, image.configurationRef)
+	}
+
+	for _, r := range s.k8s {
+		for i, e := range r.entities {
+			newE, _, err := k8s.InjectRegistryMirror(e, s.mirrorRegistry, skip)
+			if err != nil {
+				return err
+			}
+			r.entities[i] = newE
+		}
+	}
+
+	for i, e := range s.k8sUnresourced {
+		newE, _, err := k8s.InjectRegistryMirror(e, s.mirrorRegistry, skip)
+		if err != nil {
+			return err
+		}
+		s.k8sUnresourced[i] = newE
+	}
+
+	return nil
+}
+
 func (s *tiltfileState) dockerignoresFromPathsAndContextFilters(paths []string, ignores []string, onlys []string) []model.Dockerignore {
 	var result []model.Dockerignore
 	dupeSet := map[string]bool{}
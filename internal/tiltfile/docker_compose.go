@@ -31,8 +31,9 @@ func (dc dcResourceSet) Empty() bool { return reflect.DeepEqual(dc, dcResourceSe
 
 func (s *tiltfileState) dockerCompose(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var configPathsValue starlark.Value
+	var profilesVal starlark.Sequence
 
-	err := s.unpackArgs(fn.Name(), args, kwargs, "configPaths", &configPathsValue)
+	err := s.unpackArgs(fn.Name(), args, kwargs, "configPaths", &configPathsValue, "profiles?", &profilesVal)
 	if err != nil {
 		return nil, err
 	}
@@ -47,6 +48,15 @@ func (s *tiltfileState) dockerCompose(thread *starlark.Thread, fn *starlark.Buil
 		configPaths = append(configPaths, path)
 	}
 
+	profiles, err := value.SequenceToStringSlice(profilesVal)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: profiles", fn.Name())
+	}
+
+	// Profiles change which services `docker-compose config` reports,
+	// so activate them before parsing.
+	s.dcCli.SetProfiles(profiles)
+
 	var services []*dcService
 	tempServices, err := parseDCConfig(s.ctx, s.dcCli, configPaths)
 	services = append(services, tempServices...)
@@ -73,6 +83,9 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 	var imageVal starlark.Value
 	var triggerMode triggerMode
 	var resourceDepsVal starlark.Sequence
+	var autoRestart bool
+	autoInit := true
+	var linksVal starlark.Value
 
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
 		"name", &name,
@@ -88,6 +101,9 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 
 		"trigger_mode?", &triggerMode,
 		"resource_deps?", &resourceDepsVal,
+		"auto_restart?", &autoRestart,
+		"auto_init?", &autoInit,
+		"links?", &linksVal,
 	); err != nil {
 		return nil, err
 	}
@@ -111,7 +127,15 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 		return nil, err
 	}
 
+	links, err := parseLinks(linksVal)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %q", fn.Name(), name)
+	}
+
 	svc.TriggerMode = triggerMode
+	svc.AutoRestart = autoRestart
+	svc.AutoInit = autoInit
+	svc.Links = links
 
 	if imageRefAsStr != nil {
 		normalized, err := container.ParseNamed(*imageRefAsStr)
@@ -165,6 +189,24 @@ type dcService struct {
 
 	TriggerMode triggerMode
 
+	// services named in depends_on in the compose file
+	DependsOn []string
+
+	// env_file entries, relative to the compose file unless absolute
+	EnvFilePaths []string
+
+	// whether the service defines a healthcheck
+	HasHealthcheck bool
+
+	// restart the service when its container exits with an error (set via dc_resource)
+	AutoRestart bool
+
+	// whether the service builds/starts on `tilt up` without a manual trigger
+	AutoInit bool
+
+	// links the user attached via dc_resource
+	Links []model.Link
+
 	resourceDeps []string
 }
 
@@ -211,6 +253,11 @@ func DockerComposeConfigToService(c dockercompose.Config, name string) (dcServic
 
 		ServiceConfig:  svcConfig.RawYAML,
 		PublishedPorts: publishedPorts,
+
+		DependsOn:      svcConfig.DependsOn.ServiceNames,
+		EnvFilePaths:   svcConfig.EnvFiles,
+		HasHealthcheck: svcConfig.HealthCheck.Defined(),
+		AutoInit:       true,
 	}
 
 	if svcConfig.Image != "" {
@@ -256,20 +303,29 @@ func parseDCConfig(ctx context.Context, dcc dockercompose.DockerComposeClient, c
 func (s *tiltfileState) dcServiceToManifest(service *dcService, dcSet dcResourceSet) (manifest model.Manifest,
 	configFiles []string, err error) {
 	dcInfo := model.DockerComposeTarget{
-		ConfigPaths: dcSet.configPaths,
-		YAMLRaw:     service.ServiceConfig,
-		DfRaw:       service.DfContents,
+		ConfigPaths:    dcSet.configPaths,
+		YAMLRaw:        service.ServiceConfig,
+		DfRaw:          service.DfContents,
+		HasHealthcheck: service.HasHealthcheck,
+		AutoRestart:    service.AutoRestart,
 	}.WithDependencyIDs(service.DependencyIDs).
 		WithPublishedPorts(service.PublishedPorts).
 		WithIgnoredLocalDirectories(service.MountedLocalDirs)
 
-	um, err := starlarkTriggerModeToModel(s.triggerModeForResource(service.TriggerMode), true)
+	um, err := starlarkTriggerModeToModel(s.triggerModeForResource(service.TriggerMode), service.AutoInit)
 	if err != nil {
 		return model.Manifest{}, nil, err
 	}
 
+	// depends_on from the compose file and resource_deps from the Tiltfile
+	// gate the service the same way: the dependency has to be ready first.
+	seenDeps := make(map[string]bool)
 	var mds []model.ManifestName
-	for _, md := range service.resourceDeps {
+	for _, md := range append(append([]string{}, service.DependsOn...), service.resourceDeps...) {
+		if seenDeps[md] {
+			continue
+		}
+		seenDeps[md] = true
 		mds = append(mds, model.ManifestName(md))
 	}
 
@@ -277,11 +333,21 @@ func (s *tiltfileState) dcServiceToManifest(service *dcService, dcSet dcResource
 		Name:                 model.ManifestName(service.Name),
 		TriggerMode:          um,
 		ResourceDependencies: mds,
+		Links:                service.Links,
 	}.WithDeployTarget(dcInfo)
 
+	// env_files are read at container start, so edits to them should trigger
+	// a redeploy just like edits to the compose file itself.
+	for _, envFile := range service.EnvFilePaths {
+		if !filepath.IsAbs(envFile) && len(dcSet.configPaths) > 0 {
+			envFile = filepath.Join(filepath.Dir(dcSet.configPaths[0]), envFile)
+		}
+		configFiles = append(configFiles, envFile)
+	}
+
 	if service.DfPath == "" {
 		// DC service may not have Dockerfile -- e.g. may be just an image that we pull and run.
-		return m, nil, nil
+		return m, configFiles, nil
 	}
 
 	dcInfo = dcInfo.WithBuildPath(service.BuildContext)
@@ -307,5 +373,5 @@ func (s *tiltfileState) dcServiceToManifest(service *dcService, dcSet dcResource
 
 	m = m.WithDeployTarget(dcInfo)
 
-	return m, []string{service.DfPath}, nil
+	return m, append(configFiles, service.DfPath), nil
 }
@@ -123,6 +123,101 @@ version: '3.0'
 	}
 }
 
+func TestDependsOn(t *testing.T) {
+	f := newDCFixture(t)
+
+	output := `services:
+  app:
+    image: tilt.dev/app
+    depends_on:
+      db:
+        condition: service_healthy
+  db:
+    image: tilt.dev/db
+    healthcheck:
+      test: ["CMD", "pg_isready"]
+version: '3.2'
+`
+	servicesOutput := `db
+app
+`
+	services := f.parse(output, servicesOutput)
+	if assert.Len(t, services, 2) {
+		assert.True(t, services[0].HasHealthcheck)
+		assert.Empty(t, services[0].DependsOn)
+		assert.False(t, services[1].HasHealthcheck)
+		assert.Equal(t, []string{"db"}, services[1].DependsOn)
+	}
+}
+
+func TestDependsOnShortForm(t *testing.T) {
+	f := newDCFixture(t)
+
+	output := `services:
+  app:
+    image: tilt.dev/app
+    depends_on:
+    - db
+    - redis
+  db:
+    image: tilt.dev/db
+  redis:
+    image: tilt.dev/redis
+version: '3.0'
+`
+	servicesOutput := `db
+redis
+app
+`
+	services := f.parse(output, servicesOutput)
+	if assert.Len(t, services, 3) {
+		assert.Equal(t, []string{"db", "redis"}, services[2].DependsOn)
+	}
+}
+
+func TestHealthcheckDisabled(t *testing.T) {
+	f := newDCFixture(t)
+
+	output := `services:
+  app:
+    image: tilt.dev/app
+    healthcheck:
+      test: ["CMD", "curl", "localhost"]
+      disable: true
+version: '3.0'
+`
+	servicesOutput := `app
+`
+	services := f.parse(output, servicesOutput)
+	if assert.Len(t, services, 1) {
+		assert.False(t, services[0].HasHealthcheck)
+	}
+}
+
+func TestEnvFile(t *testing.T) {
+	f := newDCFixture(t)
+
+	output := `services:
+  app:
+    image: tilt.dev/app
+    env_file: .env
+  db:
+    image: tilt.dev/db
+    env_file:
+    - db.env
+    - secrets/db.env
+version: '3.2'
+`
+	servicesOutput := `db
+app
+`
+	services := f.parse(output, servicesOutput)
+	if assert.Len(t, services, 2) {
+		assert.Equal(t, []string{"db.env", "secrets/db.env"}, services[0].EnvFilePaths)
+		assert.Equal(t, []string{".env"}, services[1].EnvFilePaths)
+	}
+}
+
 type dcFixture struct {
 	t     *testing.T
 	ctx   context.Context
@@ -19,6 +19,12 @@ const (
 	readJSONN   = "read_json"
 	decodeJSONN = "decode_json"
 	encodeJSONN = "encode_json"
+
+	// The same encoders, namespaced as a json module for extension
+	// authors who want an API that matches modern Starlark dialects.
+	jsonEncodeN = "json.encode"
+	jsonDecodeN = "json.decode"
+	jsonIndentN = "json.indent"
 )
 
 func (Extension) OnStart(env *starkit.Environment) error {
@@ -36,6 +42,10 @@ func (Extension) OnStart(env *starkit.Environment) error {
 		{readJSONN, readJSON},
 		{decodeJSONN, decodeJSON},
 		{encodeJSONN, encodeJSON},
+
+		{jsonEncodeN, encodeJSON},
+		{jsonDecodeN, decodeJSON},
+		{jsonIndentN, indentJSON},
 	} {
 		err := env.AddBuiltin(b.name, b.f)
 		if err != nil {
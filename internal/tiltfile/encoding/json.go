@@ -89,6 +89,25 @@ func encodeJSON(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tup
 	return starlark.String(ret), nil
 }
 
+// Re-indent a JSON string without interpreting it, so documents round-trip
+// byte-for-byte regardless of what Starlark would do to the values.
+func indentJSON(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var x string
+	indent := "  "
+	prefix := ""
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "x", &x, "indent?", &indent, "prefix?", &prefix); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	err := json.Indent(&out, []byte(x), prefix, indent)
+	if err != nil {
+		return nil, errors.Wrap(err, "error indenting json")
+	}
+
+	return starlark.String(out.String()), nil
+}
+
 func starlarkToJSONString(obj starlark.Value) (string, error) {
 	v, err := convertStarlarkToStructuredData(obj)
 	if err != nil {
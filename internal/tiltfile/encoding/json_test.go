@@ -194,3 +194,32 @@ encode_json(blob('hello'))
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "unsupported type io.Blob")
 }
+
+func TestJSONModule(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+load("assert.tilt", "assert")
+
+s = json.encode({"a": 1})
+assert.equals(decode_json(s), json.decode(s))
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+}
+
+func TestJSONIndent(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+load("assert.tilt", "assert")
+
+assert.equals('{\n    "a": 1\n}', json.indent('{"a":1}', indent="    "))
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+}
@@ -8,10 +8,33 @@ import (
 
 func (s *tiltfileState) fail(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var msg string
-	err := s.unpackArgs(fn.Name(), args, kwargs, "msg", &msg)
+	var resource string
+	err := s.unpackArgs(fn.Name(), args, kwargs,
+		"msg", &msg,
+		"resource?", &resource)
 	if err != nil {
 		return nil, err
 	}
 
+	if resource != "" {
+		// Prefix with the resource name so the error reads as belonging
+		// to that resource rather than the Tiltfile as a whole.
+		return nil, fmt.Errorf("%s: %s", resource, msg)
+	}
+
 	return nil, fmt.Errorf(msg)
 }
+
+// warn logs a non-fatal warning. It lands in the Tiltfile's log span at
+// warning level, so the HUD and web UI count it alongside other
+// Tiltfile warnings.
+func (s *tiltfileState) warn(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var msg string
+	err := s.unpackArgs(fn.Name(), args, kwargs, "msg", &msg)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Warnf("%s", msg)
+	return starlark.None, nil
+}
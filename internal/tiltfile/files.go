@@ -6,9 +6,11 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"time"
 
 	"github.com/windmilleng/tilt/internal/k8s"
 	tiltfile_io "github.com/windmilleng/tilt/internal/tiltfile/io"
+	"github.com/windmilleng/tilt/internal/tiltfile/sandbox"
 	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
 	"github.com/windmilleng/tilt/internal/tiltfile/value"
 	"github.com/windmilleng/tilt/pkg/logger"
@@ -24,9 +26,11 @@ const localLogPrefix = " → "
 func (s *tiltfileState) local(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var commandValue starlark.Value
 	quiet := false
+	parallel := false
 	err := s.unpackArgs(fn.Name(), args, kwargs,
 		"command", &commandValue,
 		"quiet?", &quiet,
+		"parallel?", &parallel,
 	)
 	if err != nil {
 		return nil, err
@@ -37,15 +41,53 @@ func (s *tiltfileState) local(thread *starlark.Thread, fn *starlark.Builtin, arg
 		return nil, err
 	}
 	s.logger.Infof("local: %s", cmd)
-	out, err := s.execLocalCmd(thread, exec.Command(cmd.Argv[0], cmd.Argv[1:]...), !quiet)
+
+	source := fmt.Sprintf("local: %s", cmd)
+	run, err := s.prepLocalCmd(thread, exec.Command(cmd.Argv[0], cmd.Argv[1:]...), !quiet)
+	if err != nil {
+		return nil, err
+	}
+
+	if parallel {
+		return s.newFuture(source, func() (starlark.Value, error) {
+			out, err := run()
+			if err != nil {
+				return nil, err
+			}
+			return tiltfile_io.NewBlob(out, source), nil
+		}), nil
+	}
+
+	out, err := run()
 	if err != nil {
 		return nil, err
 	}
 
-	return tiltfile_io.NewBlob(out, fmt.Sprintf("local: %s", cmd)), nil
+	return tiltfile_io.NewBlob(out, source), nil
 }
 
 func (s *tiltfileState) execLocalCmd(t *starlark.Thread, c *exec.Cmd, logOutput bool) (string, error) {
+	run, err := s.prepLocalCmd(t, c, logOutput)
+	if err != nil {
+		return "", err
+	}
+	return run()
+}
+
+// prepLocalCmd does the bookkeeping that has to happen on the Tiltfile
+// thread — deadline and subprocess-limit checks, working dir resolution
+// — and returns a function that runs the command. The returned function
+// is safe to call from another goroutine.
+func (s *tiltfileState) prepLocalCmd(t *starlark.Thread, c *exec.Cmd, logOutput bool) (func() (string, error), error) {
+	if err := s.checkExecDeadline(); err != nil {
+		return nil, err
+	}
+	s.localCallCount++
+	if s.limits.MaxLocalCalls > 0 && s.localCallCount > s.limits.MaxLocalCalls {
+		return nil, fmt.Errorf("Tiltfile spawned more than %d local subprocesses (limit set by %s)",
+			s.limits.MaxLocalCalls, sandbox.MaxLocalEnvName)
+	}
+
 	stdout := bytes.NewBuffer(nil)
 	stderr := bytes.NewBuffer(nil)
 
@@ -60,27 +102,43 @@ func (s *tiltfileState) execLocalCmd(t *starlark.Thread, c *exec.Cmd, logOutput
 		c.Stderr = io.MultiWriter(stderr, logOutput)
 	}
 
-	err := c.Run()
-	if err != nil {
-		// If we already logged the output, we don't need to log it again.
-		if logOutput {
-			return "", fmt.Errorf("command %q failed.\nerror: %v", c.Args, err)
+	return func() (string, error) {
+		err := c.Start()
+		if err == nil {
+			// If the sandbox has a wall-clock budget, don't let a hung
+			// subprocess outlive it.
+			if deadline, ok := s.limits.Deadline(s.execStartTime); ok && !s.execStartTime.IsZero() {
+				timer := time.AfterFunc(time.Until(deadline), func() {
+					if p := c.Process; p != nil {
+						_ = p.Kill()
+					}
+				})
+				defer timer.Stop()
+			}
+			err = c.Wait()
 		}
+		if err != nil {
+			// If we already logged the output, we don't need to log it again.
+			if logOutput {
+				return "", fmt.Errorf("command %q failed.\nerror: %v", c.Args, err)
+			}
 
-		errorMessage := fmt.Sprintf("command %q failed.\nerror: %v\nstdout: %q\nstderr: %q", c.Args, err, stdout.String(), stderr.String())
-		return "", errors.New(errorMessage)
-	}
+			errorMessage := fmt.Sprintf("command %q failed.\nerror: %v\nstdout: %q\nstderr: %q", c.Args, err, stdout.String(), stderr.String())
+			return "", errors.New(errorMessage)
+		}
 
-	if stdout.Len() == 0 && stderr.Len() == 0 {
-		s.logger.Infof("%s[no output]", localLogPrefix)
-	}
+		if stdout.Len() == 0 && stderr.Len() == 0 {
+			s.logger.Infof("%s[no output]", localLogPrefix)
+		}
 
-	return stdout.String(), nil
+		return stdout.String(), nil
+	}, nil
 }
 
 func (s *tiltfileState) kustomize(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var path starlark.Value
-	err := s.unpackArgs(fn.Name(), args, kwargs, "paths", &path)
+	parallel := false
+	err := s.unpackArgs(fn.Name(), args, kwargs, "paths", &path, "parallel?", &parallel)
 	if err != nil {
 		return nil, err
 	}
@@ -98,10 +156,6 @@ func (s *tiltfileState) kustomize(thread *starlark.Thread, fn *starlark.Builtin,
 		cmd = []string{"kubectl", "kustomize", kustomizePath}
 	}
 
-	yaml, err := s.execLocalCmd(thread, exec.Command(cmd[0], cmd[1:]...), false)
-	if err != nil {
-		return nil, err
-	}
 	deps, err := kustomize.Deps(kustomizePath)
 	if err != nil {
 		return nil, fmt.Errorf("internal error: %v", err)
@@ -113,7 +167,28 @@ func (s *tiltfileState) kustomize(thread *starlark.Thread, fn *starlark.Builtin,
 		}
 	}
 
-	return tiltfile_io.NewBlob(yaml, fmt.Sprintf("kustomize: %s", kustomizePath)), nil
+	source := fmt.Sprintf("kustomize: %s", kustomizePath)
+	run, err := s.prepLocalCmd(thread, exec.Command(cmd[0], cmd[1:]...), false)
+	if err != nil {
+		return nil, err
+	}
+
+	if parallel {
+		return s.newFuture(source, func() (starlark.Value, error) {
+			yaml, err := run()
+			if err != nil {
+				return nil, err
+			}
+			return tiltfile_io.NewBlob(yaml, source), nil
+		}), nil
+	}
+
+	yaml, err := run()
+	if err != nil {
+		return nil, err
+	}
+
+	return tiltfile_io.NewBlob(yaml, source), nil
 }
 
 func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
@@ -122,12 +197,14 @@ func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args
 	var namespace string
 	var valueFilesV starlark.Value
 	var setV starlark.Value
+	parallel := false
 	err := s.unpackArgs(fn.Name(), args, kwargs,
 		"paths", &path,
 		"name?", &name,
 		"namespace?", &namespace,
 		"values?", &valueFilesV,
-		"set?", &setV)
+		"set?", &setV,
+		"parallel?", &parallel)
 	if err != nil {
 		return nil, err
 	}
@@ -205,51 +282,64 @@ func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args
 
 	s.logger.Infof("Running: %s", cmd)
 
-	stdout, err := s.execLocalCmd(thread, exec.Command(cmd[0], cmd[1:]...), false)
+	err = tiltfile_io.RecordReadFile(thread, localPath)
 	if err != nil {
 		return nil, err
 	}
 
-	err = tiltfile_io.RecordReadFile(thread, localPath)
+	source := fmt.Sprintf("helm: %s", localPath)
+	run, err := s.prepLocalCmd(thread, exec.Command(cmd[0], cmd[1:]...), false)
 	if err != nil {
 		return nil, err
 	}
 
-	yaml := filterHelmTestYAML(string(stdout))
-
-	if namespace != "" {
-		// helm template --namespace doesn't inject the namespace, nor provide
-		// YAML that defines the namespace, so we have to do both ourselves :\
-		// https://github.com/helm/helm/issues/5465
-		parsed, err := k8s.ParseYAMLFromString(yaml)
+	finish := func() (starlark.Value, error) {
+		stdout, err := run()
 		if err != nil {
 			return nil, err
 		}
 
-		var haveYAMLForNamespace bool
-		for i, e := range parsed {
-			if e.GVK().Kind == "Namespace" && e.Name() == namespace {
-				// Chart already has YAML for the --namespace passed, we don't need to insert it
-				haveYAMLForNamespace = true
-				continue
+		yaml := filterHelmTestYAML(stdout)
+
+		if namespace != "" {
+			// helm template --namespace doesn't inject the namespace, nor provide
+			// YAML that defines the namespace, so we have to do both ourselves :\
+			// https://github.com/helm/helm/issues/5465
+			parsed, err := k8s.ParseYAMLFromString(yaml)
+			if err != nil {
+				return nil, err
 			}
-			parsed[i] = e.WithNamespace(namespace)
-		}
 
-		var entities []k8s.K8sEntity
-		if !haveYAMLForNamespace {
-			// User is relying on Helm to create the namespace, which it does independent
-			// of the YAML it generates, so we need to make sure the new namespace is included
-			// in the YAML.
-			entities = []k8s.K8sEntity{k8s.NewNamespaceEntity(namespace)}
-		}
-		entities = append(entities, parsed...)
+			var haveYAMLForNamespace bool
+			for i, e := range parsed {
+				if e.GVK().Kind == "Namespace" && e.Name() == namespace {
+					// Chart already has YAML for the --namespace passed, we don't need to insert it
+					haveYAMLForNamespace = true
+					continue
+				}
+				parsed[i] = e.WithNamespace(namespace)
+			}
 
-		yaml, err = k8s.SerializeSpecYAML(entities)
-		if err != nil {
-			return nil, err
+			var entities []k8s.K8sEntity
+			if !haveYAMLForNamespace {
+				// User is relying on Helm to create the namespace, which it does independent
+				// of the YAML it generates, so we need to make sure the new namespace is included
+				// in the YAML.
+				entities = []k8s.K8sEntity{k8s.NewNamespaceEntity(namespace)}
+			}
+			entities = append(entities, parsed...)
+
+			yaml, err = k8s.SerializeSpecYAML(entities)
+			if err != nil {
+				return nil, err
+			}
 		}
+
+		return tiltfile_io.NewBlob(yaml, source), nil
 	}
 
-	return tiltfile_io.NewBlob(yaml, fmt.Sprintf("helm: %s", localPath)), nil
+	if parallel {
+		return s.newFuture(source, finish), nil
+	}
+	return finish()
 }
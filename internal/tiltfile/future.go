@@ -0,0 +1,92 @@
+package tiltfile
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+)
+
+// A future is a handle on a local subprocess still running in the
+// background, created by passing parallel=True to local(), helm(), or
+// kustomize(). Calling wait() blocks until the command finishes and
+// returns the same value the synchronous call would have.
+type future struct {
+	source string
+	done   chan struct{}
+	result starlark.Value
+	err    error
+}
+
+func newFuture(source string, run func() (starlark.Value, error)) *future {
+	f := &future{source: source, done: make(chan struct{})}
+	go func() {
+		defer close(f.done)
+		f.result, f.err = run()
+	}()
+	return f
+}
+
+func (f *future) wait() (starlark.Value, error) {
+	<-f.done
+	return f.result, f.err
+}
+
+var _ starlark.Value = &future{}
+var _ starlark.HasAttrs = &future{}
+
+func (f *future) String() string {
+	return fmt.Sprintf("future(%s)", f.source)
+}
+
+func (f *future) Type() string {
+	return "future"
+}
+
+func (f *future) Freeze() {}
+
+func (f *future) Truth() starlark.Bool {
+	return starlark.True
+}
+
+func (f *future) Hash() (uint32, error) {
+	return 0, fmt.Errorf("unhashable type: future")
+}
+
+func (f *future) Attr(name string) (starlark.Value, error) {
+	if name == "wait" {
+		return starlark.NewBuiltin("wait", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			err := starlark.UnpackArgs(fn.Name(), args, kwargs)
+			if err != nil {
+				return nil, err
+			}
+			return f.wait()
+		}), nil
+	}
+	return nil, nil
+}
+
+func (f *future) AttrNames() []string {
+	return []string{"wait"}
+}
+
+// newFuture starts run in the background and tracks the future so
+// execution doesn't finish until the command does.
+func (s *tiltfileState) newFuture(source string, run func() (starlark.Value, error)) *future {
+	f := newFuture(source, run)
+	s.pendingFutures = append(s.pendingFutures, f)
+	return f
+}
+
+// waitPendingFutures blocks until every background command has
+// finished, and returns the first failure, so that an error from a
+// parallel command fails the Tiltfile even if it was never waited on.
+func (s *tiltfileState) waitPendingFutures() error {
+	var firstErr error
+	for _, f := range s.pendingFutures {
+		_, err := f.wait()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -17,7 +17,60 @@ func NewExtension() Extension {
 }
 
 func (Extension) OnStart(env *starkit.Environment) error {
-	return env.AddBuiltin("local_git_repo", localGitRepo)
+	err := env.AddBuiltin("local_git_repo", localGitRepo)
+	if err != nil {
+		return err
+	}
+
+	err = env.AddBuiltin("git.branch", branch)
+	if err != nil {
+		return err
+	}
+	env.AddBuiltinDoc("git.branch", starkit.BuiltinDoc{
+		Signature: "git.branch(repo='.')",
+		Summary:   "The name of the checked-out branch, or '' if HEAD is detached.",
+		Args: []starkit.ArgDoc{
+			{Name: "repo", Type: "string", Doc: "Path to the repo. Defaults to the Tiltfile's directory."},
+		},
+	})
+
+	err = env.AddBuiltin("git.sha", sha)
+	if err != nil {
+		return err
+	}
+	env.AddBuiltinDoc("git.sha", starkit.BuiltinDoc{
+		Signature: "git.sha(repo='.')",
+		Summary:   "The full sha of the current HEAD commit, e.g. for tagging images.",
+		Args: []starkit.ArgDoc{
+			{Name: "repo", Type: "string", Doc: "Path to the repo. Defaults to the Tiltfile's directory."},
+		},
+	})
+
+	err = env.AddBuiltin("git.is_dirty", isDirty)
+	if err != nil {
+		return err
+	}
+	env.AddBuiltinDoc("git.is_dirty", starkit.BuiltinDoc{
+		Signature: "git.is_dirty(repo='.')",
+		Summary:   "True if any tracked file differs from HEAD, staged or not. Untracked files don't count.",
+		Args: []starkit.ArgDoc{
+			{Name: "repo", Type: "string", Doc: "Path to the repo. Defaults to the Tiltfile's directory."},
+		},
+	})
+
+	err = env.AddBuiltin("git.changed_files", changedFiles)
+	if err != nil {
+		return err
+	}
+	env.AddBuiltinDoc("git.changed_files", starkit.BuiltinDoc{
+		Signature: "git.changed_files(base, repo='.')",
+		Summary:   "The repo-relative paths that differ between the base ref and the working tree: commits since base, staged changes, and unstaged edits to tracked files.",
+		Args: []starkit.ArgDoc{
+			{Name: "base", Type: "string", Doc: "The ref to diff against, e.g. 'master' or a sha."},
+			{Name: "repo", Type: "string", Doc: "Path to the repo. Defaults to the Tiltfile's directory."},
+		},
+	})
+	return nil
 }
 
 func NewGitRepo(t *starlark.Thread, path string) (*Repo, error) {
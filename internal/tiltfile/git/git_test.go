@@ -1,6 +1,8 @@
 package git
 
 import (
+	"os"
+	"os/exec"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,6 +44,61 @@ local_git_repo('.').asdf()
 	}
 }
 
+func TestGitModule(t *testing.T) {
+	f := NewFixture(t)
+	defer f.TearDown()
+
+	f.UseRealFS()
+	gitCmd(t, f.Path(), "init", "-b", "master")
+	f.File("a.txt", "hello")
+	gitCmd(t, f.Path(), "add", ".")
+	gitCmd(t, f.Path(), "commit", "-m", "initial")
+	f.File("a.txt", "changed")
+
+	f.File("Tiltfile", `
+print(git.branch())
+print(git.sha())
+print(git.is_dirty())
+print(git.changed_files('HEAD'))
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	assert.NoError(t, err)
+	assert.Contains(t, f.PrintOutput(), "master\n")
+	assert.Contains(t, f.PrintOutput(), "True\n")
+	assert.Contains(t, f.PrintOutput(), `["a.txt"]`)
+}
+
+func TestGitModuleNotARepo(t *testing.T) {
+	f := NewFixture(t)
+	defer f.TearDown()
+
+	f.UseRealFS()
+	f.File("Tiltfile", `
+git.sha()
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "isn't a valid git repo")
+	}
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
 func NewFixture(tb testing.TB) *starkit.Fixture {
 	return starkit.NewFixture(tb, NewExtension())
 }
@@ -0,0 +1,84 @@
+package git
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+
+	gitrepo "github.com/windmilleng/tilt/internal/git"
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+	"github.com/windmilleng/tilt/internal/tiltfile/value"
+)
+
+// The git.* builtins read the .git directory directly (see
+// internal/git.Reader), so they work without a git binary on PATH and
+// don't fork a process on every Tiltfile re-execution.
+
+func branch(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	r, err := openReader(thread, fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	b, err := r.Branch()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+	return starlark.String(b), nil
+}
+
+func sha(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	r, err := openReader(thread, fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	s, err := r.HeadSHA()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+	return starlark.String(s), nil
+}
+
+func isDirty(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	r, err := openReader(thread, fn, args, kwargs)
+	if err != nil {
+		return nil, err
+	}
+	dirty, err := r.IsDirty()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+	return starlark.Bool(dirty), nil
+}
+
+func changedFiles(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var base string
+	var repoPath string = "."
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "base", &base, "repo?", &repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := gitrepo.NewReader(starkit.AbsPath(thread, repoPath))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+	files, err := r.ChangedFiles(base)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+	return value.StringSliceToList(files), nil
+}
+
+func openReader(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (*gitrepo.Reader, error) {
+	var repoPath string = "."
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "repo?", &repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := gitrepo.NewReader(starkit.AbsPath(thread, repoPath))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+	return r, nil
+}
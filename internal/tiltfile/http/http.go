@@ -0,0 +1,244 @@
+// Package http implements the starlark http module, for fetching
+// URLs from a Tiltfile without shelling out to curl.
+//
+// Responses can be cached on disk (cache_ttl), and cached copies are
+// served as an offline fallback when the network is unreachable, so a
+// Tiltfile that fetches a version manifest or remote YAML doesn't
+// re-download it on every re-execution and still loads on a plane.
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	nethttp "net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/windmilleng/wmclient/pkg/dirs"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+
+	"github.com/windmilleng/tilt/internal/tiltfile/sandbox"
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+	"github.com/windmilleng/tilt/internal/tiltfile/value"
+	"github.com/windmilleng/tilt/pkg/logger"
+)
+
+type Extension struct {
+	cacheDir string
+	client   *nethttp.Client
+}
+
+func NewExtension() Extension {
+	dir, err := dirs.GetWindmillDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return NewExtensionAt(filepath.Join(dir, "httpcache"))
+}
+
+func NewExtensionAt(cacheDir string) Extension {
+	return Extension{
+		cacheDir: cacheDir,
+		client: &nethttp.Client{
+			Timeout: 20 * time.Second,
+		},
+	}
+}
+
+func (e Extension) OnStart(env *starkit.Environment) error {
+	err := env.AddBuiltin("http.get", e.get)
+	if err != nil {
+		return err
+	}
+	env.AddBuiltinDoc("http.get", starkit.BuiltinDoc{
+		Signature: "http.get(url, headers={}, cache_ttl='')",
+		Summary:   "Fetch a URL. Returns a struct with url, status_code, and body. Responses are cached on disk for cache_ttl and served as an offline fallback.",
+		Args: []starkit.ArgDoc{
+			{Name: "url", Type: "string", Doc: "The URL to fetch."},
+			{Name: "headers", Type: "dict", Doc: "Request headers."},
+			{Name: "cache_ttl", Type: "string", Doc: "How long a cached response stays fresh, as a duration like '5m'. Empty means always re-fetch."},
+		},
+	})
+
+	err = env.AddBuiltin("http.head", e.head)
+	if err != nil {
+		return err
+	}
+	env.AddBuiltinDoc("http.head", starkit.BuiltinDoc{
+		Signature: "http.head(url, headers={}, cache_ttl='')",
+		Summary:   "Like http.get, but issues a HEAD request; the response body is empty.",
+	})
+	return nil
+}
+
+func (e Extension) get(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	return e.do(thread, fn, args, kwargs, "GET")
+}
+
+func (e Extension) head(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	return e.do(thread, fn, args, kwargs, "HEAD")
+}
+
+func (e Extension) do(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple, method string) (starlark.Value, error) {
+	var url string
+	var headersV starlark.Value
+	var cacheTTL string
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"url", &url,
+		"headers?", &headersV,
+		"cache_ttl?", &cacheTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := value.ValueToStringMap(headersV)
+	if err != nil {
+		return nil, fmt.Errorf("Argument 'headers': %v", err)
+	}
+
+	var ttl time.Duration
+	if cacheTTL != "" {
+		ttl, err = time.ParseDuration(cacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("cache_ttl must be a duration like '5m': %v", err)
+		}
+	}
+
+	key := cacheKey(method, url, headers)
+	cached, hasCached := e.readCache(key)
+
+	if hasCached && ttl > 0 && time.Since(cached.FetchedAt) < ttl {
+		return cached.value(), nil
+	}
+
+	if !sandbox.NetworkAllowed() {
+		if hasCached {
+			return cached.value(), nil
+		}
+		return nil, fmt.Errorf("cannot fetch %q: network access is disabled (%s) and there is no cached copy",
+			url, sandbox.DisableNetworkEnvName)
+	}
+
+	resp, err := e.fetch(method, url, headers)
+	if err != nil {
+		// Offline fallback: a stale cached copy beats failing the load.
+		if hasCached {
+			ctx, ctxErr := starkit.ContextFromThread(thread)
+			if ctxErr == nil && ctx.Value(logger.LoggerContextKey) != nil {
+				logger.Get(ctx).Infof("%s %s failed (%v); using cached copy from %s",
+					method, url, err, cached.FetchedAt.Format(time.RFC3339))
+			}
+			return cached.value(), nil
+		}
+		return nil, err
+	}
+
+	if resp.StatusCode == nethttp.StatusOK {
+		e.writeCache(key, resp)
+	}
+	return resp.value(), nil
+}
+
+func (e Extension) fetch(method string, url string, headers map[string]string) (cachedResponse, error) {
+	req, err := nethttp.NewRequest(method, url, nil)
+	if err != nil {
+		return cachedResponse{}, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return cachedResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return cachedResponse{}, err
+	}
+
+	return cachedResponse{
+		URL:        url,
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		FetchedAt:  time.Now(),
+	}, nil
+}
+
+type cachedResponse struct {
+	URL        string    `json:"url"`
+	StatusCode int       `json:"status_code"`
+	Body       string    `json:"body"`
+	FetchedAt  time.Time `json:"fetched_at"`
+}
+
+func (r cachedResponse) value() starlark.Value {
+	return starlarkstruct.FromStringDict(starlark.String("http.response"), starlark.StringDict{
+		"url":         starlark.String(r.URL),
+		"status_code": starlark.MakeInt(r.StatusCode),
+		"body":        starlark.String(r.Body),
+	})
+}
+
+func cacheKey(method string, url string, headers map[string]string) string {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b := strings.Builder{}
+	b.WriteString(method)
+	b.WriteString(" ")
+	b.WriteString(url)
+	for _, k := range keys {
+		b.WriteString("\n")
+		b.WriteString(k)
+		b.WriteString(": ")
+		b.WriteString(headers[k])
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func (e Extension) cachePath(key string) string {
+	return filepath.Join(e.cacheDir, key+".json")
+}
+
+func (e Extension) readCache(key string) (cachedResponse, bool) {
+	contents, err := ioutil.ReadFile(e.cachePath(key))
+	if err != nil {
+		return cachedResponse{}, false
+	}
+	var r cachedResponse
+	err = json.Unmarshal(contents, &r)
+	if err != nil {
+		return cachedResponse{}, false
+	}
+	return r, true
+}
+
+// Cache writes are best-effort; a failure just means a re-download later.
+func (e Extension) writeCache(key string, r cachedResponse) {
+	err := os.MkdirAll(e.cacheDir, os.FileMode(0700))
+	if err != nil {
+		return
+	}
+	contents, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+	_ = ioutil.WriteFile(e.cachePath(key), contents, os.FileMode(0600))
+}
+
+var _ starkit.Extension = Extension{}
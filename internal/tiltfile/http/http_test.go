@@ -0,0 +1,124 @@
+package http
+
+import (
+	"fmt"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+)
+
+func TestGet(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("Tiltfile", fmt.Sprintf(`
+resp = http.get('%s/manifest.yaml')
+resp.status_code == 200 or fail('got %%d' %% resp.status_code)
+resp.body == 'hello 0' or fail('got %%s' %% resp.body)
+`, f.server.URL))
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.requestCount)
+}
+
+func TestGetCacheTTL(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("Tiltfile", fmt.Sprintf(`
+resp = http.get('%s/manifest.yaml', cache_ttl='5m')
+resp.body == 'hello 0' or fail('got %%s' %% resp.body)
+`, f.server.URL))
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	_, err = f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	// Second execution is served from the on-disk cache.
+	assert.Equal(t, 1, f.requestCount)
+}
+
+func TestGetOfflineFallback(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	url := f.server.URL
+	f.File("Tiltfile", fmt.Sprintf(`
+resp = http.get('%s/manifest.yaml')
+resp.body == 'hello 0' or fail('got %%s' %% resp.body)
+`, url))
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	// Take the server down; the cached copy should still satisfy the
+	// request even without a cache_ttl.
+	f.server.Close()
+	_, err = f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+}
+
+func TestGetNoCacheNoNetwork(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	url := f.server.URL
+	f.server.Close()
+	f.File("Tiltfile", fmt.Sprintf(`
+http.get('%s/manifest.yaml')
+`, url))
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+}
+
+func TestHead(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.File("Tiltfile", fmt.Sprintf(`
+resp = http.head('%s/manifest.yaml')
+resp.status_code == 200 or fail('got %%d' %% resp.status_code)
+resp.body == '' or fail('got %%s' %% resp.body)
+`, f.server.URL))
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+}
+
+type fixture struct {
+	*starkit.Fixture
+	server       *httptest.Server
+	cacheDir     string
+	requestCount int
+}
+
+func newFixture(t *testing.T) *fixture {
+	f := &fixture{}
+	f.server = httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		fmt.Fprintf(w, "hello %d", f.requestCount)
+		f.requestCount++
+	}))
+
+	cacheDir, err := ioutil.TempDir("", "httpcache")
+	require.NoError(t, err)
+	f.cacheDir = cacheDir
+
+	f.Fixture = starkit.NewFixture(t, NewExtensionAt(cacheDir))
+	return f
+}
+
+func (f *fixture) TearDown() {
+	f.server.Close()
+	_ = os.RemoveAll(f.cacheDir)
+	f.Fixture.TearDown()
+}
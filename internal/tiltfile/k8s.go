@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/docker/distribution/reference"
 	"github.com/pkg/errors"
@@ -50,8 +51,29 @@ type k8sResource struct {
 	dependencyIDs []model.TargetID
 
 	triggerMode triggerMode
+	autoInit    bool
+
+	links []model.Link
+
+	// how long pods may stay unready after a deploy before the resource
+	// is marked degraded; zero means wait forever
+	readinessTimeout time.Duration
+
+	deployStrategy model.DeployStrategy
 
 	resourceDeps []string
+
+	// if non-empty, the kube context to deploy this resource to, instead of
+	// the one Tilt started against
+	kubeContext string
+
+	// if non-empty, overrides the command of the workload's primary
+	// containers in the deployed YAML
+	entrypoint model.Cmd
+
+	// overrides the args of the workload's primary containers in the
+	// deployed YAML
+	containerArgs model.OverrideArgs
 }
 
 const deprecatedResourceAssemblyV1Warning = "This Tiltfile is using k8s resource assembly version 1, which has been " +
@@ -64,8 +86,15 @@ type k8sResourceOptions struct {
 	portForwards      []model.PortForward
 	extraPodSelectors []labels.Selector
 	triggerMode       triggerMode
+	autoInit          bool
+	links             []model.Link
+	readinessTimeout  time.Duration
+	deployStrategy    model.DeployStrategy
 	tiltfilePosition  syntax.Position
 	resourceDeps      []string
+	kubeContext       string
+	entrypoint        model.Cmd
+	containerArgs     model.OverrideArgs
 }
 
 func (r *k8sResource) addRefSelector(selector container.RefSelector) {
@@ -122,6 +151,7 @@ func (s *tiltfileState) k8sYaml(thread *starlark.Thread, fn *starlark.Builtin, a
 
 func (s *tiltfileState) extractSecrets() model.SecretSet {
 	result := model.SecretSet{}
+	result.AddAll(s.secrets)
 	for _, e := range s.k8sUnresourced {
 		secrets := s.maybeExtractSecrets(e)
 		result.AddAll(secrets)
@@ -360,7 +390,14 @@ func (s *tiltfileState) k8sResourceV2(thread *starlark.Thread, fn *starlark.Buil
 	var portForwardsVal starlark.Value
 	var extraPodSelectorsVal starlark.Value
 	var triggerMode triggerMode
+	autoInit := true
 	var resourceDepsVal starlark.Sequence
+	var kubeContext string
+	var linksVal starlark.Value
+	var readinessTimeoutStr string
+	var deployStrategyStr string
+	var entrypointVal starlark.Value
+	var containerArgsVal starlark.Sequence
 
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
 		"workload", &workload,
@@ -369,6 +406,13 @@ func (s *tiltfileState) k8sResourceV2(thread *starlark.Thread, fn *starlark.Buil
 		"extra_pod_selectors?", &extraPodSelectorsVal,
 		"trigger_mode?", &triggerMode,
 		"resource_deps?", &resourceDepsVal,
+		"context?", &kubeContext,
+		"auto_init?", &autoInit,
+		"links?", &linksVal,
+		"readiness_timeout?", &readinessTimeoutStr,
+		"deploy_strategy?", &deployStrategyStr,
+		"entrypoint?", &entrypointVal,
+		"args?", &containerArgsVal,
 	); err != nil {
 		return nil, err
 	}
@@ -396,13 +440,55 @@ func (s *tiltfileState) k8sResourceV2(thread *starlark.Thread, fn *starlark.Buil
 		return nil, errors.Wrapf(err, "%s: resource_deps", fn.Name())
 	}
 
+	links, err := parseLinks(linksVal)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %q", fn.Name(), workload)
+	}
+
+	var readinessTimeout time.Duration
+	if readinessTimeoutStr != "" {
+		readinessTimeout, err = time.ParseDuration(readinessTimeoutStr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s %q: readiness_timeout", fn.Name(), workload)
+		}
+	}
+
+	deployStrategy := model.DeployStrategy(deployStrategyStr)
+	switch deployStrategy {
+	case model.DeployStrategyDefault, model.DeployStrategyBlueGreen:
+	default:
+		return nil, fmt.Errorf("%s %q: unknown deploy_strategy %q (expected %q)",
+			fn.Name(), workload, deployStrategyStr, model.DeployStrategyBlueGreen)
+	}
+
+	entrypoint, err := value.ValueToUnixCmd(entrypointVal)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %q: entrypoint", fn.Name(), workload)
+	}
+
+	var containerArgs model.OverrideArgs
+	if containerArgsVal != nil {
+		args, err := value.SequenceToStringSlice(containerArgsVal)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s %q: args", fn.Name(), workload)
+		}
+		containerArgs = model.OverrideArgs{ShouldOverride: true, Args: args}
+	}
+
 	s.k8sResourceOptions[workload] = k8sResourceOptions{
 		newName:           newName,
 		portForwards:      portForwards,
 		extraPodSelectors: extraPodSelectors,
 		tiltfilePosition:  thread.CallFrame(1).Pos,
 		triggerMode:       triggerMode,
+		autoInit:          autoInit,
+		links:             links,
+		readinessTimeout:  readinessTimeout,
+		deployStrategy:    deployStrategy,
 		resourceDeps:      resourceDeps,
+		kubeContext:       kubeContext,
+		entrypoint:        entrypoint,
+		containerArgs:     containerArgs,
 	}
 
 	return starlark.None, nil
@@ -658,6 +744,7 @@ func (s *tiltfileState) makeK8sResource(name string) (*k8sResource, error) {
 	r := &k8sResource{
 		name:        name,
 		imageRefMap: make(map[string]int),
+		autoInit:    true,
 	}
 	s.k8s = append(s.k8s, r)
 	s.k8sByName[name] = r
@@ -101,6 +101,24 @@ func (s State) IsAllowed() bool {
 	return false
 }
 
+// Whether we're allowed to deploy to the given context. The context Tilt
+// started against follows the usual IsAllowed() rules; any other context
+// must have been explicitly allowed, since we can't detect whether it's a
+// local cluster without connecting to it.
+func (s State) IsAllowedContext(ctx k8s.KubeContext) bool {
+	if ctx == s.context {
+		return s.IsAllowed()
+	}
+
+	for _, c := range s.allowed {
+		if c == ctx {
+			return true
+		}
+	}
+
+	return false
+}
+
 func MustState(model starkit.Model) State {
 	state, err := GetState(model)
 	if err != nil {
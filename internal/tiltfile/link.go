@@ -0,0 +1,83 @@
+package tiltfile
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.starlark.net/starlark"
+
+	"github.com/windmilleng/tilt/internal/tiltfile/value"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// A link() value, passed to a resource's links= parameter.
+type link struct {
+	model.Link
+}
+
+func (l link) String() string {
+	return fmt.Sprintf("link(%q)", l.URL)
+}
+
+func (l link) Type() string {
+	return "link"
+}
+
+func (l link) Freeze() {
+	// noop
+}
+
+func (l link) Truth() starlark.Bool {
+	return l.URL != ""
+}
+
+func (l link) Hash() (uint32, error) {
+	return starlark.String(l.URL).Hash()
+}
+
+var _ starlark.Value = link{}
+
+func (s *tiltfileState) link(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var url string
+	var healthCheckURL string
+	expectedStatus := http.StatusOK
+	var required bool
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"url", &url,
+		"health_check_url?", &healthCheckURL,
+		"health_check_expected_status?", &expectedStatus,
+		"required?", &required,
+	); err != nil {
+		return nil, err
+	}
+
+	if url == "" {
+		return nil, fmt.Errorf("%s: url must not be empty", fn.Name())
+	}
+	if healthCheckURL == "" && required {
+		return nil, fmt.Errorf("%s: required=True needs a health_check_url", fn.Name())
+	}
+
+	return link{model.Link{
+		URL:                       url,
+		HealthCheckURL:            healthCheckURL,
+		HealthCheckExpectedStatus: expectedStatus,
+		RequiredForReadiness:      required,
+	}}, nil
+}
+
+// Parse a links= value: a string, a link(), or a sequence of either.
+func parseLinks(v starlark.Value) ([]model.Link, error) {
+	var links []model.Link
+	for _, val := range value.ValueOrSequenceToSlice(v) {
+		switch val := val.(type) {
+		case starlark.String:
+			links = append(links, model.Link{URL: string(val)})
+		case link:
+			links = append(links, val.Link)
+		default:
+			return nil, fmt.Errorf("links must be a string, a link, or a sequence of those; found a %s", val.Type())
+		}
+	}
+	return links, nil
+}
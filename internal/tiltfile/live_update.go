@@ -49,6 +49,7 @@ func (l liveUpdateFallBackOnStep) declarationPos() string { return l.position.St
 
 type liveUpdateSyncStep struct {
 	localPath, remotePath string
+	runAs                 string
 	position              syntax.Position
 }
 
@@ -64,7 +65,7 @@ func (l liveUpdateSyncStep) Truth() starlark.Bool {
 	return len(l.localPath) > 0 || len(l.remotePath) > 0
 }
 func (l liveUpdateSyncStep) Hash() (uint32, error) {
-	return starlark.Tuple{starlark.String(l.localPath), starlark.String(l.remotePath)}.Hash()
+	return starlark.Tuple{starlark.String(l.localPath), starlark.String(l.remotePath), starlark.String(l.runAs)}.Hash()
 }
 func (l liveUpdateSyncStep) liveUpdateStep()        {}
 func (l liveUpdateSyncStep) declarationPos() string { return l.position.String() }
@@ -145,14 +146,15 @@ func (s *tiltfileState) liveUpdateFallBackOn(thread *starlark.Thread, fn *starla
 }
 
 func (s *tiltfileState) liveUpdateSync(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
-	var localPath, remotePath string
-	if err := s.unpackArgs(fn.Name(), args, kwargs, "local_path", &localPath, "remote_path", &remotePath); err != nil {
+	var localPath, remotePath, runAs string
+	if err := s.unpackArgs(fn.Name(), args, kwargs, "local_path", &localPath, "remote_path", &remotePath, "run_as?", &runAs); err != nil {
 		return nil, err
 	}
 
 	ret := liveUpdateSyncStep{
 		localPath:  starkit.AbsPath(thread, localPath),
 		remotePath: remotePath,
+		runAs:      runAs,
 		position:   thread.CallFrame(1).Pos,
 	}
 	s.recordLiveUpdateStep(ret)
@@ -214,7 +216,12 @@ func (s *tiltfileState) liveUpdateStepToModel(t *starlark.Thread, l liveUpdateSt
 		if !path.IsAbs(x.remotePath) {
 			return nil, fmt.Errorf("sync destination '%s' (%s) is not absolute", x.remotePath, x.position.String())
 		}
-		return model.LiveUpdateSyncStep{Source: x.localPath, Dest: x.remotePath}, nil
+		if x.runAs != "" {
+			if _, _, err := model.ParseRunAs(x.runAs); err != nil {
+				return nil, fmt.Errorf("sync run_as (%s): %v", x.position.String(), err)
+			}
+		}
+		return model.LiveUpdateSyncStep{Source: x.localPath, Dest: x.remotePath, RunAs: x.runAs}, nil
 	case liveUpdateRunStep:
 		return model.LiveUpdateRunStep{
 			Command: x.command,
@@ -49,6 +49,46 @@ docker_build('gcr.io/foo', 'foo',
 	f.loadErrString("sync destination", "'baz'", "is not absolute")
 }
 
+func TestLiveUpdateSyncRunAs(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync('foo', '/baz', run_as='1000:2000'),
+  ]
+)`)
+	f.load("foo")
+
+	lu := model.LiveUpdate{
+		Steps: []model.LiveUpdateStep{
+			model.LiveUpdateSyncStep{Source: f.JoinPath("foo"), Dest: "/baz", RunAs: "1000:2000"},
+		},
+		BaseDir: f.Path(),
+	}
+	f.assertNextManifest("foo", db(image("gcr.io/foo"), lu))
+}
+
+func TestLiveUpdateSyncBadRunAs(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync('foo', '/baz', run_as='tilt-user'),
+  ]
+)`)
+	f.loadErrString("sync run_as", "run_as must be of the form 'uid' or 'uid:gid'", `"tilt-user"`)
+}
+
 func TestLiveUpdateRunBeforeSync(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -23,6 +23,7 @@ type localResource struct {
 	repos        []model.LocalGitRepo
 	resourceDeps []string
 	ignores      []string
+	links        []model.Link
 }
 
 func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
@@ -32,6 +33,7 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 	var deps starlark.Value
 	var resourceDepsVal starlark.Sequence
 	var ignoresVal starlark.Value
+	var linksVal starlark.Value
 	autoInit := true
 
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
@@ -43,6 +45,7 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		"ignore?", &ignoresVal,
 		"auto_init?", &autoInit,
 		"serve_cmd?", &serveCmdVal,
+		"links?", &linksVal,
 	); err != nil {
 		return nil, err
 	}
@@ -69,6 +72,11 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		return nil, err
 	}
 
+	links, err := parseLinks(linksVal)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %q", fn.Name(), name)
+	}
+
 	updateCmd, err := value.ValueToHostCmd(updateCmdVal)
 	if err != nil {
 		return nil, err
@@ -93,6 +101,7 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		repos:        repos,
 		resourceDeps: resourceDeps,
 		ignores:      ignores,
+		links:        links,
 	}
 	s.localResources = append(s.localResources, res)
 
@@ -0,0 +1,111 @@
+package tiltfile
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.starlark.net/starlark"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+var invalidNamespaceCharsRegexp = regexp.MustCompile(`[^a-z0-9-]`)
+
+// Derives a namespace for the current developer and records it, so that
+// assembly rewrites all deployed objects into that namespace. This lets
+// many developers share one cluster without editing YAML by hand.
+//
+// Port forwards need no rewriting: they attach to the pods of the
+// (rewritten) workloads, wherever those pods land.
+func (s *tiltfileState) devNamespaceFn(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if s.devNamespace != "" {
+		return starlark.None, errors.New("dev namespace already defined")
+	}
+
+	template := "dev-$USER"
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"template?", &template); err != nil {
+		return nil, err
+	}
+
+	ns, err := expandNamespaceTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid namespace %q (from template %q): %s",
+			ns, template, strings.Join(errs, "; "))
+	}
+
+	s.devNamespace = ns
+
+	return starlark.String(ns), nil
+}
+
+func expandNamespaceTemplate(template string) (string, error) {
+	var expandErr error
+	ns := os.Expand(template, func(key string) string {
+		if key != "USER" {
+			expandErr = fmt.Errorf("unknown variable $%s in namespace template %q (only $USER is supported)", key, template)
+			return ""
+		}
+
+		username := os.Getenv("USER")
+		if username == "" {
+			u, err := user.Current()
+			if err != nil {
+				expandErr = errors.Wrap(err, "expanding $USER in namespace template")
+				return ""
+			}
+			username = u.Username
+		}
+
+		// Usernames aren't necessarily valid DNS labels (e.g. First.Last).
+		return invalidNamespaceCharsRegexp.ReplaceAllString(strings.ToLower(username), "-")
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return ns, nil
+}
+
+// Rewrites all deployed entities into the dev namespace, and adds the
+// namespace itself to the deployed YAML so `tilt up` creates it and
+// `tilt down` can delete it.
+func (s *tiltfileState) applyDevNamespace() error {
+	if s.devNamespace == "" {
+		return nil
+	}
+
+	for _, r := range s.k8s {
+		for i, e := range r.entities {
+			if isClusterScoped(e) {
+				continue
+			}
+			r.entities[i] = e.WithNamespace(s.devNamespace)
+		}
+	}
+
+	for i, e := range s.k8sUnresourced {
+		if isClusterScoped(e) {
+			continue
+		}
+		s.k8sUnresourced[i] = e.WithNamespace(s.devNamespace)
+	}
+
+	nsEntity, err := k8s.InjectLabels(k8s.NewNamespaceEntity(s.devNamespace),
+		[]model.LabelPair{{Key: k8s.DevNamespaceLabel, Value: "true"}})
+	if err != nil {
+		return errors.Wrap(err, "labeling dev namespace")
+	}
+	s.k8sUnresourced = append([]k8s.K8sEntity{nsEntity}, s.k8sUnresourced...)
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package notifysettings
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+
+	"github.com/windmilleng/tilt/pkg/model"
+
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+	"github.com/windmilleng/tilt/internal/tiltfile/value"
+)
+
+// Implements functions for dealing with webhook notification settings.
+type Extension struct {
+}
+
+func NewExtension() Extension {
+	return Extension{}
+}
+
+func (e Extension) NewState() interface{} {
+	return model.DefaultNotifySettings()
+}
+
+func (e Extension) OnStart(env *starkit.Environment) error {
+	return env.AddBuiltin("notify_settings", e.notifySettings)
+}
+
+func (e Extension) notifySettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var webhookURL string
+	var desktop bool
+	var eventsVal starlark.Value
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"webhook_url?", &webhookURL,
+		"desktop?", &desktop,
+		"events?", &eventsVal); err != nil {
+		return nil, err
+	}
+
+	if webhookURL == "" && !desktop {
+		return nil, fmt.Errorf("notify_settings requires webhook_url and/or desktop=True")
+	}
+
+	var events []model.NotifyEvent
+	for _, v := range value.ValueOrSequenceToSlice(eventsVal) {
+		s, ok := starlark.AsString(v)
+		if !ok {
+			return nil, fmt.Errorf("events must be a list of strings; found %s", v.Type())
+		}
+		event, err := parseEvent(s)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	err := starkit.SetState(thread, func(settings model.NotifySettings) model.NotifySettings {
+		settings.WebhookURL = webhookURL
+		settings.Desktop = desktop
+		settings.Events = events
+		return settings
+	})
+
+	return starlark.None, err
+}
+
+func parseEvent(s string) (model.NotifyEvent, error) {
+	for _, event := range model.NotifyEvents {
+		if s == string(event) {
+			return event, nil
+		}
+	}
+	return "", fmt.Errorf("unknown notify event %q; valid events: %v", s, model.NotifyEvents)
+}
+
+var _ starkit.StatefulExtension = Extension{}
+
+func MustState(model starkit.Model) model.NotifySettings {
+	state, err := GetState(model)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+func GetState(m starkit.Model) (model.NotifySettings, error) {
+	var state model.NotifySettings
+	err := m.Load(&state)
+	return state, err
+}
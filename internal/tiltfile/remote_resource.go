@@ -0,0 +1,147 @@
+package tiltfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.starlark.net/starlark"
+
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+	"github.com/windmilleng/tilt/internal/tiltfile/value"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// remote_resource syncs files and runs commands on a plain SSH host,
+// reusing the live_update sync/run step values. It translates into a
+// local resource whose update command rsyncs the sync steps to the host
+// and runs the run/restart commands over ssh, so the engine's file
+// watching, triggering, and serve_cmd handling all apply unchanged.
+func (s *tiltfileState) remoteResource(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name, host string
+	var syncVal, runVal starlark.Value
+	var restartCmd, serveCmd string
+	var triggerMode triggerMode
+	var depsVal starlark.Value
+	var resourceDepsVal starlark.Sequence
+	autoInit := true
+
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"name", &name,
+		"host", &host,
+		"sync?", &syncVal,
+		"run?", &runVal,
+		"restart_cmd?", &restartCmd,
+		"serve_cmd?", &serveCmd,
+		"deps?", &depsVal,
+		"trigger_mode?", &triggerMode,
+		"resource_deps?", &resourceDepsVal,
+		"auto_init?", &autoInit,
+	); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("%s: name must not be empty", fn.Name())
+	}
+	if host == "" {
+		return nil, fmt.Errorf("%s: host must not be empty", fn.Name())
+	}
+
+	var syncs []liveUpdateSyncStep
+	for _, v := range starlarkValueOrSequenceToSlice(syncVal) {
+		step, ok := v.(liveUpdateSyncStep)
+		if !ok {
+			return nil, fmt.Errorf("%s: sync must be a list of sync steps; found a %s", fn.Name(), v.Type())
+		}
+		s.consumeLiveUpdateStep(step)
+		syncs = append(syncs, step)
+	}
+
+	var runs []liveUpdateRunStep
+	for _, v := range starlarkValueOrSequenceToSlice(runVal) {
+		step, ok := v.(liveUpdateRunStep)
+		if !ok {
+			return nil, fmt.Errorf("%s: run must be a list of run steps; found a %s", fn.Name(), v.Type())
+		}
+		if len(step.triggers) > 0 {
+			return nil, fmt.Errorf("%s: run triggers are not supported on a remote host", fn.Name())
+		}
+		s.consumeLiveUpdateStep(step)
+		runs = append(runs, step)
+	}
+
+	if len(syncs) == 0 && len(runs) == 0 && restartCmd == "" && serveCmd == "" {
+		return nil, fmt.Errorf("%s must have at least one sync, run, restart_cmd, or serve_cmd", fn.Name())
+	}
+
+	var parts []string
+	for _, sync := range syncs {
+		parts = append(parts, rsyncCmd(sync.localPath, host, sync.remotePath))
+	}
+	for _, run := range runs {
+		parts = append(parts, sshCmd(host, run.command.String()))
+	}
+	if restartCmd != "" {
+		parts = append(parts, sshCmd(host, restartCmd))
+	}
+
+	var updateCmd model.Cmd
+	if len(parts) > 0 {
+		updateCmd = model.ToHostCmd(strings.Join(parts, " && "))
+	}
+	var remoteServeCmd model.Cmd
+	if serveCmd != "" {
+		remoteServeCmd = model.ToHostCmd(sshCmd(host, serveCmd))
+	}
+
+	// The sync steps' local paths are watched like local_resource deps.
+	depsVals := starlarkValueOrSequenceToSlice(depsVal)
+	var depsStrings []string
+	for _, v := range depsVals {
+		path, err := value.ValueToAbsPath(thread, v)
+		if err != nil {
+			return nil, fmt.Errorf("deps must be a string or a sequence of strings; found a %T", v)
+		}
+		depsStrings = append(depsStrings, path)
+	}
+	for _, sync := range syncs {
+		depsStrings = append(depsStrings, sync.localPath)
+	}
+
+	resourceDeps, err := value.SequenceToStringSlice(resourceDepsVal)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: resource_deps", fn.Name())
+	}
+
+	res := localResource{
+		name:         name,
+		updateCmd:    updateCmd,
+		serveCmd:     remoteServeCmd,
+		workdir:      filepath.Dir(starkit.CurrentExecPath(thread)),
+		deps:         depsStrings,
+		triggerMode:  triggerMode,
+		autoInit:     autoInit,
+		repos:        reposForPaths(depsStrings),
+		resourceDeps: resourceDeps,
+	}
+	s.localResources = append(s.localResources, res)
+
+	return starlark.None, nil
+}
+
+// Build an rsync invocation mirroring live_update sync semantics:
+// the contents of a local directory land under the remote path.
+func rsyncCmd(localPath, host, remotePath string) string {
+	src := localPath
+	if fi, err := os.Stat(localPath); err == nil && fi.IsDir() && !strings.HasSuffix(src, "/") {
+		src += "/"
+	}
+	return fmt.Sprintf("rsync -az %q %q", src, host+":"+remotePath)
+}
+
+func sshCmd(host, cmd string) string {
+	return fmt.Sprintf("ssh %q %q", host, cmd)
+}
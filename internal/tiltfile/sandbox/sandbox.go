@@ -0,0 +1,87 @@
+// Package sandbox defines limits on Tiltfile execution.
+//
+// The limits come from environment variables rather than the Tiltfile
+// itself, so a bad Tiltfile edit can't relax its own sandbox and
+// platform teams can enforce policy by setting them in the environment
+// that launches tilt.
+package sandbox
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// Wall-clock budget for a single Tiltfile execution, as a Go duration
+// ("30s", "2m"). Unset or unparseable means no limit.
+const TimeoutEnvName = "TILT_TILTFILE_TIMEOUT"
+
+// How many local() subprocesses a single Tiltfile execution may start.
+// Unset or unparseable means no limit.
+const MaxLocalEnvName = "TILT_TILTFILE_MAX_LOCAL"
+
+// Set to "1" or "true" to make builtins that fetch URLs (like the
+// ext:// extension fetcher) fail instead of touching the network.
+const DisableNetworkEnvName = "TILT_TILTFILE_DISABLE_NETWORK"
+
+type Limits struct {
+	// Zero means unlimited.
+	Timeout time.Duration
+
+	// Zero means unlimited.
+	MaxLocalCalls int
+
+	AllowNetwork bool
+}
+
+func FromEnv() Limits {
+	return Limits{
+		Timeout:       timeoutFromEnv(),
+		MaxLocalCalls: maxLocalFromEnv(),
+		AllowNetwork:  !networkDisabledFromEnv(),
+	}
+}
+
+// The deadline for an execution that started at `start`, or false if
+// there's no timeout configured.
+func (l Limits) Deadline(start time.Time) (time.Time, bool) {
+	if l.Timeout == 0 {
+		return time.Time{}, false
+	}
+	return start.Add(l.Timeout), true
+}
+
+func timeoutFromEnv() time.Duration {
+	v := os.Getenv(TimeoutEnvName)
+	if v == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+func maxLocalFromEnv() int {
+	v := os.Getenv(MaxLocalEnvName)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+func networkDisabledFromEnv() bool {
+	v := os.Getenv(DisableNetworkEnvName)
+	return v == "1" || v == "true"
+}
+
+// NetworkAllowed is the check URL-fetching builtins make before going
+// to the network.
+func NetworkAllowed() bool {
+	return !networkDisabledFromEnv()
+}
@@ -0,0 +1,63 @@
+package sandbox
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromEnv(t *testing.T) {
+	unset := setEnv(t, map[string]string{
+		TimeoutEnvName:        "",
+		MaxLocalEnvName:       "",
+		DisableNetworkEnvName: "",
+	})
+	defer unset()
+
+	limits := FromEnv()
+	assert.Equal(t, time.Duration(0), limits.Timeout)
+	assert.Equal(t, 0, limits.MaxLocalCalls)
+	assert.True(t, limits.AllowNetwork)
+
+	_ = os.Setenv(TimeoutEnvName, "30s")
+	_ = os.Setenv(MaxLocalEnvName, "5")
+	_ = os.Setenv(DisableNetworkEnvName, "1")
+
+	limits = FromEnv()
+	assert.Equal(t, 30*time.Second, limits.Timeout)
+	assert.Equal(t, 5, limits.MaxLocalCalls)
+	assert.False(t, limits.AllowNetwork)
+
+	// Garbage values fall back to no limit rather than failing startup.
+	_ = os.Setenv(TimeoutEnvName, "not-a-duration")
+	_ = os.Setenv(MaxLocalEnvName, "-1")
+	limits = FromEnv()
+	assert.Equal(t, time.Duration(0), limits.Timeout)
+	assert.Equal(t, 0, limits.MaxLocalCalls)
+}
+
+func TestDeadline(t *testing.T) {
+	start := time.Now()
+
+	_, ok := Limits{}.Deadline(start)
+	assert.False(t, ok)
+
+	deadline, ok := Limits{Timeout: time.Minute}.Deadline(start)
+	assert.True(t, ok)
+	assert.Equal(t, start.Add(time.Minute), deadline)
+}
+
+func setEnv(t *testing.T, vars map[string]string) func() {
+	old := make(map[string]string, len(vars))
+	for k, v := range vars {
+		old[k] = os.Getenv(k)
+		_ = os.Setenv(k, v)
+	}
+	return func() {
+		for k, v := range old {
+			_ = os.Setenv(k, v)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package tiltfile
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"go.starlark.net/starlark"
+
+	"github.com/windmilleng/tilt/internal/tiltfile/io"
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+	"github.com/windmilleng/tilt/internal/tiltfile/value"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// Registers every value in a local env file (KEY=VALUE lines) as a secret,
+// so it's scrubbed from logs, snapshots, and API output wherever it appears.
+// Returns a dict of the keys and values, so they can be threaded into
+// build args or a local resource's command.
+func (s *tiltfileState) secret(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var pathVal starlark.Value
+	err := s.unpackArgs(fn.Name(), args, kwargs,
+		"env_file", &pathVal)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := value.ValueToAbsPath(thread, pathVal)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := io.ReadFile(thread, p)
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.Base(p)
+	ret := starlark.NewDict(0)
+	for i, line := range strings.Split(string(contents), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if eq <= 0 {
+			return nil, fmt.Errorf("%s: %s:%d: expected lines of the form KEY=VALUE", fn.Name(), p, i+1)
+		}
+
+		key := strings.TrimSpace(line[:eq])
+		v := strings.TrimSpace(line[eq+1:])
+		if len(v) >= 2 && (v[0] == '"' || v[0] == '\'') && v[len(v)-1] == v[0] {
+			v = v[1 : len(v)-1]
+		}
+
+		s.secrets.AddSecret(name, key, []byte(v))
+		err = ret.SetKey(starlark.String(key), starlark.String(v))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// Marks a string as sensitive and returns it unchanged, so it can be passed
+// to docker_build build args (or anywhere else) without its value showing up
+// in logs, snapshots, or API output.
+func (s *tiltfileState) sensitive(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var v string
+	var name string
+	err := s.unpackArgs(fn.Name(), args, kwargs,
+		"value", &v,
+		"name?", &name)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = sensitiveN
+	}
+	s.secrets.AddSecret("Tiltfile", name, []byte(v))
+	return starlark.String(v), nil
+}
+
+// Reads a secret from an external source and registers it with the log
+// scrubber, so the value never shows up in logs, snapshots, or API output.
+//
+// Sources: 'env' reads an environment variable, 'file' reads a local file,
+// 'exec' runs a command (e.g., `op` or `vault`) and captures its stdout.
+func (s *tiltfileState) readSecret(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var source, from, name string
+	err := s.unpackArgs(fn.Name(), args, kwargs,
+		"source", &source,
+		"from", &from,
+		"name?", &name)
+	if err != nil {
+		return nil, err
+	}
+
+	if from == "" {
+		return nil, fmt.Errorf("%s: 'from' must be non-empty", fn.Name())
+	}
+
+	var v string
+	switch source {
+	case "env":
+		ev, ok := os.LookupEnv(from)
+		if !ok {
+			return nil, fmt.Errorf("%s: environment variable %q not set", fn.Name(), from)
+		}
+		v = ev
+		if name == "" {
+			name = from
+		}
+	case "file":
+		p := starkit.AbsPath(thread, from)
+		contents, err := io.ReadFile(thread, p)
+		if err != nil {
+			return nil, err
+		}
+		v = string(contents)
+		if name == "" {
+			name = filepath.Base(p)
+		}
+	case "exec":
+		cmd := model.ToHostCmd(from)
+		out, err := exec.Command(cmd.Argv[0], cmd.Argv[1:]...).Output()
+		if err != nil {
+			if ee, ok := err.(*exec.ExitError); ok {
+				return nil, fmt.Errorf("%s: command %q failed: %v\n%s", fn.Name(), from, err, string(ee.Stderr))
+			}
+			return nil, fmt.Errorf("%s: command %q failed: %v", fn.Name(), from, err)
+		}
+		v = string(out)
+		if name == "" {
+			name = filepath.Base(strings.Fields(from)[0])
+		}
+	default:
+		return nil, fmt.Errorf("%s: unknown secret source %q (must be 'env', 'file', or 'exec')", fn.Name(), source)
+	}
+
+	v = strings.TrimSpace(v)
+	s.secrets.AddSecret(source, name, []byte(v))
+	return starlark.String(v), nil
+}
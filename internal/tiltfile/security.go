@@ -0,0 +1,67 @@
+package tiltfile
+
+import (
+	"fmt"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/internal/tiltfile/securitysettings"
+)
+
+// Kinds that live outside any namespace. Not exhaustive -- CRDs can define
+// more -- but covers what a Tiltfile is likely to deploy.
+var clusterScopedKinds = map[string]bool{
+	"APIService":                     true,
+	"ClusterRole":                    true,
+	"ClusterRoleBinding":             true,
+	"CSIDriver":                      true,
+	"CSINode":                        true,
+	"CustomResourceDefinition":       true,
+	"MutatingWebhookConfiguration":   true,
+	"Namespace":                      true,
+	"PersistentVolume":               true,
+	"PodSecurityPolicy":              true,
+	"PriorityClass":                  true,
+	"RuntimeClass":                   true,
+	"StorageClass":                   true,
+	"ValidatingWebhookConfiguration": true,
+	"VolumeAttachment":               true,
+}
+
+func isClusterScoped(e k8s.K8sEntity) bool {
+	return clusterScopedKinds[e.GVK().Kind]
+}
+
+// Enforces the policy set by security_settings() against everything the
+// Tiltfile asked us to deploy.
+func (s *tiltfileState) validateSecuritySettings(settings securitysettings.Settings) error {
+	if !settings.AllowPortForwards {
+		for _, r := range s.k8s {
+			if len(r.portForwards) > 0 {
+				return fmt.Errorf("security_settings: port-forwards are disabled, but resource %q declares one", r.name)
+			}
+		}
+	}
+
+	var entities []k8s.K8sEntity
+	for _, r := range s.k8s {
+		entities = append(entities, r.entities...)
+	}
+	entities = append(entities, s.k8sUnresourced...)
+
+	for _, e := range entities {
+		if isClusterScoped(e) {
+			if !settings.AllowClusterScopedObjects {
+				return fmt.Errorf("security_settings: cluster-scoped objects are disabled, but the Tiltfile deploys %s %q",
+					e.GVK().Kind, e.Name())
+			}
+			continue
+		}
+
+		if !settings.NamespaceAllowed(e.Namespace().String()) {
+			return fmt.Errorf("security_settings: %s %q is in namespace %q, which is not in allowed_namespaces",
+				e.GVK().Kind, e.Name(), e.Namespace())
+		}
+	}
+
+	return nil
+}
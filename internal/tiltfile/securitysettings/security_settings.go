@@ -0,0 +1,93 @@
+package securitysettings
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+
+	"github.com/windmilleng/tilt/pkg/model"
+
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+	"github.com/windmilleng/tilt/internal/tiltfile/value"
+)
+
+// Implements functions for dealing with security settings.
+type Extension struct {
+}
+
+func NewExtension() Extension {
+	return Extension{}
+}
+
+type Settings struct {
+	model.SecuritySettings
+
+	// security_settings() may only be called once, so that code loaded
+	// later in the Tiltfile can't quietly loosen the policy.
+	set bool
+}
+
+func (e Extension) NewState() interface{} {
+	return Settings{SecuritySettings: model.DefaultSecuritySettings()}
+}
+
+func (e Extension) OnStart(env *starkit.Environment) error {
+	return env.AddBuiltin("security_settings", e.securitySettings)
+}
+
+func (e Extension) securitySettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	allowExec := true
+	allowPortForwards := true
+	allowClusterScopedObjects := true
+	var namespacesVal starlark.Value
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"allow_exec?", &allowExec,
+		"allow_port_forwards?", &allowPortForwards,
+		"allowed_namespaces?", &namespacesVal,
+		"allow_cluster_scoped_objects?", &allowClusterScopedObjects); err != nil {
+		return nil, err
+	}
+
+	namespaces, ok := value.AsStringOrStringList(namespacesVal)
+	if !ok {
+		return nil, fmt.Errorf("allowed_namespaces must be a string or a list of strings; found %s", namespacesVal.Type())
+	}
+
+	alreadySet := false
+	err := starkit.SetState(thread, func(settings Settings) Settings {
+		if settings.set {
+			alreadySet = true
+			return settings
+		}
+		settings.AllowExec = allowExec
+		settings.AllowPortForwards = allowPortForwards
+		settings.AllowedNamespaces = namespaces
+		settings.AllowClusterScopedObjects = allowClusterScopedObjects
+		settings.set = true
+		return settings
+	})
+	if err != nil {
+		return nil, err
+	}
+	if alreadySet {
+		return nil, fmt.Errorf("security_settings can only be called once")
+	}
+
+	return starlark.None, nil
+}
+
+var _ starkit.StatefulExtension = Extension{}
+
+func MustState(model starkit.Model) Settings {
+	state, err := GetState(model)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+func GetState(m starkit.Model) (Settings, error) {
+	var state Settings
+	err := m.Load(&state)
+	return state, err
+}
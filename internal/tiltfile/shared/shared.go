@@ -0,0 +1,113 @@
+// Package shared implements a key/value store for passing computed
+// values between composed Tiltfiles.
+//
+// A monorepo root Tiltfile often include()s per-project Tiltfiles that
+// need values the root computed (rendered hostnames, allocated ports).
+// Before this, the only channel was temp files. Values live for a
+// single Tiltfile execution; they are not persisted.
+package shared
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+)
+
+type Settings struct {
+	Values map[string]starlark.Value
+}
+
+type Extension struct{}
+
+func NewExtension() Extension {
+	return Extension{}
+}
+
+func (e Extension) NewState() interface{} {
+	return Settings{
+		Values: make(map[string]starlark.Value),
+	}
+}
+
+func (Extension) OnStart(env *starkit.Environment) error {
+	err := env.AddBuiltin("experimental_set_shared", setShared)
+	if err != nil {
+		return err
+	}
+	return env.AddBuiltin("experimental_get_shared", getShared)
+}
+
+func setShared(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var key string
+	var v starlark.Value
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"key", &key,
+		"value", &v)
+	if err != nil {
+		return nil, err
+	}
+
+	if key == "" {
+		return nil, fmt.Errorf("key cannot be empty")
+	}
+
+	// Freeze so a later Tiltfile can't mutate a value out from under
+	// the one that set it.
+	v.Freeze()
+
+	err = starkit.SetState(thread, func(settings Settings) Settings {
+		settings.Values[key] = v
+		return settings
+	})
+
+	return starlark.None, err
+}
+
+func getShared(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var key string
+	var defaultV starlark.Value
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"key", &key,
+		"default?", &defaultV)
+	if err != nil {
+		return nil, err
+	}
+
+	model, err := starkit.ModelFromThread(thread)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings Settings
+	err = model.Load(&settings)
+	if err != nil {
+		return nil, err
+	}
+
+	v, ok := settings.Values[key]
+	if !ok {
+		if defaultV != nil {
+			return defaultV, nil
+		}
+		return nil, fmt.Errorf("no shared value %q; set it first with experimental_set_shared", key)
+	}
+	return v, nil
+}
+
+var _ starkit.StatefulExtension = Extension{}
+
+func MustState(model starkit.Model) Settings {
+	state, err := GetState(model)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+func GetState(m starkit.Model) (Settings, error) {
+	var state Settings
+	err := m.Load(&state)
+	return state, err
+}
@@ -0,0 +1,55 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/windmilleng/tilt/internal/tiltfile/include"
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+)
+
+func TestSetGetAcrossIncludedTiltfiles(t *testing.T) {
+	f := NewFixture(t)
+
+	f.File("Tiltfile", `
+experimental_set_shared('host', 'foo.localhost')
+include('./foo/Tiltfile')
+`)
+	f.File("foo/Tiltfile", `
+host = experimental_get_shared('host')
+host == 'foo.localhost' or fail('got %s' % host)
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	assert.NoError(t, err)
+}
+
+func TestGetMissing(t *testing.T) {
+	f := NewFixture(t)
+
+	f.File("Tiltfile", `
+experimental_get_shared('port')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `no shared value "port"`)
+	}
+}
+
+func TestGetDefault(t *testing.T) {
+	f := NewFixture(t)
+
+	f.File("Tiltfile", `
+port = experimental_get_shared('port', default=8000)
+port == 8000 or fail('got %s' % port)
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	assert.NoError(t, err)
+}
+
+func NewFixture(tb testing.TB) *starkit.Fixture {
+	return starkit.NewFixture(tb, NewExtension(), &include.IncludeFn{})
+}
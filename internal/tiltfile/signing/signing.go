@@ -0,0 +1,62 @@
+package signing
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+
+	"github.com/windmilleng/tilt/pkg/model"
+
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+)
+
+// Implements functions for dealing with image signing settings.
+type Extension struct {
+}
+
+func NewExtension() Extension {
+	return Extension{}
+}
+
+func (e Extension) NewState() interface{} {
+	return model.SigningSettings{}
+}
+
+func (e Extension) OnStart(env *starkit.Environment) error {
+	return env.AddBuiltin("image_signing", e.imageSigning)
+}
+
+func (e Extension) imageSigning(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var command string
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"command", &command); err != nil {
+		return nil, err
+	}
+
+	if command == "" {
+		return nil, fmt.Errorf("image_signing requires a non-empty command")
+	}
+
+	err := starkit.SetState(thread, func(settings model.SigningSettings) model.SigningSettings {
+		settings.Command = command
+		return settings
+	})
+
+	return starlark.None, err
+}
+
+var _ starkit.StatefulExtension = Extension{}
+
+func MustState(model starkit.Model) model.SigningSettings {
+	state, err := GetState(model)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+func GetState(m starkit.Model) (model.SigningSettings, error) {
+	var state model.SigningSettings
+	err := m.Load(&state)
+	return state, err
+}
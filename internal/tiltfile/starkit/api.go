@@ -0,0 +1,56 @@
+package starkit
+
+import "sort"
+
+// Structured documentation for one builtin (or value), so editor
+// tooling and `tilt api` can be generated from the binary itself.
+type BuiltinDoc struct {
+	Name      string   `json:"name"`
+	Signature string   `json:"signature,omitempty"`
+	Summary   string   `json:"summary,omitempty"`
+	Args      []ArgDoc `json:"args,omitempty"`
+}
+
+type ArgDoc struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+	Doc  string `json:"doc,omitempty"`
+}
+
+// Attach documentation to a builtin added with AddBuiltin (or a value
+// added with AddValue). Extensions call this from OnStart; builtins
+// without docs still appear in the API dump by name.
+func (e *Environment) AddBuiltinDoc(name string, doc BuiltinDoc) {
+	doc.Name = name
+	e.docs[name] = doc
+}
+
+// Every name registered in the environment, with documentation where
+// the registering extension provided it, sorted by name.
+func (e *Environment) APIDocs() []BuiltinDoc {
+	names := append([]string{}, e.names...)
+	sort.Strings(names)
+
+	docs := make([]BuiltinDoc, 0, len(names))
+	for _, name := range names {
+		doc, ok := e.docs[name]
+		if !ok {
+			doc = BuiltinDoc{Name: name}
+		}
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+// Enumerate the API of a set of extensions without executing anything:
+// builds an environment, runs OnStart, and dumps what was registered.
+func APIDocs(extensions ...Extension) ([]BuiltinDoc, error) {
+	env := newEnvironment(extensions...)
+	for _, ext := range env.extensions {
+		err := ext.OnStart(env)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return env.APIDocs(), nil
+}
@@ -0,0 +1,39 @@
+package starkit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.starlark.net/starlark"
+)
+
+type documentedExtension struct{}
+
+func (documentedExtension) OnStart(env *Environment) error {
+	err := env.AddBuiltin("greet", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		return starlark.None, nil
+	})
+	if err != nil {
+		return err
+	}
+	env.AddBuiltinDoc("greet", BuiltinDoc{
+		Signature: "greet(name)",
+		Summary:   "Say hello.",
+	})
+
+	return env.AddValue("undocumented", starlark.None)
+}
+
+func TestAPIDocs(t *testing.T) {
+	docs, err := APIDocs(documentedExtension{})
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, "greet", docs[0].Name)
+	assert.Equal(t, "greet(name)", docs[0].Signature)
+	assert.Equal(t, "Say hello.", docs[0].Summary)
+
+	assert.Equal(t, "undocumented", docs[1].Name)
+	assert.Equal(t, "", docs[1].Signature)
+}
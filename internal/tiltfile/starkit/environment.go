@@ -13,7 +13,15 @@ import (
 // The main entrypoint to starkit.
 // Execute a file with a set of starlark extensions.
 func ExecFile(path string, extensions ...Extension) (Model, error) {
-	return newEnvironment(extensions...).start(path)
+	return ExecFileWithCache(nil, path, extensions...)
+}
+
+// Like ExecFile, but reuses cached results of pure load()ed modules
+// from previous executions. A nil cache disables reuse.
+func ExecFileWithCache(cache *ModuleCache, path string, extensions ...Extension) (Model, error) {
+	env := newEnvironment(extensions...)
+	env.SetModuleCache(cache)
+	return env.start(path)
 }
 
 const argUnpackerKey = "starkit.ArgUnpacker"
@@ -40,6 +48,15 @@ type Environment struct {
 	extensions       []Extension
 	fakeFileSystem   map[string]string
 	loadInterceptors []LoadInterceptor
+
+	// Optional cross-execution cache of pure modules. Nil disables it.
+	moduleCache  *ModuleCache
+	builtinCount int
+	depStack     []*depRecord
+
+	// Registered names and their documentation, for API introspection.
+	names []string
+	docs  map[string]BuiltinDoc
 }
 
 func newEnvironment(extensions ...Extension) *Environment {
@@ -49,6 +66,7 @@ func newEnvironment(extensions ...Extension) *Environment {
 		extensions:     append([]Extension{}, extensions...),
 		predeclared:    starlark.StringDict{},
 		fakeFileSystem: nil,
+		docs:           make(map[string]BuiltinDoc),
 	}
 }
 
@@ -56,6 +74,10 @@ func (e *Environment) AddLoadInterceptor(i LoadInterceptor) {
 	e.loadInterceptors = append(e.loadInterceptors, i)
 }
 
+func (e *Environment) SetModuleCache(c *ModuleCache) {
+	e.moduleCache = c
+}
+
 func (e *Environment) SetArgUnpacker(unpackArgs ArgUnpacker) {
 	e.unpackArgs = unpackArgs
 }
@@ -67,6 +89,7 @@ func (e *Environment) SetArgUnpacker(unpackArgs ArgUnpacker) {
 // All builtins should use starkit.UnpackArgs to get instrumentation.
 func (e *Environment) AddBuiltin(name string, f Function) error {
 	wrapped := starlark.NewBuiltin(name, func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		e.builtinCount++
 		for _, ext := range e.extensions {
 			onBuiltinCallExt, ok := ext.(OnBuiltinCallExtension)
 			if ok {
@@ -110,9 +133,28 @@ func (e *Environment) AddValue(name string, val starlark.Value) error {
 		return fmt.Errorf("multiple values added named %s", name)
 	}
 	attrMap[baseName] = val
+	e.names = append(e.names, name)
 	return nil
 }
 
+// Whether a value with the given (possibly dotted) name has been added
+// to the environment, e.g., HasValue("flags.define_bool").
+func (e *Environment) HasValue(name string) bool {
+	split := strings.Split(name, ".")
+
+	var attrMap = e.predeclared
+	for i := 0; i < len(split)-1; i++ {
+		currentModule, ok := attrMap[split[i]].(Module)
+		if !ok {
+			return false
+		}
+		attrMap = currentModule.attrs
+	}
+
+	_, ok := attrMap[split[len(split)-1]]
+	return ok
+}
+
 func (e *Environment) SetPrint(print func(thread *starlark.Thread, msg string)) {
 	e.print = print
 }
@@ -187,6 +229,20 @@ func (e *Environment) exec(t *starlark.Thread, path string) (starlark.StringDict
 		return entry.exports, entry.err
 	}
 
+	// Only load()ed modules are cached; the root Tiltfile always executes.
+	isRoot := len(e.depStack) == 0
+
+	// Reuse a still-valid result from a previous execution, if we have one.
+	if cached, ok := e.moduleCache.get(localPath); ok && !isRoot && e.cacheEntryValid(localPath, cached) {
+		exports, err := e.replayCached(t, localPath, cached)
+		e.loadCache[localPath] = loadCacheEntry{
+			status:  loadStatusDone,
+			exports: exports,
+			err:     err,
+		}
+		return exports, err
+	}
+
 	e.loadCache[localPath] = loadCacheEntry{
 		status: loadStatusExecuting,
 	}
@@ -194,10 +250,29 @@ func (e *Environment) exec(t *starlark.Thread, path string) (starlark.StringDict
 	oldPath := t.Local(execingTiltfileKey)
 	t.SetLocal(execingTiltfileKey, localPath)
 
+	record := newDepRecord()
+	e.depStack = append(e.depStack, record)
+	builtinsBefore := e.builtinCount
+
 	exports, err := e.doLoad(t, localPath)
 
+	e.depStack = e.depStack[:len(e.depStack)-1]
+	pure := e.builtinCount == builtinsBefore
+
 	t.SetLocal(execingTiltfileKey, oldPath)
 
+	// The loading module depends on this file and everything it loaded.
+	if parent := e.currentDepRecord(); parent != nil {
+		parent.add(localPath)
+		parent.add(record.paths...)
+	}
+
+	// A module that called no builtins is a pure function of the files in
+	// its load graph, so its exports are safe to reuse next execution.
+	if err == nil && pure && !isRoot {
+		e.storeCacheEntry(localPath, record, exports)
+	}
+
 	e.loadCache[localPath] = loadCacheEntry{
 		status:  loadStatusDone,
 		exports: exports,
@@ -206,6 +281,64 @@ func (e *Environment) exec(t *starlark.Thread, path string) (starlark.StringDict
 	return exports, err
 }
 
+func (e *Environment) currentDepRecord() *depRecord {
+	if len(e.depStack) == 0 {
+		return nil
+	}
+	return e.depStack[len(e.depStack)-1]
+}
+
+func (e *Environment) storeCacheEntry(path string, record *depRecord, exports starlark.StringDict) {
+	if e.moduleCache == nil {
+		return
+	}
+
+	contentHash, err := e.hashFile(path)
+	if err != nil {
+		return
+	}
+
+	depHashes := make(map[string]string, len(record.paths))
+	for _, dep := range record.paths {
+		h, err := e.hashFile(dep)
+		if err != nil {
+			return
+		}
+		depHashes[dep] = h
+	}
+
+	e.moduleCache.put(path, moduleCacheEntry{
+		contentHash: contentHash,
+		depPaths:    record.paths,
+		depHashes:   depHashes,
+		exports:     exports,
+	})
+}
+
+// Serve a module from the cross-execution cache. OnExec hooks still run
+// for the module and everything it loaded, so file reads are recorded
+// for watching just as on a real execution.
+func (e *Environment) replayCached(t *starlark.Thread, path string, entry moduleCacheEntry) (starlark.StringDict, error) {
+	for _, p := range append([]string{path}, entry.depPaths...) {
+		for _, ext := range e.extensions {
+			onExecExt, ok := ext.(OnExecExtension)
+			if ok {
+				err := onExecExt.OnExec(t, p)
+				if err != nil {
+					return starlark.StringDict{}, err
+				}
+			}
+		}
+	}
+
+	if parent := e.currentDepRecord(); parent != nil {
+		parent.add(path)
+		parent.add(entry.depPaths...)
+	}
+
+	return entry.exports, nil
+}
+
 func (e *Environment) getPath(t *starlark.Thread, path string) (string, error) {
 	for _, i := range e.loadInterceptors {
 		newPath, err := i.LocalPath(t, path)
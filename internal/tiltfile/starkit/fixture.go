@@ -26,6 +26,7 @@ type Fixture struct {
 	out              *bytes.Buffer
 	useRealFS        bool // Use a real filesystem
 	loadInterceptors []LoadInterceptor
+	moduleCache      *ModuleCache
 }
 
 func NewFixture(tb testing.TB, extensions ...Extension) *Fixture {
@@ -60,9 +61,16 @@ func (f *Fixture) ExecFile(name string) (Model, error) {
 	for _, i := range f.loadInterceptors {
 		env.AddLoadInterceptor(i)
 	}
+	env.SetModuleCache(f.moduleCache)
 	return env.start(filepath.Join(f.path, name))
 }
 
+// Share a module cache across ExecFile calls, as `tilt up` does across
+// Tiltfile re-executions.
+func (f *Fixture) SetModuleCache(c *ModuleCache) {
+	f.moduleCache = c
+}
+
 func (f *Fixture) SetLoadInterceptor(i LoadInterceptor) {
 	f.loadInterceptors = append(f.loadInterceptors, i)
 }
@@ -0,0 +1,119 @@
+package starkit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"sync"
+
+	"go.starlark.net/starlark"
+)
+
+// ModuleCache caches the exports of load()ed modules across executions.
+//
+// A module is only cached if executing it called no builtins, so it
+// can't have registered resources or otherwise had side effects — its
+// exports are a pure function of its file contents and the contents of
+// the files it transitively load()s. Entries are keyed on those content
+// hashes, so editing any file in the load graph invalidates exactly the
+// modules that (transitively) loaded it.
+//
+// The zero of *ModuleCache (nil) disables caching.
+type ModuleCache struct {
+	mu      sync.Mutex
+	entries map[string]moduleCacheEntry
+}
+
+func NewModuleCache() *ModuleCache {
+	return &ModuleCache{entries: make(map[string]moduleCacheEntry)}
+}
+
+type moduleCacheEntry struct {
+	contentHash string
+
+	// Transitively loaded files, in load order, with their content
+	// hashes at the time the entry was stored.
+	depPaths  []string
+	depHashes map[string]string
+
+	exports starlark.StringDict
+}
+
+func (c *ModuleCache) get(path string) (moduleCacheEntry, bool) {
+	if c == nil {
+		return moduleCacheEntry{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[path]
+	return entry, ok
+}
+
+func (c *ModuleCache) put(path string, entry moduleCacheEntry) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[path] = entry
+}
+
+// Tracks the files a module execution reads, so that if the module
+// turns out to be cacheable we know what invalidates it.
+type depRecord struct {
+	paths []string
+	seen  map[string]bool
+}
+
+func newDepRecord() *depRecord {
+	return &depRecord{seen: make(map[string]bool)}
+}
+
+func (r *depRecord) add(paths ...string) {
+	if r == nil {
+		return
+	}
+	for _, p := range paths {
+		if !r.seen[p] {
+			r.seen[p] = true
+			r.paths = append(r.paths, p)
+		}
+	}
+}
+
+// Hash the current contents of a file, respecting the fake file system
+// if one is configured.
+func (e *Environment) hashFile(path string) (string, error) {
+	var contents []byte
+	if e.fakeFileSystem != nil {
+		s, ok := e.fakeFileSystem[path]
+		if !ok {
+			return "", nil
+		}
+		contents = []byte(s)
+	} else {
+		var err error
+		contents, err = ioutil.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+	}
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Whether a cached entry is still valid: the module file and every file
+// it transitively loaded must hash the same as when it was stored.
+func (e *Environment) cacheEntryValid(path string, entry moduleCacheEntry) bool {
+	h, err := e.hashFile(path)
+	if err != nil || h != entry.contentHash {
+		return false
+	}
+	for _, dep := range entry.depPaths {
+		h, err := e.hashFile(dep)
+		if err != nil || h != entry.depHashes[dep] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package starkit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A pure module (no builtin calls) should execute once and be served
+// from the cache on the next execution.
+func TestModuleCacheReusesPureModule(t *testing.T) {
+	f := NewFixture(t)
+	defer f.TearDown()
+	f.SetModuleCache(NewModuleCache())
+
+	f.File("helper/Tiltfile", `
+print("executing helper")
+def double(x):
+  return x * 2
+`)
+	f.File("Tiltfile", `
+load('./helper/Tiltfile', 'double')
+print(double(21))
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	_, err = f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	out := f.PrintOutput()
+	assert.Equal(t, 1, strings.Count(out, "executing helper"))
+	assert.Equal(t, 2, strings.Count(out, "42"))
+}
+
+// Editing the module invalidates the cache entry.
+func TestModuleCacheInvalidatedOnEdit(t *testing.T) {
+	f := NewFixture(t)
+	defer f.TearDown()
+	f.SetModuleCache(NewModuleCache())
+
+	f.File("helper/Tiltfile", `
+x = 1
+`)
+	f.File("Tiltfile", `
+load('./helper/Tiltfile', 'x')
+print(x)
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	f.File("helper/Tiltfile", `
+x = 2
+`)
+	_, err = f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	out := f.PrintOutput()
+	assert.Equal(t, 1, strings.Count(out, "1"))
+	assert.Equal(t, 1, strings.Count(out, "2"))
+}
+
+// Editing a transitively loaded file invalidates the modules that
+// loaded it, even though their own contents didn't change.
+func TestModuleCacheInvalidatedTransitively(t *testing.T) {
+	f := NewFixture(t)
+	defer f.TearDown()
+	f.SetModuleCache(NewModuleCache())
+
+	f.File("leaf/Tiltfile", `
+x = 1
+`)
+	f.File("mid/Tiltfile", `
+load('../leaf/Tiltfile', 'x')
+y = x + 1
+`)
+	f.File("Tiltfile", `
+load('./mid/Tiltfile', 'y')
+print(y)
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	f.File("leaf/Tiltfile", `
+x = 10
+`)
+	_, err = f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	out := f.PrintOutput()
+	assert.Equal(t, 1, strings.Count(out, "2"))
+	assert.Equal(t, 1, strings.Count(out, "11"))
+}
+
+// A module that calls a builtin may have side effects, so it must
+// re-execute every time.
+func TestModuleCacheSkipsImpureModule(t *testing.T) {
+	e := NewExtensionWithIdentifier("hi")
+	f := NewFixture(t, e)
+	defer f.TearDown()
+	f.SetModuleCache(NewModuleCache())
+
+	f.File("helper/Tiltfile", `
+hi()
+x = 1
+`)
+	f.File("Tiltfile", `
+load('./helper/Tiltfile', 'x')
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	_, err = f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, e.callCount)
+}
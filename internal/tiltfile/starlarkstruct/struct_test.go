@@ -21,6 +21,21 @@ print("b",x.b)
 	require.Contains(t, f.PrintOutput(), "b 2")
 }
 
+func TestModule(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+m = module("mymod", a = "foo", b = 2)
+print("a",m.a)
+print("b",m.b)
+print(m)
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	require.Contains(t, f.PrintOutput(), "a foo")
+	require.Contains(t, f.PrintOutput(), "b 2")
+	require.Contains(t, f.PrintOutput(), "<module \"mymod\">")
+}
+
 func NewFixture(tb testing.TB) *starkit.Fixture {
 	return starkit.NewFixture(tb, NewExtension())
 }
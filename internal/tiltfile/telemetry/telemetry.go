@@ -2,6 +2,7 @@ package telemetry
 
 import (
 	"fmt"
+	"net/url"
 	"path/filepath"
 
 	"go.starlark.net/starlark"
@@ -22,7 +23,11 @@ func (e Extension) NewState() interface{} {
 }
 
 func (Extension) OnStart(env *starkit.Environment) error {
-	return env.AddBuiltin("experimental_telemetry_cmd", setTelemetryCmd)
+	err := env.AddBuiltin("experimental_telemetry_cmd", setTelemetryCmd)
+	if err != nil {
+		return err
+	}
+	return env.AddBuiltin("experimental_telemetry_endpoint", setTelemetryEndpoint)
 }
 
 func setTelemetryCmd(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
@@ -45,6 +50,9 @@ func setTelemetryCmd(thread *starlark.Thread, fn *starlark.Builtin, args starlar
 		if len(settings.Cmd.Argv) > 0 {
 			return settings, fmt.Errorf("%v called multiple times; already set to %v", fn.Name(), settings.Cmd)
 		}
+		if settings.Endpoint != "" {
+			return settings, fmt.Errorf("%v cannot be used together with experimental_telemetry_endpoint", fn.Name())
+		}
 
 		settings.Cmd = cmd
 		settings.Workdir = filepath.Dir(starkit.CurrentExecPath(thread))
@@ -59,6 +67,42 @@ func setTelemetryCmd(thread *starlark.Thread, fn *starlark.Builtin, args starlar
 	return starlark.None, nil
 }
 
+func setTelemetryEndpoint(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var endpoint string
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "endpoint", &endpoint)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	if endpoint == "" {
+		return starlark.None, fmt.Errorf("endpoint cannot be empty")
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return starlark.None, fmt.Errorf("endpoint must be an http(s) URL: %v", endpoint)
+	}
+
+	err = starkit.SetState(thread, func(settings model.TelemetrySettings) (model.TelemetrySettings, error) {
+		if settings.Endpoint != "" {
+			return settings, fmt.Errorf("%v called multiple times; already set to %v", fn.Name(), settings.Endpoint)
+		}
+		if len(settings.Cmd.Argv) > 0 {
+			return settings, fmt.Errorf("%v cannot be used together with experimental_telemetry_cmd", fn.Name())
+		}
+
+		settings.Endpoint = endpoint
+
+		return settings, nil
+	})
+
+	if err != nil {
+		return starlark.None, err
+	}
+
+	return starlark.None, nil
+}
+
 var _ starkit.StatefulExtension = Extension{}
 
 func MustState(model starkit.Model) model.TelemetrySettings {
@@ -50,6 +50,36 @@ experimental_telemetry_cmd('bar.sh')
 	assert.EqualError(t, err, "experimental_telemetry_cmd called multiple times; already set to foo.sh")
 }
 
+func TestTelemetryEndpoint(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+	f.File("Tiltfile", "experimental_telemetry_endpoint('https://collector.example.com/v1/spans')")
+	result, err := f.ExecFile("Tiltfile")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://collector.example.com/v1/spans", MustState(result).Endpoint)
+}
+
+func TestTelemetryEndpointInvalid(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+	f.File("Tiltfile", "experimental_telemetry_endpoint('collector.example.com')")
+	_, err := f.ExecFile("Tiltfile")
+
+	assert.EqualError(t, err, "endpoint must be an http(s) URL: collector.example.com")
+}
+
+func TestTelemetryEndpointAndCmd(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+	f.File("Tiltfile", `
+experimental_telemetry_cmd('foo.sh')
+experimental_telemetry_endpoint('https://collector.example.com')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	assert.EqualError(t, err, "experimental_telemetry_endpoint cannot be used together with experimental_telemetry_cmd")
+}
+
 func newFixture(tb testing.TB) *starkit.Fixture {
 	return starkit.NewFixture(tb, NewExtension())
 }
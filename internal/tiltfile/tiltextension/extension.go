@@ -54,13 +54,21 @@ func (e *Extension) LocalPath(t *starlark.Thread, arg string) (string, error) {
 	}
 
 	moduleName := strings.TrimPrefix(arg, extensionPrefix)
+	name, pin := parseNamePin(moduleName)
+
 	// If the module can't be found we fetch it below
-	localPath, err := e.store.ModulePath(ctx, moduleName)
+	localPath, err := e.store.ModulePath(ctx, name)
 	if err != nil && !os.IsNotExist(err) {
 		return "", err
 	}
 	if localPath != "" {
-		return localPath, nil
+		ok, err := e.cachedModuleMatchesPin(ctx, name, pin)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return localPath, nil
+		}
 	}
 
 	contents, err := e.fetcher.Fetch(ctx, moduleName)
@@ -71,5 +79,38 @@ func (e *Extension) LocalPath(t *starlark.Thread, arg string) (string, error) {
 	return e.store.Write(ctx, contents)
 }
 
+// An unpinned load is satisfied by whatever version is already on disk.
+// A pinned load ("ext://module@ref") is only satisfied if the cached copy
+// was fetched at that ref; otherwise we re-fetch.
+func (e *Extension) cachedModuleMatchesPin(ctx context.Context, name string, pin string) (bool, error) {
+	if pin == "" {
+		return true, nil
+	}
+
+	metadata, err := e.store.Metadata(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	if metadata == nil {
+		// A module vendored by hand, with no metadata. Trust it.
+		return true, nil
+	}
+	return refMatches(metadata.GitCommitHash, pin), nil
+}
+
+// Split a module name from its optional pin ("module@ref").
+func parseNamePin(moduleName string) (name string, pin string) {
+	idx := strings.Index(moduleName, "@")
+	if idx == -1 {
+		return moduleName, ""
+	}
+	return moduleName[:idx], moduleName[idx+1:]
+}
+
+// A pin can be a full commit SHA, an abbreviated one, or a tag/branch name.
+func refMatches(fetchedAt string, pin string) bool {
+	return fetchedAt == pin || (len(pin) >= 7 && strings.HasPrefix(fetchedAt, pin))
+}
+
 var _ starkit.LoadInterceptor = (*Extension)(nil)
 var _ starkit.Extension = (*Extension)(nil)
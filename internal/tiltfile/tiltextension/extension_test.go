@@ -61,6 +61,61 @@ printFoo()
 	f.assertError("unfetchable can't be fetched")
 }
 
+func TestFetchPinned(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.tiltfile(`
+load("ext://fetchable@abc1234", "printFoo")
+printFoo()
+`)
+
+	f.assertExecOutput("foo")
+	if f.fetcher.fetchCount != 1 {
+		t.Fatalf("expected 1 fetch; got %d", f.fetcher.fetchCount)
+	}
+}
+
+func TestPinnedMatchingCacheNotRefetched(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.storeModule(ModuleContents{
+		Name:             "fetchable",
+		TiltfileContents: libText,
+		GitCommitHash:    "abc1234def5678",
+	})
+	f.tiltfile(`
+load("ext://fetchable@abc1234", "printFoo")
+printFoo()
+`)
+
+	f.assertExecOutput("foo")
+	if f.fetcher.fetchCount != 0 {
+		t.Fatalf("expected no fetches; got %d", f.fetcher.fetchCount)
+	}
+}
+
+func TestPinnedMismatchRefetched(t *testing.T) {
+	f := newExtensionFixture(t)
+	defer f.tearDown()
+
+	f.storeModule(ModuleContents{
+		Name:             "fetchable",
+		TiltfileContents: libText,
+		GitCommitHash:    "oldoldold",
+	})
+	f.tiltfile(`
+load("ext://fetchable@newnew1", "printFoo")
+printFoo()
+`)
+
+	f.assertExecOutput("foo")
+	if f.fetcher.fetchCount != 1 {
+		t.Fatalf("expected 1 fetch; got %d", f.fetcher.fetchCount)
+	}
+}
+
 func TestExtensionCantIncludeExtension(t *testing.T) {
 	f := newExtensionFixture(t)
 	defer f.tearDown()
@@ -75,24 +130,27 @@ printFoo()
 }
 
 type extensionFixture struct {
-	t   *testing.T
-	skf *starkit.Fixture
-	tmp *tempdir.TempDirFixture
+	t       *testing.T
+	skf     *starkit.Fixture
+	tmp     *tempdir.TempDirFixture
+	fetcher *fakeFetcher
+	store   *LocalStore
 }
 
 func newExtensionFixture(t *testing.T) *extensionFixture {
 	tmp := tempdir.NewTempDirFixture(t)
-	ext := NewExtension(
-		&fakeFetcher{},
-		NewLocalStore(tmp.JoinPath("project")),
-	)
+	fetcher := &fakeFetcher{}
+	store := NewLocalStore(tmp.JoinPath("project"))
+	ext := NewExtension(fetcher, store)
 	skf := starkit.NewFixture(t, ext)
 	skf.UseRealFS()
 
 	return &extensionFixture{
-		t:   t,
-		skf: skf,
-		tmp: tmp,
+		t:       t,
+		skf:     skf,
+		tmp:     tmp,
+		fetcher: fetcher,
+		store:   store,
 	}
 }
 
@@ -129,6 +187,13 @@ func (f *extensionFixture) writeModuleLocally(name string, contents string) {
 	f.tmp.WriteFile(filepath.Join("project", "tilt_modules", name, "Tiltfile"), contents)
 }
 
+func (f *extensionFixture) storeModule(contents ModuleContents) {
+	_, err := f.store.Write(context.Background(), contents)
+	if err != nil {
+		f.t.Fatalf("unexpected error %v", err)
+	}
+}
+
 const libText = `
 def printFoo():
   print("foo")
@@ -142,14 +207,22 @@ def printFoo():
 	printBar()
 `
 
-type fakeFetcher struct{}
+type fakeFetcher struct {
+	fetchCount int
+}
 
 func (f *fakeFetcher) Fetch(ctx context.Context, moduleName string) (ModuleContents, error) {
-	if moduleName != "fetchable" {
+	f.fetchCount++
+	name, pin := parseNamePin(moduleName)
+	if name != "fetchable" {
 		return ModuleContents{}, fmt.Errorf("module %s can't be fetched because... reasons", moduleName)
 	}
+	if pin == "" {
+		pin = "aaaa1111aaaa1111"
+	}
 	return ModuleContents{
 		Name:             "fetchable",
 		TiltfileContents: libText,
+		GitCommitHash:    pin,
 	}, nil
 }
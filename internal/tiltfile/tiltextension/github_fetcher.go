@@ -5,42 +5,76 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/google/go-github/v29/github"
 
+	"github.com/windmilleng/tilt/internal/tiltfile/sandbox"
 	pkgtiltextension "github.com/windmilleng/tilt/pkg/tiltextension"
 )
 
+// The GitHub repo to fetch extensions from, as "owner/name".
+// Most people want the default community repo, but orgs can point this
+// at their own fork to curate what their Tiltfiles can load.
+const extensionRepoEnvName = "TILT_EXTENSION_REPO"
+
+const defaultExtensionRepoOwner = "windmilleng"
+const defaultExtensionRepoName = "tilt-extensions"
+
 type GithubFetcher struct {
+	owner string
+	repo  string
 }
 
 // TODO(dmiller): DI github
 // TODO(dmiller): DI HTTP client
 func NewGithubFetcher() *GithubFetcher {
-	return &GithubFetcher{}
+	owner, repo := defaultExtensionRepoOwner, defaultExtensionRepoName
+	if v := os.Getenv(extensionRepoEnvName); v != "" {
+		parts := strings.SplitN(v, "/", 2)
+		if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+			owner, repo = parts[0], parts[1]
+		}
+	}
+	return &GithubFetcher{owner: owner, repo: repo}
 }
 
-const githubTemplate = "https://raw.githubusercontent.com/windmilleng/tilt-extensions/%s/%s/Tiltfile"
+const githubTemplate = "https://raw.githubusercontent.com/%s/%s/%s/%s/Tiltfile"
 
+// Fetch a module. The module name may carry a pin ("module@ref") naming
+// the commit, tag, or branch to fetch; unpinned modules fetch the head
+// of master.
 func (f *GithubFetcher) Fetch(ctx context.Context, moduleName string) (ModuleContents, error) {
-	client := github.NewClient(nil)
-	masterBranch, _, err := client.Repositories.GetBranch(ctx, "windmilleng", "tilt-extensions", "master")
-	if err != nil {
-		return ModuleContents{}, err
+	if !sandbox.NetworkAllowed() {
+		return ModuleContents{}, fmt.Errorf("cannot fetch extension %q: network access is disabled (%s)",
+			moduleName, sandbox.DisableNetworkEnvName)
 	}
-	headOfMaster := masterBranch.GetCommit()
-	masterSHA := headOfMaster.GetSHA()
 
-	err = pkgtiltextension.ValidateName(moduleName)
+	name, pin := parseNamePin(moduleName)
+
+	err := pkgtiltextension.ValidateName(name)
 	if err != nil {
 		return ModuleContents{}, err
 	}
+
+	ref := pin
+	if ref == "" {
+		client := github.NewClient(nil)
+		masterBranch, _, err := client.Repositories.GetBranch(ctx, f.owner, f.repo, "master")
+		if err != nil {
+			return ModuleContents{}, err
+		}
+		headOfMaster := masterBranch.GetCommit()
+		ref = headOfMaster.GetSHA()
+	}
+
 	c := &http.Client{
 		Timeout: 20 * time.Second,
 	}
 
-	urlText := fmt.Sprintf(githubTemplate, masterSHA, moduleName)
+	urlText := fmt.Sprintf(githubTemplate, f.owner, f.repo, ref, name)
 	resp, err := c.Get(urlText)
 	if err != nil {
 		return ModuleContents{}, err
@@ -58,10 +92,10 @@ func (f *GithubFetcher) Fetch(ctx context.Context, moduleName string) (ModuleCon
 	}
 
 	return ModuleContents{
-		Name:              moduleName,
+		Name:              name,
 		TiltfileContents:  string(body),
-		GitCommitHash:     masterSHA,
-		ExtensionRegistry: "https://github.com/windmilleng/tilt-extensions",
+		GitCommitHash:     ref,
+		ExtensionRegistry: fmt.Sprintf("https://github.com/%s/%s", f.owner, f.repo),
 		TimeFetched:       time.Now(),
 	}, nil
 }
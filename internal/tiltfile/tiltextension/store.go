@@ -19,6 +19,9 @@ type Store interface {
 	// ModulePath is used to check if an extension exists before fetching it
 	// Returns ErrNotExist if module doesn't exist
 	ModulePath(ctx context.Context, moduleName string) (string, error)
+	// Metadata returns what we recorded about a module when we fetched it,
+	// or nil if we have no record of it.
+	Metadata(ctx context.Context, moduleName string) (*Metadata, error)
 	Write(ctx context.Context, contents ModuleContents) (string, error)
 }
 
@@ -63,11 +66,48 @@ func (s *LocalStore) ModulePath(ctx context.Context, moduleName string) (string,
 	return tiltfilePath, nil
 }
 
+func (s *LocalStore) Metadata(ctx context.Context, moduleName string) (*Metadata, error) {
+	metadataFile, err := s.readMetadataFile()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range metadataFile.Extensions {
+		if e.Name == moduleName {
+			e := e
+			return &e, nil
+		}
+	}
+	return nil, nil
+}
+
+// Every extension we've fetched, in the order they appear in the
+// metadata file.
+func (s *LocalStore) List(ctx context.Context) ([]Metadata, error) {
+	metadataFile, err := s.readMetadataFile()
+	if err != nil {
+		return nil, err
+	}
+	return metadataFile.Extensions, nil
+}
+
+func (s *LocalStore) readMetadataFile() (MetadataFile, error) {
+	var metadataFile MetadataFile
+	extensionMetadataFilePath := filepath.Join(s.baseDir, metadataFileName)
+	b, err := ioutil.ReadFile(extensionMetadataFilePath)
+	if os.IsNotExist(err) {
+		return MetadataFile{}, nil
+	} else if err != nil {
+		return MetadataFile{}, errors.Wrapf(err, "unable to open extension metadata file at path %s", extensionMetadataFilePath)
+	}
+	err = json.Unmarshal(b, &metadataFile)
+	if err != nil {
+		return MetadataFile{}, errors.Wrapf(err, "Unable to unmarshal metadata file at path %s", extensionMetadataFilePath)
+	}
+	return metadataFile, nil
+}
+
 // TODO(dmiller): handle atomic writes to the metadata file and the modules?
 // Right now if a write to the metadata file fails the module will still be written
-
-// TODO(dmiller): should this error if we try to write an extension with the same name as
-// one that already exists?
 func (s *LocalStore) Write(ctx context.Context, contents ModuleContents) (string, error) {
 	moduleDir := filepath.Join(s.baseDir, contents.Name)
 	if err := os.MkdirAll(moduleDir, os.FileMode(0700)); err != nil {
@@ -86,24 +126,26 @@ func (s *LocalStore) Write(ctx context.Context, contents ModuleContents) (string
 		TimeFetched:       contents.TimeFetched,
 	}
 
-	// read file if it exists, append extension, write out the file
-	var metadataFile MetadataFile
-	extensionMetadataFilePath := filepath.Join(s.baseDir, metadataFileName)
-	b, err := ioutil.ReadFile(extensionMetadataFilePath)
-	if os.IsNotExist(err) {
-		metadataFile = MetadataFile{
-			Extensions: []Metadata{metadata},
-		}
-	} else if err != nil {
-		return "", errors.Wrapf(err, "unable to open extension metadata file at path %s", extensionMetadataFilePath)
-	} else {
-		err = json.Unmarshal(b, &metadataFile)
-		if err != nil {
-			return "", errors.Wrapf(err, "Unable to unmarshal metadata file at path %s", extensionMetadataFilePath)
+	// Read the file if it exists, upsert the extension, and write the file
+	// back out. Re-fetching a module (e.g., `tilt ext update`) replaces its
+	// entry rather than appending a duplicate.
+	metadataFile, err := s.readMetadataFile()
+	if err != nil {
+		return "", err
+	}
+	replaced := false
+	for i, e := range metadataFile.Extensions {
+		if e.Name == metadata.Name {
+			metadataFile.Extensions[i] = metadata
+			replaced = true
+			break
 		}
+	}
+	if !replaced {
 		metadataFile.Extensions = append(metadataFile.Extensions, metadata)
 	}
 
+	extensionMetadataFilePath := filepath.Join(s.baseDir, metadataFileName)
 	js, err := json.MarshalIndent(metadataFile, "", "  ")
 	if err != nil {
 		return "", errors.Wrap(err, "internal error: unable to marshal metadataFile as JSON")
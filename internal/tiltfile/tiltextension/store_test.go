@@ -52,6 +52,32 @@ func TestStatModuleDoesntExist(t *testing.T) {
 	f.assertModulePathDoesntExist("test")
 }
 
+func TestWriteSameModuleTwiceReplacesMetadata(t *testing.T) {
+	f := newFixture(t)
+	defer f.tearDown()
+
+	f.writeModule(ModuleContents{
+		Name:              "test",
+		TiltfileContents:  "print('hi')",
+		GitCommitHash:     "aaaaaa",
+		ExtensionRegistry: "https://github.com/windmill/tilt-extensions",
+	})
+	f.writeModule(ModuleContents{
+		Name:              "test",
+		TiltfileContents:  "print('bye')",
+		GitCommitHash:     "bbbbbb",
+		ExtensionRegistry: "https://github.com/windmill/tilt-extensions",
+	})
+
+	// assertExtension fails if "test" appears twice in the metadata file.
+	f.assertExtension("test", "print('bye')", "bbbbbb", "https://github.com/windmill/tilt-extensions")
+
+	md, err := f.store.Metadata(f.ctx, "test")
+	require.NoError(f.t, err)
+	require.NotNil(f.t, md)
+	assert.Equal(f.t, "bbbbbb", md.GitCommitHash)
+}
+
 func TestTwoExtensions(t *testing.T) {
 	f := newFixture(t)
 	defer f.tearDown()
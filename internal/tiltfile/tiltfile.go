@@ -13,6 +13,9 @@ import (
 	"go.starlark.net/resolve"
 	"go.starlark.net/starlark"
 
+	"github.com/windmilleng/tilt/internal/tiltfile/notifysettings"
+	"github.com/windmilleng/tilt/internal/tiltfile/securitysettings"
+	"github.com/windmilleng/tilt/internal/tiltfile/signing"
 	"github.com/windmilleng/tilt/internal/tiltfile/updatesettings"
 
 	"github.com/windmilleng/tilt/internal/analytics"
@@ -25,6 +28,7 @@ import (
 	"github.com/windmilleng/tilt/internal/tiltfile/dockerprune"
 	"github.com/windmilleng/tilt/internal/tiltfile/io"
 	"github.com/windmilleng/tilt/internal/tiltfile/k8scontext"
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
 	"github.com/windmilleng/tilt/internal/tiltfile/telemetry"
 	"github.com/windmilleng/tilt/internal/tiltfile/value"
 	"github.com/windmilleng/tilt/internal/tiltfile/version"
@@ -54,17 +58,24 @@ type TiltfileLoadResult struct {
 	AnalyticsOpt        wmanalytics.Opt
 	VersionSettings     model.VersionSettings
 	UpdateSettings      model.UpdateSettings
+	NotifySettings      model.NotifySettings
+	SecuritySettings    model.SecuritySettings
+	SigningSettings     model.SigningSettings
+	PrePullImages       []string
 }
 
 func (r TiltfileLoadResult) Orchestrator() model.Orchestrator {
+	orc := model.OrchestratorUnknown
 	for _, manifest := range r.Manifests {
 		if manifest.IsK8s() {
+			// Mixed-mode sessions answer for the cluster: compose builds
+			// always go to the local Docker daemon regardless.
 			return model.OrchestratorK8s
 		} else if manifest.IsDC() {
-			return model.OrchestratorDC
+			orc = model.OrchestratorDC
 		}
 	}
-	return model.OrchestratorUnknown
+	return orc
 }
 
 type TiltfileLoader interface {
@@ -116,6 +127,7 @@ func ProvideTiltfileLoader(
 		webHost:       webHost,
 		fDefaults:     fDefaults,
 		env:           env,
+		moduleCache:   starkit.NewModuleCache(),
 	}
 }
 
@@ -129,6 +141,10 @@ type tiltfileLoader struct {
 	versionExt    version.Extension
 	fDefaults     feature.Defaults
 	env           k8s.Env
+
+	// Shared across Loads so pure load()ed modules aren't re-executed
+	// on every Tiltfile re-execution.
+	moduleCache *starkit.ModuleCache
 }
 
 var _ TiltfileLoader = &tiltfileLoader{}
@@ -169,6 +185,7 @@ func (tfl tiltfileLoader) Load(ctx context.Context, filename string, userConfigS
 	localRegistry := tfl.kCli.LocalRegistry(ctx)
 
 	s := newTiltfileState(ctx, tfl.dcCli, tfl.webHost, tfl.k8sContextExt, tfl.versionExt, localRegistry, feature.FromDefaults(tfl.fDefaults))
+	s.moduleCache = tfl.moduleCache
 
 	manifests, result, err := s.loadManifests(absFilename, userConfigState)
 
@@ -186,6 +203,7 @@ func (tfl tiltfileLoader) Load(ctx context.Context, filename string, userConfigS
 	tlr.Error = err
 	tlr.Manifests = manifests
 	tlr.TeamID = s.teamID
+	tlr.PrePullImages = s.prePullImages
 
 	vs, _ := version.GetState(result)
 	tlr.VersionSettings = vs
@@ -196,6 +214,18 @@ func (tfl tiltfileLoader) Load(ctx context.Context, filename string, userConfigS
 	us, _ := updatesettings.GetState(result)
 	tlr.UpdateSettings = us
 
+	ns, _ := notifysettings.GetState(result)
+	tlr.NotifySettings = ns
+
+	sgs, _ := signing.GetState(result)
+	tlr.SigningSettings = sgs
+
+	ss, _ := securitysettings.GetState(result)
+	tlr.SecuritySettings = ss.SecuritySettings
+	if tlr.Error == nil {
+		tlr.Error = s.validateSecuritySettings(ss)
+	}
+
 	duration := time.Since(start)
 	s.logger.Infof("Successfully loaded Tiltfile (%s)", duration)
 	tfl.reportTiltfileLoaded(s.builtinCallCounts, s.builtinArgCounts, duration)
@@ -162,7 +162,30 @@ docker_compose('docker-compose2.yml')`
 	f.loadErrString("already have a docker-compose resource declared")
 }
 
-func TestDockerComposeAndK8sNotSupported(t *testing.T) {
+func TestDockerComposeAndK8sMixed(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.dockerfile(filepath.Join("bar", "Dockerfile"))
+	f.yaml("bar.yaml", deployment("bar", image("gcr.io/bar")))
+	f.gitInit("")
+	f.file("docker-compose.yml", simpleConfig)
+	tf := `docker_compose('docker-compose.yml')
+docker_build('gcr.io/bar', 'bar')
+k8s_yaml('bar.yaml')
+k8s_resource('bar', resource_deps=['foo'])`
+	f.file("Tiltfile", tf)
+
+	f.load()
+	f.assertNextManifest("bar",
+		db(image("gcr.io/bar")),
+		deployment("bar"),
+		resourceDeps("foo"))
+	f.assertDcManifest("foo")
+}
+
+func TestDockerComposeAndK8sNameConflict(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
@@ -173,8 +196,7 @@ docker_build('gcr.io/foo', 'foo')
 k8s_yaml('foo.yaml')`
 	f.file("Tiltfile", tf)
 
-	f.loadErrString("can't declare both k8s " +
-		"resources/entities and docker-compose resources")
+	f.loadErrString("resource name conflict: foo is defined multiple times")
 }
 
 func TestDockerComposeResourceCreationFromAbsPath(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/docker/distribution/reference"
 	"github.com/looplab/tarjan"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/windmilleng/tilt/internal/container"
 	"github.com/windmilleng/tilt/internal/dockercompose"
+	"github.com/windmilleng/tilt/internal/dockerfile"
 	"github.com/windmilleng/tilt/internal/feature"
 	"github.com/windmilleng/tilt/internal/k8s"
 	"github.com/windmilleng/tilt/internal/ospath"
@@ -24,10 +26,16 @@ import (
 	"github.com/windmilleng/tilt/internal/tiltfile/dockerprune"
 	"github.com/windmilleng/tilt/internal/tiltfile/encoding"
 	"github.com/windmilleng/tilt/internal/tiltfile/git"
+	"github.com/windmilleng/tilt/internal/tiltfile/http"
 	"github.com/windmilleng/tilt/internal/tiltfile/include"
 	"github.com/windmilleng/tilt/internal/tiltfile/io"
 	"github.com/windmilleng/tilt/internal/tiltfile/k8scontext"
+	"github.com/windmilleng/tilt/internal/tiltfile/notifysettings"
 	"github.com/windmilleng/tilt/internal/tiltfile/os"
+	"github.com/windmilleng/tilt/internal/tiltfile/sandbox"
+	"github.com/windmilleng/tilt/internal/tiltfile/securitysettings"
+	"github.com/windmilleng/tilt/internal/tiltfile/shared"
+	"github.com/windmilleng/tilt/internal/tiltfile/signing"
 	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
 	"github.com/windmilleng/tilt/internal/tiltfile/starlarkstruct"
 	"github.com/windmilleng/tilt/internal/tiltfile/telemetry"
@@ -36,6 +44,7 @@ import (
 	"github.com/windmilleng/tilt/internal/tiltfile/version"
 	"github.com/windmilleng/tilt/pkg/logger"
 	"github.com/windmilleng/tilt/pkg/model"
+	pluginapi "github.com/windmilleng/tilt/pkg/starkit"
 )
 
 type resourceSet struct {
@@ -52,8 +61,13 @@ type tiltfileState struct {
 	versionExt    version.Extension
 	localRegistry container.Registry
 	features      feature.FeatureSet
+	limits        sandbox.Limits
+	moduleCache   *starkit.ModuleCache
 
 	// added to during execution
+	execStartTime      time.Time
+	localCallCount     int
+	pendingFutures     []*future
 	buildIndex         *buildIndex
 	k8s                []*k8sResource
 	k8sByName          map[string]*k8sResource
@@ -61,10 +75,27 @@ type tiltfileState struct {
 	dc                 dcResourceSet // currently only support one d-c.yml
 	k8sResourceOptions map[string]k8sResourceOptions
 	localResources     []localResource
+	cmdButtons         []cmdButton
 
 	// ensure that any images are pushed to/pulled from this registry, rewriting names if needed
 	defaultReg container.Registry
 
+	// pull Docker Hub images through this registry mirror, both for base
+	// images in docker builds and for images in deployed YAML
+	mirrorRegistry string
+
+	// deploy all objects into this per-developer namespace
+	devNamespace string
+
+	devOverridesEnabled bool
+	devOverridesExclude []string
+
+	// how to name the tags of built images
+	tagStrategy model.TagStrategy
+
+	// images to pull at startup, before any builds run
+	prePullImages []string
+
 	// JSON paths to images in k8s YAML (other than Container specs)
 	k8sImageJSONPaths map[k8sObjectSelector][]k8s.JSONPath
 	// objects of these types are considered workloads, whether or not they have an image
@@ -98,6 +129,10 @@ type tiltfileState struct {
 
 	teamID string
 
+	// Secrets registered by the secret() and sensitive() builtins,
+	// in addition to the ones extracted from k8s Secret objects.
+	secrets model.SecretSet
+
 	logger                           logger.Logger
 	warnedDeprecatedResourceAssembly bool
 	postExecReadFiles                []string
@@ -127,6 +162,7 @@ func newTiltfileState(
 		k8sContextExt:              k8sContextExt,
 		versionExt:                 versionExt,
 		localRegistry:              localRegistry,
+		limits:                     sandbox.FromEnv(),
 		buildIndex:                 newBuildIndex(),
 		k8sByName:                  make(map[string]*k8sResource),
 		k8sImageJSONPaths:          make(map[k8sObjectSelector][]k8s.JSONPath),
@@ -138,6 +174,7 @@ func newTiltfileState(
 		k8sResourceAssemblyVersion: 2,
 		k8sResourceOptions:         make(map[string]k8sResourceOptions),
 		localResources:             []localResource{},
+		secrets:                    model.SecretSet{},
 		triggerMode:                TriggerModeAuto,
 		features:                   features,
 	}
@@ -155,12 +192,13 @@ func (s *tiltfileState) print(_ *starlark.Thread, msg string) {
 //
 // TODO(nick): Eventually this will just return a starkit.Model, which will contain
 // all the mutable state collected by execution.
-func (s *tiltfileState) loadManifests(absFilename string, userConfigState model.UserConfigState) ([]model.Manifest, starkit.Model, error) {
-	s.logger.Infof("Beginning Tiltfile execution")
-	result, err := starkit.ExecFile(absFilename,
+// The full set of starkit extensions a Tiltfile executes with.
+func (s *tiltfileState) extensions(userConfigState model.UserConfigState, tiltfileDir string) []starkit.Extension {
+	extensions := []starkit.Extension{
 		s,
 		include.IncludeFn{},
 		git.NewExtension(),
+		http.NewExtension(),
 		os.NewExtension(),
 		io.NewExtension(),
 		s.k8sContextExt,
@@ -171,14 +209,48 @@ func (s *tiltfileState) loadManifests(absFilename string, userConfigState model.
 		starlarkstruct.NewExtension(),
 		telemetry.NewExtension(),
 		updatesettings.NewExtension(),
+		notifysettings.NewExtension(),
+		securitysettings.NewExtension(),
+		signing.NewExtension(),
 		encoding.NewExtension(),
-		tiltextension.NewExtension(tiltextension.NewGithubFetcher(), tiltextension.NewLocalStore(filepath.Dir(absFilename))),
-	)
+		shared.NewExtension(),
+		tiltextension.NewExtension(tiltextension.NewGithubFetcher(), tiltextension.NewLocalStore(tiltfileDir)),
+	}
+
+	// Out-of-tree Go extensions, linked in with the tiltplugins build tag.
+	return append(extensions, pluginapi.RegisteredExtensions()...)
+}
+
+func (s *tiltfileState) loadManifests(absFilename string, userConfigState model.UserConfigState) ([]model.Manifest, starkit.Model, error) {
+	s.logger.Infof("Beginning Tiltfile execution")
+	s.execStartTime = time.Now()
+	extensions := s.extensions(userConfigState, filepath.Dir(absFilename))
+
+	result, err := starkit.ExecFileWithCache(s.moduleCache, absFilename, extensions...)
+
+	// Don't let background commands started with parallel=True outlive
+	// execution, and fail on any error the Tiltfile never waited on.
+	futureErr := s.waitPendingFutures()
 	if err != nil {
 		return nil, result, starkit.UnpackBacktrace(err)
 	}
+	if futureErr != nil {
+		return nil, result, futureErr
+	}
 
-	resources, unresourced, err := s.assemble()
+	us, _ := updatesettings.GetState(result)
+	resources, unresourced, err := s.assemble(us)
+	if err != nil {
+		return nil, result, err
+	}
+
+	configSettings, _ := config.GetState(result)
+
+	// If the user narrowed the session to a subset of resources, drop the
+	// rest before translation, so we don't do image resolution and watch
+	// setup for resources that won't run.
+	hasK8sResources := len(resources.k8s) > 0
+	err = s.filterUnselectedResources(configSettings, &resources)
 	if err != nil {
 		return nil, result, err
 	}
@@ -189,7 +261,7 @@ func (s *tiltfileState) loadManifests(absFilename string, userConfigState model.
 		return nil, result, err
 	}
 
-	if len(resources.k8s) > 0 {
+	if hasK8sResources {
 		manifests, err = s.translateK8s(resources.k8s)
 		if err != nil {
 			return nil, result, err
@@ -203,11 +275,25 @@ If you're sure you want to deploy there, add:
 allow_k8s_contexts('%s')
 to your Tiltfile. Otherwise, switch k8s contexts and restart Tilt.`, kubeContext, kubeContext)
 		}
-	} else {
-		manifests, err = s.translateDC(resources.dc)
+
+		for _, m := range manifests {
+			kubeContext := k8s.KubeContext(m.K8sTarget().KubeContext)
+			if kubeContext == "" || k8sContextState.IsAllowedContext(kubeContext) {
+				continue
+			}
+			return nil, result, fmt.Errorf(`Stop! Resource %q deploys to context %q, which might be production.
+If you're sure you want to deploy there, add:
+allow_k8s_contexts('%s')
+to your Tiltfile.`, m.Name, kubeContext, kubeContext)
+		}
+	}
+
+	if len(resources.dc.services) > 0 {
+		dcManifests, err := s.translateDC(resources.dc)
 		if err != nil {
 			return nil, result, err
 		}
+		manifests = append(manifests, dcManifests...)
 	}
 
 	err = s.checkForUnconsumedLiveUpdateSteps()
@@ -221,7 +307,6 @@ to your Tiltfile. Otherwise, switch k8s contexts and restart Tilt.`, kubeContext
 	}
 	manifests = append(manifests, localManifests...)
 
-	configSettings, _ := config.GetState(result)
 	manifests, err = configSettings.EnabledResources(manifests)
 	if err != nil {
 		return nil, starkit.Model{}, err
@@ -235,14 +320,82 @@ to your Tiltfile. Otherwise, switch k8s contexts and restart Tilt.`, kubeContext
 		manifests = append(manifests, yamlManifest)
 	}
 
+	err = validateUniqueManifestNames(manifests)
+	if err != nil {
+		return nil, starkit.Model{}, err
+	}
+
 	err = validateResourceDependencies(manifests)
 	if err != nil {
 		return nil, starkit.Model{}, err
 	}
 
+	manifests, err = s.applyCmdButtons(manifests)
+	if err != nil {
+		return nil, starkit.Model{}, err
+	}
+
 	return manifests, result, nil
 }
 
+// filterUnselectedResources drops resources (and local resources) the
+// user didn't select with set_enabled_resources or args, keeping their
+// transitive resource_deps, so they never get translated to manifests.
+func (s *tiltfileState) filterUnselectedResources(configSettings config.Settings, resources *resourceSet) error {
+	var names []model.ManifestName
+	deps := make(map[model.ManifestName][]model.ManifestName)
+	record := func(name string, resourceDeps []string) {
+		mn := model.ManifestName(name)
+		names = append(names, mn)
+		for _, d := range resourceDeps {
+			deps[mn] = append(deps[mn], model.ManifestName(d))
+		}
+	}
+	for _, r := range resources.k8s {
+		record(r.name, r.resourceDeps)
+	}
+	for _, svc := range resources.dc.services {
+		record(svc.Name, svc.resourceDeps)
+	}
+	for _, lr := range s.localResources {
+		record(lr.name, lr.resourceDeps)
+	}
+
+	selected, err := configSettings.SelectedResources(names, deps)
+	if err != nil {
+		return err
+	}
+	if selected == nil {
+		return nil
+	}
+
+	k8sResources := make([]*k8sResource, 0, len(resources.k8s))
+	for _, r := range resources.k8s {
+		if selected[model.ManifestName(r.name)] {
+			k8sResources = append(k8sResources, r)
+		}
+	}
+	resources.k8s = k8sResources
+
+	services := make([]*dcService, 0, len(resources.dc.services))
+	for _, svc := range resources.dc.services {
+		if selected[model.ManifestName(svc.Name)] {
+			services = append(services, svc)
+		}
+	}
+	resources.dc.services = services
+
+	localResources := make([]localResource, 0, len(s.localResources))
+	for _, lr := range s.localResources {
+		if selected[model.ManifestName(lr.name)] {
+			localResources = append(localResources, lr)
+		}
+	}
+	s.localResources = localResources
+
+	return nil
+}
+
 // Builtin functions
 
 const (
@@ -251,6 +404,9 @@ const (
 	fastBuildN       = "fast_build"
 	customBuildN     = "custom_build"
 	defaultRegistryN = "default_registry"
+	registryMirrorN  = "registry_mirror"
+	imageTagSchemeN  = "image_tag_scheme"
+	prePullN         = "pre_pull"
 
 	// docker compose functions
 	dockerComposeN = "docker_compose"
@@ -262,10 +418,13 @@ const (
 	filterYamlN                 = "filter_yaml"
 	k8sResourceN                = "k8s_resource"
 	localResourceN              = "local_resource"
+	remoteResourceN             = "remote_resource"
 	portForwardN                = "port_forward"
 	k8sKindN                    = "k8s_kind"
 	k8sImageJSONPathN           = "k8s_image_json_path"
 	workloadToResourceFunctionN = "workload_to_resource_function"
+	devNamespaceN               = "dev_namespace"
+	devOverridesN               = "dev_overrides"
 
 	// file functions
 	localN     = "local"
@@ -289,9 +448,17 @@ const (
 
 	disableSnapshotsN = "disable_snapshots"
 
+	// secret functions
+	secretN     = "secret"
+	sensitiveN  = "sensitive"
+	readSecretN = "read_secret"
+
 	// other functions
-	failN    = "fail"
-	setTeamN = "set_team"
+	failN      = "fail"
+	warnN      = "warn"
+	setTeamN   = "set_team"
+	cmdButtonN = "cmd_button"
+	linkN      = "link"
 )
 
 type triggerMode int
@@ -342,10 +509,11 @@ func (s *tiltfileState) triggerModeForResource(resourceTriggerMode triggerMode)
 func starlarkTriggerModeToModel(triggerMode triggerMode, autoInit bool) (model.TriggerMode, error) {
 	switch triggerMode {
 	case TriggerModeAuto:
-		if !autoInit {
-			return 0, errors.New("auto_init=False incompatible with trigger_mode=TRIGGER_MODE_AUTO")
+		if autoInit {
+			return model.TriggerModeAuto, nil
+		} else {
+			return model.TriggerModeAutoWithManualInit, nil
 		}
-		return model.TriggerModeAuto, nil
 	case TriggerModeManual:
 		if autoInit {
 			return model.TriggerModeManualAfterInitial, nil
@@ -363,9 +531,25 @@ func (s *tiltfileState) OnBuiltinCall(name string, fn *starlark.Builtin) {
 }
 
 func (s *tiltfileState) OnExec(t *starlark.Thread, tiltfilePath string) error {
+	if err := s.checkExecDeadline(); err != nil {
+		return err
+	}
 	return io.RecordReadFile(t, tiltIgnorePath(tiltfilePath))
 }
 
+// Fail execution once the sandbox's wall-clock budget is spent, so a bad
+// Tiltfile edit can't hang tilt forever.
+func (s *tiltfileState) checkExecDeadline() error {
+	deadline, ok := s.limits.Deadline(s.execStartTime)
+	if !ok || s.execStartTime.IsZero() {
+		return nil
+	}
+	if time.Now().After(deadline) {
+		return fmt.Errorf("Tiltfile execution exceeded the %s timeout (set by %s)", s.limits.Timeout, sandbox.TimeoutEnvName)
+	}
+	return nil
+}
+
 // wrap a builtin such that it's only allowed to run when we have a known safe k8s context
 // (none (e.g., docker-compose), local, or specified by `allow_k8s_contexts`)
 func (s *tiltfileState) potentiallyK8sUnsafeBuiltin(f starkit.Function) starkit.Function {
@@ -435,6 +619,9 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		{fastBuildN, s.fastBuild},
 		{customBuildN, s.customBuild},
 		{defaultRegistryN, s.defaultRegistry},
+		{registryMirrorN, s.registryMirror},
+		{imageTagSchemeN, s.imageTagScheme},
+		{prePullN, s.prePull},
 		{dockerComposeN, s.dockerCompose},
 		{dcResourceN, s.dcResource},
 		{k8sResourceAssemblyVersionN, s.k8sResourceAssemblyVersionFn},
@@ -442,13 +629,17 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		{filterYamlN, s.filterYaml},
 		{k8sResourceN, s.k8sResource},
 		{localResourceN, s.localResource},
+		{remoteResourceN, s.remoteResource},
 		{portForwardN, s.portForward},
 		{k8sKindN, s.k8sKind},
 		{k8sImageJSONPathN, s.k8sImageJsonPath},
 		{workloadToResourceFunctionN, s.workloadToResourceFunctionFn},
+		{devNamespaceN, s.devNamespaceFn},
+		{devOverridesN, s.devOverridesFn},
 		{kustomizeN, s.kustomize},
 		{helmN, s.helm},
 		{failN, s.fail},
+		{warnN, s.warn},
 		{triggerModeN, s.triggerModeFn},
 		{fallBackOnN, s.liveUpdateFallBackOn},
 		{syncN, s.liveUpdateSync},
@@ -458,6 +649,11 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		{disableFeatureN, s.disableFeature},
 		{disableSnapshotsN, s.disableSnapshots},
 		{setTeamN, s.setTeam},
+		{secretN, s.secret},
+		{sensitiveN, s.sensitive},
+		{readSecretN, s.readSecret},
+		{cmdButtonN, s.cmdButton},
+		{linkN, s.link},
 	} {
 		err := e.AddBuiltin(b.name, b.builtin)
 		if err != nil {
@@ -478,21 +674,83 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		}
 	}
 
+	for _, d := range coreBuiltinDocs {
+		e.AddBuiltinDoc(d.Name, d)
+	}
+
 	return nil
 }
 
+// Docs for the most commonly used core builtins, surfaced by `tilt api`
+// and the API dump. Builtins without an entry here still appear by name.
+var coreBuiltinDocs = []starkit.BuiltinDoc{
+	{
+		Name:      dockerBuildN,
+		Signature: "docker_build(ref, context, build_args={}, dockerfile='Dockerfile', live_update=[], ...)",
+		Summary:   "Build a Docker image from a context directory and associate it with the k8s resources that use it.",
+	},
+	{
+		Name:      k8sYamlN,
+		Signature: "k8s_yaml(yaml)",
+		Summary:   "Register Kubernetes objects to deploy, from a path, a list of paths, or a Blob.",
+	},
+	{
+		Name:      k8sResourceN,
+		Signature: "k8s_resource(workload, new_name='', port_forwards=[], extra_pod_selectors=[], trigger_mode=TRIGGER_MODE_AUTO)",
+		Summary:   "Configure an existing k8s resource: rename it, add port forwards, or change its trigger mode.",
+	},
+	{
+		Name:      localN,
+		Signature: "local(command, quiet=False)",
+		Summary:   "Run a command on the host at Tiltfile-execution time and return its stdout as a Blob.",
+	},
+	{
+		Name:      localResourceN,
+		Signature: "local_resource(name, cmd, deps=[], trigger_mode=TRIGGER_MODE_AUTO)",
+		Summary:   "Declare a resource that runs a local command when its deps change.",
+	},
+	{
+		Name:      failN,
+		Signature: "fail(msg, resource='')",
+		Summary:   "Abort Tiltfile execution with an error, optionally attributed to a specific resource.",
+	},
+	{
+		Name:      warnN,
+		Signature: "warn(msg)",
+		Summary:   "Record a non-fatal warning, surfaced in the HUD and web UI.",
+	},
+	{
+		Name:      secretN,
+		Signature: "secret(env_file)",
+		Summary:   "Read a local env file of KEY=VALUE lines, scrub every value from Tilt's output, and return the keys and values as a dict.",
+	},
+	{
+		Name:      sensitiveN,
+		Signature: "sensitive(value, name='')",
+		Summary:   "Mark a string as sensitive and return it, so it's scrubbed from Tilt's output wherever it appears.",
+	},
+	{
+		Name:      readSecretN,
+		Signature: "read_secret(source, from, name='')",
+		Summary:   "Read a secret from an external source ('env', 'file', or 'exec') and scrub its value from Tilt's output wherever it appears.",
+	},
+}
+
 // Returns the current orchestrator.
 //
 // Note that assemble() will eventually error out if this has
 // both DC and K8s resources.
 func (s *tiltfileState) orchestrator() model.Orchestrator {
-	if !s.dc.Empty() {
+	// In a mixed-mode session (compose + k8s in one Tiltfile), the
+	// cluster's orchestrator wins: registry decisions only apply to
+	// images deployed to k8s.
+	if !s.dc.Empty() && len(s.k8s) == 0 && len(s.k8sUnresourced) == 0 {
 		return model.OrchestratorDC
 	}
 	return model.OrchestratorK8s
 }
 
-func (s *tiltfileState) assemble() (resourceSet, []k8s.K8sEntity, error) {
+func (s *tiltfileState) assemble(us model.UpdateSettings) (resourceSet, []k8s.K8sEntity, error) {
 	err := s.assembleImages()
 	if err != nil {
 		return resourceSet{}, nil, err
@@ -508,17 +766,27 @@ func (s *tiltfileState) assemble() (resourceSet, []k8s.K8sEntity, error) {
 		return resourceSet{}, nil, err
 	}
 
-	err = s.assembleDC()
+	err = s.applyRegistryMirror()
 	if err != nil {
 		return resourceSet{}, nil, err
 	}
 
-	if !s.dc.Empty() && (len(s.k8s) > 0 || len(s.k8sUnresourced) > 0) {
-		return resourceSet{}, nil, fmt.Errorf("can't declare both k8s " +
-			"resources/entities and docker-compose resources")
+	err = s.applyDevNamespace()
+	if err != nil {
+		return resourceSet{}, nil, err
 	}
 
-	err = s.buildIndex.assertAllMatched()
+	err = s.applyDevOverrides()
+	if err != nil {
+		return resourceSet{}, nil, err
+	}
+
+	err = s.assembleDC()
+	if err != nil {
+		return resourceSet{}, nil, err
+	}
+
+	err = s.buildIndex.assertAllMatched(us)
 	if err != nil {
 		s.logger.Warnf("%s", err.Error())
 	}
@@ -553,7 +821,9 @@ func (s *tiltfileState) assembleImages() error {
 }
 
 func (s *tiltfileState) assembleDC() error {
-	if len(s.dc.services) > 0 && !s.defaultReg.Empty() {
+	// In a mixed-mode session the registry applies to the k8s images, so
+	// only reject it when docker compose is the whole session.
+	if len(s.dc.services) > 0 && s.orchestrator() == model.OrchestratorDC && !s.defaultReg.Empty() {
 		return errors.New("default_registry is not supported with docker compose")
 	}
 
@@ -607,7 +877,14 @@ func (s *tiltfileState) assembleK8sV2() error {
 			r.extraPodSelectors = opts.extraPodSelectors
 			r.portForwards = opts.portForwards
 			r.triggerMode = opts.triggerMode
+			r.autoInit = opts.autoInit
+			r.links = opts.links
+			r.readinessTimeout = opts.readinessTimeout
+			r.deployStrategy = opts.deployStrategy
 			r.resourceDeps = opts.resourceDeps
+			r.kubeContext = opts.kubeContext
+			r.entrypoint = opts.entrypoint
+			r.containerArgs = opts.containerArgs
 			if opts.newName != "" && opts.newName != r.name {
 				if _, ok := s.k8sByName[opts.newName]; ok {
 					return fmt.Errorf("k8s_resource at %s specified to rename '%s' to '%s', but there is already a resource with that name", opts.tiltfilePosition.String(), r.name, opts.newName)
@@ -900,7 +1177,7 @@ func (s *tiltfileState) translateK8s(resources []*k8sResource) ([]model.Manifest
 	registry := s.decideRegistry()
 	for _, r := range resources {
 		mn := model.ManifestName(r.name)
-		tm, err := starlarkTriggerModeToModel(s.triggerModeForResource(r.triggerMode), true)
+		tm, err := starlarkTriggerModeToModel(s.triggerModeForResource(r.triggerMode), r.autoInit)
 		if err != nil {
 			return nil, err
 		}
@@ -913,6 +1190,9 @@ func (s *tiltfileState) translateK8s(resources []*k8sResource) ([]model.Manifest
 			Name:                 mn,
 			TriggerMode:          tm,
 			ResourceDependencies: mds,
+			Links:                r.links,
+			ReadinessTimeout:     r.readinessTimeout,
+			DeployStrategy:       r.deployStrategy,
 		}
 
 		k8sTarget, err := k8s.NewTarget(mn.TargetName(), r.entities, s.defaultedPortForwards(r.portForwards),
@@ -920,6 +1200,9 @@ func (s *tiltfileState) translateK8s(resources []*k8sResource) ([]model.Manifest
 		if err != nil {
 			return nil, err
 		}
+		k8sTarget.KubeContext = r.kubeContext
+		k8sTarget.OverrideCmd = r.entrypoint
+		k8sTarget.OverrideArgs = r.containerArgs
 
 		m = m.WithDeployTarget(k8sTarget)
 
@@ -1092,8 +1375,17 @@ func (s *tiltfileState) imgTargetsForDependencyIDsHelper(ids []model.TargetID, c
 
 		switch image.Type() {
 		case DockerBuild:
+			df := image.dbDockerfile
+			if s.mirrorRegistry != "" {
+				newDf, _, err := dockerfile.RewriteRegistryMirror(df, s.mirrorRegistry)
+				if err != nil {
+					return nil, err
+				}
+				df = newDf
+			}
+
 			iTarget = iTarget.WithBuildDetails(model.DockerBuild{
-				Dockerfile:  image.dbDockerfile.String(),
+				Dockerfile:  df.String(),
 				BuildPath:   image.dbBuildPath,
 				BuildArgs:   image.dbBuildArgs,
 				LiveUpdate:  lu,
@@ -1102,6 +1394,7 @@ func (s *tiltfileState) imgTargetsForDependencyIDsHelper(ids []model.TargetID, c
 				SecretSpecs: image.secretSpecs,
 				Network:     image.network,
 				ExtraTags:   image.extraTags,
+				TagStrategy: s.tagStrategy,
 			})
 		case CustomBuild:
 			r := model.CustomBuild{
@@ -1310,6 +1603,7 @@ func (s *tiltfileState) translateLocal() ([]model.Manifest, error) {
 			Name:                 mn,
 			TriggerMode:          tm,
 			ResourceDependencies: mds,
+			Links:                r.links,
 		}.WithDeployTarget(lt)
 
 		result = append(result, m)
@@ -1318,6 +1612,20 @@ func (s *tiltfileState) translateLocal() ([]model.Manifest, error) {
 	return result, nil
 }
 
+// Now that one Tiltfile can mix docker-compose services and k8s resources,
+// a compose service and a k8s resource could collide on a name; the later
+// one would silently shadow the earlier one in the engine.
+func validateUniqueManifestNames(ms []model.Manifest) error {
+	seen := make(map[model.ManifestName]bool)
+	for _, m := range ms {
+		if seen[m.Name] {
+			return fmt.Errorf("resource name conflict: %s is defined multiple times", m.Name)
+		}
+		seen[m.Name] = true
+	}
+	return nil
+}
+
 func validateResourceDependencies(ms []model.Manifest) error {
 	// make sure that:
 	// 1. all deps exist
@@ -36,6 +36,7 @@ import (
 	"github.com/windmilleng/tilt/internal/testutils"
 	"github.com/windmilleng/tilt/internal/testutils/tempdir"
 	"github.com/windmilleng/tilt/internal/tiltfile/k8scontext"
+	"github.com/windmilleng/tilt/internal/tiltfile/sandbox"
 	"github.com/windmilleng/tilt/internal/tiltfile/testdata"
 	"github.com/windmilleng/tilt/internal/yaml"
 	"github.com/windmilleng/tilt/pkg/logger"
@@ -284,6 +285,41 @@ k8s_yaml(yaml)
 	assert.Contains(t, f.out.String(), " → kind: Deployment")
 }
 
+func TestSandboxMaxLocalCalls(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	oldVal := os.Getenv(sandbox.MaxLocalEnvName)
+	_ = os.Setenv(sandbox.MaxLocalEnvName, "1")
+	defer func() {
+		_ = os.Setenv(sandbox.MaxLocalEnvName, oldVal)
+	}()
+
+	f.file("Tiltfile", `
+local('echo one', quiet=True)
+local('echo two', quiet=True)
+`)
+
+	f.loadErrString("more than 1 local subprocesses", sandbox.MaxLocalEnvName)
+}
+
+func TestSandboxTimeout(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	oldVal := os.Getenv(sandbox.TimeoutEnvName)
+	_ = os.Setenv(sandbox.TimeoutEnvName, "1ns")
+	defer func() {
+		_ = os.Setenv(sandbox.TimeoutEnvName, oldVal)
+	}()
+
+	f.file("Tiltfile", `
+local('echo one', quiet=True)
+`)
+
+	f.loadErrString("exceeded the 1ns timeout", sandbox.TimeoutEnvName)
+}
+
 func TestLocalQuiet(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -314,6 +350,41 @@ func TestLocalArgvCmd(t *testing.T) {
 	assert.Contains(t, f.out.String(), `a"b`)
 }
 
+func TestLocalParallel(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+cmd = 'cat foo.yaml'
+if os.name == 'nt':
+  cmd = 'type foo.yaml'
+fut = local(cmd, parallel=True)
+k8s_yaml(fut.wait())
+`)
+
+	f.load()
+
+	f.assertNextManifest("foo",
+		db(image("gcr.io/foo")),
+		deployment("foo"))
+}
+
+func TestLocalParallelUnwaitedError(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	// An error from a parallel command should fail the Tiltfile even if
+	// it was never waited on.
+	f.file("Tiltfile", `
+local('exit 1', quiet=True, parallel=True)
+`)
+
+	f.loadErrString("exit status 1")
+}
+
 func TestReadFile(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -882,7 +953,9 @@ k8s_yaml('bar.yaml')
 		db(image("gcr.io/foo")),
 		deployment("foo"))
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml", "bar/Dockerfile", "bar/.dockerignore", "bar.yaml")
+	// bar is unselected, so we never assemble it and never consult its
+	// .dockerignore.
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml", "bar/Dockerfile", "bar.yaml")
 }
 
 func TestLoadTypoManifest(t *testing.T) {
@@ -1487,6 +1560,31 @@ fail("or this")
 	f.loadErrString("this is an error")
 }
 
+func TestFailWithResource(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+fail("image tag missing", resource="frontend")
+`)
+
+	f.loadErrString("frontend: image tag missing")
+}
+
+func TestWarn(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+warn("this chart is deprecated")
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+`)
+
+	f.loadAssertWarnings("this chart is deprecated")
+}
+
 func TestBlob(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -2479,309 +2577,599 @@ default_registry("docker.io")`)
 	f.loadErrString("default registry already defined")
 }
 
-func TestDefaultRegistryInvalid(t *testing.T) {
+func TestRegistryMirrorRewritesDockerfile(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
 	f.setupFoo()
 	f.file("Tiltfile", `
-default_registry("foo")
+registry_mirror('mirror.example.com')
 docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
 `)
 
-	f.loadErrString("Traceback ", "repository name must be canonical")
+	f.load()
+
+	m := f.assertNextManifest("foo", db(image("gcr.io/foo")), deployment("foo"))
+	assert.Contains(t, m.ImageTargetAt(0).DockerBuildInfo().Dockerfile,
+		"FROM mirror.example.com/library/golang:1.10")
 }
 
-func TestDefaultRegistryHostFromCluster(t *testing.T) {
+func TestRegistryMirrorRewritesYAML(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
-	f.setupFoo()
+	f.yaml("bb.yaml", deployment("bb", image("busybox:1.31")))
 	f.file("Tiltfile", `
-default_registry("abc.io", host_from_cluster="def.io")
-k8s_yaml('foo.yaml')
-docker_build('gcr.io/foo', 'foo')
+registry_mirror('mirror.example.com')
+k8s_yaml('bb.yaml')
 `)
 
 	f.load()
 
-	f.assertNextManifest("foo",
-		db(image("gcr.io/foo").withLocalRef("abc.io/gcr.io_foo").withClusterRef("def.io/gcr.io_foo")),
-		deployment("foo"))
+	m := f.assertNextManifest("bb")
+	assert.Contains(t, m.K8sTarget().YAML, "mirror.example.com/library/busybox:1.31")
 }
 
-func TestDefaultRegistryAtEndOfTiltfile(t *testing.T) {
+func TestRegistryMirrorSkipsBuiltImages(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
-	f.setupFoo()
-	// default_registry is the last entry to test that it doesn't only affect subsequently defined images
+	f.dockerfile("foo/Dockerfile")
+	f.yaml("foo.yaml", deployment("foo", image("foo-img")))
+	f.gitInit("")
 	f.file("Tiltfile", `
-docker_build('gcr.io/foo', 'foo')
+registry_mirror('mirror.example.com')
+docker_build('foo-img', 'foo')
 k8s_yaml('foo.yaml')
-default_registry('bar.com')
 `)
 
 	f.load()
 
-	f.assertNextManifest("foo",
-		db(image("gcr.io/foo").withLocalRef("bar.com/gcr.io_foo")),
-		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
+	m := f.assertNextManifest("foo", db(image("foo-img")), deployment("foo"))
+	assert.NotContains(t, m.K8sTarget().YAML, "mirror.example.com/library/foo-img")
 }
 
-func TestLocalRegistry(t *testing.T) {
+func TestRegistryMirrorAlreadyDefined(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
-	f.kCli.Registry = container.MustNewRegistry("localhost:32000")
-
-	f.setupFoo()
 	f.file("Tiltfile", `
-default_registry('bar.com')  # localRegistry should override this
-docker_build('gcr.io/foo', 'foo')
-k8s_yaml('foo.yaml')
+registry_mirror('mirror.example.com')
+registry_mirror('mirror2.example.com')
 `)
 
-	f.load()
-
-	f.assertNextManifest("foo",
-		db(image("gcr.io/foo").withLocalRef("localhost:32000/gcr.io_foo")),
-		deployment("foo"))
+	f.loadErrString("registry mirror already defined")
 }
 
-func TestLocalRegistryDockerCompose(t *testing.T) {
+func TestDevNamespaceRewritesYAML(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
-	f.kCli.Registry = container.MustNewRegistry("localhost:32000")
+	oldUser := os.Getenv("USER")
+	_ = os.Setenv("USER", "First.Last")
+	defer func() {
+		_ = os.Setenv("USER", oldUser)
+	}()
 
 	f.setupFoo()
-	f.file("docker-compose.yml", `version: '3'
-services:
-  foo:
-    image: gcr.io/foo
-`)
 	f.file("Tiltfile", `
+dev_namespace()
 docker_build('gcr.io/foo', 'foo')
-docker_compose('docker-compose.yml')
+k8s_yaml('foo.yaml')
 `)
 
 	f.load()
 
-	// Assert that when we use a docker-compose orchestrator, we don't
-	// use the k8s private registry.
-	f.assertNextManifest("foo",
-		db(image("gcr.io/foo")))
+	m := f.assertNextManifest("foo", db(image("gcr.io/foo")), deployment("foo"))
+	assert.Contains(t, m.K8sTarget().YAML, "namespace: dev-first-last")
+	f.assertNextManifestUnresourced("dev-first-last")
 }
 
-func TestDefaultRegistryTwoImagesOnlyDifferByTag(t *testing.T) {
+func TestDevNamespaceUnknownVariable(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
-	f.dockerfile("bar/Dockerfile")
-	f.yaml("bar.yaml", deployment("bar", image("gcr.io/foo:bar")))
+	f.file("Tiltfile", `
+dev_namespace('dev-$TEAM')
+`)
 
-	f.dockerfile("baz/Dockerfile")
-	f.yaml("baz.yaml", deployment("baz", image("gcr.io/foo:baz")))
+	f.loadErrString("unknown variable $TEAM", "only $USER is supported")
+}
+
+func TestDevNamespaceAlreadyDefined(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
 
-	f.gitInit("")
 	f.file("Tiltfile", `
-k8s_resource_assembly_version(2)
-docker_build('gcr.io/foo:bar', 'bar')
-docker_build('gcr.io/foo:baz', 'baz')
-k8s_yaml('bar.yaml')
-k8s_yaml('baz.yaml')
-default_registry('example.com')
+dev_namespace('alice')
+dev_namespace('bob')
 `)
 
-	f.load()
-
-	f.assertNextManifest("bar",
-		db(image("gcr.io/foo:bar").withLocalRef("example.com/gcr.io_foo")),
-		deployment("bar"))
-	f.assertNextManifest("baz",
-		db(image("gcr.io/foo:baz").withLocalRef("example.com/gcr.io_foo")),
-		deployment("baz"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "bar/Dockerfile", "bar/.dockerignore", "bar.yaml", "baz/Dockerfile", "baz/.dockerignore", "baz.yaml")
+	f.loadErrString("dev namespace already defined")
 }
 
-func TestDefaultReadFile(t *testing.T) {
+const devOverridesDeploymentYAML = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: foo
+  labels:
+    app: foo
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: foo
+  template:
+    metadata:
+      labels:
+        app: foo
+    spec:
+      containers:
+      - name: foo
+        image: gcr.io/foo
+        imagePullPolicy: Always
+        resources:
+          limits:
+            cpu: "1"
+            memory: 512Mi
+`
+
+func TestDevOverridesRewritesYAML(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
-	f.setupFooAndBar()
-	tiltfile := `
-result = read_file("this_file_does_not_exist", default="foo")
-docker_build('gcr.io/foo', 'foo')
-k8s_yaml(str(result) + '.yaml')
-`
 
-	f.file("Tiltfile", tiltfile)
+	f.setupFoo()
+	f.file("foo.yaml", devOverridesDeploymentYAML)
+	f.file("Tiltfile", `
+dev_overrides()
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+`)
 
 	f.load()
 
-	f.assertNextManifest("foo",
-		db(image("gcr.io/foo")),
-		deployment("foo"))
-
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "this_file_does_not_exist", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
+	m := f.assertNextManifest("foo", db(image("gcr.io/foo")), deployment("foo"))
+	yaml := m.K8sTarget().YAML
+	assert.Contains(t, yaml, "replicas: 1")
+	assert.NotContains(t, yaml, "limits")
+	assert.Contains(t, yaml, "imagePullPolicy: IfNotPresent")
 }
 
-func TestWatchFile(t *testing.T) {
+func TestDevOverridesExclude(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
 	f.setupFoo()
-
-	f.file("hello", "world")
+	f.file("foo.yaml", devOverridesDeploymentYAML)
 	f.file("Tiltfile", `
+dev_overrides(exclude=['foo'])
 docker_build('gcr.io/foo', 'foo')
-watch_file('hello')
 k8s_yaml('foo.yaml')
 `)
 
 	f.load()
 
-	f.assertNextManifest("foo",
-		db(image("gcr.io/foo")),
-		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml", "hello")
+	m := f.assertNextManifest("foo", db(image("gcr.io/foo")), deployment("foo"))
+	yaml := m.K8sTarget().YAML
+	assert.Contains(t, yaml, "replicas: 3")
+	assert.Contains(t, yaml, "limits")
 }
 
-func TestK8sResourceAssemblyVersionAfterYAML(t *testing.T) {
+func TestDevOverridesUnknownExclude(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
 	f.setupFoo()
-
 	f.file("Tiltfile", `
-k8s_resource('foo', 'bar')
-k8s_resource_assembly_version(2)
+dev_overrides(exclude=['doggos'])
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
 `)
 
-	f.loadErrString("k8s_resource_assembly_version can only be called before introducing any k8s resources")
+	f.loadErrString("dev_overrides: unknown resource", "doggos")
 }
 
-func TestK8sResourceAssemblyK8sResourceYAMLNamedArg(t *testing.T) {
+func TestDevOverridesAlreadyEnabled(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
-	f.setupFoo()
-
 	f.file("Tiltfile", `
-k8s_resource('foo', yaml='bar')
+dev_overrides()
+dev_overrides()
 `)
 
-	f.loadErrString("kwarg \"yaml\"", "deprecated", "https://docs.tilt.dev/resource_assembly_migration.html")
+	f.loadErrString("dev overrides already enabled")
 }
 
-func TestK8sResourceAssemblyK8sResourceImage(t *testing.T) {
+func TestImageTagScheme(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
 	f.setupFoo()
-
 	f.file("Tiltfile", `
-k8s_resource('foo', image='bar')
+image_tag_scheme('template', tag_template='dev-{user}-{hash}')
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
 `)
 
-	f.loadErrString("kwarg \"image\"", "deprecated", "https://docs.tilt.dev/resource_assembly_migration.html")
+	f.load()
+	m := f.assertNextManifest("foo", db(image("gcr.io/foo")))
+	strategy := m.ImageTargetAt(0).DockerBuildInfo().TagStrategy
+	assert.Equal(t, "dev-{user}-{hash}", strategy.Template)
 }
 
-func TestK8sResourceAssemblyK8sResourceYAMLPositionalArg(t *testing.T) {
+func TestImageTagSchemeGitSHA(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
 	f.setupFoo()
-
 	f.file("Tiltfile", `
-k8s_resource('foo', 'foo.yaml')
+image_tag_scheme('git-sha')
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
 `)
 
-	f.loadErrString("second arg", "looks like a yaml file name", "deprecated", "https://docs.tilt.dev/resource_assembly_migration.html")
+	f.load()
+	m := f.assertNextManifest("foo", db(image("gcr.io/foo")))
+	strategy := m.ImageTargetAt(0).DockerBuildInfo().TagStrategy
+	assert.Equal(t, "tilt-{gitsha}-{hash}", strategy.Template)
 }
 
-func TestK8sResourceAssemblyK8sResourceNameKeywordArg(t *testing.T) {
+func TestImageTagSchemeUnknown(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
-	f.setupFoo()
-
 	f.file("Tiltfile", `
-k8s_resource('foo', name='bar')
+image_tag_scheme('moon-phase')
 `)
 
-	f.loadErrString("kwarg \"name\"", "deprecated", "https://docs.tilt.dev/resource_assembly_migration.html")
+	f.loadErrString("unknown scheme \"moon-phase\"")
 }
 
-func TestAssemblyVersion2Basic(t *testing.T) {
+func TestImageTagSchemeTemplateMissingHash(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
-	f.setupFoo()
-
 	f.file("Tiltfile", `
-docker_build('gcr.io/foo', 'foo')
-k8s_yaml('foo.yaml')
+image_tag_scheme('template', tag_template='dev-{user}')
 `)
 
-	f.load("foo")
-
-	f.assertNextManifest("foo",
-		db(image("gcr.io/foo")),
-		deployment("foo"))
-
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
+	f.loadErrString("must contain {hash}")
 }
 
-func TestAssemblyVersion2TwoWorkloadsSameImage(t *testing.T) {
+func TestPrePull(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
 	f.setupFoo()
-	f.yaml("bar.yaml", deployment("bar", image("gcr.io/foo")))
-
 	f.file("Tiltfile", `
-k8s_resource_assembly_version(2)
+pre_pull(['golang:1.13', 'gcr.io/some-project-162817/helper'])
+pre_pull('alpine:3.10')
 docker_build('gcr.io/foo', 'foo')
-k8s_yaml(['foo.yaml', 'bar.yaml'])
+k8s_yaml('foo.yaml')
 `)
 
-	f.load("foo", "bar")
-
-	f.assertNextManifest("foo",
-		db(image("gcr.io/foo")),
-		deployment("foo"))
-	f.assertNextManifest("bar",
-		db(image("gcr.io/foo")),
-		deployment("bar"))
-
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "bar.yaml", "foo/Dockerfile", "foo/.dockerignore")
+	f.load()
+	assert.Equal(t, []string{"golang:1.13", "gcr.io/some-project-162817/helper", "alpine:3.10"},
+		f.loadResult.PrePullImages)
 }
 
-func TestK8sResourceNoMatch(t *testing.T) {
+func TestPrePullInvalidImage(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
-	f.setupFoo()
 	f.file("Tiltfile", `
-k8s_resource_assembly_version(2)
-k8s_yaml('foo.yaml')
-k8s_resource('bar', new_name='baz')
+pre_pull('gcr.io/foo:bar:baz')
 `)
 
-	f.loadErrString("specified unknown resource 'bar'. known resources: foo", "https://docs.tilt.dev/resource_assembly_migration.html")
+	f.loadErrString("gcr.io/foo:bar:baz")
 }
 
-func TestK8sResourceNewName(t *testing.T) {
+func TestDefaultRegistryInvalid(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
 
 	f.setupFoo()
 	f.file("Tiltfile", `
-k8s_resource_assembly_version(2)
-k8s_yaml('foo.yaml')
-k8s_resource('foo', new_name='bar')
+default_registry("foo")
+docker_build('gcr.io/foo', 'foo')
+`)
+
+	f.loadErrString("Traceback ", "repository name must be canonical")
+}
+
+func TestDefaultRegistryHostFromCluster(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+default_registry("abc.io", host_from_cluster="def.io")
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo')
+`)
+
+	f.load()
+
+	f.assertNextManifest("foo",
+		db(image("gcr.io/foo").withLocalRef("abc.io/gcr.io_foo").withClusterRef("def.io/gcr.io_foo")),
+		deployment("foo"))
+}
+
+func TestDefaultRegistryAtEndOfTiltfile(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	// default_registry is the last entry to test that it doesn't only affect subsequently defined images
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+default_registry('bar.com')
+`)
+
+	f.load()
+
+	f.assertNextManifest("foo",
+		db(image("gcr.io/foo").withLocalRef("bar.com/gcr.io_foo")),
+		deployment("foo"))
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
+}
+
+func TestLocalRegistry(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.kCli.Registry = container.MustNewRegistry("localhost:32000")
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+default_registry('bar.com')  # localRegistry should override this
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+`)
+
+	f.load()
+
+	f.assertNextManifest("foo",
+		db(image("gcr.io/foo").withLocalRef("localhost:32000/gcr.io_foo")),
+		deployment("foo"))
+}
+
+func TestLocalRegistryDockerCompose(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.kCli.Registry = container.MustNewRegistry("localhost:32000")
+
+	f.setupFoo()
+	f.file("docker-compose.yml", `version: '3'
+services:
+  foo:
+    image: gcr.io/foo
+`)
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+docker_compose('docker-compose.yml')
+`)
+
+	f.load()
+
+	// Assert that when we use a docker-compose orchestrator, we don't
+	// use the k8s private registry.
+	f.assertNextManifest("foo",
+		db(image("gcr.io/foo")))
+}
+
+func TestDefaultRegistryTwoImagesOnlyDifferByTag(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.dockerfile("bar/Dockerfile")
+	f.yaml("bar.yaml", deployment("bar", image("gcr.io/foo:bar")))
+
+	f.dockerfile("baz/Dockerfile")
+	f.yaml("baz.yaml", deployment("baz", image("gcr.io/foo:baz")))
+
+	f.gitInit("")
+	f.file("Tiltfile", `
+k8s_resource_assembly_version(2)
+docker_build('gcr.io/foo:bar', 'bar')
+docker_build('gcr.io/foo:baz', 'baz')
+k8s_yaml('bar.yaml')
+k8s_yaml('baz.yaml')
+default_registry('example.com')
+`)
+
+	f.load()
+
+	f.assertNextManifest("bar",
+		db(image("gcr.io/foo:bar").withLocalRef("example.com/gcr.io_foo")),
+		deployment("bar"))
+	f.assertNextManifest("baz",
+		db(image("gcr.io/foo:baz").withLocalRef("example.com/gcr.io_foo")),
+		deployment("baz"))
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "bar/Dockerfile", "bar/.dockerignore", "bar.yaml", "baz/Dockerfile", "baz/.dockerignore", "baz.yaml")
+}
+
+func TestDefaultReadFile(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+	f.setupFooAndBar()
+	tiltfile := `
+result = read_file("this_file_does_not_exist", default="foo")
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml(str(result) + '.yaml')
+`
+
+	f.file("Tiltfile", tiltfile)
+
+	f.load()
+
+	f.assertNextManifest("foo",
+		db(image("gcr.io/foo")),
+		deployment("foo"))
+
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "this_file_does_not_exist", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
+}
+
+func TestWatchFile(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+
+	f.file("hello", "world")
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+watch_file('hello')
+k8s_yaml('foo.yaml')
+`)
+
+	f.load()
+
+	f.assertNextManifest("foo",
+		db(image("gcr.io/foo")),
+		deployment("foo"))
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml", "hello")
+}
+
+func TestK8sResourceAssemblyVersionAfterYAML(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_resource('foo', 'bar')
+k8s_resource_assembly_version(2)
+`)
+
+	f.loadErrString("k8s_resource_assembly_version can only be called before introducing any k8s resources")
+}
+
+func TestK8sResourceAssemblyK8sResourceYAMLNamedArg(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_resource('foo', yaml='bar')
+`)
+
+	f.loadErrString("kwarg \"yaml\"", "deprecated", "https://docs.tilt.dev/resource_assembly_migration.html")
+}
+
+func TestK8sResourceAssemblyK8sResourceImage(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_resource('foo', image='bar')
+`)
+
+	f.loadErrString("kwarg \"image\"", "deprecated", "https://docs.tilt.dev/resource_assembly_migration.html")
+}
+
+func TestK8sResourceAssemblyK8sResourceYAMLPositionalArg(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_resource('foo', 'foo.yaml')
+`)
+
+	f.loadErrString("second arg", "looks like a yaml file name", "deprecated", "https://docs.tilt.dev/resource_assembly_migration.html")
+}
+
+func TestK8sResourceAssemblyK8sResourceNameKeywordArg(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_resource('foo', name='bar')
+`)
+
+	f.loadErrString("kwarg \"name\"", "deprecated", "https://docs.tilt.dev/resource_assembly_migration.html")
+}
+
+func TestAssemblyVersion2Basic(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+`)
+
+	f.load("foo")
+
+	f.assertNextManifest("foo",
+		db(image("gcr.io/foo")),
+		deployment("foo"))
+
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
+}
+
+func TestAssemblyVersion2TwoWorkloadsSameImage(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.yaml("bar.yaml", deployment("bar", image("gcr.io/foo")))
+
+	f.file("Tiltfile", `
+k8s_resource_assembly_version(2)
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml(['foo.yaml', 'bar.yaml'])
+`)
+
+	f.load("foo", "bar")
+
+	f.assertNextManifest("foo",
+		db(image("gcr.io/foo")),
+		deployment("foo"))
+	f.assertNextManifest("bar",
+		db(image("gcr.io/foo")),
+		deployment("bar"))
+
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "bar.yaml", "foo/Dockerfile", "foo/.dockerignore")
+}
+
+func TestK8sResourceNoMatch(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_resource_assembly_version(2)
+k8s_yaml('foo.yaml')
+k8s_resource('bar', new_name='baz')
+`)
+
+	f.loadErrString("specified unknown resource 'bar'. known resources: foo", "https://docs.tilt.dev/resource_assembly_migration.html")
+}
+
+func TestK8sResourceNewName(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_resource_assembly_version(2)
+k8s_yaml('foo.yaml')
+k8s_resource('foo', new_name='bar')
 `)
 
 	f.load()
@@ -3119,6 +3507,7 @@ func TestTriggerModeLocal(t *testing.T) {
 		{"local_resource manual", TriggerModeUnset, TriggerModeManual, false, true, model.TriggerModeManualAfterInitial},
 		{"local_resource manual, autoInit=True", TriggerModeUnset, TriggerModeManual, true, true, model.TriggerModeManualAfterInitial},
 		{"local_resource manual, autoInit=False", TriggerModeUnset, TriggerModeManual, true, false, model.TriggerModeManualIncludingInitial},
+		{"local_resource auto, autoInit=False", TriggerModeUnset, TriggerModeAuto, true, false, model.TriggerModeAutoWithManualInit},
 		{"local_resource override auto", TriggerModeManual, TriggerModeAuto, false, true, model.TriggerModeAuto},
 		{"local_resource override manual", TriggerModeAuto, TriggerModeManual, false, true, model.TriggerModeManualAfterInitial},
 	} {
@@ -3173,7 +3562,181 @@ func TestLocalResourceAutoTriggerModeAutoInitFalse(t *testing.T) {
 	defer f.TearDown()
 
 	f.file("Tiltfile", `local_resource("foo", "echo hi", auto_init=False)`)
-	f.loadErrString("auto_init=False incompatible with trigger_mode=TRIGGER_MODE_AUTO")
+	f.load()
+
+	f.assertNumManifests(1)
+	f.assertNextManifest("foo", model.TriggerModeAutoWithManualInit)
+}
+
+func TestK8sResourceAutoInitFalse(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', auto_init=False)
+`)
+	f.load()
+
+	f.assertNumManifests(1)
+	f.assertNextManifest("foo", model.TriggerModeAutoWithManualInit)
+}
+
+func TestCmdButton(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+cmd_button('flush-cache', resource='foo', cmd='echo flushed')
+`)
+	f.load()
+
+	f.assertNumManifests(1)
+	m := f.assertNextManifest("foo")
+	if assert.Len(t, m.Buttons, 1) {
+		assert.Equal(t, "flush-cache", m.Buttons[0].Name)
+		assert.Equal(t, model.ToHostCmd("echo flushed"), m.Buttons[0].Cmd)
+		assert.False(t, m.Buttons[0].InContainer)
+	}
+}
+
+func TestK8sResourceReadinessTimeout(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', readiness_timeout='5m')
+`)
+	f.load()
+
+	f.assertNumManifests(1)
+	m := f.assertNextManifest("foo")
+	assert.Equal(t, 5*time.Minute, m.ReadinessTimeout)
+}
+
+func TestK8sResourceEntrypointAndArgs(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', entrypoint='air', args=['--debug'])
+`)
+	f.load()
+
+	f.assertNumManifests(1)
+	m := f.assertNextManifest("foo")
+	assert.Equal(t, model.ToUnixCmd("air").Argv, m.K8sTarget().OverrideCmd.Argv)
+	assert.Equal(t,
+		model.OverrideArgs{ShouldOverride: true, Args: []string{"--debug"}},
+		m.K8sTarget().OverrideArgs)
+}
+
+func TestK8sResourceReadinessTimeoutInvalid(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', readiness_timeout='whenever')
+`)
+	f.loadErrString("readiness_timeout")
+}
+
+func TestK8sResourceDeployStrategy(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', deploy_strategy='blue_green')
+`)
+	f.load()
+
+	f.assertNumManifests(1)
+	m := f.assertNextManifest("foo")
+	assert.Equal(t, model.DeployStrategyBlueGreen, m.DeployStrategy)
+}
+
+func TestK8sResourceDeployStrategyInvalid(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', deploy_strategy='canary')
+`)
+	f.loadErrString(`unknown deploy_strategy "canary"`)
+}
+
+func TestResourceLinks(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', links=[
+  'http://localhost:8000/',
+  link('http://localhost:9000/', health_check_url='http://localhost:9000/healthz', required=True),
+])
+`)
+	f.load()
+
+	f.assertNumManifests(1)
+	m := f.assertNextManifest("foo")
+	if assert.Len(t, m.Links, 2) {
+		assert.Equal(t, model.Link{URL: "http://localhost:8000/"}, m.Links[0])
+		assert.Equal(t, model.Link{
+			URL:                       "http://localhost:9000/",
+			HealthCheckURL:            "http://localhost:9000/healthz",
+			HealthCheckExpectedStatus: 200,
+			RequiredForReadiness:      true,
+		}, m.Links[1])
+	}
+}
+
+func TestLinkRequiredNeedsHealthCheck(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', links=[link('http://localhost:8000/', required=True)])
+`)
+	f.loadErrString("link: required=True needs a health_check_url")
+}
+
+func TestCmdButtonUnknownResource(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+cmd_button('flush-cache', resource='bar', cmd='echo flushed')
+`)
+	f.loadErrString(`cmd_button "flush-cache": no resource named "bar"`)
 }
 
 func TestTriggerModeInt(t *testing.T) {
@@ -3806,6 +4369,39 @@ allow_k8s_contexts("allowed-context")
 	}
 }
 
+func TestK8sResourceKubeContext(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+allow_k8s_contexts('staging-ctx')
+k8s_resource('foo', context='staging-ctx')
+`)
+
+	f.load()
+	m := f.assertNextManifest("foo")
+	assert.Equal(t, "staging-ctx", m.K8sTarget().KubeContext)
+}
+
+func TestK8sResourceKubeContextNotAllowed(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', context='staging-ctx')
+`)
+
+	// Deploying to a context other than the one Tilt started against
+	// always requires an explicit allow_k8s_contexts.
+	f.loadErrString("foo", "'staging-ctx'", "If you're sure", "allow_k8s_contexts")
+}
+
 func TestLocalObeysAllowedK8sContexts(t *testing.T) {
 	for _, test := range []struct {
 		name                    string
@@ -3826,17 +4422,69 @@ func TestLocalObeysAllowedK8sContexts(t *testing.T) {
 allow_k8s_contexts("allowed-context")
 local('echo hi')
 `)
-			f.setupFoo()
+			f.setupFoo()
+
+			f.k8sContext = test.contextName
+			f.k8sEnv = test.env
+			if !test.expectError {
+				f.load()
+			} else {
+				f.loadErrString(test.expectedErrorSubstrings...)
+			}
+		})
+	}
+}
+
+func TestRemoteResource(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+remote_resource(
+  'api',
+  host='dev@devbox',
+  sync=[sync('./foo', '/app/src')],
+  run=[run('make build')],
+  restart_cmd='systemctl restart api',
+  serve_cmd='tail -f /var/log/api.log',
+)
+`)
+	f.load()
+
+	f.assertNumManifests(1)
+	m := f.assertNextManifest("api")
+	lt := m.LocalTarget()
+
+	updateCmd := lt.UpdateCmd.String()
+	assert.Contains(t, updateCmd, "rsync -az")
+	assert.Contains(t, updateCmd, `"dev@devbox:/app/src"`)
+	assert.Contains(t, updateCmd, `ssh "dev@devbox" "make build"`)
+	assert.Contains(t, updateCmd, `ssh "dev@devbox" "systemctl restart api"`)
+	assert.Contains(t, lt.ServeCmd.String(), `ssh "dev@devbox" "tail -f /var/log/api.log"`)
+
+	// The sync step's local path is watched.
+	assert.Contains(t, lt.Dependencies(), f.JoinPath("foo"))
+}
+
+func TestRemoteResourceEmpty(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+remote_resource('api', host='dev@devbox')
+`)
+	f.loadErrString("remote_resource must have at least one sync, run, restart_cmd, or serve_cmd")
+}
 
-			f.k8sContext = test.contextName
-			f.k8sEnv = test.env
-			if !test.expectError {
-				f.load()
-			} else {
-				f.loadErrString(test.expectedErrorSubstrings...)
-			}
-		})
-	}
+func TestRemoteResourceRunTrigger(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+remote_resource('api', host='dev@devbox', run=[run('make build', trigger='./src')])
+`)
+	f.loadErrString("run triggers are not supported on a remote host")
 }
 
 func TestLocalResourceOnlyUpdateCmd(t *testing.T) {
@@ -4095,6 +4743,141 @@ k8s_yaml('secret.yaml')
 	assert.Equal(t, "d29ybGQ=", string(secrets["world"].ValueEncoded))
 }
 
+func TestSecretEnvFile(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file(".secrets.env", `
+# credentials for the dev registry
+CLIENT_ID=hello12345
+CLIENT_SECRET="world67890"
+`)
+	f.file("Tiltfile", `
+creds = secret('.secrets.env')
+print(creds['CLIENT_ID'])
+`)
+
+	f.load()
+
+	secrets := f.loadResult.Secrets
+	assert.Equal(t, 2, len(secrets))
+	assert.Equal(t, ".secrets.env", secrets["hello12345"].Name)
+	assert.Equal(t, "CLIENT_ID", secrets["hello12345"].Key)
+	assert.Equal(t, "CLIENT_SECRET", secrets["world67890"].Key)
+	assert.Equal(t, "world67890", string(secrets["world67890"].Value))
+	f.assertConfigFiles("Tiltfile", ".tiltignore", ".secrets.env")
+}
+
+func TestSecretEnvFileMalformed(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file(".secrets.env", `
+CLIENT_ID
+`)
+	f.file("Tiltfile", `
+secret('.secrets.env')
+`)
+
+	f.loadErrString("expected lines of the form KEY=VALUE")
+}
+
+func TestSensitive(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+token = sensitive('supersecrettoken', name='github-token')
+print(token)
+`)
+
+	f.load()
+
+	secrets := f.loadResult.Secrets
+	assert.Equal(t, 1, len(secrets))
+	assert.Equal(t, "Tiltfile", secrets["supersecrettoken"].Name)
+	assert.Equal(t, "github-token", secrets["supersecrettoken"].Key)
+	assert.Equal(t, "supersecrettoken", string(secrets["supersecrettoken"].Value))
+}
+
+func TestReadSecretEnv(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	err := os.Setenv("TILT_TEST_SECRET", "hunter2hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = os.Unsetenv("TILT_TEST_SECRET")
+	}()
+
+	f.file("Tiltfile", `
+token = read_secret('env', 'TILT_TEST_SECRET')
+print(token)
+`)
+	f.load()
+
+	secrets := f.loadResult.Secrets
+	assert.Equal(t, 1, len(secrets))
+	assert.Equal(t, "env", secrets["hunter2hunter2"].Name)
+	assert.Equal(t, "TILT_TEST_SECRET", secrets["hunter2hunter2"].Key)
+}
+
+func TestReadSecretEnvNotSet(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+read_secret('env', 'TILT_TEST_SECRET_DOES_NOT_EXIST')
+`)
+	f.loadErrString(`environment variable "TILT_TEST_SECRET_DOES_NOT_EXIST" not set`)
+}
+
+func TestReadSecretFile(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("token.txt", "hunter2hunter2\n")
+	f.file("Tiltfile", `
+token = read_secret('file', 'token.txt')
+print(token)
+`)
+	f.load()
+
+	secrets := f.loadResult.Secrets
+	assert.Equal(t, 1, len(secrets))
+	assert.Equal(t, "file", secrets["hunter2hunter2"].Name)
+	assert.Equal(t, "token.txt", secrets["hunter2hunter2"].Key)
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "token.txt")
+}
+
+func TestReadSecretExec(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+token = read_secret('exec', 'echo hunter2hunter2', name='vault-token')
+print(token)
+`)
+	f.load()
+
+	secrets := f.loadResult.Secrets
+	assert.Equal(t, 1, len(secrets))
+	assert.Equal(t, "exec", secrets["hunter2hunter2"].Name)
+	assert.Equal(t, "vault-token", secrets["hunter2hunter2"].Key)
+}
+
+func TestReadSecretUnknownSource(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+read_secret('s3', 'bucket/token')
+`)
+	f.loadErrString(`unknown secret source "s3"`)
+}
+
 func TestDockerPruneSettings(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -4294,12 +5077,11 @@ func TestMaxParallelUpdates(t *testing.T) {
 			expectErrorContains: "must be >= 1",
 		},
 		{
-			// as more settings are configurable from this func, max_parallel_updates
-			// won't be a required arg and instead we should test that it gets
-			// set to the approprirate default; but for now, it IS required.
-			name:                "max_parallel_updates is required arg",
-			tiltfile:            "update_settings()",
-			expectErrorContains: "missing argument for max_parallel_updates",
+			// Now that several settings hang off this func, all args are
+			// optional; a bare call leaves the defaults in place.
+			name:                  "no args leaves defaults",
+			tiltfile:              "update_settings()",
+			expectedMaxBuildSlots: model.DefaultMaxParallelUpdates,
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
@@ -4320,6 +5102,247 @@ func TestMaxParallelUpdates(t *testing.T) {
 	}
 }
 
+func TestK8sUpsertTimeout(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		tiltfile            string
+		expectErrorContains string
+		expectedTimeout     time.Duration
+	}{
+		{
+			name:            "default timeout",
+			tiltfile:        "print('hello world')",
+			expectedTimeout: model.DefaultK8sUpsertTimeout,
+		},
+		{
+			name:            "set timeout",
+			tiltfile:        "update_settings(k8s_upsert_timeout_secs=120)",
+			expectedTimeout: 2 * time.Minute,
+		},
+		{
+			name:                "NaN error",
+			tiltfile:            "update_settings(k8s_upsert_timeout_secs='boop')",
+			expectErrorContains: "got string, want int",
+		},
+		{
+			name:                "must be at least a second",
+			tiltfile:            "update_settings(k8s_upsert_timeout_secs=0)",
+			expectErrorContains: "minimum k8s upsert timeout is 1s",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t)
+			defer f.TearDown()
+
+			f.file("Tiltfile", tc.tiltfile)
+
+			if tc.expectErrorContains != "" {
+				f.loadErrString(tc.expectErrorContains)
+				return
+			}
+
+			f.load()
+			assert.Equal(t, tc.expectedTimeout, f.loadResult.UpdateSettings.K8sUpsertTimeout)
+		})
+	}
+}
+
+func TestSuppressUnusedImageWarnings(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.gitInit("")
+	f.file("Dockerfile", "FROM golang:1.10")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo")))
+	f.file("Tiltfile", `
+update_settings(suppress_unused_image_warnings='gcr.io/foo:stable')
+docker_build('gcr.io/foo:stable', '.')
+k8s_yaml('foo.yaml')
+`)
+
+	f.loadAssertWarnings()
+}
+
+func TestSuppressUnusedImageWarningsList(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.gitInit("")
+	f.file("Dockerfile", "FROM golang:1.10")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo")))
+	f.file("Tiltfile", `
+update_settings(suppress_unused_image_warnings=['gcr.io/unused'])
+docker_build('gcr.io/foo:stable', '.')
+docker_build('gcr.io/unused', 'unused')
+k8s_yaml('foo.yaml')
+`)
+	f.file("unused/Dockerfile", "FROM golang:1.10")
+
+	// Only the suppressed image's warning goes away.
+	f.loadAssertWarnings(unusedImageWarning("gcr.io/foo:stable", []string{"gcr.io/foo"}))
+}
+
+func TestNotifySettings(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		tiltfile            string
+		expectErrorContains string
+		expectedURL         string
+		expectedEvents      []model.NotifyEvent
+	}{
+		{
+			name:        "no notify settings",
+			tiltfile:    "print('hello world')",
+			expectedURL: "",
+		},
+		{
+			name:        "webhook for all events",
+			tiltfile:    "notify_settings(webhook_url='https://hooks.example.com/abc')",
+			expectedURL: "https://hooks.example.com/abc",
+		},
+		{
+			name:           "webhook for some events",
+			tiltfile:       "notify_settings(webhook_url='https://hooks.example.com/abc', events=['build_failed', 'resource_ready'])",
+			expectedURL:    "https://hooks.example.com/abc",
+			expectedEvents: []model.NotifyEvent{model.NotifyEventBuildFailed, model.NotifyEventResourceReady},
+		},
+		{
+			name:        "desktop only",
+			tiltfile:    "notify_settings(desktop=True)",
+			expectedURL: "",
+		},
+		{
+			name:                "no notification sink",
+			tiltfile:            "notify_settings(webhook_url='')",
+			expectErrorContains: "requires webhook_url and/or desktop=True",
+		},
+		{
+			name:                "unknown event",
+			tiltfile:            "notify_settings(webhook_url='https://hooks.example.com/abc', events=['build_exploded'])",
+			expectErrorContains: `unknown notify event "build_exploded"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t)
+			defer f.TearDown()
+
+			f.file("Tiltfile", tc.tiltfile)
+
+			if tc.expectErrorContains != "" {
+				f.loadErrString(tc.expectErrorContains)
+				return
+			}
+
+			f.load()
+			assert.Equal(t, tc.expectedURL, f.loadResult.NotifySettings.WebhookURL)
+			assert.Equal(t, tc.expectedEvents, f.loadResult.NotifySettings.Events)
+		})
+	}
+}
+
+func TestImageSigning(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+image_signing(command='cosign sign --key dev.key "$TILT_IMAGE"')
+`)
+	f.load()
+
+	assert.Equal(t, `cosign sign --key dev.key "$TILT_IMAGE"`, f.loadResult.SigningSettings.Command)
+}
+
+func TestImageSigningEmptyCommand(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", "image_signing(command='')")
+	f.loadErrString("image_signing requires a non-empty command")
+}
+
+func TestSecuritySettings(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+security_settings(allow_exec=False, allowed_namespaces=['ns1', 'ns2'])
+`)
+	f.load()
+
+	ss := f.loadResult.SecuritySettings
+	assert.False(t, ss.AllowExec)
+	assert.True(t, ss.AllowPortForwards)
+	assert.True(t, ss.AllowClusterScopedObjects)
+	assert.Equal(t, []string{"ns1", "ns2"}, ss.AllowedNamespaces)
+}
+
+func TestSecuritySettingsDefaults(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", "print('hello world')")
+	f.load()
+
+	assert.Equal(t, model.DefaultSecuritySettings(), f.loadResult.SecuritySettings)
+}
+
+func TestSecuritySettingsCalledTwice(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("Tiltfile", `
+security_settings(allowed_namespaces=['ns1'])
+security_settings(allowed_namespaces=['ns1', 'ns2'])
+`)
+	f.loadErrString("security_settings can only be called once")
+}
+
+func TestSecuritySettingsDisallowedNamespace(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.dockerfile("foo/Dockerfile")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo"), namespace("ns2")))
+	f.gitInit("")
+	f.file("Tiltfile", `
+security_settings(allowed_namespaces=['ns1'])
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+`)
+	f.loadErrString("namespace", "ns2", "not in allowed_namespaces")
+}
+
+func TestSecuritySettingsClusterScopedObjectDenied(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.file("ns.yaml", `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: foo-ns
+`)
+	f.file("Tiltfile", `
+security_settings(allow_cluster_scoped_objects=False)
+k8s_yaml('ns.yaml')
+`)
+	f.loadErrString("cluster-scoped objects are disabled", `Namespace "foo-ns"`)
+}
+
+func TestSecuritySettingsPortForwardsDenied(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+security_settings(allow_port_forwards=False)
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', port_forwards=8000)
+`)
+	f.loadErrString("port-forwards are disabled", `resource "foo"`)
+}
+
 // recursion is disabled by default in Starlark. Make sure we've enabled it for Tiltfiles.
 func TestRecursionEnabled(t *testing.T) {
 	f := newFixture(t)
@@ -2,12 +2,14 @@ package updatesettings
 
 import (
 	"fmt"
+	"time"
 
 	"go.starlark.net/starlark"
 
 	"github.com/windmilleng/tilt/pkg/model"
 
 	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+	"github.com/windmilleng/tilt/internal/tiltfile/value"
 )
 
 // Implements functions for dealing with update settings.
@@ -27,25 +29,64 @@ func (e Extension) OnStart(env *starkit.Environment) error {
 }
 
 func (e Extension) updateSettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
-	var maxParallelUpdates int
+	var maxParallelUpdatesVal, k8sUpsertTimeoutSecsVal, suppressVal starlark.Value
 	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
-		"max_parallel_updates", &maxParallelUpdates); err != nil {
+		"max_parallel_updates?", &maxParallelUpdatesVal,
+		"k8s_upsert_timeout_secs?", &k8sUpsertTimeoutSecsVal,
+		"suppress_unused_image_warnings?", &suppressVal); err != nil {
 		return nil, err
 	}
 
-	if maxParallelUpdates < 1 {
-		return nil, fmt.Errorf("max number of parallel updates must be >= 1(got: %d)",
-			maxParallelUpdates)
-	}
+	// Settings the user didn't pass keep their current values, so
+	// calling update_settings twice doesn't reset earlier calls.
+	err := starkit.SetState(thread, func(settings model.UpdateSettings) (model.UpdateSettings, error) {
+		if maxParallelUpdatesVal != nil {
+			maxParallelUpdates, err := asIntArg(fn, "max_parallel_updates", maxParallelUpdatesVal)
+			if err != nil {
+				return settings, err
+			}
+			if maxParallelUpdates < 1 {
+				return settings, fmt.Errorf("max number of parallel updates must be >= 1(got: %d)",
+					maxParallelUpdates)
+			}
+			settings.MaxParallelUpdates = maxParallelUpdates
+		}
+
+		if k8sUpsertTimeoutSecsVal != nil {
+			timeoutSecs, err := asIntArg(fn, "k8s_upsert_timeout_secs", k8sUpsertTimeoutSecsVal)
+			if err != nil {
+				return settings, err
+			}
+			if timeoutSecs < 1 {
+				return settings, fmt.Errorf("minimum k8s upsert timeout is 1s (got: %ds)",
+					timeoutSecs)
+			}
+			settings.K8sUpsertTimeout = time.Duration(timeoutSecs) * time.Second
+		}
 
-	err := starkit.SetState(thread, func(settings model.UpdateSettings) model.UpdateSettings {
-		settings.MaxParallelUpdates = maxParallelUpdates
-		return settings
+		if suppressVal != nil {
+			names, ok := value.AsStringOrStringList(suppressVal)
+			if !ok {
+				return settings, fmt.Errorf("%s: for parameter suppress_unused_image_warnings: got %s, want string or list of strings",
+					fn.Name(), suppressVal.Type())
+			}
+			settings.SuppressUnusedImageWarnings = append(settings.SuppressUnusedImageWarnings, names...)
+		}
+
+		return settings, nil
 	})
 
 	return starlark.None, err
 }
 
+func asIntArg(fn *starlark.Builtin, name string, v starlark.Value) (int, error) {
+	i, err := starlark.AsInt32(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: for parameter %s: %v", fn.Name(), name, err)
+	}
+	return i, nil
+}
+
 var _ starkit.StatefulExtension = Extension{}
 
 func MustState(model starkit.Model) model.UpdateSettings {
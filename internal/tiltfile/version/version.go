@@ -28,7 +28,26 @@ func (e Extension) NewState() interface{} {
 }
 
 func (e Extension) OnStart(env *starkit.Environment) error {
-	return env.AddBuiltin("version_settings", e.setVersionSettings)
+	err := env.AddBuiltin("version_settings", e.setVersionSettings)
+	if err != nil {
+		return err
+	}
+
+	err = env.AddValue("tilt.version", starlark.String(e.tiltVersion))
+	if err != nil {
+		return err
+	}
+
+	// Answers at call time, so builtins registered by later extensions
+	// (including linked-in plugins) are visible too.
+	return env.AddBuiltin("tilt.has_builtin", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var name string
+		err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "name", &name)
+		if err != nil {
+			return nil, err
+		}
+		return starlark.Bool(env.HasValue(name)), nil
+	})
 }
 
 func (e Extension) setVersionSettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
@@ -90,6 +90,27 @@ version_settings(constraint='0.x')
 	require.False(t, settings.CheckUpdates)
 }
 
+func TestTiltVersionValue(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", fmt.Sprintf(`
+tilt.version == '%s' or fail('got %%s' %% tilt.version)
+`, TestingVersion))
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+}
+
+func TestHasBuiltin(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+tilt.has_builtin('version_settings') or fail('version_settings should exist')
+tilt.has_builtin('tilt.has_builtin') or fail('tilt.has_builtin should exist')
+not tilt.has_builtin('flags.define_bool') or fail('flags.define_bool should not exist')
+not tilt.has_builtin('nope') or fail('nope should not exist')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+}
+
 const TestingVersion = "0.5.3"
 
 func NewFixture(tb testing.TB) *starkit.Fixture {
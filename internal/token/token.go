@@ -9,6 +9,10 @@ import (
 
 const tokenFileName = "token"
 
+// Lets CI runners and self-hosted backends inject a token without
+// touching the on-disk token file.
+const tokenEnvName = "TILT_CLOUD_TOKEN"
+
 type Token string
 
 func (t Token) String() string {
@@ -16,6 +20,10 @@ func (t Token) String() string {
 }
 
 func GetOrCreateToken(dir *dirs.WindmillDir) (Token, error) {
+	if env := os.Getenv(tokenEnvName); env != "" {
+		return Token(env), nil
+	}
+
 	token, err := getExistingToken(dir)
 	if os.IsNotExist(err) {
 		u := uuid.New()
@@ -1,6 +1,7 @@
 package token
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -26,6 +27,16 @@ func TestGetOrCreateToken(t *testing.T) {
 	require.Equal(f.t, t1, t2)
 }
 
+func TestTokenFromEnv(t *testing.T) {
+	f := newFixture(t)
+	os.Setenv(tokenEnvName, "my-team-token")
+	defer os.Unsetenv(tokenEnvName)
+
+	tok, err := GetOrCreateToken(f.dir)
+	require.NoError(t, err)
+	require.Equal(t, Token("my-team-token"), tok)
+}
+
 type fixture struct {
 	*tempdir.TempDirFixture
 	t   *testing.T
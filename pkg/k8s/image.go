@@ -0,0 +1,43 @@
+package k8s
+
+import (
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/windmilleng/tilt/internal/container"
+	"github.com/windmilleng/tilt/internal/k8s"
+)
+
+// InjectImageDigest replaces image references matching selector (an image
+// name, e.g. "gcr.io/foo/bar") with injectRef (a full reference, usually
+// tagged or digested), and sets the image pull policy on the containers it
+// touched. Returns whether anything matched.
+//
+// Tag and digest on the selector are ignored: any reference with the same
+// name matches.
+func InjectImageDigest(entity Entity, selector string, injectRef string, policy v1.PullPolicy) (Entity, bool, error) {
+	selRef, err := container.ParseNamed(selector)
+	if err != nil {
+		return Entity{}, false, err
+	}
+	ref, err := container.ParseNamed(injectRef)
+	if err != nil {
+		return Entity{}, false, err
+	}
+	return k8s.InjectImageDigest(entity, container.NameSelector(selRef), ref, false, policy)
+}
+
+// InjectImagePullPolicy sets the pull policy on every container in the
+// entity.
+func InjectImagePullPolicy(entity Entity, policy v1.PullPolicy) (Entity, error) {
+	return k8s.InjectImagePullPolicy(entity, policy)
+}
+
+// EntityHasImage reports whether the entity runs a container whose image
+// reference has the given name.
+func EntityHasImage(entity Entity, image string) (bool, error) {
+	ref, err := container.ParseNamed(image)
+	if err != nil {
+		return false, err
+	}
+	return entity.HasImage(container.NameSelector(ref), nil, false)
+}
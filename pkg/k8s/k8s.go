@@ -0,0 +1,54 @@
+// Package k8s exposes Tilt's Kubernetes YAML handling -- entity parsing,
+// serialization, image injection, label management, and selector matching --
+// as a stable API, so other tools (CD generators, linters) can reuse it
+// without depending on Tilt internals.
+//
+// The implementation lives in internal/k8s, where Tilt's engine uses it
+// directly; this package re-exports the pieces that are safe to build on.
+package k8s
+
+import (
+	"io"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+)
+
+// Entity is a single Kubernetes object, plus the metadata Tilt needs to
+// work with it. It wraps both structured API objects and unstructured
+// ones (CRDs), and its methods (Name, Namespace, GVK, ...) work on either.
+type Entity = k8s.K8sEntity
+
+// Namespace is a Kubernetes namespace name. The empty namespace stands
+// for "default".
+type Namespace = k8s.Namespace
+
+// ParseYAML parses a stream of Kubernetes YAML documents, flattening any
+// v1.Lists into their items. Unregistered types decode as unstructured
+// entities rather than erroring.
+func ParseYAML(r io.Reader) ([]Entity, error) {
+	return k8s.ParseYAML(r)
+}
+
+// ParseYAMLFromString is ParseYAML on a string.
+func ParseYAMLFromString(yaml string) ([]Entity, error) {
+	return k8s.ParseYAMLFromString(yaml)
+}
+
+// SerializeYAML renders entities back into a multi-document YAML stream.
+func SerializeYAML(entities []Entity) (string, error) {
+	return k8s.SerializeSpecYAML(entities)
+}
+
+// SortedEntities returns a copy of the entities sorted into a sensible
+// apply order (namespaces and other cluster-level objects first,
+// workloads last).
+func SortedEntities(entities []Entity) []Entity {
+	return k8s.SortedEntities(entities)
+}
+
+// UniqueNames returns a short display name for each entity, adding name
+// components (kind, namespace, group) only as needed to de-duplicate.
+// Each name has at least minComponents components.
+func UniqueNames(entities []Entity, minComponents int) []string {
+	return k8s.UniqueNames(entities, minComponents)
+}
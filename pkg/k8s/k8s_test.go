@@ -0,0 +1,78 @@
+package k8s_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/windmilleng/tilt/internal/k8s/testyaml"
+	"github.com/windmilleng/tilt/pkg/k8s"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+func TestParseSerializeRoundTrip(t *testing.T) {
+	entities, err := k8s.ParseYAMLFromString(testyaml.SanchoYAML)
+	require.NoError(t, err)
+	require.Len(t, entities, 1)
+
+	e := entities[0]
+	assert.Equal(t, "sancho", e.Name())
+	assert.Equal(t, k8s.Namespace("sancho-ns"), e.Namespace())
+	assert.Equal(t, "Deployment", e.GVK().Kind)
+
+	out, err := k8s.SerializeYAML(entities)
+	require.NoError(t, err)
+	assert.Contains(t, out, "name: sancho")
+
+	reparsed, err := k8s.ParseYAML(strings.NewReader(out))
+	require.NoError(t, err)
+	require.Len(t, reparsed, 1)
+	assert.Equal(t, e.Name(), reparsed[0].Name())
+}
+
+func TestInjectImageDigest(t *testing.T) {
+	entities, err := k8s.ParseYAMLFromString(testyaml.SanchoYAML)
+	require.NoError(t, err)
+	require.Len(t, entities, 1)
+
+	newRef := testyaml.SanchoImage + ":deadbeef"
+	e, replaced, err := k8s.InjectImageDigest(entities[0], testyaml.SanchoImage, newRef, v1.PullNever)
+	require.NoError(t, err)
+	assert.True(t, replaced)
+
+	out, err := k8s.SerializeYAML([]k8s.Entity{e})
+	require.NoError(t, err)
+	assert.Contains(t, out, newRef)
+	assert.Contains(t, out, "imagePullPolicy: Never")
+
+	hasImage, err := k8s.EntityHasImage(e, testyaml.SanchoImage)
+	require.NoError(t, err)
+	assert.True(t, hasImage)
+}
+
+func TestInjectLabels(t *testing.T) {
+	entities, err := k8s.ParseYAMLFromString(testyaml.SanchoYAML)
+	require.NoError(t, err)
+	require.Len(t, entities, 1)
+
+	e, err := k8s.InjectLabels(entities[0], []model.LabelPair{
+		{Key: "owner", Value: "me"},
+	})
+	require.NoError(t, err)
+
+	out, err := k8s.SerializeYAML([]k8s.Entity{e})
+	require.NoError(t, err)
+	assert.Contains(t, out, "owner: me")
+}
+
+func TestUniqueNames(t *testing.T) {
+	entities, err := k8s.ParseYAMLFromString(testyaml.SanchoYAML + "\n---\n" + testyaml.DoggosDeploymentYaml)
+	require.NoError(t, err)
+	require.Len(t, entities, 2)
+
+	names := k8s.UniqueNames(entities, 1)
+	assert.Equal(t, []string{"sancho", "doggos"}, names)
+}
@@ -0,0 +1,36 @@
+package k8s
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/windmilleng/tilt/internal/k8s"
+	"github.com/windmilleng/tilt/pkg/model"
+)
+
+// InjectLabels adds the labels to the entity's metadata and, for workload
+// kinds, to their pod template and selector, leaving any existing labels
+// in place.
+func InjectLabels(entity Entity, lps []model.LabelPair) (Entity, error) {
+	return k8s.InjectLabels(entity, lps)
+}
+
+// OverwriteLabels is InjectLabels, but replaces existing values for the
+// same keys.
+func OverwriteLabels(entity Entity, lps []model.LabelPair) (Entity, error) {
+	return k8s.OverwriteLabels(entity, lps)
+}
+
+// ManagedByTiltSelector selects the objects that Tilt deployed.
+func ManagedByTiltSelector() labels.Selector {
+	return k8s.ManagedByTiltSelector()
+}
+
+// LabelPairsToSelector builds an equality selector matching all the pairs.
+func LabelPairsToSelector(lps []model.LabelPair) labels.Selector {
+	return k8s.LabelPairsToSelector(lps)
+}
+
+// SelectorEqual reports whether two selectors match the same objects.
+func SelectorEqual(a, b labels.Selector) bool {
+	return k8s.SelectorEqual(a, b)
+}
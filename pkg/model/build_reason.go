@@ -18,6 +18,10 @@ const (
 	BuildReasonFlagTriggerWeb
 	BuildReasonFlagTriggerCLI
 	BuildReasonFlagTriggerUnknown
+
+	// A trigger that explicitly requests a full image build,
+	// bypassing live_update.
+	BuildReasonFlagForceFullBuild
 )
 
 func (r BuildReason) With(flag BuildReason) BuildReason {
@@ -49,6 +53,7 @@ var translations = map[BuildReason]string{
 	BuildReasonFlagTriggerWeb:     "Web Trigger",
 	BuildReasonFlagTriggerCLI:     "CLI Trigger",
 	BuildReasonFlagTriggerUnknown: "Unknown Trigger",
+	BuildReasonFlagForceFullBuild: "Force Full Build",
 }
 
 var triggerBuildReasons = []BuildReason{
@@ -65,6 +70,7 @@ var allBuildReasons = []BuildReason{
 	BuildReasonFlagTriggerWeb,
 	BuildReasonFlagTriggerCLI,
 	BuildReasonFlagTriggerUnknown,
+	BuildReasonFlagForceFullBuild,
 }
 
 func (r BuildReason) String() string {
@@ -4,7 +4,11 @@ import (
 	"time"
 )
 
-const BuildHistoryLimit = 2
+// How many build records we keep per resource. The records themselves
+// are small (the logs live in the logstore, keyed by span id), so we
+// keep enough of them that past builds stay inspectable in the HUD,
+// web UI, and API.
+const BuildHistoryLimit = 10
 
 type BuildType string
 
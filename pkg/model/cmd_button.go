@@ -0,0 +1,30 @@
+package model
+
+import "github.com/windmilleng/tilt/pkg/webview"
+
+// A button rendered next to a resource in the UIs. Clicking it runs the
+// given command, either locally or inside the resource's running
+// container, with output streamed to the resource's log. Set via the
+// cmd_button builtin.
+type CmdButton struct {
+	Name        string
+	Cmd         Cmd
+	InContainer bool
+}
+
+func (b CmdButton) ToProto() *webview.CmdButton {
+	return &webview.CmdButton{
+		Name:        b.Name,
+		Argv:        b.Cmd.Argv,
+		InContainer: b.InContainer,
+	}
+}
+
+func ButtonsToProto(buttons []CmdButton) []*webview.CmdButton {
+	ret := make([]*webview.CmdButton, len(buttons))
+	for i, b := range buttons {
+		ret[i] = b.ToProto()
+	}
+
+	return ret
+}
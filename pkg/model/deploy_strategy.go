@@ -0,0 +1,15 @@
+package model
+
+// DeployStrategy controls how a resource's endpoints cut over to new
+// pods during a rollout.
+type DeployStrategy string
+
+const (
+	// Point endpoints at the newest pod as soon as it exists, even if
+	// it isn't serving yet (the historical behavior).
+	DeployStrategyDefault DeployStrategy = ""
+
+	// Keep endpoints pointed at the old pods until the new ones are
+	// ready, so the dev endpoint never 502s during a rollout.
+	DeployStrategyBlueGreen DeployStrategy = "blue_green"
+)
@@ -16,6 +16,15 @@ type DockerComposeTarget struct {
 	YAMLRaw []byte // for diff'ing when config files change
 	DfRaw   []byte // for diff'ing when config files change
 
+	// Whether the service defines a healthcheck. When it does, Tilt waits
+	// for the container to report healthy before treating the service as
+	// ready, instead of marking it ready as soon as it starts.
+	HasHealthcheck bool
+
+	// Bring the service back up when its container exits with an error,
+	// set via dc_resource(auto_restart=True).
+	AutoRestart bool
+
 	// TODO(nick): It might eventually make sense to represent
 	// Tiltfile as a separate nodes in the build graph, rather
 	// than duplicating it in each DockerComposeTarget.
@@ -238,6 +238,9 @@ type DockerBuild struct {
 	// Named 'tag' for consistency with how it's used throughout the docker API,
 	// even though this is really more like a reference.NamedTagged
 	ExtraTags []string
+
+	// How to name the tag of the built image.
+	TagStrategy TagStrategy
 }
 
 func (DockerBuild) buildDetails() {}
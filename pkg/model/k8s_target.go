@@ -24,6 +24,18 @@ type K8sTarget struct {
 	// for easy access. This should duplicate what's specified in the YAML.
 	ObjectRefs []v1.ObjectReference
 
+	// The kube context to deploy this target to. Empty means the context
+	// Tilt started against.
+	KubeContext string
+
+	// User-supplied command override for this workload's primary containers
+	// (i.e. overrides k8s yaml "command", container ENTRYPOINT, etc.)
+	OverrideCmd Cmd
+
+	// User-supplied args override for this workload's primary containers.
+	// (i.e. overrides k8s yaml "args")
+	OverrideArgs OverrideArgs
+
 	dependencyIDs []TargetID
 
 	// Map configRef -> number of times we (expect to) inject it.
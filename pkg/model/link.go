@@ -0,0 +1,20 @@
+package model
+
+// Link is a URL attached to a resource in the UIs, alongside the
+// endpoints Tilt derives from port-forwards and published ports.
+type Link struct {
+	URL string
+
+	// If non-empty, the engine periodically GETs this URL and marks the
+	// link up when the response status matches HealthCheckExpectedStatus.
+	HealthCheckURL            string
+	HealthCheckExpectedStatus int
+
+	// If true, the resource doesn't count as ready while the link's
+	// health check is failing.
+	RequiredForReadiness bool
+}
+
+func (l Link) HasHealthCheck() bool {
+	return l.HealthCheckURL != ""
+}
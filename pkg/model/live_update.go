@@ -68,6 +68,10 @@ func (l LiveUpdateFallBackOnStep) liveUpdateStep() {}
 // Specifies that changes to local path `Source` should be synced to container path `Dest`
 type LiveUpdateSyncStep struct {
 	Source, Dest string
+
+	// Optional "uid" or "uid:gid" the synced files should be owned by in the
+	// container.
+	RunAs string
 }
 
 func (l LiveUpdateSyncStep) liveUpdateStep() {}
@@ -76,6 +80,7 @@ func (l LiveUpdateSyncStep) toSync() Sync {
 	return Sync{
 		LocalPath:     l.Source,
 		ContainerPath: l.Dest,
+		RunAs:         l.RunAs,
 	}
 }
 
@@ -11,7 +11,7 @@ const BaseDir = "/base/directory"
 func TestNewLiveUpdate(t *testing.T) {
 	steps := []LiveUpdateStep{
 		LiveUpdateFallBackOnStep{[]string{"quu", "qux"}},
-		LiveUpdateSyncStep{"foo", "bar"},
+		LiveUpdateSyncStep{Source: "foo", Dest: "bar"},
 		LiveUpdateRunStep{Cmd{[]string{"hello"}}, NewPathSet([]string{"goodbye"}, BaseDir)},
 		LiveUpdateRestartContainerStep{},
 	}
@@ -24,7 +24,7 @@ func TestNewLiveUpdate(t *testing.T) {
 }
 
 func TestNewLiveUpdateRestartContainerNotLast(t *testing.T) {
-	steps := []LiveUpdateStep{LiveUpdateRestartContainerStep{}, LiveUpdateSyncStep{"foo", "bar"}}
+	steps := []LiveUpdateStep{LiveUpdateRestartContainerStep{}, LiveUpdateSyncStep{Source: "foo", Dest: "bar"}}
 	_, err := NewLiveUpdate(steps, BaseDir)
 	if !assert.Error(t, err) {
 		return
@@ -33,7 +33,7 @@ func TestNewLiveUpdateRestartContainerNotLast(t *testing.T) {
 }
 
 func TestNewLiveUpdateSyncAfterRun(t *testing.T) {
-	steps := []LiveUpdateStep{LiveUpdateRunStep{}, LiveUpdateSyncStep{"foo", "bar"}}
+	steps := []LiveUpdateStep{LiveUpdateRunStep{}, LiveUpdateSyncStep{Source: "foo", Dest: "bar"}}
 	_, err := NewLiveUpdate(steps, BaseDir)
 	if !assert.Error(t, err) {
 		return
@@ -44,9 +44,9 @@ func TestNewLiveUpdateSyncAfterRun(t *testing.T) {
 func TestNewLiveUpdateFallBackOnStepsNotFirst(t *testing.T) {
 	steps := []LiveUpdateStep{
 		LiveUpdateFallBackOnStep{[]string{"a"}},
-		LiveUpdateSyncStep{"foo", "bar"},
+		LiveUpdateSyncStep{Source: "foo", Dest: "bar"},
 		LiveUpdateFallBackOnStep{[]string{"b", "c"}},
-		LiveUpdateSyncStep{"baz", "qux"},
+		LiveUpdateSyncStep{Source: "baz", Dest: "qux"},
 	}
 	_, err := NewLiveUpdate(steps, BaseDir)
 	if !assert.Error(t, err) {
@@ -312,6 +312,17 @@ func (s *LogStore) tailHelper(n int, spans map[SpanID]*Span, showManifestPrefix
 	})
 }
 
+// Copy returns a snapshot of the log store that stays valid after the
+// original goes back to being mutated by the state loop.
+//
+// Segments are copied because secret scrubbing edits them in place.
+func (s *LogStore) Copy() *LogStore {
+	ret := *s
+	ret.spans = s.cloneSpanMap()
+	ret.segments = append([]LogSegment{}, s.segments...)
+	return &ret
+}
+
 func (s *LogStore) cloneSpanMap() map[SpanID]*Span {
 	newSpans := make(map[SpanID]*Span, len(s.spans))
 	for spanID, span := range s.spans {
@@ -670,6 +681,18 @@ func (s *LogStore) computeLen() int {
 	return result
 }
 
+// The maximum length of the log, in bytes, before we truncate it.
+func (s *LogStore) MaxLength() int {
+	return s.maxLogLengthInBytes
+}
+
+// Lowers the length limit and truncates immediately. Used to shed memory
+// when Tilt is over its memory limit.
+func (s *LogStore) SetMaxLength(n int) {
+	s.maxLogLengthInBytes = n
+	s.ensureMaxLength()
+}
+
 // After a log hits its limit, we need to truncate it to keep it small
 // we do this by cutting a big chunk at a time, so that we have rarer, larger changes, instead of
 // a small change every time new data is written to the log
@@ -3,7 +3,9 @@ package model
 import (
 	"fmt"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"k8s.io/apimachinery/pkg/labels"
 
@@ -43,6 +45,20 @@ type Manifest struct {
 	// The resource in this manifest will not be built until all of its dependencies have been
 	// ready at least once.
 	ResourceDependencies []ManifestName
+
+	// Custom actions the user can run against this resource from the UIs.
+	Buttons []CmdButton
+
+	// URLs the user attached to this resource in the Tiltfile, shown in
+	// the UIs next to the derived endpoints.
+	Links []Link
+
+	// If set, how long after a deploy pods may stay unready before the
+	// resource is marked degraded instead of in-progress.
+	ReadinessTimeout time.Duration
+
+	// How endpoints cut over to new pods during a rollout.
+	DeployStrategy DeployStrategy
 }
 
 func (m Manifest) ID() TargetID {
@@ -215,7 +231,7 @@ func ChangesInvalidateBuild(old, new Manifest) bool {
 
 }
 func (m1 Manifest) fieldGroupsEqual(m2 Manifest) (primitivesEq, dockerEq, k8sEq, dcEq, localEq, depsEq bool) {
-	primitivesEq = m1.Name == m2.Name && m1.TriggerMode == m2.TriggerMode
+	primitivesEq = m1.Name == m2.Name && m1.TriggerMode == m2.TriggerMode && m1.ReadinessTimeout == m2.ReadinessTimeout && m1.DeployStrategy == m2.DeployStrategy && DeepEqual(m1.Buttons, m2.Buttons) && DeepEqual(m1.Links, m2.Links)
 
 	dockerEq = DeepEqual(m1.ImageTargets, m2.ImageTargets)
 
@@ -260,6 +276,29 @@ var _ TargetSpec = Manifest{}
 type Sync struct {
 	LocalPath     string
 	ContainerPath string
+
+	// Who the synced files should be owned by in the container, as
+	// "uid" or "uid:gid". Empty means ownership is left alone
+	// (i.e., the files land owned by root).
+	RunAs string
+}
+
+// ParseRunAs parses a sync run_as= option of the form "uid" or "uid:gid".
+// When no gid is given, it defaults to the uid.
+func ParseRunAs(runAs string) (uid int, gid int, err error) {
+	parts := strings.SplitN(runAs, ":", 2)
+	uid, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("run_as must be of the form 'uid' or 'uid:gid'; got %q", runAs)
+	}
+	gid = uid
+	if len(parts) == 2 {
+		gid, err = strconv.Atoi(parts[1])
+		if err != nil {
+			return 0, 0, fmt.Errorf("run_as must be of the form 'uid' or 'uid:gid'; got %q", runAs)
+		}
+	}
+	return uid, gid, nil
 }
 
 type Dockerignore struct {
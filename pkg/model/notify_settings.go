@@ -0,0 +1,46 @@
+package model
+
+type NotifyEvent string
+
+const (
+	NotifyEventBuildFailed    NotifyEvent = "build_failed"
+	NotifyEventBuildSucceeded NotifyEvent = "build_succeeded"
+	NotifyEventResourceReady  NotifyEvent = "resource_ready"
+	NotifyEventCrash          NotifyEvent = "crash"
+)
+
+var NotifyEvents = []NotifyEvent{
+	NotifyEventBuildFailed,
+	NotifyEventBuildSucceeded,
+	NotifyEventResourceReady,
+	NotifyEventCrash,
+}
+
+type NotifySettings struct {
+	WebhookURL string
+
+	// Show native OS notifications (macOS notification center,
+	// libnotify) in addition to, or instead of, the webhook.
+	Desktop bool
+
+	// The events to notify on. Empty means all events.
+	Events []NotifyEvent
+}
+
+// Whether the user asked to be notified about this event. Each
+// notification sink (webhook, desktop) applies its own checks on top.
+func (ns NotifySettings) EventSelected(event NotifyEvent) bool {
+	if len(ns.Events) == 0 {
+		return true
+	}
+	for _, e := range ns.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func DefaultNotifySettings() NotifySettings {
+	return NotifySettings{}
+}
@@ -0,0 +1,48 @@
+package model
+
+// ResourceState summarizes where a resource is in its lifecycle at a
+// finer grain than RuntimeStatus, which only distinguishes ok, pending,
+// and error. Every state comes with a human-readable reason string so
+// UIs can explain *why* a resource is waiting, not just that it is.
+type ResourceState string
+
+const (
+	ResourceStateUnknown ResourceState = "unknown"
+
+	// The resource never builds automatically and hasn't been triggered.
+	ResourceStateDisabled ResourceState = "disabled"
+
+	// The first build is blocked on resource_deps that aren't ready yet.
+	ResourceStateWaitingOnDeps ResourceState = "waiting-on-deps"
+
+	// The first build is in progress.
+	ResourceStateBuilding ResourceState = "building"
+
+	// A subsequent build is in progress.
+	ResourceStateUpdating ResourceState = "updating"
+
+	// Built successfully, waiting for the runtime to come up.
+	ResourceStateDeploying ResourceState = "deploying"
+
+	// The pod exists but hasn't been scheduled onto a node.
+	ResourceStateWaitingForPodSchedule ResourceState = "waiting-for-pod-schedule"
+
+	// The pod is blocked pulling its image.
+	ResourceStateImagePulling ResourceState = "image-pulling"
+
+	// Built and the runtime is healthy.
+	ResourceStateReady ResourceState = "ready"
+
+	// The deployed objects have been edited out-of-band since our last
+	// apply; re-applying (via a triggered update) resolves it.
+	ResourceStateDrifted ResourceState = "drifted"
+
+	// The last build failed, or the runtime is in an error state.
+	ResourceStateDegraded ResourceState = "degraded"
+)
+
+// A state plus the reason the resource is in that state.
+type ResourceStateStatus struct {
+	State  ResourceState
+	Reason string
+}
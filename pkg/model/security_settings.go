@@ -0,0 +1,42 @@
+package model
+
+// Restrictions a committed Tiltfile can place on what the Tilt session
+// is allowed to do, so platform teams can hand Tilt to app teams
+// against shared clusters.
+type SecuritySettings struct {
+	// Whether API endpoints that inject actions into the running engine
+	// (e.g., restarting pods) are enabled.
+	AllowExec bool
+
+	// Whether resources may declare port-forwards into the cluster.
+	AllowPortForwards bool
+
+	// If non-empty, every namespaced object Tilt deploys must live in
+	// one of these namespaces. Empty means any namespace is fine.
+	AllowedNamespaces []string
+
+	// Whether Tilt may deploy cluster-scoped objects
+	// (Namespaces, CRDs, ClusterRoles, and the like).
+	AllowClusterScopedObjects bool
+}
+
+// Everything is allowed until a Tiltfile says otherwise.
+func DefaultSecuritySettings() SecuritySettings {
+	return SecuritySettings{
+		AllowExec:                 true,
+		AllowPortForwards:         true,
+		AllowClusterScopedObjects: true,
+	}
+}
+
+func (s SecuritySettings) NamespaceAllowed(ns string) bool {
+	if len(s.AllowedNamespaces) == 0 {
+		return true
+	}
+	for _, allowed := range s.AllowedNamespaces {
+		if ns == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package model
+
+// Settings for signing or attesting images, set by image_signing() in
+// the Tiltfile.
+type SigningSettings struct {
+	// A command run once per image after it's pushed and before it's
+	// deployed, with TILT_IMAGE set to the pushed ref. A non-zero exit
+	// blocks the deploy.
+	Command string
+}
+
+func (s SigningSettings) Empty() bool {
+	return s.Command == ""
+}
@@ -0,0 +1,25 @@
+package model
+
+import "strings"
+
+// How Tilt tags the images it builds, set by image_tag_scheme() in the
+// Tiltfile.
+//
+// Whatever the scheme, the deployed YAML always references the exact tag
+// that was built and pushed, so the two can't drift apart.
+type TagStrategy struct {
+	// A template for the tag, with these placeholders:
+	//   {hash}   - a prefix of the image's content hash
+	//   {gitsha} - the abbreviated sha of git HEAD at build time
+	//   {user}   - the current OS user
+	// Empty means the default content-hash scheme ("tilt-{hash}").
+	Template string
+}
+
+func (s TagStrategy) Empty() bool {
+	return s.Template == ""
+}
+
+func (s TagStrategy) UsesGitSHA() bool {
+	return strings.Contains(s.Template, "{gitsha}")
+}
@@ -3,4 +3,7 @@ package model
 type TelemetrySettings struct {
 	Cmd     Cmd
 	Workdir string // directory from which this Cmd should be run
+
+	// HTTP(S) collector to POST spans to, as an alternative to Cmd.
+	Endpoint string
 }
@@ -16,12 +16,15 @@ const (
 	TriggerModeManualAfterInitial TriggerMode = iota
 	// Tilt requires manual intervention for all builds, and never automatically performs a build
 	TriggerModeManualIncludingInitial TriggerMode = iota
+	// Tilt requires manual intervention for the initial build, but performs non-initial builds automatically.
+	// I.e. the resource exists but does nothing until the user first triggers it.
+	TriggerModeAutoWithManualInit TriggerMode = iota
 )
 
 func (t TriggerMode) AutoOnChange() bool {
-	return t == TriggerModeAuto
+	return t == TriggerModeAuto || t == TriggerModeAutoWithManualInit
 }
 
 func (t TriggerMode) AutoInitial() bool {
-	return t != TriggerModeManualIncludingInitial
+	return t == TriggerModeAuto || t == TriggerModeManualAfterInitial
 }
@@ -1,9 +1,19 @@
 package model
 
+import "time"
+
 var DefaultMaxParallelUpdates = 3
 
+// How long a `kubectl apply` gets before we give up on the update.
+const DefaultK8sUpsertTimeout = 15 * time.Second
+
 type UpdateSettings struct {
-	MaxParallelUpdates int // max number of updates to run concurrently
+	MaxParallelUpdates int           // max number of updates to run concurrently
+	K8sUpsertTimeout   time.Duration // timeout for applying a set of entities to the cluster
+
+	// Image names the user told us not to warn about when they're not
+	// used in any deploy config.
+	SuppressUnusedImageWarnings []string
 }
 
 func (us UpdateSettings) MaxParallelUpdatesMinOne() int {
@@ -13,6 +23,18 @@ func (us UpdateSettings) MaxParallelUpdatesMinOne() int {
 	return us.MaxParallelUpdates
 }
 
+func (us UpdateSettings) SuppressesUnusedImageWarning(name string) bool {
+	for _, n := range us.SuppressUnusedImageWarnings {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
 func DefaultUpdateSettings() UpdateSettings {
-	return UpdateSettings{MaxParallelUpdates: DefaultMaxParallelUpdates}
+	return UpdateSettings{
+		MaxParallelUpdates: DefaultMaxParallelUpdates,
+		K8sUpsertTimeout:   DefaultK8sUpsertTimeout,
+	}
 }
@@ -0,0 +1,22 @@
+package starkit
+
+import "sync"
+
+var registryMu sync.Mutex
+var registry []Extension
+
+// Register an extension to be included in every Tiltfile execution.
+// Intended to be called from init() in a plugin registration file;
+// see the top-level plugins package.
+func RegisterExtension(ext Extension) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, ext)
+}
+
+// All extensions registered with RegisterExtension, in registration order.
+func RegisteredExtensions() []Extension {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return append([]Extension{}, registry...)
+}
@@ -0,0 +1,50 @@
+// Package starkit is the public face of the Starkit extension API, for
+// building Tilt binaries with out-of-tree Starlark builtins.
+//
+// The types here alias the internal Starkit implementation, so an
+// extension written against this package is interchangeable with the
+// built-in ones (io, config, etc). Register extensions with
+// RegisterExtension and link them in with the tiltplugins build tag;
+// see the top-level plugins package for the mechanics.
+package starkit
+
+import (
+	"github.com/windmilleng/tilt/internal/tiltfile/starkit"
+)
+
+// The execution environment extensions hook into. Most extensions only
+// need AddBuiltin and AddValue from their OnStart hook.
+type Environment = starkit.Environment
+
+// An extension to a starlark execution environment. See the internal
+// starkit docs for the OnStart/OnExec/state-model contract.
+type Extension = starkit.Extension
+
+// An extension that's called before each new Starlark file is loaded.
+type OnExecExtension = starkit.OnExecExtension
+
+// An extension that's called before each builtin is called.
+type OnBuiltinCallExtension = starkit.OnBuiltinCallExtension
+
+// An extension that accumulates state with the init/reduce pattern.
+type StatefulExtension = starkit.StatefulExtension
+
+// An extension that rewrites load() paths, like the ext:// handler.
+type LoadInterceptor = starkit.LoadInterceptor
+
+// The signature of a Starlark builtin.
+type Function = starkit.Function
+
+// The accumulated state of all extensions after execution.
+type Model = starkit.Model
+
+// Helpers for use inside builtins.
+var (
+	UnpackArgs        = starkit.UnpackArgs
+	SetState          = starkit.SetState
+	ModelFromThread   = starkit.ModelFromThread
+	ContextFromThread = starkit.ContextFromThread
+	AbsPath           = starkit.AbsPath
+	AbsWorkingDir     = starkit.AbsWorkingDir
+	CurrentExecPath   = starkit.CurrentExecPath
+)
@@ -0,0 +1,47 @@
+package starkit_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.starlark.net/starlark"
+
+	"github.com/windmilleng/tilt/internal/tiltfile/config"
+	tiltfileio "github.com/windmilleng/tilt/internal/tiltfile/io"
+	internalstarkit "github.com/windmilleng/tilt/internal/tiltfile/starkit"
+	"github.com/windmilleng/tilt/pkg/model"
+	"github.com/windmilleng/tilt/pkg/starkit"
+)
+
+// The built-in io and config (flags) extensions satisfy the public API,
+// so an out-of-tree extension written against it is interchangeable
+// with the ones compiled into tilt.
+var _ starkit.OnExecExtension = tiltfileio.NewExtension()
+var _ starkit.StatefulExtension = tiltfileio.NewExtension()
+var _ starkit.StatefulExtension = config.NewExtension(model.UserConfigState{})
+
+func TestRegisteredExtensionAddsBuiltin(t *testing.T) {
+	starkit.RegisterExtension(helloExtension{})
+
+	exts := starkit.RegisteredExtensions()
+	require.NotEmpty(t, exts)
+
+	f := internalstarkit.NewFixture(t, exts...)
+	defer f.TearDown()
+
+	f.File("Tiltfile", "print(hello())")
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Contains(t, f.PrintOutput(), "world")
+}
+
+type helloExtension struct{}
+
+func (helloExtension) OnStart(env *starkit.Environment) error {
+	return env.AddBuiltin("hello", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		return starlark.String("world"), nil
+	})
+}
+
+var _ starkit.Extension = helloExtension{}
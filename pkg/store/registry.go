@@ -0,0 +1,22 @@
+package store
+
+import "sync"
+
+var registryMu sync.Mutex
+var registry []Subscriber
+
+// Register a subscriber to be attached to the store on startup.
+// Intended to be called from init() in a plugin registration file;
+// see the top-level plugins package.
+func RegisterSubscriber(sub Subscriber) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, sub)
+}
+
+// All subscribers registered with RegisterSubscriber, in registration order.
+func RegisteredSubscribers() []Subscriber {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return append([]Subscriber{}, registry...)
+}
@@ -0,0 +1,32 @@
+// Package store is the public face of the store subscriber API, for
+// building Tilt binaries with out-of-tree subscribers (custom
+// reconcilers, notification hooks, and the like).
+//
+// The types here alias the internal store implementation, so a
+// subscriber written against this package is interchangeable with the
+// built-in ones. Register subscribers with RegisterSubscriber and link
+// them in with the tiltplugins build tag; see the top-level plugins
+// package for the mechanics.
+package store
+
+import (
+	"github.com/windmilleng/tilt/internal/store"
+)
+
+// A read-only view of the store. Subscribers read state with
+// RLockState/RUnlockState and effect change with Dispatch.
+type RStore = store.RStore
+
+// Notified whenever engine state changes. See the internal store docs
+// for the threading contract.
+type Subscriber = store.Subscriber
+
+// Optional lifecycle hooks for subscribers that need them.
+type SetUpper = store.SetUpper
+type TearDowner = store.TearDowner
+
+// The full engine state, as seen under RLockState.
+type EngineState = store.EngineState
+
+// A state-change request, for use with RStore.Dispatch.
+type Action = store.Action
@@ -0,0 +1,46 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/windmilleng/tilt/internal/history"
+	"github.com/windmilleng/tilt/internal/session"
+	internalstore "github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/pkg/store"
+)
+
+// The built-in subscribers satisfy the public API, so an out-of-tree
+// reconciler written against it is interchangeable with the ones
+// compiled into tilt.
+var _ store.Subscriber = &history.Controller{}
+var _ store.Subscriber = &session.Controller{}
+
+func TestRegisteredSubscriberSeesChanges(t *testing.T) {
+	sub := &countingSubscriber{}
+	store.RegisterSubscriber(sub)
+
+	subs := store.RegisteredSubscribers()
+	require.NotEmpty(t, subs)
+
+	st := internalstore.NewTestingStore()
+	for _, s := range subs {
+		s.OnChange(context.Background(), st)
+	}
+	assert.Equal(t, 1, sub.count)
+}
+
+type countingSubscriber struct {
+	count int
+}
+
+func (c *countingSubscriber) OnChange(ctx context.Context, st store.RStore) {
+	st.RLockState()
+	defer st.RUnlockState()
+	c.count++
+}
+
+var _ store.Subscriber = &countingSubscriber{}
@@ -0,0 +1,30 @@
+// Package testing gives extension authors and downstream forks the
+// test doubles Tilt uses internally, without copying internal test
+// code: the fake Kubernetes and Docker clients, and a headless engine
+// fixture that runs the real reducers against them.
+//
+// The types here alias the internal implementations, so a fake handed
+// to out-of-tree code is interchangeable with the ones the engine's
+// own tests use.
+package testing
+
+import (
+	"github.com/windmilleng/tilt/internal/docker"
+	"github.com/windmilleng/tilt/internal/k8s"
+)
+
+// An in-memory Kubernetes client: records upserts, deletes, and port
+// forwards, and lets tests inject pods, logs, and exec results.
+type FakeK8sClient = k8s.FakeK8sClient
+
+func NewFakeK8sClient() *FakeK8sClient {
+	return k8s.NewFakeK8sClient()
+}
+
+// An in-memory Docker client: records builds, execs, and restarts,
+// and lets tests script their results.
+type FakeDockerClient = docker.FakeClient
+
+func NewFakeDockerClient() *FakeDockerClient {
+	return docker.NewFakeClient()
+}
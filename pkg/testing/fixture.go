@@ -0,0 +1,111 @@
+package testing
+
+import (
+	"context"
+	gotesting "testing"
+	"time"
+
+	"github.com/windmilleng/tilt/internal/engine"
+	istore "github.com/windmilleng/tilt/internal/store"
+	"github.com/windmilleng/tilt/internal/testutils"
+	"github.com/windmilleng/tilt/pkg/model"
+	"github.com/windmilleng/tilt/pkg/store"
+)
+
+const waitTimeout = 5 * time.Second
+
+// Fixture runs the real engine store and reducers headlessly: no
+// terminal HUD, no web server, no cluster. Tests arrange state with
+// UpsertManifest/WithState, drive it with Dispatch, and observe the
+// results with WaitUntil, the same way the engine's own tests do.
+type Fixture struct {
+	t      gotesting.TB
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan error
+
+	Store  *istore.Store
+	K8s    *FakeK8sClient
+	Docker *FakeDockerClient
+}
+
+func NewFixture(t gotesting.TB) *Fixture {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	ctx, cancel := context.WithCancel(ctx)
+	st := istore.NewStore(engine.UpperReducer, istore.LogActionsFlag(false))
+	f := &Fixture{
+		t:      t,
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan error, 1),
+		Store:  st,
+		K8s:    NewFakeK8sClient(),
+		Docker: NewFakeDockerClient(),
+	}
+	go func() {
+		f.done <- st.Loop(ctx)
+	}()
+	return f
+}
+
+// The fixture's root context, with a test logger attached.
+func (f *Fixture) Context() context.Context {
+	return f.ctx
+}
+
+// Attach a subscriber to the store, the way the cli wiring does on
+// startup.
+func (f *Fixture) AddSubscriber(sub store.Subscriber) {
+	f.Store.AddSubscriber(f.ctx, sub)
+}
+
+func (f *Fixture) Dispatch(action store.Action) {
+	f.Store.Dispatch(action)
+}
+
+// Install a manifest directly, skipping Tiltfile execution.
+func (f *Fixture) UpsertManifest(m model.Manifest) {
+	f.WithState(func(state *store.EngineState) {
+		state.UpsertManifestTarget(istore.NewManifestTarget(m))
+	})
+}
+
+// Mutate engine state under the store lock, then wake subscribers.
+// Prefer dispatching actions; this is for arranging preconditions
+// actions can't express.
+func (f *Fixture) WithState(fn func(state *store.EngineState)) {
+	state := f.Store.LockMutableStateForTesting()
+	fn(state)
+	f.Store.UnlockMutableState()
+	f.Store.NotifySubscribers(f.ctx)
+}
+
+// Block until the engine state satisfies the predicate, failing the
+// test if it doesn't within the timeout.
+func (f *Fixture) WaitUntil(msg string, pred func(state store.EngineState) bool) {
+	deadline := time.After(waitTimeout)
+	for {
+		state := f.Store.RLockState()
+		ok := pred(state)
+		f.Store.RUnlockState()
+		if ok {
+			return
+		}
+
+		select {
+		case <-deadline:
+			f.t.Fatalf("timed out waiting until %s", msg)
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func (f *Fixture) TearDown() {
+	f.cancel()
+	err := <-f.done
+	if err != nil && err != context.Canceled {
+		f.t.Errorf("store loop: %v", err)
+	}
+	f.K8s.TearDown()
+}
@@ -0,0 +1,48 @@
+package testing
+
+import (
+	gotesting "testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/windmilleng/tilt/internal/hud/server"
+	"github.com/windmilleng/tilt/pkg/model"
+	"github.com/windmilleng/tilt/pkg/store"
+)
+
+func TestFixtureDispatchesThroughRealReducers(t *gotesting.T) {
+	f := NewFixture(t)
+	defer f.TearDown()
+
+	f.UpsertManifest(model.Manifest{Name: "fe"})
+	f.Dispatch(server.AppendToTriggerQueueAction{Name: "fe", Reason: model.BuildReasonFlagTriggerWeb})
+
+	f.WaitUntil("fe is queued for build", func(state store.EngineState) bool {
+		for _, name := range state.TriggerQueue {
+			if name == "fe" {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func TestFixtureWithState(t *gotesting.T) {
+	f := NewFixture(t)
+	defer f.TearDown()
+
+	f.UpsertManifest(model.Manifest{Name: "fe"})
+
+	state := f.Store.RLockState()
+	defer f.Store.RUnlockState()
+	_, ok := state.ManifestState("fe")
+	assert.True(t, ok)
+}
+
+func TestFakeClients(t *gotesting.T) {
+	f := NewFixture(t)
+	defer f.TearDown()
+
+	assert.NotNil(t, f.K8s)
+	assert.NotNil(t, f.Docker)
+}
@@ -164,7 +164,9 @@ type BuildRecord struct {
 	UpdateTypes    []UpdateType         `protobuf:"varint,9,rep,packed,name=update_types,json=updateTypes,proto3,enum=webview.UpdateType" json:"update_types,omitempty"`
 	IsCrashRebuild bool                 `protobuf:"varint,7,opt,name=is_crash_rebuild,json=isCrashRebuild,proto3" json:"is_crash_rebuild,omitempty"`
 	// The span id for this build record's logs in the main logstore.
-	SpanId               string   `protobuf:"bytes,8,opt,name=span_id,json=spanId,proto3" json:"span_id,omitempty"`
+	SpanId string `protobuf:"bytes,8,opt,name=span_id,json=spanId,proto3" json:"span_id,omitempty"`
+	// What kicked off this build, as a model.BuildReason bitfield.
+	BuildReason          int32    `protobuf:"varint,10,opt,name=build_reason,json=buildReason,proto3" json:"build_reason,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -251,6 +253,13 @@ func (m *BuildRecord) GetSpanId() string {
 	return ""
 }
 
+func (m *BuildRecord) GetBuildReason() int32 {
+	if m != nil {
+		return m.BuildReason
+	}
+	return 0
+}
+
 type K8SResourceInfo struct {
 	PodName            string `protobuf:"bytes,1,opt,name=pod_name,json=podName,proto3" json:"pod_name,omitempty"`
 	PodCreationTime    string `protobuf:"bytes,2,opt,name=pod_creation_time,json=podCreationTime,proto3" json:"pod_creation_time,omitempty"`
@@ -554,34 +563,138 @@ func (m *Facet) GetSpanId() string {
 	return ""
 }
 
+type CmdButton struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Argv                 []string `protobuf:"bytes,2,rep,name=argv,proto3" json:"argv,omitempty"`
+	InContainer          bool     `protobuf:"varint,3,opt,name=in_container,json=inContainer,proto3" json:"in_container,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CmdButton) Reset()         { *m = CmdButton{} }
+func (m *CmdButton) String() string { return proto.CompactTextString(m) }
+func (*CmdButton) ProtoMessage()    {}
+
+func (m *CmdButton) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CmdButton.Unmarshal(m, b)
+}
+func (m *CmdButton) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CmdButton.Marshal(b, m, deterministic)
+}
+func (m *CmdButton) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CmdButton.Merge(m, src)
+}
+func (m *CmdButton) XXX_Size() int {
+	return xxx_messageInfo_CmdButton.Size(m)
+}
+func (m *CmdButton) XXX_DiscardUnknown() {
+	xxx_messageInfo_CmdButton.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CmdButton proto.InternalMessageInfo
+
+func (m *CmdButton) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CmdButton) GetArgv() []string {
+	if m != nil {
+		return m.Argv
+	}
+	return nil
+}
+
+func (m *CmdButton) GetInContainer() bool {
+	if m != nil {
+		return m.InContainer
+	}
+	return false
+}
+
+type Link struct {
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// "up", "down", or "unknown" if the link declares a health check;
+	// empty otherwise.
+	HealthStatus         string   `protobuf:"bytes,2,opt,name=health_status,json=healthStatus,proto3" json:"health_status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Link) Reset()         { *m = Link{} }
+func (m *Link) String() string { return proto.CompactTextString(m) }
+func (*Link) ProtoMessage()    {}
+
+func (m *Link) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Link.Unmarshal(m, b)
+}
+func (m *Link) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Link.Marshal(b, m, deterministic)
+}
+func (m *Link) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Link.Merge(m, src)
+}
+func (m *Link) XXX_Size() int {
+	return xxx_messageInfo_Link.Size(m)
+}
+func (m *Link) XXX_DiscardUnknown() {
+	xxx_messageInfo_Link.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Link proto.InternalMessageInfo
+
+func (m *Link) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *Link) GetHealthStatus() string {
+	if m != nil {
+		return m.HealthStatus
+	}
+	return ""
+}
+
 type Resource struct {
-	Name               string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	DirectoriesWatched []string             `protobuf:"bytes,2,rep,name=directories_watched,json=directoriesWatched,proto3" json:"directories_watched,omitempty"`
-	PathsWatched       []string             `protobuf:"bytes,3,rep,name=paths_watched,json=pathsWatched,proto3" json:"paths_watched,omitempty"`
-	LastDeployTime     *timestamp.Timestamp `protobuf:"bytes,4,opt,name=last_deploy_time,json=lastDeployTime,proto3" json:"last_deploy_time,omitempty"`
-	TriggerMode        int32                `protobuf:"varint,5,opt,name=trigger_mode,json=triggerMode,proto3" json:"trigger_mode,omitempty"`
-	BuildHistory       []*BuildRecord       `protobuf:"bytes,6,rep,name=build_history,json=buildHistory,proto3" json:"build_history,omitempty"`
-	CurrentBuild       *BuildRecord         `protobuf:"bytes,7,opt,name=current_build,json=currentBuild,proto3" json:"current_build,omitempty"`
-	PendingBuildReason int32                `protobuf:"varint,8,opt,name=pending_build_reason,json=pendingBuildReason,proto3" json:"pending_build_reason,omitempty"`
-	PendingBuildEdits  []string             `protobuf:"bytes,9,rep,name=pending_build_edits,json=pendingBuildEdits,proto3" json:"pending_build_edits,omitempty"`
-	PendingBuildSince  *timestamp.Timestamp `protobuf:"bytes,10,opt,name=pending_build_since,json=pendingBuildSince,proto3" json:"pending_build_since,omitempty"`
-	HasPendingChanges  bool                 `protobuf:"varint,11,opt,name=has_pending_changes,json=hasPendingChanges,proto3" json:"has_pending_changes,omitempty"`
-	Endpoints          []string             `protobuf:"bytes,12,rep,name=endpoints,proto3" json:"endpoints,omitempty"`
-	PodID              string               `protobuf:"bytes,13,opt,name=podID,proto3" json:"podID,omitempty"`
-	K8SResourceInfo    *K8SResourceInfo     `protobuf:"bytes,14,opt,name=k8s_resource_info,json=k8sResourceInfo,proto3" json:"k8s_resource_info,omitempty"`
-	DcResourceInfo     *DCResourceInfo      `protobuf:"bytes,15,opt,name=dc_resource_info,json=dcResourceInfo,proto3" json:"dc_resource_info,omitempty"`
-	YamlResourceInfo   *YAMLResourceInfo    `protobuf:"bytes,16,opt,name=yaml_resource_info,json=yamlResourceInfo,proto3" json:"yaml_resource_info,omitempty"`
-	LocalResourceInfo  *LocalResourceInfo   `protobuf:"bytes,17,opt,name=local_resource_info,json=localResourceInfo,proto3" json:"local_resource_info,omitempty"`
-	RuntimeStatus      string               `protobuf:"bytes,18,opt,name=runtime_status,json=runtimeStatus,proto3" json:"runtime_status,omitempty"`
-	IsTiltfile         bool                 `protobuf:"varint,19,opt,name=is_tiltfile,json=isTiltfile,proto3" json:"is_tiltfile,omitempty"`
-	Specs              []*TargetSpec        `protobuf:"bytes,27,rep,name=specs,proto3" json:"specs,omitempty"`
-	ShowBuildStatus    bool                 `protobuf:"varint,20,opt,name=show_build_status,json=showBuildStatus,proto3" json:"show_build_status,omitempty"`
+	Name                string               `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	DirectoriesWatched  []string             `protobuf:"bytes,2,rep,name=directories_watched,json=directoriesWatched,proto3" json:"directories_watched,omitempty"`
+	PathsWatched        []string             `protobuf:"bytes,3,rep,name=paths_watched,json=pathsWatched,proto3" json:"paths_watched,omitempty"`
+	LastDeployTime      *timestamp.Timestamp `protobuf:"bytes,4,opt,name=last_deploy_time,json=lastDeployTime,proto3" json:"last_deploy_time,omitempty"`
+	TriggerMode         int32                `protobuf:"varint,5,opt,name=trigger_mode,json=triggerMode,proto3" json:"trigger_mode,omitempty"`
+	BuildHistory        []*BuildRecord       `protobuf:"bytes,6,rep,name=build_history,json=buildHistory,proto3" json:"build_history,omitempty"`
+	CurrentBuild        *BuildRecord         `protobuf:"bytes,7,opt,name=current_build,json=currentBuild,proto3" json:"current_build,omitempty"`
+	PendingBuildReason  int32                `protobuf:"varint,8,opt,name=pending_build_reason,json=pendingBuildReason,proto3" json:"pending_build_reason,omitempty"`
+	PendingBuildEdits   []string             `protobuf:"bytes,9,rep,name=pending_build_edits,json=pendingBuildEdits,proto3" json:"pending_build_edits,omitempty"`
+	PendingBuildSince   *timestamp.Timestamp `protobuf:"bytes,10,opt,name=pending_build_since,json=pendingBuildSince,proto3" json:"pending_build_since,omitempty"`
+	HasPendingChanges   bool                 `protobuf:"varint,11,opt,name=has_pending_changes,json=hasPendingChanges,proto3" json:"has_pending_changes,omitempty"`
+	Endpoints           []string             `protobuf:"bytes,12,rep,name=endpoints,proto3" json:"endpoints,omitempty"`
+	PodID               string               `protobuf:"bytes,13,opt,name=podID,proto3" json:"podID,omitempty"`
+	K8SResourceInfo     *K8SResourceInfo     `protobuf:"bytes,14,opt,name=k8s_resource_info,json=k8sResourceInfo,proto3" json:"k8s_resource_info,omitempty"`
+	DcResourceInfo      *DCResourceInfo      `protobuf:"bytes,15,opt,name=dc_resource_info,json=dcResourceInfo,proto3" json:"dc_resource_info,omitempty"`
+	YamlResourceInfo    *YAMLResourceInfo    `protobuf:"bytes,16,opt,name=yaml_resource_info,json=yamlResourceInfo,proto3" json:"yaml_resource_info,omitempty"`
+	LocalResourceInfo   *LocalResourceInfo   `protobuf:"bytes,17,opt,name=local_resource_info,json=localResourceInfo,proto3" json:"local_resource_info,omitempty"`
+	RuntimeStatus       string               `protobuf:"bytes,18,opt,name=runtime_status,json=runtimeStatus,proto3" json:"runtime_status,omitempty"`
+	ResourceState       string               `protobuf:"bytes,28,opt,name=resource_state,json=resourceState,proto3" json:"resource_state,omitempty"`
+	ResourceStateReason string               `protobuf:"bytes,29,opt,name=resource_state_reason,json=resourceStateReason,proto3" json:"resource_state_reason,omitempty"`
+	IsTiltfile          bool                 `protobuf:"varint,19,opt,name=is_tiltfile,json=isTiltfile,proto3" json:"is_tiltfile,omitempty"`
+	Specs               []*TargetSpec        `protobuf:"bytes,27,rep,name=specs,proto3" json:"specs,omitempty"`
+	ShowBuildStatus     bool                 `protobuf:"varint,20,opt,name=show_build_status,json=showBuildStatus,proto3" json:"show_build_status,omitempty"`
 	// Obsoleted by crash_log_span_id.
 	CrashLog string `protobuf:"bytes,22,opt,name=crash_log,json=crashLog,proto3" json:"crash_log,omitempty"`
 	// A span id for the log that crashed.
-	CrashLogSpanId       string   `protobuf:"bytes,26,opt,name=crash_log_span_id,json=crashLogSpanId,proto3" json:"crash_log_span_id,omitempty"`
-	Facets               []*Facet `protobuf:"bytes,24,rep,name=facets,proto3" json:"facets,omitempty"`
-	Queued               bool     `protobuf:"varint,25,opt,name=queued,proto3" json:"queued,omitempty"`
+	CrashLogSpanId string   `protobuf:"bytes,26,opt,name=crash_log_span_id,json=crashLogSpanId,proto3" json:"crash_log_span_id,omitempty"`
+	Facets         []*Facet `protobuf:"bytes,24,rep,name=facets,proto3" json:"facets,omitempty"`
+	Queued         bool     `protobuf:"varint,25,opt,name=queued,proto3" json:"queued,omitempty"`
+	// Custom actions defined via the cmd_button builtin.
+	Buttons []*CmdButton `protobuf:"bytes,30,rep,name=buttons,proto3" json:"buttons,omitempty"`
+	// Links defined via the link builtin, with health check state.
+	Links                []*Link  `protobuf:"bytes,31,rep,name=links,proto3" json:"links,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -738,6 +851,20 @@ func (m *Resource) GetRuntimeStatus() string {
 	return ""
 }
 
+func (m *Resource) GetResourceState() string {
+	if m != nil {
+		return m.ResourceState
+	}
+	return ""
+}
+
+func (m *Resource) GetResourceStateReason() string {
+	if m != nil {
+		return m.ResourceStateReason
+	}
+	return ""
+}
+
 func (m *Resource) GetIsTiltfile() bool {
 	if m != nil {
 		return m.IsTiltfile
@@ -787,6 +914,20 @@ func (m *Resource) GetQueued() bool {
 	return false
 }
 
+func (m *Resource) GetButtons() []*CmdButton {
+	if m != nil {
+		return m.Buttons
+	}
+	return nil
+}
+
+func (m *Resource) GetLinks() []*Link {
+	if m != nil {
+		return m.Links
+	}
+	return nil
+}
+
 type TiltBuild struct {
 	Version              string   `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
 	CommitSHA            string   `protobuf:"bytes,2,opt,name=commitSHA,proto3" json:"commitSHA,omitempty"`
@@ -1337,6 +1478,8 @@ func init() {
 	proto.RegisterType((*YAMLResourceInfo)(nil), "webview.YAMLResourceInfo")
 	proto.RegisterType((*LocalResourceInfo)(nil), "webview.LocalResourceInfo")
 	proto.RegisterType((*Facet)(nil), "webview.Facet")
+	proto.RegisterType((*CmdButton)(nil), "webview.CmdButton")
+	proto.RegisterType((*Link)(nil), "webview.Link")
 	proto.RegisterType((*Resource)(nil), "webview.Resource")
 	proto.RegisterType((*TiltBuild)(nil), "webview.TiltBuild")
 	proto.RegisterType((*VersionSettings)(nil), "webview.VersionSettings")
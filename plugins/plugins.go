@@ -0,0 +1,30 @@
+// Package plugins is the linking point for out-of-tree Starkit
+// extensions. It's only compiled into the tilt binary when building
+// with the tiltplugins build tag.
+//
+// To add your own Starlark builtins without forking tilt, drop a file
+// in this directory that registers your extension:
+//
+//   package plugins
+//
+//   import (
+//       foo "github.com/example/tilt-ext-foo"
+//
+//       "github.com/windmilleng/tilt/pkg/starkit"
+//   )
+//
+//   func init() {
+//       starkit.RegisterExtension(foo.NewExtension())
+//   }
+//
+// then build the binary with:
+//
+//   go build -tags tiltplugins ./cmd/tilt
+//
+// The same mechanism works for custom store subscribers (reconcilers
+// that react to engine state changes), via pkg/store:
+//
+//   func init() {
+//       store.RegisterSubscriber(foo.NewSubscriber())
+//   }
+package plugins